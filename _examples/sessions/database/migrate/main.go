@@ -0,0 +1,52 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kataras/iris"
+
+	"github.com/kataras/iris/sessions"
+	"github.com/kataras/iris/sessions/sessiondb/badger"
+	"github.com/kataras/iris/sessions/sessiondb/redis"
+	"github.com/kataras/iris/sessions/sessiondb/redis/service"
+)
+
+// Moves the sessions of a badger database to a redis one, with their remaining lifetime,
+// so the clients are not logged out when the application is deployed with the new database.
+// The application should not run while the migration of an external database is in progress.
+func main() {
+	src, err := badger.New("./sessions/")
+	if err != nil {
+		panic(err)
+	}
+	defer src.Close()
+
+	dst := redis.New(service.Config{Network: "tcp", Addr: "127.0.0.1:6379"})
+	defer dst.Close()
+
+	app := iris.New()
+
+	result, err := sessions.Migrate(src, dst, sessions.MigrateOptions{
+		OnSession: func(sid string, lifetime sessions.LifeTime) {
+			app.Logger().Debugf("migrated session %s, expires at: %s", sid, lifetime.Format(time.RFC3339))
+		},
+	})
+	if err != nil {
+		app.Logger().Fatalf("migration failed: %v", err)
+		return
+	}
+
+	app.Logger().Infof("%d sessions migrated, %d skipped", result.Migrated, result.Skipped)
+
+	// the in-memory sessions of a running application can be moved too, i.e on an admin's request:
+	// sessions.Migrate(sess.Database(), dst)
+	// sess.UseDatabase(dst)
+	sess := sessions.New(sessions.Config{Cookie: "sessionscookieid", Expires: 45 * time.Minute})
+	sess.UseDatabase(dst)
+
+	app.Get("/", func(ctx iris.Context) {
+		ctx.Writef("Hello %s", sess.Start(ctx).GetString("name"))
+	})
+
+	app.Run(iris.Addr(":8080"))
+}
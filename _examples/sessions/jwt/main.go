@@ -0,0 +1,59 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kataras/iris"
+
+	"github.com/kataras/iris/sessions"
+)
+
+var (
+	// the keys should be kept secret, i.e loaded by environment variables,
+	// prepend a new key to rotate them, the old ones still verify the existing tokens.
+	sess = sessions.New(sessions.Config{
+		Cookie:    "mysessiontoken",
+		Expires:   24 * time.Hour,
+		JWT:       true,
+		JWTKeys:   [][]byte{[]byte("the-current-secret-key")},
+		JWTIssuer: "myapp",
+	})
+)
+
+func main() {
+	app := iris.New()
+
+	// the session's values are kept in memory, or in a database by the `sess.UseDatabase`,
+	// under the subject of the token.
+	app.Post("/login", func(ctx iris.Context) {
+		session := sess.Start(ctx)
+		if err := session.Authenticate(ctx, "username", ctx.FormValue("username")); err != nil {
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+
+		// the token is sent by the cookie as well,
+		// the API clients send it back with the "Authorization: Bearer $token" header.
+		ctx.JSON(iris.Map{"token": sess.SetClaims(ctx, iris.Map{"role": "member"})})
+	})
+
+	app.Get("/me", func(ctx iris.Context) {
+		username := sess.Start(ctx).GetString("username")
+		if username == "" {
+			ctx.StatusCode(iris.StatusUnauthorized)
+			return
+		}
+
+		ctx.JSON(iris.Map{"username": username, "role": sess.Claims(ctx)["role"]})
+	})
+
+	app.Get("/logout", func(ctx iris.Context) {
+		// the values are removed, the token is still valid until its expiration,
+		// but it does not authenticate the user anymore.
+		sess.Destroy(ctx)
+	})
+
+	// curl -d "username=kataras" http://localhost:8080/login
+	// curl -H "Authorization: Bearer $token" http://localhost:8080/me
+	app.Run(iris.Addr(":8080"))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kataras/iris"
+
+	"github.com/kataras/iris/sessions"
+)
+
+var (
+	// a long-lived cookie which keeps the visitor's preferences, encrypted on the client-side.
+	preferences = sessions.New(sessions.Config{
+		Cookie:        "preferences",
+		Expires:       365 * 24 * time.Hour,
+		Stateless:     true,
+		StatelessKeys: [][]byte{[]byte("the-preferences-aes-256-key-32by")},
+	})
+
+	// a short-lived session of the administrators, it can use its own database by the `UseDatabase`.
+	admins = sessions.New(sessions.Config{
+		Cookie:      "admin_session",
+		Expires:     30 * time.Minute,
+		IdleTimeout: 10 * time.Minute,
+	})
+)
+
+func main() {
+	app := iris.New()
+	// bind the preferences to all of the routes.
+	app.Use(preferences.Handler())
+
+	app.Get("/theme/{theme}", func(ctx iris.Context) {
+		sessions.Get(ctx).Set("theme", ctx.Params().Get("theme"))
+	})
+
+	// bind the administrators' sessions to the admin Party,
+	// the `sessions.Get` returns the session of the last bound manager.
+	admin := app.Party("/admin", admins.Handler())
+	{
+		admin.Get("/login", func(ctx iris.Context) {
+			sessions.Get(ctx).Authenticate(ctx, "admin", true)
+		})
+
+		admin.Get("/", func(ctx iris.Context) {
+			if auth, _ := sessions.Get(ctx).GetBoolean("admin"); !auth {
+				ctx.StatusCode(iris.StatusForbidden)
+				return
+			}
+
+			ctx.Writef("Hello admin, your theme is: %s",
+				sessions.GetByCookie(ctx, "preferences").GetStringDefault("theme", "light"))
+		})
+	}
+
+	app.Run(iris.Addr(":8080"))
+}
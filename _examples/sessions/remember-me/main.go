@@ -0,0 +1,66 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kataras/iris"
+
+	"github.com/kataras/iris/sessions"
+	"github.com/kataras/iris/sessions/remember"
+)
+
+var (
+	sess = sessions.New(sessions.Config{
+		Cookie:  "sessionid",
+		Expires: 30 * time.Minute,
+	})
+
+	// the tokens are kept in memory, implement the `remember.Store` to keep them in a database.
+	rememberMe = remember.New(sess, remember.NewMemStore(), remember.Config{
+		Cookie:  "remember_me",
+		Expires: 14 * 24 * time.Hour,
+		OnTheft: func(ctx iris.Context, userID string) {
+			ctx.Application().Logger().Warnf("the remember-me token of the user %s was stolen", userID)
+		},
+	})
+)
+
+func main() {
+	app := iris.New()
+	// re-establish the expired sessions of the remembered users.
+	app.Use(rememberMe.Handler)
+
+	app.Get("/login", func(ctx iris.Context) {
+		// authentication goes here...
+		sess.Get(ctx).Authenticate(ctx, "user_id", "kataras")
+
+		if remember, _ := ctx.URLParamBool("remember"); remember {
+			if err := rememberMe.Remember(ctx, "kataras"); err != nil {
+				ctx.StatusCode(iris.StatusInternalServerError)
+				return
+			}
+		}
+
+		ctx.Writef("logged in")
+	})
+
+	app.Get("/", func(ctx iris.Context) {
+		// read the session by the `Get`, so the one which is re-established by the token is used.
+		userID := sess.Get(ctx).GetString("user_id")
+		if userID == "" {
+			ctx.StatusCode(iris.StatusUnauthorized)
+			return
+		}
+
+		ctx.Writef("hello %s", userID)
+	})
+
+	app.Get("/logout", func(ctx iris.Context) {
+		sess.Destroy(ctx)
+		rememberMe.Forget(ctx)
+	})
+
+	// visit http://localhost:8080/login?remember=true, wait for the session's expiration
+	// or remove its "sessionid" cookie and visit http://localhost:8080 again.
+	app.Run(iris.Addr(":8080"))
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kataras/iris"
+
+	"github.com/kataras/iris/sessions"
+)
+
+var (
+	// the keys should be kept secret, i.e loaded by environment variables,
+	// prepend a new key to rotate them, the old ones still decrypt the existing cookies.
+	currentKey  = []byte("the-current-aes-256-key-32-bytes")
+	previousKey = []byte("the-previous-aes-128-key")[:16]
+
+	// the session's values are stored to its cookie, encrypted,
+	// so any instance of the application can read them without a database.
+	sess = sessions.New(sessions.Config{
+		Cookie:        "mycookiesessionnameid",
+		Expires:       24 * time.Hour,
+		Stateless:     true,
+		StatelessKeys: [][]byte{currentKey, previousKey},
+	})
+)
+
+func secret(ctx iris.Context) {
+	// Check if user is authenticated
+	if auth, _ := sess.Start(ctx).GetBoolean("authenticated"); !auth {
+		ctx.StatusCode(iris.StatusForbidden)
+		return
+	}
+
+	// Print secret message
+	ctx.WriteString("The cake is a lie!")
+}
+
+func login(ctx iris.Context) {
+	session := sess.Start(ctx)
+
+	// Authentication goes here
+	// ...
+
+	// Set user as authenticated,
+	// the values should be set before the response body is written.
+	session.Set("authenticated", true)
+}
+
+func logout(ctx iris.Context) {
+	// Remove the session's cookie
+	sess.Destroy(ctx)
+}
+
+func main() {
+	app := iris.New()
+
+	app.Get("/secret", secret)
+	app.Get("/login", login)
+	app.Get("/logout", logout)
+
+	app.Run(iris.Addr(":8080"))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+
+	"github.com/kataras/iris"
+
+	"github.com/kataras/iris/sessions"
+	"github.com/kataras/iris/sessions/sessiondb/boltdb"
+)
+
+// User is stored as it's, no need to serialize it to a string.
+type User struct {
+	Username string   `json:"username" msgpack:"username"`
+	Roles    []string `json:"roles" msgpack:"roles"`
+}
+
+func main() {
+	// the codec of all of the session databases, set it before any session is started.
+	sessions.DefaultTranscoder = sessions.MsgPackTranscoder
+	// for the gob one, register your types:
+	// gob.Register(User{})
+	// sessions.DefaultTranscoder = sessions.GobTranscoder
+
+	db, err := boltdb.New("./sessions.db", os.FileMode(0750))
+	if err != nil {
+		panic(err)
+	}
+
+	iris.RegisterOnInterrupt(func() {
+		db.Close()
+	})
+	defer db.Close() // close the database connection if application errored.
+
+	sess := sessions.New(sessions.Config{Cookie: "sessionscookieid"})
+	sess.UseDatabase(db)
+
+	app := iris.New()
+
+	app.Get("/login", func(ctx iris.Context) {
+		sess.Start(ctx).Set("user", User{Username: "kataras", Roles: []string{"admin"}})
+		ctx.Writef("logged in")
+	})
+
+	app.Get("/", func(ctx iris.Context) {
+		var user User
+		// the value is decoded to the "user", whatever the codec or the database is.
+		if err := sess.Start(ctx).Decode("user", &user); err != nil {
+			ctx.StatusCode(iris.StatusUnauthorized)
+			return
+		}
+
+		ctx.JSON(user)
+	})
+
+	app.Run(iris.Addr(":8080"))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/websocket"
+)
+
+// The client and the server send requests to each other by the `EmitWithAck` and they wait for the replies,
+// the `OnRequest` handlers return the replies.
+func main() {
+	app := iris.New()
+	ws := websocket.New(websocket.Config{})
+
+	ws.OnConnection(func(c websocket.Connection) {
+		// the reply of the client's ws.EmitWithAck("double", 21, 1000).
+		c.OnRequest("double", func(message interface{}) interface{} {
+			n, _ := message.(int)
+			return n * 2
+		})
+
+		c.On("ready", func() {
+			// it waits for the reply, so not by the reader of the connection which calls this listener.
+			go func() {
+				reply, err := c.EmitWithAck("name", nil, 5*time.Second)
+				if err != nil {
+					app.Logger().Warnf("the client did not reply: %v", err)
+					return
+				}
+				c.Emit("chat", "welcome "+reply.(string))
+			}()
+		})
+	})
+
+	app.Get("/echo", ws.Handler())
+	app.Get("/iris-ws.js", websocket.ClientHandler())
+	app.Get("/", func(ctx iris.Context) {
+		ctx.HTML(page)
+	})
+
+	app.Run(iris.Addr(":8080"))
+}
+
+const page = `<html><body><pre id="output"></pre>
+<script src="/iris-ws.js"></script>
+<script>
+var output = document.getElementById("output");
+var socket = new Ws("ws://localhost:8080/echo");
+
+socket.OnRequest("name", function () {
+	return "gopher";
+});
+
+socket.On("chat", function (msg) {
+	output.innerHTML += msg + "\n";
+});
+
+socket.OnConnect(function () {
+	socket.EmitWithAck("double", 21, 1000).then(function (reply) {
+		output.innerHTML += "21 * 2 = " + reply + "\n";
+		socket.Emit("ready");
+	});
+});
+</script></body></html>`
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/sessions/sessiondb/redis/service"
+	"github.com/kataras/iris/websocket"
+	"github.com/kataras/iris/websocket/exchange/redis"
+)
+
+// Run two or more instances of this application, i.e with the "-addr :8080" and the "-addr :8081",
+// the messages of the clients of each one of them reach the clients of all of them through the redis pub/sub.
+func main() {
+	addr := flag.String("addr", ":8080", "the address of this instance")
+	flag.Parse()
+
+	app := iris.New()
+	ws := websocket.New(websocket.Config{})
+
+	exchange, err := redis.New("chat", service.Config{
+		Network: service.DefaultRedisNetwork,
+		Addr:    service.DefaultRedisAddr,
+	})
+	if err != nil {
+		app.Logger().Fatal(err)
+	}
+	defer exchange.Close()
+
+	if err = ws.UseExchange(exchange); err != nil {
+		app.Logger().Fatal(err)
+	}
+
+	ws.OnConnection(func(c websocket.Connection) {
+		c.Join("chat")
+		c.On("chat", func(msg string) {
+			// to the members of the "chat" room of all of the instances, except itself.
+			c.To("chat").Emit("chat", msg)
+		})
+	})
+
+	app.Get("/echo", ws.Handler())
+	app.Any("/iris-ws.js", func(ctx iris.Context) {
+		ctx.Write(websocket.ClientSource)
+	})
+
+	// POST http://localhost:8080/broadcast with the message as the "msg" form value,
+	// to all of the connections of all of the instances.
+	app.Post("/broadcast", func(ctx iris.Context) {
+		ws.To(websocket.All).Emit("chat", "server: "+ctx.FormValue("msg"))
+	})
+
+	app.Run(iris.Addr(*addr))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/websocket"
+)
+
+// The clients join a room by the "join" event and they send messages to its members by the "chat" event,
+// the server sends messages to a room by the `Server#To` and it lists the rooms and their members,
+// the connections leave their rooms automatically on disconnect.
+func main() {
+	app := iris.New()
+	ws := websocket.New(websocket.Config{})
+
+	ws.OnConnection(func(c websocket.Connection) {
+		c.On("join", func(room string) {
+			ws.JoinRoom(c, room)
+			ws.To(room).Emit("chat", c.ID()+" joined the "+room)
+		})
+
+		c.On("leave", func(room string) {
+			if ws.LeaveRoom(c, room) {
+				ws.To(room).Emit("chat", c.ID()+" left the "+room)
+			}
+		})
+
+		c.On("chat", func(msg string) {
+			// to the members of all of the connection's rooms, except itself.
+			for _, room := range ws.RoomsOf(c.ID()) {
+				c.To(room).Emit("chat", msg)
+			}
+		})
+	})
+
+	app.Get("/echo", ws.Handler())
+	app.Any("/iris-ws.js", func(ctx iris.Context) {
+		ctx.Write(websocket.ClientSource)
+	})
+
+	// GET http://localhost:8080/rooms
+	app.Get("/rooms", func(ctx iris.Context) {
+		rooms := make(map[string]int)
+		for _, room := range ws.Rooms() {
+			rooms[room] = ws.GetTotalConnectionsByRoom(room)
+		}
+		ctx.JSON(rooms)
+	})
+
+	// POST http://localhost:8080/rooms/{room} with the message as the "msg" form value.
+	app.Post("/rooms/{room}", func(ctx iris.Context) {
+		ws.To(ctx.Params().Get("room")).Emit("chat", "server: "+ctx.FormValue("msg"))
+	})
+
+	app.Run(iris.Addr(":8080"))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/websocket"
+)
+
+// ChatMessage is the payload of the "chat" event.
+type ChatMessage struct {
+	Text     string    `json:"text"`
+	From     string    `json:"from"`
+	SentAt   time.Time `json:"sentAt"`
+	Mentions []string  `json:"mentions,omitempty"`
+}
+
+// The events are declared with the types of their payloads, the server serves the javascript client
+// which decodes their messages and the typescript declarations of the events, so the front-end is type-checked against them.
+func main() {
+	app := iris.New()
+	ws := websocket.New(websocket.Config{})
+
+	ws.DeclareEvent("chat", ChatMessage{})
+	ws.DeclareEvent("online", 0)
+
+	ws.OnConnection(func(c websocket.Connection) {
+		c.Join("chat")
+		ws.To("chat").Emit("online", ws.GetTotalConnectionsByRoom("chat"))
+
+		c.On("chat", func(msg interface{}) {
+			m, _ := msg.(map[string]interface{})
+			text, _ := m["text"].(string)
+			// the Emit returns an error if the data is not a ChatMessage.
+			c.To("chat").Emit("chat", ChatMessage{Text: text, From: c.ID(), SentAt: time.Now()})
+		})
+	})
+
+	app.Get("/echo", ws.Handler())
+	// the client-side library, its "chat" listeners receive objects instead of strings.
+	app.Get("/iris-ws.js", ws.ClientHandler())
+	// GET http://localhost:8080/iris-ws.d.ts, save it to the front-end project.
+	app.Get("/iris-ws.d.ts", func(ctx iris.Context) {
+		ctx.ContentType("application/typescript")
+		ctx.Write(ws.ClientTypeScript())
+	})
+
+	app.Run(iris.Addr(":8080"))
+}
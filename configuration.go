@@ -2,16 +2,19 @@ package iris
 
 import (
 	"io/ioutil"
+	"net"
 	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v2"
 
 	"github.com/kataras/iris/context"
 	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/core/netutil"
 )
 
 const globalConfigurationKeyword = "~"
@@ -237,6 +240,16 @@ var WithoutPathCorrection = func(app *Application) {
 	app.config.DisablePathCorrection = true
 }
 
+// WithPathNormalization sets the PathNormalization setting,
+// the options of the request path's normalization before the route matching.
+//
+// See `Configuration`.
+func WithPathNormalization(opts context.PathNormalization) Configurator {
+	return func(app *Application) {
+		app.config.PathNormalization = opts
+	}
+}
+
 // WithoutBodyConsumptionOnUnmarshal disables BodyConsumptionOnUnmarshal setting.
 //
 // See `Configuration`.
@@ -272,6 +285,27 @@ var WithFireMethodNotAllowed = func(app *Application) {
 	app.config.FireMethodNotAllowed = true
 }
 
+// WithAutoHead enables the AutoHead setting.
+//
+// See `Configuration`.
+var WithAutoHead = func(app *Application) {
+	app.config.AutoHead = true
+}
+
+// WithAutoOptions enables the AutoOptions setting.
+//
+// See `Configuration`.
+var WithAutoOptions = func(app *Application) {
+	app.config.AutoOptions = true
+}
+
+// WithUploadContentTypeVerification enables the UploadContentTypeVerification setting.
+//
+// See `Configuration`.
+var WithUploadContentTypeVerification = func(app *Application) {
+	app.config.UploadContentTypeVerification = true
+}
+
 // WithTimeFormat sets the TimeFormat setting.
 //
 // See `Configuration`.
@@ -290,6 +324,19 @@ func WithCharset(charset string) Configurator {
 	}
 }
 
+// WithContentTypeCharset sets the response charset of a content type,
+// i.e iris.WithContentTypeCharset("text/csv", "ISO-8859-1").
+//
+// See `Configuration#ContentTypeCharsets`.
+func WithContentTypeCharset(contentType, charset string) Configurator {
+	return func(app *Application) {
+		if app.config.ContentTypeCharsets == nil {
+			app.config.ContentTypeCharsets = make(map[string]string)
+		}
+		app.config.ContentTypeCharsets[contentType] = charset
+	}
+}
+
 // WithPostMaxMemory sets the maximum post data size
 // that a client can send to the server, this differs
 // from the overral request body size which can be modified
@@ -302,6 +349,104 @@ func WithPostMaxMemory(limit int64) Configurator {
 	}
 }
 
+// WithPushViewAssets enables the HTTP/2 server push of the local stylesheets and scripts
+// which are referenced by the views that are rendered by the `Context#View`.
+//
+// See `Configuration#PushViewAssets` and `Context#Push` for more.
+var WithPushViewAssets = func(app *Application) {
+	app.config.PushViewAssets = true
+}
+
+// WithRequestLogger sets the factory of the `Context#Logger`, i.e:
+//
+// app.Configure(iris.WithRequestLogger(func(ctx context.Context) context.Logger {
+// 	return myLogger{entry: logrus.WithField("request_id", ctx.RequestID())}
+// }))
+//
+// See `Configuration#RequestLogger` for more.
+func WithRequestLogger(factory func(ctx context.Context) context.Logger) Configurator {
+	return func(app *Application) {
+		app.config.RequestLogger = factory
+	}
+}
+
+// WithUserAgentRules sets the ruleset of the `Context#UserAgentInfo`
+// and `Context#IsBot`, i.e an updated one which is loaded by the `context#NewUserAgentRules`.
+//
+// See `Configuration#UserAgentRules` for more.
+func WithUserAgentRules(rules *context.UserAgentRules) Configurator {
+	return func(app *Application) {
+		app.config.UserAgentRules = rules
+	}
+}
+
+// WithLocalizer sets the translations provider of the `Context#Tr`,
+// the framework's messages and the "tr" template function, i.e:
+//
+// i18n := i18n.New()
+// i18n.Load("./locales/*/*.yml", "en-US", "el-GR")
+// app.Configure(iris.WithLocalizer(i18n))
+//
+// See `Configuration#Localizer` and the i18n package for more.
+func WithLocalizer(localizer context.Localizer) Configurator {
+	return func(app *Application) {
+		app.config.Localizer = localizer
+	}
+}
+
+// WithJSONCodec sets the JSON functions of the `Context#JSON`, `Context#JSONP`, `Context#ReadJSON`,
+// `Context#Bind`, the JSON streams and of the mvc and hero results, i.e to use a faster implementation.
+//
+// It panics if the `Marshal` or the `Unmarshal` of the "codec" is missing.
+//
+// See `context#JSONCodec` for more.
+func WithJSONCodec(codec context.JSONCodec) Configurator {
+	if err := codec.Validate(); err != nil {
+		panic(err)
+	}
+
+	return func(app *Application) {
+		app.config.JSONCodec = &codec
+	}
+}
+
+// WithSecureCookie sets the keys which the `Context#SetSecureCookie` uses to sign,
+// and encrypt if a block key is set, the cookies' values and the `Context#GetSecureCookie` to verify them.
+// The first key pair encodes the new values, the rest of them are accepted too, so the keys can be rotated.
+// The "maxAge" is the cookies' lifetime, zero means no limit.
+//
+// It panics if a key pair is invalid, see `context#SecureCookieKey`.
+func WithSecureCookie(maxAge time.Duration, keys ...context.SecureCookieKey) Configurator {
+	secureCookie := &context.SecureCookie{Keys: keys, MaxAge: maxAge}
+	if err := secureCookie.Validate(); err != nil {
+		panic(err)
+	}
+
+	return func(app *Application) {
+		app.config.SecureCookie = secureCookie
+	}
+}
+
+// WithValidator sets the validator which the `Context#Bind`, `Context#ReadJSON`, `Context#ReadForm`
+// and the rest of the Read methods use to validate the decoded request's input.
+func WithValidator(validator context.Validator) Configurator {
+	return func(app *Application) {
+		app.config.Validator = validator
+	}
+}
+
+// WithUploadScanner sets the scanner which the `Context#UploadFormFiles`, `Context#FormFile`,
+// `Context#FormFiles` and `Context#ReadMultipart` use to scan each uploaded file
+// before it's persisted or handed to the caller, i.e an anti-virus.
+// The `Context#MultipartStream` does not store the parts, so they are not scanned.
+//
+// See the `scanner` package for a clamd implementation.
+func WithUploadScanner(scanner context.Scanner) Configurator {
+	return func(app *Application) {
+		app.config.UploadScanner = scanner
+	}
+}
+
 // WithRemoteAddrHeader enables or adds a new or existing request header name
 // that can be used to validate the client's real IP.
 //
@@ -344,6 +489,34 @@ func WithoutRemoteAddrHeader(headerName string) Configurator {
 	}
 }
 
+// WithTrustedProxies adds the CIDRs or the IPs of the proxies, i.e "10.0.0.0/8" or "127.0.0.1",
+// whose "Forwarded", "X-Forwarded-For" and "X-Real-Ip" headers are trusted
+// by the `ctx.ClientIP()` and the `ctx.RemoteAddr()`.
+//
+// Look `context.ClientIP()` for more.
+func WithTrustedProxies(proxies ...string) Configurator {
+	return func(app *Application) {
+		app.config.TrustedProxies = append(app.config.TrustedProxies, proxies...)
+		app.parseTrustedProxies()
+	}
+}
+
+// parseTrustedProxies parses the `Configuration#TrustedProxies` once, on configuration,
+// instead of on each request, the invalid ones are logged and ignored.
+func (app *Application) parseTrustedProxies() {
+	nets := make([]*net.IPNet, 0, len(app.config.TrustedProxies))
+	for _, proxy := range app.config.TrustedProxies {
+		ipNet, err := netutil.ParseIPNet(proxy)
+		if err != nil {
+			app.logger.Warnf("trusted proxies: %v", err)
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+
+	app.config.trustedProxyNets = nets
+}
+
 // WithOtherValue adds a value based on a key to the Other setting.
 //
 // See `Configuration`.
@@ -401,6 +574,15 @@ type Configuration struct {
 	// Defaults to false.
 	DisablePathCorrection bool `json:"disablePathCorrection,omitempty" yaml:"DisablePathCorrection" toml:"DisablePathCorrection"`
 
+	// PathNormalization normalizes the request path before the route matching,
+	// it can collapse the double slashes, resolve the "." and ".." segments
+	// and convert the path to the unicode NFC form.
+	// The client is redirected to the normalized path if its `Redirect` is true,
+	// otherwise the request's path is rewritten.
+	//
+	// Defaults to a zero value, no normalization.
+	PathNormalization context.PathNormalization `json:"pathNormalization,omitempty" yaml:"PathNormalization" toml:"PathNormalization"`
+
 	// EnablePathEscape when is true then its escapes the path, the named parameters (if any).
 	// Change to false it if you want something like this https://github.com/kataras/iris/issues/135 to work
 	//
@@ -423,6 +605,20 @@ type Configuration struct {
 	//  fires the 405 error instead of 404
 	// Defaults to false.
 	FireMethodNotAllowed bool `json:"fireMethodNotAllowed,omitempty" yaml:"FireMethodNotAllowed" toml:"FireMethodNotAllowed"`
+	// AutoOptions if it's true then an OPTIONS route is registered, on build,
+	// for each registered path that has not an OPTIONS route already,
+	// it responds with 204 No Content and the "Allow" header of the path's methods.
+	// The `UseGlobal` and `DoneGlobal` handlers, i.e a CORS middleware, are executed for these routes too.
+	//
+	// Defaults to false.
+	AutoOptions bool `json:"autoOptions,omitempty" yaml:"AutoOptions" toml:"AutoOptions"`
+	// AutoHead if it's true then a HEAD route is registered, on build,
+	// for each GET route that has not a HEAD route on the same path already,
+	// it runs the GET route's handlers but it discards the response body,
+	// the status code and the headers are kept.
+	//
+	// Defaults to false.
+	AutoHead bool `json:"autoHead,omitempty" yaml:"AutoHead" toml:"AutoHead"`
 
 	// DisableBodyConsumptionOnUnmarshal manages the reading behavior of the context's body readers/binders.
 	// If setted to true then it
@@ -457,6 +653,16 @@ type Configuration struct {
 	// Defaults to "UTF-8".
 	Charset string `json:"charset,omitempty" yaml:"Charset" toml:"Charset"`
 
+	// ContentTypeCharsets sets the response charset of the content types,
+	// i.e {"text/csv": "ISO-8859-1"}, the rest of them use the `Charset`.
+	// The charset is only declared on the "Content-Type" header,
+	// the handler should write the body in that charset.
+	//
+	// The request bodies and forms are decoded to UTF-8 by the charset of their "Content-Type" anyway.
+	//
+	// Defaults to nil.
+	ContentTypeCharsets map[string]string `json:"contentTypeCharsets,omitempty" yaml:"ContentTypeCharsets" toml:"ContentTypeCharsets"`
+
 	// PostMaxMemory sets the maximum post data size
 	// that a client can send to the server, this differs
 	// from the overral request body size which can be modified
@@ -464,6 +670,68 @@ type Configuration struct {
 	//
 	// Defaults to 32MB or 32 << 20 if you prefer.
 	PostMaxMemory int64 `json:"postMaxMemory" yaml:"PostMaxMemory" toml:"PostMaxMemory"`
+
+	// UploadScanner if not nil, the `Context#UploadFormFiles`, `Context#FormFile`, `Context#FormFiles`
+	// and `Context#ReadMultipart` scan each uploaded file through it before persisting it
+	// or handing it to the caller, i.e an anti-virus,
+	// files that are rejected are not saved and a 422 status code is sent instead.
+	// The parts of the `Context#MultipartStream` are not scanned.
+	//
+	// Defaults to nil.
+	UploadScanner context.Scanner `json:"-" yaml:"-" toml:"-"`
+	// UploadContentTypeVerification if true then the `Context#UploadFormFiles`
+	// verifies that the contents (magic bytes) of each uploaded file
+	// match its declared Content-Type and its filename extension's type,
+	// mismatched files are not saved and a 415 status code is sent instead.
+	// It protects against stored-XSS, i.e an html document uploaded as an image.
+	//
+	// See `context#VerifyContentType` too.
+	//
+	// Defaults to false.
+	UploadContentTypeVerification bool `json:"uploadContentTypeVerification,omitempty" yaml:"UploadContentTypeVerification" toml:"UploadContentTypeVerification"`
+	// Validator if not nil, the `Context#Bind`, `Context#ReadJSON`, `Context#ReadForm`
+	// and the rest of the Read methods validate the decoded value through it,
+	// invalid values fail with a `context#ValidationErrors` error and a 400 status code.
+	//
+	// Defaults to nil.
+	Validator context.Validator `json:"-" yaml:"-" toml:"-"`
+	// SecureCookie if not nil, the `Context#SetSecureCookie` signs, and encrypts,
+	// the cookies' values with its keys and the `Context#GetSecureCookie` verifies them.
+	//
+	// Defaults to nil.
+	SecureCookie *context.SecureCookie `json:"-" yaml:"-" toml:"-"`
+	// JSONCodec if not nil, it replaces the JSON functions of the `Context#JSON`, `Context#JSONP`,
+	// `Context#ReadJSON`, `Context#Bind`, the JSON streams and of the mvc and hero results,
+	// even if the `EnableOptimizations` is true.
+	// A codec without a `Marshal` or an `Unmarshal` function is ignored, see `context#JSONCodec#Validate`.
+	//
+	// Defaults to nil.
+	JSONCodec *context.JSONCodec `json:"-" yaml:"-" toml:"-"`
+	// PushViewAssets if true, the `Context#View` pushes the local stylesheets and scripts,
+	// which are referenced by the rendered HTML, to the HTTP/2 clients before it sends the view.
+	// The view is rendered to a buffer first, in order to find its assets.
+	//
+	// Defaults to false.
+	PushViewAssets bool `json:"pushViewAssets,omitempty" yaml:"PushViewAssets" toml:"PushViewAssets"`
+	// Localizer if not nil, it translates the messages of the `Context#Tr`,
+	// the framework's client-facing messages and the "tr" template function
+	// to the language of each request, see the i18n package.
+	//
+	// Defaults to nil.
+	Localizer context.Localizer `json:"-" yaml:"-" toml:"-"`
+	// UserAgentRules if not nil, it replaces the ruleset which detects the browser,
+	// the operating system and the device class of the clients,
+	// see `Context#UserAgentInfo` and `Context#IsBot`.
+	//
+	// Defaults to nil, the `context#DefaultUserAgentRules` is used.
+	UserAgentRules *context.UserAgentRules `json:"-" yaml:"-" toml:"-"`
+	// RequestLogger if not nil, it creates the logger of each request, see `Context#Logger`,
+	// i.e to write structured messages by a third-party logger.
+	// If it returns nil then the default one is used.
+	//
+	// Defaults to nil, the `context#NewLogger` of the application's logger
+	// with the request ID, method, path and route fields is used.
+	RequestLogger func(ctx context.Context) context.Logger `json:"-" yaml:"-" toml:"-"`
 	//  +----------------------------------------------------+
 	//  | Context's keys for values used on various featuers |
 	//  +----------------------------------------------------+
@@ -512,6 +780,19 @@ type Configuration struct {
 	//
 	// Look `context.RemoteAddr()` for more.
 	RemoteAddrHeaders map[string]bool `json:"remoteAddrHeaders,omitempty" yaml:"RemoteAddrHeaders" toml:"RemoteAddrHeaders"`
+	// TrustedProxies are the CIDRs or the IPs of the proxies, i.e "10.0.0.0/8" or "127.0.0.1",
+	// whose "Forwarded", "X-Forwarded-For" and "X-Real-Ip" headers
+	// are trusted by the `ctx.ClientIP()` and the `ctx.RemoteAddr()`.
+	// The headers of the requests which do not come from these proxies are ignored,
+	// so the clients can not spoof their IP in logs and rate limiters.
+	//
+	// Defaults to empty, the `ctx.RemoteAddr()` uses the `RemoteAddrHeaders` instead.
+	//
+	// Look `context.ClientIP()` for more.
+	TrustedProxies []string `json:"trustedProxies,omitempty" yaml:"TrustedProxies" toml:"TrustedProxies"`
+	// trustedProxyNets are the parsed TrustedProxies, they are set by the `WithTrustedProxies`
+	// and the `WithConfiguration`.
+	trustedProxyNets []*net.IPNet
 
 	// Other are the custom, dynamic options, can be empty.
 	// This field used only by you to set any app's options you want.
@@ -540,6 +821,12 @@ func (c Configuration) GetDisablePathCorrection() bool {
 	return c.DisablePathCorrection
 }
 
+// GetPathNormalization returns the Configuration#PathNormalization,
+// the options of the request path's normalization before the route matching.
+func (c Configuration) GetPathNormalization() context.PathNormalization {
+	return c.PathNormalization
+}
+
 // GetEnablePathEscape is the Configuration#EnablePathEscape,
 // returns true when its escapes the path, the named parameters (if any).
 func (c Configuration) GetEnablePathEscape() bool {
@@ -552,6 +839,16 @@ func (c Configuration) GetEnableOptimizations() bool {
 	return c.EnableOptimizations
 }
 
+// GetAutoOptions returns the Configuration#AutoOptions.
+func (c Configuration) GetAutoOptions() bool {
+	return c.AutoOptions
+}
+
+// GetAutoHead returns the Configuration#AutoHead.
+func (c Configuration) GetAutoHead() bool {
+	return c.AutoHead
+}
+
 // GetFireMethodNotAllowed returns the Configuration#FireMethodNotAllowed.
 func (c Configuration) GetFireMethodNotAllowed() bool {
 	return c.FireMethodNotAllowed
@@ -589,6 +886,12 @@ func (c Configuration) GetCharset() string {
 	return c.Charset
 }
 
+// GetContentTypeCharsets returns the Configuration#ContentTypeCharsets,
+// the response charset of each content type which differs from the `Charset`.
+func (c Configuration) GetContentTypeCharsets() map[string]string {
+	return c.ContentTypeCharsets
+}
+
 // GetPostMaxMemory returns the maximum configured post data size
 // that a client can send to the server, this differs
 // from the overral request body size which can be modified
@@ -599,6 +902,60 @@ func (c Configuration) GetPostMaxMemory() int64 {
 	return c.PostMaxMemory
 }
 
+// GetUploadScanner returns the Configuration#UploadScanner,
+// the scanner of the uploaded files, if any.
+func (c Configuration) GetUploadScanner() context.Scanner {
+	return c.UploadScanner
+}
+
+// GetValidator returns the Configuration#Validator,
+// the validator of the decoded request's input, if any.
+func (c Configuration) GetValidator() context.Validator {
+	return c.Validator
+}
+
+// GetSecureCookie returns the Configuration#SecureCookie,
+// the keys of the signed and encrypted cookies, if any.
+func (c Configuration) GetSecureCookie() *context.SecureCookie {
+	return c.SecureCookie
+}
+
+// GetJSONCodec returns the Configuration#JSONCodec,
+// the custom JSON functions, if any.
+func (c Configuration) GetJSONCodec() *context.JSONCodec {
+	return c.JSONCodec
+}
+
+// GetPushViewAssets returns the Configuration#PushViewAssets,
+// if true then the `Context#View` pushes the stylesheets and the scripts of the views.
+func (c Configuration) GetPushViewAssets() bool {
+	return c.PushViewAssets
+}
+
+// GetLocalizer returns the Configuration#Localizer,
+// the translations provider of the `Context#Tr`, if any.
+func (c Configuration) GetLocalizer() context.Localizer {
+	return c.Localizer
+}
+
+// GetUserAgentRules returns the Configuration#UserAgentRules,
+// the ruleset of the `Context#UserAgentInfo`, if any.
+func (c Configuration) GetUserAgentRules() *context.UserAgentRules {
+	return c.UserAgentRules
+}
+
+// GetRequestLogger returns the Configuration#RequestLogger,
+// the factory of the `Context#Logger`, if any.
+func (c Configuration) GetRequestLogger() func(ctx context.Context) context.Logger {
+	return c.RequestLogger
+}
+
+// GetUploadContentTypeVerification returns the Configuration#UploadContentTypeVerification,
+// if true then the contents of the uploaded files are verified against their declared types.
+func (c Configuration) GetUploadContentTypeVerification() bool {
+	return c.UploadContentTypeVerification
+}
+
 // GetTranslateFunctionContextKey returns the configuration's TranslateFunctionContextKey value,
 // used for i18n.
 func (c Configuration) GetTranslateFunctionContextKey() string {
@@ -626,6 +983,22 @@ func (c Configuration) GetViewDataContextKey() string {
 	return c.ViewDataContextKey
 }
 
+// GetTrustedProxies returns the Configuration#TrustedProxies,
+// the CIDRs or IPs of the proxies whose forwarding headers are trusted.
+//
+// Look `context.ClientIP()` for more.
+func (c Configuration) GetTrustedProxies() []string {
+	return c.TrustedProxies
+}
+
+// GetTrustedProxyNets returns the parsed Configuration#TrustedProxies,
+// they are parsed once, when they are configured, the invalid ones are ignored.
+//
+// Look `context.ClientIP()` for more.
+func (c Configuration) GetTrustedProxyNets() []*net.IPNet {
+	return c.trustedProxyNets
+}
+
 // GetRemoteAddrHeaders returns the allowed request headers names
 // that can be valid to parse the client's IP based on.
 // By-default no "X-" header is consired safe to be used for retrieving the
@@ -685,6 +1058,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.DisablePathCorrection = v
 		}
 
+		if v := c.PathNormalization; v != (context.PathNormalization{}) {
+			main.PathNormalization = v
+		}
+
 		if v := c.EnablePathEscape; v {
 			main.EnablePathEscape = v
 		}
@@ -697,6 +1074,14 @@ func WithConfiguration(c Configuration) Configurator {
 			main.FireMethodNotAllowed = v
 		}
 
+		if v := c.AutoOptions; v {
+			main.AutoOptions = v
+		}
+
+		if v := c.AutoHead; v {
+			main.AutoHead = v
+		}
+
 		if v := c.DisableBodyConsumptionOnUnmarshal; v {
 			main.DisableBodyConsumptionOnUnmarshal = v
 		}
@@ -717,6 +1102,42 @@ func WithConfiguration(c Configuration) Configurator {
 			main.PostMaxMemory = v
 		}
 
+		if v := c.UploadScanner; v != nil {
+			main.UploadScanner = v
+		}
+
+		if v := c.Validator; v != nil {
+			main.Validator = v
+		}
+
+		if v := c.SecureCookie; v != nil {
+			main.SecureCookie = v
+		}
+
+		if v := c.JSONCodec; v != nil {
+			main.JSONCodec = v
+		}
+
+		if v := c.PushViewAssets; v {
+			main.PushViewAssets = v
+		}
+
+		if v := c.Localizer; v != nil {
+			main.Localizer = v
+		}
+
+		if v := c.UserAgentRules; v != nil {
+			main.UserAgentRules = v
+		}
+
+		if v := c.RequestLogger; v != nil {
+			main.RequestLogger = v
+		}
+
+		if v := c.UploadContentTypeVerification; v {
+			main.UploadContentTypeVerification = v
+		}
+
 		if v := c.TranslateFunctionContextKey; v != "" {
 			main.TranslateFunctionContextKey = v
 		}
@@ -733,6 +1154,15 @@ func WithConfiguration(c Configuration) Configurator {
 			main.ViewDataContextKey = v
 		}
 
+		if v := c.ContentTypeCharsets; len(v) > 0 {
+			if main.ContentTypeCharsets == nil {
+				main.ContentTypeCharsets = make(map[string]string, len(v))
+			}
+			for key, value := range v {
+				main.ContentTypeCharsets[key] = value
+			}
+		}
+
 		if v := c.RemoteAddrHeaders; len(v) > 0 {
 			if main.RemoteAddrHeaders == nil {
 				main.RemoteAddrHeaders = make(map[string]bool, len(v))
@@ -742,6 +1172,11 @@ func WithConfiguration(c Configuration) Configurator {
 			}
 		}
 
+		if v := c.TrustedProxies; len(v) > 0 {
+			main.TrustedProxies = v
+			app.parseTrustedProxies()
+		}
+
 		if v := c.Other; len(v) > 0 {
 			if main.Other == nil {
 				main.Other = make(map[string]interface{}, len(v))
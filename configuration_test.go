@@ -325,3 +325,20 @@ Charset = "UTF-8"
 		t.Fatalf("error on TestConfigurationTOML: Expected Other['MyServerName'] %s but got %s", expected, got)
 	}
 }
+
+func TestConfigurationTrustedProxies(t *testing.T) {
+	app := New()
+	app.Logger().SetLevel("disable")
+	app.Configure(WithTrustedProxies("10.0.0.0/8", "invalid"), WithTrustedProxies("::1"))
+
+	nets := app.ConfigurationReadOnly().GetTrustedProxyNets()
+	if len(nets) != 2 || nets[0].String() != "10.0.0.0/8" || nets[1].String() != "::1/128" {
+		t.Fatalf("expected the valid trusted proxies to be parsed but got: %v", nets)
+	}
+
+	app.Configure(WithConfiguration(Configuration{TrustedProxies: []string{"127.0.0.1"}}))
+	nets = app.ConfigurationReadOnly().GetTrustedProxyNets()
+	if len(nets) != 1 || nets[0].String() != "127.0.0.1/32" {
+		t.Fatalf("expected the trusted proxies to be replaced but got: %v", nets)
+	}
+}
@@ -22,6 +22,11 @@ type Application interface {
 	// Use context.View to render templates to the client instead.
 	// Returns an error on failure, otherwise nil.
 	View(writer io.Writer, filename string, layout string, bindingData interface{}) error
+	// ViewFuncs is like `View` but the "funcs" are available to this template execution only,
+	// the registered view engine's functions are not modified.
+	//
+	// Use context.ViewFunc and context.View instead.
+	ViewFuncs(writer io.Writer, filename string, layout string, bindingData interface{}, funcs map[string]interface{}) error
 
 	// ServeHTTPC is the internal router, it's visible because it can be used for advanced use cases,
 	// i.e: routing within a foreign context.
@@ -0,0 +1,204 @@
+package context
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/textproto"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/core/msgpack"
+)
+
+// BindError is the failure to bind a single field by the `Context#Bind` or the `Context#ReadQuery`.
+type BindError struct {
+	// Source is the request's source of the value: "param", "query" or "header".
+	Source string
+	// Field is the name of the value on its source, i.e the query's key.
+	Field string
+	// Value is the value which could not be decoded.
+	Value string
+	// Err is the reason of the failure.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BindError) Error() string {
+	return fmt.Sprintf("bind: %s %q: %v", e.Source, e.Field, e.Err)
+}
+
+// BindErrors is the error of the `Context#Bind` and the `Context#ReadQuery`,
+// it contains all the fields that could not be bound.
+type BindErrors []*BindError
+
+// Error implements the error interface.
+func (e BindErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// The struct field tags of the `Context#Bind`, by order of precedence.
+const (
+	bindParamTag  = "param"
+	bindQueryTag  = "query"
+	bindHeaderTag = "header"
+)
+
+// The struct field tags of the `Context#ReadQuery`.
+const (
+	readQueryTag   = "url"
+	readDefaultTag = "default"
+)
+
+var (
+	errBindPtr         = errors.New("bind: expected a pointer to a struct but got %T")
+	errBindContentType = errors.New("bind: unsupported content type '%s'")
+)
+
+// bindBody decodes the request body to the "ptr" by the request's content type.
+func (ctx *context) bindBody(ptr interface{}) error {
+	if ctx.request.Body == nil || ctx.request.ContentLength == 0 {
+		return nil
+	}
+
+	contentType := strings.ToLower(baseContentType(ctx.GetHeader(ContentTypeHeaderKey)))
+	// the value is validated once, after all of its fields are bound.
+	switch {
+	case contentType == "" || strings.HasSuffix(contentType, "json"):
+		return ctx.unmarshalBody(ptr, UnmarshalerFunc(ctx.jsonCodec().Unmarshal))
+	case strings.HasSuffix(contentType, "xml"):
+		return ctx.unmarshalBody(ptr, UnmarshalerFunc(xml.Unmarshal))
+	case strings.HasSuffix(contentType, "yaml"):
+		ctx.request.Body = limitBody(ctx.request.Body, DefaultYAMLReaderOptions.MaxBodySize)
+		return ctx.unmarshalBody(ptr, DefaultYAMLReaderOptions)
+	case contentType == ContentMsgPackHeaderValue:
+		return ctx.unmarshalBody(ptr, UnmarshalerFunc(msgpack.Unmarshal))
+	case contentType == ContentFormHeaderValue:
+		if err := ctx.request.ParseForm(); err != nil {
+			return err
+		}
+		return decodeForm(ctx.request.PostForm, ptr)
+	case contentType == ContentFormMultipartHeaderValue:
+		if err := ctx.parseMultipartForm(); err != nil {
+			return err
+		}
+		return decodeForm(ctx.request.MultipartForm.Value, ptr)
+	}
+
+	return errBindContentType.Format(contentType)
+}
+
+// bindFields fills the tagged fields of the "v" struct from the path parameters,
+// the url query and the headers of the request.
+func (ctx *context) bindFields(v reflect.Value, errs BindErrors) BindErrors {
+	typ := v.Type()
+	query := ctx.request.URL.Query()
+
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		f := typ.Field(i)
+		field := v.Field(i)
+
+		if f.Anonymous && field.Kind() == reflect.Struct {
+			errs = ctx.bindFields(field, errs)
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported.
+			continue
+		}
+
+		var (
+			source string
+			name   string
+			vals   []string
+		)
+
+		if name = f.Tag.Get(bindParamTag); name != "" && name != "-" {
+			source = bindParamTag
+			if value := ctx.params.Get(name); value != "" {
+				vals = []string{value}
+			}
+		} else if name = f.Tag.Get(bindQueryTag); name != "" && name != "-" {
+			source = bindQueryTag
+			vals = query[name]
+		} else if name = f.Tag.Get(bindHeaderTag); name != "" && name != "-" {
+			source = bindHeaderTag
+			vals = ctx.request.Header[textproto.CanonicalMIMEHeaderKey(name)]
+		} else {
+			continue
+		}
+
+		if len(vals) == 0 || vals[0] == "" {
+			continue
+		}
+
+		if field.Kind() == reflect.Slice {
+			// the values override the body's slice, they are not appended to it.
+			field.Set(reflect.Zero(field.Type()))
+		}
+
+		if err := decodeFormValue(field, nil, vals); err != nil {
+			errs = append(errs, &BindError{Source: source, Field: name, Value: vals[0], Err: err})
+		}
+	}
+
+	return errs
+}
+
+// bindQuery fills the fields of the "v" struct from the url "query",
+// the missing values are set by their `default` tag.
+func bindQuery(v reflect.Value, query url.Values, errs BindErrors) BindErrors {
+	typ := v.Type()
+
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		f := typ.Field(i)
+		field := v.Field(i)
+
+		if f.Anonymous && field.Kind() == reflect.Struct {
+			errs = bindQuery(field, query, errs)
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported.
+			continue
+		}
+
+		name := f.Tag.Get(readQueryTag)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		vals := query[name]
+		if len(vals) == 0 || vals[0] == "" {
+			def, ok := f.Tag.Lookup(readDefaultTag)
+			if !ok {
+				continue
+			}
+
+			vals = []string{def}
+			if kind := f.Type.Kind(); kind == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 || kind == reflect.Array {
+				vals = strings.Split(def, ",")
+			}
+		}
+
+		if field.Kind() == reflect.Slice {
+			// the values override the slice's initial value, they are not appended to it.
+			field.Set(reflect.Zero(field.Type()))
+		}
+
+		if err := decodeFormValue(field, nil, vals); err != nil {
+			errs = append(errs, &BindError{Source: bindQueryTag, Field: name, Value: strings.Join(vals, ","), Err: err})
+		}
+	}
+
+	return errs
+}
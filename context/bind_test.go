@@ -0,0 +1,84 @@
+package context_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+type testBindPage struct {
+	Page int `query:"page" json:"page" form:"page"`
+}
+
+type testBindInput struct {
+	testBindPage
+	ID      int64    `param:"id" json:"id" xml:"id" form:"id"`
+	Name    string   `query:"name" json:"name" xml:"name" form:"name"`
+	Token   string   `header:"X-Token" json:"token" xml:"token" form:"token"`
+	Source  string   `query:"source" header:"X-Source" json:"source" form:"source"`
+	Tags    []string `query:"tag" json:"tags" form:"tags"`
+	Retries int      `header:"X-Retries" json:"retries" form:"retries"`
+	Body    string   `json:"body" xml:"body" form:"body"`
+}
+
+func TestBind(t *testing.T) {
+	app := iris.New()
+	handler := func(ctx context.Context) {
+		var in testBindInput
+		if err := ctx.Bind(&in); err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.WriteString(err.Error())
+			return
+		}
+
+		ctx.Writef("%d %s %s %s %d %s %d %s", in.ID, in.Name, in.Token, in.Source, in.Page, strings.Join(in.Tags, ","), in.Retries, in.Body)
+	}
+	app.Post("/users/{id:long}", handler)
+	app.Post("/users", handler)
+	app.Post("/value", func(ctx context.Context) {
+		var in testBindInput
+		ctx.WriteString(fmt.Sprint(ctx.Bind(in)))
+	})
+
+	e := httptest.New(t, app)
+	body := `{"id":1,"name":"body","token":"body","source":"body","page":9,"tags":["body"],"retries":1,"body":"body"}`
+
+	// the path parameters, the query and the headers override the body's values.
+	e.POST("/users/42").WithQuery("name", "query").WithQuery("page", 2).WithQuery("tag", "a").WithQuery("tag", "b").
+		WithHeader("X-Token", "header").WithHeader("X-Retries", "3").WithHeader("Content-Type", "application/json").
+		WithBytes([]byte(body)).Expect().Status(iris.StatusOK).
+		Body().Equal("42 query header body 2 a,b 3 body")
+
+	// the missing and the empty sources keep the body's values.
+	e.POST("/users").WithQuery("name", "").WithHeader("Content-Type", "application/json").
+		WithBytes([]byte(body)).Expect().Status(iris.StatusOK).
+		Body().Equal("1 body body body 9 body 1 body")
+
+	// the first tag of a field is its source, the query wins over the header.
+	e.POST("/users/42").WithQuery("source", "query").WithHeader("X-Source", "header").Expect().Status(iris.StatusOK).
+		Body().Equal("42   query 0  0 ")
+
+	// the other content types of the body.
+	e.POST("/users/42").WithQuery("name", "query").
+		WithFormField("name", "form").WithFormField("body", "form").WithFormField("page", 5).
+		Expect().Status(iris.StatusOK).Body().Equal("42 query   5  0 form")
+	e.POST("/users").WithHeader("Content-Type", "application/xml").
+		WithBytes([]byte(`<testBindInput><id>7</id><name>xml</name><body>xml</body></testBindInput>`)).
+		Expect().Status(iris.StatusOK).Body().Equal("7 xml   0  0 xml")
+
+	// the failed fields of all the sources are reported together.
+	resp := e.POST("/users/42").WithQuery("page", "two").WithHeader("X-Retries", "three").
+		Expect().Status(iris.StatusBadRequest)
+	resp.Body().Contains(`bind: query "page": `).Contains(`; bind: header "X-Retries": `)
+
+	e.POST("/users/42").WithHeader("Content-Type", "text/plain").WithBytes([]byte("text")).
+		Expect().Status(iris.StatusBadRequest).Body().Equal("bind: unsupported content type 'text/plain'")
+	e.POST("/users/42").WithHeader("Content-Type", "application/json").WithBytes([]byte("{")).
+		Expect().Status(iris.StatusBadRequest).Body().Equal("unexpected end of JSON input")
+	e.POST("/value").Expect().Status(iris.StatusOK).
+		Body().Equal("bind: expected a pointer to a struct but got context_test.testBindInput")
+}
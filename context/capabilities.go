@@ -0,0 +1,285 @@
+package context
+
+// The capabilities of the framework that are not available on every version of it,
+// see `Context#Supports`.
+//
+// They are untyped strings, so middleware can check for them
+// without importing a specific version of this package, i.e:
+//
+//	if c, ok := ctx.(interface{ Supports(string) bool }); ok && c.Supports("read-json-stream") {
+//	    ...
+//	}
+const (
+	// CapabilityReadJSONStream is the `Context#ReadJSONStream`.
+	CapabilityReadJSONStream = "read-json-stream"
+	// CapabilityReadJSONOptions is the `Context#ReadJSON` with `JSONReader` options.
+	CapabilityReadJSONOptions = "read-json-options"
+	// CapabilityMsgPack is the `Context#MsgPack` and `Context#ReadMsgPack`.
+	CapabilityMsgPack = "msgpack"
+	// CapabilityProtobuf is the `Context#Protobuf` and `Context#ReadProtobuf`.
+	CapabilityProtobuf = "protobuf"
+	// CapabilityReadYAML is the `Context#ReadYAML` with its decoding limits.
+	CapabilityReadYAML = "read-yaml"
+	// CapabilityStream is the `Context#Stream` with its buffered `StreamWriter`.
+	CapabilityStream = "stream"
+	// CapabilityJSONStream is the `Context#JSONStream` and `Context#NDJSON`.
+	CapabilityJSONStream = "json-stream"
+	// CapabilityReadMultipart is the `Context#ReadMultipart`.
+	CapabilityReadMultipart = "read-multipart"
+	// CapabilityBind is the `Context#Bind`.
+	CapabilityBind = "bind"
+	// CapabilityValidator is the `Configuration#Validator` of the decoded request's input.
+	CapabilityValidator = "validator"
+	// CapabilityMultipartStream is the `Context#MultipartStream`.
+	CapabilityMultipartStream = "multipart-stream"
+	// CapabilityBodyDigest is the "Digest" header verification of the multipart forms, see `Context#BodyDigest`.
+	CapabilityBodyDigest = "body-digest"
+	// CapabilityUploadScanner is the `Configuration#UploadScanner` of the uploaded files.
+	CapabilityUploadScanner = "upload-scanner"
+	// CapabilityUploadOptions is the `Context#UploadFormFilesWith`.
+	CapabilityUploadOptions = "upload-options"
+	// CapabilityUploadContentTypeVerification is the `Configuration#UploadContentTypeVerification`.
+	CapabilityUploadContentTypeVerification = "upload-content-type-verification"
+	// CapabilityCompress is the `Context#Compress` and the `RegisterCompressor` of the response encodings.
+	CapabilityCompress = "compress"
+	// CapabilityNoGzip is the `NoGzip` handler.
+	CapabilityNoGzip = "no-gzip"
+	// CapabilityDecompressBody is the `DecompressBody` handler and the `Route#Decompress`.
+	CapabilityDecompressBody = "decompress-body"
+	// CapabilityTypedValues is the typed keys of the request's values, i.e `StringKey`.
+	CapabilityTypedValues = "typed-values"
+	// CapabilityRedirectPreserveMethod is the `Context#RedirectPreserveMethod`.
+	CapabilityRedirectPreserveMethod = "redirect-preserve-method"
+	// CapabilityCookieOptions is the `CookieOption`s of the cookie helpers and the sessions.
+	CapabilityCookieOptions = "cookie-options"
+	// CapabilitySecureCookie is the `Context#SetSecureCookie` and `Context#GetSecureCookie`.
+	CapabilitySecureCookie = "secure-cookie"
+	// CapabilityTrustedProxies is the `Context#ClientIP` and the `Configuration#TrustedProxies`.
+	CapabilityTrustedProxies = "trusted-proxies"
+	// CapabilityRequestID is the `Context#RequestID` and the `middleware/requestid`.
+	CapabilityRequestID = "request-id"
+	// CapabilityStdContext is the `Context#Deadline`, `Done`, `Err` and `Value` and the `Route#Timeout`.
+	CapabilityStdContext = "std-context"
+	// CapabilityJSONCodec is the `Configuration#JSONCodec`.
+	CapabilityJSONCodec = "json-codec"
+	// CapabilityProblem is the `Context#Problem` and the `ProblemHandler`.
+	CapabilityProblem = "problem"
+	// CapabilityPush is the `Context#Push` and the `Configuration#PushViewAssets`.
+	CapabilityPush = "push"
+	// CapabilityETag is the `Context#WriteWithEtag` and the `ETag` middleware.
+	CapabilityETag = "etag"
+	// CapabilityRecorderFlushHooks is the `ResponseRecorder#OnFlush`.
+	CapabilityRecorderFlushHooks = "recorder-flush-hooks"
+	// CapabilityTransactionRollbacks is the `Transaction#OnRollback`, `BeginTransaction` and `CapturePanics`.
+	CapabilityTransactionRollbacks = "transaction-rollbacks"
+	// CapabilityReadQuery is the `Context#ReadQuery`.
+	CapabilityReadQuery = "read-query"
+	// CapabilityServeContentReader is the `Context#ServeContentReader`.
+	CapabilityServeContentReader = "serve-content-reader"
+	// CapabilityViewFuncs is the `Context#ViewFunc`.
+	CapabilityViewFuncs = "view-funcs"
+	// CapabilityLocalizer is the `Context#Tr` and the `Localizer`.
+	CapabilityLocalizer = "localizer"
+	// CapabilityUserAgentInfo is the `Context#UserAgentInfo` and the `Context#IsBot`.
+	CapabilityUserAgentInfo = "user-agent-info"
+	// CapabilityTypedParams is the `RequestParams#GetValue` and its typed getters.
+	CapabilityTypedParams = "typed-params"
+	// CapabilityParamTypes is the `RequestParams#VisitTyped` and `RequestParams#GetType`.
+	CapabilityParamTypes = "param-types"
+	// CapabilityStopWithStatus is the `Context#StopWithStatus`, `Context#StopWithJSON` and `Context#StopWithProblem`.
+	CapabilityStopWithStatus = "stop-with-status"
+	// CapabilityBufferResponse is the `Context#BufferResponse` and the `ResponseRecorder#SetBufferLimit`.
+	CapabilityBufferResponse = "buffer-response"
+	// CapabilityRequestLogger is the `Context#Logger` and the `Configuration#RequestLogger`.
+	CapabilityRequestLogger = "request-logger"
+	// CapabilityHijack is the `Context#Hijack` and `Context#IsHijacked`.
+	CapabilityHijack = "hijack"
+	// CapabilityFormFiles is the `Context#FormFiles`.
+	CapabilityFormFiles = "form-files"
+	// CapabilityRecordRequestBody is the `Context#RecordRequestBody`.
+	CapabilityRecordRequestBody = "record-request-body"
+	// CapabilityCharsets is the charset decoding of the request bodies and the `Configuration#ContentTypeCharsets`.
+	CapabilityCharsets = "charsets"
+	// CapabilityUploadProgress is the `Context#TrackUploadProgress` and the `middleware/uploadprogress`.
+	CapabilityUploadProgress = "upload-progress"
+	// CapabilityCanonicalQuery is the `Context#URLWithParams` and `Context#CanonicalQuery`.
+	CapabilityCanonicalQuery = "canonical-query"
+	// CapabilitySendReader is the `Context#SendReader` and the RFC 5987 filenames of the `ContentDisposition`.
+	CapabilitySendReader = "send-reader"
+	// CapabilityNegotiate is the `Context#Negotiate`.
+	CapabilityNegotiate = "negotiate"
+	// CapabilityPathNormalization is the `Configuration#PathNormalization`.
+	CapabilityPathNormalization = "path-normalization"
+	// CapabilityMessages is the translation of the framework's client-facing messages, see `Context#Message`.
+	CapabilityMessages = "messages"
+	// CapabilityRouteMaxBodySize is the route's request body limit.
+	CapabilityRouteMaxBodySize = "route-max-body-size"
+	// CapabilityRouteIPFilter is the route's client IP allow and deny lists.
+	CapabilityRouteIPFilter = "route-ip-filter"
+	// CapabilityRouteCanary is the route's traffic split between two handler versions.
+	CapabilityRouteCanary = "route-canary"
+	// CapabilityRouteShadow is the route's traffic mirroring to a shadow handler.
+	CapabilityRouteShadow = "route-shadow"
+	// CapabilityAutoOptions is the `Configuration#AutoOptions`.
+	CapabilityAutoOptions = "auto-options"
+	// CapabilityAutoHead is the `Configuration#AutoHead`.
+	CapabilityAutoHead = "auto-head"
+	// CapabilityHostParties is the router's full hostname parties.
+	CapabilityHostParties = "host-parties"
+	// CapabilityUint64Params is the ":uint64" path parameters and the `RequestParams#GetUint64`.
+	CapabilityUint64Params = "uint64-params"
+	// CapabilityHandleIf is the `Party#HandleIf` of the feature-flag routes.
+	CapabilityHandleIf = "handle-if"
+	// CapabilitySegmentParams is the more than one path parameters per segment, i.e "/{name}.{format}".
+	CapabilitySegmentParams = "segment-params"
+	// CapabilityReadFormNested is the `Context#ReadForm` of the nested structs, slices and maps.
+	CapabilityReadFormNested = "read-form-nested"
+
+	// CapabilitySessionsRedisCluster is the sentinel and cluster modes of the redis sessions database.
+	CapabilitySessionsRedisCluster = "sessions-redis-cluster"
+	// CapabilitySessionsMemcached is the memcached sessions database.
+	CapabilitySessionsMemcached = "sessions-memcached"
+	// CapabilitySessionsSQL is the SQL sessions database.
+	CapabilitySessionsSQL = "sessions-sql"
+	// CapabilitySessionsDynamoDB is the DynamoDB sessions database.
+	CapabilitySessionsDynamoDB = "sessions-dynamodb"
+	// CapabilitySessionsStateless is the sessions' `Config#Stateless` encrypted cookie mode.
+	CapabilitySessionsStateless = "sessions-stateless"
+	// CapabilitySessionsExpiration is the sessions' `Config#SlidingExpiration`, `AbsoluteExpiration` and `IdleTimeout`.
+	CapabilitySessionsExpiration = "sessions-expiration"
+	// CapabilitySessionsRotateID is the `Session#RotateID`.
+	CapabilitySessionsRotateID = "sessions-rotate-id"
+	// CapabilitySessionsTypedFlashes is the typed flash messages' getters, i.e `Session#GetFlashInt`.
+	CapabilitySessionsTypedFlashes = "sessions-typed-flashes"
+	// CapabilitySessionsEvents is the sessions' `OnCreate`, `OnUpdate` and `OnDestroy` listeners.
+	CapabilitySessionsEvents = "sessions-events"
+	// CapabilitySessionsDecode is the `Session#Decode` of the stored structs.
+	CapabilitySessionsDecode = "sessions-decode"
+	// CapabilitySessionsGC is the sessions' `Config#GCInterval`, `Sweep` and `Stats`.
+	CapabilitySessionsGC = "sessions-gc"
+	// CapabilitySessionsLocks is the per-session locks, see the sessions' `Config#LockTimeout`.
+	CapabilitySessionsLocks = "sessions-locks"
+	// CapabilitySessionsJWT is the sessions' `Config#JWT` mode.
+	CapabilitySessionsJWT = "sessions-jwt"
+	// CapabilitySessionsHandler is the `Sessions#Handler` of the multiple session managers per Party.
+	CapabilitySessionsHandler = "sessions-handler"
+	// CapabilitySessionsRemember is the remember-me persistent login tokens of the "sessions/remember".
+	CapabilitySessionsRemember = "sessions-remember"
+	// CapabilitySessionsMigrate is the `sessions.Migrate` between the sessions databases.
+	CapabilitySessionsMigrate = "sessions-migrate"
+	// CapabilitySessionsIDGenerator is the sessions' configurable id length, alphabet and entropy validation.
+	CapabilitySessionsIDGenerator = "sessions-id-generator"
+	// CapabilitySessionsStore is the `sessions.Store` dependency of the mvc controllers.
+	CapabilitySessionsStore = "sessions-store"
+	// CapabilitySessionsDatabaseOptions is the tuning options of the badger and boltdb sessions databases.
+	CapabilitySessionsDatabaseOptions = "sessions-database-options"
+
+	// CapabilityWebsocketRooms is the websocket `Server#JoinRoom`, `LeaveRoom`, `Rooms` and the room emitter.
+	CapabilityWebsocketRooms = "websocket-rooms"
+	// CapabilityWebsocketExchange is the websocket `Server#UseExchange` to broadcast across instances.
+	CapabilityWebsocketExchange = "websocket-exchange"
+	// CapabilityWebsocketCodecs is the websocket `Server#RegisterCodec` of the binary messages.
+	CapabilityWebsocketCodecs = "websocket-codecs"
+	// CapabilityWebsocketWriteQueue is the websocket `Config#WriteQueueSize` and `WriteQueuePolicy`.
+	CapabilityWebsocketWriteQueue = "websocket-write-queue"
+	// CapabilityWebsocketHeartbeat is the websocket `Config#MaxMissedPongs` and the `Connection#OnTimeout`.
+	CapabilityWebsocketHeartbeat = "websocket-heartbeat"
+	// CapabilityWebsocketAuthenticate is the websocket `Config#Authenticate` and the `Connection#Identity`.
+	CapabilityWebsocketAuthenticate = "websocket-authenticate"
+	// CapabilityWebsocketAck is the websocket `Connection#EmitWithAck` and `Connection#OnRequest`.
+	CapabilityWebsocketAck = "websocket-ack"
+	// CapabilityWebsocketSchema is the websocket `Server#DeclareEvent` of the typed events.
+	CapabilityWebsocketSchema = "websocket-schema"
+)
+
+var capabilities = map[string]struct{}{
+	CapabilityReadJSONStream:                {},
+	CapabilityReadJSONOptions:               {},
+	CapabilityMsgPack:                       {},
+	CapabilityProtobuf:                      {},
+	CapabilityReadYAML:                      {},
+	CapabilityStream:                        {},
+	CapabilityJSONStream:                    {},
+	CapabilityReadMultipart:                 {},
+	CapabilityBind:                          {},
+	CapabilityValidator:                     {},
+	CapabilityMultipartStream:               {},
+	CapabilityBodyDigest:                    {},
+	CapabilityUploadScanner:                 {},
+	CapabilityUploadOptions:                 {},
+	CapabilityUploadContentTypeVerification: {},
+	CapabilityCompress:                      {},
+	CapabilityNoGzip:                        {},
+	CapabilityDecompressBody:                {},
+	CapabilityTypedValues:                   {},
+	CapabilityRedirectPreserveMethod:        {},
+	CapabilityCookieOptions:                 {},
+	CapabilitySecureCookie:                  {},
+	CapabilityTrustedProxies:                {},
+	CapabilityRequestID:                     {},
+	CapabilityStdContext:                    {},
+	CapabilityJSONCodec:                     {},
+	CapabilityProblem:                       {},
+	CapabilityPush:                          {},
+	CapabilityETag:                          {},
+	CapabilityRecorderFlushHooks:            {},
+	CapabilityTransactionRollbacks:          {},
+	CapabilityReadQuery:                     {},
+	CapabilityServeContentReader:            {},
+	CapabilityViewFuncs:                     {},
+	CapabilityLocalizer:                     {},
+	CapabilityUserAgentInfo:                 {},
+	CapabilityTypedParams:                   {},
+	CapabilityParamTypes:                    {},
+	CapabilityStopWithStatus:                {},
+	CapabilityBufferResponse:                {},
+	CapabilityRequestLogger:                 {},
+	CapabilityHijack:                        {},
+	CapabilityFormFiles:                     {},
+	CapabilityRecordRequestBody:             {},
+	CapabilityCharsets:                      {},
+	CapabilityUploadProgress:                {},
+	CapabilityCanonicalQuery:                {},
+	CapabilitySendReader:                    {},
+	CapabilityNegotiate:                     {},
+	CapabilityPathNormalization:             {},
+	CapabilityMessages:                      {},
+	CapabilityRouteMaxBodySize:              {},
+	CapabilityRouteIPFilter:                 {},
+	CapabilityRouteCanary:                   {},
+	CapabilityRouteShadow:                   {},
+	CapabilityAutoOptions:                   {},
+	CapabilityAutoHead:                      {},
+	CapabilityHostParties:                   {},
+	CapabilityUint64Params:                  {},
+	CapabilityHandleIf:                      {},
+	CapabilitySegmentParams:                 {},
+	CapabilityReadFormNested:                {},
+	CapabilitySessionsRedisCluster:          {},
+	CapabilitySessionsMemcached:             {},
+	CapabilitySessionsSQL:                   {},
+	CapabilitySessionsDynamoDB:              {},
+	CapabilitySessionsStateless:             {},
+	CapabilitySessionsExpiration:            {},
+	CapabilitySessionsRotateID:              {},
+	CapabilitySessionsTypedFlashes:          {},
+	CapabilitySessionsEvents:                {},
+	CapabilitySessionsDecode:                {},
+	CapabilitySessionsGC:                    {},
+	CapabilitySessionsLocks:                 {},
+	CapabilitySessionsJWT:                   {},
+	CapabilitySessionsHandler:               {},
+	CapabilitySessionsRemember:              {},
+	CapabilitySessionsMigrate:               {},
+	CapabilitySessionsIDGenerator:           {},
+	CapabilitySessionsStore:                 {},
+	CapabilitySessionsDatabaseOptions:       {},
+	CapabilityWebsocketRooms:                {},
+	CapabilityWebsocketExchange:             {},
+	CapabilityWebsocketCodecs:               {},
+	CapabilityWebsocketWriteQueue:           {},
+	CapabilityWebsocketHeartbeat:            {},
+	CapabilityWebsocketAuthenticate:         {},
+	CapabilityWebsocketAck:                  {},
+	CapabilityWebsocketSchema:               {},
+}
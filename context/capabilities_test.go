@@ -0,0 +1,91 @@
+package context_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+// declaredCapabilities returns the values of the `Capability...` constants of the capabilities.go by their names.
+func declaredCapabilities(t *testing.T) map[string]string {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "capabilities.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	declared := make(map[string]string)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				if !strings.HasPrefix(name.Name, "Capability") {
+					continue
+				}
+
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					t.Fatalf("expected the %s to be a string literal", name.Name)
+				}
+
+				s, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				declared[name.Name] = s
+			}
+		}
+	}
+
+	return declared
+}
+
+func TestSupports(t *testing.T) {
+	declared := declaredCapabilities(t)
+	if len(declared) == 0 {
+		t.Fatal("expected the capabilities.go to declare the capabilities")
+	}
+
+	names := make(map[string]string, len(declared))
+	for name, capability := range declared {
+		if other, ok := names[capability]; ok {
+			t.Fatalf("expected the %s and %s to have different values but both are '%s'", name, other, capability)
+		}
+		names[capability] = name
+	}
+
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		for name, capability := range declared {
+			if !ctx.Supports(capability) {
+				t.Errorf("expected the %s ('%s') to be supported, is it missing from the capabilities map?", name, capability)
+			}
+		}
+
+		if ctx.Supports("") || ctx.Supports("unknown-capability") {
+			t.Error("expected an unknown capability to not be supported")
+		}
+
+		// the check of the middleware which are written against more than one versions of the framework.
+		var c interface{} = ctx
+		s, ok := c.(interface{ Supports(string) bool })
+		if !ok || !s.Supports("read-json-stream") {
+			t.Error("expected the context to support the capabilities by their untyped strings")
+		}
+	})
+
+	httptest.New(t, app).GET("/").Expect().Status(httptest.StatusOK)
+}
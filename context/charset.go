@@ -0,0 +1,106 @@
+package context
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kataras/iris/core/errors"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ErrUnsupportedCharset is returned by the body readers when the "Content-Type"
+// request header declares a charset which can not be decoded.
+var ErrUnsupportedCharset = errors.New("request body: unsupported charset '%s'")
+
+// formCharsetDecodedContextKey is set when the form's values are decoded, see `decodeFormCharset`.
+const formCharsetDecodedContextKey = "iris.form_charset_decoded"
+
+// requestCharset returns the charset of the "Content-Type" request header,
+// or empty if it's missing or it's an UTF-8 compatible one.
+func requestCharset(r *http.Request) string {
+	contentType := r.Header.Get(ContentTypeHeaderKey)
+	if !strings.Contains(contentType, "charset") {
+		return ""
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch charset := strings.ToLower(params["charset"]); charset {
+	case "", "utf-8", "utf8", "us-ascii":
+		return ""
+	default:
+		return charset
+	}
+}
+
+// charsetDecoder returns the decoder of the "charset",
+// which is one of the names of the https://encoding.spec.whatwg.org/, i.e "iso-8859-1" or "shift_jis".
+func charsetDecoder(charset string) (*encoding.Decoder, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, ErrUnsupportedCharset.Format(charset)
+	}
+
+	return enc.NewDecoder(), nil
+}
+
+// decodeCharset converts the "b" request body to UTF-8, by the charset of its "Content-Type".
+func (ctx *context) decodeCharset(b []byte) ([]byte, error) {
+	charset := requestCharset(ctx.request)
+	if charset == "" {
+		return b, nil
+	}
+
+	dec, err := charsetDecoder(charset)
+	if err != nil {
+		ctx.StatusCode(http.StatusUnsupportedMediaType)
+		return nil, err
+	}
+
+	return dec.Bytes(b)
+}
+
+// decodeFormCharset converts the parsed post form's values to UTF-8, once,
+// by the charset of the "Content-Type". The values are decoded after the parsing
+// because the percent-encoded bytes are of that charset too.
+// The form is rebuilt by the decoded post form's values and the url query's ones.
+func (ctx *context) decodeFormCharset() error {
+	if len(ctx.request.PostForm) == 0 || ctx.values.GetBoolDefault(formCharsetDecodedContextKey, false) {
+		return nil
+	}
+	ctx.values.Set(formCharsetDecodedContextKey, true)
+
+	charset := requestCharset(ctx.request)
+	if charset == "" {
+		return nil
+	}
+
+	dec, err := charsetDecoder(charset)
+	if err != nil {
+		return err
+	}
+
+	form := make(url.Values, len(ctx.request.PostForm))
+	for key, values := range ctx.request.PostForm {
+		for i, v := range values {
+			if values[i], err = dec.String(v); err != nil {
+				return err
+			}
+		}
+		form[key] = append(form[key], values...)
+	}
+
+	for key, values := range ctx.request.URL.Query() {
+		form[key] = append(form[key], values...)
+	}
+
+	ctx.request.Form = form
+	return nil
+}
@@ -0,0 +1,90 @@
+package context
+
+import (
+	"net"
+	"strings"
+)
+
+// ForwardedHeaderKey is the header key of "Forwarded", see RFC 7239.
+const ForwardedHeaderKey = "Forwarded"
+
+// trustedProxies are the parsed `Configuration#TrustedProxies`, see `ConfigurationReadOnly#GetTrustedProxyNets`.
+type trustedProxies []*net.IPNet
+
+func (t trustedProxies) contains(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range t {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forwardedFor returns the "for" addresses of a "Forwarded" header value,
+// i.e `for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"`.
+func forwardedFor(header string) (addrs []string) {
+	for _, element := range strings.Split(header, ",") {
+		for _, pair := range strings.Split(element, ";") {
+			pair = strings.TrimSpace(pair)
+			if len(pair) < 4 || !strings.EqualFold(pair[:4], "for=") {
+				continue
+			}
+
+			addrs = append(addrs, stripAddrPort(strings.Trim(pair[4:], `"`)))
+		}
+	}
+
+	return
+}
+
+// stripAddrPort returns the IP of an "ip", "ip:port", "[ipv6]" or "[ipv6]:port" address.
+func stripAddrPort(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+}
+
+// clientIP returns the client's IP based on the "peer", the address of the connection,
+// and the forwarding headers, which are trusted only if the peer is one of the "proxies".
+// The forwarded addresses are read from right to left, the first one which is not
+// a trusted proxy is the client.
+func clientIP(peer string, proxies trustedProxies, header func(string) string) string {
+	if !proxies.contains(peer) {
+		return peer
+	}
+
+	var chain []string
+	if h := header(ForwardedHeaderKey); h != "" {
+		chain = forwardedFor(h)
+	} else if h := header(xForwardedForHeaderKey); h != "" {
+		for _, addr := range strings.Split(h, ",") {
+			chain = append(chain, stripAddrPort(addr))
+		}
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if addr := chain[i]; net.ParseIP(addr) != nil && !proxies.contains(addr) {
+			return addr
+		}
+	}
+
+	if len(chain) > 0 && net.ParseIP(chain[0]) != nil {
+		// all of them are trusted proxies.
+		return chain[0]
+	}
+
+	if realIP := stripAddrPort(header("X-Real-Ip")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return peer
+}
@@ -0,0 +1,76 @@
+package context
+
+import (
+	"net"
+	"testing"
+
+	"github.com/kataras/iris/core/netutil"
+)
+
+func testTrustedProxies(t *testing.T, proxies ...string) trustedProxies {
+	nets := make(trustedProxies, len(proxies))
+	for i, proxy := range proxies {
+		ipNet, err := netutil.ParseIPNet(proxy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nets[i] = ipNet
+	}
+
+	return nets
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := testTrustedProxies(t, "10.0.0.0/8", "127.0.0.1", "2001:db8::/32")
+
+	tests := []struct {
+		name    string
+		peer    string
+		headers map[string]string
+		ip      string
+	}{
+		{"untrusted peer", "203.0.113.7", map[string]string{"X-Forwarded-For": "1.1.1.1", "X-Real-Ip": "2.2.2.2"}, "203.0.113.7"},
+		{"no headers", "10.0.0.1", nil, "10.0.0.1"},
+		{"single hop", "10.0.0.1", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "198.51.100.1"},
+		// the client can prepend any address, the right-most untrusted one is the client.
+		{"spoofed hop", "10.0.0.1", map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.1, 10.0.0.2"}, "198.51.100.1"},
+		{"all trusted", "127.0.0.1", map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"port", "10.0.0.1", map[string]string{"X-Forwarded-For": "198.51.100.1:4711"}, "198.51.100.1"},
+		{"forwarded", "10.0.0.1", map[string]string{"Forwarded": `for=192.0.2.43, for="[2001:db8:cafe::17]:4711";proto=https`, "X-Forwarded-For": "1.1.1.1"}, "192.0.2.43"},
+		{"forwarded ipv6", "10.0.0.1", map[string]string{"Forwarded": `for="[2001:db9::1]:4711"`}, "2001:db9::1"},
+		{"ipv6 peer", "2001:db8::1", map[string]string{"X-Forwarded-For": "2001:db9::2"}, "2001:db9::2"},
+		{"untrusted ipv6 peer", "2001:db9::1", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "2001:db9::1"},
+		{"real ip", "10.0.0.1", map[string]string{"X-Real-Ip": "198.51.100.2"}, "198.51.100.2"},
+		{"malformed forwarded for", "10.0.0.1", map[string]string{"X-Forwarded-For": "unknown, not-an-ip", "X-Real-Ip": "198.51.100.2"}, "198.51.100.2"},
+		{"malformed", "10.0.0.1", map[string]string{"X-Forwarded-For": ",,", "X-Real-Ip": "<script>"}, "10.0.0.1"},
+		{"obfuscated forwarded", "10.0.0.1", map[string]string{"Forwarded": "for=_hidden;proto=http"}, "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		header := func(key string) string { return tt.headers[key] }
+		if got := clientIP(tt.peer, proxies, header); got != tt.ip {
+			t.Fatalf("[%s] expected the client's IP to be '%s' but got: '%s'", tt.name, tt.ip, got)
+		}
+	}
+}
+
+func TestTrustedProxiesContains(t *testing.T) {
+	proxies := testTrustedProxies(t, "192.168.1.0/24", "::1")
+
+	for addr, expected := range map[string]bool{
+		"192.168.1.10": true,
+		"192.168.2.10": false,
+		"::1":          true,
+		"::2":          false,
+		"":             false,
+		"localhost":    false,
+	} {
+		if got := proxies.contains(addr); got != expected {
+			t.Fatalf("expected '%s' to be trusted: %v but got: %v", addr, expected, got)
+		}
+	}
+
+	if (trustedProxies{&net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}}).contains("::1") {
+		t.Fatalf("expected the IPv6 address to not be contained by an IPv4 network")
+	}
+}
@@ -0,0 +1,152 @@
+package context
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+)
+
+const (
+	// DeflateHeaderValue is the header value of "deflate",
+	// its responses are in the zlib format, see RFC 7230 section 4.2.2.
+	DeflateHeaderValue = "deflate"
+	// BrotliHeaderValue is the header value of "br".
+	// Iris does not ship a brotli encoder, register one by the `RegisterCompressor`.
+	BrotliHeaderValue = "br"
+	// ZstdHeaderValue is the header value of "zstd".
+	// Iris does not ship a zstd encoder, register one by the `RegisterCompressor`.
+	ZstdHeaderValue = "zstd"
+)
+
+// CompressWriter is the writer of a response's "Content-Encoding",
+// the writers are pooled and reset for each response, see `RegisterCompressor`.
+type CompressWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+type compressor struct {
+	encoding  string
+	newWriter func(w io.Writer) (CompressWriter, error)
+	pool      sync.Pool
+}
+
+func (c *compressor) acquire(w io.Writer) (CompressWriter, error) {
+	if v := c.pool.Get(); v != nil {
+		cw := v.(CompressWriter)
+		cw.Reset(w)
+		return cw, nil
+	}
+
+	return c.newWriter(w)
+}
+
+func (c *compressor) release(cw CompressWriter) {
+	cw.Close()
+	c.pool.Put(cw)
+}
+
+// write writes a compressed form of "b" to "w".
+func (c *compressor) write(w io.Writer, b []byte) (int, error) {
+	cw, err := c.acquire(w)
+	if err != nil {
+		return -1, err
+	}
+
+	n, err := cw.Write(b)
+	if err != nil {
+		c.release(cw)
+		return -1, err
+	}
+
+	err = cw.Flush()
+	c.release(cw)
+	return n, err
+}
+
+var (
+	compressors = make(map[string]*compressor)
+	// the registered encodings by order of server's preference,
+	// it's used when the client accepts more than one of them with the same quality.
+	compressorsOrder []string
+)
+
+// RegisterCompressor registers a response compression "encoding", i.e "br" or "zstd",
+// it's negotiated by the "Accept-Encoding" request header, with its quality values,
+// by the `Context#Compress` and the `Compress` middleware.
+// The latest registered encodings are preferred when the client accepts them with the same quality,
+// "gzip" and "deflate" are registered by default, the "br" and the "zstd" are not,
+// their encoders are third-party packages, i.e "github.com/andybalholm/brotli"
+// and "github.com/klauspost/compress/zstd", so the application registers them:
+//
+// It should be called before the server's start, i.e on the main function:
+//
+// context.RegisterCompressor(context.BrotliHeaderValue, func(w io.Writer) (context.CompressWriter, error) {
+// 	return brotli.NewWriterLevel(w, brotli.DefaultCompression), nil
+// })
+//
+// context.RegisterCompressor(context.ZstdHeaderValue, func(w io.Writer) (context.CompressWriter, error) {
+// 	return zstd.NewWriter(w)
+// })
+func RegisterCompressor(encoding string, newWriter func(w io.Writer) (CompressWriter, error)) {
+	if _, exists := compressors[encoding]; exists {
+		for i, enc := range compressorsOrder {
+			if enc == encoding {
+				compressorsOrder = append(compressorsOrder[:i], compressorsOrder[i+1:]...)
+				break
+			}
+		}
+	}
+
+	compressors[encoding] = &compressor{encoding: encoding, newWriter: newWriter}
+	compressorsOrder = append([]string{encoding}, compressorsOrder...)
+}
+
+func init() {
+	RegisterCompressor(DeflateHeaderValue, func(w io.Writer) (CompressWriter, error) {
+		return zlib.NewWriterLevel(w, zlib.DefaultCompression)
+	})
+	RegisterCompressor(GzipHeaderValue, func(w io.Writer) (CompressWriter, error) {
+		return gzip.NewWriterLevel(w, gzip.DefaultCompression)
+	})
+}
+
+// negotiateEncoding returns the registered encoding with the highest quality
+// on the "accept" header value, "Accept-Encoding", or empty if none of them is acceptable.
+func negotiateEncoding(accept string) string {
+	if accept == "" {
+		return ""
+	}
+
+	var (
+		specs = parseAccept(accept)
+		best  string
+		bestQ float64
+	)
+
+	for _, enc := range compressorsOrder {
+		q, wildcardQ := -1.0, -1.0
+		for _, spec := range specs {
+			if spec.value == enc {
+				q = spec.q
+				break
+			}
+			if spec.value == "*" && wildcardQ == -1 {
+				wildcardQ = spec.q
+			}
+		}
+
+		if q == -1 {
+			q = wildcardQ
+		}
+
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+
+	return best
+}
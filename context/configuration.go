@@ -1,5 +1,7 @@
 package context
 
+import "net"
+
 // ConfigurationReadOnly can be implemented
 // by Configuration, it's being used inside the Context.
 // All methods that it contains should be "safe" to be called by the context
@@ -20,6 +22,10 @@ type ConfigurationReadOnly interface {
 	// (permant)redirects the client to the correct path /home.
 	GetDisablePathCorrection() bool
 
+	// GetPathNormalization returns the configuration.PathNormalization,
+	// the options of the request path's normalization before the route matching.
+	GetPathNormalization() PathNormalization
+
 	// GetEnablePathEscape is the configuration.EnablePathEscape,
 	// returns true when its escapes the path, the named parameters (if any).
 	GetEnablePathEscape() bool
@@ -30,6 +36,10 @@ type ConfigurationReadOnly interface {
 
 	// GetFireMethodNotAllowed returns the configuration.FireMethodNotAllowed.
 	GetFireMethodNotAllowed() bool
+	// GetAutoOptions returns the configuration.AutoOptions.
+	GetAutoOptions() bool
+	// GetAutoHead returns the configuration.AutoHead.
+	GetAutoHead() bool
 	// GetDisableBodyConsumptionOnUnmarshal returns the configuration.GetDisableBodyConsumptionOnUnmarshal,
 	// manages the reading behavior of the context's body readers/binders.
 	// If returns true then the body consumption by the `context.UnmarshalBody/ReadJSON/ReadXML`
@@ -53,6 +63,9 @@ type ConfigurationReadOnly interface {
 	// the character encoding for various rendering
 	// used for templates and the rest of the responses.
 	GetCharset() string
+	// GetContentTypeCharsets returns the configuration.ContentTypeCharsets,
+	// the response charset of each content type which differs from the `GetCharset`.
+	GetContentTypeCharsets() map[string]string
 
 	// GetPostMaxMemory returns the maximum configured post data size
 	// that a client can send to the server, this differs
@@ -62,6 +75,34 @@ type ConfigurationReadOnly interface {
 	// Defaults to 32MB or 32 << 20 if you prefer.
 	GetPostMaxMemory() int64
 
+	// GetUploadScanner returns the configuration.UploadScanner,
+	// the scanner of the uploaded files, if any.
+	GetUploadScanner() Scanner
+	// GetUploadContentTypeVerification returns the configuration.UploadContentTypeVerification,
+	// if true then the contents of the uploaded files are verified against their declared types.
+	GetUploadContentTypeVerification() bool
+	// GetValidator returns the configuration.Validator,
+	// the validator of the decoded request's input, if any.
+	GetValidator() Validator
+	// GetSecureCookie returns the configuration.SecureCookie,
+	// the keys of the signed and encrypted cookies, if any.
+	GetSecureCookie() *SecureCookie
+	// GetJSONCodec returns the configuration.JSONCodec,
+	// the custom JSON functions, if any.
+	GetJSONCodec() *JSONCodec
+	// GetPushViewAssets returns the configuration.PushViewAssets,
+	// if true then the `Context#View` pushes the stylesheets and the scripts of the views.
+	GetPushViewAssets() bool
+	// GetLocalizer returns the configuration.Localizer,
+	// the translations provider of the `Context#Tr`, if any.
+	GetLocalizer() Localizer
+	// GetUserAgentRules returns the configuration.UserAgentRules,
+	// the ruleset of the `Context#UserAgentInfo`, if any.
+	GetUserAgentRules() *UserAgentRules
+	// GetRequestLogger returns the configuration.RequestLogger,
+	// the factory of the `Context#Logger`, if any.
+	GetRequestLogger() func(ctx Context) Logger
+
 	// GetTranslateLanguageContextKey returns the configuration's TranslateFunctionContextKey value,
 	// used for i18n.
 	GetTranslateFunctionContextKey() string
@@ -90,6 +131,14 @@ type ConfigurationReadOnly interface {
 	//
 	// Look `context.RemoteAddr()` for more.
 	GetRemoteAddrHeaders() map[string]bool
+	// GetTrustedProxies returns the configuration.TrustedProxies,
+	// the CIDRs or IPs of the proxies whose forwarding headers are trusted.
+	//
+	// Look `context.ClientIP()` for more.
+	GetTrustedProxies() []string
+	// GetTrustedProxyNets returns the parsed configuration.TrustedProxies,
+	// they are parsed once, when they are configured, the invalid ones are ignored.
+	GetTrustedProxyNets() []*net.IPNet
 
 	// GetOther returns the configuration.Other map.
 	GetOther() map[string]interface{}
@@ -0,0 +1,77 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ContentDisposition returns the value of a "Content-Disposition" header for the "filename",
+// "inline" to be displayed by the browser or "attachment" to be downloaded.
+//
+// The non-ASCII filenames are encoded as UTF-8 per RFC 5987, with an ASCII fallback
+// for the older clients, i.e `attachment; filename="_.txt"; filename*=UTF-8''%CF%80.txt`.
+func ContentDisposition(filename string, inline bool) string {
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+
+	if filename == "" {
+		return disposition
+	}
+
+	var (
+		fallback bytes.Buffer
+		ascii    = true
+	)
+
+	for _, r := range filename {
+		switch {
+		case r >= utf8.RuneSelf || r < 32 || r == 127:
+			ascii = false
+			fallback.WriteByte('_')
+		case r == '"' || r == '\\':
+			fallback.WriteByte('\\')
+			fallback.WriteRune(r)
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+
+	value := disposition + `; filename="` + fallback.String() + `"`
+	if !ascii {
+		value += "; filename*=UTF-8''" + encodeRFC5987(filename)
+	}
+
+	return value
+}
+
+// encodeRFC5987 percent-encodes the "s" except its "attr-char"s.
+func encodeRFC5987(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isRFC5987AttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func isRFC5987AttrChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+
+	switch c {
+	case '!', '#', '$', '&', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package context_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		filename string
+		inline   bool
+		expected string
+	}{
+		{"", false, "attachment"},
+		{"", true, "inline"},
+		{"report.pdf", false, `attachment; filename="report.pdf"`},
+		{"report.pdf", true, `inline; filename="report.pdf"`},
+		{"my report.txt", false, `attachment; filename="my report.txt"`},
+		// the quotes and the backslashes are escaped.
+		{`a"b\c.txt`, false, `attachment; filename="a\"b\\c.txt"`},
+		// the non-ASCII and the control characters are replaced by the ASCII fallback.
+		{"π.txt", false, `attachment; filename="_.txt"; filename*=UTF-8''%CF%80.txt`},
+		{"résumé 1.pdf", true, `inline; filename="r_sum_ 1.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9%201.pdf`},
+		{"a\tb.txt", false, `attachment; filename="a_b.txt"; filename*=UTF-8''a%09b.txt`},
+	}
+
+	for i, tt := range tests {
+		if got := context.ContentDisposition(tt.filename, tt.inline); got != tt.expected {
+			t.Fatalf("[%d] expected the Content-Disposition of '%s' to be '%s' but got '%s'", i, tt.filename, tt.expected, got)
+		}
+	}
+}
+
+func TestSendReader(t *testing.T) {
+	content := "0123456789"
+
+	app := iris.New()
+	app.Get("/{name:path}", func(ctx context.Context) {
+		if cType := ctx.URLParam("type"); cType != "" {
+			ctx.ContentType(cType)
+		}
+
+		size := ctx.URLParamInt64Default("size", -1)
+		if err := ctx.SendReader(strings.NewReader(content), size, ctx.Params().Get("name"), ctx.URLParamExists("inline")); err != nil {
+			t.Error(err)
+		}
+	})
+
+	e := httptest.New(t, app)
+
+	resp := e.GET("/report.txt").Expect().Status(iris.StatusOK)
+	resp.Header(context.ContentDispositionHeaderKey).Equal(`attachment; filename="report.txt"`)
+	resp.ContentType("text/plain", "utf-8")
+	resp.Body().Equal(content)
+
+	// only the "size" bytes are sent.
+	resp = e.GET("/π.pdf").WithQuery("size", 4).WithQuery("inline", true).Expect().Status(iris.StatusOK)
+	resp.Header(context.ContentDispositionHeaderKey).Equal(`inline; filename="_.pdf"; filename*=UTF-8''%CF%80.pdf`)
+	resp.Header(context.ContentLengthHeaderKey).Equal("4")
+	resp.ContentType("application/pdf", "utf-8")
+	resp.Body().Equal("0123")
+
+	// the unknown extensions are sent as binary, the Content-Type which is already set is kept.
+	e.GET("/data.unknown").Expect().Status(iris.StatusOK).
+		ContentType(context.ContentBinaryHeaderValue, "").Body().Equal(content)
+	e.GET("/data.txt").WithQuery("type", "application/json").Expect().Status(iris.StatusOK).
+		ContentType("application/json", "utf-8").Body().Equal(content)
+}
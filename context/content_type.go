@@ -0,0 +1,106 @@
+package context
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// ErrContentTypeMismatch is returned by the `VerifyContentType` and the upload helpers
+// when the contents of an uploaded file do not match its declared or extension's type.
+var ErrContentTypeMismatch = errors.New("upload: file '%s' declared as '%s' but its contents are '%s'")
+
+// sniffedTypes are the content types that the `http.DetectContentType`
+// recognises by their signature (magic bytes), if a file is declared as one of these
+// then its contents should be detected as the same.
+var sniffedTypes = []string{
+	"image/", "video/", "audio/", "font/",
+	"application/pdf", "application/zip", "application/x-gzip",
+	"application/x-rar-compressed", "application/wasm", "application/ogg",
+	"text/html",
+}
+
+func isSniffedType(typ string) bool {
+	for _, t := range sniffedTypes {
+		if strings.HasPrefix(typ, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func baseContentType(typ string) string {
+	if mediaType, _, err := mime.ParseMediaType(typ); err == nil {
+		return mediaType
+	}
+	return typ
+}
+
+// contentTypeMatches reports whether the "detected" (by the magic bytes)
+// content type is compatible with the "declared" one.
+func contentTypeMatches(declared, detected string) bool {
+	if declared == "" || declared == detected || declared == "application/octet-stream" {
+		return true
+	}
+
+	switch detected {
+	case "text/html", "text/xml":
+		// the most dangerous ones, scripts can be executed by the browser,
+		// allow only when they are declared as (x)html or xml.
+		return strings.Contains(declared, "html") || strings.Contains(declared, "xml")
+	case "text/plain":
+		// any text-based type: json, csv, javascript, svg...
+		// but never a binary one that the sniffer should recognise.
+		return !isSniffedType(declared) || declared == "image/svg+xml"
+	case "application/octet-stream":
+		// unknown binary, reject only if the declared type has a known signature.
+		return !isSniffedType(declared)
+	case "application/zip":
+		// office documents, jars and epubs are zip archives.
+		return !isSniffedType(declared) || declared == "application/zip"
+	}
+
+	// same family, i.e image/jpeg and image/pjpeg.
+	if idx := strings.IndexByte(detected, '/'); idx > 0 && strings.HasPrefix(declared, detected[:idx+1]) {
+		return true
+	}
+
+	return false
+}
+
+// VerifyContentType checks if the contents (magic bytes) of the uploaded file "fh"
+// match its declared Content-Type and its filename extension's type.
+// It returns an `ErrContentTypeMismatch` error on mismatch.
+//
+// See `iris#WithUploadContentTypeVerification` too.
+func VerifyContentType(fh *multipart.FileHeader) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	var buf [512]byte
+	n, err := io.ReadFull(src, buf[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	detected := baseContentType(http.DetectContentType(buf[:n]))
+
+	for _, declared := range []string{
+		baseContentType(fh.Header.Get(ContentTypeHeaderKey)),
+		baseContentType(mime.TypeByExtension(filepath.Ext(fh.Filename))),
+	} {
+		if !contentTypeMatches(declared, detected) {
+			return ErrContentTypeMismatch.Format(fh.Filename, declared, detected)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package context_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+)
+
+var testPNG = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+// testFileHeader returns the parsed file header of a "filename" declared as "contentType".
+func testFileHeader(t *testing.T, filename, contentType string, contents []byte) *multipart.FileHeader {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	part, err := w.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(contents)
+	w.Close()
+
+	form, err := multipart.NewReader(&b, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return form.File["file"][0]
+}
+
+func TestVerifyContentType(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		contents    []byte
+		ok          bool
+	}{
+		{"a.png", "image/png", testPNG, true},
+		{"a.jpg", "image/jpeg", testPNG, true}, // same family.
+		{"a", "application/octet-stream", testPNG, true},
+		{"image", "", testPNG, true},
+		{"a.json", "application/json", []byte(`{"name":"iris"}`), true},
+		{"a.svg", "image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), true},
+		{"a.html", "text/html", []byte("<html></html>"), true},
+		{"a.png", "image/png", []byte("<html><script>alert(1)</script></html>"), false},
+		{"a.txt", "text/plain", []byte("<html><script>alert(1)</script></html>"), false},
+		{"a.pdf", "application/pdf", []byte("plain text"), false},
+		// declared as an image but its extension is a pdf.
+		{"a.pdf", "image/png", testPNG, false},
+	}
+
+	for _, tt := range tests {
+		err := context.VerifyContentType(testFileHeader(t, tt.filename, tt.contentType, tt.contents))
+		if tt.ok {
+			if err != nil {
+				t.Fatalf("[%s as %s] expected no error but got: %v", tt.filename, tt.contentType, err)
+			}
+			continue
+		}
+
+		if e, ok := err.(errors.Error); !ok || !e.Equal(context.ErrContentTypeMismatch) {
+			t.Fatalf("[%s as %s] expected a content type mismatch error but got: %v", tt.filename, tt.contentType, err)
+		}
+	}
+}
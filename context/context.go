@@ -1,12 +1,14 @@
 package context
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"mime"
 	"mime/multipart"
 	"net"
@@ -15,21 +17,24 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/fatih/structs"
-	formbinder "github.com/iris-contrib/formBinder"
 	"github.com/json-iterator/go"
 	"github.com/microcosm-cc/bluemonday"
+	"github.com/satori/go.uuid"
 	"gopkg.in/russross/blackfriday.v2"
 	"gopkg.in/yaml.v2"
 
 	"github.com/kataras/iris/core/errors"
 	"github.com/kataras/iris/core/memstore"
+	"github.com/kataras/iris/core/msgpack"
 )
 
 type (
@@ -80,6 +85,10 @@ func (u UnmarshalerFunc) Unmarshal(data []byte, v interface{}) error {
 // Empty if the route is static.
 type RequestParams struct {
 	store memstore.Store
+	// the typed values of the parameter types which convert them, see `GetValue`.
+	values memstore.Store
+	// the parameters' types of the matched route, read-only, see `GetType`.
+	types map[string]string
 }
 
 // Set adds a key-value pair to the path parameters values
@@ -88,6 +97,35 @@ func (r *RequestParams) Set(key, value string) {
 	r.store.Set(key, value)
 }
 
+// SetValue stores the typed value of a parameter,
+// it's being called internally by the router for the parameter types which convert their values,
+// i.e "uuid", "time", "decimal" and the custom ones, see `GetValue`.
+func (r *RequestParams) SetValue(key string, value interface{}) {
+	r.values.Set(key, value)
+}
+
+// SetTypes sets the parameters' types of the matched route, by their names,
+// it's being called internally by the router, the "types" should not be modified.
+func (r *RequestParams) SetTypes(types map[string]string) {
+	r.types = types
+}
+
+// GetType returns the macro type of a parameter, i.e "int" or "uuid",
+// as it's declared by the matched route, a parameter without a type is a "string".
+// Returns an empty string if the parameter does not exist.
+func (r RequestParams) GetType(key string) string {
+	return r.types[key]
+}
+
+// VisitTyped is like `Visit` but the visitor accepts the macro type of each parameter too,
+// so generic middleware, i.e audit logging or authorization, can read the resolved parameters
+// of any route without knowing it, the typed values can be read by the `GetValue`.
+func (r *RequestParams) VisitTyped(visitor func(key string, typ string, value string)) {
+	r.store.Visit(func(k string, v interface{}) {
+		visitor(k, r.types[k], v.(string))
+	})
+}
+
 // Visit accepts a visitor which will be filled
 // by the key-value params.
 func (r *RequestParams) Visit(visitor func(key string, value string)) {
@@ -150,6 +188,11 @@ func (r RequestParams) GetInt64(key string) (int64, error) {
 	return r.store.GetInt64(key)
 }
 
+// GetUint64 returns the path parameter's value as uint64, based on its key.
+func (r RequestParams) GetUint64(key string) (uint64, error) {
+	return r.store.GetUint64(key)
+}
+
 // GetFloat64 returns a path parameter's value based as float64 on its route's dynamic path key.
 func (r RequestParams) GetFloat64(key string) (float64, error) {
 	return r.store.GetFloat64(key)
@@ -182,6 +225,64 @@ func (r RequestParams) GetIntUnslashed(key string) (int, error) {
 	return -1, fmt.Errorf("unable to find int for '%s'", key)
 }
 
+// GetValue returns the typed value of a parameter whose type converts it,
+// i.e "uuid", "time", "decimal" or a custom one of the `macro#Map#Register`,
+// the router has already validated and converted it.
+// Returns false if the parameter has no typed value.
+func (r RequestParams) GetValue(key string) (interface{}, bool) {
+	v := r.values.Get(key)
+	return v, v != nil
+}
+
+// GetUUID returns the path parameter's value as uuid.UUID, based on its key,
+// it's already converted by the router if the parameter's type is "uuid", i.e {id:uuid}.
+func (r RequestParams) GetUUID(key string) (uuid.UUID, error) {
+	if v, ok := r.values.Get(key).(uuid.UUID); ok {
+		return v, nil
+	}
+
+	v := r.Get(key)
+	if v == "" {
+		return uuid.Nil, fmt.Errorf("unable to find uuid for '%s'", key)
+	}
+
+	return uuid.FromString(v)
+}
+
+// GetTime returns the path parameter's value as time.Time, based on its key,
+// it's already converted by the router if the parameter's type is "time", i.e {date:time}.
+// Otherwise the value is parsed as an RFC 3339 date-time or as a "2006-01-02" date.
+func (r RequestParams) GetTime(key string) (time.Time, error) {
+	if v, ok := r.values.Get(key).(time.Time); ok {
+		return v, nil
+	}
+
+	v := r.Get(key)
+	if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unable to find time for '%s'", key)
+}
+
+// GetDecimal returns the path parameter's value as an exact decimal number, based on its key,
+// it's already converted by the router if the parameter's type is "decimal", i.e {price:decimal}.
+func (r RequestParams) GetDecimal(key string) (*big.Rat, error) {
+	if v, ok := r.values.Get(key).(*big.Rat); ok {
+		return v, nil
+	}
+
+	if v, ok := new(big.Rat).SetString(r.Get(key)); ok {
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("unable to find decimal for '%s'", key)
+}
+
 // Len returns the full length of the parameters.
 func (r RequestParams) Len() int {
 	return r.store.Len()
@@ -222,9 +323,41 @@ type Context interface {
 	ResponseWriter() ResponseWriter
 	// ResetResponseWriter should change or upgrade the Context's ResponseWriter.
 	ResetResponseWriter(ResponseWriter)
+	// Hijack takes over the connection of the current request, i.e for custom protocols
+	// or a "CONNECT" tunnel, the returned buffered reader may hold request data which
+	// were already read from the connection.
+	// It returns the `ErrHijackNotSupported` if the underline response writer
+	// can not be hijacked, i.e on HTTP/2, and the `http.ErrHijacked` on a second call.
+	//
+	// After a successful call the handlers chain is stopped and the framework
+	// does not fire error codes or flush the response of this request,
+	// the caller is responsible to write the response and to close the connection.
+	Hijack() (net.Conn, *bufio.ReadWriter, error)
+	// IsHijacked reports whether the connection of the current request was taken over by the `Hijack`.
+	IsHijacked() bool
 
 	// Request returns the original *http.Request, as expected.
 	Request() *http.Request
+	// ResetRequest sets the Context's Request,
+	// i.e to replace its standard context by the `http.Request#WithContext`.
+	ResetRequest(r *http.Request)
+
+	// Deadline returns the time when the request's standard context will be canceled,
+	// if a timeout was set, i.e by the `Route#Timeout`.
+	//
+	// Deadline, Done, Err and Value make the Context a standard `context.Context`,
+	// so it can be passed to the functions which should stop
+	// when the client disconnects or the route's timeout fires.
+	Deadline() (deadline time.Time, ok bool)
+	// Done returns a channel which is closed when the client disconnects,
+	// the route's timeout fires or the request is served.
+	Done() <-chan struct{}
+	// Err returns the reason of the `Done`'s close, `context.Canceled`
+	// or `context.DeadlineExceeded`, or nil if it's not closed yet.
+	Err() error
+	// Value returns the value of the "key" of the request's values, if it's a string,
+	// otherwise the one of the request's standard context.
+	Value(key interface{}) interface{}
 
 	// SetCurrentRouteName sets the route's name internally,
 	// in order to be able to find the correct current "read-only" Route when
@@ -337,6 +470,20 @@ type Context interface {
 	// StopExecution if called then the following .Next calls are ignored,
 	// as a result the next handlers in the chain will not be fire.
 	StopExecution()
+	// StopWithStatus stops the handlers chain and sets the "statusCode",
+	// an error code fires its registered error handler, i.e the default status text,
+	// it replaces the `StatusCode` and `StopExecution` pair of calls.
+	//
+	// Example: if !authorized { ctx.StopWithStatus(iris.StatusUnauthorized); return }
+	StopWithStatus(statusCode int)
+	// StopWithJSON stops the handlers chain and writes the "jsonObject" with the "statusCode",
+	// see `StopWithStatus` and `JSON`.
+	StopWithJSON(statusCode int, jsonObject interface{}) error
+	// StopWithProblem stops the handlers chain and writes the "problem" with the "statusCode",
+	// see `StopWithStatus` and `Problem`.
+	//
+	// Example: ctx.StopWithProblem(iris.StatusBadRequest, iris.NewProblem(0, "the name is required"))
+	StopWithProblem(statusCode int, problem Problem) error
 	// IsStopped checks and returns true if the current position of the Context is 255,
 	// means that the StopExecution() was called.
 	IsStopped() bool
@@ -364,6 +511,21 @@ type Context interface {
 	//
 	// Example: https://github.com/kataras/iris/tree/master/_examples/miscellaneous/i18n
 	Translate(format string, args ...interface{}) string
+	// Tr returns the "key" message translated to the request's language
+	// by the `Configuration#Localizer`, the "args" select the plural form and format the message.
+	// If no localizer is configured then it calls the `Translate` of the i18n middleware.
+	// It returns an empty string if the message is missing.
+	//
+	// Example: ctx.Tr("cart.items", 3)
+	Tr(key string, args ...interface{}) string
+	// Message returns the "id" message translated by the `Translate`,
+	// if it's not translated then it returns the "fallback" formatted with the "args".
+	// The framework's client-facing messages are sent through this method,
+	// see the `MessageStatusText` too.
+	Message(id, fallback string, args ...interface{}) string
+	// StatusText returns the, maybe translated, text of the "statusCode",
+	// the message ID is the `MessageStatusText` and the fallback is the `http.StatusText`.
+	StatusText(statusCode int) string
 
 	//  +------------------------------------------------------------+
 	//  | Path, Host, Subdomain, IP, Headers etc...                  |
@@ -392,10 +554,39 @@ type Context interface {
 	// If parse based on these headers fail then it will return the Request's `RemoteAddr` field
 	// which is filled by the server before the HTTP handler.
 	//
+	// If the `Configuration.TrustedProxies` is not empty then it's the same as the `ClientIP`.
+	//
 	// Look `Configuration.RemoteAddrHeaders`,
 	//      `Configuration.WithRemoteAddrHeader(...)`,
 	//      `Configuration.WithoutRemoteAddrHeader(...)` for more.
 	RemoteAddr() string
+	// RequestID returns the ID of the current request, which is set
+	// by the `middleware/requestid` from the "X-Request-Id" request header or a generated one,
+	// or empty if that middleware is not registered.
+	//
+	// Look `middleware/requestid#New` for more.
+	RequestID() string
+	// Logger returns the logger of the current request, its messages are written
+	// by the application's logger with the "request_id", if any, "method", "path" and "route" fields,
+	// i.e: ctx.Logger().Infof("user %d logged in", id) prints
+	// "user 42 logged in request_id=... method=POST path=/login route=POST/login".
+	//
+	// The first call creates the logger by the `Configuration#RequestLogger`, if any,
+	// and the next calls of the same request return that logger.
+	// Use the `SetLogger` to add fields for the next handlers, i.e:
+	// ctx.SetLogger(ctx.Logger().With("user", username)).
+	Logger() Logger
+	// SetLogger replaces the logger of the current request, see `Logger`.
+	SetLogger(logger Logger)
+	// ClientIP returns the real client's request IP.
+	// The "Forwarded", "X-Forwarded-For" and "X-Real-Ip" headers are read
+	// only if the request's connection comes from one of the `Configuration.TrustedProxies`,
+	// so the clients can not spoof their IP, otherwise the connection's IP is returned.
+	// The forwarded addresses are read from right to left, the first one which is not
+	// a trusted proxy is the client.
+	//
+	// Look `iris#WithTrustedProxies` for more.
+	ClientIP() string
 	// GetHeader returns the request header's value based on its name.
 	GetHeader(name string) string
 	// IsAjax returns true if this request is an 'ajax request'( XMLHttpRequest)
@@ -417,8 +608,19 @@ type Context interface {
 	// If the return value is true that means that the http client using a mobile
 	// device to communicate with the server, otherwise false.
 	//
-	// Keep note that this checks the "User-Agent" request header.
+	// Keep note that this checks the "User-Agent" request header,
+	// it does not depend on the `Configuration#UserAgentRules`, see `UserAgentInfo` for the device classes.
 	IsMobile() bool
+	// IsBot reports whether the client is a crawler or a non-browser client,
+	// i.e to skip the analytics, see `UserAgentInfo` too.
+	//
+	// Keep note that this checks the "User-Agent" request header.
+	IsBot() bool
+	// UserAgentInfo returns the browser, the operating system and the device class of the client,
+	// parsed from the "User-Agent" request header by the `Configuration#UserAgentRules`,
+	// i.e to switch the templates per device class.
+	// It's parsed once per request.
+	UserAgentInfo() UserAgentInfo
 	//  +------------------------------------------------------------+
 	//  | Response Headers helpers                                   |
 	//  +------------------------------------------------------------+
@@ -453,6 +655,15 @@ type Context interface {
 	// or 303 (StatusSeeOther) if POST method,
 	// or StatusTemporaryRedirect(307) if that's nessecery.
 	Redirect(urlToRedirect string, statusHeader ...int)
+	// RedirectPreserveMethod sends a redirect response which makes the client
+	// repeat the request with the same method and body to the "urlToRedirect",
+	// with the 308 (StatusPermanentRedirect) status code if "permanent" is true,
+	// otherwise with the 307 (StatusTemporaryRedirect) one.
+	// Use it to redirect POST, PUT and DELETE requests, the 301 and 302 change them to GET on most clients.
+	//
+	// The relative urls are resolved against the current request's path, per RFC 3986,
+	// i.e "../users" on "/api/v1/accounts" redirects to "/api/users".
+	RedirectPreserveMethod(urlToRedirect string, permanent bool)
 
 	//  +------------------------------------------------------------+
 	//  | Various Request and Post Data                              |
@@ -493,6 +704,15 @@ type Context interface {
 	// URLParams returns a map of GET query parameters separated by comma if more than one
 	// it returns an empty map if nothing found.
 	URLParams() map[string]string
+	// URLWithParams returns the current request's path and url query with the "params" set,
+	// an empty value removes that parameter, i.e for the pagination links:
+	// ctx.URLWithParams(map[string]string{"page": "2"}) returns "/posts?page=2&sort=date" on "/posts?sort=date&page=1".
+	// The query parameters are sorted by their keys, see `CanonicalQuery`.
+	URLWithParams(params map[string]string) string
+	// CanonicalQuery returns the url query of the current request with its parameters sorted by their keys
+	// and escaped, the repeated parameters keep their order,
+	// i.e for the cache keys: "b=2&a=1" and "a=1&b=2" return "a=1&b=2". The empty parameters are removed.
+	CanonicalQuery() string
 
 	// FormValueDefault returns a single parsed form value by its "name",
 	// including both the URL field's query parameters and the POST or PUT form data.
@@ -565,6 +785,9 @@ type Context interface {
 	PostValues(name string) []string
 	// FormFile returns the first uploaded file that received from the client.
 	//
+	// If an upload scanner is configured, through `iris#WithUploadScanner`, the file is scanned
+	// before it's returned and if rejected an `ErrScanRejected` error is returned with a 422 status code.
+	//
 	// The default form's memory maximum size is 32MB, it can be changed by the
 	//  `iris#WithPostMaxMemory` configurator at main configuration passed on `app.Run`'s second argument.
 	//
@@ -584,7 +807,12 @@ type Context interface {
 	// Returns the copied length as int64 and
 	// a not nil error if at least one new file
 	// can't be created due to the operating system's permissions or
-	// http.ErrMissingFile if no file received.
+	// http.ErrMissingFile if the request is not a multipart form, a form without files uploads nothing.
+	//
+	// If an upload scanner is configured, through `iris#WithUploadScanner`, each file is scanned
+	// before it's saved and if rejected an `ErrScanRejected` error is returned with a 422 status code.
+	// If the `iris#WithUploadContentTypeVerification` is used then each file's contents are verified
+	// against its declared type and if mismatch an `ErrContentTypeMismatch` error is returned with a 415 status code.
 	//
 	// If you want to receive & accept files and manage them manually you can use the `context#FormFile`
 	// instead and create a copy function that suits your needs, the below is for generic usage.
@@ -597,6 +825,23 @@ type Context interface {
 	//
 	// Example: https://github.com/kataras/iris/tree/master/_examples/http_request/upload-files
 	UploadFormFiles(destDirectory string, before ...func(Context, *multipart.FileHeader)) (n int64, err error)
+	// UploadFormFilesWith same as `UploadFormFiles` but it accepts options
+	// which limit the size, the extensions and the content types of the files,
+	// sanitize or randomize their filenames and report their stored path.
+	//
+	// The files are checked before any of them is saved,
+	// the too large ones fail with a 413 status code and an `ErrUploadFileTooLarge` or `ErrUploadTotalTooLarge` error,
+	// the not allowed ones fail with a 415 status code and an `ErrUploadFileNotAllowed` error.
+	UploadFormFilesWith(destDirectory string, opts UploadOptions) (n int64, err error)
+	// BodyDigest returns the digest of the request body computed while a multipart form was parsed,
+	// in form of "ALGORITHM=base64-value", i.e "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=".
+	// It's filled only when the client sent a "Digest" or a "Content-MD5" header,
+	// useful to be stored as metadata of the uploaded files.
+	//
+	// When the received body does not match these headers the upload helpers
+	// (FormFile, UploadFormFiles, ReadMultipart and ReadForm) fail with a 400 Bad Request status code
+	// and an `ErrDigestMismatch` error and the form values are not available at all.
+	BodyDigest() string
 
 	//  +------------------------------------------------------------+
 	//  | Custom HTTP Errors                                         |
@@ -617,25 +862,148 @@ type Context interface {
 	// SetMaxRequestBodySize sets a limit to the request body size
 	// should be called before reading the request body from the client.
 	SetMaxRequestBodySize(limitOverBytes int64)
+	// RecordRequestBody reads the request body up to "limit" bytes and keeps it,
+	// so middleware, i.e audit logging or signature verification, can read the body
+	// and the next handlers can still read it through the `ReadJSON`, `UnmarshalBody` and the rest.
+	// The body is rewound on each call and after each `UnmarshalBody`, i.e `ReadJSON` and `ReadXML`.
+	// A zero "limit" means the `Configuration#PostMaxMemory`.
+	//
+	// It returns the `ErrRequestBodyTooLarge` error and sets the 413 Request Entity Too Large status code
+	// if the body is larger than the "limit", the body is not recorded but it can still be read once.
+	RecordRequestBody(limit int64) ([]byte, error)
+	// TrackUploadProgress reports the bytes of the request body which are read by the next handlers
+	// to the "onProgress", the total is the "Content-Length" or negative if it's unknown, i.e chunked.
+	// It should be called before the body is read, i.e by a middleware,
+	// see the `middleware/uploadprogress` for a progress endpoint keyed by an upload ID.
+	TrackUploadProgress(onProgress ProgressFunc)
 
 	// UnmarshalBody reads the request's body and binds it to a value or pointer of any type.
 	// Examples of usage: context.ReadJSON, context.ReadXML.
 	//
+	// If a `Validator` is configured, through `iris#WithValidator`, the decoded value is validated
+	// and a `ValidationErrors` error is returned with a 400 status code if it's invalid.
+	//
 	// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-custom-via-unmarshaler/main.go
 	UnmarshalBody(outPtr interface{}, unmarshaler Unmarshaler) error
 	// ReadJSON reads JSON from request's body and binds it to a pointer of a value of any json-valid type.
 	//
+	// The optional "opts" can reject the unknown fields, the too deep or the too large bodies,
+	// the failures are returned as `*JSONReadError` which describes the offending field.
+	//
 	// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-json/main.go
-	ReadJSON(jsonObjectPtr interface{}) error
+	ReadJSON(jsonObjectPtr interface{}, opts ...JSONReader) error
+	// ReadJSONStream decodes a JSON array from the request's body element by element,
+	// without buffering the whole payload into memory first.
+	// The "onItem" should be a function of form `func(item T) error`,
+	// a new T is decoded for each array element and passed to the function,
+	// any other "onItem", i.e nil, fails before the body is read.
+	// If "onItem" returns a non-nil error the decoding stops immediately
+	// and that error is returned back to the caller.
+	// If a `Validator` is configured, through `iris#WithValidator`, each item is validated before the "onItem"
+	// and the decoding stops with a `ValidationErrors` error and a 400 status code on the first invalid one.
+	//
+	// Useful for bulk import endpoints which accept large arrays.
+	ReadJSONStream(onItem interface{}) error
 	// ReadXML reads XML from request's body and binds it to a pointer of a value of any xml-valid type.
 	//
 	// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-xml/main.go
 	ReadXML(xmlObjectPtr interface{}) error
+	// ReadMsgPack reads MessagePack from request's body and binds it to a pointer of a value of any type.
+	ReadMsgPack(msgpackObjectPtr interface{}) error
+	// ReadYAML reads YAML from request's body and binds it to a pointer of a value of any yaml-valid type.
+	// The body size and the number of its aliases are limited by the `DefaultYAMLReaderOptions`,
+	// the optional "opts" can override them.
+	ReadYAML(yamlObjectPtr interface{}, opts ...YAMLReader) error
+	// ReadProtobuf reads a Protocol Buffers message from request's body and binds it to the "msg".
+	// If the request's content type is JSON then the body is decoded from its JSON transcoding.
+	//
+	// See `DefaultProtoCodec` too.
+	ReadProtobuf(msg ProtoMessage) error
 	// ReadForm binds the formObject  with the form data
 	// it supports any kind of struct.
 	//
+	// The keys are the field names or their "form" tags and they can describe nested values:
+	// "address.city" for structs, "items[0].name" for slices, "tags[]" to append to a slice
+	// and "meta[color]" for maps. The `encoding.TextUnmarshaler` types decode their own values.
+	// A field with a "form" tag matches its name too, a field with a "form:"-"" tag is skipped.
+	// The returned error is a `FormErrors` which reports each one of the failed fields,
+	// including the keys which do not match any field.
+	//
 	// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-form/main.go
 	ReadForm(formObjectPtr interface{}) error
+	// ReadMultipart binds a multipart/form-data request to the "outPtr" struct,
+	// each field is mapped to a part by its `part:"name"` tag.
+	// Fields of type *multipart.FileHeader or []*multipart.FileHeader receive the uploaded file(s),
+	// string fields receive the part's value, []byte fields its raw contents,
+	// any other field type is decoded from the part's contents as JSON,
+	// i.e: Meta CreateDoc `part:"meta"` and File *multipart.FileHeader `part:"file"`.
+	//
+	// Parts that are missing from the request are ignored.
+	//
+	// If an upload scanner is configured, through `iris#WithUploadScanner`, the files of the tagged parts
+	// are scanned before they're bound and if rejected an `ErrScanRejected` error is returned with a 422 status code.
+	//
+	// The default form's memory maximum size is 32MB, it can be changed by the
+	// `iris#WithPostMaxMemory` configurator at main configuration passed on `app.Run`'s second argument.
+	ReadMultipart(outPtr interface{}) error
+	// Bind fills the "ptr" struct from all the sources of the request.
+	// The body is decoded first, by its content type (JSON, XML, YAML, MessagePack or form),
+	// then the fields tagged with `param:"name"`, `query:"name"` and `header:"name"`
+	// are set from the path parameters, the url query and the headers, they override the body's values.
+	// The fields of the embedded structs are promoted.
+	//
+	// The value is validated by the configured `Validator`, if any, after all of its fields are bound.
+	// The body's errors are returned as they are, the tagged fields' ones as `BindErrors`,
+	// i.e: ID int64 `param:"id"`, Page int `query:"page"`, Token string `header:"X-Token"`
+	// and Name string `json:"name"`.
+	Bind(ptr interface{}) error
+	// ReadQuery binds the url query parameters to the "ptr" struct.
+	// The keys are the `url:"name"` tags of the fields or their names, `url:"-"` skips a field,
+	// the fields of the embedded structs are promoted.
+	// The `default:"value"` tag sets the value of a missing parameter,
+	// it's comma-separated for the slices, which are filled by the repeated parameters, i.e ?tag=a&tag=b.
+	//
+	// The value is validated by the configured `Validator`, if any.
+	// The decoding failures are returned as `BindErrors`, one for each failed field.
+	//
+	// Example: Page int `url:"page" default:"1"`, Tags []string `url:"tag"`.
+	ReadQuery(ptr interface{}) error
+	// MultipartStream processes the parts of a multipart/form-data request as they arrive,
+	// without writing them to temporary files or loading them into memory,
+	// useful for endpoints that accept multi-GB uploads.
+	// The "onPart" is called once per part, the part is closed after it returns
+	// and a non-nil error stops the processing and it's returned back to the caller.
+	//
+	// The optional "limits" reject the too large parts and the requests with too many parts,
+	// with the `ErrMultipartPartTooLarge` and `ErrMultipartTooManyParts` errors
+	// and a 413 Request Entity Too Large status code.
+	//
+	// The request body can be read once, it can't be combined with the `FormFile`, `FormValue` and the rest of the form helpers.
+	// The parts are not scanned by the `iris#WithUploadScanner`, they are never stored,
+	// the "onPart" should scan them itself, i.e through the `Configuration#GetUploadScanner`, or use the `FormFiles` instead.
+	MultipartStream(onPart func(part *multipart.Part) error, limits ...MultipartLimits) error
+	// FormFiles iterates over the uploaded files of a multipart/form-data request as they arrive,
+	// instead of parsing the whole form up front like the `FormFile` and the `UploadFormFiles` do.
+	// Each file is kept in memory up to the `Configuration#PostMaxMemory` bytes and the rest
+	// of it in a temporary file, which is removed after the "onFile" returns.
+	// A non-nil error of the "onFile" stops the iteration and it's returned back to the caller.
+	//
+	// The rest of the form's values are stored to the request's post form,
+	// so they are available through the `PostValue` and `FormValue` after the call.
+	//
+	// If an upload scanner is configured, through `iris#WithUploadScanner`, each file is scanned
+	// before the "onFile" and if rejected an `ErrScanRejected` error is returned with a 422 status code.
+	//
+	// Example:
+	// err := ctx.FormFiles(func(fh *multipart.FileHeader) error {
+	// 	f, err := fh.Open()
+	// 	if err != nil {
+	// 		return err
+	// 	}
+	// 	defer f.Close()
+	// 	return store(fh.Filename, f)
+	// })
+	FormFiles(onFile func(fh *multipart.FileHeader) error) error
 
 	//  +------------------------------------------------------------+
 	//  | Body (raw) Writers                                         |
@@ -705,6 +1073,12 @@ type Context interface {
 	// WriteWithExpiration like Write but it sends with an expiration datetime
 	// which is refreshed every package-level `StaticCacheDuration` field.
 	WriteWithExpiration(body []byte, modtime time.Time) (int, error)
+	// WriteWithEtag writes the "body" with its ETag, see `ComputeETag`,
+	// or it sends a 304 Not Modified, without the body, if the ETag matches
+	// the "If-None-Match" request header of a GET or HEAD request.
+	//
+	// Look the `ETag` middleware to compute the ETag of all the responses of a route.
+	WriteWithEtag(body []byte) (int, error)
 	// StreamWriter registers the given stream writer for populating
 	// response body.
 	//
@@ -720,12 +1094,37 @@ type Context interface {
 	// receives a function which receives the response writer
 	// and returns false when it should stop writing, otherwise true in order to continue
 	StreamWriter(writer func(w io.Writer) bool)
+	// Stream calls the "writer" function until it returns false or the client is gone,
+	// the data are written to a buffer which is flushed to the client
+	// after each call, or every `StreamOptions#FlushInterval` even while the "writer" waits, and on the `StreamWriter#Flush`.
+	// It's useful for long-polling and progressive rendering handlers,
+	// the "writer" should wait for new data, i.e on a channel, and select on the `StreamWriter#Done` too.
+	//
+	// It returns the request context's error if the client is gone or the first write error, if any.
+	Stream(writer func(w *StreamWriter) bool, opts ...StreamOptions) error
+	// Push initiates an HTTP/2 server push of the "target", a local path i.e "/public/app.css",
+	// the client receives it before it parses the response which references it.
+	// The optional "opts" can set the method and the headers of the pushed request.
+	//
+	// It does nothing and returns nil if the push is not supported, i.e on HTTP/1.x,
+	// so it can be called unconditionally. It should be called before writing the response's body.
+	//
+	// Look `iris#WithPushViewAssets` to push the assets of the views automatically.
+	Push(target string, opts ...*http.PushOptions) error
 
 	//  +------------------------------------------------------------+
 	//  | Body Writers with compression                              |
 	//  +------------------------------------------------------------+
 	// ClientSupportsGzip retruns true if the client supports gzip compression.
+	// It returns false if the compression was disabled by the `NoGzip` middleware.
 	ClientSupportsGzip() bool
+	// ClientSupportsCompression returns the best "Content-Encoding" of the registered ones
+	// that the client accepts, by the "Accept-Encoding" quality values,
+	// or empty if the client does not accept any of them.
+	// It returns empty if the compression was disabled by the `NoGzip` middleware.
+	//
+	// See `RegisterCompressor` too.
+	ClientSupportsCompression() string
 	// WriteGzip accepts bytes, which are compressed to gzip format and sent to the client.
 	// returns the number of bytes written and an error ( if the client doesn' supports gzip compression)
 	// You may re-use this function in the same handler
@@ -743,6 +1142,12 @@ type Context interface {
 	// supports gzip compression, so the following response data will
 	// be sent as compressed gzip data to the client.
 	Gzip(enable bool)
+	// Compress enables or disables (if enabled before) the compression of the response,
+	// the encoding, i.e "br", "zstd", "gzip" or "deflate", is negotiated by the client's "Accept-Encoding" header,
+	// so the following response data will be sent compressed to the client.
+	//
+	// See `RegisterCompressor` and `ClientSupportsCompression` too.
+	Compress(enable bool)
 
 	//  +------------------------------------------------------------+
 	//  | Rich Body Content Writers/Renderers                        |
@@ -773,12 +1178,29 @@ type Context interface {
 	// to clear the view data, developers can call:
 	// ctx.Set(ctx.Application().ConfigurationReadOnly().GetViewDataContextKey(), nil)
 	//
+	// The view data, the layout and the functions of the `ViewFunc` are stored to the request's values,
+	// so they live as long as the request does, they are never shared between requests
+	// and middleware can set them without modifying the registered view engine.
+	//
 	// If 'key' is empty then the value is added as it's (struct or map) and developer is unable to add other value.
 	//
 	// Look .ViewLayout and .View too.
 	//
 	// Example: https://github.com/kataras/iris/tree/master/_examples/view/context-view-data/
 	ViewData(key string, value interface{})
+	// ViewFunc registers a template function for the next .View calls of this request only,
+	// the registered view engine's functions are not modified, so middleware can
+	// pass request-specific helpers, i.e the current user or a CSRF token func:
+	//
+	// ctx.ViewFunc("currentUser", func() *User { return user })
+	//
+	// The templates are parsed once, so the view engine should know the function's name
+	// before the first request, i.e by a placeholder function of the engine's `AddFunc`.
+	// A view engine which does not support the per-request functions ignores them,
+	// the html and django ones support them.
+	//
+	// Look .ViewData and .View too.
+	ViewFunc(name string, fn interface{})
 	// GetViewData returns the values registered by `context#ViewData`.
 	// The return value is `map[string]interface{}`, this means that
 	// if a custom struct registered to ViewData then this function
@@ -814,12 +1236,44 @@ type Context interface {
 	JSON(v interface{}, options ...JSON) (int, error)
 	// JSONP marshals the given interface object and writes the JSON response.
 	JSONP(v interface{}, options ...JSONP) (int, error)
+	// Problem writes the "p" as an RFC 7807 Problem Details JSON response,
+	// "application/problem+json", with its status code.
+	// The missing type, title and status are filled by the response's status code,
+	// the `ProblemHandler` renders the error codes as problems.
+	//
+	// Example: ctx.Problem(iris.NewProblem(iris.StatusBadRequest, "the name is required").Key("field", "name"))
+	Problem(p Problem, options ...JSON) (int, error)
+	// JSONStream renders the "items" as a JSON array, each item is encoded
+	// and flushed to the client as soon as it's received,
+	// so a large result does not have to be buffered in memory.
+	// It returns when the "items" channel is closed or the client is gone.
+	JSONStream(items <-chan interface{}) (int, error)
+	// NDJSON is like the `JSONStream` but it renders the "items"
+	// as newline delimited JSON values, the "application/x-ndjson" content type.
+	NDJSON(items <-chan interface{}) (int, error)
 	// XML marshals the given interface object and writes the XML response.
 	XML(v interface{}, options ...XML) (int, error)
 	// Markdown parses the markdown to html and renders its result to the client.
 	Markdown(markdownB []byte, options ...Markdown) (int, error)
 	// YAML parses the "v" using the yaml parser and renders its result to the client.
 	YAML(v interface{}) (int, error)
+	// MsgPack marshals the "v" to MessagePack and renders its result to the client.
+	MsgPack(v interface{}) (int, error)
+	// Protobuf marshals the Protocol Buffers "msg" and renders its result to the client,
+	// the optional `Protobuf#JSON` option renders its JSON transcoding instead.
+	//
+	// See `DefaultProtoCodec` too.
+	Protobuf(msg ProtoMessage, options ...Protobuf) (int, error)
+	// Negotiate renders the "v" to the content type that the client prefers,
+	// based on its "Accept" header, JSON, XML, YAML, MessagePack, HTML via the `N#Template` view
+	// or a custom `N#Marshalers` one, i.e "application/cbor".
+	//
+	// The "Accept-Charset" is checked against the `Configuration#Charset` too.
+	// If the client does not accept any of the offers then it responds with 406 Not Acceptable.
+	// The "Vary" header is always set, so caches can keep the different representations.
+	//
+	// Example: ctx.Negotiate(user, context.N{Template: "user.html"})
+	Negotiate(v interface{}, opts ...N) (int, error)
 	//  +------------------------------------------------------------+
 	//  | Serve files                                                |
 	//  +------------------------------------------------------------+
@@ -849,23 +1303,58 @@ type Context interface {
 	//
 	// Use this instead of ServeFile to 'force-download' bigger files to the client.
 	SendFile(filename string, destinationName string) error
+	// SendReader sends the contents of the "r" for force-download, if "inline" is false,
+	// or to be displayed by the browser, for the files which are generated on the fly
+	// or stored in an object storage.
+	// The "size" is sent as the Content-Length, if it's not negative, and only that many bytes are copied.
+	// The "destinationName" is the filename of the client, its extension sets the Content-Type if not already set.
+	//
+	// See `ContentDisposition` too.
+	SendReader(r io.Reader, size int64, destinationName string, inline bool) error
+	// ServeContentReader serves the contents of the "r", i.e a proxied download or an object of a storage,
+	// with the headers of a file: the Content-Type by the extension of the "name",
+	// the Content-Length if the "size" is not negative and the Last-Modified if the "modtime" is not zero,
+	// a 304 Not Modified is sent when the client's copy is up to date.
+	// If the "r" is an `io.ReadSeeker` then the range requests are served too, see `http.ServeContent`.
+	//
+	// The optional "onProgress" is called with the bytes sent so far after each write to the client.
+	ServeContentReader(r io.Reader, name string, size int64, modtime time.Time, onProgress ...ProgressFunc) error
 
 	//  +------------------------------------------------------------+
 	//  | Cookies                                                    |
 	//  +------------------------------------------------------------+
 
-	// SetCookie adds a cookie
-	SetCookie(cookie *http.Cookie)
+	// SetCookie adds a cookie.
+	// The optional "options" set the rest of its attributes, i.e
+	// `CookieSameSite(SameSiteLax)`, `CookiePartitioned` and `CookieHost`, see `CookieOption`.
+	SetCookie(cookie *http.Cookie, options ...CookieOption)
 	// SetCookieKV adds a cookie, receives just a name(string) and a value(string)
 	//
 	// If you use this method, it expires at 2 hours
-	// use ctx.SetCookie or http.SetCookie if you want to change more fields.
-	SetCookieKV(name, value string)
+	// use ctx.SetCookie or http.SetCookie if you want to change more fields,
+	// or pass the "options", see `CookieOption`.
+	SetCookieKV(name, value string, options ...CookieOption)
 	// GetCookie returns cookie's value by it's name
 	// returns empty string if nothing was found.
-	GetCookie(name string) string
+	// The optional "options" should be the same as the `SetCookie`'s ones
+	// when they change the cookie's name, i.e the `CookieHost`.
+	GetCookie(name string, options ...CookieOption) string
 	// RemoveCookie deletes a cookie by it's name.
-	RemoveCookie(name string)
+	// The optional "options" should be the same as the `SetCookie`'s ones,
+	// the browsers delete a cookie only if its name, path and domain match.
+	RemoveCookie(name string, options ...CookieOption)
+	// SetSecureCookie adds a cookie whose value is signed, and encrypted if a block key is set,
+	// by the configured `SecureCookie`, see `iris#WithSecureCookie`.
+	// The cookie is "HttpOnly", its path is "/" and it expires after the `SecureCookie#MaxAge`, if any,
+	// the optional "options" can change them, see `CookieOption`.
+	SetSecureCookie(name, value string, options ...CookieOption) error
+	// GetSecureCookie returns the verified, and decrypted, value of a cookie
+	// which was sent by the `SetSecureCookie`.
+	// It returns an `ErrSecureCookieInvalid` error if the value is tampered
+	// and an `ErrSecureCookieExpired` error if it's older than the `SecureCookie#MaxAge`.
+	// The optional "options" should be the same as the `SetSecureCookie`'s ones
+	// when they change the cookie's name, i.e the `CookieHost`.
+	GetSecureCookie(name string, options ...CookieOption) (string, error)
 	// VisitAllCookies takes a visitor which loops
 	// on each (request's) cookies' name and value.
 	VisitAllCookies(visitor func(name string, value string))
@@ -890,6 +1379,15 @@ type Context interface {
 	// when the response writer is recording the status code, body, headers and so on,
 	// else returns nil and false.
 	IsRecording() (*ResponseRecorder, bool)
+	// BufferResponse records the whole response of the next handlers, up to "maxSize" bytes, zero means no limit,
+	// so a late middleware can still change the status code and the headers after the body was written,
+	// i.e to replace the response on a late error.
+	// The body is kept in memory and it's sent at the end of the request, so the memory usage
+	// and the time to the first byte increase, prefer it for the small responses.
+	// A response which exceeds the "maxSize" is sent directly from that point, see `ResponseRecorder#SetBufferLimit`.
+	//
+	// It has no effect if the response is compressed by the `Gzip`, see `Route#BufferResponse` too.
+	BufferResponse(maxSize int64)
 
 	// BeginTransaction starts a scoped transaction.
 	//
@@ -946,6 +1444,11 @@ type Context interface {
 	// and methods are not available here for the developer's safety.
 	Application() Application
 
+	// Supports reports whether this version of the framework supports the "capability",
+	// so middleware can adapt at runtime to different versions of it,
+	// see the `Capability...` constants, i.e `CapabilityReadJSONStream`.
+	Supports(capability string) bool
+
 	// String returns the string representation of this request.
 	// Each context has a unique string representation.
 	// It can be used for simple debugging scenarios, i.e print context as string.
@@ -987,6 +1490,24 @@ var Gzip = func(ctx Context) {
 	ctx.Next()
 }
 
+// Compress is a middleware which enables the compression of the response
+// by the best encoding that the client accepts, see `RegisterCompressor`.
+var Compress = func(ctx Context) {
+	ctx.Compress(true)
+	ctx.Next()
+}
+
+const noGzipContextKey = "@no_gzip"
+
+// NoGzip is a middleware which disables the gzip compression
+// for the rest of the request, even if a next handler calls the `Gzip(true)`.
+// Useful for already-compressed binaries and server-sent events.
+var NoGzip = func(ctx Context) {
+	ctx.Gzip(false)
+	ctx.Values().Set(noGzipContextKey, true)
+	ctx.Next()
+}
+
 // Map is just a shortcut of the map[string]interface{}.
 type Map map[string]interface{}
 
@@ -1016,6 +1537,8 @@ type context struct {
 	handlers Handlers
 	// the current position of the handler's chain
 	currentHandlerIndex int
+	// true if the connection was taken over by the `Hijack`.
+	hijacked bool
 }
 
 // NewContext returns the default, internal, context implementation.
@@ -1041,8 +1564,11 @@ func (ctx *context) BeginRequest(w http.ResponseWriter, r *http.Request) {
 	ctx.handlers = nil           // will be filled by router.Serve/HTTP
 	ctx.values = ctx.values[0:0] // >>      >>     by context.Values().Set
 	ctx.params.store = ctx.params.store[0:0]
+	ctx.params.values = ctx.params.values[0:0]
+	ctx.params.types = nil
 	ctx.request = r
 	ctx.currentHandlerIndex = 0
+	ctx.hijacked = false
 	ctx.writer = AcquireResponseWriter()
 	ctx.writer.BeginResponse(w)
 }
@@ -1069,6 +1595,12 @@ var StatusCodeNotSuccessful = func(statusCode int) bool {
 // 2. release the response writer
 // and any other optional steps, depends on dev's application type.
 func (ctx *context) EndRequest() {
+	if ctx.hijacked {
+		// the connection is not ours anymore.
+		ctx.writer.EndResponse()
+		return
+	}
+
 	if StatusCodeNotSuccessful(ctx.GetStatusCode()) &&
 		!ctx.Application().ConfigurationReadOnly().GetDisableAutoFireStatusCode() {
 		// author's note:
@@ -1108,11 +1640,84 @@ func (ctx *context) ResetResponseWriter(newResponseWriter ResponseWriter) {
 	ctx.writer = newResponseWriter
 }
 
+// Hijack takes over the connection of the current request, i.e for custom protocols
+// or a "CONNECT" tunnel, the returned buffered reader may hold request data which
+// were already read from the connection.
+// It returns the `ErrHijackNotSupported` if the underline response writer
+// can not be hijacked, i.e on HTTP/2, and the `http.ErrHijacked` on a second call.
+//
+// After a successful call the handlers chain is stopped and the framework
+// does not fire error codes or flush the response of this request,
+// the caller is responsible to write the response and to close the connection.
+func (ctx *context) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if ctx.hijacked {
+		return nil, nil, http.ErrHijacked
+	}
+
+	if _, ok := ctx.writer.Naive().(http.Hijacker); !ok {
+		return nil, nil, ErrHijackNotSupported
+	}
+
+	conn, buf, err := ctx.writer.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx.hijacked = true
+	ctx.StopExecution()
+	return conn, buf, nil
+}
+
+// IsHijacked reports whether the connection of the current request was taken over by the `Hijack`.
+func (ctx *context) IsHijacked() bool {
+	return ctx.hijacked
+}
+
 // Request returns the original *http.Request, as expected.
 func (ctx *context) Request() *http.Request {
 	return ctx.request
 }
 
+// ResetRequest sets the Context's Request,
+// i.e to replace its standard context by the `http.Request#WithContext`.
+func (ctx *context) ResetRequest(r *http.Request) {
+	ctx.request = r
+}
+
+// Deadline returns the time when the request's standard context will be canceled,
+// if a timeout was set, i.e by the `Route#Timeout`.
+//
+// Deadline, Done, Err and Value make the Context a standard `context.Context`,
+// so it can be passed to the functions which should stop
+// when the client disconnects or the route's timeout fires.
+func (ctx *context) Deadline() (deadline time.Time, ok bool) {
+	return ctx.request.Context().Deadline()
+}
+
+// Done returns a channel which is closed when the client disconnects,
+// the route's timeout fires or the request is served.
+func (ctx *context) Done() <-chan struct{} {
+	return ctx.request.Context().Done()
+}
+
+// Err returns the reason of the `Done`'s close, `context.Canceled`
+// or `context.DeadlineExceeded`, or nil if it's not closed yet.
+func (ctx *context) Err() error {
+	return ctx.request.Context().Err()
+}
+
+// Value returns the value of the "key" of the request's values, if it's a string,
+// otherwise the one of the request's standard context.
+func (ctx *context) Value(key interface{}) interface{} {
+	if k, ok := key.(string); ok {
+		if v := ctx.values.Get(k); v != nil {
+			return v
+		}
+	}
+
+	return ctx.request.Context().Value(key)
+}
+
 // SetCurrentRouteName sets the route's name internally,
 // in order to be able to find the correct current "read-only" Route when
 // end-developer calls the `GetCurrentRoute()` function.
@@ -1326,6 +1931,35 @@ func (ctx *context) StopExecution() {
 	ctx.currentHandlerIndex = stopExecutionIndex
 }
 
+// StopWithStatus stops the handlers chain and sets the "statusCode",
+// an error code fires its registered error handler, i.e the default status text,
+// it replaces the `StatusCode` and `StopExecution` pair of calls.
+//
+// Example: if !authorized { ctx.StopWithStatus(iris.StatusUnauthorized); return }
+func (ctx *context) StopWithStatus(statusCode int) {
+	ctx.StopExecution()
+	ctx.StatusCode(statusCode)
+}
+
+// StopWithJSON stops the handlers chain and writes the "jsonObject" with the "statusCode",
+// see `StopWithStatus` and `JSON`.
+func (ctx *context) StopWithJSON(statusCode int, jsonObject interface{}) error {
+	ctx.StopWithStatus(statusCode)
+	_, err := ctx.JSON(jsonObject)
+	return err
+}
+
+// StopWithProblem stops the handlers chain and writes the "problem" with the "statusCode",
+// see `StopWithStatus` and `Problem`.
+//
+// Example: ctx.StopWithProblem(iris.StatusBadRequest, iris.NewProblem(0, "the name is required"))
+func (ctx *context) StopWithProblem(statusCode int, problem Problem) error {
+	ctx.StopWithStatus(statusCode)
+	problem.Status = statusCode
+	_, err := ctx.Problem(problem)
+	return err
+}
+
 // IsStopped checks and returns true if the current position of the context is -1,
 // means that the StopExecution() was called.
 func (ctx *context) IsStopped() bool {
@@ -1364,9 +1998,76 @@ func (ctx *context) Translate(format string, args ...interface{}) string {
 		return cb(format, args...)
 	}
 
+	if l := ctx.Application().ConfigurationReadOnly().GetLocalizer(); l != nil {
+		return l.Tr(l.GetLanguage(ctx), format, args...)
+	}
+
 	return ""
 }
 
+// Tr returns the "key" message translated to the request's language
+// by the `Configuration#Localizer`, the "args" select the plural form and format the message.
+// If no localizer is configured then it calls the `Translate` of the i18n middleware.
+// It returns an empty string if the message is missing.
+//
+// Example: ctx.Tr("cart.items", 3)
+func (ctx *context) Tr(key string, args ...interface{}) string {
+	if l := ctx.Application().ConfigurationReadOnly().GetLocalizer(); l != nil {
+		return l.Tr(l.GetLanguage(ctx), key, args...)
+	}
+
+	return ctx.Translate(key, args...)
+}
+
+// Message returns the "id" message translated by the `Translate`,
+// if it's not translated then it returns the "fallback" formatted with the "args".
+// The framework's client-facing messages are sent through this method,
+// see the `MessageStatusText` too.
+func (ctx *context) Message(id, fallback string, args ...interface{}) string {
+	if msg, ok := ctx.translateMessage(id, args...); ok {
+		return msg
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(fallback, args...)
+	}
+
+	return fallback
+}
+
+// translateMessage returns the translation of the "id" message and true,
+// or false if the "id" is not translated.
+func (ctx *context) translateMessage(id string, args ...interface{}) (string, bool) {
+	if l := ctx.Application().ConfigurationReadOnly().GetLocalizer(); l != nil {
+		// the localizer returns an empty string when it's missing.
+		msg := l.Tr(l.GetLanguage(ctx), id, args...)
+		return msg, msg != ""
+	}
+
+	// the i18n middleware's translate function returns the "id" itself when it's missing,
+	// so it's called without the "args" to compare the result with the key.
+	msg := ctx.Translate(id)
+	if msg == "" || msg == id {
+		return "", false
+	}
+
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+
+	return msg, true
+}
+
+// StatusText returns the, maybe translated, text of the "statusCode",
+// the message ID is the `MessageStatusText` and the fallback is the `http.StatusText`.
+func (ctx *context) StatusText(statusCode int) string {
+	if msg, ok := ctx.translateMessage(fmt.Sprintf(MessageStatusText, statusCode)); ok {
+		return msg
+	}
+
+	return http.StatusText(statusCode)
+}
+
 //  +------------------------------------------------------------+
 //  | Path, Host, Subdomain, IP, Headers etc...                  |
 //  +------------------------------------------------------------+
@@ -1489,10 +2190,16 @@ const xForwardedForHeaderKey = "X-Forwarded-For"
 // If parse based on these headers fail then it will return the Request's `RemoteAddr` field
 // which is filled by the server before the HTTP handler.
 //
+// If the `Configuration.TrustedProxies` is not empty then it's the same as the `ClientIP`.
+//
 // Look `Configuration.RemoteAddrHeaders`,
 //      `Configuration.WithRemoteAddrHeader(...)`,
 //      `Configuration.WithoutRemoteAddrHeader(...)` for more.
 func (ctx *context) RemoteAddr() string {
+	if len(ctx.Application().ConfigurationReadOnly().GetTrustedProxies()) > 0 {
+		return ctx.ClientIP()
+	}
+
 	remoteHeaders := ctx.Application().ConfigurationReadOnly().GetRemoteAddrHeaders()
 
 	for headerName, enabled := range remoteHeaders {
@@ -1524,6 +2231,66 @@ func (ctx *context) RemoteAddr() string {
 	return addr
 }
 
+// RequestID returns the ID of the current request, which is set
+// by the `middleware/requestid` from the "X-Request-Id" request header or a generated one,
+// or empty if that middleware is not registered.
+//
+// Look `middleware/requestid#New` for more.
+func (ctx *context) RequestID() string {
+	id, _ := RequestIDKey.Get(ctx)
+	return id
+}
+
+// Logger returns the logger of the current request, its messages are written
+// by the application's logger with the "request_id", if any, "method", "path" and "route" fields,
+// i.e: ctx.Logger().Infof("user %d logged in", id) prints
+// "user 42 logged in request_id=... method=POST path=/login route=POST/login".
+//
+// The first call creates the logger by the `Configuration#RequestLogger`, if any,
+// and the next calls of the same request return that logger.
+// Use the `SetLogger` to add fields for the next handlers, i.e:
+// ctx.SetLogger(ctx.Logger().With("user", username)).
+func (ctx *context) Logger() Logger {
+	if l, ok := ctx.values.Get(loggerContextKey).(Logger); ok {
+		return l
+	}
+
+	var l Logger
+	if factory := ctx.Application().ConfigurationReadOnly().GetRequestLogger(); factory != nil {
+		l = factory(ctx)
+	}
+
+	if l == nil {
+		l = newRequestLogger(ctx)
+	}
+
+	ctx.values.Set(loggerContextKey, l)
+	return l
+}
+
+// SetLogger replaces the logger of the current request, see `Logger`.
+func (ctx *context) SetLogger(logger Logger) {
+	ctx.values.Set(loggerContextKey, logger)
+}
+
+// ClientIP returns the real client's request IP.
+// The "Forwarded", "X-Forwarded-For" and "X-Real-Ip" headers are read
+// only if the request's connection comes from one of the `Configuration.TrustedProxies`,
+// so the clients can not spoof their IP, otherwise the connection's IP is returned.
+// The forwarded addresses are read from right to left, the first one which is not
+// a trusted proxy is the client.
+//
+// Look `iris#WithTrustedProxies` for more.
+func (ctx *context) ClientIP() string {
+	peer := stripAddrPort(ctx.request.RemoteAddr)
+	proxies := ctx.Application().ConfigurationReadOnly().GetTrustedProxyNets()
+	if len(proxies) == 0 {
+		return peer
+	}
+
+	return clientIP(peer, proxies, ctx.GetHeader)
+}
+
 // GetHeader returns the request header's value based on its name.
 func (ctx *context) GetHeader(name string) string {
 	return ctx.request.Header.Get(name)
@@ -1553,12 +2320,40 @@ var isMobileRegex = regexp.MustCompile(`(?i)(android|avantgo|blackberry|bolt|boo
 // If the return value is true that means that the http client using a mobile
 // device to communicate with the server, otherwise false.
 //
-// Keep note that this checks the "User-Agent" request header.
+// Keep note that this checks the "User-Agent" request header,
+// it does not depend on the `Configuration#UserAgentRules`, see `UserAgentInfo` for the device classes.
 func (ctx *context) IsMobile() bool {
 	s := ctx.GetHeader("User-Agent")
 	return isMobileRegex.MatchString(s)
 }
 
+// IsBot reports whether the client is a crawler or a non-browser client,
+// i.e to skip the analytics, see `UserAgentInfo` too.
+//
+// Keep note that this checks the "User-Agent" request header.
+func (ctx *context) IsBot() bool {
+	return ctx.UserAgentInfo().Device == DeviceBot
+}
+
+// UserAgentInfo returns the browser, the operating system and the device class of the client,
+// parsed from the "User-Agent" request header by the `Configuration#UserAgentRules`,
+// i.e to switch the templates per device class.
+// It's parsed once per request.
+func (ctx *context) UserAgentInfo() UserAgentInfo {
+	if info, ok := ctx.values.Get(userAgentInfoContextKey).(UserAgentInfo); ok {
+		return info
+	}
+
+	rules := ctx.Application().ConfigurationReadOnly().GetUserAgentRules()
+	if rules == nil {
+		rules = DefaultUserAgentRules
+	}
+
+	info := rules.Parse(ctx.GetHeader("User-Agent"))
+	ctx.values.Set(userAgentInfoContextKey, info)
+	return info
+}
+
 //  +------------------------------------------------------------+
 //  | Response Headers helpers                                   |
 //  +------------------------------------------------------------+
@@ -1588,7 +2383,12 @@ func (ctx *context) ContentType(cType string) {
 	// if doesn't contain a charset already then append it
 	if !strings.Contains(cType, "charset") {
 		if cType != ContentBinaryHeaderValue {
-			cType += "; charset=" + ctx.Application().ConfigurationReadOnly().GetCharset()
+			c := ctx.Application().ConfigurationReadOnly()
+			charset, ok := c.GetContentTypeCharsets()[cType]
+			if !ok {
+				charset = c.GetCharset()
+			}
+			cType += "; charset=" + charset
 		}
 	}
 
@@ -1771,6 +2571,57 @@ func (ctx *context) URLParams() map[string]string {
 	return values
 }
 
+// URLWithParams returns the current request's path and url query with the "params" set,
+// an empty value removes that parameter, i.e for the pagination links:
+// ctx.URLWithParams(map[string]string{"page": "2"}) returns "/posts?page=2&sort=date" on "/posts?sort=date&page=1".
+// The query parameters are sorted by their keys, see `CanonicalQuery`.
+func (ctx *context) URLWithParams(params map[string]string) string {
+	q := ctx.request.URL.Query()
+	for k, v := range params {
+		if v == "" {
+			q.Del(k)
+			continue
+		}
+		q.Set(k, v)
+	}
+
+	path := ctx.request.URL.EscapedPath()
+	if query := canonicalQuery(q); query != "" {
+		return path + "?" + query
+	}
+
+	return path
+}
+
+// CanonicalQuery returns the url query of the current request with its parameters sorted by their keys
+// and escaped, the repeated parameters keep their order,
+// i.e for the cache keys: "b=2&a=1" and "a=1&b=2" return "a=1&b=2". The empty parameters are removed.
+func (ctx *context) CanonicalQuery() string {
+	return canonicalQuery(ctx.request.URL.Query())
+}
+
+// canonicalQuery encodes the non-empty parameters of the "q" sorted by their keys.
+func canonicalQuery(q url.Values) string {
+	for k, values := range q {
+		n := 0
+		for _, v := range values {
+			if v != "" {
+				values[n] = v
+				n++
+			}
+		}
+
+		if n == 0 {
+			delete(q, k)
+			continue
+		}
+		q[k] = values[:n]
+	}
+
+	// the url.Values#Encode sorts them by key.
+	return q.Encode()
+}
+
 // No need anymore, net/http checks for the Form already.
 // func (ctx *context) askParseForm() error {
 // 	if ctx.request.Form == nil {
@@ -1827,7 +2678,11 @@ func (ctx *context) form() (form map[string][]string, found bool) {
 	// therefore we don't need to call it here, although it doesn't hurt.
 	// After one call to ParseMultipartForm or ParseForm,
 	// subsequent calls have no effect, are idempotent.
-	ctx.request.ParseMultipartForm(ctx.Application().ConfigurationReadOnly().GetPostMaxMemory())
+	if err := ctx.parseMultipartForm(); err != nil && ctx.bodyDigestErr() != nil {
+		// the body does not match its "Digest" or "Content-MD5" header,
+		// none of its values can be trusted, the status code is already a 400 one.
+		return nil, false
+	}
 
 	if form := ctx.request.Form; len(form) > 0 {
 		return form, true
@@ -1851,7 +2706,9 @@ func (ctx *context) form() (form map[string][]string, found bool) {
 //
 // If not found then "def" is returned instead.
 func (ctx *context) PostValueDefault(name string, def string) string {
-	ctx.form()
+	if _, has := ctx.form(); !has {
+		return def
+	}
 	if v := ctx.request.PostForm[name]; len(v) > 0 {
 		return v[0]
 	}
@@ -1963,12 +2820,16 @@ func (ctx *context) PostValueBool(name string) (bool, error) {
 // The default form's memory maximum size is 32MB, it can be changed by the
 // `iris#WithPostMaxMemory` configurator at main configuration passed on `app.Run`'s second argument.
 func (ctx *context) PostValues(name string) []string {
-	ctx.form()
+	if _, has := ctx.form(); !has {
+		return nil
+	}
 	return ctx.request.PostForm[name]
 }
 
 // FormFile returns the first uploaded file that received from the client.
 //
+// If an upload scanner is configured, through `iris#WithUploadScanner`, the file is scanned
+// before it's returned and if rejected an `ErrScanRejected` error is returned with a 422 status code.
 //
 // The default form's memory maximum size is 32MB, it can be changed by the
 // `iris#WithPostMaxMemory` configurator at main configuration passed on `app.Run`'s second argument.
@@ -1980,11 +2841,21 @@ func (ctx *context) FormFile(key string) (multipart.File, *multipart.FileHeader,
 	// here but do it in order to apply the post limit,
 	// the internal request.FormFile will not do it if that's filled
 	// and it's not a stream body.
-	if err := ctx.request.ParseMultipartForm(ctx.Application().ConfigurationReadOnly().GetPostMaxMemory()); err != nil {
+	if err := ctx.parseMultipartForm(); err != nil {
+		return nil, nil, err
+	}
+
+	file, fh, err := ctx.request.FormFile(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = ctx.scanFiles(fh); err != nil {
+		file.Close()
 		return nil, nil, err
 	}
 
-	return ctx.request.FormFile(key)
+	return file, fh, nil
 }
 
 // UploadFormFiles uploads any received file(s) from the client
@@ -2001,7 +2872,12 @@ func (ctx *context) FormFile(key string) (multipart.File, *multipart.FileHeader,
 // Returns the copied length as int64 and
 // a not nil error if at least one new file
 // can't be created due to the operating system's permissions or
-// http.ErrMissingFile if no file received.
+// http.ErrMissingFile if the request is not a multipart form, a form without files uploads nothing.
+//
+// If an upload scanner is configured, through `iris#WithUploadScanner`, each file is scanned
+// before it's saved and if rejected an `ErrScanRejected` error is returned with a 422 status code.
+// If the `iris#WithUploadContentTypeVerification` is used then each file's contents are verified
+// against its declared type and if mismatch an `ErrContentTypeMismatch` error is returned with a 415 status code.
 //
 // If you want to receive & accept files and manage them manually you can use the `context#FormFile`
 // instead and create a copy function that suits your needs, the below is for generic usage.
@@ -2013,51 +2889,192 @@ func (ctx *context) FormFile(key string) (multipart.File, *multipart.FileHeader,
 //
 // Example: https://github.com/kataras/iris/tree/master/_examples/http_request/upload-files
 func (ctx *context) UploadFormFiles(destDirectory string, before ...func(Context, *multipart.FileHeader)) (n int64, err error) {
-	err = ctx.request.ParseMultipartForm(ctx.Application().ConfigurationReadOnly().GetPostMaxMemory())
+	return ctx.UploadFormFilesWith(destDirectory, UploadOptions{Before: before})
+}
+
+// UploadFormFilesWith same as `UploadFormFiles` but it accepts options
+// which limit the size, the extensions and the content types of the files,
+// sanitize or randomize their filenames and report their stored path.
+//
+// The files are checked before any of them is saved,
+// the too large ones fail with a 413 status code and an `ErrUploadFileTooLarge` or `ErrUploadTotalTooLarge` error,
+// the not allowed ones fail with a 415 status code and an `ErrUploadFileNotAllowed` error.
+func (ctx *context) UploadFormFilesWith(destDirectory string, opts UploadOptions) (n int64, err error) {
+	err = ctx.parseMultipartForm()
 	if err != nil {
 		return 0, err
 	}
 
-	if ctx.request.MultipartForm != nil {
-		if fhs := ctx.request.MultipartForm.File; fhs != nil {
-			for _, files := range fhs {
-				for _, file := range files {
-
-					for _, b := range before {
-						b(ctx, file)
-					}
-
-					n0, err0 := uploadTo(file, destDirectory)
-					if err0 != nil {
-						return 0, err0
-					}
-					n += n0
-				}
-			}
-			return n, nil
-		}
+	if ctx.request.MultipartForm == nil || ctx.request.MultipartForm.File == nil {
+		return 0, http.ErrMissingFile
 	}
 
-	return 0, http.ErrMissingFile
-}
-
-func uploadTo(fh *multipart.FileHeader, destDirectory string) (int64, error) {
-	src, err := fh.Open()
-	if err != nil {
-		return 0, err
+	fhs := ctx.request.MultipartForm.File
+	keys := make([]string, 0, len(fhs))
+	for key := range fhs {
+		keys = append(keys, key)
 	}
-	defer src.Close()
+	sort.Strings(keys)
 
-	out, err := os.OpenFile(filepath.Join(destDirectory, fh.Filename),
-		os.O_WRONLY|os.O_CREATE, os.FileMode(0666))
+	var files []*multipart.FileHeader
+	for _, key := range keys {
+		files = append(files, fhs[key]...)
+	}
 
-	if err != nil {
+	if err = opts.check(files); err != nil {
+		if e, ok := err.(errors.Error); ok && ErrUploadFileNotAllowed.Equal(e) {
+			ctx.StatusCode(http.StatusUnsupportedMediaType)
+		} else {
+			ctx.StatusCode(http.StatusRequestEntityTooLarge)
+		}
 		return 0, err
 	}
-	defer out.Close()
 
-	return io.Copy(out, src)
-}
+	for _, file := range files {
+		if opts.RandomFilename {
+			file.Filename = RandomFilename(file.Filename)
+		} else if opts.SanitizeFilename {
+			file.Filename = SanitizeFilename(file.Filename)
+		}
+
+		for _, b := range opts.Before {
+			b(ctx, file)
+		}
+
+		if ctx.Application().ConfigurationReadOnly().GetUploadContentTypeVerification() {
+			if err0 := VerifyContentType(file); err0 != nil {
+				ctx.StatusCode(http.StatusUnsupportedMediaType)
+				return 0, err0
+			}
+		}
+
+		if err0 := ctx.scanFiles(file); err0 != nil {
+			return 0, err0
+		}
+
+		dest := filepath.Join(destDirectory, file.Filename)
+		n0, err0 := uploadTo(file, dest)
+		if err0 != nil {
+			return 0, err0
+		}
+		n += n0
+
+		if opts.OnUploaded != nil {
+			if err0 = opts.OnUploaded(ctx, file, dest); err0 != nil {
+				return n, err0
+			}
+		}
+	}
+
+	return n, nil
+}
+
+const (
+	bodyDigestContextKey    = "@body_digest"
+	bodyDigestErrContextKey = "@body_digest_err"
+)
+
+// parseMultipartForm parses the multipart form, based on the `iris#WithPostMaxMemory` configuration,
+// and verifies the "Digest" or "Content-MD5" request headers against the received body, if any.
+// Subsequent calls return the result of the first call.
+func (ctx *context) parseMultipartForm() error {
+	if ctx.request.MultipartForm != nil {
+		return ctx.bodyDigestErr()
+	}
+
+	dr := newDigestReader(ctx.request)
+	if dr != nil {
+		ctx.request.Body = dr
+	}
+
+	err := ctx.request.ParseMultipartForm(ctx.Application().ConfigurationReadOnly().GetPostMaxMemory())
+	// the url-encoded forms are parsed even if it's not a multipart one.
+	if cErr := ctx.decodeFormCharset(); cErr != nil {
+		return cErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if dr != nil {
+		digest, err := dr.verify()
+		ctx.values.Set(bodyDigestContextKey, digest)
+		if err != nil {
+			ctx.values.Set(bodyDigestErrContextKey, err)
+			ctx.StatusCode(http.StatusBadRequest)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bodyDigestErr returns the `ErrDigestMismatch` of the parsed multipart form, if any.
+func (ctx *context) bodyDigestErr() error {
+	err, _ := ctx.values.Get(bodyDigestErrContextKey).(error)
+	return err
+}
+
+// BodyDigest returns the digest of the request body computed while a multipart form was parsed,
+// in form of "ALGORITHM=base64-value", i.e "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=".
+// It's filled only when the client sent a "Digest" or a "Content-MD5" header,
+// useful to be stored as metadata of the uploaded files.
+//
+// When the received body does not match these headers the upload helpers
+// (FormFile, UploadFormFiles, ReadMultipart and ReadForm) fail with a 400 Bad Request status code
+// and an `ErrDigestMismatch` error and the form values are not available at all.
+func (ctx *context) BodyDigest() string {
+	return ctx.values.GetString(bodyDigestContextKey)
+}
+
+// scanFiles scans the "files" through the configured upload scanner, if any,
+// the first rejected one fails with a 422 status code.
+func (ctx *context) scanFiles(files ...*multipart.FileHeader) error {
+	scanner := ctx.Application().ConfigurationReadOnly().GetUploadScanner()
+	if scanner == nil {
+		return nil
+	}
+
+	for _, fh := range files {
+		if err := scanFile(scanner, fh); err != nil {
+			ctx.StatusCode(http.StatusUnprocessableEntity)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanFile(scanner Scanner, fh *multipart.FileHeader) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err = scanner.Scan(src); err != nil {
+		return ErrScanRejected.Format(fh.Filename, err.Error())
+	}
+
+	return nil
+}
+
+func uploadTo(fh *multipart.FileHeader, dest string) (int64, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(0666))
+
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	return io.Copy(out, src)
+}
 
 // Redirect sends a redirect response to the client
 // to a specific url or relative path.
@@ -2091,6 +3108,30 @@ func (ctx *context) Redirect(urlToRedirect string, statusHeader ...int) {
 	http.Redirect(ctx.writer, ctx.request, urlToRedirect, status)
 }
 
+// RedirectPreserveMethod sends a redirect response which makes the client
+// repeat the request with the same method and body to the "urlToRedirect",
+// with the 308 (StatusPermanentRedirect) status code if "permanent" is true,
+// otherwise with the 307 (StatusTemporaryRedirect) one.
+// Use it to redirect POST, PUT and DELETE requests, the 301 and 302 change them to GET on most clients.
+//
+// The relative urls are resolved against the current request's path, per RFC 3986,
+// i.e "../users" on "/api/v1/accounts" redirects to "/api/users".
+func (ctx *context) RedirectPreserveMethod(urlToRedirect string, permanent bool) {
+	ctx.StopExecution()
+
+	status := http.StatusTemporaryRedirect
+	if permanent {
+		status = http.StatusPermanentRedirect
+	}
+
+	if u, err := url.Parse(urlToRedirect); err == nil && !u.IsAbs() && u.Host == "" {
+		urlToRedirect = ctx.request.URL.ResolveReference(u).String()
+	}
+
+	ctx.writer.Header().Set("Location", urlToRedirect)
+	ctx.StatusCode(status)
+}
+
 //  +------------------------------------------------------------+
 //  | Body Readers                                               |
 //  +------------------------------------------------------------+
@@ -2101,11 +3142,75 @@ func (ctx *context) SetMaxRequestBodySize(limitOverBytes int64) {
 	ctx.request.Body = http.MaxBytesReader(ctx.writer, ctx.request.Body, limitOverBytes)
 }
 
+// RecordRequestBody reads the request body up to "limit" bytes and keeps it,
+// so middleware, i.e audit logging or signature verification, can read the body
+// and the next handlers can still read it through the `ReadJSON`, `UnmarshalBody` and the rest.
+// The body is rewound on each call and after each `UnmarshalBody`, i.e `ReadJSON` and `ReadXML`.
+// A zero "limit" means the `Configuration#PostMaxMemory`.
+//
+// It returns the `ErrRequestBodyTooLarge` error and sets the 413 Request Entity Too Large status code
+// if the body is larger than the "limit", the body is not recorded but it can still be read once.
+func (ctx *context) RecordRequestBody(limit int64) ([]byte, error) {
+	if b, ok := ctx.values.Get(recordedBodyContextKey).([]byte); ok {
+		ctx.request.Body = recordedBody(b, ctx.request.Body)
+		return b, nil
+	}
+
+	if ctx.request.Body == nil {
+		return nil, nil
+	}
+
+	if limit <= 0 {
+		limit = ctx.Application().ConfigurationReadOnly().GetPostMaxMemory()
+	}
+
+	body := ctx.request.Body
+	b, err := ioutil.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(b)) > limit {
+		ctx.request.Body = readerCloser{Reader: io.MultiReader(bytes.NewReader(b), body), Closer: body}
+		ctx.StatusCode(http.StatusRequestEntityTooLarge)
+		return nil, ErrRequestBodyTooLarge.Format(limit)
+	}
+
+	ctx.values.Set(recordedBodyContextKey, b)
+	ctx.request.Body = recordedBody(b, body)
+	return b, nil
+}
+
+// TrackUploadProgress reports the bytes of the request body which are read by the next handlers
+// to the "onProgress", the total is the "Content-Length" or negative if it's unknown, i.e chunked.
+// It should be called before the body is read, i.e by a middleware,
+// see the `middleware/uploadprogress` for a progress endpoint keyed by an upload ID.
+func (ctx *context) TrackUploadProgress(onProgress ProgressFunc) {
+	if ctx.request.Body == nil || onProgress == nil {
+		return
+	}
+
+	// the server sets it to -1 when the length is unknown.
+	total := ctx.request.ContentLength
+	ctx.request.Body = &progressReader{ReadCloser: ctx.request.Body, total: total, onProgress: onProgress}
+}
+
 // UnmarshalBody reads the request's body and binds it to a value or pointer of any type
 // Examples of usage: context.ReadJSON, context.ReadXML.
 //
+// If a `Validator` is configured, through `iris#WithValidator`, the decoded value is validated
+// and a `ValidationErrors` error is returned with a 400 status code if it's invalid.
+//
 // Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-custom-via-unmarshaler/main.go
 func (ctx *context) UnmarshalBody(outPtr interface{}, unmarshaler Unmarshaler) error {
+	if err := ctx.unmarshalBody(outPtr, unmarshaler); err != nil {
+		return err
+	}
+
+	return ctx.validate(outPtr)
+}
+
+func (ctx *context) unmarshalBody(outPtr interface{}, unmarshaler Unmarshaler) error {
 	if ctx.request.Body == nil {
 		return errors.New("unmarshal: empty body")
 	}
@@ -2119,6 +3224,13 @@ func (ctx *context) UnmarshalBody(outPtr interface{}, unmarshaler Unmarshaler) e
 		// * remember, Request.Body has no Bytes(), we have to consume them first
 		// and after re-set them to the body, this is the only solution.
 		ctx.request.Body = ioutil.NopCloser(bytes.NewBuffer(rawData))
+	} else if b, ok := ctx.values.Get(recordedBodyContextKey).([]byte); ok {
+		// rewind the recorded body, see `RecordRequestBody`.
+		ctx.request.Body = recordedBody(b, ctx.request.Body)
+	}
+
+	if rawData, err = ctx.decodeCharset(rawData); err != nil {
+		return err
 	}
 
 	// check if the v contains its own decode
@@ -2146,13 +3258,98 @@ func (ctx *context) shouldOptimize() bool {
 
 // ReadJSON reads JSON from request's body and binds it to a value of any json-valid type.
 //
+// The optional "opts" can reject the unknown fields, the too deep or the too large bodies,
+// the failures are returned as `*JSONReadError` which describes the offending field.
+//
 // Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-json/main.go
-func (ctx *context) ReadJSON(jsonObject interface{}) error {
-	var unmarshaler = json.Unmarshal
-	if ctx.shouldOptimize() {
-		unmarshaler = jsoniter.Unmarshal
+func (ctx *context) ReadJSON(jsonObject interface{}, opts ...JSONReader) error {
+	if len(opts) > 0 {
+		ctx.request.Body = limitBody(ctx.request.Body, opts[0].MaxBodySize)
+		return ctx.UnmarshalBody(jsonObject, opts[0])
+	}
+
+	return ctx.UnmarshalBody(jsonObject, UnmarshalerFunc(ctx.jsonCodec().Unmarshal))
+}
+
+var (
+	errReadJSONStreamHandler = errors.New("read json stream: expected a func(item T) error but got %s")
+	errReadJSONStreamArray   = errors.New("read json stream: expected a JSON array but got %v")
+)
+
+var errorTyp = reflect.TypeOf((*error)(nil)).Elem()
+
+// ReadJSONStream decodes a JSON array from the request's body element by element,
+// without buffering the whole payload into memory first.
+// The "onItem" should be a function of form `func(item T) error`,
+// a new T is decoded for each array element and passed to the function,
+// any other "onItem", i.e nil, fails before the body is read.
+// If "onItem" returns a non-nil error the decoding stops immediately
+// and that error is returned back to the caller.
+// If a `Validator` is configured, through `iris#WithValidator`, each item is validated before the "onItem"
+// and the decoding stops with a `ValidationErrors` error and a 400 status code on the first invalid one.
+//
+// Useful for bulk import endpoints which accept large arrays.
+func (ctx *context) ReadJSONStream(onItem interface{}) error {
+	fn := reflect.ValueOf(onItem)
+	if !fn.IsValid() {
+		return errReadJSONStreamHandler.Format("nil")
+	}
+
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func || fn.IsNil() || typ.IsVariadic() ||
+		typ.NumIn() != 1 || typ.NumOut() != 1 || typ.Out(0) != errorTyp {
+		return errReadJSONStreamHandler.Format(typ.String())
+	}
+
+	if ctx.request.Body == nil {
+		return errors.New("unmarshal: empty body")
+	}
+
+	itemTyp := typ.In(0)
+	isPtr := itemTyp.Kind() == reflect.Ptr
+	if isPtr {
+		itemTyp = itemTyp.Elem()
+	}
+
+	// the decoder splits the items, they are decoded by the JSON codec.
+	codec := ctx.jsonCodec()
+	dec := json.NewDecoder(ctx.request.Body)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
 	}
-	return ctx.UnmarshalBody(jsonObject, UnmarshalerFunc(unmarshaler))
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return errReadJSONStreamArray.Format(tok)
+	}
+
+	for dec.More() {
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			return err
+		}
+
+		item := reflect.New(itemTyp)
+		if err = codec.Unmarshal(raw, item.Interface()); err != nil {
+			return err
+		}
+
+		if err = ctx.validate(item.Interface()); err != nil {
+			return err
+		}
+
+		if !isPtr {
+			item = item.Elem()
+		}
+
+		if out := fn.Call([]reflect.Value{item})[0]; !out.IsNil() {
+			return out.Interface().(error)
+		}
+	}
+
+	// consume the closing bracket.
+	_, err = dec.Token()
+	return err
 }
 
 // ReadXML reads XML from request's body and binds it to a value of any xml-valid type.
@@ -2162,24 +3359,353 @@ func (ctx *context) ReadXML(xmlObject interface{}) error {
 	return ctx.UnmarshalBody(xmlObject, UnmarshalerFunc(xml.Unmarshal))
 }
 
-var (
-	errReadBody = errors.New("while trying to read %s from the request body. Trace %s")
-)
+// ReadYAML reads YAML from request's body and binds it to a pointer of a value of any yaml-valid type.
+// The body size and the number of its aliases are limited by the `DefaultYAMLReaderOptions`,
+// the optional "opts" can override them.
+func (ctx *context) ReadYAML(yamlObject interface{}, opts ...YAMLReader) error {
+	options := DefaultYAMLReaderOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	ctx.request.Body = limitBody(ctx.request.Body, options.MaxBodySize)
+	return ctx.UnmarshalBody(yamlObject, options)
+}
+
+// ReadMsgPack reads MessagePack from request's body and binds it to a pointer of a value of any type.
+func (ctx *context) ReadMsgPack(msgpackObject interface{}) error {
+	return ctx.UnmarshalBody(msgpackObject, UnmarshalerFunc(msgpack.Unmarshal))
+}
+
+// ReadProtobuf reads a Protocol Buffers message from request's body and binds it to the "msg".
+// If the request's content type is JSON then the body is decoded from its JSON transcoding.
+//
+// See `DefaultProtoCodec` too.
+func (ctx *context) ReadProtobuf(msg ProtoMessage) error {
+	unmarshal := DefaultProtoCodec.Unmarshal
+	if strings.HasPrefix(ctx.GetHeader(ContentTypeHeaderKey), ContentJSONHeaderValue) {
+		unmarshal = DefaultProtoCodec.UnmarshalJSON
+	}
+
+	return ctx.UnmarshalBody(msg, UnmarshalerFunc(func(data []byte, _ interface{}) error {
+		return unmarshal(data, msg)
+	}))
+}
 
 // ReadForm binds the formObject  with the form data
 // it supports any kind of struct.
 //
+// The keys are the field names or their "form" tags and they can describe nested values:
+// "address.city" for structs, "items[0].name" for slices, "tags[]" to append to a slice
+// and "meta[color]" for maps. The `encoding.TextUnmarshaler` types decode their own values.
+// A field with a "form" tag matches its name too, a field with a "form:"-"" tag is skipped.
+// The returned error is a `FormErrors` which reports each one of the failed fields,
+// including the keys which do not match any field.
+//
 // Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-form/main.go
 func (ctx *context) ReadForm(formObject interface{}) error {
 	values := ctx.FormValues()
 	if values == nil {
+		if err := ctx.bodyDigestErr(); err != nil {
+			return err
+		}
 		return errors.New("An empty form passed on ReadForm")
 	}
 
-	// or dec := formbinder.NewDecoder(&formbinder.DecoderOptions{TagName: "form"})
-	// somewhere at the app level. I did change the tagName to "form"
-	// inside its source code, so it's not needed for now.
-	return errReadBody.With(formbinder.Decode(values, formObject))
+	if err := decodeForm(values, formObject); err != nil {
+		return err
+	}
+
+	return ctx.validate(formObject)
+}
+
+var (
+	errReadMultipartPtr = errors.New("read multipart: expected a pointer to a struct but got %s")
+	errReadMultipart    = errors.New("read multipart: part '%s': %s")
+	fileHeaderTyp       = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersTyp      = reflect.TypeOf([]*multipart.FileHeader(nil))
+	bytesTyp            = reflect.TypeOf([]byte(nil))
+)
+
+// ReadMultipart binds a multipart/form-data request to the "outPtr" struct,
+// each field is mapped to a part by its `part:"name"` tag.
+// Fields of type *multipart.FileHeader or []*multipart.FileHeader receive the uploaded file(s),
+// string fields receive the part's value, []byte fields its raw contents,
+// any other field type is decoded from the part's contents as JSON,
+// i.e: Meta CreateDoc `part:"meta"` and File *multipart.FileHeader `part:"file"`.
+//
+// Parts that are missing from the request are ignored.
+//
+// If an upload scanner is configured, through `iris#WithUploadScanner`, the files of the tagged parts
+// are scanned before they're bound and if rejected an `ErrScanRejected` error is returned with a 422 status code.
+//
+// The default form's memory maximum size is 32MB, it can be changed by the
+// `iris#WithPostMaxMemory` configurator at main configuration passed on `app.Run`'s second argument.
+func (ctx *context) ReadMultipart(outPtr interface{}) error {
+	v := reflect.ValueOf(outPtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errReadMultipartPtr.Format(v.Kind().String())
+	}
+
+	if err := ctx.parseMultipartForm(); err != nil {
+		return err
+	}
+
+	form := ctx.request.MultipartForm
+	if form == nil {
+		return http.ErrNotMultipart
+	}
+
+	v = v.Elem()
+	typ := v.Type()
+
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		f := typ.Field(i)
+		name := f.Tag.Get("part")
+		if name == "" || name == "-" || f.PkgPath != "" { // untagged or unexported.
+			continue
+		}
+
+		field := v.Field(i)
+		files := form.File[name]
+		if err := ctx.scanFiles(files...); err != nil {
+			return err
+		}
+
+		switch f.Type {
+		case fileHeaderTyp:
+			if len(files) > 0 {
+				field.Set(reflect.ValueOf(files[0]))
+			}
+			continue
+		case fileHeadersTyp:
+			if len(files) > 0 {
+				field.Set(reflect.ValueOf(files))
+			}
+			continue
+		}
+
+		// the part may be sent as a simple value or as a file (i.e application/json part with a filename).
+		var contents []byte
+		if values := form.Value[name]; len(values) > 0 {
+			contents = []byte(values[0])
+		} else if len(files) > 0 {
+			b, err := readFileHeader(files[0])
+			if err != nil {
+				return errReadMultipart.Format(name, err.Error())
+			}
+			contents = b
+		} else {
+			continue
+		}
+
+		switch {
+		case f.Type == bytesTyp:
+			field.SetBytes(contents)
+		case f.Type.Kind() == reflect.String:
+			field.SetString(string(contents))
+		default:
+			if err := ctx.jsonCodec().Unmarshal(contents, field.Addr().Interface()); err != nil {
+				return errReadMultipart.Format(name, err.Error())
+			}
+		}
+	}
+
+	return ctx.validate(outPtr)
+}
+
+// MultipartStream processes the parts of a multipart/form-data request as they arrive,
+// without writing them to temporary files or loading them into memory,
+// useful for endpoints that accept multi-GB uploads.
+// The "onPart" is called once per part, the part is closed after it returns
+// and a non-nil error stops the processing and it's returned back to the caller.
+//
+// The optional "limits" reject the too large parts and the requests with too many parts,
+// with the `ErrMultipartPartTooLarge` and `ErrMultipartTooManyParts` errors
+// and a 413 Request Entity Too Large status code.
+//
+// The request body can be read once, it can't be combined with the `FormFile`, `FormValue` and the rest of the form helpers.
+// The parts are not scanned by the `iris#WithUploadScanner`, they are never stored,
+// the "onPart" should scan them itself, i.e through the `Configuration#GetUploadScanner`, or use the `FormFiles` instead.
+func (ctx *context) MultipartStream(onPart func(part *multipart.Part) error, limits ...MultipartLimits) error {
+	var opts MultipartLimits
+	if len(limits) > 0 {
+		opts = limits[0]
+	}
+
+	if ctx.request.Body == nil {
+		return http.ErrNotMultipart
+	}
+
+	body := &partLimitedBody{ReadCloser: ctx.request.Body, max: opts.MaxPartSize}
+	ctx.request.Body = body
+
+	mr, err := ctx.request.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	// fail checks if the request body was rejected by the limits, the multipart reader wraps its errors.
+	fail := func(err error) error {
+		if body.err != nil {
+			ctx.StatusCode(http.StatusRequestEntityTooLarge)
+			return body.err
+		}
+		return err
+	}
+
+	for n := 1; ; n++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fail(err)
+		}
+
+		if opts.MaxParts > 0 && n > opts.MaxParts {
+			part.Close()
+			ctx.StatusCode(http.StatusRequestEntityTooLarge)
+			return ErrMultipartTooManyParts.Format(opts.MaxParts)
+		}
+
+		body.reset()
+		err = onPart(part)
+		part.Close()
+		if err != nil {
+			return fail(err)
+		}
+	}
+}
+
+// FormFiles iterates over the uploaded files of a multipart/form-data request as they arrive,
+// instead of parsing the whole form up front like the `FormFile` and the `UploadFormFiles` do.
+// Each file is kept in memory up to the `Configuration#PostMaxMemory` bytes and the rest
+// of it in a temporary file, which is removed after the "onFile" returns.
+// A non-nil error of the "onFile" stops the iteration and it's returned back to the caller.
+//
+// The rest of the form's values are stored to the request's post form,
+// so they are available through the `PostValue` and `FormValue` after the call.
+//
+// If an upload scanner is configured, through `iris#WithUploadScanner`, each file is scanned
+// before the "onFile" and if rejected an `ErrScanRejected` error is returned with a 422 status code.
+//
+// Example:
+// err := ctx.FormFiles(func(fh *multipart.FileHeader) error {
+// 	f, err := fh.Open()
+// 	if err != nil {
+// 		return err
+// 	}
+// 	defer f.Close()
+// 	return store(fh.Filename, f)
+// })
+func (ctx *context) FormFiles(onFile func(fh *multipart.FileHeader) error) error {
+	maxMemory := ctx.Application().ConfigurationReadOnly().GetPostMaxMemory()
+
+	if ctx.request.PostForm == nil {
+		ctx.request.PostForm = make(url.Values)
+	}
+	if ctx.request.Form == nil {
+		ctx.request.Form = make(url.Values)
+	}
+
+	return ctx.MultipartStream(func(part *multipart.Part) error {
+		name := part.FormName()
+		if name == "" {
+			return nil
+		}
+
+		if part.FileName() == "" {
+			b, err := ioutil.ReadAll(io.LimitReader(part, maxMemory+1))
+			if err != nil {
+				return err
+			}
+			if int64(len(b)) > maxMemory {
+				return multipart.ErrMessageTooLarge
+			}
+
+			ctx.request.PostForm.Add(name, string(b))
+			ctx.request.Form.Add(name, string(b))
+			return nil
+		}
+
+		form, err := readFilePart(part, maxMemory)
+		if err != nil {
+			return err
+		}
+		defer form.RemoveAll()
+
+		for _, fh := range form.File[name] {
+			if err = ctx.scanFiles(fh); err != nil {
+				return err
+			}
+			if err = onFile(fh); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Bind fills the "ptr" struct from all the sources of the request.
+// The body is decoded first, by its content type (JSON, XML, YAML, MessagePack or form),
+// then the fields tagged with `param:"name"`, `query:"name"` and `header:"name"`
+// are set from the path parameters, the url query and the headers, they override the body's values.
+// The fields of the embedded structs are promoted.
+//
+// The value is validated by the configured `Validator`, if any, after all of its fields are bound.
+// The body's errors are returned as they are, the tagged fields' ones as `BindErrors`,
+// i.e: ID int64 `param:"id"`, Page int `query:"page"`, Token string `header:"X-Token"`
+// and Name string `json:"name"`.
+func (ctx *context) Bind(ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errBindPtr.Format(ptr)
+	}
+
+	if err := ctx.bindBody(ptr); err != nil {
+		return err
+	}
+
+	if errs := ctx.bindFields(v.Elem(), nil); len(errs) > 0 {
+		return errs
+	}
+
+	return ctx.validate(ptr)
+}
+
+// ReadQuery binds the url query parameters to the "ptr" struct.
+// The keys are the `url:"name"` tags of the fields or their names, `url:"-"` skips a field,
+// the fields of the embedded structs are promoted.
+// The `default:"value"` tag sets the value of a missing parameter,
+// it's comma-separated for the slices, which are filled by the repeated parameters, i.e ?tag=a&tag=b.
+//
+// The value is validated by the configured `Validator`, if any.
+// The decoding failures are returned as `BindErrors`, one for each failed field.
+//
+// Example: Page int `url:"page" default:"1"`, Tags []string `url:"tag"`.
+func (ctx *context) ReadQuery(ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errBindPtr.Format(ptr)
+	}
+
+	if errs := bindQuery(v.Elem(), ctx.request.URL.Query(), nil); len(errs) > 0 {
+		return errs
+	}
+
+	return ctx.validate(ptr)
+}
+
+func readFileHeader(fh *multipart.FileHeader) ([]byte, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return ioutil.ReadAll(src)
 }
 
 //  +------------------------------------------------------------+
@@ -2358,6 +3884,24 @@ func (ctx *context) WriteWithExpiration(body []byte, modtime time.Time) (int, er
 	return ctx.writer.Write(body)
 }
 
+// WriteWithEtag writes the "body" with its ETag, see `ComputeETag`,
+// or it sends a 304 Not Modified, without the body, if the ETag matches
+// the "If-None-Match" request header of a GET or HEAD request.
+//
+// Look the `ETag` middleware to compute the ETag of all the responses of a route.
+func (ctx *context) WriteWithEtag(body []byte) (int, error) {
+	etag := ComputeETag(body)
+	ctx.Header(ETagHeaderKey, etag)
+
+	if method := ctx.Method(); (method == http.MethodGet || method == http.MethodHead) &&
+		etagMatches(ctx.GetHeader(IfNoneMatchHeaderKey), etag) {
+		ctx.WriteNotModified()
+		return 0, nil
+	}
+
+	return ctx.writer.Write(body)
+}
+
 // StreamWriter registers the given stream writer for populating
 // response body.
 //
@@ -2390,20 +3934,107 @@ func (ctx *context) StreamWriter(writer func(w io.Writer) bool) {
 	}
 }
 
+// Stream calls the "writer" function until it returns false or the client is gone,
+// the data are written to a buffer which is flushed to the client
+// after each call, or every `StreamOptions#FlushInterval` even while the "writer" waits, and on the `StreamWriter#Flush`.
+// It's useful for long-polling and progressive rendering handlers,
+// the "writer" should wait for new data, i.e on a channel, and select on the `StreamWriter#Done` too.
+//
+// It returns the request context's error if the client is gone or the first write error, if any.
+func (ctx *context) Stream(writer func(w *StreamWriter) bool, opts ...StreamOptions) error {
+	var options StreamOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	size := options.BufferSize
+	if size <= 0 {
+		size = 4096
+	}
+
+	w := &StreamWriter{
+		ctx: ctx,
+		buf: bufio.NewWriterSize(ctx.writer, size),
+	}
+
+	if options.FlushInterval > 0 {
+		// stopped before the return, so nothing is written after the handler.
+		stop := w.flushEvery(options.FlushInterval)
+		defer stop()
+	}
+
+	for {
+		if w.Gone() {
+			return ctx.request.Context().Err()
+		}
+
+		shouldContinue := writer(w)
+		if !shouldContinue {
+			return w.Flush()
+		}
+
+		if options.FlushInterval > 0 {
+			if err := w.Err(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// Push initiates an HTTP/2 server push of the "target", a local path i.e "/public/app.css",
+// the client receives it before it parses the response which references it.
+// The optional "opts" can set the method and the headers of the pushed request.
+//
+// It does nothing and returns nil if the push is not supported, i.e on HTTP/1.x,
+// so it can be called unconditionally. It should be called before writing the response's body.
+//
+// Look `iris#WithPushViewAssets` to push the assets of the views automatically.
+func (ctx *context) Push(target string, opts ...*http.PushOptions) error {
+	var options *http.PushOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	err := ctx.writer.Push(target, options)
+	if e, ok := err.(errors.Error); ok && ErrPushNotSupported.Equal(e) {
+		return nil
+	}
+
+	return err
+}
+
 //  +------------------------------------------------------------+
 //  | Body Writers with compression                              |
 //  +------------------------------------------------------------+
 
 // ClientSupportsGzip retruns true if the client supports gzip compression.
+// It returns false if the compression was disabled by the `NoGzip` middleware.
 func (ctx *context) ClientSupportsGzip() bool {
-	if h := ctx.GetHeader(AcceptEncodingHeaderKey); h != "" {
-		for _, v := range strings.Split(h, ";") {
-			if strings.Contains(v, GzipHeaderValue) { // we do Contains because sometimes browsers has the q=, we don't use it atm. || strings.Contains(v,"deflate"){
-				return true
-			}
-		}
+	if disabled, _ := ctx.values.GetBool(noGzipContextKey); disabled {
+		return false
 	}
-	return false
+
+	h := ctx.GetHeader(AcceptEncodingHeaderKey)
+	return h != "" && acceptsValue(h, GzipHeaderValue)
+}
+
+// ClientSupportsCompression returns the best "Content-Encoding" of the registered ones
+// that the client accepts, by the "Accept-Encoding" quality values,
+// or empty if the client does not accept any of them.
+// It returns empty if the compression was disabled by the `NoGzip` middleware.
+//
+// See `RegisterCompressor` too.
+func (ctx *context) ClientSupportsCompression() string {
+	if disabled, _ := ctx.values.GetBool(noGzipContextKey); disabled {
+		return ""
+	}
+
+	return negotiateEncoding(ctx.GetHeader(AcceptEncodingHeaderKey))
 }
 
 var (
@@ -2454,6 +4085,22 @@ func (ctx *context) GzipResponseWriter() *GzipResponseWriter {
 	return gzipResWriter
 }
 
+// Compress enables or disables (if enabled before) the compression of the response,
+// the encoding, i.e "br", "zstd", "gzip" or "deflate", is negotiated by the client's "Accept-Encoding" header,
+// so the following response data will be sent compressed to the client.
+//
+// See `RegisterCompressor` and `ClientSupportsCompression` too.
+func (ctx *context) Compress(enable bool) {
+	if !enable {
+		ctx.Gzip(false)
+		return
+	}
+
+	if encoding := ctx.ClientSupportsCompression(); encoding != "" {
+		ctx.GzipResponseWriter().encoding = encoding
+	}
+}
+
 // Gzip enables or disables (if enabled before) the gzip response writer,if the client
 // supports gzip compression, so the following response data will
 // be sent as compressed gzip data to the client.
@@ -2478,6 +4125,9 @@ const (
 	NoLayout = "iris.nolayout"
 )
 
+// viewFuncsContextKey is the request's values key of the `ViewFunc`'s functions.
+const viewFuncsContextKey = "iris.view.funcs"
+
 // ViewLayout sets the "layout" option if and when .View
 // is being called afterwards, in the same request.
 // Useful when need to set or/and change a layout based on the previous handlers in the chain.
@@ -2506,6 +4156,10 @@ func (ctx *context) ViewLayout(layoutTmplFile string) {
 // to clear the view data, developers can call:
 // ctx.Set(ctx.Application().ConfigurationReadOnly().GetViewDataContextKey(), nil)
 //
+// The view data, the layout and the functions of the `ViewFunc` are stored to the request's values,
+// so they live as long as the request does, they are never shared between requests
+// and middleware can set them without modifying the registered view engine.
+//
 // If 'key' is empty then the value is added as it's (struct or map) and developer is unable to add other value.
 //
 // Look .ViewLayout and .View too.
@@ -2531,6 +4185,28 @@ func (ctx *context) ViewData(key string, value interface{}) {
 	}
 }
 
+// ViewFunc registers a template function for the next .View calls of this request only,
+// the registered view engine's functions are not modified, so middleware can
+// pass request-specific helpers, i.e the current user or a CSRF token func:
+//
+// ctx.ViewFunc("currentUser", func() *User { return user })
+//
+// The templates are parsed once, so the view engine should know the function's name
+// before the first request, i.e by a placeholder function of the engine's `AddFunc`.
+// A view engine which does not support the per-request functions ignores them,
+// the html and django ones support them.
+//
+// Look .ViewData and .View too.
+func (ctx *context) ViewFunc(name string, fn interface{}) {
+	funcs, ok := ctx.values.Get(viewFuncsContextKey).(map[string]interface{})
+	if !ok {
+		funcs = make(map[string]interface{})
+		ctx.values.Set(viewFuncsContextKey, funcs)
+	}
+
+	funcs[name] = fn
+}
+
 // GetViewData returns the values registered by `context#ViewData`.
 // The return value is `map[string]interface{}`, this means that
 // if a custom struct registered to ViewData then this function
@@ -2595,7 +4271,11 @@ func (ctx *context) View(filename string, optionalViewModel ...interface{}) erro
 		bindingData = ctx.values.Get(cfg.GetViewDataContextKey())
 	}
 
-	err := ctx.Application().View(ctx.writer, filename, layout, bindingData)
+	if cfg.GetPushViewAssets() && ctx.request.ProtoMajor == 2 {
+		return ctx.viewAndPush(filename, layout, bindingData)
+	}
+
+	err := ctx.executeView(ctx.writer, filename, layout, bindingData)
 	if err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		ctx.StopExecution()
@@ -2604,6 +4284,42 @@ func (ctx *context) View(filename string, optionalViewModel ...interface{}) erro
 	return err
 }
 
+// executeView executes the view with the functions of the `ViewFunc`, if any,
+// and the "tr" of the `Localizer`.
+func (ctx *context) executeView(w io.Writer, filename, layout string, bindingData interface{}) error {
+	if ctx.Application().ConfigurationReadOnly().GetLocalizer() != nil {
+		if funcs, _ := ctx.values.Get(viewFuncsContextKey).(map[string]interface{}); funcs["tr"] == nil {
+			ctx.ViewFunc("tr", ctx.Tr)
+		}
+	}
+
+	if funcs, ok := ctx.values.Get(viewFuncsContextKey).(map[string]interface{}); ok && len(funcs) > 0 {
+		return ctx.Application().ViewFuncs(w, filename, layout, bindingData, funcs)
+	}
+
+	return ctx.Application().View(w, filename, layout, bindingData)
+}
+
+// viewAndPush renders the view to a buffer, pushes its stylesheets and scripts
+// and then it writes the view to the client.
+func (ctx *context) viewAndPush(filename, layout string, bindingData interface{}) error {
+	var buf bytes.Buffer
+	if err := ctx.executeView(&buf, filename, layout, bindingData); err != nil {
+		ctx.StatusCode(http.StatusInternalServerError)
+		ctx.StopExecution()
+		return err
+	}
+
+	for _, target := range pushableAssets(buf.Bytes()) {
+		if err := ctx.Push(target); err != nil {
+			ctx.Application().Logger().Debugf("push %s: %v", target, err)
+		}
+	}
+
+	_, err := ctx.Write(buf.Bytes())
+	return err
+}
+
 const (
 	// ContentBinaryHeaderValue header value for binary data.
 	ContentBinaryHeaderValue = "application/octet-stream"
@@ -2621,6 +4337,12 @@ const (
 	ContentMarkdownHeaderValue = "text/markdown"
 	// ContentYAMLHeaderValue header value for YAML data.
 	ContentYAMLHeaderValue = "application/x-yaml"
+	// ContentMsgPackHeaderValue header value for MessagePack data.
+	ContentMsgPackHeaderValue = "application/msgpack"
+	// ContentFormHeaderValue header value for the url-encoded form data.
+	ContentFormHeaderValue = "application/x-www-form-urlencoded"
+	// ContentFormMultipartHeaderValue header value for the multipart form data.
+	ContentFormMultipartHeaderValue = "multipart/form-data"
 )
 
 // Binary writes out the raw bytes as binary data.
@@ -2651,6 +4373,66 @@ type JSON struct {
 	Prefix       string
 }
 
+// ContentNDJSONHeaderValue header value for newline delimited JSON data.
+const ContentNDJSONHeaderValue = "application/x-ndjson"
+
+// JSONStream renders the "items" as a JSON array, each item is encoded
+// and flushed to the client as soon as it's received,
+// so a large result does not have to be buffered in memory.
+// It returns when the "items" channel is closed or the client is gone.
+func (ctx *context) JSONStream(items <-chan interface{}) (int, error) {
+	ctx.ContentType(ContentJSONHeaderValue)
+	return ctx.writeJSONStream(items, "[", ",", "]")
+}
+
+// NDJSON is like the `JSONStream` but it renders the "items"
+// as newline delimited JSON values, the "application/x-ndjson" content type.
+func (ctx *context) NDJSON(items <-chan interface{}) (int, error) {
+	ctx.ContentType(ContentNDJSONHeaderValue)
+	return ctx.writeJSONStream(items, "", "", "")
+}
+
+func (ctx *context) writeJSONStream(items <-chan interface{}, begin, sep, end string) (int, error) {
+	total, err := ctx.writer.WriteString(begin)
+	if err != nil {
+		return total, err
+	}
+
+	codec := ctx.jsonCodec()
+	done := ctx.request.Context().Done()
+	for i := 0; ; i++ {
+		select {
+		case <-done:
+			return total, ctx.request.Context().Err()
+		case item, ok := <-items:
+			if !ok {
+				n, err := ctx.writer.WriteString(end)
+				ctx.writer.Flush()
+				return total + n, err
+			}
+
+			b, err := codec.Marshal(item)
+			if err != nil {
+				return total, err
+			}
+
+			if i > 0 {
+				b = append([]byte(sep), b...)
+			}
+			if sep == "" {
+				b = append(b, '\n')
+			}
+
+			n, err := ctx.writer.Write(b)
+			total += n
+			if err != nil {
+				return total, err
+			}
+			ctx.writer.Flush()
+		}
+	}
+}
+
 // JSONP contains the options for the JSONP (Context's) Renderer.
 type JSONP struct {
 	// content-specific
@@ -2687,27 +4469,20 @@ var (
 // WriteJSON marshals the given interface object and writes the JSON response to the 'writer'.
 // Ignores StatusCode, Gzip, StreamingJSON options.
 func WriteJSON(writer io.Writer, v interface{}, options JSON, enableOptimization ...bool) (int, error) {
+	return writeJSON(writer, v, options, defaultJSONCodec(enableOptimization))
+}
+
+func writeJSON(writer io.Writer, v interface{}, options JSON, codec JSONCodec) (int, error) {
 	var (
-		result   []byte
-		err      error
-		optimize = len(enableOptimization) > 0 && enableOptimization[0]
+		result []byte
+		err    error
 	)
 
 	if indent := options.Indent; indent != "" {
-		marshalIndent := json.MarshalIndent
-		if optimize {
-			marshalIndent = jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent
-		}
-
-		result, err = marshalIndent(v, "", indent)
+		result, err = codec.marshalIndent(v, "", indent)
 		result = append(result, newLineB...)
 	} else {
-		marshal := json.Marshal
-		if optimize {
-			marshal = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal
-		}
-
-		result, err = marshal(v)
+		result, err = codec.Marshal(v)
 	}
 
 	if err != nil {
@@ -2739,12 +4514,14 @@ func (ctx *context) JSON(v interface{}, opts ...JSON) (n int, err error) {
 		options = opts[0]
 	}
 
-	optimize := ctx.shouldOptimize()
+	codec := ctx.jsonCodec()
+	_, custom := ctx.customJSONCodec()
 
 	ctx.ContentType(ContentJSONHeaderValue)
 
-	if options.StreamingJSON {
-		if optimize {
+	// the streaming encoders are not used when a custom codec is set, see `iris#WithJSONCodec`.
+	if options.StreamingJSON && !custom {
+		if ctx.shouldOptimize() {
 			var jsoniterConfig = jsoniter.Config{
 				EscapeHTML:    !options.UnescapeHTML,
 				IndentionStep: 4,
@@ -2765,7 +4542,7 @@ func (ctx *context) JSON(v interface{}, opts ...JSON) (n int, err error) {
 		return ctx.writer.Written(), err
 	}
 
-	n, err = WriteJSON(ctx.writer, v, options, optimize)
+	n, err = writeJSON(ctx.writer, v, options, codec)
 	if err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		return 0, err
@@ -2780,20 +4557,17 @@ var (
 
 // WriteJSONP marshals the given interface object and writes the JSON response to the writer.
 func WriteJSONP(writer io.Writer, v interface{}, options JSONP, enableOptimization ...bool) (int, error) {
+	return writeJSONP(writer, v, options, defaultJSONCodec(enableOptimization))
+}
+
+func writeJSONP(writer io.Writer, v interface{}, options JSONP, codec JSONCodec) (int, error) {
 	if callback := options.Callback; callback != "" {
 		writer.Write([]byte(callback + "("))
 		defer writer.Write(finishCallbackB)
 	}
 
-	optimize := len(enableOptimization) > 0 && enableOptimization[0]
-
 	if indent := options.Indent; indent != "" {
-		marshalIndent := json.MarshalIndent
-		if optimize {
-			marshalIndent = jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent
-		}
-
-		result, err := marshalIndent(v, "", indent)
+		result, err := codec.marshalIndent(v, "", indent)
 		if err != nil {
 			return 0, err
 		}
@@ -2801,12 +4575,7 @@ func WriteJSONP(writer io.Writer, v interface{}, options JSONP, enableOptimizati
 		return writer.Write(result)
 	}
 
-	marshal := json.Marshal
-	if optimize {
-		marshal = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal
-	}
-
-	result, err := marshal(v)
+	result, err := codec.Marshal(v)
 	if err != nil {
 		return 0, err
 	}
@@ -2827,7 +4596,46 @@ func (ctx *context) JSONP(v interface{}, opts ...JSONP) (int, error) {
 
 	ctx.ContentType(ContentJavascriptHeaderValue)
 
-	n, err := WriteJSONP(ctx.writer, v, options, ctx.shouldOptimize())
+	n, err := writeJSONP(ctx.writer, v, options, ctx.jsonCodec())
+	if err != nil {
+		ctx.StatusCode(http.StatusInternalServerError)
+		return 0, err
+	}
+
+	return n, err
+}
+
+// Problem writes the "p" as an RFC 7807 Problem Details JSON response,
+// "application/problem+json", with its status code.
+// The missing type, title and status are filled by the response's status code,
+// the `ProblemHandler` renders the error codes as problems.
+//
+// Example: ctx.Problem(iris.NewProblem(iris.StatusBadRequest, "the name is required").Key("field", "name"))
+func (ctx *context) Problem(p Problem, opts ...JSON) (int, error) {
+	options := DefaultJSONOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+		if status := ctx.GetStatusCode(); StatusCodeNotSuccessful(status) {
+			p.Status = status
+		}
+	}
+
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	if p.Title == "" {
+		p.Title = ctx.StatusText(p.Status)
+	}
+
+	ctx.ContentType(ContentProblemJSONHeaderValue)
+	ctx.StatusCode(p.Status)
+
+	n, err := writeJSON(ctx.writer, p.toMap(), options, ctx.jsonCodec())
 	if err != nil {
 		ctx.StatusCode(http.StatusInternalServerError)
 		return 0, err
@@ -2925,6 +4733,124 @@ func (ctx *context) YAML(v interface{}) (int, error) {
 	return ctx.Write(out)
 }
 
+// MsgPack marshals the "v" to MessagePack and renders its result to the client.
+func (ctx *context) MsgPack(v interface{}) (int, error) {
+	out, err := msgpack.Marshal(v)
+	if err != nil {
+		ctx.StatusCode(http.StatusInternalServerError)
+		return 0, err
+	}
+
+	ctx.writer.Header().Set(ContentTypeHeaderKey, ContentMsgPackHeaderValue)
+	return ctx.Write(out)
+}
+
+// Protobuf marshals the Protocol Buffers "msg" and renders its result to the client,
+// the optional `Protobuf#JSON` option renders its JSON transcoding instead.
+//
+// See `DefaultProtoCodec` too.
+func (ctx *context) Protobuf(msg ProtoMessage, opts ...Protobuf) (int, error) {
+	options := DefaultProtobufOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	marshal, contentType := DefaultProtoCodec.Marshal, ContentProtobufHeaderValue
+	if options.JSON {
+		marshal, contentType = DefaultProtoCodec.MarshalJSON, ContentJSONHeaderValue
+	}
+
+	out, err := marshal(msg)
+	if err != nil {
+		ctx.StatusCode(http.StatusInternalServerError)
+		return 0, err
+	}
+
+	if options.JSON {
+		ctx.ContentType(contentType)
+	} else {
+		ctx.writer.Header().Set(ContentTypeHeaderKey, contentType)
+	}
+
+	return ctx.Write(out)
+}
+
+// Negotiate renders the "v" to the content type that the client prefers,
+// based on its "Accept" header, JSON, XML, YAML, MessagePack, HTML via the `N#Template` view
+// or a custom `N#Marshalers` one, i.e "application/cbor".
+//
+// The "Accept-Charset" is checked against the `Configuration#Charset` too.
+// If the client does not accept any of the offers then it responds with 406 Not Acceptable.
+// The "Vary" header is always set, so caches can keep the different representations.
+//
+// Example: ctx.Negotiate(user, context.N{Template: "user.html"})
+func (ctx *context) Negotiate(v interface{}, opts ...N) (int, error) {
+	var options N
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	h := ctx.writer.Header()
+	h.Add(VaryHeaderKey, "Accept")
+	h.Add(VaryHeaderKey, "Accept-Charset")
+	if options.Gzip {
+		h.Add(VaryHeaderKey, AcceptEncodingHeaderKey)
+	}
+
+	charset := ctx.Application().ConfigurationReadOnly().GetCharset()
+	if !acceptsValue(ctx.GetHeader("Accept-Charset"), charset) {
+		ctx.StatusCode(http.StatusNotAcceptable)
+		return 0, errNotAcceptable.Format(charset)
+	}
+
+	accept := ctx.GetHeader("Accept")
+	contentType := negotiateContentType(accept, options.offers())
+	if contentType == "" {
+		ctx.StatusCode(http.StatusNotAcceptable)
+		return 0, errNotAcceptable.Format(accept)
+	}
+
+	if options.Gzip {
+		ctx.Gzip(true)
+	}
+
+	if marshal, ok := options.Marshalers[contentType]; ok {
+		b, err := marshal(v)
+		if err != nil {
+			ctx.StatusCode(http.StatusInternalServerError)
+			return 0, err
+		}
+
+		h.Set(ContentTypeHeaderKey, contentType)
+		return ctx.Write(b)
+	}
+
+	switch contentType {
+	case ContentJSONHeaderValue:
+		return ctx.JSON(v)
+	case ContentXMLHeaderValue:
+		return ctx.XML(v)
+	case ContentXMLAltHeaderValue:
+		ctx.ContentType(ContentXMLAltHeaderValue)
+		n, err := WriteXML(ctx.writer, v, DefaultXMLOptions)
+		if err != nil {
+			ctx.StatusCode(http.StatusInternalServerError)
+		}
+		return n, err
+	case ContentYAMLHeaderValue:
+		return ctx.YAML(v)
+	case ContentMsgPackHeaderValue:
+		return ctx.MsgPack(v)
+	case ContentHTMLHeaderValue:
+		if options.Template != "" {
+			return 0, ctx.View(options.Template, v)
+		}
+	}
+
+	ctx.StatusCode(http.StatusNotAcceptable)
+	return 0, errNotAcceptable.Format(contentType)
+}
+
 //  +------------------------------------------------------------+
 //  | Serve files                                                |
 //  +------------------------------------------------------------+
@@ -2987,17 +4913,101 @@ func (ctx *context) ServeFile(filename string, gzipCompression bool) error {
 //
 // Use this instead of ServeFile to 'force-download' bigger files to the client.
 func (ctx *context) SendFile(filename string, destinationName string) error {
-	ctx.writer.Header().Set(ContentDispositionHeaderKey, "attachment;filename="+destinationName)
+	ctx.writer.Header().Set(ContentDispositionHeaderKey, ContentDisposition(destinationName, false))
 	return ctx.ServeFile(filename, false)
 }
 
+// SendReader sends the contents of the "r" for force-download, if "inline" is false,
+// or to be displayed by the browser, for the files which are generated on the fly
+// or stored in an object storage.
+// The "size" is sent as the Content-Length, if it's not negative, and only that many bytes are copied.
+// The "destinationName" is the filename of the client, its extension sets the Content-Type if not already set.
+//
+// See `ContentDisposition` too.
+func (ctx *context) SendReader(r io.Reader, size int64, destinationName string, inline bool) error {
+	ctx.writer.Header().Set(ContentDispositionHeaderKey, ContentDisposition(destinationName, inline))
+
+	if ctx.GetContentType() == "" {
+		if cType := mime.TypeByExtension(filepath.Ext(destinationName)); cType != "" {
+			ctx.ContentType(cType)
+		} else {
+			ctx.ContentType(ContentBinaryHeaderValue)
+		}
+	}
+
+	if size >= 0 {
+		ctx.writer.Header().Set(ContentLengthHeaderKey, strconv.FormatInt(size, 10))
+		r = io.LimitReader(r, size)
+	}
+
+	_, err := io.Copy(ctx.writer, r)
+	return errServeContent.With(err)
+}
+
+// ServeContentReader serves the contents of the "r", i.e a proxied download or an object of a storage,
+// with the headers of a file: the Content-Type by the extension of the "name",
+// the Content-Length if the "size" is not negative and the Last-Modified if the "modtime" is not zero,
+// a 304 Not Modified is sent when the client's copy is up to date.
+// If the "r" is an `io.ReadSeeker` then the range requests are served too, see `http.ServeContent`.
+//
+// The optional "onProgress" is called with the bytes sent so far after each write to the client.
+func (ctx *context) ServeContentReader(r io.Reader, name string, size int64, modtime time.Time, onProgress ...ProgressFunc) error {
+	var w ResponseWriter = ctx.writer
+	if len(onProgress) > 0 && onProgress[0] != nil {
+		w = &progressWriter{ResponseWriter: ctx.writer, total: size, onProgress: onProgress[0]}
+	}
+
+	if rs, ok := r.(io.ReadSeeker); ok {
+		http.ServeContent(w, ctx.request, name, modtime, rs)
+		return nil
+	}
+
+	if modified, err := ctx.CheckIfModifiedSince(modtime); !modified && err == nil {
+		ctx.WriteNotModified()
+		return nil
+	}
+
+	if ctx.GetContentType() == "" {
+		if cType := mime.TypeByExtension(filepath.Ext(name)); cType != "" {
+			ctx.ContentType(cType)
+		} else {
+			ctx.ContentType(ContentBinaryHeaderValue)
+		}
+	}
+
+	if !IsZeroTime(modtime) {
+		ctx.SetLastModified(modtime)
+	}
+
+	if size >= 0 {
+		ctx.writer.Header().Set(ContentLengthHeaderKey, strconv.FormatInt(size, 10))
+		r = io.LimitReader(r, size)
+	}
+
+	if ctx.Method() == http.MethodHead {
+		ctx.StatusCode(http.StatusOK)
+		return nil
+	}
+
+	_, err := io.Copy(w, r)
+	return errServeContent.With(err)
+}
+
 //  +------------------------------------------------------------+
 //  | Cookies, Session and Flashes                               |
 //  +------------------------------------------------------------+
 
-// SetCookie adds a cookie
-func (ctx *context) SetCookie(cookie *http.Cookie) {
-	http.SetCookie(ctx.writer, cookie)
+// SetCookie adds a cookie.
+// The optional "options" set the rest of its attributes, i.e
+// `CookieSameSite(SameSiteLax)`, `CookiePartitioned` and `CookieHost`, see `CookieOption`.
+func (ctx *context) SetCookie(cookie *http.Cookie, options ...CookieOption) {
+	ctx.setCookie(NewCookie(cookie, options...))
+}
+
+func (ctx *context) setCookie(cookie *Cookie) {
+	if v := cookie.String(); v != "" {
+		ctx.writer.Header().Add("Set-Cookie", v)
+	}
 }
 
 var (
@@ -3009,20 +5019,27 @@ var (
 // SetCookieKV adds a cookie, receives just a name(string) and a value(string)
 //
 // If you use this method, it expires at 2 hours
-// use ctx.SetCookie or http.SetCookie if you want to change more fields.
-func (ctx *context) SetCookieKV(name, value string) {
+// use ctx.SetCookie or http.SetCookie if you want to change more fields,
+// or pass the "options", see `CookieOption`.
+func (ctx *context) SetCookieKV(name, value string, options ...CookieOption) {
 	c := &http.Cookie{}
 	c.Name = name
 	c.Value = url.QueryEscape(value)
 	c.HttpOnly = true
 	c.Expires = time.Now().Add(SetCookieKVExpiration)
 	c.MaxAge = int(SetCookieKVExpiration.Seconds())
-	ctx.SetCookie(c)
+	ctx.SetCookie(c, options...)
 }
 
 // GetCookie returns cookie's value by it's name
 // returns empty string if nothing was found.
-func (ctx *context) GetCookie(name string) string {
+// The optional "options" should be the same as the `SetCookie`'s ones
+// when they change the cookie's name, i.e the `CookieHost`.
+func (ctx *context) GetCookie(name string, options ...CookieOption) string {
+	if len(options) > 0 {
+		name = NewCookie(&http.Cookie{Name: name}, options...).Name
+	}
+
 	cookie, err := ctx.request.Cookie(name)
 	if err != nil {
 		return ""
@@ -3032,21 +5049,76 @@ func (ctx *context) GetCookie(name string) string {
 }
 
 // RemoveCookie deletes a cookie by it's name.
-func (ctx *context) RemoveCookie(name string) {
+// The optional "options" should be the same as the `SetCookie`'s ones,
+// the browsers delete a cookie only if its name, path and domain match.
+func (ctx *context) RemoveCookie(name string, options ...CookieOption) {
 	c := &http.Cookie{}
 	c.Name = name
 	c.Value = ""
 	c.Path = "/"
 	c.HttpOnly = true
+	cookie := NewCookie(c, options...)
 	// RFC says 1 second, but let's do it 1 minute to make sure is working
 	exp := time.Now().Add(-time.Duration(1) * time.Minute)
-	c.Expires = exp
-	c.MaxAge = -1
-	ctx.SetCookie(c)
+	cookie.Expires = exp
+	cookie.MaxAge = -1
+	ctx.setCookie(cookie)
 	// delete request's cookie also, which is temporary available.
 	ctx.request.Header.Set("Cookie", "")
 }
 
+// SetSecureCookie adds a cookie whose value is signed, and encrypted if a block key is set,
+// by the configured `SecureCookie`, see `iris#WithSecureCookie`.
+// The cookie is "HttpOnly", its path is "/" and it expires after the `SecureCookie#MaxAge`, if any,
+// the optional "options" can change them, see `CookieOption`.
+func (ctx *context) SetSecureCookie(name, value string, options ...CookieOption) error {
+	codec := ctx.Application().ConfigurationReadOnly().GetSecureCookie()
+
+	c := &http.Cookie{}
+	c.Name = name
+	c.Path = "/"
+	c.HttpOnly = true
+	if codec != nil && codec.MaxAge > 0 {
+		c.Expires = time.Now().Add(codec.MaxAge)
+		c.MaxAge = int(codec.MaxAge.Seconds())
+	}
+
+	cookie := NewCookie(c, options...)
+	// the signature depends on the final name, i.e with its "__Host-" prefix.
+	encoded, err := codec.Encode(cookie.Name, value)
+	if err != nil {
+		return err
+	}
+
+	cookie.Value = encoded
+	ctx.setCookie(cookie)
+	return nil
+}
+
+// GetSecureCookie returns the verified, and decrypted, value of a cookie
+// which was sent by the `SetSecureCookie`.
+// It returns an `ErrSecureCookieInvalid` error if the value is tampered
+// and an `ErrSecureCookieExpired` error if it's older than the `SecureCookie#MaxAge`.
+// The optional "options" should be the same as the `SetSecureCookie`'s ones
+// when they change the cookie's name, i.e the `CookieHost`.
+func (ctx *context) GetSecureCookie(name string, options ...CookieOption) (string, error) {
+	codec := ctx.Application().ConfigurationReadOnly().GetSecureCookie()
+	if codec == nil {
+		return "", ErrSecureCookieNotConfigured
+	}
+
+	if len(options) > 0 {
+		name = NewCookie(&http.Cookie{Name: name}, options...).Name
+	}
+
+	cookie, err := ctx.request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	return codec.Decode(name, cookie.Value)
+}
+
 // VisitAllCookies takes a visitor which loops
 // on each (request's) cookies' name and value.
 func (ctx *context) VisitAllCookies(visitor func(name string, value string)) {
@@ -3096,6 +5168,21 @@ func (ctx *context) Recorder() *ResponseRecorder {
 	return ctx.writer.(*ResponseRecorder)
 }
 
+// BufferResponse records the whole response of the next handlers, up to "maxSize" bytes, zero means no limit,
+// so a late middleware can still change the status code and the headers after the body was written,
+// i.e to replace the response on a late error.
+// The body is kept in memory and it's sent at the end of the request, so the memory usage
+// and the time to the first byte increase, prefer it for the small responses.
+// A response which exceeds the "maxSize" is sent directly from that point, see `ResponseRecorder#SetBufferLimit`.
+//
+// It has no effect if the response is compressed by the `Gzip`, see `Route#BufferResponse` too.
+func (ctx *context) BufferResponse(maxSize int64) {
+	ctx.Record()
+	if w, ok := ctx.IsRecording(); ok {
+		w.SetBufferLimit(maxSize)
+	}
+}
+
 // IsRecording returns the response recorder and a true value
 // when the response writer is recording the status code, body, headers and so on,
 // else returns nil and false.
@@ -3125,6 +5212,11 @@ var errTransactionInterrupted = errors.New("transaction interrupted, recovery fr
 //
 // See https://github.com/kataras/iris/tree/master/_examples/ for more
 func (ctx *context) BeginTransaction(pipe func(t *Transaction)) {
+	ctx.beginTransaction(pipe, nil)
+}
+
+// beginTransaction starts a transaction, the "outer" is not nil on the nested transactions.
+func (ctx *context) beginTransaction(pipe func(t *Transaction), outer *Transaction) {
 	// do NOT begin a transaction when the previous transaction has been failed
 	// and it was requested scoped or SkipTransactions called manually.
 	if ctx.TransactionsSkipped() {
@@ -3135,12 +5227,33 @@ func (ctx *context) BeginTransaction(pipe func(t *Transaction)) {
 	ctx.Record()
 
 	t := newTransaction(ctx) // it calls this *context, so the overriding with a new pool's New of context.Context wil not work here.
+	t.outer = outer
+	if outer != nil {
+		t.capturePanics = outer.capturePanics
+	}
+
 	defer func() {
 		if err := recover(); err != nil {
 			ctx.Application().Logger().Warn(errTransactionInterrupted.Format(err).Error())
-			// complete (again or not , doesn't matters) the scope without loud
-			t.Complete(nil)
+			if t.capturePanics {
+				// fail the transaction, its scope decides what to do next,
+				// and its outer transaction, the panics are captured by the outer one's scope too.
+				err := TransactionErrResult{
+					StatusCode: http.StatusInternalServerError,
+					Reason:     ctx.StatusText(http.StatusInternalServerError),
+				}
+				t.Complete(err)
+				if outer != nil && outer.innerErr == nil {
+					outer.innerErr = err
+				}
+			} else {
+				// complete (again or not , doesn't matters) the scope without loud
+				t.Complete(nil)
+			}
 			// we continue as normal, no need to return here*
+		} else if t.innerErr != nil && !t.hasError {
+			// a nested transaction failed but this one was not completed after it.
+			t.Complete(nil)
 		}
 
 		// write the temp contents to the original writer
@@ -3148,6 +5261,14 @@ func (ctx *context) BeginTransaction(pipe func(t *Transaction)) {
 		// give back to the transaction the original writer (SetBeforeFlush works this way and only this way)
 		// this is tricky but nessecery if we want ctx.FireStatusCode to work inside transactions
 		t.Context().ResetResponseWriter(ctx.writer)
+		// and to its nested transactions, their writer was this transaction's one.
+		for _, nested := range t.nested {
+			nested.ResetResponseWriter(ctx.writer)
+		}
+
+		if outer != nil {
+			outer.nested = append(outer.nested, append(t.nested, t.context)...)
+		}
 
 	}()
 
@@ -3258,6 +5379,14 @@ func (ctx *context) Application() Application {
 	return ctx.app
 }
 
+// Supports reports whether this version of the framework supports the "capability",
+// so middleware can adapt at runtime to different versions of it,
+// see the `Capability...` constants, i.e `CapabilityReadJSONStream`.
+func (ctx *context) Supports(capability string) bool {
+	_, ok := capabilities[capability]
+	return ok
+}
+
 var lastCapturedContextID uint64
 
 // LastCapturedContextID returns the total number of `context#String` calls.
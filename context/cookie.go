@@ -0,0 +1,170 @@
+package context
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SameSite is the "SameSite" attribute of a cookie,
+// it controls whether the cookie is sent with the cross-site requests.
+type SameSite int
+
+const (
+	// SameSiteDefault does not send the "SameSite" attribute, the browser decides.
+	SameSiteDefault SameSite = iota
+	// SameSiteLax sends the cookie with the top-level cross-site navigations, i.e links.
+	SameSiteLax
+	// SameSiteStrict sends the cookie only with the same-site requests.
+	SameSiteStrict
+	// SameSiteNone sends the cookie with all the requests, it requires the "Secure" attribute.
+	SameSiteNone
+)
+
+func (s SameSite) String() string {
+	switch s {
+	case SameSiteLax:
+		return "Lax"
+	case SameSiteStrict:
+		return "Strict"
+	case SameSiteNone:
+		return "None"
+	default:
+		return ""
+	}
+}
+
+const (
+	// CookieHostPrefix is the "__Host-" cookie name prefix, the browsers accept such a cookie
+	// only if it's secure, its path is "/" and it has no domain.
+	CookieHostPrefix = "__Host-"
+	// CookieSecurePrefix is the "__Secure-" cookie name prefix, the browsers accept such a cookie
+	// only if it's secure.
+	CookieSecurePrefix = "__Secure-"
+)
+
+// Cookie is an `http.Cookie` with the attributes that the standard one does not support,
+// it's built by the `CookieOption`s of the cookie helpers, see `Context#SetCookie`.
+type Cookie struct {
+	*http.Cookie
+	// SameSite is the "SameSite" attribute.
+	SameSite SameSite
+	// Partitioned is the "Partitioned" attribute of the CHIPS,
+	// the cookie is stored per top-level site, it requires the "Secure" attribute.
+	Partitioned bool
+}
+
+// NewCookie applies the "options" to the "cookie" and returns the result.
+// Note that the "cookie" is modified, i.e its name by the `CookieHost`.
+func NewCookie(cookie *http.Cookie, options ...CookieOption) *Cookie {
+	c := &Cookie{Cookie: cookie}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// String returns the serialization of the cookie for use in a "Set-Cookie" response header,
+// it returns empty if the cookie's name is not valid.
+func (c *Cookie) String() string {
+	s := c.Cookie.String()
+	if s == "" {
+		return ""
+	}
+
+	if sameSite := c.SameSite.String(); sameSite != "" {
+		s += "; SameSite=" + sameSite
+	}
+
+	if c.Partitioned {
+		s += "; Partitioned"
+	}
+
+	return s
+}
+
+// CookieOption modifies a cookie before it's sent to the client or read from the request,
+// the options can be passed to the `Context#SetCookie`, `Context#SetCookieKV`,
+// `Context#GetCookie` and `Context#RemoveCookie` and to the sessions' configuration.
+type CookieOption func(c *Cookie)
+
+// CookieSameSite sets the "SameSite" attribute, the `SameSiteNone` sets the "Secure" too.
+func CookieSameSite(sameSite SameSite) CookieOption {
+	return func(c *Cookie) {
+		c.SameSite = sameSite
+		if sameSite == SameSiteNone {
+			c.Secure = true
+		}
+	}
+}
+
+// CookieSecure sets the "Secure" attribute, the cookie is sent only over HTTPS.
+func CookieSecure(c *Cookie) {
+	c.Secure = true
+}
+
+// CookieHTTPOnly sets or unsets the "HttpOnly" attribute,
+// the cookie is not accessible by the client-side scripts when it's set.
+func CookieHTTPOnly(httpOnly bool) CookieOption {
+	return func(c *Cookie) {
+		c.HttpOnly = httpOnly
+	}
+}
+
+// CookieDomain sets the "Domain" attribute.
+func CookieDomain(domain string) CookieOption {
+	return func(c *Cookie) {
+		c.Domain = domain
+	}
+}
+
+// CookiePath sets the "Path" attribute.
+func CookiePath(path string) CookieOption {
+	return func(c *Cookie) {
+		c.Path = path
+	}
+}
+
+// CookieMaxAge sets the "Max-Age" and the "Expires" attributes,
+// a zero or negative "maxAge" deletes the cookie.
+func CookieMaxAge(maxAge time.Duration) CookieOption {
+	return func(c *Cookie) {
+		if maxAge <= 0 {
+			c.MaxAge = -1
+			c.Expires = time.Unix(0, 0)
+			return
+		}
+
+		c.MaxAge = int(maxAge.Seconds())
+		c.Expires = time.Now().Add(maxAge)
+	}
+}
+
+// CookiePartitioned sets the "Partitioned" (CHIPS) and the "Secure" attributes.
+func CookiePartitioned(c *Cookie) {
+	c.Partitioned = true
+	c.Secure = true
+}
+
+// CookieHost adds the "__Host-" prefix to the cookie's name
+// and sets the attributes that the browsers require for it:
+// "Secure", "Path=/" and no "Domain".
+func CookieHost(c *Cookie) {
+	if !strings.HasPrefix(c.Name, CookieHostPrefix) {
+		c.Name = CookieHostPrefix + c.Name
+	}
+
+	c.Secure = true
+	c.Path = "/"
+	c.Domain = ""
+}
+
+// CookieSecureName adds the "__Secure-" prefix to the cookie's name and sets the "Secure" attribute.
+func CookieSecureName(c *Cookie) {
+	if !strings.HasPrefix(c.Name, CookieSecurePrefix) {
+		c.Name = CookieSecurePrefix + c.Name
+	}
+
+	c.Secure = true
+}
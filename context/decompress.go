@@ -0,0 +1,161 @@
+package context
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// ErrDecompressionLimit is returned by the request body readers
+// when a compressed request body exceeds the `DecompressOptions` limits,
+// it's a strong indication of a "zip bomb".
+var ErrDecompressionLimit = errors.New("request body: decompression limit exceeded: %s")
+
+// DecompressOptions are the limits of the `DecompressBody` middleware.
+type DecompressOptions struct {
+	// MaxSize is the maximum size of the decompressed body, in bytes.
+	// Zero means no limit.
+	//
+	// Defaults to 32MB.
+	MaxSize int64
+	// MaxRatio is the maximum expansion ratio, decompressed/compressed bytes,
+	// it's enforced after the first 64KB of the decompressed body.
+	// Zero means no limit.
+	//
+	// Defaults to 100.
+	MaxRatio float64
+	// OnLimit if not nil it's called when a limit was exceeded,
+	// it can be used to record metrics or to log the client.
+	// The status code is already set to 413 at that point.
+	OnLimit func(ctx Context, err error)
+}
+
+// DefaultDecompressOptions are the default limits of the `DecompressBody` middleware.
+var DefaultDecompressOptions = DecompressOptions{
+	MaxSize:  32 << 20,
+	MaxRatio: 100,
+}
+
+// ratioThreshold is the size of decompressed data which
+// should be read before the ratio check,
+// small payloads can have very high ratios.
+const ratioThreshold = 64 << 10
+
+// DecompressBody is a middleware which decompresses the request body
+// when it's sent with a "Content-Encoding" of "gzip" or "deflate",
+// so the next handlers can read it through the `ReadJSON`, `ReadForm` and e.t.c. as usual.
+// The decompression is aborted as soon as one of the "opts" limits is exceeded,
+// the body readers fail with an `ErrDecompressionLimit` error and the status code is set to 413.
+//
+// Receives optional `DecompressOptions`, defaults to the `DefaultDecompressOptions`.
+// See the `Route#Decompress` to enable it per route.
+var DecompressBody = func(opts ...DecompressOptions) Handler {
+	options := DefaultDecompressOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	return func(ctx Context) {
+		r := ctx.Request()
+		if r.Body == nil {
+			ctx.Next()
+			return
+		}
+
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get(ContentEncodingHeaderKey)))
+		if encoding != GzipHeaderValue && encoding != "deflate" {
+			ctx.Next()
+			return
+		}
+
+		compressed := &countingReader{r: r.Body}
+		var (
+			dec io.ReadCloser
+			err error
+		)
+
+		if encoding == GzipHeaderValue {
+			dec, err = gzip.NewReader(compressed)
+		} else {
+			dec = flate.NewReader(compressed)
+		}
+
+		if err != nil {
+			ctx.StatusCode(http.StatusBadRequest)
+			ctx.StopExecution()
+			return
+		}
+
+		r.Body = &decompressReader{
+			ctx:        ctx,
+			dec:        dec,
+			body:       r.Body,
+			compressed: compressed,
+			opts:       options,
+		}
+		r.Header.Del(ContentEncodingHeaderKey)
+		r.Header.Del(ContentLengthHeaderKey)
+		r.ContentLength = -1
+
+		ctx.Next()
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type decompressReader struct {
+	ctx        Context
+	dec        io.ReadCloser
+	body       io.Closer
+	compressed *countingReader
+	n          int64
+	opts       DecompressOptions
+	err        error
+}
+
+func (d *decompressReader) Read(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	n, err := d.dec.Read(p)
+	d.n += int64(n)
+
+	if max := d.opts.MaxSize; max > 0 && d.n > max {
+		return 0, d.fail("size")
+	}
+
+	if max := d.opts.MaxRatio; max > 0 && d.n > ratioThreshold && d.compressed.n > 0 &&
+		float64(d.n)/float64(d.compressed.n) > max {
+		return 0, d.fail("ratio")
+	}
+
+	return n, err
+}
+
+func (d *decompressReader) fail(limit string) error {
+	d.err = ErrDecompressionLimit.Format(limit)
+	d.ctx.StatusCode(http.StatusRequestEntityTooLarge)
+	if d.opts.OnLimit != nil {
+		d.opts.OnLimit(d.ctx, d.err)
+	}
+	return d.err
+}
+
+func (d *decompressReader) Close() error {
+	d.dec.Close()
+	return d.body.Close()
+}
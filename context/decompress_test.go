@@ -0,0 +1,94 @@
+package context_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/iris-contrib/httpexpect"
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+func testGzip(b []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+func testDeflate(b []byte) []byte {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecompressBody(t *testing.T) {
+	var limited []error
+
+	echo := func(ctx context.Context) {
+		b, err := ioutil.ReadAll(ctx.Request().Body)
+		if err != nil {
+			if ctx.GetStatusCode() == http.StatusOK {
+				ctx.StatusCode(http.StatusBadRequest)
+			}
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.Write(b)
+	}
+
+	app := iris.New()
+	app.Post("/", context.DecompressBody(), echo)
+	app.Post("/limits", context.DecompressBody(context.DecompressOptions{
+		MaxSize:  1 << 20,
+		MaxRatio: 10,
+		OnLimit: func(ctx context.Context, err error) {
+			limited = append(limited, err)
+		},
+	}), echo)
+
+	e := httptest.New(t, app)
+	post := func(path, encoding string, body []byte) *httpexpect.Response {
+		return e.POST(path).WithHeader("Content-Encoding", encoding).WithBytes(body).Expect()
+	}
+
+	post("/", "gzip", testGzip([]byte("gzip body"))).Status(http.StatusOK).Body().Equal("gzip body")
+	post("/", "deflate", testDeflate([]byte("deflate body"))).Status(http.StatusOK).Body().Equal("deflate body")
+	// the not compressed bodies are passed as they are.
+	post("/", "", []byte("plain body")).Status(http.StatusOK).Body().Equal("plain body")
+	post("/", "br", []byte("unknown encoding")).Status(http.StatusOK).Body().Equal("unknown encoding")
+
+	// corrupt streams.
+	post("/", "gzip", []byte("not a gzip header")).Status(http.StatusBadRequest)
+	corrupt := testGzip([]byte("gzip body"))
+	corrupt = append(corrupt[:10:10], []byte("garbage garbage garbage")...)
+	post("/", "gzip", corrupt).Status(http.StatusBadRequest).Body().NotEmpty()
+
+	// a body of random data, its ratio is low, which is larger than the size limit.
+	large := make([]byte, 1<<20+1)
+	rand.New(rand.NewSource(1)).Read(large)
+	post("/limits", "gzip", testGzip(large)).Status(http.StatusRequestEntityTooLarge).
+		Body().Equal("request body: decompression limit exceeded: size")
+
+	// a tiny payload which expands too much, a zip bomb.
+	bomb := testDeflate(make([]byte, 512<<10))
+	post("/limits", "deflate", bomb).Status(http.StatusRequestEntityTooLarge).
+		Body().Equal("request body: decompression limit exceeded: ratio")
+
+	if len(limited) != 2 || limited[0].Error() != "request body: decompression limit exceeded: size" ||
+		limited[1].Error() != "request body: decompression limit exceeded: ratio" {
+		t.Fatalf("expected the OnLimit to be called for the size and the ratio but got: %v", limited)
+	}
+
+	// the limits allow the bodies under the thresholds.
+	post("/limits", "gzip", testGzip(large[:1<<20])).Status(http.StatusOK).Body().Length().Equal(1 << 20)
+}
@@ -0,0 +1,93 @@
+package context
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// ErrDigestMismatch is returned by the upload helpers when
+// the "Content-MD5" or "Digest" request header does not match the received body.
+var ErrDigestMismatch = errors.New("digest mismatch: expected %s but got %s")
+
+const (
+	contentMD5HeaderKey = "Content-MD5"
+	digestHeaderKey     = "Digest"
+)
+
+// digestAlgorithms are the supported RFC 3230 algorithms, with preference order.
+var digestAlgorithms = []struct {
+	name string
+	new  func() hash.Hash
+}{
+	{"SHA-512", sha512.New},
+	{"SHA-256", sha256.New},
+	{"SHA", sha1.New},
+	{"MD5", md5.New},
+}
+
+// digestReader hashes the request body while it is being read,
+// the body is never buffered just for the sake of the verification.
+type digestReader struct {
+	io.ReadCloser
+	algorithm string
+	expected  string
+	h         hash.Hash
+}
+
+// newDigestReader returns a digestReader for the strongest supported algorithm
+// of the request's "Digest" header or for its "Content-MD5" one.
+// It returns nil if the client did not send any of these.
+func newDigestReader(r *http.Request) *digestReader {
+	if digest := r.Header.Get(digestHeaderKey); digest != "" {
+		// Digest: SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=,MD5=...
+		values := make(map[string]string)
+		for _, entry := range strings.Split(digest, ",") {
+			if idx := strings.IndexByte(entry, '='); idx > 0 {
+				values[strings.ToUpper(strings.TrimSpace(entry[:idx]))] = strings.TrimSpace(entry[idx+1:])
+			}
+		}
+
+		for _, alg := range digestAlgorithms {
+			if expected, ok := values[alg.name]; ok {
+				return &digestReader{ReadCloser: r.Body, algorithm: alg.name, expected: expected, h: alg.new()}
+			}
+		}
+	}
+
+	if expected := r.Header.Get(contentMD5HeaderKey); expected != "" {
+		return &digestReader{ReadCloser: r.Body, algorithm: "MD5", expected: expected, h: md5.New()}
+	}
+
+	return nil
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.ReadCloser.Read(p)
+	d.h.Write(p[:n])
+	return n, err
+}
+
+// verify consumes the rest of the body, if any, and compares the computed digest with the expected one.
+func (d *digestReader) verify() (digest string, err error) {
+	if _, err = io.Copy(ioutil.Discard, d); err != nil {
+		return "", err
+	}
+
+	got := base64.StdEncoding.EncodeToString(d.h.Sum(nil))
+	digest = d.algorithm + "=" + got
+	if got != d.expected {
+		return digest, ErrDigestMismatch.Format(d.expected, got)
+	}
+
+	return digest, nil
+}
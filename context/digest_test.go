@@ -0,0 +1,80 @@
+package context
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+func testDigest(sum []byte) string {
+	return base64.StdEncoding.EncodeToString(sum)
+}
+
+func TestDigestReader(t *testing.T) {
+	const body = "the request body"
+	sha := sha256.Sum256([]byte(body))
+	md := md5.Sum([]byte(body))
+
+	tests := []struct {
+		name     string
+		header   map[string]string
+		expected string // the verified digest, empty for no verification at all.
+		mismatch bool
+	}{
+		{"sha-256", map[string]string{"Digest": "SHA-256=" + testDigest(sha[:])}, "SHA-256=" + testDigest(sha[:]), false},
+		{"strongest", map[string]string{"Digest": "md5=" + testDigest(md[:]) + ", sha-256=" + testDigest(sha[:])}, "SHA-256=" + testDigest(sha[:]), false},
+		{"content-md5", map[string]string{"Content-MD5": testDigest(md[:])}, "MD5=" + testDigest(md[:]), false},
+		{"mismatch", map[string]string{"Digest": "SHA-256=" + testDigest(md[:])}, "SHA-256=" + testDigest(sha[:]), true},
+		{"content-md5 mismatch", map[string]string{"Content-MD5": "not-base64"}, "MD5=" + testDigest(md[:]), true},
+		// the values without an algorithm are skipped, the "Content-MD5" is used instead.
+		{"bad header", map[string]string{"Digest": "garbage, =value", "Content-MD5": testDigest(md[:])}, "MD5=" + testDigest(md[:]), false},
+		{"unknown algorithm", map[string]string{"Digest": "UNIXsum=30637"}, "", false},
+		{"no header", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		for k, v := range tt.header {
+			r.Header.Set(k, v)
+		}
+
+		dr := newDigestReader(r)
+		if tt.expected == "" {
+			if dr != nil {
+				t.Fatalf("[%s] expected no verification but got one for the %s algorithm", tt.name, dr.algorithm)
+			}
+			continue
+		}
+		if dr == nil {
+			t.Fatalf("[%s] expected a verification but got none", tt.name)
+		}
+
+		// a part of the body is read by the consumer, the rest by the verification.
+		if _, err := dr.Read(make([]byte, 4)); err != nil {
+			t.Fatalf("[%s] %v", tt.name, err)
+		}
+
+		digest, err := dr.verify()
+		if digest != tt.expected {
+			t.Fatalf("[%s] expected digest '%s' but got '%s'", tt.name, tt.expected, digest)
+		}
+
+		if tt.mismatch {
+			if e, ok := err.(errors.Error); !ok || !e.Equal(ErrDigestMismatch) {
+				t.Fatalf("[%s] expected a digest mismatch error but got: %v", tt.name, err)
+			}
+		} else if err != nil {
+			t.Fatalf("[%s] expected no error but got: %v", tt.name, err)
+		}
+
+		if rest, _ := ioutil.ReadAll(dr); len(rest) > 0 {
+			t.Fatalf("[%s] expected the body to be consumed but got: '%s'", tt.name, rest)
+		}
+	}
+}
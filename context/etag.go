@@ -0,0 +1,78 @@
+package context
+
+import (
+	"hash/fnv"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// IfNoneMatchHeaderKey is the header key of "If-None-Match".
+const IfNoneMatchHeaderKey = "If-None-Match"
+
+// ComputeETag returns a strong entity tag of the "body",
+// it's based on its length and its FNV-1a hash, i.e `"1f-9a3c08d1ee6b2f55"`.
+func ComputeETag(body []byte) string {
+	h := fnv.New64a()
+	h.Write(body)
+	return `"` + strconv.FormatInt(int64(len(body)), 16) + "-" + strconv.FormatUint(h.Sum64(), 16) + `"`
+}
+
+// etagMatches reports whether the "ifNoneMatch" request header value
+// matches the "etag" by the weak comparison of the RFC 7232.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = textproto.TrimString(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ETag is a middleware which computes the ETag of the successful GET and HEAD responses
+// of the next handlers and responds with a 304 Not Modified, without a body,
+// when it matches the request's "If-None-Match" header.
+// The responses are recorded in order to hash their body, see `Context#Record`,
+// the responses that set their own "ETag" are sent as they are.
+// The hashed body is the final one, after the `ResponseRecorder#OnFlush` hooks
+// which are registered before the end of the next handlers.
+//
+// Usage: app.Get("/users", context.ETag, listUsers)
+func ETag(ctx Context) {
+	if method := ctx.Method(); method != http.MethodGet && method != http.MethodHead {
+		ctx.Next()
+		return
+	}
+
+	ctx.Record()
+	ctx.Next()
+
+	w, ok := ctx.IsRecording()
+	if !ok {
+		return
+	}
+
+	// registered after the next handlers, so it runs after their hooks.
+	w.OnFlush(func(body []byte) []byte {
+		if w.StatusCode() != http.StatusOK || w.Header().Get(ETagHeaderKey) != "" {
+			return body
+		}
+
+		etag := ComputeETag(body)
+		w.Header().Set(ETagHeaderKey, etag)
+		if etagMatches(ctx.GetHeader(IfNoneMatchHeaderKey), etag) {
+			ctx.WriteNotModified()
+			return nil
+		}
+
+		return body
+	})
+}
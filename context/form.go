@@ -0,0 +1,412 @@
+package context
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormError is the failure to decode a single form field,
+// see `Context#ReadForm`.
+type FormError struct {
+	// Field is the form key, i.e "items[0].name".
+	Field string
+	// Value is the form value which could not be decoded, if any.
+	Value string
+	// Err is the reason of the failure.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FormError) Error() string {
+	return fmt.Sprintf("form: %s: %v", e.Field, e.Err)
+}
+
+// FormErrors is the error of the `Context#ReadForm`,
+// it contains all the fields that could not be decoded.
+type FormErrors []*FormError
+
+// Error implements the error interface.
+func (e FormErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// formTagName is the struct field tag which overrides the field's form key.
+const formTagName = "form"
+
+// maxFormSliceIndex protects the server from allocating huge slices,
+// i.e "items[100000000].name".
+const maxFormSliceIndex = 10000
+
+var formTimeFormats = []string{
+	"2006-01-02",
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	time.RFC822,
+	time.RFC822Z,
+	time.RFC850,
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC3339,
+	time.RFC3339Nano,
+	time.Kitchen,
+	time.Stamp,
+	time.StampMilli,
+	time.StampMicro,
+	time.StampNano,
+}
+
+var (
+	textUnmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeTyp            = reflect.TypeOf(time.Time{})
+)
+
+// decodeForm binds the form "values" to the "ptr".
+//
+// The keys are the field names or their "form" tags, a "-" tag skips the field, and they can describe nested values:
+// "address.city" or "address[city]" for structs,
+// "items[0].name" for slices and arrays, "tags[]" or a repeated "tags" key to append to a slice
+// and "meta[color]" for maps.
+// The embedded structs' fields are promoted
+// and the `encoding.TextUnmarshaler` types decode their own values.
+func decodeForm(values url.Values, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("form: expected a non-nil pointer but got %T", ptr)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs FormErrors
+	for _, key := range keys {
+		vals := values[key]
+		if len(vals) == 0 || vals[0] == "" {
+			continue
+		}
+
+		path, err := parseFormKey(key)
+		if err == nil {
+			err = decodeFormValue(rv.Elem(), path, vals)
+		}
+
+		if err != nil {
+			errs = append(errs, &FormError{Field: key, Value: vals[0], Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// parseFormKey splits a form key to its path segments,
+// i.e "items[0].name" to "items", "0", "name" and "tags[]" to "tags", "".
+func parseFormKey(key string) ([]string, error) {
+	var (
+		path []string
+		seg  bytes.Buffer
+	)
+
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; c {
+		case '.':
+			if seg.Len() > 0 {
+				path = append(path, seg.String())
+				seg.Reset()
+			}
+		case '[':
+			if seg.Len() > 0 {
+				path = append(path, seg.String())
+				seg.Reset()
+			}
+
+			end := strings.IndexByte(key[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("missing closing bracket")
+			}
+			path = append(path, key[i+1:i+end])
+			i += end
+		default:
+			seg.WriteByte(c)
+		}
+	}
+
+	if seg.Len() > 0 {
+		path = append(path, seg.String())
+	}
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+
+	return path, nil
+}
+
+func decodeFormValue(rv reflect.Value, path []string, vals []string) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeFormValue(rv.Elem(), path, vals)
+	}
+
+	// the time.Time is a TextUnmarshaler too but it's decoded by the most common time formats.
+	if len(path) == 0 && rv.Type() != timeTyp && rv.CanAddr() && rv.Addr().Type().Implements(textUnmarshalerTyp) {
+		return rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(vals[0]))
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		if len(path) == 0 {
+			if rv.Type() == timeTyp {
+				return setFormScalar(rv, vals[0])
+			}
+			return fmt.Errorf("expected a field of %s", rv.Type())
+		}
+
+		field, ok := findFormField(rv, path[0])
+		if !ok {
+			return fmt.Errorf("unknown field %q", path[0])
+		}
+		return decodeFormValue(field, path[1:], vals)
+	case reflect.Slice:
+		if len(path) == 0 {
+			// tags=a&tags=b
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				rv.SetBytes([]byte(vals[0]))
+				return nil
+			}
+			return appendFormValues(rv, nil, vals)
+		}
+
+		if path[0] == "" {
+			// tags[]=a&tags[]=b
+			return appendFormValues(rv, path[1:], vals)
+		}
+
+		idx, err := parseFormIndex(path[0])
+		if err != nil {
+			return err
+		}
+
+		if idx >= rv.Len() {
+			grown := reflect.MakeSlice(rv.Type(), idx+1, idx+1)
+			reflect.Copy(grown, rv)
+			rv.Set(grown)
+		}
+		return decodeFormValue(rv.Index(idx), path[1:], vals)
+	case reflect.Array:
+		if len(path) == 0 {
+			for i := 0; i < rv.Len() && i < len(vals); i++ {
+				if err := decodeFormValue(rv.Index(i), nil, vals[i:i+1]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		idx, err := parseFormIndex(path[0])
+		if err != nil {
+			return err
+		}
+		if idx >= rv.Len() {
+			return fmt.Errorf("index %d out of range of %s", idx, rv.Type())
+		}
+		return decodeFormValue(rv.Index(idx), path[1:], vals)
+	case reflect.Map:
+		if len(path) == 0 {
+			return fmt.Errorf("expected a key of %s", rv.Type())
+		}
+
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+
+		key := reflect.New(rv.Type().Key()).Elem()
+		if err := decodeFormValue(key, nil, []string{path[0]}); err != nil {
+			return err
+		}
+
+		// map elements are not addressable, decode to a copy and set it back.
+		elem := reflect.New(rv.Type().Elem()).Elem()
+		if existing := rv.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := decodeFormValue(elem, path[1:], vals); err != nil {
+			return err
+		}
+		rv.SetMapIndex(key, elem)
+		return nil
+	}
+
+	if len(path) > 0 {
+		return fmt.Errorf("unexpected %q, %s has no fields", path[0], rv.Type())
+	}
+
+	return setFormScalar(rv, vals[0])
+}
+
+func parseFormIndex(s string) (int, error) {
+	idx, err := strconv.Atoi(s)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("invalid index %q", s)
+	}
+
+	if idx > maxFormSliceIndex {
+		return 0, fmt.Errorf("index %d exceeds the limit of %d", idx, maxFormSliceIndex)
+	}
+
+	return idx, nil
+}
+
+func appendFormValues(rv reflect.Value, path []string, vals []string) error {
+	for _, v := range vals {
+		elem := reflect.New(rv.Type().Elem()).Elem()
+		if err := decodeFormValue(elem, path, []string{v}); err != nil {
+			return err
+		}
+		rv.Set(reflect.Append(rv, elem))
+	}
+
+	return nil
+}
+
+// findFormField returns the field of the "name" form key,
+// the fields of the struct take precedence over the embedded structs' ones.
+func findFormField(rv reflect.Value, name string) (reflect.Value, bool) {
+	typ := rv.Type()
+	var embedded []int
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get(formTagName)
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && tag == "" {
+			embedded = append(embedded, i)
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported.
+			continue
+		}
+
+		// the field's name matches even if it has a tag, like the previous decoder.
+		if f.Name == name || (tag != "" && tag == name) {
+			return rv.Field(i), true
+		}
+	}
+
+	for _, i := range embedded {
+		field := rv.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.Type().Elem().Kind() != reflect.Struct || !field.CanSet() {
+				continue
+			}
+
+			if field.IsNil() {
+				// allocate it only if the field is there.
+				if _, ok := findFormField(reflect.New(field.Type().Elem()).Elem(), name); !ok {
+					continue
+				}
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+
+		if field.Kind() != reflect.Struct {
+			continue
+		}
+
+		if f, ok := findFormField(field, name); ok {
+			return f, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func setFormScalar(rv reflect.Value, value string) error {
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(value)
+	case reflect.Bool:
+		if value == "on" {
+			rv.SetBool(true)
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected a boolean")
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("expected a duration")
+			}
+			rv.SetInt(int64(d))
+			return nil
+		}
+
+		n, err := strconv.ParseInt(value, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an integer of %d bits", rv.Type().Bits())
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an unsigned integer of %d bits", rv.Type().Bits())
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+		rv.SetFloat(n)
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return fmt.Errorf("unsupported type %s", rv.Type())
+		}
+		rv.Set(reflect.ValueOf(value))
+	case reflect.Struct:
+		if rv.Type() != timeTyp {
+			return fmt.Errorf("unsupported type %s", rv.Type())
+		}
+
+		for _, layout := range formTimeFormats {
+			if t, err := time.Parse(layout, value); err == nil {
+				rv.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a time")
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package context
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testFormAddress struct {
+	City string `form:"city"`
+	Zip  int
+}
+
+type testFormBase struct {
+	ID int64 `form:"id"`
+}
+
+type testFormItem struct {
+	Name string `form:"name"`
+	Qty  uint8  `form:"qty"`
+}
+
+type testForm struct {
+	testFormBase
+	Name     string            `form:"name"`
+	Age      *int              `form:"age"`
+	Active   bool              `form:"active"`
+	Tags     []string          `form:"tags"`
+	Items    []testFormItem    `form:"items"`
+	Address  testFormAddress   `form:"address"`
+	Manager  *testFormAddress  `form:"manager"`
+	Meta     map[string]string `form:"meta"`
+	Born     time.Time         `form:"born"`
+	Timeout  time.Duration     `form:"timeout"`
+	IP       net.IP            `form:"ip"`
+	Internal string            `form:"-"`
+}
+
+func TestDecodeForm(t *testing.T) {
+	values := url.Values{
+		"id":               {"42"},
+		"name":             {"makis"},
+		"age":              {"30"},
+		"active":           {"on"},
+		"tags":             {"a", "b"},
+		"tags[]":           {"c"},
+		"items[1].name":    {"second"},
+		"items[0][name]":   {"first"},
+		"items[0].qty":     {"2"},
+		"address.city":     {"Athens"},
+		"address.Zip":      {"10431"},
+		"manager[city]":    {"Thessaloniki"},
+		"meta[color]":      {"red"},
+		"born":             {"2018-03-01"},
+		"timeout":          {"1m"},
+		"ip":               {"10.0.0.1"},
+		"empty_is_skipped": {""},
+	}
+
+	var got testForm
+	if err := decodeForm(values, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	age := 30
+	expected := testForm{
+		testFormBase: testFormBase{ID: 42},
+		Name:         "makis",
+		Age:          &age,
+		Active:       true,
+		Tags:         []string{"a", "b", "c"},
+		Items:        []testFormItem{{Name: "first", Qty: 2}, {Name: "second"}},
+		Address:      testFormAddress{City: "Athens", Zip: 10431},
+		Manager:      &testFormAddress{City: "Thessaloniki"},
+		Meta:         map[string]string{"color": "red"},
+		Born:         time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+		Timeout:      time.Minute,
+		IP:           net.ParseIP("10.0.0.1"),
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected:\n%#v\nbut got:\n%#v", expected, got)
+	}
+}
+
+func TestDecodeFormFieldNames(t *testing.T) {
+	// the fields with a tag match their names too.
+	var got testForm
+	if err := decodeForm(url.Values{"Name": {"makis"}, "Address.city": {"Athens"}}, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Name != "makis" || got.Address.City != "Athens" {
+		t.Fatalf("expected the fields to be matched by their names but got: %#v", got)
+	}
+
+	// the skipped fields are unknown.
+	if err := decodeForm(url.Values{"-": {"value"}, "Internal": {"value"}}, &got); err == nil || got.Internal != "" {
+		t.Fatalf("expected the skipped field to not be decoded")
+	}
+}
+
+func TestDecodeFormErrors(t *testing.T) {
+	values := url.Values{
+		"unknown":            {"value"},
+		"age":                {"thirty"},
+		"items[100001].name": {"huge"},
+		"items[x].name":      {"invalid"},
+		"address[city":       {"unclosed"},
+		"name.first":         {"not a struct"},
+		"items[0].qty":       {"256"},
+	}
+
+	var got testForm
+	err := decodeForm(values, &got)
+	errs, ok := err.(FormErrors)
+	if !ok {
+		t.Fatalf("expected FormErrors but got: %#v", err)
+	}
+
+	if len(errs) != len(values) {
+		t.Fatalf("expected %d errors but got: %v", len(values), errs)
+	}
+
+	for _, e := range errs {
+		if _, ok := values[e.Field]; !ok {
+			t.Fatalf("expected the error of a form key but got: %v", e)
+		}
+	}
+
+	if err = decodeForm(values, got); err == nil {
+		t.Fatalf("expected an error for a non-pointer value")
+	}
+}
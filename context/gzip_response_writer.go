@@ -46,20 +46,6 @@ func releaseGzipWriter(gzipWriter *gzip.Writer) {
 	gzipPool.Put(gzipWriter)
 }
 
-// writeGzip writes a compressed form of p to the underlying io.Writer. The
-// compressed bytes are not necessarily flushed until the Writer is closed.
-func writeGzip(w io.Writer, b []byte) (int, error) {
-	gzipWriter := acquireGzipWriter(w)
-	n, err := gzipWriter.Write(b)
-	if err != nil {
-		releaseGzipWriter(gzipWriter)
-		return -1, err
-	}
-	err = gzipWriter.Flush()
-	releaseGzipWriter(gzipWriter)
-	return n, err
-}
-
 var gzpool = sync.Pool{New: func() interface{} { return &GzipResponseWriter{} }}
 
 // AcquireGzipResponseWriter returns a new *GzipResponseWriter from the pool.
@@ -74,6 +60,7 @@ func releaseGzipResponseWriter(w *GzipResponseWriter) {
 }
 
 // GzipResponseWriter is an upgraded response writer which writes compressed data to the underline ResponseWriter.
+// The data are compressed by gzip or by the encoding which was negotiated by the `Context#Compress`.
 //
 // It's a separate response writer because iris gives you the ability to "fallback" and "roll-back" the gzip encoding if something
 // went wrong with the response, and write http errors in plain form instead.
@@ -81,6 +68,7 @@ type GzipResponseWriter struct {
 	ResponseWriter
 	chunks   []byte
 	disabled bool
+	encoding string
 }
 
 var _ ResponseWriter = (*GzipResponseWriter)(nil)
@@ -94,6 +82,7 @@ func (w *GzipResponseWriter) BeginGzipResponse(underline ResponseWriter) {
 
 	w.chunks = w.chunks[0:0]
 	w.disabled = false
+	w.encoding = GzipHeaderValue
 }
 
 // EndResponse called right before the contents of this
@@ -169,12 +158,23 @@ func (w *GzipResponseWriter) WriteNow(contents []byte) (int, error) {
 		return w.ResponseWriter.Write(contents)
 	}
 
-	AddGzipHeaders(w.ResponseWriter)
+	c, ok := compressors[w.encoding]
+	if !ok {
+		return w.ResponseWriter.Write(contents)
+	}
+
+	w.ResponseWriter.Header().Add(VaryHeaderKey, AcceptEncodingHeaderKey)
+	w.ResponseWriter.Header().Add(ContentEncodingHeaderKey, w.encoding)
 	// if not `WriteNow` but "Content-Length" header
 	// is exists, then delete it before `.Write`
 	// Content-Length should not be there.
 	// no, for now at least: w.ResponseWriter.Header().Del(contentLengthHeaderKey)
-	return writeGzip(w.ResponseWriter, contents)
+	return c.write(w.ResponseWriter, contents)
+}
+
+// Encoding returns the "Content-Encoding" of the compressed data, i.e "gzip" or "br".
+func (w *GzipResponseWriter) Encoding() string {
+	return w.encoding
 }
 
 // AddGzipHeaders just adds the headers "Vary" to "Accept-Encoding"
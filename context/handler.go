@@ -34,3 +34,10 @@ func HandlerName(h Handler) string {
 	// return fmt.Sprintf("%s:%d", l, n)
 	return runtime.FuncForPC(pc).Name()
 }
+
+// HandlerFileLine returns the source file and the line number
+// of the function that defines the handler "h".
+func HandlerFileLine(h Handler) (file string, line int) {
+	pc := reflect.ValueOf(h).Pointer()
+	return runtime.FuncForPC(pc).FileLine(pc)
+}
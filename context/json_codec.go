@@ -0,0 +1,117 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/kataras/iris/core/errors"
+
+	"github.com/json-iterator/go"
+)
+
+// JSONCodec contains the JSON functions of the `Context#JSON`, `Context#JSONP`, `Context#ReadJSON`,
+// `Context#Bind`, the JSON streams and, so, of the mvc and hero results, see `iris#WithJSONCodec`.
+//
+// Iris does not depend on third-party JSON implementations other than the jsoniter,
+// a faster one can be set on the application's main function, i.e:
+//
+// app.Configure(iris.WithJSONCodec(context.JSONCodec{
+// 	Marshal:       sonic.Marshal,
+// 	MarshalIndent: sonic.MarshalIndent,
+// 	Unmarshal:     sonic.Unmarshal,
+// }))
+//
+// app.Configure(iris.WithJSONCodec(context.JSONCodec{
+// 	Marshal:   gojson.Marshal,
+// 	Unmarshal: gojson.Unmarshal,
+// }))
+type JSONCodec struct {
+	// Marshal returns the JSON encoding of "v", it's required.
+	Marshal func(v interface{}) ([]byte, error)
+	// MarshalIndent is like `Marshal` but it indents the output.
+	// If it's nil then the output of the `Marshal` is indented by the "encoding/json".
+	MarshalIndent func(v interface{}, prefix, indent string) ([]byte, error)
+	// Unmarshal parses the JSON "data" and stores the result in the value pointed to by "v", it's required.
+	Unmarshal func(data []byte, v interface{}) error
+}
+
+// ErrInvalidJSONCodec is returned by the `JSONCodec#Validate`
+// when a required function of the codec is missing.
+var ErrInvalidJSONCodec = errors.New("json codec: the %s function is required")
+
+// Validate returns an `ErrInvalidJSONCodec` error if the `Marshal` or the `Unmarshal` is nil.
+func (c JSONCodec) Validate() error {
+	if c.Marshal == nil {
+		return ErrInvalidJSONCodec.Format("Marshal")
+	}
+
+	if c.Unmarshal == nil {
+		return ErrInvalidJSONCodec.Format("Unmarshal")
+	}
+
+	return nil
+}
+
+var (
+	stdJSONCodec = JSONCodec{
+		Marshal:       json.Marshal,
+		MarshalIndent: json.MarshalIndent,
+		Unmarshal:     json.Unmarshal,
+	}
+
+	optimizedJSONCodec = JSONCodec{
+		Marshal:       jsoniter.ConfigCompatibleWithStandardLibrary.Marshal,
+		MarshalIndent: jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent,
+		Unmarshal:     jsoniter.Unmarshal,
+	}
+)
+
+func (c JSONCodec) marshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	if c.MarshalIndent != nil {
+		return c.MarshalIndent(v, prefix, indent)
+	}
+
+	b, err := c.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, b, prefix, indent); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// customJSONCodec returns the `Configuration#JSONCodec`, if it's a valid one.
+func (ctx *context) customJSONCodec() (JSONCodec, bool) {
+	if c := ctx.Application().ConfigurationReadOnly().GetJSONCodec(); c != nil && c.Validate() == nil {
+		return *c, true
+	}
+
+	return JSONCodec{}, false
+}
+
+// jsonCodec returns the `Configuration#JSONCodec`, if any,
+// otherwise the jsoniter's when the optimizations are enabled or the "encoding/json"'s.
+func (ctx *context) jsonCodec() JSONCodec {
+	if c, ok := ctx.customJSONCodec(); ok {
+		return c
+	}
+
+	if ctx.shouldOptimize() {
+		return optimizedJSONCodec
+	}
+
+	return stdJSONCodec
+}
+
+// defaultJSONCodec returns the codec of the `WriteJSON` and `WriteJSONP`.
+func defaultJSONCodec(enableOptimization []bool) JSONCodec {
+	if len(enableOptimization) > 0 && enableOptimization[0] {
+		return optimizedJSONCodec
+	}
+
+	return stdJSONCodec
+}
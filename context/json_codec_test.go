@@ -0,0 +1,141 @@
+package context_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/httptest"
+)
+
+// countingJSONCodec returns a JSON codec of the "encoding/json"
+// which counts its calls.
+func countingJSONCodec(marshals, unmarshals *int32) context.JSONCodec {
+	return context.JSONCodec{
+		Marshal: func(v interface{}) ([]byte, error) {
+			atomic.AddInt32(marshals, 1)
+			return json.Marshal(v)
+		},
+		Unmarshal: func(data []byte, v interface{}) error {
+			atomic.AddInt32(unmarshals, 1)
+			return json.Unmarshal(data, v)
+		},
+	}
+}
+
+func TestJSONCodec(t *testing.T) {
+	var marshals, unmarshals int32
+
+	app := iris.New()
+	app.Configure(iris.WithJSONCodec(countingJSONCodec(&marshals, &unmarshals)), iris.WithOptimizations)
+
+	app.Get("/json", func(ctx context.Context) {
+		ctx.JSON(testStreamItem{ID: 1, Name: "a"})
+	})
+	app.Get("/json/streaming", func(ctx context.Context) {
+		ctx.JSON(testStreamItem{ID: 1, Name: "a"}, context.JSON{StreamingJSON: true, Indent: "  "})
+	})
+	app.Post("/read", func(ctx context.Context) {
+		var item testStreamItem
+		if err := ctx.ReadJSON(&item); err != nil {
+			ctx.StatusCode(http.StatusBadRequest)
+			return
+		}
+		ctx.WriteString(item.Name)
+	})
+	app.Post("/bind", func(ctx context.Context) {
+		var item testStreamItem
+		if err := ctx.Bind(&item); err != nil {
+			ctx.StatusCode(http.StatusBadRequest)
+			return
+		}
+		ctx.WriteString(item.Name)
+	})
+	app.Post("/read/stream", func(ctx context.Context) {
+		var names string
+		ctx.ReadJSONStream(func(item *testStreamItem) error {
+			names += item.Name
+			return nil
+		})
+		ctx.WriteString(names)
+	})
+	stream := func(write func(ctx context.Context, items <-chan interface{})) context.Handler {
+		return func(ctx context.Context) {
+			items := make(chan interface{}, 2)
+			items <- testStreamItem{ID: 1, Name: "a"}
+			items <- testStreamItem{ID: 2, Name: "b"}
+			close(items)
+			write(ctx, items)
+		}
+	}
+	app.Get("/stream", stream(func(ctx context.Context, items <-chan interface{}) { ctx.JSONStream(items) }))
+	app.Get("/ndjson", stream(func(ctx context.Context, items <-chan interface{}) { ctx.NDJSON(items) }))
+
+	e := httptest.New(t, app)
+
+	expect := func(expectedMarshals, expectedUnmarshals int32) {
+		t.Helper()
+		if got := atomic.LoadInt32(&marshals); got != expectedMarshals {
+			t.Fatalf("expected %d calls of the codec's Marshal but got %d", expectedMarshals, got)
+		}
+		if got := atomic.LoadInt32(&unmarshals); got != expectedUnmarshals {
+			t.Fatalf("expected %d calls of the codec's Unmarshal but got %d", expectedUnmarshals, got)
+		}
+	}
+
+	e.GET("/json").Expect().Status(http.StatusOK).Body().Equal(`{"id":1,"name":"a"}`)
+	expect(1, 0)
+	// the streaming encoders are not used for a custom codec, the indent is still applied.
+	e.GET("/json/streaming").Expect().Status(http.StatusOK).Body().Equal("{\n  \"id\": 1,\n  \"name\": \"a\"\n}\n")
+	expect(2, 0)
+	e.POST("/read").WithText(`{"id":1,"name":"a"}`).Expect().Status(http.StatusOK).Body().Equal("a")
+	expect(2, 1)
+	e.POST("/bind").WithHeader("Content-Type", "application/json").WithBytes([]byte(`{"id":1,"name":"a"}`)).Expect().
+		Status(http.StatusOK).Body().Equal("a")
+	expect(2, 2)
+	e.POST("/read/stream").WithText(`[{"id":1,"name":"a"},{"id":2,"name":"b"}]`).Expect().
+		Status(http.StatusOK).Body().Equal("ab")
+	expect(2, 4)
+	e.GET("/stream").Expect().Status(http.StatusOK).Body().Equal(`[{"id":1,"name":"a"},{"id":2,"name":"b"}]`)
+	expect(4, 4)
+	e.GET("/ndjson").Expect().Status(http.StatusOK).Body().Equal("{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n")
+	expect(6, 4)
+}
+
+func TestJSONCodecValidate(t *testing.T) {
+	tests := []struct {
+		codec    context.JSONCodec
+		expected string
+	}{
+		{context.JSONCodec{Unmarshal: json.Unmarshal}, "json codec: the Marshal function is required"},
+		{context.JSONCodec{Marshal: json.Marshal}, "json codec: the Unmarshal function is required"},
+		{context.JSONCodec{Marshal: json.Marshal, Unmarshal: json.Unmarshal}, ""},
+	}
+
+	for _, tt := range tests {
+		err := tt.codec.Validate()
+		if tt.expected == "" {
+			if err != nil {
+				t.Fatalf("expected a valid codec but got: %v", err)
+			}
+			continue
+		}
+
+		if e, ok := err.(errors.Error); !ok || !e.Equal(context.ErrInvalidJSONCodec) || err.Error() != tt.expected {
+			t.Fatalf("expected error '%s' but got: %v", tt.expected, err)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("expected the WithJSONCodec to panic on an invalid codec")
+				}
+			}()
+			iris.WithJSONCodec(tt.codec)
+		}()
+	}
+}
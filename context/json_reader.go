@@ -0,0 +1,200 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// JSONReader contains the options of the `Context#ReadJSON`,
+// they can be used to enforce a strict contract of the accepted JSON bodies.
+type JSONReader struct {
+	// DisallowUnknownFields rejects the objects with keys that do not match
+	// any of the exported fields of the destination struct.
+	DisallowUnknownFields bool
+	// MaxDepth rejects the bodies with more nested objects and arrays than this value.
+	// Defaults to 0, no limit.
+	MaxDepth int
+	// MaxBodySize rejects the bodies with a greater size, in bytes.
+	// Defaults to 0, no limit.
+	MaxBodySize int64
+}
+
+// JSONReadError is the error which is returned by the `Context#ReadJSON` when
+// it's called with `JSONReader` options and the body does not satisfy them
+// or it does not match the destination value.
+type JSONReadError struct {
+	// Field is the path of the offending field, i.e "address.city", it may be empty.
+	Field string
+	// Reason describes the failure, i.e "expected int but got string".
+	Reason string
+	// UnknownField reports whether the body was rejected because of the `JSONReader#DisallowUnknownFields`,
+	// the Reason contains the field's name then.
+	UnknownField bool
+	// Err is the underline error, if any.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *JSONReadError) Error() string {
+	if e.Field == "" {
+		return "read json: " + e.Reason
+	}
+
+	return fmt.Sprintf("read json: %s: %s", e.Field, e.Reason)
+}
+
+// limitedBody is the request body which is limited by the `JSONReader#MaxBodySize` or `YAMLReader#MaxBodySize`,
+// it reads one more byte than the limit so the unmarshaler can tell if it exceeded.
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
+
+func limitBody(body io.ReadCloser, maxBodySize int64) io.ReadCloser {
+	if maxBodySize <= 0 || body == nil {
+		return body
+	}
+
+	return limitedBody{Reader: io.LimitReader(body, maxBodySize+1), Closer: body}
+}
+
+// Unmarshal implements the `Unmarshaler`, it uses the "encoding/json" package
+// even if the `Configuration#EnableOptimizations` is true.
+func (opts JSONReader) Unmarshal(data []byte, outPtr interface{}) error {
+	if opts.MaxBodySize > 0 && int64(len(data)) > opts.MaxBodySize {
+		return &JSONReadError{Reason: fmt.Sprintf("body is larger than %d bytes", opts.MaxBodySize)}
+	}
+
+	if opts.MaxDepth > 0 {
+		if err := checkJSONDepth(data, opts.MaxDepth); err != nil {
+			return err
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(outPtr); err != nil {
+		readErr := newJSONReadError(err)
+		if opts.DisallowUnknownFields && readErr.Field == "" && acceptsUnknownFields(data, outPtr) {
+			readErr.UnknownField = true
+		}
+		return readErr
+	}
+
+	return nil
+}
+
+// acceptsUnknownFields reports whether the "data" can be decoded to a new value of the "outPtr"'s type
+// when the unknown fields are allowed, so a failure with the `JSONReader#DisallowUnknownFields`
+// is caused by an unknown field, the "encoding/json" does not export a type for it.
+func acceptsUnknownFields(data []byte, outPtr interface{}) bool {
+	typ := reflect.TypeOf(outPtr)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return false
+	}
+
+	return json.Unmarshal(data, reflect.New(typ.Elem()).Interface()) == nil
+}
+
+func newJSONReadError(err error) *JSONReadError {
+	switch e := err.(type) {
+	case *json.UnmarshalTypeError:
+		return &JSONReadError{
+			Field:  e.Field,
+			Reason: fmt.Sprintf("expected %s but got %s", e.Type, e.Value),
+			Err:    err,
+		}
+	case *json.SyntaxError:
+		return &JSONReadError{
+			Reason: fmt.Sprintf("%s at offset %d", e.Error(), e.Offset),
+			Err:    err,
+		}
+	}
+
+	return &JSONReadError{Reason: err.Error(), Err: err}
+}
+
+// jsonFrame is an object or an array, see `checkJSONDepth`.
+type jsonFrame struct {
+	object   bool
+	index    int
+	key      string
+	expKey   bool
+	hasValue bool
+}
+
+func (f *jsonFrame) name() string {
+	if f.object {
+		return f.key
+	}
+
+	return fmt.Sprintf("[%d]", f.index)
+}
+
+// checkJSONDepth returns an error if the "data" contains
+// more than "max" nested objects and arrays.
+func checkJSONDepth(data []byte, max int) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []*jsonFrame
+
+	path := func() string {
+		var names []string
+		for _, f := range stack {
+			if f.hasValue {
+				names = append(names, f.name())
+			}
+		}
+		return strings.Replace(strings.Join(names, "."), ".[", "[", -1)
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return newJSONReadError(err)
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		if top != nil {
+			if top.object && top.expKey {
+				top.key, _ = tok.(string)
+				top.expKey = false
+				top.hasValue = false
+				continue
+			}
+
+			if top.object {
+				top.expKey = true
+			} else if top.hasValue {
+				top.index++
+			}
+			top.hasValue = true
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			if len(stack) >= max {
+				return &JSONReadError{Field: path(), Reason: fmt.Sprintf("exceeds the max depth of %d", max)}
+			}
+
+			stack = append(stack, &jsonFrame{object: delim == '{', expKey: delim == '{'})
+		}
+	}
+}
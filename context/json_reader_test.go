@@ -0,0 +1,74 @@
+package context_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+type testJSONAddress struct {
+	City string `json:"city"`
+}
+
+type testJSONUser struct {
+	Name    string          `json:"name"`
+	Age     int             `json:"age"`
+	Address testJSONAddress `json:"address"`
+	Tags    []string        `json:"tags"`
+}
+
+func TestReadJSONOptions(t *testing.T) {
+	app := iris.New()
+	app.Post("/", func(ctx context.Context) {
+		var user testJSONUser
+		err := ctx.ReadJSON(&user, context.JSONReader{
+			DisallowUnknownFields: ctx.URLParamExists("strict"),
+			MaxDepth:              2,
+			MaxBodySize:           100,
+		})
+		if err == nil {
+			ctx.Writef("%s:%d:%s", user.Name, user.Age, user.Address.City)
+			return
+		}
+
+		readErr, ok := err.(*context.JSONReadError)
+		if !ok {
+			t.Errorf("expected a *JSONReadError but got %T: %v", err, err)
+			return
+		}
+
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.Writef("%s|%v", readErr.Field, readErr.UnknownField)
+	})
+
+	e := httptest.New(t, app)
+
+	tests := []struct {
+		strict       bool
+		body         string
+		expectedCode int
+		expected     string
+	}{
+		{false, `{"name":"iris","age":7,"address":{"city":"Athens"}}`, iris.StatusOK, "iris:7:Athens"},
+		// the unknown fields are ignored unless they are disallowed.
+		{false, `{"name":"iris","email":"iris@example.com"}`, iris.StatusOK, "iris:0:"},
+		{true, `{"name":"iris","email":"iris@example.com"}`, iris.StatusBadRequest, "|true"},
+		{true, `{"name":"iris","address":{"zip":"12345"}}`, iris.StatusBadRequest, "|true"},
+		// the other failures are not unknown fields.
+		{true, `{"name":"iris","age":"seven"}`, iris.StatusBadRequest, "age|false"},
+		{true, `{"name":"iris",}`, iris.StatusBadRequest, "|false"},
+		{false, `{"name":"iris","address":{"city":{"name":"Athens"}}}`, iris.StatusBadRequest, "address.city|false"},
+		{false, `{"name":"` + string(make([]byte, 100)) + `"}`, iris.StatusBadRequest, "|false"},
+	}
+
+	for _, tt := range tests {
+		req := e.POST("/").WithText(tt.body)
+		if tt.strict {
+			req = req.WithQuery("strict", "true")
+		}
+
+		req.Expect().Status(tt.expectedCode).Body().Equal(tt.expected)
+	}
+}
@@ -0,0 +1,238 @@
+package context_test
+
+import (
+	"bufio"
+	stdContext "context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+type testStreamItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type testStreamError struct{}
+
+func (*testStreamError) Error() string { return "custom" }
+
+func TestReadJSONStream(t *testing.T) {
+	errStop := errors.New("stop")
+
+	app := iris.New()
+	app.Post("/{handler}", func(ctx context.Context) {
+		var names []string
+
+		var onItem interface{}
+		switch ctx.Params().Get("handler") {
+		case "value":
+			onItem = func(item testStreamItem) error {
+				names = append(names, item.Name)
+				return nil
+			}
+		case "ptr":
+			onItem = func(item *testStreamItem) error {
+				names = append(names, fmt.Sprintf("%d", item.ID))
+				return nil
+			}
+		case "abort":
+			onItem = func(item testStreamItem) error {
+				names = append(names, item.Name)
+				if item.ID == 2 {
+					return errStop
+				}
+				return nil
+			}
+		}
+
+		if err := ctx.ReadJSONStream(onItem); err != nil {
+			ctx.StatusCode(http.StatusBadRequest)
+			ctx.WriteString(err.Error() + ":" + strings.Join(names, ","))
+			return
+		}
+
+		ctx.WriteString(strings.Join(names, ","))
+	})
+
+	e := httptest.New(t, app)
+	body := `[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"}]`
+
+	e.POST("/value").WithText(body).Expect().Status(http.StatusOK).Body().Equal("a,b,c")
+	e.POST("/ptr").WithText(body).Expect().Status(http.StatusOK).Body().Equal("1,2,3")
+	e.POST("/value").WithText(`[]`).Expect().Status(http.StatusOK).Body().Equal("")
+	e.POST("/abort").WithText(body).Expect().Status(http.StatusBadRequest).Body().Equal("stop:a,b")
+	e.POST("/value").WithText(`{"id":1}`).Expect().Status(http.StatusBadRequest)
+	e.POST("/value").WithText(`[{"id":1,"name":"a"},{"id":"x"}]`).Expect().Status(http.StatusBadRequest).
+		Body().Contains(":a")
+	e.POST("/value").WithText(`[{"id":1,"name":"a"}`).Expect().Status(http.StatusBadRequest)
+}
+
+func TestReadJSONStreamInvalidHandler(t *testing.T) {
+	var nilFunc func(testStreamItem) error
+
+	for _, onItem := range []interface{}{
+		nil,
+		nilFunc,
+		"not a func",
+		func() error { return nil },
+		func(testStreamItem) {},
+		func(testStreamItem) *testStreamError { return nil },
+		func(testStreamItem) (bool, error) { return false, nil },
+		func(...testStreamItem) error { return nil },
+	} {
+		app := iris.New()
+		app.Post("/", func(ctx context.Context) {
+			err := ctx.ReadJSONStream(onItem)
+			if err == nil {
+				ctx.StatusCode(http.StatusOK)
+				return
+			}
+
+			ctx.StatusCode(http.StatusBadRequest)
+			ctx.WriteString(err.Error())
+		})
+
+		httptest.New(t, app).POST("/").WithText(`[{"id":1}]`).Expect().Status(http.StatusBadRequest).
+			Body().Contains("read json stream: expected a func(item T) error")
+	}
+}
+
+func TestJSONStream(t *testing.T) {
+	stream := func(items ...interface{}) <-chan interface{} {
+		ch := make(chan interface{}, len(items))
+		for _, item := range items {
+			ch <- item
+		}
+		close(ch)
+		return ch
+	}
+
+	app := iris.New()
+	app.Get("/json/{n:int}", func(ctx context.Context) {
+		n, _ := ctx.Params().GetInt("n")
+		items := make([]interface{}, n)
+		for i := range items {
+			items[i] = testStreamItem{ID: i + 1, Name: fmt.Sprintf("item%d", i+1)}
+		}
+		ctx.JSONStream(stream(items...))
+	})
+	app.Get("/ndjson/{n:int}", func(ctx context.Context) {
+		n, _ := ctx.Params().GetInt("n")
+		items := make([]interface{}, n)
+		for i := range items {
+			items[i] = testStreamItem{ID: i + 1}
+		}
+		ctx.NDJSON(stream(items...))
+	})
+	app.Get("/error", func(ctx context.Context) {
+		_, err := ctx.JSONStream(stream(testStreamItem{ID: 1}, func() {}))
+		if err == nil {
+			t.Error("expected the error of the item which can not be marshaled")
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/json/0").Expect().Status(http.StatusOK).
+		ContentType(context.ContentJSONHeaderValue, "utf-8").Body().Equal("[]")
+	e.GET("/json/1").Expect().Status(http.StatusOK).Body().Equal(`[{"id":1,"name":"item1"}]`)
+	e.GET("/json/3").Expect().Status(http.StatusOK).JSON().Array().Length().Equal(3)
+	e.GET("/json/2").Expect().Status(http.StatusOK).
+		Body().Equal(`[{"id":1,"name":"item1"},{"id":2,"name":"item2"}]`)
+
+	e.GET("/ndjson/0").Expect().Status(http.StatusOK).
+		ContentType(context.ContentNDJSONHeaderValue, "utf-8").Body().Empty()
+	e.GET("/ndjson/2").Expect().Status(http.StatusOK).
+		Body().Equal(`{"id":1,"name":""}` + "\n" + `{"id":2,"name":""}` + "\n")
+
+	// the items which are already sent are not taken back, the stream stops at the failed one.
+	e.GET("/error").Expect().Status(http.StatusOK).Body().Equal(`[{"id":1,"name":""}`)
+}
+
+func TestJSONStreamFlush(t *testing.T) {
+	received := make(chan struct{})
+
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		items := make(chan interface{})
+		go func() {
+			defer close(items)
+			items <- testStreamItem{ID: 1}
+			// waits for the client to receive the first item before the next one.
+			select {
+			case <-received:
+				items <- testStreamItem{ID: 2}
+			case <-time.After(2 * time.Second):
+				items <- "timeout"
+			}
+		}()
+
+		ctx.NDJSON(items)
+	})
+
+	cancelled := make(chan error, 1)
+	app.Get("/cancel", func(ctx context.Context) {
+		items := make(chan interface{}, 1)
+		items <- testStreamItem{ID: 1}
+		// never closed, the stream ends when the client goes away.
+		_, err := ctx.JSONStream(items)
+		cancelled <- err
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	r := bufio.NewReader(resp.Body)
+	line, err := r.ReadString('\n')
+	if err != nil || line != `{"id":1,"name":""}`+"\n" {
+		t.Fatalf("expected the first item to be flushed before the next one but got '%s': %v", line, err)
+	}
+	received <- struct{}{}
+
+	rest, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"id":2,"name":""}` + "\n"; string(rest) != expected {
+		t.Fatalf("expected the rest of the stream to be '%s' but got '%s'", expected, rest)
+	}
+
+	resp, err = http.Get(srv.URL + "/cancel")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = bufio.NewReader(resp.Body)
+	if b, err := r.ReadBytes('}'); err != nil || string(b) != `[{"id":1,"name":""}` {
+		t.Fatalf("expected the first item to be flushed but got '%s': %v", b, err)
+	}
+	resp.Body.Close()
+
+	select {
+	case err := <-cancelled:
+		if err != stdContext.Canceled {
+			t.Fatalf("expected the stream to end with the request's context error but got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the stream to end when the client goes away")
+	}
+}
@@ -0,0 +1,11 @@
+package context
+
+// Localizer is the translations provider of the `Context#Tr`, i.e the i18n package's `I18n`,
+// see `iris#WithLocalizer`.
+type Localizer interface {
+	// GetLanguage returns the language of the request, one of the loaded ones.
+	GetLanguage(ctx Context) string
+	// Tr returns the "key" message of the "lang" language, formatted by the "args".
+	// It returns an empty string if the message is missing.
+	Tr(lang, key string, args ...interface{}) string
+}
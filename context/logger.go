@@ -0,0 +1,107 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/golog"
+)
+
+// Logger is the leveled logger of a request, see `Context#Logger`.
+// Its fields are appended to each message as "key=value" pairs.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	// With returns a new logger with the "key" field added,
+	// this logger is not modified.
+	With(key string, value interface{}) Logger
+}
+
+// LogField is a key-value pair of the `Logger`.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// NewLogger returns a new `Logger` which writes to the "logger" with the "fields",
+// it's the default logger of the `Context#Logger`.
+func NewLogger(logger *golog.Logger, fields ...LogField) Logger {
+	var b bytes.Buffer
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(formatLogValue(f.Value))
+	}
+
+	return &fieldsLogger{logger: logger, fields: fields, suffix: b.String()}
+}
+
+type fieldsLogger struct {
+	logger *golog.Logger
+	fields []LogField
+	// the formatted fields.
+	suffix string
+}
+
+var _ Logger = (*fieldsLogger)(nil)
+
+func (l *fieldsLogger) format(format string, args []interface{}) string {
+	return fmt.Sprintf(format, args...) + l.suffix
+}
+
+// formatLogValue quotes the values which contain spaces, quotes or equal signs.
+func formatLogValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+func (l *fieldsLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debug(l.format(format, args))
+}
+
+func (l *fieldsLogger) Infof(format string, args ...interface{}) {
+	l.logger.Info(l.format(format, args))
+}
+
+func (l *fieldsLogger) Warnf(format string, args ...interface{}) {
+	l.logger.Warn(l.format(format, args))
+}
+
+func (l *fieldsLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(l.format(format, args))
+}
+
+func (l *fieldsLogger) With(key string, value interface{}) Logger {
+	fields := make([]LogField, len(l.fields), len(l.fields)+1)
+	copy(fields, l.fields)
+	return NewLogger(l.logger, append(fields, LogField{key, value})...)
+}
+
+// loggerContextKey is the request's values key of the `Context#Logger`.
+const loggerContextKey = "iris.logger"
+
+// newRequestLogger returns the default logger of a request,
+// its fields are the request ID, if any, the method, the path and the route's name.
+func newRequestLogger(ctx Context) Logger {
+	fields := make([]LogField, 0, 4)
+	if id := ctx.RequestID(); id != "" {
+		fields = append(fields, LogField{"request_id", id})
+	}
+
+	fields = append(fields, LogField{"method", ctx.Method()}, LogField{"path", ctx.Path()})
+
+	if route := ctx.GetCurrentRoute(); route != nil {
+		fields = append(fields, LogField{"route", route.Name()})
+	}
+
+	return NewLogger(ctx.Application().Logger(), fields...)
+}
@@ -0,0 +1,42 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/kataras/golog"
+)
+
+func TestNewLogger(t *testing.T) {
+	l := NewLogger(golog.Default, LogField{"method", "GET"}, LogField{"user", "the kataras"},
+		LogField{"query", "a=b"}, LogField{"empty", ""}, LogField{"id", 42})
+
+	expected := ` method=GET user="the kataras" query="a=b" empty="" id=42`
+	if got := l.(*fieldsLogger).suffix; got != expected {
+		t.Fatalf("expected the fields to be formatted as '%s' but got '%s'", expected, got)
+	}
+
+	if got := l.(*fieldsLogger).format("user %d logged in", []interface{}{42}); got != "user 42 logged in"+expected {
+		t.Fatalf("expected the fields to be appended to the message but got '%s'", got)
+	}
+
+	// the With does not modify the logger.
+	with := l.With("admin", true)
+	if got := with.(*fieldsLogger).suffix; got != expected+" admin=true" {
+		t.Fatalf("expected the new field to be appended but got '%s'", got)
+	}
+	if got := l.(*fieldsLogger).suffix; got != expected {
+		t.Fatalf("expected the logger to not be modified by the With but got '%s'", got)
+	}
+
+	other := l.With("admin", false)
+	if got := with.(*fieldsLogger).suffix; got != expected+" admin=true" {
+		t.Fatalf("expected the loggers of the With to not share their fields but got '%s'", got)
+	}
+	if got := other.(*fieldsLogger).suffix; got != expected+" admin=false" {
+		t.Fatalf("expected the new field to be appended but got '%s'", got)
+	}
+
+	if got := NewLogger(golog.Default).(*fieldsLogger).suffix; got != "" {
+		t.Fatalf("expected no fields but got '%s'", got)
+	}
+}
@@ -0,0 +1,17 @@
+package context
+
+// The message IDs of the framework's client-facing messages,
+// they are passed to the `Context#Message`, so they can be localized
+// by adding them to the i18n middleware's locale files, i.e:
+//
+// [iris]
+// status_404 = Η σελίδα δεν βρέθηκε
+// validation_required = Το πεδίο %s είναι υποχρεωτικό
+const (
+	// MessageStatusText is the ID of a status code's default text, formatted with the status code,
+	// it's used by the default http error handlers and the redirection notes.
+	MessageStatusText = "iris.status_%d"
+	// MessageValidationError is the ID of a `ValidationError`, formatted with its reason, i.e "iris.validation_required",
+	// its message receives the field, if any, as argument, see `ValidationError#Message`.
+	MessageValidationError = "iris.validation_%s"
+)
@@ -0,0 +1,131 @@
+package context_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+// testTranslations is a translate function like the i18n middleware's one,
+// it returns the key itself, formatted if there are "args", when it's missing.
+var testTranslations = map[string]string{
+	"iris.status_500":          "Εσωτερικό σφάλμα",
+	"iris.validation_required": "Το πεδίο %s είναι υποχρεωτικό",
+	// a translation which starts with its own key is still a translation.
+	"iris.status_404": "iris.status_404: δεν βρέθηκε",
+}
+
+func testTranslate(format string, args ...interface{}) string {
+	if msg, ok := testTranslations[format]; ok {
+		format = msg
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(format, args...)
+	}
+	return format
+}
+
+type testUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestMessages(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithValidator(context.ValidatorFunc(func(v interface{}) error {
+		u := v.(*testUser)
+		var errs context.ValidationErrors
+		if u.Name == "" {
+			errs = append(errs, &context.ValidationError{Field: "name", Reason: "required"})
+		}
+		if u.Email == "" {
+			errs = append(errs, &context.ValidationError{Field: "email", Reason: "email"})
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+		return nil
+	})))
+
+	app.Use(func(ctx context.Context) {
+		if ctx.URLParam("lang") == "el" {
+			translateFuncKey := ctx.Application().ConfigurationReadOnly().GetTranslateFunctionContextKey()
+			ctx.Values().Set(translateFuncKey, testTranslate)
+		}
+		ctx.Next()
+	})
+
+	app.Get("/error", func(ctx context.Context) { ctx.StatusCode(iris.StatusInternalServerError) })
+	app.Get("/notfound", func(ctx context.Context) { ctx.NotFound() })
+	app.Get("/message", func(ctx context.Context) {
+		ctx.WriteString(ctx.Message("iris.status_500", "fallback %d", 42))
+	})
+	app.Post("/user", func(ctx context.Context) {
+		var u testUser
+		if err := ctx.ReadJSON(&u); err != nil {
+			if errs, ok := context.GetValidationErrors(ctx); ok {
+				ctx.WriteString(strings.Join(errs.Messages(ctx), "\n"))
+				return
+			}
+			ctx.WriteString(err.Error())
+		}
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/error").Expect().Status(iris.StatusInternalServerError).Body().Equal("Internal Server Error")
+	e.GET("/error").WithQuery("lang", "el").Expect().Status(iris.StatusInternalServerError).Body().Equal("Εσωτερικό σφάλμα")
+	e.GET("/notfound").WithQuery("lang", "el").Expect().Status(iris.StatusNotFound).Body().Equal("iris.status_404: δεν βρέθηκε")
+
+	e.GET("/message").Expect().Body().Equal("fallback 42")
+	e.GET("/message").WithQuery("lang", "el").Expect().Body().Equal("Εσωτερικό σφάλμα%!(EXTRA int=42)")
+
+	e.POST("/user").WithJSON(testUser{}).Expect().Status(iris.StatusBadRequest).
+		Body().Equal("validation: name: required\nvalidation: email: email")
+	// the missing translations fall back to the english text.
+	e.POST("/user").WithQuery("lang", "el").WithJSON(testUser{}).Expect().Status(iris.StatusBadRequest).
+		Body().Equal("Το πεδίο name είναι υποχρεωτικό\nvalidation: email: email")
+}
+
+func TestValidationErrorMessageWithoutField(t *testing.T) {
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		err := &context.ValidationError{Reason: "invalid"}
+		ctx.WriteString(err.Message(ctx))
+	})
+
+	httptest.New(t, app).GET("/").Expect().Body().Equal("validation: invalid")
+}
+
+type testLocalizer map[string]string
+
+func (l testLocalizer) GetLanguage(ctx context.Context) string { return "el-GR" }
+
+func (l testLocalizer) Tr(lang, key string, args ...interface{}) string {
+	if msg, ok := l[key]; ok {
+		return fmt.Sprintf(msg, args...)
+	}
+	return ""
+}
+
+func TestMessagesLocalizer(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithLocalizer(testLocalizer{"iris.validation_required": "Το πεδίο %s είναι υποχρεωτικό"}))
+	app.Get("/", func(ctx context.Context) {
+		errs := context.ValidationErrors{
+			{Field: "name", Reason: "required"},
+			{Field: "ratio", Reason: "max 100%"},
+		}
+		ctx.WriteString(strings.Join(errs.Messages(ctx), "\n"))
+	})
+	app.Get("/error", func(ctx context.Context) { ctx.StatusCode(iris.StatusInternalServerError) })
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Body().Equal("Το πεδίο name είναι υποχρεωτικό\nvalidation: ratio: max 100%")
+	e.GET("/error").Expect().Body().Equal("Internal Server Error")
+}
@@ -0,0 +1,38 @@
+package context
+
+import (
+	"io"
+	"mime/multipart"
+)
+
+// readFilePart stores the file "part" like the `http.Request#ParseMultipartForm` does,
+// up to "maxMemory" bytes in memory and the rest in a temporary file,
+// the caller should call the `RemoveAll` of the returned form.
+//
+// The part is re-encoded as a single-part form because the contents
+// of a `multipart.FileHeader` can only be set by the "mime/multipart" package.
+func readFilePart(part *multipart.Part, maxMemory int64) (*multipart.Form, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w, err := mw.CreatePart(part.Header)
+		if err == nil {
+			_, err = io.Copy(w, part)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	form, err := multipart.NewReader(pr, mw.Boundary()).ReadForm(maxMemory)
+	// release the writer, it may still write the closing boundary or fail,
+	// and wait for it, so it does not read the "part" after the return,
+	// its next write fails so it stops after its current read at most.
+	pr.CloseWithError(err)
+	<-done
+	return form, err
+}
@@ -0,0 +1,114 @@
+package context_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+// testFormFilesBody returns a multipart body of two fields and two files, and its content type.
+func testFormFilesBody(t *testing.T) ([]byte, string) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	w.WriteField("title", "first")
+	part, err := w.CreateFormFile("files", "small.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte("small"))
+	w.WriteField("title", "second")
+	part, err = w.CreateFormFile("files", "large.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(strings.Repeat("l", 64)))
+	w.Close()
+
+	return b.Bytes(), w.FormDataContentType()
+}
+
+func TestFormFiles(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithPostMaxMemory(32))
+
+	var tempFiles []string
+	app.Post("/", func(ctx context.Context) {
+		var visited []string
+		err := ctx.FormFiles(func(fh *multipart.FileHeader) error {
+			f, err := fh.Open()
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			b, err := ioutil.ReadAll(f)
+			if err != nil {
+				return err
+			}
+
+			// the files larger than the max memory are stored in temporary files.
+			storage := "memory"
+			if osFile, ok := f.(*os.File); ok {
+				storage = "disk"
+				tempFiles = append(tempFiles, osFile.Name())
+			}
+
+			visited = append(visited, fh.Filename+":"+storage+":"+string(b[:5]))
+			return nil
+		})
+		if err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.WriteString(err.Error())
+			return
+		}
+
+		ctx.Writef("%s|%s", strings.Join(visited, ","), strings.Join(ctx.FormValues()["title"], ","))
+	})
+
+	app.Post("/error", func(ctx context.Context) {
+		n := 0
+		err := ctx.FormFiles(func(fh *multipart.FileHeader) error {
+			n++
+			return errors.New("rejected " + fh.Filename)
+		})
+		ctx.Writef("%d %v", n, err)
+	})
+
+	e := httptest.New(t, app)
+	body, contentType := testFormFilesBody(t)
+
+	e.POST("/").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(iris.StatusOK).Body().Equal("small.txt:memory:small,large.txt:disk:lllll|first,second")
+
+	if len(tempFiles) != 1 {
+		t.Fatalf("expected one temporary file but got: %v", tempFiles)
+	}
+
+	if _, err := os.Stat(tempFiles[0]); !os.IsNotExist(err) {
+		t.Fatalf("expected the temporary file to be removed after the call")
+	}
+
+	// the error of the callback stops the iteration.
+	e.POST("/error").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(iris.StatusOK).Body().Equal("1 rejected small.txt")
+
+	// a truncated file part fails the call.
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="files"; filename="truncated.txt"`)
+	part, _ := w.CreatePart(h)
+	part.Write([]byte(strings.Repeat("t", 64)))
+
+	e.POST("/").WithHeader("Content-Type", w.FormDataContentType()).WithBytes(b.Bytes()).Expect().
+		Status(iris.StatusBadRequest)
+}
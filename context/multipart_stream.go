@@ -0,0 +1,64 @@
+package context
+
+import (
+	"io"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// MultipartLimits contains the options of the `Context#MultipartStream`.
+type MultipartLimits struct {
+	// MaxPartSize rejects the parts with a greater size, in bytes.
+	// The part's reader fails with an `ErrMultipartPartTooLarge` error,
+	// the limit may be exceeded by up to the size of the multipart reader's buffer (4KB),
+	// so the handler should not depend on it for exact quotas.
+	// Zero means no limit.
+	MaxPartSize int64
+	// MaxParts rejects the requests with more parts than this value.
+	// Zero means no limit.
+	MaxParts int
+}
+
+var (
+	// ErrMultipartPartTooLarge is returned by the `Context#MultipartStream`
+	// when a part is larger than the `MultipartLimits#MaxPartSize`.
+	ErrMultipartPartTooLarge = errors.New("multipart stream: part is larger than %d bytes")
+	// ErrMultipartTooManyParts is returned by the `Context#MultipartStream`
+	// when the request contains more parts than the `MultipartLimits#MaxParts`.
+	ErrMultipartTooManyParts = errors.New("multipart stream: request contains more than %d parts")
+)
+
+// multipartBufferSize is the size of the buffer of the "mime/multipart#Reader",
+// the bytes of a part that are read from the request body may differ by up to this value.
+const multipartBufferSize = 4096
+
+// partLimitedBody is the request body of the `Context#MultipartStream`,
+// it counts the bytes that are read since the current part started.
+type partLimitedBody struct {
+	io.ReadCloser
+	max  int64
+	read int64
+	err  error
+}
+
+// reset should be called when the next part is ready.
+func (b *partLimitedBody) reset() {
+	b.read = 0
+}
+
+func (b *partLimitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	// the buffered bytes of the next part are counted too,
+	// allow them up to the size of the buffer so only the really large parts fail.
+	if b.max > 0 && b.read > b.max+multipartBufferSize {
+		b.err = ErrMultipartPartTooLarge.Format(b.max)
+		return n, b.err
+	}
+
+	return n, err
+}
@@ -0,0 +1,98 @@
+package context_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/iris-contrib/httpexpect"
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+// testMultipartParts returns a multipart body of a field and files of the "sizes" bytes, the first one is the field.
+func testMultipartParts(t *testing.T, sizes ...int) ([]byte, string) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	for i, size := range sizes {
+		if i == 0 {
+			w.WriteField("field", strings.Repeat("f", size))
+			continue
+		}
+
+		part, err := w.CreateFormFile("file", fmt.Sprintf("%d.txt", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(bytes.Repeat([]byte{'a' + byte(i)}, size))
+	}
+	w.Close()
+
+	return b.Bytes(), w.FormDataContentType()
+}
+
+func TestMultipartStream(t *testing.T) {
+	errStop := errors.New("stop")
+
+	app := iris.New()
+	app.Post("/", func(ctx context.Context) {
+		var limits context.MultipartLimits
+		limits.MaxParts, _ = ctx.URLParamInt("parts")
+		limits.MaxPartSize, _ = ctx.URLParamInt64("size")
+		stopAt := ctx.URLParamIntDefault("stop", 0)
+
+		var visited []string
+		err := ctx.MultipartStream(func(part *multipart.Part) error {
+			b, err := ioutil.ReadAll(part)
+			if err != nil {
+				return err
+			}
+
+			visited = append(visited, fmt.Sprintf("%s:%s:%d", part.FormName(), part.FileName(), len(b)))
+			if len(visited) == stopAt {
+				return errStop
+			}
+			return nil
+		}, limits)
+
+		if err != nil {
+			if ctx.GetStatusCode() == iris.StatusOK {
+				ctx.StatusCode(iris.StatusBadRequest)
+			}
+			ctx.WriteString(err.Error() + "|")
+		}
+		ctx.WriteString(strings.Join(visited, ","))
+	})
+
+	e := httptest.New(t, app)
+	post := func(query string, sizes ...int) *httpexpect.Response {
+		body, contentType := testMultipartParts(t, sizes...)
+		return e.POST("/").WithQueryString(query).WithHeader("Content-Type", contentType).WithBytes(body).Expect()
+	}
+
+	// the parts are visited in order, the limit of their size is per part.
+	post("size=10000", 5, 8000, 8000).Status(iris.StatusOK).
+		Body().Equal("field::5,file:1.txt:8000,file:2.txt:8000")
+
+	// the error of the callback stops the processing.
+	post("stop=2", 5, 10, 10).Status(iris.StatusBadRequest).
+		Body().Equal("stop|field::5,file:1.txt:10")
+
+	post("parts=2", 5, 10, 10).Status(iris.StatusRequestEntityTooLarge).
+		Body().Equal("multipart stream: request contains more than 2 parts|field::5,file:1.txt:10")
+	post("parts=3", 5, 10, 10).Status(iris.StatusOK)
+
+	// the part's size may exceed the limit by up to the size of the reader's buffer.
+	post("size=1024", 5, 64*1024).Status(iris.StatusRequestEntityTooLarge).
+		Body().Equal("multipart stream: part is larger than 1024 bytes|field::5")
+	post("size=1024", 5, 1024).Status(iris.StatusOK)
+
+	e.POST("/").WithText("not a multipart body").Expect().Status(iris.StatusBadRequest).
+		Body().Equal(http.ErrNotMultipart.Error() + "|")
+}
@@ -0,0 +1,180 @@
+package context
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// N is the options of the `Context#Negotiate`.
+type N struct {
+	// Template is the view file which renders the value
+	// when the client accepts "text/html", it's not offered if empty.
+	Template string
+	// Marshalers are the custom marshalers per content type, i.e "application/cbor",
+	// they are offered before the builtin ones.
+	Marshalers map[string]func(v interface{}) ([]byte, error)
+	// Offers limits the content types that can be sent to the client, by order of preference.
+	// Defaults to the content types of the `Marshalers` followed by
+	// "application/json", "text/xml", "application/xml", "application/x-yaml", "application/msgpack"
+	// and "text/html" if `Template` is not empty.
+	Offers []string
+	// Gzip compresses the response if the client accepts gzip.
+	Gzip bool
+}
+
+// ContentXMLAltHeaderValue is the alternative, "application/xml", content type of XML data,
+// it's offered by the `Context#Negotiate`.
+const ContentXMLAltHeaderValue = "application/xml"
+
+var errNotAcceptable = errors.New("not acceptable: %s")
+
+func (n N) offers() []string {
+	if len(n.Offers) > 0 {
+		return n.Offers
+	}
+
+	var offers []string
+	for contentType := range n.Marshalers {
+		offers = append(offers, contentType)
+	}
+	sort.Strings(offers)
+
+	offers = append(offers, ContentJSONHeaderValue, ContentXMLHeaderValue, ContentXMLAltHeaderValue, ContentYAMLHeaderValue, ContentMsgPackHeaderValue)
+	if n.Template != "" {
+		offers = append(offers, ContentHTMLHeaderValue)
+	}
+
+	return offers
+}
+
+// acceptSpec is a value of an "Accept" family header with its quality.
+type acceptSpec struct {
+	value string
+	q     float64
+}
+
+// parseAccept parses an "Accept", "Accept-Charset" or "Accept-Encoding" header value,
+// the result is sorted by the quality, the not acceptable (q=0) values are included.
+func parseAccept(header string) (specs []acceptSpec) {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		spec := acceptSpec{value: part, q: 1}
+		if idx := strings.IndexByte(part, ';'); idx != -1 {
+			spec.value = strings.TrimSpace(part[:idx])
+			for _, param := range strings.Split(part[idx+1:], ";") {
+				param = strings.TrimSpace(param)
+				if strings.HasPrefix(param, "q=") {
+					if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+						spec.q = q
+					}
+				}
+			}
+		}
+
+		spec.value = strings.ToLower(spec.value)
+		specs = append(specs, spec)
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		return specs[i].q > specs[j].q
+	})
+
+	return
+}
+
+// negotiateContentType returns the offer with the highest quality on the "accept" header value,
+// or empty if none of them is acceptable.
+// An offer gets the quality of its most specific media range, so "*/*, application/json;q=0"
+// excludes the JSON one, the ties are resolved by the header's order and then by the offers' order.
+func negotiateContentType(accept string, offers []string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	if accept == "" {
+		return offers[0]
+	}
+
+	var (
+		specs     = parseAccept(accept)
+		best      string
+		bestQ     float64
+		bestIndex = len(specs)
+	)
+
+	for _, offer := range offers {
+		q, index := mediaTypeQuality(specs, strings.ToLower(offer))
+		if q > bestQ || (q == bestQ && q > 0 && index < bestIndex) {
+			best, bestQ, bestIndex = offer, q, index
+		}
+	}
+
+	return best
+}
+
+// mediaTypeQuality returns the quality of the most specific of the "specs" that matches the "offer"
+// and its index, the quality is -1 if none of them matches.
+func mediaTypeQuality(specs []acceptSpec, offer string) (q float64, index int) {
+	q, index = -1, len(specs)
+	specificity := 0
+
+	for i, spec := range specs {
+		if !matchMediaType(spec.value, offer) {
+			continue
+		}
+
+		s := 1 // "*/*"
+		if spec.value == offer {
+			s = 3
+		} else if strings.HasSuffix(spec.value, "/*") && spec.value != "*/*" {
+			s = 2
+		}
+
+		if s > specificity {
+			q, index, specificity = spec.q, i, s
+		}
+	}
+
+	return
+}
+
+func matchMediaType(accepted, offer string) bool {
+	if accepted == "*/*" || accepted == "*" || accepted == offer {
+		return true
+	}
+
+	if strings.HasSuffix(accepted, "/*") {
+		return strings.HasPrefix(offer, accepted[:len(accepted)-1])
+	}
+
+	return false
+}
+
+// acceptsValue reports whether the "value", i.e a charset,
+// is acceptable by the "accept" header value, an empty header accepts everything.
+// The "value" itself takes precedence over the "*", i.e "*, iso-8859-1;q=0".
+func acceptsValue(accept, value string) bool {
+	if accept == "" {
+		return true
+	}
+
+	value = strings.ToLower(value)
+	wildcardQ := -1.0
+	for _, spec := range parseAccept(accept) {
+		if spec.value == value {
+			return spec.q > 0
+		}
+		if spec.value == "*" && wildcardQ == -1 {
+			wildcardQ = spec.q
+		}
+	}
+
+	return wildcardQ > 0
+}
@@ -0,0 +1,60 @@
+package context
+
+import "testing"
+
+func TestNegotiateContentType(t *testing.T) {
+	offers := []string{ContentJSONHeaderValue, ContentXMLHeaderValue, ContentHTMLHeaderValue}
+
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", ContentJSONHeaderValue},
+		{"text/html", ContentHTMLHeaderValue},
+		{"text/xml, application/json", ContentXMLHeaderValue},
+		{"application/json;q=0.5, text/html", ContentHTMLHeaderValue},
+		{"text/*", ContentXMLHeaderValue},
+		{"*/*", ContentJSONHeaderValue},
+		{"image/png", ""},
+		// the q=0 exclusions win over the wildcards, whatever their order.
+		{"*/*, application/json;q=0", ContentXMLHeaderValue},
+		{"application/json;q=0, */*", ContentXMLHeaderValue},
+		{"text/*, text/xml;q=0", ContentHTMLHeaderValue},
+		{"*/*;q=0.1, text/*;q=0", ContentJSONHeaderValue},
+		{"*/*, application/json;q=0, text/*;q=0", ""},
+		{"application/json;q=0", ""},
+		// the most specific range gives the quality.
+		{"text/*;q=0.9, text/html;q=0.3, application/json;q=0.5", ContentXMLHeaderValue},
+		{"*/*;q=0.8, text/html", ContentHTMLHeaderValue},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateContentType(tt.accept, offers); got != tt.expected {
+			t.Fatalf("expected the content type of '%s' to be '%s' but got '%s'", tt.accept, tt.expected, got)
+		}
+	}
+}
+
+func TestAcceptsValue(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{"", true},
+		{"utf-8", true},
+		{"UTF-8;q=0.5", true},
+		{"iso-8859-1", false},
+		{"*", true},
+		{"*;q=0", false},
+		{"utf-8;q=0", false},
+		// the value wins over the wildcard, whatever their order.
+		{"*, utf-8;q=0", false},
+		{"*;q=0, utf-8", true},
+	}
+
+	for _, tt := range tests {
+		if got := acceptsValue(tt.accept, "utf-8"); got != tt.expected {
+			t.Fatalf("expected the utf-8 to be accepted by '%s': %v but got %v", tt.accept, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package context
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// PathNormalization holds the options of the request path's normalization,
+// which is applied by the router before the route matching.
+//
+// See `NormalizePath` and the `Configuration#PathNormalization`.
+type PathNormalization struct {
+	// CollapseSlashes replaces the consecutive slashes with a single one, i.e "/a//b" to "/a/b".
+	CollapseSlashes bool `json:"collapseSlashes,omitempty" yaml:"CollapseSlashes" toml:"CollapseSlashes"`
+	// ResolveDotSegments resolves the "." and ".." path segments, i.e "/a/./b/../c" to "/a/c".
+	ResolveDotSegments bool `json:"resolveDotSegments,omitempty" yaml:"ResolveDotSegments" toml:"ResolveDotSegments"`
+	// UnicodeNFC converts the path to the unicode Normalization Form C,
+	// so the same characters with different byte representations match the same route.
+	UnicodeNFC bool `json:"unicodeNFC,omitempty" yaml:"UnicodeNFC" toml:"UnicodeNFC"`
+	// Redirect if true then the client is redirected to the normalized path,
+	// otherwise the request's path is rewritten and the normalized route is served directly.
+	Redirect bool `json:"redirect,omitempty" yaml:"Redirect" toml:"Redirect"`
+}
+
+// Enabled reports whether any of the normalizations is enabled.
+func (opts PathNormalization) Enabled() bool {
+	return opts.CollapseSlashes || opts.ResolveDotSegments || opts.UnicodeNFC
+}
+
+// NormalizePath returns the "path" normalized by the "opts".
+func NormalizePath(path string, opts PathNormalization) string {
+	if opts.UnicodeNFC && !norm.NFC.IsNormalString(path) {
+		path = norm.NFC.String(path)
+	}
+
+	if opts.CollapseSlashes && strings.Contains(path, "//") {
+		path = collapseSlashes(path)
+	}
+
+	if opts.ResolveDotSegments && strings.Contains(path, "/.") {
+		path = resolveDotSegments(path)
+	}
+
+	return path
+}
+
+func collapseSlashes(path string) string {
+	b := make([]byte, 0, len(path))
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+		b = append(b, path[i])
+	}
+
+	return string(b)
+}
+
+func resolveDotSegments(path string) string {
+	segments := strings.Split(path, "/")
+	resolved := make([]string, 0, len(segments))
+	last := len(segments) - 1
+	for i, s := range segments {
+		switch s {
+		case ".":
+		case "..":
+			if len(resolved) > 1 { // keep the root.
+				resolved = resolved[:len(resolved)-1]
+			}
+		default:
+			resolved = append(resolved, s)
+			continue
+		}
+
+		if i == last { // "/a/b/.." is the directory "/a/".
+			resolved = append(resolved, "")
+		}
+	}
+
+	if path = strings.Join(resolved, "/"); path == "" {
+		return "/"
+	}
+
+	return path
+}
@@ -0,0 +1,81 @@
+package context
+
+// ContentProblemJSONHeaderValue is the header value of the RFC 7807 Problem Details, see `Context#Problem`.
+const ContentProblemJSONHeaderValue = "application/problem+json"
+
+// Problem is an RFC 7807 Problem Details response body of an HTTP API error,
+// see `Context#Problem` and the `ProblemHandler`.
+type Problem struct {
+	// Type is a URI reference which identifies the problem type.
+	//
+	// Defaults to "about:blank".
+	Type string
+	// Title is a short, human-readable summary of the problem type.
+	//
+	// Defaults to the status text of the `Status`.
+	Title string
+	// Status is the HTTP status code.
+	//
+	// Defaults to the response's status code if it's an error one, otherwise to 500.
+	Status int
+	// Detail is a human-readable explanation of this occurrence of the problem.
+	Detail string
+	// Instance is a URI reference which identifies this occurrence of the problem.
+	Instance string
+	// Extensions are the additional members of the problem, i.e "errors" or "trace_id".
+	// They can not override the standard members.
+	Extensions map[string]interface{}
+}
+
+// NewProblem returns a new `Problem` of the "status" with a "detail" message.
+func NewProblem(status int, detail string) Problem {
+	return Problem{Status: status, Detail: detail}
+}
+
+// Key sets an extension member of the problem.
+//
+// Returns itself.
+func (p Problem) Key(key string, value interface{}) Problem {
+	extensions := make(map[string]interface{}, len(p.Extensions)+1)
+	for k, v := range p.Extensions {
+		extensions[k] = v
+	}
+	extensions[key] = value
+	p.Extensions = extensions
+	return p
+}
+
+// toMap returns the members of the problem as they are rendered,
+// the extensions are flattened.
+func (p Problem) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	} else {
+		delete(m, "detail")
+	}
+
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	} else {
+		delete(m, "instance")
+	}
+
+	return m
+}
+
+// ProblemHandler renders the response's error status code as a `Problem`,
+// the instance is the request's path.
+// Register it as the error code handler to respond with consistent API error bodies, i.e:
+//
+// app.OnAnyErrorCode(context.ProblemHandler)
+func ProblemHandler(ctx Context) {
+	ctx.Problem(Problem{Instance: ctx.Path()})
+}
@@ -0,0 +1,45 @@
+package context
+
+import "io"
+
+// ProgressFunc reports the progress of a transfer,
+// the "total" is negative when the size is unknown.
+type ProgressFunc func(transferred, total int64)
+
+// progressWriter is the response writer of the `Context#ServeContentReader`,
+// it reports the written bytes of the body to the "onProgress".
+type progressWriter struct {
+	ResponseWriter
+	written    int64
+	total      int64
+	onProgress ProgressFunc
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	if n > 0 {
+		w.written += int64(n)
+		w.onProgress(w.written, w.total)
+	}
+
+	return n, err
+}
+
+// progressReader is the request body of the `Context#TrackUploadProgress`,
+// it reports the read bytes of the body to the "onProgress".
+type progressReader struct {
+	io.ReadCloser
+	read       int64
+	total      int64
+	onProgress ProgressFunc
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	if n > 0 {
+		r.read += int64(n)
+		r.onProgress(r.read, r.total)
+	}
+
+	return n, err
+}
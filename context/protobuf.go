@@ -0,0 +1,87 @@
+package context
+
+import (
+	"encoding/json"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// ContentProtobufHeaderValue header value for Protocol Buffers data.
+const ContentProtobufHeaderValue = "application/x-protobuf"
+
+// ProtoMessage is a Protocol Buffers message,
+// it's compatible with the `proto.Message` of the "github.com/golang/protobuf/proto" package.
+type ProtoMessage interface {
+	Reset()
+	String() string
+	ProtoMessage()
+}
+
+// ProtoCodec encodes and decodes the Protocol Buffers messages
+// of the `Context#Protobuf` and `Context#ReadProtobuf`.
+//
+// Iris does not depend on a Protocol Buffers implementation,
+// the `DefaultProtoCodec` can be replaced on the application's main function, i.e:
+//
+// context.DefaultProtoCodec = context.ProtoCodec{
+// 	Marshal: proto.Marshal,
+// 	Unmarshal: proto.Unmarshal,
+// 	MarshalJSON: func(m context.ProtoMessage) ([]byte, error) {
+// 		s, err := new(jsonpb.Marshaler).MarshalToString(m)
+// 		return []byte(s), err
+// 	},
+// 	UnmarshalJSON: func(b []byte, m context.ProtoMessage) error {
+// 		return jsonpb.UnmarshalString(string(b), m)
+// 	},
+// }
+type ProtoCodec struct {
+	Marshal       func(m ProtoMessage) ([]byte, error)
+	Unmarshal     func(b []byte, m ProtoMessage) error
+	MarshalJSON   func(m ProtoMessage) ([]byte, error)
+	UnmarshalJSON func(b []byte, m ProtoMessage) error
+}
+
+var errProtoCodec = errors.New("protobuf: %T does not implement the %s method, set the context.DefaultProtoCodec")
+
+// DefaultProtoCodec is the codec of the `Context#Protobuf` and `Context#ReadProtobuf`.
+// By default it uses the generated `Marshal` and `Unmarshal` methods of the message, if any,
+// and the "encoding/json" for the JSON transcoding.
+var DefaultProtoCodec = ProtoCodec{
+	Marshal: func(m ProtoMessage) ([]byte, error) {
+		if marshaler, ok := m.(interface {
+			Marshal() ([]byte, error)
+		}); ok {
+			return marshaler.Marshal()
+		}
+
+		return nil, errProtoCodec.Format(m, "Marshal")
+	},
+	Unmarshal: func(b []byte, m ProtoMessage) error {
+		if unmarshaler, ok := m.(interface {
+			Unmarshal([]byte) error
+		}); ok {
+			m.Reset()
+			return unmarshaler.Unmarshal(b)
+		}
+
+		return errProtoCodec.Format(m, "Unmarshal")
+	},
+	MarshalJSON: func(m ProtoMessage) ([]byte, error) {
+		return json.Marshal(m)
+	},
+	UnmarshalJSON: func(b []byte, m ProtoMessage) error {
+		m.Reset() // like the binary format, the fields which are not part of the "b" are not kept.
+		return json.Unmarshal(b, m)
+	},
+}
+
+// Protobuf contains the options for the Protobuf (Context's) Renderer.
+type Protobuf struct {
+	// JSON transcodes the message to JSON, via the `ProtoCodec#MarshalJSON`,
+	// so browsers and tools that do not speak Protocol Buffers can read it.
+	JSON bool
+}
+
+// DefaultProtobufOptions is the optional settings that are being used
+// inside `ctx.Protobuf`.
+var DefaultProtobufOptions = Protobuf{}
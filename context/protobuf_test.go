@@ -0,0 +1,145 @@
+package context_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+// testProtoMessage is a stub of a generated message, its binary format is "$id:$name".
+type testProtoMessage struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Extra string `json:"extra,omitempty"`
+}
+
+func (m *testProtoMessage) Reset()         { *m = testProtoMessage{} }
+func (m *testProtoMessage) String() string { return fmt.Sprintf("%d:%s", m.ID, m.Name) }
+func (m *testProtoMessage) ProtoMessage()  {}
+
+func (m *testProtoMessage) Marshal() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *testProtoMessage) Unmarshal(b []byte) error {
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return errors.New("invalid message")
+	}
+
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return err
+	}
+
+	m.ID, m.Name = id, parts[1]
+	return nil
+}
+
+// testProtoMessageNoCodec does not implement the generated Marshal and Unmarshal methods.
+type testProtoMessageNoCodec struct{}
+
+func (m *testProtoMessageNoCodec) Reset()         {}
+func (m *testProtoMessageNoCodec) String() string { return "" }
+func (m *testProtoMessageNoCodec) ProtoMessage()  {}
+
+func TestProtobuf(t *testing.T) {
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		ctx.Protobuf(&testProtoMessage{ID: 1, Name: "kataras"})
+	})
+	app.Get("/json", func(ctx context.Context) {
+		ctx.Protobuf(&testProtoMessage{ID: 1, Name: "kataras"}, context.Protobuf{JSON: true})
+	})
+	app.Get("/nocodec", func(ctx context.Context) {
+		if _, err := ctx.Protobuf(&testProtoMessageNoCodec{}); err != nil {
+			ctx.WriteString(err.Error())
+		}
+	})
+	app.Post("/", func(ctx context.Context) {
+		// the message is reset before it's unmarshaled.
+		msg := &testProtoMessage{Extra: "stale"}
+		if err := ctx.ReadProtobuf(msg); err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.Writef("%d %s %q", msg.ID, msg.Name, msg.Extra)
+	})
+	app.Post("/nocodec", func(ctx context.Context) {
+		ctx.WriteString(ctx.ReadProtobuf(&testProtoMessageNoCodec{}).Error())
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).
+		ContentType(context.ContentProtobufHeaderValue, "").Body().Equal("1:kataras")
+	e.GET("/json").Expect().Status(iris.StatusOK).
+		ContentType(context.ContentJSONHeaderValue, "utf-8").JSON().Equal(map[string]interface{}{"id": 1, "name": "kataras"})
+	e.GET("/nocodec").Expect().Status(iris.StatusInternalServerError).
+		Body().Equal("protobuf: *context_test.testProtoMessageNoCodec does not implement the Marshal method, set the context.DefaultProtoCodec")
+
+	e.POST("/").WithHeader("Content-Type", context.ContentProtobufHeaderValue).WithBytes([]byte("2:makis")).
+		Expect().Status(iris.StatusOK).Body().Equal(`2 makis ""`)
+	e.POST("/").WithHeader("Content-Type", context.ContentProtobufHeaderValue).WithBytes([]byte("invalid")).
+		Expect().Status(iris.StatusBadRequest).Body().Equal("invalid message")
+	// the JSON transcoding.
+	e.POST("/").WithHeader("Content-Type", context.ContentJSONHeaderValue).WithBytes([]byte(`{"id":3,"name":"json"}`)).
+		Expect().Status(iris.StatusOK).Body().Equal(`3 json ""`)
+	e.POST("/nocodec").WithHeader("Content-Type", context.ContentProtobufHeaderValue).WithBytes([]byte("1:a")).
+		Expect().Status(iris.StatusOK).
+		Body().Equal("protobuf: *context_test.testProtoMessageNoCodec does not implement the Unmarshal method, set the context.DefaultProtoCodec")
+}
+
+func TestProtobufCodec(t *testing.T) {
+	defaultCodec := context.DefaultProtoCodec
+	defer func() { context.DefaultProtoCodec = defaultCodec }()
+
+	var calls []string
+	context.DefaultProtoCodec = context.ProtoCodec{
+		Marshal: func(m context.ProtoMessage) ([]byte, error) {
+			calls = append(calls, "Marshal")
+			return []byte("stub"), nil
+		},
+		Unmarshal: func(b []byte, m context.ProtoMessage) error {
+			calls = append(calls, "Unmarshal")
+			m.(*testProtoMessageNoCodec).Reset()
+			return nil
+		},
+		MarshalJSON: func(m context.ProtoMessage) ([]byte, error) {
+			calls = append(calls, "MarshalJSON")
+			return []byte(`{"stub":true}`), nil
+		},
+		UnmarshalJSON: func(b []byte, m context.ProtoMessage) error {
+			calls = append(calls, "UnmarshalJSON")
+			return nil
+		},
+	}
+
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		ctx.Protobuf(&testProtoMessageNoCodec{}, context.Protobuf{JSON: ctx.URLParamExists("json")})
+	})
+	app.Post("/", func(ctx context.Context) {
+		if err := ctx.ReadProtobuf(&testProtoMessageNoCodec{}); err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("stub")
+	e.GET("/").WithQuery("json", true).Expect().Status(iris.StatusOK).Body().Equal(`{"stub":true}`)
+	e.POST("/").WithHeader("Content-Type", context.ContentProtobufHeaderValue).WithBytes([]byte("data")).
+		Expect().Status(iris.StatusOK)
+	e.POST("/").WithHeader("Content-Type", context.ContentJSONHeaderValue).WithBytes([]byte("{}")).
+		Expect().Status(iris.StatusOK)
+
+	if expected, got := "Marshal MarshalJSON Unmarshal UnmarshalJSON", strings.Join(calls, " "); expected != got {
+		t.Fatalf("expected the codec calls to be '%s' but got '%s'", expected, got)
+	}
+}
@@ -0,0 +1,76 @@
+package context
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// pushableAssets returns the local stylesheets and scripts which are referenced by the "document",
+// by order of appearance and without duplicates.
+// The absolute and protocol-relative URLs are skipped, they can not be pushed.
+// The "document" is tokenized, so the commented out tags and the text of the scripts are ignored.
+func pushableAssets(document []byte) (targets []string) {
+	seen := make(map[string]struct{})
+	add := func(target string) {
+		if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") {
+			return
+		}
+
+		if _, ok := seen[target]; ok {
+			return
+		}
+
+		seen[target] = struct{}{}
+		targets = append(targets, target)
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(document))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// io.EOF or a malformed document, the tags so far are pushed.
+			return
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			if !hasAttr {
+				continue
+			}
+
+			var rel, href, src string
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				switch string(key) {
+				case "rel":
+					rel = string(val)
+				case "href":
+					href = string(val)
+				case "src":
+					src = string(val)
+				}
+			}
+
+			switch string(name) {
+			case "link":
+				if isStylesheet(rel) {
+					add(href)
+				}
+			case "script":
+				add(src)
+			}
+		}
+	}
+}
+
+// isStylesheet reports whether the "rel" attribute's value, a list of link types, contains the "stylesheet".
+func isStylesheet(rel string) bool {
+	for _, typ := range strings.Fields(rel) {
+		if strings.EqualFold(typ, "stylesheet") {
+			return true
+		}
+	}
+
+	return false
+}
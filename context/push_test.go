@@ -0,0 +1,42 @@
+package context
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPushableAssets(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected []string
+	}{
+		{"stylesheets and scripts by order", `<html><head>
+<script src="/js/first.js"></script>
+<link rel="stylesheet" href="/css/app.css">
+<link href='/css/print.css' media="print" rel=stylesheet />
+</head><body><script src="/js/app.js" defer></script></body></html>`,
+			[]string{"/js/first.js", "/css/app.css", "/css/print.css", "/js/app.js"}},
+		{"link types", `<link rel="alternate stylesheet" href="/css/alt.css"><link REL="StyleSheet" HREF="/css/upper.css">
+<link rel="icon" href="/favicon.ico"><link rel="preload" href="/js/preload.js">`,
+			[]string{"/css/alt.css", "/css/upper.css"}},
+		{"absolute and protocol-relative urls", `<script src="https://cdn.example.com/a.js"></script>
+<script src="//cdn.example.com/b.js"></script><script src="relative.js"></script><script>var x = 1;</script>`,
+			nil},
+		{"duplicates", `<script src="/js/app.js"></script><script src="/js/app.js"></script>`,
+			[]string{"/js/app.js"}},
+		// the tags which are not elements of the document are not pushed.
+		{"comments and script text", `<!-- <script src="/js/commented.js"></script> -->
+<script>document.write('<script src="/js/written.js"><\/script>');</script>
+<textarea><link rel="stylesheet" href="/css/text.css"></textarea>`,
+			nil},
+		{"malformed", `<link rel="stylesheet" href="/css/app.css"><script src="/js/app.js`,
+			[]string{"/css/app.css"}},
+	}
+
+	for _, tt := range tests {
+		if got := pushableAssets([]byte(tt.html)); !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("%s: expected the assets %v but got %v", tt.name, tt.expected, got)
+		}
+	}
+}
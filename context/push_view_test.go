@@ -0,0 +1,71 @@
+package context_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/view"
+)
+
+// testPusher is an HTTP/2 response writer which records the pushes.
+type testPusher struct {
+	*httptest.ResponseRecorder
+	targets []string
+}
+
+func (w *testPusher) Push(target string, opts *http.PushOptions) error {
+	w.targets = append(w.targets, target)
+	return nil
+}
+
+func TestViewPushAssets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	page := `<link rel="stylesheet" href="/css/app.css"><script src="/js/app.js"></script>`
+	if err = ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte(page), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.Configure(iris.WithPushViewAssets)
+	app.RegisterView(view.HTML(dir, ".html"))
+	app.Get("/", func(ctx context.Context) {
+		ctx.View("index.html")
+	})
+
+	if err = app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, protoMajor := range []int{1, 2} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.ProtoMajor = protoMajor
+
+		w := &testPusher{ResponseRecorder: httptest.NewRecorder()}
+		app.ServeHTTP(w, req)
+
+		if body := w.Body.String(); w.Code != http.StatusOK || body != page {
+			t.Fatalf("HTTP/%d: expected the view but got %d: %s", protoMajor, w.Code, body)
+		}
+
+		var expected []string
+		if protoMajor == 2 {
+			expected = []string{"/css/app.css", "/js/app.js"}
+		}
+
+		if !reflect.DeepEqual(w.targets, expected) {
+			t.Fatalf("HTTP/%d: expected the pushes %v but got %v", protoMajor, expected, w.targets)
+		}
+	}
+}
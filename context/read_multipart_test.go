@@ -0,0 +1,123 @@
+package context_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+type testMultipartMeta struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+type testMultipartForm struct {
+	Meta        testMultipartMeta       `part:"meta"`
+	Options     *testMultipartMeta      `part:"options"`
+	Description string                  `part:"description"`
+	Raw         []byte                  `part:"raw"`
+	File        *multipart.FileHeader   `part:"file"`
+	Attachments []*multipart.FileHeader `part:"attachments"`
+	Missing     string                  `part:"missing"`
+	Ignored     string                  `part:"-"`
+	Untagged    string
+	unexported  string `part:"description"`
+}
+
+func testReadMultipartBody(t *testing.T, meta string) ([]byte, string) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	w.WriteField("meta", meta)
+	w.WriteField("description", "a document")
+	w.WriteField("raw", "raw bytes")
+	w.WriteField("Untagged", "untagged")
+	w.WriteField("-", "ignored")
+
+	// a JSON part which is sent as a file.
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="options"; filename="options.json"`)
+	h.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(`{"title":"options","tags":["c"]}`))
+
+	for name, filename := range map[string]string{"file": "doc.pdf", "attachments": "a.txt"} {
+		part, err = w.CreateFormFile(name, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("contents of " + filename))
+	}
+	part, err = w.CreateFormFile("attachments", "b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte("contents of b.txt"))
+
+	w.Close()
+	return b.Bytes(), w.FormDataContentType()
+}
+
+func TestReadMultipart(t *testing.T) {
+	app := iris.New()
+	app.Post("/", func(ctx context.Context) {
+		var form testMultipartForm
+		if err := ctx.ReadMultipart(&form); err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.WriteString(err.Error())
+			return
+		}
+
+		var attachments []string
+		for _, fh := range form.Attachments {
+			attachments = append(attachments, fh.Filename)
+		}
+
+		f, err := form.File.Open()
+		if err != nil {
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		contents, _ := ioutil.ReadAll(f)
+
+		ctx.Writef("%s %v|%s %v|%s|%s|%s: %s|%s|%q %q %q %q",
+			form.Meta.Title, form.Meta.Tags, form.Options.Title, form.Options.Tags,
+			form.Description, form.Raw, form.File.Filename, contents, strings.Join(attachments, ","),
+			form.Missing, form.Ignored, form.Untagged, form.unexported)
+	})
+	app.Post("/ptr", func(ctx context.Context) {
+		var form testMultipartForm
+		ctx.WriteString(fmt.Sprint(ctx.ReadMultipart(form)))
+	})
+
+	e := httptest.New(t, app)
+
+	body, contentType := testReadMultipartBody(t, `{"title":"meta","tags":["a","b"]}`)
+	e.POST("/").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(iris.StatusOK).Body().
+		Equal(`meta [a b]|options [c]|a document|raw bytes|doc.pdf: contents of doc.pdf|a.txt,b.txt|"" "" "" ""`)
+
+	// the JSON parts are decoded through the JSON codec and their errors name the part.
+	body, contentType = testReadMultipartBody(t, `{"title":`)
+	e.POST("/").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(iris.StatusBadRequest).Body().Equal("read multipart: part 'meta': unexpected end of JSON input")
+
+	e.POST("/").WithFormField("meta", "{}").Expect().
+		Status(iris.StatusBadRequest).Body().Equal(http.ErrNotMultipart.Error())
+
+	body, contentType = testReadMultipartBody(t, `{}`)
+	e.POST("/ptr").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(iris.StatusOK).Body().Equal("read multipart: expected a pointer to a struct but got struct")
+}
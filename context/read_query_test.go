@@ -0,0 +1,101 @@
+package context_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+type testQueryPage struct {
+	Page  int `url:"page" default:"1"`
+	Limit int `url:"limit" default:"10"`
+}
+
+type testQuery struct {
+	testQueryPage
+	Search  string        `url:"q"`
+	Sort    string        `url:"sort" default:"name"`
+	Tags    []string      `url:"tag" default:"a,b"`
+	IDs     []int         `url:"id"`
+	Active  *bool         `url:"active"`
+	Timeout time.Duration `url:"timeout" default:"1s"`
+	Name    string
+	Ignored string `url:"-"`
+}
+
+func TestReadQuery(t *testing.T) {
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		// the slices are replaced by the query's values, not appended,
+		// the missing parameters without a default keep the initial values.
+		q := testQuery{IDs: []int{0}}
+		if err := ctx.ReadQuery(&q); err != nil {
+			errs, ok := err.(context.BindErrors)
+			if !ok {
+				t.Errorf("expected the errors to be BindErrors but got %T", err)
+			}
+			ctx.StatusCode(iris.StatusBadRequest)
+			for _, e := range errs {
+				ctx.Writef("%s:%s=%s;", e.Source, e.Field, e.Value)
+			}
+			return
+		}
+
+		active := "nil"
+		if q.Active != nil {
+			active = fmt.Sprint(*q.Active)
+		}
+
+		ctx.Writef("%d %d %s %s %s %v %s %s %s %s", q.Page, q.Limit, q.Search, q.Sort, strings.Join(q.Tags, ","), q.IDs,
+			active, q.Timeout, q.Name, q.Ignored)
+	})
+	app.Get("/value", func(ctx context.Context) {
+		ctx.WriteString(ctx.ReadQuery(testQuery{}).Error())
+	})
+
+	e := httptest.New(t, app)
+
+	// the missing and the empty parameters are set by their default values.
+	e.GET("/").Expect().Status(iris.StatusOK).
+		Body().Equal("1 10  name a,b [0] nil 1s  ")
+	e.GET("/").WithQuery("page", "").WithQuery("sort", "").Expect().Status(iris.StatusOK).
+		Body().Equal("1 10  name a,b [0] nil 1s  ")
+
+	e.GET("/").WithQueryString("page=3&limit=50&q=iris&sort=date&tag=x&tag=y&tag=z&id=1&id=2&active=false&timeout=2m&Name=kataras&Ignored=true").
+		Expect().Status(iris.StatusOK).
+		Body().Equal("3 50 iris date x,y,z [1 2] false 2m0s kataras ")
+
+	// each failed field is reported.
+	e.GET("/").WithQueryString("page=two&id=1&id=b&timeout=soon").Expect().Status(iris.StatusBadRequest).
+		Body().Equal("query:page=two;query:id=1,b;query:timeout=soon;")
+
+	e.GET("/value").Expect().Status(iris.StatusOK).
+		Body().Equal("bind: expected a pointer to a struct but got context_test.testQuery")
+}
+
+func TestReadQueryDefaultError(t *testing.T) {
+	type invalidDefault struct {
+		IDs []int `url:"id" default:"1,two"`
+	}
+
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		var q invalidDefault
+		if err := ctx.ReadQuery(&q); err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.Writef("%v", q.IDs)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithQuery("id", 3).Expect().Status(iris.StatusOK).Body().Equal("[3]")
+	// the invalid default values are reported like the invalid parameters.
+	e.GET("/").Expect().Status(iris.StatusBadRequest).Body().Contains(`bind: query "id": `)
+}
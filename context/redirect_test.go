@@ -0,0 +1,71 @@
+package context_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+func TestRedirectPreserveMethod(t *testing.T) {
+	app := iris.New()
+	app.Post("/api/v1/{action:path}", func(ctx context.Context) {
+		ctx.RedirectPreserveMethod(ctx.URLParam("to"), ctx.URLParamExists("permanent"))
+		ctx.Next()
+	}, func(ctx context.Context) {
+		t.Error("expected the next handler to not be executed after the redirect")
+	})
+	app.Any("/api/users", func(ctx context.Context) {
+		b, _ := ioutil.ReadAll(ctx.Request().Body)
+		ctx.Writef("%s %s", ctx.Method(), b)
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, to, expected string
+	}{
+		// the relative urls are resolved against the current path.
+		{"/api/v1/accounts", "../users", "/api/users"},
+		{"/api/v1/accounts", "users", "/api/v1/users"},
+		{"/api/v1/accounts", "users?page=2", "/api/v1/users?page=2"},
+		{"/api/v1/accounts", "/login", "/login"},
+		// the absolute urls are kept as they are.
+		{"/api/v1/accounts", "https://example.com/users", "https://example.com/users"},
+		{"/api/v1/accounts", "//example.com/users", "//example.com/users"},
+	}
+
+	for _, permanent := range []bool{false, true} {
+		status := iris.StatusTemporaryRedirect
+		query := "?to="
+		if permanent {
+			status = iris.StatusPermanentRedirect
+			query = "?permanent=true&to="
+		}
+
+		for i, tt := range tests {
+			rec := stdhttptest.NewRecorder()
+			app.ServeHTTP(rec, stdhttptest.NewRequest(http.MethodPost, tt.path+query+url.QueryEscape(tt.to), nil))
+			if rec.Code != status {
+				t.Fatalf("[%d] expected the status code to be %d but got %d", i, status, rec.Code)
+			}
+			if got := rec.Header().Get("Location"); got != tt.expected {
+				t.Fatalf("[%d] expected '%s' on '%s' to redirect to '%s' but got '%s'", i, tt.to, tt.path, tt.expected, got)
+			}
+		}
+	}
+
+	// the client repeats the request with the same method and body.
+	e := httptest.New(t, app)
+	e.POST("/api/v1/accounts").WithQuery("to", "../users").WithText("kataras").
+		Expect().Status(iris.StatusOK).Body().Equal("POST kataras")
+	e.POST("/api/v1/accounts").WithQuery("to", "../users").WithQuery("permanent", true).WithText("kataras").
+		Expect().Status(iris.StatusOK).Body().Equal("POST kataras")
+}
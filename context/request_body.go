@@ -0,0 +1,28 @@
+package context
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// ErrRequestBodyTooLarge is returned by the `Context#RecordRequestBody`
+// when the request body is larger than its limit.
+var ErrRequestBodyTooLarge = errors.New("request body: larger than %d bytes")
+
+// recordedBodyContextKey is the request's values key of the `Context#RecordRequestBody`.
+const recordedBodyContextKey = "iris.request_body"
+
+// readerCloser is a request body which reads from a different reader
+// than the one that it closes.
+type readerCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// recordedBody returns a new request body of the recorded "b" bytes,
+// which closes the original body, "c".
+func recordedBody(b []byte, c io.Closer) io.ReadCloser {
+	return readerCloser{Reader: bytes.NewReader(b), Closer: c}
+}
@@ -0,0 +1,79 @@
+package context_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/httptest"
+)
+
+type testRecordedUser struct {
+	Name string `json:"name"`
+}
+
+func TestRecordRequestBody(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithPostMaxMemory(32))
+
+	record := func(ctx context.Context) {
+		limit := ctx.URLParamInt64Default("limit", 0)
+		b, err := ctx.RecordRequestBody(limit)
+		if err != nil {
+			if e, ok := err.(errors.Error); !ok || !e.Equal(context.ErrRequestBodyTooLarge) {
+				t.Errorf("expected the ErrRequestBodyTooLarge but got: %v", err)
+			}
+			ctx.Values().Set("recorded", err.Error())
+		} else {
+			ctx.Values().Set("recorded", string(b))
+		}
+		ctx.Next()
+	}
+
+	app.Post("/", record, func(ctx context.Context) {
+		var first, second testRecordedUser
+		if err := ctx.ReadJSON(&first); err != nil {
+			t.Error(err)
+		}
+		// the body is rewound after each read.
+		if err := ctx.ReadJSON(&second); err != nil {
+			t.Error(err)
+		}
+
+		again, err := ctx.RecordRequestBody(0)
+		if err != nil {
+			t.Error(err)
+		}
+		raw, _ := ioutil.ReadAll(ctx.Request().Body)
+
+		ctx.Writef("%s|%s|%s|%s|%s", ctx.Values().GetString("recorded"), first.Name, second.Name, again, raw)
+	})
+
+	app.Post("/large", record, func(ctx context.Context) {
+		// the body which is not recorded can still be read once.
+		var user testRecordedUser
+		err := ctx.ReadJSON(&user)
+		ctx.Writef("%s|%s|%v", ctx.Values().GetString("recorded"), user.Name, err)
+	})
+
+	e := httptest.New(t, app)
+	body := `{"name":"kataras"}`
+
+	e.POST("/").WithHeader("Content-Type", context.ContentJSONHeaderValue).WithBytes([]byte(body)).
+		Expect().Status(iris.StatusOK).
+		Body().Equal(body + "|kataras|kataras|" + body + "|" + body)
+	e.POST("/").WithQuery("limit", len(body)).WithHeader("Content-Type", context.ContentJSONHeaderValue).WithBytes([]byte(body)).
+		Expect().Status(iris.StatusOK).
+		Body().Equal(body + "|kataras|kataras|" + body + "|" + body)
+
+	// the larger bodies are not recorded, the limit defaults to the PostMaxMemory.
+	large := `{"name":"kataras","description":"a body larger than 32 bytes"}`
+	e.POST("/large").WithHeader("Content-Type", context.ContentJSONHeaderValue).WithBytes([]byte(large)).
+		Expect().Status(iris.StatusRequestEntityTooLarge).
+		Body().Equal("request body: larger than 32 bytes|kataras|<nil>")
+	e.POST("/large").WithQuery("limit", 10).WithHeader("Content-Type", context.ContentJSONHeaderValue).WithBytes([]byte(body)).
+		Expect().Status(iris.StatusRequestEntityTooLarge).
+		Body().Equal("request body: larger than 10 bytes|kataras|<nil>")
+}
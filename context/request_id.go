@@ -0,0 +1,8 @@
+package context
+
+// RequestIDHeaderKey is the header key of the request ID, see `Context#RequestID`.
+const RequestIDHeaderKey = "X-Request-Id"
+
+// RequestIDKey is the typed key of the request ID,
+// it's set by the `middleware/requestid` and it's read by the `Context#RequestID`.
+const RequestIDKey = StringKey("iris.request.id")
@@ -0,0 +1,99 @@
+package context_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+// testLogger records the messages with its fields.
+type testLogger struct {
+	fields string
+	lines  *[]string
+}
+
+func (l testLogger) log(level, format string, args []interface{}) {
+	*l.lines = append(*l.lines, level+" "+fmt.Sprintf(format, args...)+l.fields)
+}
+
+func (l testLogger) Debugf(format string, args ...interface{}) { l.log("debug", format, args) }
+func (l testLogger) Infof(format string, args ...interface{})  { l.log("info", format, args) }
+func (l testLogger) Warnf(format string, args ...interface{})  { l.log("warn", format, args) }
+func (l testLogger) Errorf(format string, args ...interface{}) { l.log("error", format, args) }
+
+func (l testLogger) With(key string, value interface{}) context.Logger {
+	return testLogger{fields: fmt.Sprintf("%s %s=%v", l.fields, key, value), lines: l.lines}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var (
+		lines   []string
+		created int
+	)
+
+	app := iris.New()
+	app.Configure(iris.WithRequestLogger(func(ctx context.Context) context.Logger {
+		if ctx.URLParamExists("default") {
+			// the default logger is used.
+			return nil
+		}
+
+		created++
+		return testLogger{fields: fmt.Sprintf(" method=%s path=%s route=%s", ctx.Method(), ctx.Path(), ctx.GetCurrentRoute().Name()), lines: &lines}
+	}))
+
+	app.Post("/login", func(ctx context.Context) {
+		ctx.Logger().Infof("user %d logged in", 42)
+		// the next handlers log the new field.
+		ctx.SetLogger(ctx.Logger().With("user", "kataras"))
+		ctx.Next()
+	}, func(ctx context.Context) {
+		ctx.Logger().Warnf("done")
+		ctx.Logger().With("temporary", true).Errorf("failed")
+		ctx.Logger().Debugf("debug")
+	}).Name = "login"
+
+	app.Get("/default", func(ctx context.Context) {
+		l := ctx.Logger()
+		if l == nil {
+			t.Error("expected the default logger but got nil")
+			return
+		}
+		if _, ok := l.(testLogger); ok {
+			t.Error("expected the default logger but got the configured one")
+		}
+		// the same logger is returned for the same request.
+		if l != ctx.Logger() {
+			t.Error("expected the logger to be created once per request")
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/login").Expect().Status(iris.StatusOK)
+
+	expected := []string{
+		"info user 42 logged in method=POST path=/login route=login",
+		"warn done method=POST path=/login route=login user=kataras",
+		"error failed method=POST path=/login route=login user=kataras temporary=true",
+		"debug debug method=POST path=/login route=login user=kataras",
+	}
+	if got := strings.Join(lines, "\n"); got != strings.Join(expected, "\n") {
+		t.Fatalf("expected the lines:\n%s\nbut got:\n%s", strings.Join(expected, "\n"), got)
+	}
+	if created != 1 {
+		t.Fatalf("expected the logger to be created once per request but it was created %d times", created)
+	}
+
+	// each request has its own logger.
+	lines = nil
+	e.POST("/login").Expect().Status(iris.StatusOK)
+	if created != 2 || len(lines) != 4 || lines[0] != expected[0] {
+		t.Fatalf("expected a new logger for the next request but got %d loggers and the lines: %v", created, lines)
+	}
+
+	e.GET("/default").WithQuery("default", true).Expect().Status(iris.StatusOK)
+}
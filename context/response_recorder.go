@@ -37,6 +37,12 @@ type ResponseRecorder struct {
 	chunks []byte
 	// the saved headers
 	headers http.Header
+	// the hooks of the `OnFlush`, executed by order.
+	flushHooks []func(body []byte) []byte
+	// the maximum size of the body, see `SetBufferLimit`.
+	bufferLimit int64
+	// true when the body exceeded the buffer limit and it's sent directly.
+	overflowed bool
 }
 
 var _ ResponseWriter = (*ResponseRecorder)(nil)
@@ -52,6 +58,9 @@ func (w *ResponseRecorder) Naive() http.ResponseWriter {
 func (w *ResponseRecorder) BeginRecord(underline ResponseWriter) {
 	w.ResponseWriter = underline
 	w.headers = underline.Header()
+	w.flushHooks = w.flushHooks[0:0]
+	w.bufferLimit = 0
+	w.overflowed = false
 	w.ResetBody()
 }
 
@@ -84,6 +93,15 @@ func (w *ResponseRecorder) EndResponse() {
 // by all HTTP/2 clients. Handlers should read before writing if
 // possible to maximize compatibility.
 func (w *ResponseRecorder) Write(contents []byte) (int, error) {
+	if w.overflowed {
+		return w.ResponseWriter.Write(contents)
+	}
+
+	if w.bufferLimit > 0 && int64(len(w.chunks)+len(contents)) > w.bufferLimit {
+		w.overflow()
+		return w.ResponseWriter.Write(contents)
+	}
+
 	w.chunks = append(w.chunks, contents...)
 	// Remember that we should not return all the written length within `Write`:
 	// see https://github.com/kataras/iris/pull/931
@@ -120,6 +138,31 @@ func (w *ResponseRecorder) Body() []byte {
 	return w.chunks
 }
 
+// SetBufferLimit sets the maximum size, in bytes, of the recorded body, zero means no limit.
+// When a write exceeds it, the status code, the headers and the recorded body are sent to the client
+// and the rest of the body is written directly, so the memory of large responses is bounded
+// but their status code and headers can no longer change, and the `OnFlush` hooks are not executed.
+func (w *ResponseRecorder) SetBufferLimit(n int64) {
+	w.bufferLimit = n
+}
+
+// Overflowed reports whether the body exceeded the `SetBufferLimit`
+// and it's no longer recorded.
+func (w *ResponseRecorder) Overflowed() bool {
+	return w.overflowed
+}
+
+// overflow sends the recorded response to the client and stops the recording.
+func (w *ResponseRecorder) overflow() {
+	w.overflowed = true
+	w.flushHooks = w.flushHooks[0:0]
+	w.copyHeaders()
+	if len(w.chunks) > 0 {
+		w.ResponseWriter.Write(w.chunks)
+	}
+	w.ResetBody()
+}
+
 // ResetBody resets the response body.
 func (w *ResponseRecorder) ResetBody() {
 	w.chunks = w.chunks[0:0]
@@ -146,21 +189,41 @@ func (w *ResponseRecorder) Reset() {
 	w.ResetBody()
 }
 
+// OnFlush registers a hook which transforms the recorded body right before it's sent to the client,
+// i.e to minify the HTML, inject the CSP nonces or rewrite the links of the responses
+// of all the next handlers, without their cooperation:
+//
+// func minifyHTML(ctx context.Context) {
+// 	ctx.Recorder().OnFlush(func(body []byte) []byte {
+// 		if ctx.GetContentType() != context.ContentHTMLHeaderValue {
+// 			return body
+// 		}
+// 		return minify(body)
+// 	})
+// 	ctx.Next()
+// }
+//
+// The hooks are executed by order of registration, each one receives the result of the previous one.
+// The "Content-Length" header is removed if the body was transformed.
+func (w *ResponseRecorder) OnFlush(hook func(body []byte) []byte) {
+	w.flushHooks = append(w.flushHooks, hook)
+}
+
 // FlushResponse the full body, headers and status code to the underline response writer
 // called automatically at the end of each request.
 func (w *ResponseRecorder) FlushResponse() {
-	// copy the headers to the underline response writer
-	if w.headers != nil {
-		h := w.ResponseWriter.Header()
-
-		for k, values := range w.headers {
-			h[k] = nil
-			for i := range values {
-				h.Add(k, values[i])
-			}
+	if len(w.flushHooks) > 0 {
+		for _, hook := range w.flushHooks {
+			w.chunks = hook(w.chunks)
+		}
+
+		if w.headers != nil {
+			w.headers.Del(ContentLengthHeaderKey)
 		}
 	}
 
+	w.copyHeaders()
+
 	// NOTE: before the ResponseWriter.Write in order to:
 	// set the given status code even if the body is empty.
 	w.ResponseWriter.FlushResponse()
@@ -171,12 +234,30 @@ func (w *ResponseRecorder) FlushResponse() {
 	}
 }
 
+// copyHeaders copies the headers to the underline response writer.
+func (w *ResponseRecorder) copyHeaders() {
+	if w.headers == nil {
+		return
+	}
+
+	h := w.ResponseWriter.Header()
+	for k, values := range w.headers {
+		h[k] = nil
+		for i := range values {
+			h.Add(k, values[i])
+		}
+	}
+}
+
 // Clone returns a clone of this response writer
-// it copies the header, status code, headers and the beforeFlush finally  returns a new ResponseRecorder
+// it copies the header, status code, headers and the beforeFlush finally  returns a new ResponseRecorder,
+// its body is empty, so the transactions record only their own response.
 func (w *ResponseRecorder) Clone() ResponseWriter {
 	wc := &ResponseRecorder{}
 	wc.headers = w.headers
-	wc.chunks = w.chunks[0:]
+	wc.flushHooks = append([]func(body []byte) []byte(nil), w.flushHooks...)
+	wc.bufferLimit = w.bufferLimit
+	wc.overflowed = w.overflowed
 	if resW, ok := w.ResponseWriter.(*responseWriter); ok {
 		wc.ResponseWriter = &(*resW) // clone it
 	} else { // else just copy, may pointer, developer can change its behavior
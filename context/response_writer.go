@@ -293,9 +293,13 @@ func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 		return h.Hijack()
 	}
 
-	return nil, nil, errors.New("hijack is not supported by this ResponseWriter")
+	return nil, nil, ErrHijackNotSupported
 }
 
+// ErrHijackNotSupported is returned by the Hijack method to
+// indicate that the underline ResponseWriter can not be hijacked, i.e on HTTP/2.
+var ErrHijackNotSupported = errors.New("hijack is not supported by this ResponseWriter")
+
 // Flush sends any buffered data to the client.
 func (w *responseWriter) Flush() {
 	// The Flusher interface is implemented by ResponseWriters that allow
@@ -0,0 +1,27 @@
+package context
+
+import (
+	"io"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// Scanner is the interface which upload scanners, i.e an anti-virus, should implement.
+// The `Context#UploadFormFiles`, `FormFile`, `FormFiles` and `ReadMultipart` scan each file
+// through the configured scanner before it is persisted or handed to the caller, see `iris#WithUploadScanner`.
+//
+// Scan should return a non-nil error when the contents of "r" are rejected.
+type Scanner interface {
+	Scan(r io.Reader) error
+}
+
+// ScannerFunc is the functional form of the `Scanner`.
+type ScannerFunc func(r io.Reader) error
+
+// Scan calls the function itself.
+func (s ScannerFunc) Scan(r io.Reader) error {
+	return s(r)
+}
+
+// ErrScanRejected is returned by the upload helpers when the configured `Scanner` rejected a file.
+var ErrScanRejected = errors.New("upload: file '%s' rejected by the scanner: %s")
@@ -0,0 +1,199 @@
+package context
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// SecureCookieKey is a key pair of the `SecureCookie`.
+type SecureCookieKey struct {
+	// HashKey signs the cookie's value with HMAC-SHA256, it's required.
+	// It should be at least 32 random bytes.
+	HashKey []byte
+	// BlockKey encrypts the cookie's value with AES-GCM, it's optional.
+	// It should be 16, 24 or 32 random bytes to select AES-128, AES-192 or AES-256.
+	BlockKey []byte
+}
+
+// secureCookieMinHashKeyLen is the minimum length of the `SecureCookieKey#HashKey`.
+const secureCookieMinHashKeyLen = 32
+
+// Validate returns an `ErrSecureCookieKey` error if the key pair
+// has a short hash key or a block key of an invalid length.
+func (k SecureCookieKey) Validate() error {
+	if len(k.HashKey) < secureCookieMinHashKeyLen {
+		return ErrSecureCookieKey.Format("the hash key should be at least 32 bytes")
+	}
+
+	switch len(k.BlockKey) {
+	case 0, 16, 24, 32:
+		return nil
+	default:
+		return ErrSecureCookieKey.Format("the block key should be 16, 24 or 32 bytes")
+	}
+}
+
+// SecureCookie signs and optionally encrypts the values of the `Context#SetSecureCookie`
+// and verifies them on the `Context#GetSecureCookie`, see `iris#WithSecureCookie`.
+type SecureCookie struct {
+	// Keys are the key pairs, the first one encodes the new values
+	// and all of them are tried to decode the received ones, so the keys can be rotated
+	// by prepending the new key pair and removing the oldest one after the cookies' lifetime.
+	Keys []SecureCookieKey
+	// MaxAge rejects the values which are older than this duration, they are
+	// rejected even if the client did not delete the cookie.
+	// It's the expiration of the cookie too.
+	// Zero means no limit.
+	MaxAge time.Duration
+}
+
+var (
+	// ErrSecureCookieNotConfigured is returned by the `Context#SetSecureCookie` and `Context#GetSecureCookie`
+	// when no `SecureCookie` was configured, see `iris#WithSecureCookie`.
+	ErrSecureCookieNotConfigured = errors.New("secure cookie: no keys configured")
+	// ErrSecureCookieKey is returned by the `Context#SetSecureCookie` and `Context#GetSecureCookie`
+	// when one of the configured key pairs is invalid, see `SecureCookieKey#Validate`.
+	ErrSecureCookieKey = errors.New("secure cookie: invalid key: %s")
+	// ErrSecureCookieInvalid is returned by the `Context#GetSecureCookie`
+	// when the cookie's value is malformed or it's tampered.
+	ErrSecureCookieInvalid = errors.New("secure cookie: '%s' is invalid")
+	// ErrSecureCookieExpired is returned by the `Context#GetSecureCookie`
+	// when the cookie's value is older than the `SecureCookie#MaxAge`.
+	ErrSecureCookieExpired = errors.New("secure cookie: '%s' is expired")
+)
+
+var secureCookieEncoding = base64.RawURLEncoding
+
+// Validate returns an error if no key pairs are configured or one of them is invalid,
+// see `SecureCookieKey#Validate`.
+func (s *SecureCookie) Validate() error {
+	if s == nil || len(s.Keys) == 0 {
+		return ErrSecureCookieNotConfigured
+	}
+
+	for _, key := range s.Keys {
+		if err := key.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Encode signs and encrypts, if a block key is set, the "value" of the "name" cookie.
+func (s *SecureCookie) Encode(name, value string) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	key := s.Keys[0]
+
+	// the payload is the timestamp followed by the value.
+	payload := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint64(payload, uint64(time.Now().Unix()))
+	payload = append(payload, value...)
+
+	if len(key.BlockKey) > 0 {
+		gcm, err := newSecureCookieGCM(key.BlockKey)
+		if err != nil {
+			return "", err
+		}
+
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", err
+		}
+
+		payload = gcm.Seal(nonce, nonce, payload, []byte(name))
+	}
+
+	encoded := secureCookieEncoding.EncodeToString(payload)
+	return encoded + "." + secureCookieEncoding.EncodeToString(secureCookieMAC(key.HashKey, name, encoded)), nil
+}
+
+// Decode verifies and decrypts the "encoded" value of the "name" cookie.
+func (s *SecureCookie) Decode(name, encoded string) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	idx := strings.LastIndexByte(encoded, '.')
+	if idx == -1 {
+		return "", ErrSecureCookieInvalid.Format(name)
+	}
+
+	mac, err := secureCookieEncoding.DecodeString(encoded[idx+1:])
+	if err != nil {
+		return "", ErrSecureCookieInvalid.Format(name)
+	}
+
+	payload, err := secureCookieEncoding.DecodeString(encoded[:idx])
+	if err != nil {
+		return "", ErrSecureCookieInvalid.Format(name)
+	}
+
+	for _, key := range s.Keys {
+		if !hmac.Equal(mac, secureCookieMAC(key.HashKey, name, encoded[:idx])) {
+			continue
+		}
+
+		if len(key.BlockKey) > 0 {
+			gcm, err := newSecureCookieGCM(key.BlockKey)
+			if err != nil {
+				return "", err
+			}
+
+			if len(payload) < gcm.NonceSize() {
+				return "", ErrSecureCookieInvalid.Format(name)
+			}
+
+			nonce := payload[:gcm.NonceSize()]
+			if payload, err = gcm.Open(nil, nonce, payload[gcm.NonceSize():], []byte(name)); err != nil {
+				return "", ErrSecureCookieInvalid.Format(name)
+			}
+		}
+
+		if len(payload) < 8 {
+			return "", ErrSecureCookieInvalid.Format(name)
+		}
+
+		if s.MaxAge > 0 {
+			created := time.Unix(int64(binary.BigEndian.Uint64(payload[:8])), 0)
+			if time.Since(created) > s.MaxAge {
+				return "", ErrSecureCookieExpired.Format(name)
+			}
+		}
+
+		return string(payload[8:]), nil
+	}
+
+	return "", ErrSecureCookieInvalid.Format(name)
+}
+
+// secureCookieMAC returns the signature of the "name" and its "value".
+func secureCookieMAC(hashKey []byte, name, value string) []byte {
+	h := hmac.New(sha256.New, hashKey)
+	h.Write([]byte(name))
+	h.Write([]byte{'|'})
+	h.Write([]byte(value))
+	return h.Sum(nil)
+}
+
+func newSecureCookieGCM(blockKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(blockKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
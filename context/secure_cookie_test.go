@@ -0,0 +1,116 @@
+package context
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+var (
+	testHashKey  = bytes.Repeat([]byte("h"), 32)
+	testBlockKey = bytes.Repeat([]byte("b"), 32)
+)
+
+func TestSecureCookieRoundTrip(t *testing.T) {
+	for _, key := range []SecureCookieKey{
+		{HashKey: testHashKey},
+		{HashKey: testHashKey, BlockKey: testBlockKey},
+	} {
+		s := &SecureCookie{Keys: []SecureCookieKey{key}}
+		encoded, err := s.Encode("session", "value")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(key.BlockKey) > 0 && bytes.Contains([]byte(encoded), []byte("dmFsdWU")) { // base64 of "value".
+			t.Fatalf("expected the value to be encrypted but got: %s", encoded)
+		}
+
+		got, err := s.Decode("session", encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != "value" {
+			t.Fatalf("expected the decoded value to be 'value' but got: '%s'", got)
+		}
+	}
+}
+
+func TestSecureCookieTampered(t *testing.T) {
+	s := &SecureCookie{Keys: []SecureCookieKey{{HashKey: testHashKey, BlockKey: testBlockKey}}}
+	encoded, err := s.Encode("session", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flipped := []byte(encoded)
+	if flipped[0] == 'A' {
+		flipped[0] = 'B'
+	} else {
+		flipped[0] = 'A'
+	}
+
+	for _, tt := range []struct{ name, encoded string }{
+		{"session", string(flipped)},
+		{"session", encoded[:len(encoded)-2]},
+		{"session", "malformed"},
+		{"other", encoded}, // the name is signed too.
+	} {
+		if _, err = s.Decode(tt.name, tt.encoded); err == nil {
+			t.Fatalf("expected the tampered value '%s' of '%s' to be rejected", tt.encoded, tt.name)
+		}
+	}
+}
+
+func TestSecureCookieKeyRotation(t *testing.T) {
+	oldKey := SecureCookieKey{HashKey: bytes.Repeat([]byte("o"), 32)}
+	newKey := SecureCookieKey{HashKey: testHashKey}
+
+	encoded, err := (&SecureCookie{Keys: []SecureCookieKey{oldKey}}).Encode("session", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := (&SecureCookie{Keys: []SecureCookieKey{newKey, oldKey}}).Decode("session", encoded); err != nil || got != "value" {
+		t.Fatalf("expected the old key to be still accepted but got: '%s', %v", got, err)
+	}
+
+	if _, err = (&SecureCookie{Keys: []SecureCookieKey{newKey}}).Decode("session", encoded); err == nil {
+		t.Fatalf("expected the removed key to be rejected")
+	}
+}
+
+func TestSecureCookieExpired(t *testing.T) {
+	s := &SecureCookie{Keys: []SecureCookieKey{{HashKey: testHashKey}}, MaxAge: time.Hour}
+
+	payload := make([]byte, 8, 8+len("value"))
+	binary.BigEndian.PutUint64(payload, uint64(time.Now().Add(-2*time.Hour).Unix()))
+	payload = append(payload, "value"...)
+	encoded := secureCookieEncoding.EncodeToString(payload)
+	encoded += "." + secureCookieEncoding.EncodeToString(secureCookieMAC(testHashKey, "session", encoded))
+
+	if _, err := s.Decode("session", encoded); err == nil {
+		t.Fatalf("expected the old value to be expired")
+	}
+}
+
+func TestSecureCookieInvalidKeys(t *testing.T) {
+	for _, keys := range [][]SecureCookieKey{
+		nil,
+		{{}},
+		{{HashKey: []byte("short")}},
+		{{HashKey: testHashKey, BlockKey: []byte("not an aes key")}},
+		{{HashKey: testHashKey}, {HashKey: nil}},
+	} {
+		s := &SecureCookie{Keys: keys}
+		if _, err := s.Encode("session", "value"); err == nil {
+			t.Fatalf("expected the keys %v to be rejected on encode", keys)
+		}
+
+		if _, err := s.Decode("session", "value.mac"); err == nil {
+			t.Fatalf("expected the keys %v to be rejected on decode", keys)
+		}
+	}
+}
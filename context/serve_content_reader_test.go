@@ -0,0 +1,107 @@
+package context_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+func TestServeContentReader(t *testing.T) {
+	var (
+		content  = strings.Repeat("0123456789", 10000)
+		modtime  = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+		progress [][2]int64
+	)
+
+	onProgress := func(transferred, total int64) {
+		progress = append(progress, [2]int64{transferred, total})
+	}
+
+	expectProgress := func(transferred, total int64) {
+		t.Helper()
+		if len(progress) == 0 {
+			if transferred != 0 {
+				t.Fatalf("expected %d bytes to be reported but nothing was reported", transferred)
+			}
+			return
+		}
+
+		for i := 1; i < len(progress); i++ {
+			if progress[i][0] <= progress[i-1][0] {
+				t.Fatalf("expected the progress to increase but got: %v", progress)
+			}
+		}
+
+		if last := progress[len(progress)-1]; last[0] != transferred || last[1] != total {
+			t.Fatalf("expected the last progress to be %d/%d but got %d/%d", transferred, total, last[0], last[1])
+		}
+		progress = nil
+	}
+
+	app := iris.New()
+	app.Get("/{name}", func(ctx context.Context) {
+		var r io.Reader = strings.NewReader(content)
+		if !ctx.URLParamExists("seek") {
+			// hide the io.Seeker of the strings.Reader.
+			r = struct{ io.Reader }{r}
+		}
+
+		size := ctx.URLParamInt64Default("size", int64(len(content)))
+		if err := ctx.ServeContentReader(r, ctx.Params().Get("name"), size, modtime, onProgress); err != nil {
+			t.Error(err)
+		}
+	})
+	app.Head("/{name}", func(ctx context.Context) {
+		ctx.ServeContentReader(struct{ io.Reader }{strings.NewReader(content)}, ctx.Params().Get("name"), int64(len(content)), modtime, onProgress)
+	})
+
+	e := httptest.New(t, app)
+	// the Last-Modified is formatted by the application's TimeFormat, except by the http.ServeContent.
+	lastModified := modtime.Format(app.ConfigurationReadOnly().GetTimeFormat())
+
+	resp := e.GET("/data.txt").Expect().Status(iris.StatusOK)
+	resp.ContentType("text/plain", "utf-8")
+	resp.Header(context.ContentLengthHeaderKey).Equal("100000")
+	resp.Header(context.LastModifiedHeaderKey).Equal(lastModified)
+	resp.Body().Equal(content)
+	expectProgress(100000, 100000)
+
+	// only the "size" bytes are sent.
+	e.GET("/data.unknown").WithQuery("size", 10).Expect().Status(iris.StatusOK).
+		ContentType(context.ContentBinaryHeaderValue, "").Body().Equal("0123456789")
+	expectProgress(10, 10)
+
+	// the unknown size.
+	resp = e.GET("/data.txt").WithQuery("size", -1).Expect().Status(iris.StatusOK)
+	resp.Body().Equal(content)
+	expectProgress(100000, -1)
+
+	e.GET("/data.txt").WithHeader(context.IfModifiedSinceHeaderKey, lastModified).Expect().
+		Status(iris.StatusNotModified).Body().Empty()
+	expectProgress(0, 0)
+
+	e.HEAD("/data.txt").Expect().Status(iris.StatusOK).
+		Header(context.ContentLengthHeaderKey).Equal("100000")
+	expectProgress(0, 0)
+
+	// the io.ReadSeeker serves the range requests.
+	resp = e.GET("/data.txt").WithQuery("seek", true).WithHeader("Range", "bytes=10-29").Expect().
+		Status(iris.StatusPartialContent)
+	resp.Header("Content-Range").Equal("bytes 10-29/100000")
+	resp.Body().Equal(content[10:30])
+	expectProgress(20, 100000)
+
+	e.GET("/data.txt").WithQuery("seek", true).Expect().Status(iris.StatusOK).
+		Header(context.LastModifiedHeaderKey).Equal(modtime.Format(http.TimeFormat))
+	expectProgress(100000, 100000)
+
+	e.GET("/data.txt").WithQuery("seek", true).WithHeader(context.IfModifiedSinceHeaderKey, modtime.Format(http.TimeFormat)).Expect().
+		Status(iris.StatusNotModified).Body().Empty()
+	expectProgress(0, 0)
+}
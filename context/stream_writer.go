@@ -0,0 +1,147 @@
+package context
+
+import (
+	"bufio"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StreamOptions contains the options of the `Context#Stream`.
+type StreamOptions struct {
+	// FlushInterval is the period of the flushes of the buffered data to the client,
+	// they are sent even while the stream's function waits for new data.
+	// Zero means that the data are flushed after each call of the stream's function.
+	// The `StreamWriter#Flush` sends the data immediately.
+	FlushInterval time.Duration
+	// BufferSize is the size of the write buffer, in bytes,
+	// the buffer is flushed earlier when it's full.
+	//
+	// Defaults to 4096.
+	BufferSize int
+}
+
+// StreamWriter is the buffered response writer of the `Context#Stream`.
+// It is safe for concurrent use, the stream's function should write the response through it only
+// because the `StreamOptions#FlushInterval` flushes it from another goroutine.
+type StreamWriter struct {
+	ctx *context
+	mu  sync.Mutex
+	buf *bufio.Writer
+	err error
+}
+
+// Write writes "b" to the buffer, it implements the `io.Writer`.
+func (w *StreamWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n, err := w.buf.Write(b)
+	if err != nil {
+		w.err = err
+	}
+
+	return n, err
+}
+
+// WriteString writes "s" to the buffer.
+func (w *StreamWriter) WriteString(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n, err := w.buf.WriteString(s)
+	if err != nil {
+		w.err = err
+	}
+
+	return n, err
+}
+
+// Writef formats according to a format specifier and writes to the buffer.
+func (w *StreamWriter) Writef(format string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(w, format, a...)
+}
+
+// Flush sends the buffered data to the client.
+func (w *StreamWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.flush()
+}
+
+func (w *StreamWriter) flush() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		w.err = err
+		return err
+	}
+
+	w.ctx.writer.Flush()
+	return nil
+}
+
+// Done returns a channel which is closed when the client is gone,
+// the long-polling functions should select on it while they wait for new data.
+func (w *StreamWriter) Done() <-chan struct{} {
+	return w.ctx.request.Context().Done()
+}
+
+// Gone reports whether the client is gone.
+func (w *StreamWriter) Gone() bool {
+	select {
+	case <-w.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// Err returns the first write error, if any.
+func (w *StreamWriter) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// flushEvery flushes the buffered data, if any, every "interval"
+// until the returned function is called, which waits for the last flush to finish.
+func (w *StreamWriter) flushEvery(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				w.mu.Lock()
+				if w.buf.Buffered() > 0 {
+					w.flush()
+				}
+				w.mu.Unlock()
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,79 @@
+package context_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+)
+
+func TestStream(t *testing.T) {
+	received := make(chan struct{}, 1)
+
+	app := iris.New()
+	app.Get("/{interval:int}", func(ctx context.Context) {
+		interval, _ := ctx.Params().GetInt("interval")
+		calls := 0
+		err := ctx.Stream(func(w *context.StreamWriter) bool {
+			calls++
+			switch calls {
+			case 1:
+				w.WriteString("a\n")
+				return true
+			case 2:
+				// waits for new data, the "a" should be sent meanwhile.
+				select {
+				case <-received:
+					w.WriteString("b\n")
+				case <-time.After(2 * time.Second):
+					w.WriteString("timeout\n")
+				}
+				return true
+			default:
+				w.WriteString("c\n")
+				return false
+			}
+		}, context.StreamOptions{FlushInterval: time.Duration(interval) * time.Millisecond})
+
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+
+	// zero flushes after each call and the interval from the ticker.
+	for _, interval := range []string{"0", "10"} {
+		resp, err := http.Get(srv.URL + "/" + interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := bufio.NewReader(resp.Body)
+		line, err := r.ReadString('\n')
+		if err != nil || line != "a\n" {
+			t.Fatalf("%s: expected the first line to be sent before the next data but got '%s': %v", interval, line, err)
+		}
+		received <- struct{}{}
+
+		rest, err := ioutil.ReadAll(r)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(rest) != "b\nc\n" {
+			t.Fatalf("%s: expected the rest of the lines but got '%s'", interval, rest)
+		}
+	}
+}
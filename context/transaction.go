@@ -18,9 +18,10 @@ func (err TransactionErrResult) Error() string {
 	return err.Reason
 }
 
-// IsFailure returns true if this is an actual error
+// IsFailure returns true if this is an actual error,
+// the zero status code of a completed, without error, transaction is not a failure.
 func (err TransactionErrResult) IsFailure() bool {
-	return StatusCodeNotSuccessful(err.StatusCode)
+	return err.StatusCode > 0 && StatusCodeNotSuccessful(err.StatusCode)
 }
 
 // NewTransactionErrResult returns a new transaction result with the given error message,
@@ -64,6 +65,19 @@ type Transaction struct {
 	parent   Context
 	hasError bool
 	scope    TransactionScope
+	// outer is the parent transaction of a nested transaction, see `Transaction#BeginTransaction`.
+	outer *Transaction
+	// rollbacks are registered by the `OnRollback`,
+	// the ones of the succeeded nested transactions are moved to their outer transaction.
+	rollbacks []func()
+	// capturePanics is set by the `CapturePanics`.
+	capturePanics bool
+	// innerErr is the error of a failed nested transaction whose scope did not allow to continue,
+	// or of a captured panic of a nested transaction.
+	innerErr error
+	// nested are the contexts of the completed nested transactions,
+	// they are given the original writer when this transaction completes too.
+	nested []Context
 }
 
 func newTransaction(from *context) *Transaction {
@@ -90,13 +104,53 @@ func (t *Transaction) SetScope(scope TransactionScope) {
 	t.scope = scope
 }
 
+// OnRollback registers a callback which undoes a step of the transaction,
+// i.e deletes a created record or releases a reservation.
+// The callbacks are executed in the reverse order of their registration when the transaction fails,
+// or when its outer transaction fails if it's a nested one.
+func (t *Transaction) OnRollback(rollback func()) {
+	t.rollbacks = append(t.rollbacks, rollback)
+}
+
+// CapturePanics makes the panics of the transaction's handlers, and of its nested transactions,
+// to fail the transaction with a 500 Internal Server Error, so its rollbacks are executed
+// and its scope decides what to do next.
+// By default a panic is logged and the transaction completes as if it succeeded.
+func (t *Transaction) CapturePanics() {
+	t.capturePanics = true
+}
+
+// BeginTransaction starts a nested transaction, its response is written to this transaction's response.
+//
+// If it succeeds then its rollbacks are moved to this transaction,
+// so they are executed if this transaction fails later on.
+// If it fails and its scope does not allow to continue, i.e the `RequestTransactionScope`,
+// then this transaction fails too, when it completes, with the nested transaction's error.
+func (t *Transaction) BeginTransaction(pipe func(t *Transaction)) {
+	if ctx, ok := t.context.(*context); ok {
+		ctx.beginTransaction(pipe, t)
+	}
+}
+
+// rollback executes the registered rollbacks once, in the reverse order.
+func (t *Transaction) rollback() {
+	for i := len(t.rollbacks) - 1; i >= 0; i-- {
+		t.rollbacks[i]()
+	}
+	t.rollbacks = nil
+}
+
 // Complete completes the transaction
 // rollback and send an error when the error is not empty.
 // The next steps depends on its Scope.
 //
 // The error can be a type of context.NewTransactionErrResult().
+// If it's nil but a nested transaction failed then the transaction fails with the nested one's error.
 func (t *Transaction) Complete(err error) {
 	maybeErr := TransactionErrResult{}
+	if err == nil && t.innerErr != nil {
+		err = t.innerErr
+	}
 
 	if err != nil {
 		t.hasError = true
@@ -122,7 +176,13 @@ func (t *Transaction) Complete(err error) {
 		maybeErr.StatusCode = statusCode
 		maybeErr.Reason = reason
 		maybeErr.ContentType = cType
+
+		t.rollback()
+	} else if t.outer != nil {
+		t.outer.rollbacks = append(t.outer.rollbacks, t.rollbacks...)
+		t.rollbacks = nil
 	}
+
 	// the transaction ends with error or not error, it decides what to do next with its Response
 	// the Response is appended to the parent context an all cases but it checks for empty body,headers and all that,
 	// if they are empty (silent error or not error at all)
@@ -130,6 +190,9 @@ func (t *Transaction) Complete(err error) {
 	canContinue := t.scope.EndTransaction(maybeErr, t.context)
 	if !canContinue {
 		t.parent.SkipTransactions()
+		if t.outer != nil && err != nil {
+			t.outer.innerErr = err
+		}
 	}
 }
 
@@ -0,0 +1,212 @@
+package context_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+// testRollbacks records the executed rollbacks of a request.
+type testRollbacks struct {
+	mu       sync.Mutex
+	executed []string
+}
+
+func (r *testRollbacks) on(t *context.Transaction, name string) {
+	t.OnRollback(func() {
+		r.mu.Lock()
+		r.executed = append(r.executed, name)
+		r.mu.Unlock()
+	})
+}
+
+func (r *testRollbacks) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := strings.Join(r.executed, ",")
+	r.executed = nil
+	return s
+}
+
+func TestTransactionCommit(t *testing.T) {
+	app := iris.New()
+	rollbacks := new(testRollbacks)
+
+	app.Get("/", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "first")
+			t.Context().WriteString("first")
+			t.Complete(nil)
+		})
+
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "second")
+			t.Context().WriteString("second")
+		})
+
+		ctx.WriteString("-done")
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("firstsecond-done")
+
+	if got := rollbacks.String(); got != "" {
+		t.Fatalf("expected no rollbacks but got: %s", got)
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	app := iris.New()
+	rollbacks := new(testRollbacks)
+
+	app.Get("/transient", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "first")
+			rollbacks.on(t, "second")
+			t.Context().WriteString("failed")
+			t.Complete(errors.New("failure"))
+		})
+
+		// the transient scope allows the next transactions.
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			t.Context().WriteString("next")
+		})
+	})
+
+	app.Get("/request", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			t.SetScope(context.RequestTransactionScope)
+			rollbacks.on(t, "first")
+			t.Complete(context.TransactionErrResult{StatusCode: iris.StatusConflict, Reason: "conflict"})
+		})
+
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "skipped")
+			t.Context().WriteString("skipped")
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/transient").Expect().Status(iris.StatusOK).Body().Equal("next")
+	if got, expected := rollbacks.String(), "second,first"; got != expected {
+		t.Fatalf("expected the rollbacks '%s' but got: '%s'", expected, got)
+	}
+
+	e.GET("/request").Expect().Status(iris.StatusConflict).Body().Equal("conflict")
+	if got, expected := rollbacks.String(), "first"; got != expected {
+		t.Fatalf("expected the rollbacks '%s' but got: '%s'", expected, got)
+	}
+}
+
+func TestTransactionNested(t *testing.T) {
+	app := iris.New()
+	rollbacks := new(testRollbacks)
+
+	// the rollbacks of the succeeded nested transaction are executed when the outer one fails.
+	app.Get("/outer-fails", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "outer")
+			t.BeginTransaction(func(inner *context.Transaction) {
+				rollbacks.on(inner, "inner")
+				inner.Complete(nil)
+			})
+			t.Complete(errors.New("failure"))
+		})
+	})
+
+	// the failed nested transaction, whose scope does not allow to continue, fails its outer transaction
+	// and its error is the response.
+	app.Get("/inner-fails", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "outer")
+			t.BeginTransaction(func(inner *context.Transaction) {
+				rollbacks.on(inner, "first")
+				inner.Complete(nil)
+			})
+
+			t.BeginTransaction(func(inner *context.Transaction) {
+				inner.SetScope(context.RequestTransactionScope)
+				rollbacks.on(inner, "second")
+				inner.Complete(errors.New("failure"))
+			})
+
+			t.BeginTransaction(func(inner *context.Transaction) {
+				rollbacks.on(inner, "skipped")
+				inner.Context().WriteString("skipped")
+			})
+		})
+	})
+
+	// the failed nested transaction, of the transient scope, does not fail its outer transaction.
+	app.Get("/inner-transient", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "outer")
+			t.BeginTransaction(func(inner *context.Transaction) {
+				rollbacks.on(inner, "inner")
+				inner.Context().WriteString("failed")
+				inner.Complete(errors.New("failure"))
+			})
+			t.Context().WriteString("outer")
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/outer-fails").Expect().Status(iris.StatusOK).Body().Empty()
+	if got, expected := rollbacks.String(), "inner,outer"; got != expected {
+		t.Fatalf("expected the rollbacks '%s' but got: '%s'", expected, got)
+	}
+
+	e.GET("/inner-fails").Expect().Status(iris.StatusBadRequest).Body().Equal("failure")
+	if got, expected := rollbacks.String(), "second,first,outer"; got != expected {
+		t.Fatalf("expected the rollbacks '%s' but got: '%s'", expected, got)
+	}
+
+	e.GET("/inner-transient").Expect().Status(iris.StatusOK).Body().Equal("outer")
+	if got, expected := rollbacks.String(), "inner"; got != expected {
+		t.Fatalf("expected the rollbacks '%s' but got: '%s'", expected, got)
+	}
+}
+
+func TestTransactionPanic(t *testing.T) {
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	rollbacks := new(testRollbacks)
+
+	app.Get("/captured", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			t.SetScope(context.RequestTransactionScope)
+			t.CapturePanics()
+			rollbacks.on(t, "outer")
+			t.BeginTransaction(func(inner *context.Transaction) {
+				rollbacks.on(inner, "inner")
+				panic("inner panic")
+			})
+		})
+	})
+
+	app.Get("/not-captured", func(ctx context.Context) {
+		ctx.BeginTransaction(func(t *context.Transaction) {
+			rollbacks.on(t, "rollback")
+			t.Context().WriteString("written")
+			panic("panic")
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/captured").Expect().Status(iris.StatusInternalServerError).
+		Body().Equal(http.StatusText(iris.StatusInternalServerError))
+	if got, expected := rollbacks.String(), "inner,outer"; got != expected {
+		t.Fatalf("expected the rollbacks '%s' but got: '%s'", expected, got)
+	}
+
+	e.GET("/not-captured").Expect().Status(iris.StatusOK).Body().Equal("written")
+	if got := rollbacks.String(); got != "" {
+		t.Fatalf("expected no rollbacks but got: %s", got)
+	}
+}
@@ -0,0 +1,174 @@
+package context
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// UploadOptions contains the options of the `Context#UploadFormFilesWith`.
+type UploadOptions struct {
+	// MaxFileSize rejects the files with a greater size, in bytes.
+	// Zero means no limit.
+	MaxFileSize int64
+	// MaxTotalSize rejects the requests when the sum of their files' size is greater than this value, in bytes.
+	// Zero means no limit.
+	MaxTotalSize int64
+	// AllowedExtensions rejects the files whose filename's extension is not one of these, i.e ".png", ".jpg".
+	// The comparison is case-insensitive. Empty means any extension.
+	AllowedExtensions []string
+	// AllowedContentTypes rejects the files whose declared Content-Type is not one of these,
+	// i.e "image/png" or "image/*". Empty means any content type.
+	// Use it with the `iris#WithUploadContentTypeVerification` to verify that the contents match the declared type too.
+	AllowedContentTypes []string
+	// SanitizeFilename removes the directories, the control and the reserved characters
+	// and the leading dots of the client's filenames before the files are saved.
+	SanitizeFilename bool
+	// RandomFilename saves the files with a random name which keeps the original extension,
+	// it overrides the `SanitizeFilename`.
+	RandomFilename bool
+	// Before gives caller the chance to modify the *multipart.FileHeader before saving to the disk,
+	// see `Context#UploadFormFiles`.
+	// They run after the filename is sanitized or randomized.
+	Before []func(Context, *multipart.FileHeader)
+	// OnUploaded is called after each file is saved to the disk with its stored "path",
+	// a non-nil error stops the upload and it's returned back to the caller.
+	OnUploaded func(ctx Context, fh *multipart.FileHeader, path string) error
+}
+
+var (
+	// ErrUploadFileTooLarge is returned by the upload helpers
+	// when a file is larger than the `UploadOptions#MaxFileSize`.
+	ErrUploadFileTooLarge = errors.New("upload: file '%s' is larger than %d bytes")
+	// ErrUploadTotalTooLarge is returned by the upload helpers
+	// when the files of the request are larger than the `UploadOptions#MaxTotalSize`.
+	ErrUploadTotalTooLarge = errors.New("upload: files are larger than %d bytes")
+	// ErrUploadFileNotAllowed is returned by the upload helpers
+	// when a file's extension or content type is not allowed by the `UploadOptions`.
+	ErrUploadFileNotAllowed = errors.New("upload: file '%s': %s '%s' is not allowed")
+)
+
+// check returns an error if the "files" do not satisfy the options,
+// it should be called before any of them is saved.
+func (opts UploadOptions) check(files []*multipart.FileHeader) error {
+	var total int64
+	for _, fh := range files {
+		if opts.MaxFileSize > 0 && fh.Size > opts.MaxFileSize {
+			return ErrUploadFileTooLarge.Format(fh.Filename, opts.MaxFileSize)
+		}
+
+		total += fh.Size
+		if opts.MaxTotalSize > 0 && total > opts.MaxTotalSize {
+			return ErrUploadTotalTooLarge.Format(opts.MaxTotalSize)
+		}
+
+		if len(opts.AllowedExtensions) > 0 {
+			ext := strings.ToLower(filepath.Ext(fh.Filename))
+			if !containsFold(opts.AllowedExtensions, ext) {
+				return ErrUploadFileNotAllowed.Format(fh.Filename, "extension", ext)
+			}
+		}
+
+		if len(opts.AllowedContentTypes) > 0 {
+			typ := baseContentType(fh.Header.Get(ContentTypeHeaderKey))
+			if !matchesContentType(opts.AllowedContentTypes, typ) {
+				return ErrUploadFileNotAllowed.Format(fh.Filename, "content type", typ)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesContentType(allowed []string, typ string) bool {
+	typ = strings.ToLower(typ)
+	for _, a := range allowed {
+		if matchMediaType(strings.ToLower(a), typ) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxFilenameLength is the most common file system limit of a filename.
+const maxFilenameLength = 255
+
+// SanitizeFilename returns a safe to store version of the client's "filename",
+// without directories, control and reserved characters and leading dots, i.e
+// "../../etc/passwd" to "passwd" and "..\\a<b>.txt" to "ab.txt".
+// An empty result is replaced by a random name.
+func SanitizeFilename(filename string) string {
+	filename = strings.Replace(filename, "\\", "/", -1)
+	if idx := strings.LastIndexByte(filename, '/'); idx != -1 {
+		filename = filename[idx+1:]
+	}
+
+	filename = strings.Map(func(r rune) rune {
+		if r < 32 || r == 127 || strings.ContainsRune(`<>:"|?*`, r) {
+			return -1
+		}
+		return r
+	}, filename)
+
+	filename = strings.TrimLeft(strings.TrimSpace(filename), ".")
+	if filename == "" {
+		return RandomFilename("")
+	}
+
+	if len(filename) > maxFilenameLength {
+		ext := filepath.Ext(filename)
+		if len(ext) >= maxFilenameLength {
+			ext = ""
+		}
+
+		// truncate at a rune boundary, so the result is still valid UTF-8.
+		end := maxFilenameLength - len(ext)
+		for end > 0 && !utf8.RuneStart(filename[end]) {
+			end--
+		}
+		filename = filename[:end] + ext
+	}
+
+	return filename
+}
+
+// RandomFilename returns a random filename with the extension of the "filename", if any.
+func RandomFilename(filename string) string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	return hex.EncodeToString(b[:]) + safeExt(filename)
+}
+
+// safeExt returns the lowercase extension of the "filename"
+// or empty if it contains anything but letters and digits.
+func safeExt(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if len(ext) < 2 || len(ext) > 16 {
+		return ""
+	}
+
+	for _, r := range ext[1:] {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return ""
+		}
+	}
+
+	return ext
+}
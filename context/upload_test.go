@@ -0,0 +1,279 @@
+package context_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/iris-contrib/httpexpect"
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := map[string]string{
+		"../../etc/passwd":  "passwd",
+		"..\\a<b>.txt":      "ab.txt",
+		"  .hidden.txt":     "hidden.txt",
+		"name\x00\n.txt":    "name.txt",
+		"report 2018.pdf":   "report 2018.pdf",
+		"φωτογραφία.jpg":    "φωτογραφία.jpg",
+		"dir/sub/file.tar":  "file.tar",
+		"C:\\Users\\me.png": "me.png",
+	}
+
+	for filename, expected := range tests {
+		if got := context.SanitizeFilename(filename); got != expected {
+			t.Fatalf("expected '%s' to be sanitized to '%s' but got: '%s'", filename, expected, got)
+		}
+	}
+
+	for _, filename := range []string{"", "../..", "<>:\"|?*"} {
+		if got := context.SanitizeFilename(filename); len(got) != 32 {
+			t.Fatalf("expected the empty result of '%s' to be replaced by a random name but got: '%s'", filename, got)
+		}
+	}
+
+	// multi-byte runes are not split on truncation.
+	long := strings.Repeat("α", 200) + ".txt"
+	got := context.SanitizeFilename(long)
+	if len(got) > 255 || !utf8.ValidString(got) || !strings.HasSuffix(got, ".txt") {
+		t.Fatalf("expected a valid UTF-8 filename of at most 255 bytes with its extension but got: %q (%d bytes)", got, len(got))
+	}
+}
+
+func TestRandomFilename(t *testing.T) {
+	tests := map[string]string{
+		"photo.JPG":           ".jpg",
+		"archive.tar.gz":      ".gz",
+		"noext":               "",
+		"evil.php%00.png":     ".png",
+		"script.p/hp":         "",
+		"name.with space":     "",
+		"a.verylongextension": "",
+	}
+
+	for filename, ext := range tests {
+		got := context.RandomFilename(filename)
+		if len(got) != 32+len(ext) || !strings.HasSuffix(got, ext) {
+			t.Fatalf("expected a random name with the '%s' extension for '%s' but got: '%s'", ext, filename, got)
+		}
+	}
+
+	if context.RandomFilename("a.txt") == context.RandomFilename("a.txt") {
+		t.Fatalf("expected the random names to be different")
+	}
+}
+
+// testMultipart returns a multipart body of the "files", filename to contents, and its content type.
+func testMultipart(t *testing.T, files map[string]string) ([]byte, string) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	for filename, contents := range files {
+		part, err := w.CreateFormFile("files", filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(contents))
+	}
+
+	w.WriteField("name", "value")
+	w.Close()
+	return b.Bytes(), w.FormDataContentType()
+}
+
+func TestUploadFormFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var uploaded []string
+
+	app := iris.New()
+	app.Post("/", func(ctx context.Context) {
+		n, err := ctx.UploadFormFiles(dir)
+		if err != nil {
+			ctx.StatusCode(http.StatusBadRequest)
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.Writef("%d", n)
+	})
+
+	app.Post("/options", func(ctx context.Context) {
+		n, err := ctx.UploadFormFilesWith(dir, context.UploadOptions{
+			MaxFileSize:       8,
+			MaxTotalSize:      12,
+			AllowedExtensions: []string{".txt"},
+			SanitizeFilename:  true,
+			OnUploaded: func(ctx context.Context, fh *multipart.FileHeader, path string) error {
+				uploaded = append(uploaded, path)
+				return nil
+			},
+		})
+		if err != nil {
+			if ctx.GetStatusCode() == http.StatusOK {
+				ctx.StatusCode(http.StatusBadRequest)
+			}
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.Writef("%d", n)
+	})
+
+	e := httptest.New(t, app)
+	post := func(path string, files map[string]string) *httpexpect.Response {
+		body, contentType := testMultipart(t, files)
+		return e.POST(path).WithHeader("Content-Type", contentType).WithBytes(body).Expect()
+	}
+
+	post("/", map[string]string{"a.txt": "a longer content"}).Status(http.StatusOK).Body().Equal("16")
+	// the existing file is truncated.
+	post("/", map[string]string{"a.txt": "short"}).Status(http.StatusOK).Body().Equal("5")
+	if b, _ := ioutil.ReadFile(filepath.Join(dir, "a.txt")); string(b) != "short" {
+		t.Fatalf("expected the existing file to be overridden but got: '%s'", b)
+	}
+
+	// a multipart form without files is not an error.
+	post("/", nil).Status(http.StatusOK).Body().Equal("0")
+
+	post("/options", map[string]string{"../b.txt": "12345", "c.txt": "1234"}).Status(http.StatusOK).Body().Equal("9")
+	if len(uploaded) != 2 || uploaded[0] != filepath.Join(dir, "b.txt") && uploaded[1] != filepath.Join(dir, "b.txt") {
+		t.Fatalf("expected the sanitized paths to be reported but got: %v", uploaded)
+	}
+
+	post("/options", map[string]string{"d.txt": "123456789"}).Status(http.StatusRequestEntityTooLarge)
+	post("/options", map[string]string{"d.txt": "12345678", "e.txt": "12345"}).Status(http.StatusRequestEntityTooLarge)
+	post("/options", map[string]string{"d.exe": "1"}).Status(http.StatusUnsupportedMediaType)
+	if _, err = os.Stat(filepath.Join(dir, "d.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected the rejected files to not be saved")
+	}
+}
+
+func TestFormDigest(t *testing.T) {
+	app := iris.New()
+	app.Post("/", func(ctx context.Context) {
+		if v := ctx.FormValue("name"); v != "" {
+			ctx.Writef("%s %s", v, ctx.BodyDigest())
+		}
+	})
+	app.Post("/read", func(ctx context.Context) {
+		var form struct {
+			Name string `form:"name"`
+		}
+		if err := ctx.ReadForm(&form); err != nil {
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.WriteString(form.Name)
+	})
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	w.WriteField("name", "kataras")
+	w.Close()
+	sum := sha256.Sum256(b.Bytes())
+	digest := "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+
+	e := httptest.New(t, app)
+	post := func(path, digest string) *httpexpect.Response {
+		return e.POST(path).WithHeader("Content-Type", w.FormDataContentType()).
+			WithHeader("Digest", digest).WithBytes(b.Bytes()).Expect()
+	}
+
+	post("/", digest).Status(http.StatusOK).Body().Equal("kataras " + digest)
+	post("/read", digest).Status(http.StatusOK).Body().Equal("kataras")
+	// the values of a body which does not match its digest are not available.
+	post("/", "SHA-256=invalid").Status(http.StatusBadRequest).Body().NotContains("kataras")
+	post("/read", "SHA-256=invalid").Status(http.StatusBadRequest).Body().Contains("digest mismatch")
+}
+
+func TestUploadScanner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-upload-scanner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var scanned int
+	app := iris.New()
+	app.Configure(iris.WithUploadScanner(context.ScannerFunc(func(r io.Reader) error {
+		scanned++
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(b, []byte("virus")) {
+			return errors.New("infected")
+		}
+		return nil
+	})))
+
+	reply := func(ctx context.Context, err error) {
+		if err != nil {
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.WriteString("clean")
+	}
+
+	app.Post("/upload", func(ctx context.Context) {
+		_, err := ctx.UploadFormFiles(dir)
+		reply(ctx, err)
+	})
+	app.Post("/file", func(ctx context.Context) {
+		f, _, err := ctx.FormFile("files")
+		if err == nil {
+			f.Close()
+		}
+		reply(ctx, err)
+	})
+	app.Post("/files", func(ctx context.Context) {
+		reply(ctx, ctx.FormFiles(func(fh *multipart.FileHeader) error { return nil }))
+	})
+	app.Post("/read", func(ctx context.Context) {
+		var form struct {
+			Files []*multipart.FileHeader `part:"files"`
+		}
+		reply(ctx, ctx.ReadMultipart(&form))
+	})
+	app.Post("/stream", func(ctx context.Context) {
+		reply(ctx, ctx.MultipartStream(func(part *multipart.Part) error { return nil }))
+	})
+
+	e := httptest.New(t, app)
+	for _, path := range []string{"/upload", "/file", "/files", "/read"} {
+		body, contentType := testMultipart(t, map[string]string{"a.txt": "safe"})
+		e.POST(path).WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+			Status(http.StatusOK).Body().Equal("clean")
+
+		body, contentType = testMultipart(t, map[string]string{"b.txt": "a virus"})
+		e.POST(path).WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+			Status(http.StatusUnprocessableEntity).Body().Equal("upload: file 'b.txt' rejected by the scanner: infected")
+	}
+
+	if expected := 8; scanned != expected {
+		t.Fatalf("expected %d scanned files but got %d", expected, scanned)
+	}
+
+	// the streamed parts are not scanned.
+	body, contentType := testMultipart(t, map[string]string{"b.txt": "a virus"})
+	e.POST("/stream").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(http.StatusOK).Body().Equal("clean")
+	if _, err = os.Stat(filepath.Join(dir, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected the rejected file to not be saved")
+	}
+}
@@ -0,0 +1,186 @@
+package context
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// The device classes of the `UserAgentInfo`.
+const (
+	// DeviceDesktop is the device class of the desktop browsers and of the unknown, non-empty, user agents.
+	DeviceDesktop = "desktop"
+	// DeviceMobile is the device class of the phones.
+	DeviceMobile = "mobile"
+	// DeviceTablet is the device class of the tablets.
+	DeviceTablet = "tablet"
+	// DeviceBot is the device class of the crawlers and the non-browser clients.
+	DeviceBot = "bot"
+)
+
+// UserAgentInfo is the parsed "User-Agent" request header, see `Context#UserAgentInfo`.
+type UserAgentInfo struct {
+	// Raw is the "User-Agent" header value.
+	Raw string
+	// Browser is the name of the browser, i.e "Chrome", if known.
+	Browser string
+	// BrowserVersion is the version of the browser, if known.
+	BrowserVersion string
+	// OS is the name of the operating system, i.e "Android", if known.
+	OS string
+	// OSVersion is the version of the operating system, if known.
+	OSVersion string
+	// Device is the device class, one of the `DeviceDesktop`, `DeviceMobile`, `DeviceTablet`
+	// and `DeviceBot`, it's empty if the header is missing.
+	Device string
+	// Bot is the name of the crawler, i.e "Googlebot", if the device is a bot.
+	Bot string
+}
+
+// UserAgentRule is a named regular expression of the `UserAgentRules`,
+// the expressions are case-insensitive and their first group, if any, is the version.
+type UserAgentRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+
+	re *regexp.Regexp
+}
+
+// UserAgentRules is the ruleset of the `Context#UserAgentInfo`,
+// each group is checked in order and its first matched rule wins.
+//
+// The `DefaultUserAgentRules` is used by default, an updated ruleset,
+// i.e an embedded JSON file, can be set by the `iris#WithUserAgentRules`.
+type UserAgentRules struct {
+	Bots     []UserAgentRule `json:"bots"`
+	Tablets  []UserAgentRule `json:"tablets"`
+	Mobiles  []UserAgentRule `json:"mobiles"`
+	Browsers []UserAgentRule `json:"browsers"`
+	OS       []UserAgentRule `json:"os"`
+
+	once sync.Once
+	err  error
+}
+
+// DefaultUserAgentRules is the default ruleset of the `Context#UserAgentInfo`.
+var DefaultUserAgentRules = &UserAgentRules{
+	Bots: []UserAgentRule{
+		{Name: "Googlebot", Pattern: `googlebot/?([\d.]*)`},
+		{Name: "Bingbot", Pattern: `bingbot/?([\d.]*)`},
+		{Name: "Yandex", Pattern: `yandex\w*/?([\d.]*)`},
+		{Name: "Baiduspider", Pattern: `baiduspider/?([\d.]*)`},
+		{Name: "DuckDuckBot", Pattern: `duckduckbot/?([\d.]*)`},
+		{Name: "Facebook", Pattern: `facebookexternalhit/?([\d.]*)`},
+		{Name: "Twitterbot", Pattern: `twitterbot/?([\d.]*)`},
+		{Name: "Lighthouse", Pattern: `lighthouse|headlesschrome`},
+		{Name: "curl", Pattern: `^curl/?([\d.]*)`},
+		{Name: "Wget", Pattern: `^wget/?([\d.]*)`},
+		{Name: "Bot", Pattern: `bot\b|crawl|spider|slurp|archiver|python-requests|go-http-client|okhttp|java/`},
+	},
+	Tablets: []UserAgentRule{
+		{Name: "Tablet", Pattern: `ipad|tablet|kindle|silk|playbook`},
+	},
+	Mobiles: []UserAgentRule{
+		{Name: "Mobile", Pattern: `android|avantgo|blackberry|bolt|boost|cricket|docomo|fone|hiptop|mini|mobi|palm|phone|pie|up\.browser|up\.link|webos|wos`},
+	},
+	Browsers: []UserAgentRule{
+		{Name: "Edge", Pattern: `edg(?:e|a|ios)?/([\d.]+)`},
+		{Name: "Opera", Pattern: `(?:opr|opera)/([\d.]+)`},
+		{Name: "Samsung Internet", Pattern: `samsungbrowser/([\d.]+)`},
+		{Name: "Firefox", Pattern: `(?:firefox|fxios)/([\d.]+)`},
+		{Name: "Chrome", Pattern: `(?:chrome|crios)/([\d.]+)`},
+		{Name: "Safari", Pattern: `version/([\d.]+).*safari/`},
+		{Name: "Internet Explorer", Pattern: `(?:msie |trident/.*rv:)([\d.]+)`},
+	},
+	OS: []UserAgentRule{
+		{Name: "Windows Phone", Pattern: `windows phone(?: os)? ([\d.]+)`},
+		{Name: "Windows", Pattern: `windows nt ([\d.]+)`},
+		{Name: "iOS", Pattern: `(?:iphone|ipad|ipod).*? os ([\d_]+)`},
+		{Name: "macOS", Pattern: `mac os x ([\d_.]+)`},
+		{Name: "Android", Pattern: `android ([\d.]+)`},
+		{Name: "Chrome OS", Pattern: `cros`},
+		{Name: "Linux", Pattern: `linux`},
+	},
+}
+
+// NewUserAgentRules returns a new ruleset of the JSON "data", i.e:
+// {"bots": [{"name": "Googlebot", "pattern": "googlebot/?([\\d.]*)"}], "browsers": [...]}.
+// It returns an error if a pattern is not a valid regular expression.
+func NewUserAgentRules(data []byte) (*UserAgentRules, error) {
+	r := new(UserAgentRules)
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+
+	if err := r.Compile(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Compile compiles the patterns of the rules, it's called once,
+// on the first `Parse` if not called before.
+func (r *UserAgentRules) Compile() error {
+	r.once.Do(func() {
+		for _, rules := range [][]UserAgentRule{r.Bots, r.Tablets, r.Mobiles, r.Browsers, r.OS} {
+			for i := range rules {
+				re, err := regexp.Compile("(?i)" + rules[i].Pattern)
+				if err != nil {
+					r.err = err
+					return
+				}
+				rules[i].re = re
+			}
+		}
+	})
+
+	return r.err
+}
+
+// matchUserAgent returns the name and the version of the first matched rule.
+func matchUserAgent(rules []UserAgentRule, userAgent string) (name, version string, ok bool) {
+	for _, rule := range rules {
+		if rule.re == nil {
+			continue
+		}
+
+		if m := rule.re.FindStringSubmatch(userAgent); m != nil {
+			if len(m) > 1 {
+				version = strings.Replace(m[1], "_", ".", -1)
+			}
+			return rule.Name, version, true
+		}
+	}
+
+	return "", "", false
+}
+
+// Parse returns the information of the "userAgent".
+// If a pattern of the rules is not a valid regular expression then only its `Raw` is filled,
+// use the `Compile` or the `NewUserAgentRules` to check the rules before use.
+func (r *UserAgentRules) Parse(userAgent string) UserAgentInfo {
+	info := UserAgentInfo{Raw: userAgent}
+	if userAgent == "" || r.Compile() != nil {
+		return info
+	}
+
+	info.Browser, info.BrowserVersion, _ = matchUserAgent(r.Browsers, userAgent)
+	info.OS, info.OSVersion, _ = matchUserAgent(r.OS, userAgent)
+
+	if bot, _, ok := matchUserAgent(r.Bots, userAgent); ok {
+		info.Device, info.Bot = DeviceBot, bot
+	} else if _, _, ok = matchUserAgent(r.Tablets, userAgent); ok {
+		info.Device = DeviceTablet
+	} else if _, _, ok = matchUserAgent(r.Mobiles, userAgent); ok {
+		info.Device = DeviceMobile
+	} else {
+		info.Device = DeviceDesktop
+	}
+
+	return info
+}
+
+// userAgentInfoContextKey is the request's values key of the parsed `UserAgentInfo`.
+const userAgentInfoContextKey = "iris.user_agent_info"
@@ -0,0 +1,105 @@
+package context_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+const (
+	uaChromeWindows  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.88 Safari/537.36"
+	uaSafariMac      = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.4 Safari/605.1.15"
+	uaFirefoxLinux   = "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:71.0) Gecko/20100101 Firefox/71.0"
+	uaEdge           = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.74 Safari/537.36 Edg/79.0.309.43"
+	uaIPhone         = "Mozilla/5.0 (iPhone; CPU iPhone OS 13_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.4 Mobile/15E148 Safari/604.1"
+	uaAndroidChrome  = "Mozilla/5.0 (Linux; Android 10; Pixel 3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.93 Mobile Safari/537.36"
+	uaIPad           = "Mozilla/5.0 (iPad; CPU OS 13_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.4 Mobile/15E148 Safari/604.1"
+	uaAndroidTablet  = "Mozilla/5.0 (Linux; Android 9; SM-T820) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.93 Safari/537.36"
+	uaGooglebot      = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	uaGooglebotPhone = "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.88 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	uaCurl           = "curl/7.64.1"
+)
+
+func TestUserAgentRules(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  context.UserAgentInfo
+	}{
+		{uaChromeWindows, context.UserAgentInfo{Browser: "Chrome", BrowserVersion: "79.0.3945.88", OS: "Windows", OSVersion: "10.0", Device: context.DeviceDesktop}},
+		{uaSafariMac, context.UserAgentInfo{Browser: "Safari", BrowserVersion: "13.0.4", OS: "macOS", OSVersion: "10.15.2", Device: context.DeviceDesktop}},
+		{uaFirefoxLinux, context.UserAgentInfo{Browser: "Firefox", BrowserVersion: "71.0", OS: "Linux", Device: context.DeviceDesktop}},
+		{uaEdge, context.UserAgentInfo{Browser: "Edge", BrowserVersion: "79.0.309.43", OS: "Windows", OSVersion: "10.0", Device: context.DeviceDesktop}},
+		{uaIPhone, context.UserAgentInfo{Browser: "Safari", BrowserVersion: "13.0.4", OS: "iOS", OSVersion: "13.3", Device: context.DeviceMobile}},
+		{uaAndroidChrome, context.UserAgentInfo{Browser: "Chrome", BrowserVersion: "79.0.3945.93", OS: "Android", OSVersion: "10", Device: context.DeviceMobile}},
+		{uaIPad, context.UserAgentInfo{Browser: "Safari", BrowserVersion: "13.0.4", OS: "iOS", OSVersion: "13.3", Device: context.DeviceTablet}},
+		{uaGooglebot, context.UserAgentInfo{Device: context.DeviceBot, Bot: "Googlebot"}},
+		{uaGooglebotPhone, context.UserAgentInfo{Browser: "Chrome", BrowserVersion: "79.0.3945.88", OS: "Android", OSVersion: "6.0.1", Device: context.DeviceBot, Bot: "Googlebot"}},
+		{uaCurl, context.UserAgentInfo{Device: context.DeviceBot, Bot: "curl"}},
+		{"", context.UserAgentInfo{}},
+	}
+
+	for _, tt := range tests {
+		tt.expected.Raw = tt.userAgent
+		if got := context.DefaultUserAgentRules.Parse(tt.userAgent); got != tt.expected {
+			t.Fatalf("[%s] expected:\n%#+v\nbut got:\n%#+v", tt.userAgent, tt.expected, got)
+		}
+	}
+}
+
+func TestNewUserAgentRules(t *testing.T) {
+	rules, err := context.NewUserAgentRules([]byte(`{"bots": [{"name": "Internal", "pattern": "internal-monitor/([\\d.]+)"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rules.Parse("internal-monitor/1.2"); got.Device != context.DeviceBot || got.Bot != "Internal" {
+		t.Fatalf("expected the custom bot to be detected but got: %#+v", got)
+	}
+
+	if _, err = context.NewUserAgentRules([]byte(`{"bots": [{"name": "Broken", "pattern": "(unclosed"}]}`)); err == nil {
+		t.Fatalf("expected an error for the invalid pattern")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	broken := &context.UserAgentRules{Bots: []context.UserAgentRule{{Name: "Broken", Pattern: "(unclosed"}}}
+
+	handler := func(ctx context.Context) {
+		ctx.Writef("%v %v %s", ctx.IsMobile(), ctx.IsBot(), ctx.UserAgentInfo().Device)
+	}
+
+	app := iris.New()
+	app.Get("/", handler)
+	e := httptest.New(t, app)
+
+	brokenApp := iris.New()
+	brokenApp.Configure(iris.WithUserAgentRules(broken))
+	brokenApp.Get("/", handler)
+	eBroken := httptest.New(t, brokenApp)
+
+	tests := []struct {
+		userAgent string
+		expected  string
+		// the IsMobile does not depend on the rules.
+		expectedBroken string
+	}{
+		{uaChromeWindows, "false false desktop", "false false "},
+		{uaIPhone, "true false mobile", "true false "},
+		{uaAndroidChrome, "true false mobile", "true false "},
+		// the tablets are mobiles too.
+		{uaIPad, "true false tablet", "true false "},
+		{uaAndroidTablet, "true false mobile", "true false "},
+		{"Mozilla/5.0 (Linux; U; en-us; KFTT Build/IML74K) AppleWebKit/535.19 (KHTML, like Gecko) Silk/3.4 Safari/535.19 Tablet", "true false tablet", "true false "},
+		// the mobile crawlers are mobiles too.
+		{uaGooglebotPhone, "true true bot", "true false "},
+		{uaGooglebot, "false true bot", "false false "},
+		{uaCurl, "false true bot", "false false "},
+	}
+
+	for _, tt := range tests {
+		e.GET("/").WithHeader("User-Agent", tt.userAgent).Expect().Body().Equal(tt.expected)
+		eBroken.GET("/").WithHeader("User-Agent", tt.userAgent).Expect().Body().Equal(tt.expectedBroken)
+	}
+}
@@ -0,0 +1,115 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validator is the interface which validators of the request's input should implement,
+// i.e a wrapper of the "gopkg.in/go-playground/validator.v9".
+// When it's configured, through `iris#WithValidator`, the `Context#Bind`, `Context#ReadForm`,
+// `Context#ReadMultipart`, `Context#UnmarshalBody` and the rest of the Read methods
+// validate the value after it's decoded.
+//
+// Validate should return a non-nil error when the "v" is not valid,
+// a `ValidationErrors` to report each one of the invalid fields.
+type Validator interface {
+	Validate(v interface{}) error
+}
+
+// ValidatorFunc is the functional form of the `Validator`.
+type ValidatorFunc func(v interface{}) error
+
+// Validate calls the function itself.
+func (v ValidatorFunc) Validate(value interface{}) error {
+	return v(value)
+}
+
+// ValidationError is a single invalid field, see `Validator`.
+type ValidationError struct {
+	// Field is the path of the invalid field, i.e "address.city", it may be empty.
+	Field string
+	// Reason describes the failure, i.e "required".
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return "validation: " + e.Reason
+	}
+
+	return fmt.Sprintf("validation: %s: %s", e.Field, e.Reason)
+}
+
+// Message returns the, maybe translated, message of the error for the client,
+// the message ID is the `MessageValidationError` of its reason, formatted with the field, if any,
+// and the fallback is the `Error` text.
+func (e *ValidationError) Message(ctx Context) string {
+	id := fmt.Sprintf(MessageValidationError, e.Reason)
+	if e.Field == "" {
+		if msg := ctx.Message(id, ""); msg != "" {
+			return msg
+		}
+		return e.Error()
+	}
+
+	return ctx.Message(id, "validation: %s: "+strings.Replace(e.Reason, "%", "%%", -1), e.Field)
+}
+
+// ValidationErrors is the error of the Read methods when the configured `Validator` rejected the value,
+// the error-code handlers can render them through the `GetValidationErrors`
+// and localize them through the `Messages`.
+type ValidationErrors []*ValidationError
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Messages returns the, maybe translated, messages of the errors for the client, see `ValidationError#Message`.
+func (e ValidationErrors) Messages(ctx Context) []string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message(ctx)
+	}
+
+	return msgs
+}
+
+const validationErrorsContextKey = "@validation_errors"
+
+// GetValidationErrors returns the errors of the last rejected Read of the current request,
+// if any, see `Validator`.
+func GetValidationErrors(ctx Context) (ValidationErrors, bool) {
+	errs, ok := ctx.Values().Get(validationErrorsContextKey).(ValidationErrors)
+	return errs, ok
+}
+
+// validate validates the "v" through the configured `Validator`, if any.
+// On failure it sets the 400 Bad Request status code and stores the errors for the error-code handlers.
+func (ctx *context) validate(v interface{}) error {
+	validator := ctx.Application().ConfigurationReadOnly().GetValidator()
+	if validator == nil {
+		return nil
+	}
+
+	err := validator.Validate(v)
+	if err == nil {
+		return nil
+	}
+
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		errs = ValidationErrors{{Reason: err.Error()}}
+	}
+
+	ctx.values.Set(validationErrorsContextKey, errs)
+	ctx.StatusCode(400)
+	return errs
+}
@@ -0,0 +1,160 @@
+package context_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/iris-contrib/httpexpect"
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/msgpack"
+	"github.com/kataras/iris/httptest"
+)
+
+type testValidated struct {
+	Name string `json:"name" xml:"name" yaml:"name" form:"name" url:"name" query:"name" part:"name"`
+}
+
+// testReadValidated registers a route for each one of the Read methods and the Bind.
+func testReadValidated(app *iris.Application, validated *[]string) {
+	read := map[string]func(ctx context.Context, v *testValidated) error{
+		"json": func(ctx context.Context, v *testValidated) error { return ctx.ReadJSON(v) },
+		"strict": func(ctx context.Context, v *testValidated) error {
+			return ctx.ReadJSON(v, context.JSONReader{DisallowUnknownFields: true})
+		},
+		"xml":     func(ctx context.Context, v *testValidated) error { return ctx.ReadXML(v) },
+		"yaml":    func(ctx context.Context, v *testValidated) error { return ctx.ReadYAML(v) },
+		"msgpack": func(ctx context.Context, v *testValidated) error { return ctx.ReadMsgPack(v) },
+		"form":    func(ctx context.Context, v *testValidated) error { return ctx.ReadForm(v) },
+		"query":   func(ctx context.Context, v *testValidated) error { return ctx.ReadQuery(v) },
+		"part":    func(ctx context.Context, v *testValidated) error { return ctx.ReadMultipart(v) },
+		"bind":    func(ctx context.Context, v *testValidated) error { return ctx.Bind(v) },
+		"stream": func(ctx context.Context, v *testValidated) error {
+			return ctx.ReadJSONStream(func(item testValidated) error {
+				*validated = append(*validated, "item:"+item.Name)
+				return nil
+			})
+		},
+	}
+
+	app.Post("/{method}", func(ctx context.Context) {
+		var v testValidated
+		err := read[ctx.Params().Get("method")](ctx, &v)
+		if err == nil {
+			ctx.WriteString("ok")
+			return
+		}
+
+		if errs, ok := context.GetValidationErrors(ctx); ok {
+			if _, ok = err.(context.ValidationErrors); !ok {
+				ctx.StatusCode(iris.StatusInternalServerError)
+			}
+			ctx.WriteString(strings.Join(errs.Messages(ctx), ";"))
+			return
+		}
+
+		ctx.StatusCode(iris.StatusUnprocessableEntity)
+		ctx.WriteString(err.Error())
+	})
+}
+
+// testValidatedRequest returns the request of the "method" route with the "name" in the source of the method.
+func testValidatedRequest(e *httpexpect.Expect, t *testing.T, method, name string) *httpexpect.Response {
+	req := e.POST("/" + method)
+
+	switch method {
+	case "json", "strict", "bind":
+		req.WithJSON(map[string]string{"name": name})
+	case "xml":
+		req.WithHeader("Content-Type", "application/xml").WithBytes([]byte("<testValidated><name>" + name + "</name></testValidated>"))
+	case "yaml":
+		req.WithHeader("Content-Type", "application/x-yaml").WithBytes([]byte("name: '" + name + "'"))
+	case "msgpack":
+		b, err := msgpack.Marshal(map[string]string{"Name": name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.WithHeader("Content-Type", context.ContentMsgPackHeaderValue).WithBytes(b)
+	case "form":
+		req.WithFormField("name", name)
+	case "query":
+		req.WithQuery("name", name)
+	case "part":
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		w.WriteField("name", name)
+		w.Close()
+		req.WithHeader("Content-Type", w.FormDataContentType()).WithBytes(b.Bytes())
+	case "stream":
+		req.WithHeader("Content-Type", "application/json").
+			WithBytes([]byte(fmt.Sprintf(`[{"name":"first"},{"name":%q},{"name":"last"}]`, name)))
+	}
+
+	return req.Expect()
+}
+
+var testValidatedMethods = []string{"json", "strict", "xml", "yaml", "msgpack", "form", "query", "part", "bind", "stream"}
+
+func TestValidator(t *testing.T) {
+	var validated []string
+
+	app := iris.New()
+	app.Configure(iris.WithValidator(context.ValidatorFunc(func(v interface{}) error {
+		name := v.(*testValidated).Name
+		validated = append(validated, name)
+
+		switch name {
+		case "":
+			return context.ValidationErrors{{Field: "name", Reason: "required"}}
+		case "plain":
+			// the errors which are not ValidationErrors are wrapped.
+			return errors.New("not allowed")
+		}
+		return nil
+	})))
+	testReadValidated(app, &validated)
+
+	e := httptest.New(t, app)
+	for _, method := range testValidatedMethods {
+		validated = validated[:0]
+		testValidatedRequest(e, t, method, "kataras").Status(iris.StatusOK).Body().Equal("ok")
+
+		expected := "kataras"
+		if method == "stream" {
+			expected = "first item:first kataras item:kataras last item:last"
+		}
+		// the value is validated once, after all of its sources are decoded.
+		if got := strings.Join(validated, " "); got != expected {
+			t.Fatalf("%s: expected the validated values to be '%s' but got '%s'", method, expected, got)
+		}
+
+		validated = validated[:0]
+		testValidatedRequest(e, t, method, "").Status(iris.StatusBadRequest).
+			Body().Equal("validation: name: required")
+		// the stream stops on the first invalid item.
+		if method == "stream" {
+			if got := strings.Join(validated, " "); got != "first item:first " {
+				t.Fatalf("expected the stream to stop on the invalid item but got '%s'", got)
+			}
+		}
+
+		testValidatedRequest(e, t, method, "plain").Status(iris.StatusBadRequest).
+			Body().Equal("validation: not allowed")
+	}
+}
+
+func TestValidatorNotConfigured(t *testing.T) {
+	var validated []string
+
+	app := iris.New()
+	testReadValidated(app, &validated)
+
+	e := httptest.New(t, app)
+	for _, method := range testValidatedMethods {
+		testValidatedRequest(e, t, method, "").Status(iris.StatusOK).Body().Equal("ok")
+	}
+}
@@ -0,0 +1,125 @@
+package context
+
+import "time"
+
+// The typed keys of the request's values store, see `Context#Values`.
+//
+// They pass data from a middleware to the next handlers
+// with the value's type checked at compile time, i.e:
+//
+// const userIDKey = context.Int64Key("user_id")
+//
+// func auth(ctx context.Context) {
+//     userIDKey.Set(ctx, 42)
+//     ctx.Next()
+// }
+//
+// func handler(ctx context.Context) {
+//     id, ok := userIDKey.Get(ctx)
+// }
+//
+// The values are stored under the key's name, so they can be read by `ctx.Values().Get(name)` too.
+// Custom types can follow the same pattern by declaring their own key type:
+//
+// type userKey string
+//
+// func (k userKey) Set(ctx context.Context, u *User) { ctx.Values().Set(string(k), u) }
+// func (k userKey) Get(ctx context.Context) (u *User, ok bool) { u, ok = ctx.Values().Get(string(k)).(*User); return }
+type (
+	// StringKey is a typed key of a string value.
+	StringKey string
+	// StringsKey is a typed key of a []string value.
+	StringsKey string
+	// IntKey is a typed key of an int value.
+	IntKey string
+	// Int64Key is a typed key of an int64 value.
+	Int64Key string
+	// Float64Key is a typed key of a float64 value.
+	Float64Key string
+	// BoolKey is a typed key of a bool value.
+	BoolKey string
+	// TimeKey is a typed key of a time.Time value.
+	TimeKey string
+	// DurationKey is a typed key of a time.Duration value.
+	DurationKey string
+)
+
+// Set stores the "value" to the request's values.
+func (k StringKey) Set(ctx Context, value string) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k StringKey) Get(ctx Context) (value string, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).(string)
+	return
+}
+
+// Set stores the "value" to the request's values.
+func (k StringsKey) Set(ctx Context, value []string) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k StringsKey) Get(ctx Context) (value []string, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).([]string)
+	return
+}
+
+// Set stores the "value" to the request's values.
+func (k IntKey) Set(ctx Context, value int) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k IntKey) Get(ctx Context) (value int, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).(int)
+	return
+}
+
+// Set stores the "value" to the request's values.
+func (k Int64Key) Set(ctx Context, value int64) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k Int64Key) Get(ctx Context) (value int64, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).(int64)
+	return
+}
+
+// Set stores the "value" to the request's values.
+func (k Float64Key) Set(ctx Context, value float64) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k Float64Key) Get(ctx Context) (value float64, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).(float64)
+	return
+}
+
+// Set stores the "value" to the request's values.
+func (k BoolKey) Set(ctx Context, value bool) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k BoolKey) Get(ctx Context) (value bool, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).(bool)
+	return
+}
+
+// Set stores the "value" to the request's values.
+func (k TimeKey) Set(ctx Context, value time.Time) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k TimeKey) Get(ctx Context) (value time.Time, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).(time.Time)
+	return
+}
+
+// Set stores the "value" to the request's values.
+func (k DurationKey) Set(ctx Context, value time.Duration) { ctx.Values().Set(string(k), value) }
+
+// Get returns the value of the request's values and true,
+// or the zero value and false if it's missing or of a different type.
+func (k DurationKey) Get(ctx Context) (value time.Duration, ok bool) {
+	value, ok = ctx.Values().Get(string(k)).(time.Duration)
+	return
+}
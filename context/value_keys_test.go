@@ -0,0 +1,92 @@
+package context_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+const (
+	testStringKey   = context.StringKey("string")
+	testStringsKey  = context.StringsKey("strings")
+	testIntKey      = context.IntKey("int")
+	testInt64Key    = context.Int64Key("int64")
+	testFloat64Key  = context.Float64Key("float64")
+	testBoolKey     = context.BoolKey("bool")
+	testTimeKey     = context.TimeKey("time")
+	testDurationKey = context.DurationKey("duration")
+)
+
+func TestValueKeys(t *testing.T) {
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	app := iris.New()
+	app.Use(func(ctx context.Context) {
+		testStringKey.Set(ctx, "kataras")
+		testStringsKey.Set(ctx, []string{"a", "b"})
+		testIntKey.Set(ctx, 42)
+		testInt64Key.Set(ctx, 1<<40)
+		testFloat64Key.Set(ctx, 4.2)
+		testBoolKey.Set(ctx, true)
+		testTimeKey.Set(ctx, now)
+		testDurationKey.Set(ctx, time.Minute)
+		ctx.Next()
+	})
+
+	app.Get("/", func(ctx context.Context) {
+		s, ok := testStringKey.Get(ctx)
+		if !ok || s != "kataras" {
+			t.Errorf("string: %v %v", s, ok)
+		}
+		if ss, ok := testStringsKey.Get(ctx); !ok || len(ss) != 2 || ss[1] != "b" {
+			t.Errorf("strings: %v %v", ss, ok)
+		}
+		if i, ok := testIntKey.Get(ctx); !ok || i != 42 {
+			t.Errorf("int: %v %v", i, ok)
+		}
+		if i, ok := testInt64Key.Get(ctx); !ok || i != 1<<40 {
+			t.Errorf("int64: %v %v", i, ok)
+		}
+		if f, ok := testFloat64Key.Get(ctx); !ok || f != 4.2 {
+			t.Errorf("float64: %v %v", f, ok)
+		}
+		if b, ok := testBoolKey.Get(ctx); !ok || !b {
+			t.Errorf("bool: %v %v", b, ok)
+		}
+		if tm, ok := testTimeKey.Get(ctx); !ok || !tm.Equal(now) {
+			t.Errorf("time: %v %v", tm, ok)
+		}
+		if d, ok := testDurationKey.Get(ctx); !ok || d != time.Minute {
+			t.Errorf("duration: %v %v", d, ok)
+		}
+
+		// the values are stored under the key's name.
+		ctx.WriteString(ctx.Values().GetString("string"))
+	})
+
+	app.Get("/mismatch", func(ctx context.Context) {
+		// a missing value.
+		if v, ok := context.StringKey("missing").Get(ctx); ok || v != "" {
+			t.Errorf("expected a missing value to be the zero value and false but got: %v %v", v, ok)
+		}
+
+		// a value of a different type, set by the untyped store or by a different key type.
+		ctx.Values().Set("int", "42")
+		if v, ok := testIntKey.Get(ctx); ok || v != 0 {
+			t.Errorf("expected a value of a different type to be the zero value and false but got: %v %v", v, ok)
+		}
+		if v, ok := context.Int64Key("int").Get(ctx); ok || v != 0 {
+			t.Errorf("expected an int value to not be an int64 but got: %v %v", v, ok)
+		}
+		if v, ok := context.DurationKey("int64").Get(ctx); ok || v != 0 {
+			t.Errorf("expected an int64 value to not be a duration but got: %v %v", v, ok)
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("kataras")
+	e.GET("/mismatch").Expect().Status(iris.StatusOK)
+}
@@ -0,0 +1,51 @@
+package context
+
+import (
+	"regexp"
+
+	"github.com/kataras/iris/core/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+// YAMLReader contains the options of the `Context#ReadYAML`.
+type YAMLReader struct {
+	// MaxBodySize rejects the bodies with a greater size, in bytes.
+	// Zero means no limit.
+	MaxBodySize int64
+	// MaxAliases rejects the documents with more alias nodes, i.e "*base".
+	// Zero means no limit.
+	//
+	// Note that a few aliases can still expand to huge values, i.e the "billion laughs" payloads,
+	// those documents are rejected by the yaml.v2 (v2.2.8+) decoder itself, whatever the value of this field is.
+	MaxAliases int
+}
+
+// DefaultYAMLReaderOptions are the options of the `Context#ReadYAML`
+// when no options are passed.
+var DefaultYAMLReaderOptions = YAMLReader{
+	MaxBodySize: 1 << 20, // 1MB
+	MaxAliases:  100,
+}
+
+var (
+	errYAMLBodyTooLarge   = errors.New("read yaml: body is larger than %d bytes")
+	errYAMLTooManyAliases = errors.New("read yaml: document contains more than %d aliases")
+	// an alias is a "*" followed by the anchor's name, at the start of a node.
+	yamlAliasExpr = regexp.MustCompile(`(?m)(^|[\s\[\{,:\-?])\*[^\s,\[\]\{\}]+`)
+)
+
+// Unmarshal implements the `Unmarshaler`.
+func (opts YAMLReader) Unmarshal(data []byte, outPtr interface{}) error {
+	if opts.MaxBodySize > 0 && int64(len(data)) > opts.MaxBodySize {
+		return errYAMLBodyTooLarge.Format(opts.MaxBodySize)
+	}
+
+	if opts.MaxAliases > 0 {
+		if n := len(yamlAliasExpr.FindAllIndex(data, opts.MaxAliases+1)); n > opts.MaxAliases {
+			return errYAMLTooManyAliases.Format(opts.MaxAliases)
+		}
+	}
+
+	return yaml.Unmarshal(data, outPtr)
+}
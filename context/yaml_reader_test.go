@@ -0,0 +1,38 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestYAMLReaderExcessiveAliasing(t *testing.T) {
+	// 5 levels of 9 aliases, 45 aliases which expand to 9^6 elements.
+	var b strings.Builder
+	b.WriteString("l0: &l0 [a, a, a, a, a, a, a, a, a]\n")
+	for i := 1; i <= 5; i++ {
+		alias := fmt.Sprintf("*l%d", i-1)
+		fmt.Fprintf(&b, "l%d: &l%d [%s]\n", i, i, strings.TrimSuffix(strings.Repeat(alias+", ", 9), ", "))
+	}
+
+	var v map[string]interface{}
+	if err := DefaultYAMLReaderOptions.Unmarshal([]byte(b.String()), &v); err == nil {
+		t.Fatalf("expected the document to be rejected")
+	} else if !strings.Contains(err.Error(), "excessive aliasing") {
+		t.Fatalf("expected an excessive aliasing error but got: %v", err)
+	}
+
+	// a few aliases are allowed.
+	v = nil
+	if err := DefaultYAMLReaderOptions.Unmarshal([]byte("base: &base {name: iris}\nitem: *base\n"), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if item, ok := v["item"].(map[interface{}]interface{}); !ok || item["name"] != "iris" {
+		t.Fatalf("expected the alias to be resolved but got: %#v", v["item"])
+	}
+
+	if err := (YAMLReader{MaxBodySize: 4}).Unmarshal([]byte("a: b\n"), &v); err == nil {
+		t.Fatalf("expected the body to be rejected by its size")
+	}
+}
@@ -0,0 +1,190 @@
+package host
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// InflightRequest describes a request which is served by the host at the moment,
+// see `Supervisor#Inflight` and `Supervisor#RegisterOnDrainTimeout`.
+type InflightRequest struct {
+	Method     string
+	Path       string
+	RemoteAddr string
+	Started    time.Time
+}
+
+// Duration returns the time passed since the request started.
+func (r InflightRequest) Duration() time.Duration {
+	return time.Since(r.Started)
+}
+
+// String returns a readable line of the request,
+// i.e "GET /upload from 127.0.0.1:51234 running for 12.5s".
+func (r InflightRequest) String() string {
+	return fmt.Sprintf("%s %s from %s running for %s", r.Method, r.Path, r.RemoteAddr, r.Duration())
+}
+
+// forceCancelWait is the time that the `Supervisor#Shutdown` waits
+// for the force-cancelled requests to return, so they can send their 503.
+const forceCancelWait = 500 * time.Millisecond
+
+type inflightEntry struct {
+	req       InflightRequest
+	cancel    context.CancelFunc
+	cancelled int32 // accessed atomically, non-zero means that it was force-cancelled.
+}
+
+type inflightTracker struct {
+	mu      sync.Mutex
+	entries map[*inflightEntry]struct{}
+	wg      sync.WaitGroup
+}
+
+func newInflightTracker() *inflightTracker {
+	return &inflightTracker{entries: make(map[*inflightEntry]struct{})}
+}
+
+func (t *inflightTracker) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithCancel(r.Context())
+		entry := &inflightEntry{
+			req: InflightRequest{
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				RemoteAddr: r.RemoteAddr,
+				Started:    time.Now(),
+			},
+			cancel: cancel,
+		}
+
+		t.mu.Lock()
+		t.entries[entry] = struct{}{}
+		t.wg.Add(1)
+		t.mu.Unlock()
+
+		defer func() {
+			cancel()
+			t.mu.Lock()
+			delete(t.entries, entry)
+			t.mu.Unlock()
+			t.wg.Done()
+		}()
+
+		tw := &inflightWriter{ResponseWriter: w}
+		next.ServeHTTP(tw, r.WithContext(ctx))
+
+		if atomic.LoadInt32(&entry.cancelled) != 0 && !tw.written {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+	})
+}
+
+func (t *inflightTracker) list() []InflightRequest {
+	t.mu.Lock()
+	reqs := make([]InflightRequest, 0, len(t.entries))
+	for entry := range t.entries {
+		reqs = append(reqs, entry.req)
+	}
+	t.mu.Unlock()
+
+	// oldest first.
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Started.Before(reqs[j].Started) })
+	return reqs
+}
+
+func (t *inflightTracker) cancelAll() {
+	t.mu.Lock()
+	for entry := range t.entries {
+		atomic.StoreInt32(&entry.cancelled, 1)
+		entry.cancel()
+	}
+	t.mu.Unlock()
+}
+
+// wait waits for the tracked requests to return or until the "timeout" passed.
+func (t *inflightTracker) wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+var errHijackNotSupported = errors.New("hijack is not supported by the underline response writer")
+
+// inflightWriter keeps track if something was written to the client,
+// it keeps the optional interfaces of the underline response writer.
+type inflightWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *inflightWriter) WriteHeader(statusCode int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *inflightWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *inflightWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.written = true
+		flusher.Flush()
+	}
+}
+
+func (w *inflightWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		w.written = true
+		return hijacker.Hijack()
+	}
+
+	return nil, nil, errHijackNotSupported
+}
+
+func (w *inflightWriter) CloseNotify() <-chan bool {
+	if notifier, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
+
+func (w *inflightWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+
+	return http.ErrNotSupported
+}
+
+// WriteDrainReportOnTimeout is a task which accepts a logger(io.Writer)
+// and writes the requests that refused to finish in the shutdown's grace period to the "w".
+// This function should be registered on DrainTimeout.
+func WriteDrainReportOnTimeout(w io.Writer) func([]InflightRequest) {
+	return func(reqs []InflightRequest) {
+		fmt.Fprintf(w, "Shutdown grace period expired, %d request(s) still in-flight:\n", len(reqs))
+		for _, r := range reqs {
+			fmt.Fprintf(w, "  %s\n", r)
+		}
+	}
+}
@@ -8,6 +8,9 @@ import (
 )
 
 // RegisterOnInterrupt registers a global function to call when CTRL+C/CMD+C pressed or a unix kill command received.
+//
+// The SIGINT and SIGTERM signals are handled the same way, the SIGHUP is left to the application,
+// i.e to reload its configuration, see `RunService` for the Windows service's stop events.
 func RegisterOnInterrupt(cb func()) {
 	Interrupt.Register(cb)
 }
@@ -66,7 +69,7 @@ func (i *interruptListener) notifyAndFire() {
 		// os.Kill  is equivalent with the syscall.SIGKILL
 		os.Kill,
 		syscall.SIGKILL, // register that too, it should be ok
-		// kill -SIGTERM XXXX
+		// kill -SIGTERM XXXX, the service managers (i.e systemd) stop signal
 		syscall.SIGTERM,
 	)
 	select {
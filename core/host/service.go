@@ -0,0 +1,25 @@
+package host
+
+import (
+	"github.com/kataras/iris/core/errors"
+)
+
+// ErrServiceNotSupported is returned by the `InstallService` and `UninstallService`
+// on systems other than windows.
+var ErrServiceNotSupported = errors.New("services are not supported on %s, use the system's service manager instead")
+
+// ServiceConfig contains the information of a Windows service, see `InstallService`.
+type ServiceConfig struct {
+	// Name is the unique name of the service, it's the same as the `RunService`'s "name".
+	Name string
+	// DisplayName is the name of the service as shown in the services manager.
+	// Defaults to the Name.
+	DisplayName string
+	// Description is the description of the service as shown in the services manager.
+	Description string
+	// Args are the command line arguments which are passed to the executable when the service starts.
+	Args []string
+	// AutoStart if true then the service starts by itself whenever the computer reboots,
+	// otherwise it must be started manually.
+	AutoStart bool
+}
@@ -0,0 +1,33 @@
+// +build !windows
+
+package host
+
+import (
+	"runtime"
+)
+
+// RunService runs the "run" as the Windows service "name"
+// when the process is started by the service control manager,
+// the "stop" is called when the service is stopped or the system is shutting down
+// and it should make the "run" to return, i.e by a graceful shutdown.
+//
+// On systems other than windows it just calls the "run",
+// the stop signals are handled by the `Interrupt`.
+func RunService(name string, run func() error, stop func()) error {
+	return run()
+}
+
+// InstallService registers the current executable as a Windows service,
+// the executable should call the `RunService` with the same name.
+//
+// On systems other than windows it returns the `ErrServiceNotSupported`.
+func InstallService(cfg ServiceConfig) error {
+	return ErrServiceNotSupported.Format(runtime.GOOS)
+}
+
+// UninstallService removes the Windows service "name".
+//
+// On systems other than windows it returns the `ErrServiceNotSupported`.
+func UninstallService(name string) error {
+	return ErrServiceNotSupported.Format(runtime.GOOS)
+}
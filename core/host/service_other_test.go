@@ -0,0 +1,29 @@
+// +build !windows
+
+package host
+
+import (
+	"errors"
+	"testing"
+
+	irisErrors "github.com/kataras/iris/core/errors"
+)
+
+func TestRunService(t *testing.T) {
+	expected := errors.New("run")
+	stopped := false
+
+	err := RunService("iris", func() error { return expected }, func() { stopped = true })
+	if err != expected {
+		t.Fatalf("expected the error of the run but got: %v", err)
+	}
+	if stopped {
+		t.Fatalf("expected the stop to be left to the interrupt handler")
+	}
+
+	for _, err = range []error{InstallService(ServiceConfig{Name: "iris"}), UninstallService("iris")} {
+		if e, ok := err.(irisErrors.Error); !ok || !e.Equal(ErrServiceNotSupported) {
+			t.Fatalf("expected a not supported error but got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// +build windows
+
+package host
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// RunService runs the "run" as the Windows service "name"
+// when the process is started by the service control manager,
+// the "stop" is called when the service is stopped or the system is shutting down
+// and it should make the "run" to return, i.e by a graceful shutdown.
+//
+// When the process is not started as a service, i.e from a console, it just calls the "run".
+func RunService(name string, run func() error, stop func()) error {
+	interactive, err := svc.IsAnInteractiveSession()
+	if err != nil {
+		return err
+	}
+
+	if interactive {
+		return run()
+	}
+
+	h := &serviceHandler{run: run, stop: stop}
+	if err = svc.Run(name, h); err != nil {
+		return err
+	}
+
+	return h.err
+}
+
+type serviceHandler struct {
+	run  func() error
+	stop func()
+	err  error
+}
+
+func (h *serviceHandler) Execute(args []string, r <-chan svc.ChangeRequest, s chan<- svc.Status) (bool, uint32) {
+	const accepts = svc.AcceptStop | svc.AcceptShutdown
+
+	s <- svc.Status{State: svc.StartPending}
+	errCh := make(chan error, 1)
+	go func() { errCh <- h.run() }()
+	s <- svc.Status{State: svc.Running, Accepts: accepts}
+
+	for {
+		select {
+		case h.err = <-errCh:
+			s <- svc.Status{State: svc.StopPending}
+			if h.err != nil {
+				return true, 1
+			}
+			return false, 0
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				s <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				s <- svc.Status{State: svc.StopPending}
+				h.stop()
+			}
+		}
+	}
+}
+
+// InstallService registers the current executable as a Windows service,
+// the executable should call the `RunService` with the same name.
+func InstallService(cfg ServiceConfig) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if executable, err = filepath.Abs(executable); err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	displayName := cfg.DisplayName
+	if displayName == "" {
+		displayName = cfg.Name
+	}
+
+	startType := uint32(mgr.StartManual)
+	if cfg.AutoStart {
+		startType = mgr.StartAutomatic
+	}
+
+	s, err := m.CreateService(cfg.Name, executable, mgr.Config{
+		DisplayName: displayName,
+		Description: cfg.Description,
+		StartType:   startType,
+	}, cfg.Args...)
+	if err != nil {
+		return err
+	}
+
+	return s.Close()
+}
+
+// UninstallService removes the Windows service "name".
+func UninstallService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	return s.Delete()
+}
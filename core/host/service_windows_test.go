@@ -0,0 +1,71 @@
+// +build windows
+
+package host
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+)
+
+func TestServiceHandlerExecute(t *testing.T) {
+	done := make(chan struct{})
+	h := &serviceHandler{
+		run: func() error {
+			<-done
+			return nil
+		},
+		stop: func() { close(done) },
+	}
+
+	r := make(chan svc.ChangeRequest, 2)
+	s := make(chan svc.Status, 10)
+	r <- svc.ChangeRequest{Cmd: svc.Interrogate, CurrentStatus: svc.Status{State: svc.Running}}
+	r <- svc.ChangeRequest{Cmd: svc.Stop}
+
+	exited := make(chan uint32, 1)
+	go func() {
+		_, code := h.Execute(nil, r, s)
+		exited <- code
+	}()
+
+	select {
+	case code := <-exited:
+		if code != 0 {
+			t.Fatalf("expected a zero exit code but got: %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the service to stop")
+	}
+
+	close(s)
+	var states []svc.State
+	for status := range s {
+		states = append(states, status.State)
+	}
+
+	expected := []svc.State{svc.StartPending, svc.Running, svc.Running, svc.StopPending, svc.StopPending}
+	if len(states) != len(expected) {
+		t.Fatalf("expected states %v but got %v", expected, states)
+	}
+	for i := range expected {
+		if states[i] != expected[i] {
+			t.Fatalf("expected states %v but got %v", expected, states)
+		}
+	}
+}
+
+func TestServiceHandlerExecuteError(t *testing.T) {
+	expected := errors.New("run")
+	h := &serviceHandler{run: func() error { return expected }, stop: func() {}}
+
+	s := make(chan svc.Status, 10)
+	if _, code := h.Execute(nil, make(chan svc.ChangeRequest), s); code != 1 {
+		t.Fatalf("expected a non-zero exit code but got: %d", code)
+	}
+	if h.err != expected {
+		t.Fatalf("expected the error of the run but got: %v", h.err)
+	}
+}
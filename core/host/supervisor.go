@@ -47,6 +47,25 @@ type Supervisor struct {
 	IgnoredErrors []string
 	onErr         []func(error)
 	onShutdown    []func()
+
+	// TrackInflight enables the tracking of the requests that are served by the host,
+	// see `Inflight`. It's enabled automatically when `ForceCancelOnDrainTimeout` is true
+	// or when `RegisterOnDrainTimeout` is used.
+	//
+	// Should be set before serve.
+	//
+	// Defaults to false.
+	TrackInflight bool
+	// ForceCancelOnDrainTimeout if true then the requests that are still in-flight
+	// when the `Shutdown`'s context expired are cancelled through their request's context,
+	// the ones that did not write anything to the client yet will receive a 503 Service Unavailable.
+	//
+	// Defaults to false.
+	ForceCancelOnDrainTimeout bool
+	onDrainTimeout            []func([]InflightRequest)
+	inflight                  *inflightTracker
+	// listener is the latest, non-tls, listener that this host serves, used on `Upgrade`.
+	listener net.Listener
 }
 
 // New returns a new host supervisor
@@ -115,11 +134,18 @@ func (su *Supervisor) newListener() (net.Listener, error) {
 	// restarts we may want for the server.
 	//
 	// User still be able to call .Serve instead.
-	l, err := netutil.TCPKeepAlive(su.Server.Addr)
-	if err != nil {
-		return nil, err
+	l, ok := inheritedListener(su.Server.Addr)
+	if !ok {
+		var err error
+		if l, err = netutil.TCPKeepAlive(su.Server.Addr); err != nil {
+			return nil, err
+		}
 	}
 
+	su.mu.Lock()
+	su.listener = l
+	su.mu.Unlock()
+
 	// here we can check for sure, without the need of the supervisor's `manuallyTLS` field.
 	if netutil.IsTLS(su.Server) {
 		// means tls
@@ -185,9 +211,11 @@ func (su *Supervisor) notifyServe(host TaskHost) {
 // I don't know channels are not so safe, when go func and race risk..
 // so better with callbacks....
 func (su *Supervisor) supervise(blockFunc func() error) error {
+	su.trackInflight()
 	host := createTaskHost(su)
 
 	su.notifyServe(host)
+	notifyUpgradeReady()
 
 	err := blockFunc()
 	su.notifyErr(err)
@@ -217,6 +245,12 @@ func (su *Supervisor) supervise(blockFunc func() error) error {
 // Serve always returns a non-nil error. After Shutdown or Close, the
 // returned error is http.ErrServerClosed.
 func (su *Supervisor) Serve(l net.Listener) error {
+	if _, ok := l.(fileListener); ok {
+		su.mu.Lock()
+		su.listener = l
+		su.mu.Unlock()
+	}
+
 	return su.supervise(func() error { return su.Server.Serve(l) })
 }
 
@@ -366,6 +400,82 @@ func (su *Supervisor) notifyShutdown() {
 	// end
 }
 
+func (su *Supervisor) trackInflight() {
+	su.mu.Lock()
+	defer su.mu.Unlock()
+
+	if su.inflight != nil { // already wrapped, i.e on re-serve.
+		return
+	}
+
+	if !su.TrackInflight && !su.ForceCancelOnDrainTimeout && len(su.onDrainTimeout) == 0 {
+		return
+	}
+
+	handler := su.Server.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+
+	su.inflight = newInflightTracker()
+	su.Server.Handler = su.inflight.wrap(handler)
+}
+
+// Inflight returns the requests that are served at the moment, oldest first.
+// It can be used to expose the in-flight requests through an admin endpoint.
+//
+// Returns nil if the tracking is not enabled, see `TrackInflight`.
+func (su *Supervisor) Inflight() []InflightRequest {
+	su.mu.Lock()
+	inflight := su.inflight
+	su.mu.Unlock()
+
+	if inflight == nil {
+		return nil
+	}
+
+	return inflight.list()
+}
+
+// RegisterOnDrainTimeout registers a function to call when the `Shutdown`'s context
+// expired but there are still requests in-flight, these requests are passed to the "cb".
+// Use it to log or to record metrics about the requests that refuse to finish,
+// see the `WriteDrainReportOnTimeout` too.
+//
+// The registered functions are called before any force-cancellation, see `ForceCancelOnDrainTimeout`.
+//
+// Should be registered before serve.
+func (su *Supervisor) RegisterOnDrainTimeout(cb func([]InflightRequest)) {
+	su.mu.Lock()
+	su.onDrainTimeout = append(su.onDrainTimeout, cb)
+	su.mu.Unlock()
+}
+
+func (su *Supervisor) notifyDrainTimeout() {
+	su.mu.Lock()
+	inflight, callbacks := su.inflight, su.onDrainTimeout
+	su.mu.Unlock()
+
+	if inflight == nil {
+		return
+	}
+
+	reqs := inflight.list()
+	if len(reqs) == 0 {
+		return
+	}
+
+	// called in sync, the program may exit right after the shutdown.
+	for _, f := range callbacks {
+		f(reqs)
+	}
+
+	if su.ForceCancelOnDrainTimeout {
+		inflight.cancelAll()
+		inflight.wait(forceCancelWait)
+	}
+}
+
 // Shutdown gracefully shuts down the server without interrupting any
 // active connections. Shutdown works by first closing all open
 // listeners, then closing all idle connections, and then waiting
@@ -377,8 +487,16 @@ func (su *Supervisor) notifyShutdown() {
 // connections such as WebSockets. The caller of Shutdown should
 // separately notify such long-lived connections of shutdown and wait
 // for them to close, if desired.
+//
+// If the provided context expires while there are still requests in-flight
+// then the `RegisterOnDrainTimeout` functions are called
+// and, if `ForceCancelOnDrainTimeout` is true, these requests are cancelled.
 func (su *Supervisor) Shutdown(ctx context.Context) error {
 	atomic.AddInt32(&su.closedManually, 1) // future-use
 	su.notifyShutdown()
-	return su.Server.Shutdown(ctx)
+	err := su.Server.Shutdown(ctx)
+	if err != nil && ctx.Err() != nil {
+		su.notifyDrainTimeout()
+	}
+	return err
 }
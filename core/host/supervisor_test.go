@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/iris-contrib/httpexpect"
 )
@@ -108,3 +109,52 @@ func TestSupervisor(t *testing.T) {
 		return su
 	})
 }
+
+func TestSupervisorDrainTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done() // refuse to finish until cancelled.
+	})}
+
+	su := New(srv)
+	su.ForceCancelOnDrainTimeout = true
+	reported := make(chan []InflightRequest, 1)
+	su.RegisterOnDrainTimeout(func(reqs []InflightRequest) { reported <- reqs })
+	go su.Serve(l)
+
+	statusCode := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + l.Addr().String() + "/stuck")
+		if err != nil {
+			statusCode <- 0
+			return
+		}
+		resp.Body.Close()
+		statusCode <- resp.StatusCode
+	}()
+
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err = su.Shutdown(ctx); err == nil {
+		t.Fatalf("expected shutdown to timeout")
+	}
+
+	reqs := <-reported
+	if expected, got := 1, len(reqs); expected != got {
+		t.Fatalf("expected %d in-flight requests but got %d", expected, got)
+	}
+	if expected, got := "/stuck", reqs[0].Path; expected != got {
+		t.Fatalf("expected in-flight request's path to be %s but got %s", expected, got)
+	}
+
+	if expected, got := http.StatusServiceUnavailable, <-statusCode; expected != got {
+		t.Fatalf("expected status code %d but got %d", expected, got)
+	}
+}
@@ -0,0 +1,217 @@
+package host
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// The environment variables which are passed from the old process to the new one on `Upgrade`.
+const (
+	// upgradeListenersEnv contains the addresses of the inherited listeners,
+	// separated by comma, in the same order as their file descriptors (starting from 3).
+	upgradeListenersEnv = "IRIS_UPGRADE_LISTENERS"
+	// upgradeReadyEnv contains the file descriptor of the pipe
+	// that the new process closes when it's ready to serve.
+	upgradeReadyEnv = "IRIS_UPGRADE_READY"
+)
+
+var (
+	// ErrUpgradeNotSupported is returned by `Upgrade` on systems that cannot pass listeners
+	// to a child process, i.e windows.
+	ErrUpgradeNotSupported = errors.New("upgrade is not supported on %s")
+	// ErrUpgradeFailed is returned by `Upgrade` when the new process
+	// could not be started or it exited before it was ready to serve.
+	ErrUpgradeFailed = errors.New("upgrade failed: %s")
+)
+
+type fileListener interface {
+	File() (*os.File, error)
+}
+
+// inherited holds the listeners that this process inherited from its parent on `Upgrade`.
+var inherited struct {
+	once      sync.Once
+	mu        sync.Mutex
+	files     map[string]*os.File
+	ready     *os.File
+	readyOnce sync.Once
+}
+
+func loadInherited() {
+	inherited.once.Do(func() {
+		inherited.files = make(map[string]*os.File)
+
+		if addrs := os.Getenv(upgradeListenersEnv); addrs != "" {
+			for i, addr := range strings.Split(addrs, ",") {
+				inherited.files[addr] = os.NewFile(uintptr(3+i), addr)
+			}
+		}
+
+		if fd, err := strconv.Atoi(os.Getenv(upgradeReadyEnv)); err == nil {
+			inherited.ready = os.NewFile(uintptr(fd), "upgrade-ready")
+		}
+
+		// do not pass them to our own children.
+		os.Unsetenv(upgradeListenersEnv)
+		os.Unsetenv(upgradeReadyEnv)
+	})
+}
+
+// inheritedListener returns the listener of the "addr" that
+// this process inherited from its parent, if any.
+func inheritedListener(addr string) (net.Listener, bool) {
+	loadInherited()
+
+	inherited.mu.Lock()
+	f, ok := inherited.files[addr]
+	delete(inherited.files, addr)
+	inherited.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	l, err := net.FileListener(f)
+	f.Close() // FileListener dups the file descriptor.
+	if err != nil {
+		return nil, false
+	}
+
+	return l, true
+}
+
+// notifyUpgradeReady notifies the parent process that we are ready to serve,
+// it's called on each serve but it does notify the parent
+// only when all of its listeners were taken.
+func notifyUpgradeReady() {
+	loadInherited()
+
+	inherited.mu.Lock()
+	remaining := len(inherited.files)
+	inherited.mu.Unlock()
+
+	if inherited.ready == nil || remaining > 0 {
+		return
+	}
+
+	inherited.readyOnce.Do(func() {
+		inherited.ready.Write([]byte{1})
+		inherited.ready.Close()
+	})
+}
+
+// Upgrade starts a new process of the current executable, with the same arguments,
+// which inherits the listeners of the "supervisors",
+// so a new binary can take over without dropping any connection,
+// the connections are queued to the shared listeners until the new process serves them.
+//
+// It returns when the new process is ready to serve,
+// the caller should then gracefully shutdown the "supervisors" of the old process,
+// as the `Application#Upgrade` does.
+// If the "ctx" is done before the new process is ready then the new process is killed.
+//
+// Only the hosts that their listener is created by the `Supervisor#ListenAndServe`,
+// or passed to the `Supervisor#Serve`, can be upgraded, a listener is matched by the server's `Addr`.
+//
+// Upgrade is not supported on windows.
+func Upgrade(ctx context.Context, supervisors ...*Supervisor) error {
+	if runtime.GOOS == "windows" {
+		return ErrUpgradeNotSupported.Format(runtime.GOOS)
+	}
+
+	var (
+		addrs []string
+		files []*os.File
+	)
+
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, su := range supervisors {
+		su.mu.Lock()
+		l := su.listener
+		su.mu.Unlock()
+
+		fl, ok := l.(fileListener)
+		if !ok {
+			continue
+		}
+
+		f, err := fl.File()
+		if err != nil {
+			return ErrUpgradeFailed.Format(err.Error())
+		}
+
+		addr := su.Server.Addr
+		if addr == "" {
+			addr = l.Addr().String()
+		}
+
+		addrs = append(addrs, addr)
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		return ErrUpgradeFailed.Format("no listeners to pass")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return ErrUpgradeFailed.Format(err.Error())
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return ErrUpgradeFailed.Format(err.Error())
+	}
+	defer readyR.Close()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(os.Environ(),
+		upgradeListenersEnv+"="+strings.Join(addrs, ","),
+		upgradeReadyEnv+"="+strconv.Itoa(3+len(files)))
+
+	err = cmd.Start()
+	readyW.Close() // the child has its own copy now.
+	if err != nil {
+		return ErrUpgradeFailed.Format(err.Error())
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		b := make([]byte, 1)
+		if n, err := readyR.Read(b); n == 0 {
+			// the new process exited or closed the pipe without notifying us.
+			ready <- ErrUpgradeFailed.Format("new process exited before ready: " + err.Error())
+			return
+		}
+		ready <- nil
+	}()
+
+	select {
+	case err = <-ready:
+	case <-ctx.Done():
+		err = ErrUpgradeFailed.Format(ctx.Err().Error())
+	}
+
+	if err != nil {
+		cmd.Process.Kill()
+		go cmd.Wait()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+// +build !windows
+
+package host
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// upgradeHangEnv makes the new process of the `TestUpgrade` to never be ready.
+const upgradeHangEnv = "IRIS_TEST_UPGRADE_HANG"
+
+func TestMain(m *testing.M) {
+	// the test binary is the new process of the `Upgrade`.
+	if os.Getenv(upgradeListenersEnv) != "" {
+		os.Exit(runUpgraded())
+	}
+
+	os.Exit(m.Run())
+}
+
+// runUpgraded serves the inherited listener, it responds with "new"
+// and it shuts down itself on the "/exit" request.
+func runUpgraded() int {
+	if os.Getenv(upgradeHangEnv) != "" {
+		time.Sleep(time.Minute)
+		return 1
+	}
+
+	mux := http.NewServeMux()
+	srv := &http.Server{Addr: strings.Split(os.Getenv(upgradeListenersEnv), ",")[0], Handler: mux}
+	su := New(srv)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("new"))
+	})
+	mux.HandleFunc("/exit", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("exit"))
+		go su.Shutdown(context.Background())
+	})
+
+	if err := su.ListenAndServe(); err != http.ErrServerClosed {
+		return 1
+	}
+	return 0
+}
+
+func testUpgradeGet(t *testing.T, url string) string {
+	// a new connection each time, the keep-alive ones are bound to the old process.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestUpgrade(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + l.Addr().String()
+
+	su := New(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("old"))
+	})})
+	go su.Serve(l)
+
+	if got := testUpgradeGet(t, url); got != "old" {
+		t.Fatalf("expected the old process to respond but got: '%s'", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err = Upgrade(ctx, su); err != nil {
+		t.Fatal(err)
+	}
+	if err = su.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the listener is still open, served by the new process.
+	if got := testUpgradeGet(t, url); got != "new" {
+		t.Fatalf("expected the new process to respond but got: '%s'", got)
+	}
+	testUpgradeGet(t, url+"/exit")
+}
+
+func isUpgradeFailed(err error) bool {
+	e, ok := err.(errors.Error)
+	return ok && e.Equal(ErrUpgradeFailed)
+}
+
+func TestUpgradeFailed(t *testing.T) {
+	if err := Upgrade(context.Background()); !isUpgradeFailed(err) {
+		t.Fatalf("expected an upgrade failure when there are no listeners but got: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	su := New(&http.Server{Handler: http.NotFoundHandler()})
+	go su.Serve(l)
+	defer su.Shutdown(context.Background())
+	testUpgradeGet(t, "http://"+l.Addr().String()) // wait to serve.
+
+	os.Setenv(upgradeHangEnv, "1")
+	defer os.Unsetenv(upgradeHangEnv)
+
+	// the new process is killed when it's not ready in time.
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err = Upgrade(ctx, su); !isUpgradeFailed(err) || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Fatalf("expected an upgrade failure on timeout but got: %v", err)
+	}
+}
@@ -52,6 +52,12 @@ func (e Entry) GetByKindOrNil(k reflect.Kind) interface{} {
 			return nil
 		}
 		return v
+	case reflect.Uint64:
+		v, err := e.Uint64Default(0)
+		if err != nil {
+			return nil
+		}
+		return v
 	case reflect.Bool:
 		v, err := e.BoolDefault(false)
 		if err != nil {
@@ -131,6 +137,25 @@ func (e Entry) Int64Default(def int64) (int64, error) {
 	return def, errFindParse.Format("int64", e.Key)
 }
 
+// Uint64Default returns the entry's value as uint64.
+// If not found returns "def" and a non-nil error.
+func (e Entry) Uint64Default(def uint64) (uint64, error) {
+	v := e.ValueRaw
+	if v == nil {
+		return def, errFindParse.Format("uint64", e.Key)
+	}
+
+	if vuint64, ok := v.(uint64); ok {
+		return vuint64, nil
+	}
+
+	if vstring, sok := v.(string); sok {
+		return strconv.ParseUint(vstring, 10, 64)
+	}
+
+	return def, errFindParse.Format("uint64", e.Key)
+}
+
 // Float64Default returns the entry's value as float64.
 // If not found returns "def" and a non-nil error.
 func (e Entry) Float64Default(def float64) (float64, error) {
@@ -442,6 +467,26 @@ func (r *Store) GetInt64Default(key string, def int64) int64 {
 	return def
 }
 
+// GetUint64 returns the entry's value as uint64, based on its key.
+// If not found returns 0 and a non-nil error.
+func (r *Store) GetUint64(key string) (uint64, error) {
+	v := r.GetEntry(key)
+	if v == nil {
+		return 0, errFindParse.Format("uint64", key)
+	}
+	return v.Uint64Default(0)
+}
+
+// GetUint64Default returns the entry's value as uint64, based on its key.
+// If not found returns "def".
+func (r *Store) GetUint64Default(key string, def uint64) uint64 {
+	if v, err := r.GetUint64(key); err == nil {
+		return v
+	}
+
+	return def
+}
+
 // GetFloat64 returns the entry's value as float64, based on its key.
 // If not found returns -1 and a non nil error.
 func (r *Store) GetFloat64(key string) (float64, error) {
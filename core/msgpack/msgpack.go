@@ -0,0 +1,714 @@
+// Package msgpack is a small, reflection based, MessagePack encoder and decoder,
+// it's used by the `Context#MsgPack` and `Context#ReadMsgPack`.
+//
+// Structs are encoded as maps, their keys are the field names
+// or the names of the "msgpack" field tags, i.e `msgpack:"name,omitempty"`,
+// a "-" tag skips the field.
+// Values that implement the `encoding.TextMarshaler`, like the `time.Time`, are encoded as strings.
+package msgpack
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Marshal returns the MessagePack encoding of "v".
+func Marshal(v interface{}) ([]byte, error) {
+	e := &encoder{}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+// Unmarshal decodes the MessagePack "data" and stores the result to the value pointed to by "v".
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("msgpack: unmarshal expects a non-nil pointer but got %T", v)
+	}
+
+	d := &decoder{data: data}
+	value, err := d.decode()
+	if err != nil {
+		return err
+	}
+
+	return assign(rv.Elem(), value)
+}
+
+var textMarshalerTyp = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) writeCode(code byte, n uint64, size int) {
+	e.buf.WriteByte(code)
+	var b [8]byte
+	switch size {
+	case 1:
+		e.buf.WriteByte(byte(n))
+	case 2:
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		e.buf.Write(b[:2])
+	case 4:
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		e.buf.Write(b[:4])
+	case 8:
+		binary.BigEndian.PutUint64(b[:], n)
+		e.buf.Write(b[:8])
+	}
+}
+
+func (e *encoder) encodeInt(n int64) {
+	switch {
+	case n >= 0:
+		e.encodeUint(uint64(n))
+	case n >= -32:
+		e.buf.WriteByte(byte(n))
+	case n >= math.MinInt8:
+		e.writeCode(0xd0, uint64(n), 1)
+	case n >= math.MinInt16:
+		e.writeCode(0xd1, uint64(n), 2)
+	case n >= math.MinInt32:
+		e.writeCode(0xd2, uint64(n), 4)
+	default:
+		e.writeCode(0xd3, uint64(n), 8)
+	}
+}
+
+func (e *encoder) encodeUint(n uint64) {
+	switch {
+	case n < 128:
+		e.buf.WriteByte(byte(n))
+	case n <= math.MaxUint8:
+		e.writeCode(0xcc, n, 1)
+	case n <= math.MaxUint16:
+		e.writeCode(0xcd, n, 2)
+	case n <= math.MaxUint32:
+		e.writeCode(0xce, n, 4)
+	default:
+		e.writeCode(0xcf, n, 8)
+	}
+}
+
+func (e *encoder) encodeLen(fix, fixMax byte, code16, code32 byte, n int) {
+	switch {
+	case n <= int(fixMax):
+		e.buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		e.writeCode(code16, uint64(n), 2)
+	default:
+		e.writeCode(code32, uint64(n), 4)
+	}
+}
+
+func (e *encoder) encodeString(s string) {
+	if n := len(s); n > 31 && n <= math.MaxUint8 {
+		e.writeCode(0xd9, uint64(n), 1)
+	} else {
+		e.encodeLen(0xa0, 31, 0xda, 0xdb, n)
+	}
+	e.buf.WriteString(s)
+}
+
+func (e *encoder) encodeBytes(b []byte) {
+	switch n := len(b); {
+	case n <= math.MaxUint8:
+		e.writeCode(0xc4, uint64(n), 1)
+	case n <= math.MaxUint16:
+		e.writeCode(0xc5, uint64(n), 2)
+	default:
+		e.writeCode(0xc6, uint64(n), 4)
+	}
+	e.buf.Write(b)
+}
+
+func (e *encoder) encode(rv reflect.Value) error {
+	if !rv.IsValid() {
+		e.buf.WriteByte(0xc0)
+		return nil
+	}
+
+	if rv.Type().Implements(textMarshalerTyp) && (rv.Kind() != reflect.Ptr || !rv.IsNil()) {
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		e.encodeString(string(text))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			e.buf.WriteByte(0xc0)
+			return nil
+		}
+		return e.encode(rv.Elem())
+	case reflect.Bool:
+		if rv.Bool() {
+			e.buf.WriteByte(0xc3)
+		} else {
+			e.buf.WriteByte(0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.encodeInt(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.encodeUint(rv.Uint())
+	case reflect.Float32:
+		e.writeCode(0xca, uint64(math.Float32bits(float32(rv.Float()))), 4)
+	case reflect.Float64:
+		e.writeCode(0xcb, math.Float64bits(rv.Float()), 8)
+	case reflect.String:
+		e.encodeString(rv.String())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			e.buf.WriteByte(0xc0)
+			return nil
+		}
+
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			e.encodeBytes(b)
+			return nil
+		}
+
+		n := rv.Len()
+		e.encodeLen(0x90, 15, 0xdc, 0xdd, n)
+		for i := 0; i < n; i++ {
+			if err := e.encode(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if rv.IsNil() {
+			e.buf.WriteByte(0xc0)
+			return nil
+		}
+
+		keys := rv.MapKeys()
+		e.encodeLen(0x80, 15, 0xde, 0xdf, len(keys))
+		for _, k := range keys {
+			if err := e.encode(k); err != nil {
+				return err
+			}
+			if err := e.encode(rv.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		fields := structFields(rv.Type())
+		var values []reflect.Value
+		var names []string
+		for _, f := range fields {
+			fv := rv.FieldByIndex(f.index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			names = append(names, f.name)
+			values = append(values, fv)
+		}
+
+		e.encodeLen(0x80, 15, 0xde, 0xdf, len(names))
+		for i, name := range names {
+			e.encodeString(name)
+			if err := e.encode(values[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", rv.Type())
+	}
+
+	return nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var (
+	fieldsCache   = make(map[reflect.Type][]field)
+	fieldsCacheMu sync.RWMutex
+)
+
+func structFields(typ reflect.Type) []field {
+	fieldsCacheMu.RLock()
+	cached, ok := fieldsCache[typ]
+	fieldsCacheMu.RUnlock()
+	if ok {
+		return cached
+	}
+
+	var fields []field
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous { // unexported.
+			continue
+		}
+
+		tag := f.Tag.Get("msgpack")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx != -1 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		if name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for _, embedded := range structFields(f.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, field{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: strings.Contains(opts, "omitempty"),
+		})
+	}
+
+	fieldsCacheMu.Lock()
+	fieldsCache[typ] = fields
+	fieldsCacheMu.Unlock()
+	return fields
+}
+
+// MaxDepth is the maximum nesting depth of the arrays and the maps of the decoded data,
+// the deeper data are rejected by the `Unmarshal`.
+var MaxDepth = 100
+
+var (
+	errShortData = errors.New("msgpack: unexpected end of data")
+	errMaxDepth  = errors.New("msgpack: exceeded max depth")
+)
+
+type decoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errShortData
+	}
+
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) readUint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+// decode decodes the next value to its generic form:
+// nil, bool, int64, uint64, float64, string, []byte, []interface{} or map[interface{}]interface{}.
+func (d *decoder) decode() (interface{}, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	code := b[0]
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xe0 == 0xa0:
+		return d.decodeString(int(code & 0x1f))
+	case code&0xf0 == 0x90:
+		return d.decodeArray(int(code & 0x0f))
+	case code&0xf0 == 0x80:
+		return d.decodeMap(int(code & 0x0f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readUint(1 << (code - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case 0xca:
+		n, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := d.readUint(8)
+		return math.Float64frombits(n), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.readUint(1 << (code - 0xcc))
+	case 0xd0:
+		n, err := d.readUint(1)
+		return int64(int8(n)), err
+	case 0xd1:
+		n, err := d.readUint(2)
+		return int64(int16(n)), err
+	case 0xd2:
+		n, err := d.readUint(4)
+		return int64(int32(n)), err
+	case 0xd3:
+		n, err := d.readUint(8)
+		return int64(n), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readUint(1 << (code - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (code - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n))
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (code - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n))
+	}
+
+	return nil, fmt.Errorf("msgpack: unsupported code 0x%x", code)
+}
+
+func (d *decoder) decodeString(n int) (interface{}, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *decoder) decodeArray(n int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errShortData
+	}
+
+	if d.depth++; d.depth > MaxDepth {
+		return nil, errMaxDepth
+	}
+	defer func() { d.depth-- }()
+
+	arr := make([]interface{}, n)
+	for i := range arr {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = v
+	}
+	return arr, nil
+}
+
+func (d *decoder) decodeMap(n int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errShortData
+	}
+
+	if d.depth++; d.depth > MaxDepth {
+		return nil, errMaxDepth
+	}
+	defer func() { d.depth-- }()
+
+	m := make(map[interface{}]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		switch key := k.(type) {
+		case []byte:
+			k = string(key)
+		case []interface{}, map[interface{}]interface{}:
+			// not hashable.
+			return nil, fmt.Errorf("msgpack: unsupported map key of type %T", k)
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+var textUnmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// assign sets the generic "value" to "rv".
+func assign(rv reflect.Value, value interface{}) error {
+	if value == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return assign(rv.Elem(), value)
+	}
+
+	if s, ok := value.(string); ok && rv.CanAddr() && rv.Addr().Type().Implements(textUnmarshalerTyp) {
+		return rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("msgpack: cannot unmarshal %T into %s", value, rv.Type())
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return mismatch()
+		}
+		rv.Set(reflect.ValueOf(generic(value)))
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return mismatch()
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch v := value.(type) {
+		case int64:
+			n = v
+		case uint64:
+			if v > math.MaxInt64 {
+				return mismatch()
+			}
+			n = int64(v)
+		default:
+			return mismatch()
+		}
+		if rv.OverflowInt(n) {
+			return mismatch()
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		switch v := value.(type) {
+		case uint64:
+			n = v
+		case int64:
+			if v < 0 {
+				return mismatch()
+			}
+			n = uint64(v)
+		default:
+			return mismatch()
+		}
+		if rv.OverflowUint(n) {
+			return mismatch()
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case float64:
+			rv.SetFloat(v)
+		case int64:
+			rv.SetFloat(float64(v))
+		case uint64:
+			rv.SetFloat(float64(v))
+		default:
+			return mismatch()
+		}
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			rv.SetString(v)
+		case []byte:
+			rv.SetString(string(v))
+		default:
+			return mismatch()
+		}
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			switch v := value.(type) {
+			case []byte:
+				rv.SetBytes(v)
+				return nil
+			case string:
+				rv.SetBytes([]byte(v))
+				return nil
+			}
+		}
+
+		arr, ok := value.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(arr), len(arr))
+		for i, v := range arr {
+			if err := assign(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+	case reflect.Array:
+		arr, ok := value.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		for i := 0; i < rv.Len() && i < len(arr); i++ {
+			if err := assign(rv.Index(i), arr[i]); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return mismatch()
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		for k, v := range m {
+			key := reflect.New(rv.Type().Key()).Elem()
+			if err := assign(key, k); err != nil {
+				return err
+			}
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := assign(elem, v); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, elem)
+		}
+	case reflect.Struct:
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return mismatch()
+		}
+		fields := structFields(rv.Type())
+		for k, v := range m {
+			name, ok := k.(string)
+			if !ok {
+				continue
+			}
+			for _, f := range fields {
+				if f.name == name || strings.EqualFold(f.name, name) {
+					if err := assign(fieldByIndex(rv, f.index), v); err != nil {
+						return err
+					}
+					break
+				}
+			}
+		}
+	default:
+		return mismatch()
+	}
+
+	return nil
+}
+
+// fieldByIndex is like the `reflect.Value#FieldByIndex`
+// but it allocates the nil embedded pointers.
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(idx)
+	}
+	return rv
+}
+
+// generic converts the maps with string keys to map[string]interface{},
+// so they can be used like the JSON ones.
+func generic(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = generic(v[i])
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			s, ok := k.(string)
+			if !ok {
+				for k, e := range v {
+					v[k] = generic(e)
+				}
+				return v
+			}
+			m[s] = generic(e)
+		}
+		return m
+	}
+
+	return value
+}
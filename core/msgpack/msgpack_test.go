@@ -0,0 +1,115 @@
+package msgpack
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testAddress struct {
+	City string `msgpack:"city"`
+}
+
+type testUser struct {
+	testAddress
+	Name     string            `msgpack:"name"`
+	Age      int               `msgpack:"age"`
+	Balance  float64           `msgpack:"balance"`
+	Tags     []string          `msgpack:"tags"`
+	Avatar   []byte            `msgpack:"avatar"`
+	Meta     map[string]uint64 `msgpack:"meta"`
+	Manager  *testUser         `msgpack:"manager,omitempty"`
+	Created  time.Time         `msgpack:"created"`
+	Password string            `msgpack:"-"`
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	created, _ := time.Parse(time.RFC3339, "2018-03-01T10:00:00Z")
+	expected := testUser{
+		testAddress: testAddress{City: "Athens"},
+		Name:        "makis",
+		Age:         -1000,
+		Balance:     3.14,
+		Tags:        []string{"a", "b"},
+		Avatar:      []byte{0, 1, 2},
+		Meta:        map[string]uint64{"big": 1 << 40},
+		Manager:     &testUser{Name: "gerasimos", Age: 40, Created: created},
+		Created:     created,
+		Password:    "secret",
+	}
+
+	b, err := Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got testUser
+	if err = Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected.Password = ""
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected:\n%#v\nbut got:\n%#v", expected, got)
+	}
+
+	var generic map[string]interface{}
+	if err = Unmarshal(b, &generic); err != nil {
+		t.Fatal(err)
+	}
+
+	if name := generic["name"]; name != "makis" {
+		t.Fatalf("expected name to be 'makis' but got %v", name)
+	}
+
+	if err = Unmarshal(b[:len(b)-1], &got); err == nil {
+		t.Fatalf("expected an error on truncated data")
+	}
+}
+
+func TestUnmarshalMalformed(t *testing.T) {
+	deep := bytes.Repeat([]byte{0x91}, MaxDepth+1) // nested arrays of one element.
+	deep = append(deep, 0xc0)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"array key", []byte{0x81, 0x90, 0xc0}},
+		{"map key", []byte{0x81, 0x80, 0xc0}},
+		{"too deep", deep},
+		{"short string", []byte{0xa5, 'a'}},
+		{"short array", []byte{0xdc, 0xff, 0xff}},
+		{"short map", []byte{0xdf, 0xff, 0xff, 0xff, 0xff}},
+		{"unsupported code", []byte{0xc1}},
+	}
+
+	for _, tt := range tests {
+		var v interface{}
+		if err := Unmarshal(tt.data, &v); err == nil {
+			t.Fatalf("[%s] expected an error but got: %#v", tt.name, v)
+		}
+	}
+
+	var u testUser
+	if err := Unmarshal([]byte{0x81, 0xa4, 'n', 'a', 'm', 'e', 0x90}, &u); err == nil {
+		t.Fatalf("expected an error on a mismatched field type")
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	b, _ := Marshal(testUser{Name: "makis", Tags: []string{"a"}, Meta: map[string]uint64{"k": 1}})
+	f.Add(b)
+	f.Add([]byte{0x81, 0x90, 0xc0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var (
+			u testUser
+			v interface{}
+		)
+		Unmarshal(data, &u)
+		Unmarshal(data, &v)
+	})
+}
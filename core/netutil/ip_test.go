@@ -0,0 +1,40 @@
+package netutil
+
+import (
+	"testing"
+)
+
+func TestParseIPNet(t *testing.T) {
+	tests := []struct {
+		s       string
+		network string
+		valid   bool
+	}{
+		{"10.0.0.0/8", "10.0.0.0/8", true},
+		{" 10.1.2.3/8 ", "10.0.0.0/8", true},
+		{"127.0.0.1", "127.0.0.1/32", true},
+		{"::1", "::1/128", true},
+		{"2001:db8::/32", "2001:db8::/32", true},
+		{"localhost", "", false},
+		{"10.0.0.0/33", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		ipNet, err := ParseIPNet(tt.s)
+		if !tt.valid {
+			if err == nil {
+				t.Fatalf("expected '%s' to be invalid but got: %s", tt.s, ipNet)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := ipNet.String(); got != tt.network {
+			t.Fatalf("expected the network of '%s' to be '%s' but got: '%s'", tt.s, tt.network, got)
+		}
+	}
+}
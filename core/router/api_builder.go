@@ -4,11 +4,14 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kataras/iris/context"
 	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/core/netutil"
 	"github.com/kataras/iris/core/router/macro"
 )
 
@@ -39,6 +42,11 @@ var (
 // all the routes.
 type repository struct {
 	routes []*Route
+	// the name->route index, it's re-built by the router handler on build
+	// because route names can be changed after registration,
+	// it's validated on each lookup, see `get`.
+	// Duplicate names are reported by the router handler on build as well.
+	names map[string]*Route
 }
 
 func (r *repository) register(route *Route) {
@@ -49,12 +57,24 @@ func (r *repository) register(route *Route) {
 	}
 
 	r.routes = append(r.routes, route)
+
+	if r.names == nil {
+		r.names = make(map[string]*Route)
+	}
+	if _, exists := r.names[route.Name]; !exists {
+		r.names[route.Name] = route
+	}
 }
 
 func (r *repository) get(routeName string) *Route {
-	for _, r := range r.routes {
-		if r.Name == routeName {
-			return r
+	if route, ok := r.names[routeName]; ok && route.Name == routeName {
+		return route
+	}
+
+	// the route was renamed after build, fallback to the slow way.
+	for _, route := range r.routes {
+		if route.Name == routeName {
+			return route
 		}
 	}
 	return nil
@@ -71,6 +91,9 @@ type APIBuilder struct {
 	macros *macro.Map
 	// the api builder global handlers per status code registry (used for custom http errors)
 	errorCodeHandlers *ErrorCodeHandlers
+	// the api builder global handlers per status code registry for each `Host` party,
+	// key is the hostname, without the port.
+	hostErrorCodeHandlers map[string]*ErrorCodeHandlers
 	// the api builder global routes repository
 	routes *repository
 	// the api builder global route path reverser object
@@ -107,6 +130,8 @@ type APIBuilder struct {
 
 	// the per-party (and its children) execution rules for begin, main and done handlers.
 	handlerExecutionRules ExecutionRules
+	// the per-party (and its children) routes' compression, nil if not set, see `Compress`.
+	compress *bool
 }
 
 var _ Party = (*APIBuilder)(nil)
@@ -117,10 +142,11 @@ var _ RoutesProvider = (*APIBuilder)(nil) // passed to the default request handl
 func NewAPIBuilder() *APIBuilder {
 	api := &APIBuilder{
 		macros:            defaultMacros(),
-		errorCodeHandlers: defaultErrorCodeHandlers(),
-		reporter:          errors.NewReporter(),
-		relativePath:      "/",
-		routes:            new(repository),
+		errorCodeHandlers:     defaultErrorCodeHandlers(),
+		hostErrorCodeHandlers: make(map[string]*ErrorCodeHandlers),
+		reporter:              errors.NewReporter(),
+		relativePath:          "/",
+		routes:                new(repository),
 	}
 
 	return api
@@ -182,6 +208,16 @@ func (api *APIBuilder) SetExecutionRules(executionRules ExecutionRules) Party {
 	return api
 }
 
+// Compress sets the default compression of this Party's (and its children) routes,
+// the routes that will be registered after this call.
+// A route can override it by its `Route#Compress`.
+//
+// Returns this Party.
+func (api *APIBuilder) Compress(enable bool) Party {
+	api.compress = &enable
+	return api
+}
+
 // Handle registers a route to the server's api.
 // if empty method is passed then handler(s) are being registered to all methods, same as .Any.
 //
@@ -252,10 +288,16 @@ func (api *APIBuilder) Handle(method string, relativePath string, handlers ...co
 			return nil // fail on first error.
 		}
 
+		route.mainHandlersLen = len(mainHandlers)
+
 		// Add UseGlobal & DoneGlobal Handlers
 		route.use(api.beginGlobalHandlers)
 		route.done(api.doneGlobalHandlers)
 
+		if api.compress != nil {
+			route.Compress(*api.compress)
+		}
+
 		// global
 		api.routes.register(route)
 	}
@@ -263,6 +305,122 @@ func (api *APIBuilder) Handle(method string, relativePath string, handlers ...co
 	return route
 }
 
+// RegisterAutoOptions registers an OPTIONS route for each registered path
+// that has not an OPTIONS route already, it responds with 204 No Content
+// and the "Allow" header of the path's methods.
+// The `UseGlobal` and `DoneGlobal` handlers, i.e a CORS middleware,
+// are executed for these routes as well.
+//
+// It's called automatically on `Application#Build` when the `Configuration#AutoOptions` is true,
+// so the routes should be registered before that.
+func (api *APIBuilder) RegisterAutoOptions() {
+	type pathKey struct{ subdomain, tmpl string }
+
+	var (
+		keys       []pathKey
+		hasOptions = make(map[pathKey]bool)
+	)
+
+	for _, r := range api.routes.routes {
+		if r.Method == MethodNone {
+			continue
+		}
+
+		key := pathKey{r.Subdomain, r.tmpl.Src}
+		if _, seen := hasOptions[key]; !seen {
+			keys = append(keys, key)
+		}
+		hasOptions[key] = hasOptions[key] || r.Method == http.MethodOptions
+	}
+
+	for _, key := range keys {
+		if hasOptions[key] {
+			continue // explicit OPTIONS handlers take precedence.
+		}
+
+		route, err := NewRoute(http.MethodOptions, key.subdomain, key.tmpl, "autoOptions",
+			context.Handlers{autoOptionsHandler(api.routes, key.subdomain, key.tmpl)}, api.macros)
+		if err != nil {
+			api.reporter.Add("%v -> %s:%s:%s", err, http.MethodOptions, key.subdomain, key.tmpl)
+			continue
+		}
+
+		route.use(api.beginGlobalHandlers)
+		route.done(api.doneGlobalHandlers)
+		api.routes.register(route)
+	}
+}
+
+// RegisterAutoHead registers a HEAD route for each GET route
+// that has not a HEAD route on the same path already.
+// The HEAD route runs the whole handlers chain of its GET route, including the global ones,
+// and it sends the same status code and headers but it discards the response body,
+// so the load balancers and the link checkers do not get a 404 or 405 on HEAD requests.
+//
+// It's called automatically on `Application#Build` when the `Configuration#AutoHead` is true,
+// so the routes should be registered before that.
+func (api *APIBuilder) RegisterAutoHead() {
+	type pathKey struct{ subdomain, tmpl string }
+
+	var (
+		getRoutes []*Route
+		hasHead   = make(map[pathKey]bool)
+	)
+
+	for _, r := range api.routes.routes {
+		switch r.Method {
+		case http.MethodGet:
+			getRoutes = append(getRoutes, r)
+		case http.MethodHead:
+			hasHead[pathKey{r.Subdomain, r.tmpl.Src}] = true
+		}
+	}
+
+	for _, r := range getRoutes {
+		if hasHead[pathKey{r.Subdomain, r.tmpl.Src}] {
+			continue // explicit HEAD handlers take precedence.
+		}
+
+		head := *r
+		head.Method = http.MethodHead
+		head.Name = http.MethodHead + r.Subdomain + r.tmpl.Src
+		head.Handlers = joinHandlers(r.Handlers, nil)
+		head.beginHandlers = joinHandlers(context.Handlers{discardBodyHandler}, r.beginHandlers)
+		head.doneHandlers = joinHandlers(r.doneHandlers, nil)
+		api.routes.register(&head)
+	}
+}
+
+// discardBodyHandler records the response of the next handlers
+// and discards its body, the status code and the headers are kept.
+func discardBodyHandler(ctx context.Context) {
+	ctx.Record()
+	ctx.Next()
+
+	if recorder, ok := ctx.IsRecording(); ok {
+		if body := recorder.Body(); len(body) > 0 && recorder.Header().Get("Content-Length") == "" {
+			recorder.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+		recorder.ResetBody()
+	}
+}
+
+func autoOptionsHandler(routes *repository, subdomain, tmpl string) context.Handler {
+	return func(ctx context.Context) {
+		var methods []string
+		for _, r := range routes.routes {
+			if r.Method != MethodNone && r.Subdomain == subdomain && r.tmpl.Src == tmpl {
+				methods = append(methods, r.Method)
+			}
+		}
+		sort.Strings(methods)
+
+		ctx.Header("Allow", strings.Join(methods, ", "))
+		ctx.StatusCode(http.StatusNoContent)
+		ctx.Next()
+	}
+}
+
 // HandleMany works like `Handle` but can receive more than one
 // paths separated by spaces and returns always a slice of *Route instead of a single instance of Route.
 //
@@ -302,6 +460,24 @@ func (api *APIBuilder) HandleMany(methodOrMulti string, relativePathorMulti stri
 	return
 }
 
+// HandleIf works like `Handle` but the route is served only when the "flag" returns true,
+// otherwise the client receives a 404 Not Found, as the route was never registered.
+// The "flag" is evaluated on each request, so a feature can be toggled on and off
+// at serve-time without a redeploy.
+//
+// Usage:
+// 	app.HandleIf(func(ctx iris.Context) bool { return betaEnabled.Load().(bool) }, "GET", "/beta", betaHandler)
+//
+// Returns the read-only route information.
+func (api *APIBuilder) HandleIf(flag func(context.Context) bool, method string, relativePath string, handlers ...context.Handler) *Route {
+	route := api.Handle(method, relativePath, handlers...)
+	if route != nil {
+		route.flag = flag
+	}
+
+	return route
+}
+
 // Party groups routes which may have the same prefix and share same handlers,
 // returns that new rich subrouter.
 //
@@ -340,17 +516,19 @@ func (api *APIBuilder) Party(relativePath string, handlers ...context.Handler) P
 	return &APIBuilder{
 		// global/api builder
 		macros:              api.macros,
-		routes:              api.routes,
-		errorCodeHandlers:   api.errorCodeHandlers,
-		beginGlobalHandlers: api.beginGlobalHandlers,
-		doneGlobalHandlers:  api.doneGlobalHandlers,
-		reporter:            api.reporter,
+		routes:                api.routes,
+		errorCodeHandlers:     api.errorCodeHandlers,
+		hostErrorCodeHandlers: api.hostErrorCodeHandlers,
+		beginGlobalHandlers:   api.beginGlobalHandlers,
+		doneGlobalHandlers:    api.doneGlobalHandlers,
+		reporter:              api.reporter,
 		// per-party/children
 		middleware:            middleware,
 		doneHandlers:          api.doneHandlers[0:],
 		relativePath:          fullpath,
 		allowMethods:          allowMethods,
 		handlerExecutionRules: api.handlerExecutionRules,
+		compress:              api.compress,
 	}
 }
 
@@ -397,6 +575,58 @@ func (api *APIBuilder) Subdomain(subdomain string, middleware ...context.Handler
 	return api.Party(subdomain, middleware...)
 }
 
+// Host returns a new party which is responsible to register routes to
+// a specific, full, "hostname", i.e "api.example.com" or "admin.example.com",
+// it's not relative to the application's root domain like the `Subdomain`,
+// so many different domains can be served by the same listener.
+//
+// A host party is isolated from the rest of the application,
+// it does not inherit the middleware and the done handlers of its parent
+// (the `UseGlobal` and `DoneGlobal` ones are still executed)
+// and it has its own http error code handlers, registered through its `OnErrorCode`.
+//
+// Host can be called only from the root Party.
+//
+// Usage:
+// api := app.Host("api.example.com")
+// api.OnErrorCode(iris.StatusNotFound, apiNotFound)
+// api.Get("/users", listUsers)
+// admin := app.Host("admin.example.com", authMiddleware)
+// admin.Get("/", dashboard)
+func (api *APIBuilder) Host(hostname string, middleware ...context.Handler) Party {
+	if api.relativePath != "/" {
+		api.reporter.Add("host party '%s' can be registered only from the root party -> %s", hostname, api.relativePath)
+		return api
+	}
+
+	hostname = strings.ToLower(netutil.ResolveHostname(strings.TrimSuffix(hostname, ".")))
+	if hostname == "" {
+		return api
+	}
+
+	// the full hostname is stored as the routes' subdomain, with the dot suffix,
+	// the router handler matches it against the whole request's host.
+	p := api.Party(hostname + ".").(*APIBuilder)
+	p.middleware = joinHandlers(middleware, nil)
+	p.doneHandlers = nil
+	p.handlerExecutionRules = ExecutionRules{}
+
+	errorCodeHandlers, ok := api.hostErrorCodeHandlers[hostname]
+	if !ok {
+		errorCodeHandlers = defaultErrorCodeHandlers()
+		api.hostErrorCodeHandlers[hostname] = errorCodeHandlers
+	}
+	p.errorCodeHandlers = errorCodeHandlers
+
+	return p
+}
+
+// hostParty reports whether the routes' "subdomain" is the full hostname of a `Host` party.
+func (api *APIBuilder) hostParty(subdomain string) bool {
+	_, ok := api.hostErrorCodeHandlers[strings.TrimSuffix(subdomain, ".")]
+	return ok
+}
+
 // WildcardSubdomain returns a new party which is responsible to register routes to
 // a dynamic, wildcard(ed) subdomain. A dynamic subdomain is a subdomain which
 // can reply to any subdomain requests. Server will accept any subdomain
@@ -459,6 +689,20 @@ func (api *APIBuilder) Use(handlers ...context.Handler) {
 	api.middleware = append(api.middleware, handlers...)
 }
 
+// ResetMiddleware removes all the middleware of this Party,
+// including the ones inherited by its parent Party, so the next routes
+// of this Party and its children will not execute them,
+// i.e a "/webhooks" Party can opt-out of the application's authentication middleware.
+//
+// The `UseGlobal` handlers are not affected, they are still executed.
+// Middleware registered after the `ResetMiddleware` through `Use` are executed as expected.
+//
+// Returns itself.
+func (api *APIBuilder) ResetMiddleware() Party {
+	api.middleware = nil
+	return api
+}
+
 // UseGlobal registers handlers that should run at the very beginning.
 // It prepends those handler(s) to all routes,
 // including all parties, subdomains.
@@ -843,6 +1087,13 @@ func (api *APIBuilder) OnAnyErrorCode(handlers ...context.Handler) {
 // If a handler is not already registered,
 // then it creates & registers a new trivial handler on the-fly.
 func (api *APIBuilder) FireErrorCode(ctx context.Context) {
+	if len(api.hostErrorCodeHandlers) > 0 {
+		if h, ok := api.hostErrorCodeHandlers[strings.ToLower(netutil.ResolveHostname(ctx.Host()))]; ok {
+			h.Fire(ctx)
+			return
+		}
+	}
+
 	api.errorCodeHandlers.Fire(ctx)
 }
 
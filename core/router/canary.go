@@ -0,0 +1,120 @@
+package router
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/context"
+)
+
+// CanaryOptions are the options of the `Route#Canary`.
+type CanaryOptions struct {
+	// Percent is the percentage, 0-100, of the requests
+	// that are served by the canary handlers.
+	Percent int
+	// Cookie is the name of the cookie that keeps
+	// the version of a client, "stable" or "canary",
+	// so the same client is served by the same handlers on its next requests.
+	// Defaults to empty, no cookie is sent.
+	Cookie string
+	// Header is the name of the request header, i.e "X-User-Id",
+	// which its value is hashed to select the version of the client,
+	// the same value is always served by the same handlers.
+	// If the header is missing then the `Cookie` or the random selection is used.
+	// Defaults to empty.
+	Header string
+}
+
+const (
+	canaryVersionStable = "stable"
+	canaryVersionCanary = "canary"
+)
+
+// canary splits the traffic of a route between two handler chains.
+type canary struct {
+	opts     CanaryOptions
+	handlers context.Handlers
+
+	mu   sync.Mutex // protects the rand.
+	rand *rand.Rand
+}
+
+func newCanary(opts CanaryOptions, handlers context.Handlers) *canary {
+	if opts.Percent < 0 {
+		opts.Percent = 0
+	} else if opts.Percent > 100 {
+		opts.Percent = 100
+	}
+
+	return &canary{
+		opts:     opts,
+		handlers: handlers,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (c *canary) pick(ctx context.Context) bool {
+	if c.opts.Header != "" {
+		if v := ctx.GetHeader(c.opts.Header); v != "" {
+			h := fnv.New32a()
+			h.Write([]byte(v))
+			return int(h.Sum32()%100) < c.opts.Percent
+		}
+	}
+
+	if c.opts.Cookie != "" {
+		switch ctx.GetCookie(c.opts.Cookie) {
+		case canaryVersionCanary:
+			return true
+		case canaryVersionStable:
+			return false
+		}
+	}
+
+	c.mu.Lock()
+	n := c.rand.Intn(100)
+	c.mu.Unlock()
+
+	useCanary := n < c.opts.Percent
+	if c.opts.Cookie != "" {
+		version := canaryVersionStable
+		if useCanary {
+			version = canaryVersionCanary
+		}
+		ctx.SetCookieKV(c.opts.Cookie, version)
+	}
+
+	return useCanary
+}
+
+// handler returns the handler which executes the "stable" or the "canary" chain per request.
+func (c *canary) handler(stable, canary context.Handlers) context.Handler {
+	return func(ctx context.Context) {
+		handlers := stable
+		if c.pick(ctx) {
+			handlers = canary
+		}
+
+		ctx.HandlerIndex(0)
+		ctx.Do(handlers)
+	}
+}
+
+// build returns the canary chain of the route's "handlers",
+// it's the route's handlers with the main ones replaced by the canary handlers.
+func (c *canary) build(handlers context.Handlers, mainHandlerName string, mainHandlersLen int) context.Handlers {
+	if mainHandlersLen <= 0 {
+		mainHandlersLen = 1
+	}
+
+	for i, h := range handlers {
+		if context.HandlerName(h) == mainHandlerName && i+mainHandlersLen <= len(handlers) {
+			canaryHandlers := joinHandlers(handlers[:i], c.handlers)
+			return joinHandlers(canaryHandlers, handlers[i+mainHandlersLen:])
+		}
+	}
+
+	return c.handlers
+}
@@ -0,0 +1,7 @@
+package router
+
+// SetRouteTmplSrc changes the source of the "r" route's path template,
+// the tests use it to register an automatic route which can not be parsed.
+func SetRouteTmplSrc(r *Route, src string) {
+	r.tmpl.Src = src
+}
@@ -100,6 +100,7 @@ func StaticEmbeddedHandler(vdir string, assetFn func(name string) ([]byte, error
 			}
 
 			ctx.ContentType(cType)
+			ctx.Header(contentTypeOptionsHeaderKey, "nosniff")
 			if _, err := ctx.Write(buf); err != nil {
 				ctx.StatusCode(http.StatusInternalServerError)
 				ctx.StopExecution()
@@ -142,6 +143,7 @@ func StaticHandler(systemPath string, showList bool, gzip bool) context.Handler
 type StaticHandlerBuilder interface {
 	Gzip(enable bool) StaticHandlerBuilder
 	Listing(listDirectoriesOnOff bool) StaticHandlerBuilder
+	NoSniff(enable bool) StaticHandlerBuilder
 	Build() context.Handler
 }
 
@@ -156,6 +158,7 @@ type fsHandler struct {
 	directory       http.Dir
 	listDirectories bool
 	gzip            bool
+	noSniff         bool
 	// these are init on the Build() call
 	filesystem http.FileSystem
 	once       sync.Once
@@ -207,6 +210,18 @@ func (w *fsHandler) Gzip(enable bool) StaticHandlerBuilder {
 	return w
 }
 
+// NoSniff if enable is true then the "X-Content-Type-Options: nosniff" header is sent
+// and files with unknown extensions that look like html documents
+// are served as "text/plain" instead, so browsers will never execute
+// user-uploaded contents as html pages (stored-XSS),
+// enable it when the directory serves contents uploaded by the users.
+//
+// Defaults to false.
+func (w *fsHandler) NoSniff(enable bool) StaticHandlerBuilder {
+	w.noSniff = enable
+	return w
+}
+
 // Listing turn on/off the 'show files and directories'.
 //
 // Defaults to false.
@@ -260,6 +275,10 @@ func (w *fsHandler) Build() context.Handler {
 			// so on custom errors we use the requesturi instead.
 			// this can be changed.
 
+			if w.noSniff {
+				ctx.Header(contentTypeOptionsHeaderKey, "nosniff")
+			}
+
 			// take the gzip setting.
 			gzipEnabled := w.gzip
 			if !gzipEnabled {
@@ -395,6 +414,8 @@ var errNoOverlap = errors.New("invalid range: failed to overlap")
 // The algorithm uses at most sniffLen bytes to make its decision.
 const sniffLen = 512
 
+const contentTypeOptionsHeaderKey = "X-Content-Type-Options"
+
 func detectOrWriteContentType(ctx context.Context, name string, content io.ReadSeeker) (string, error) {
 	// If Content-Type isn't set, use the file's extension to find it, but
 	// if the Content-Type is unset explicitly, do not sniff the type.
@@ -408,6 +429,11 @@ func detectOrWriteContentType(ctx context.Context, name string, content io.ReadS
 			var buf [sniffLen]byte
 			n, _ := io.ReadFull(content, buf[:])
 			ctype = http.DetectContentType(buf[:n])
+			if strings.HasPrefix(ctype, "text/html") &&
+				ctx.ResponseWriter().Header().Get(contentTypeOptionsHeaderKey) == "nosniff" {
+				// do not let unknown files to be executed as html documents by the browser.
+				ctype = "text/plain; charset=utf-8"
+			}
 			_, err := content.Seek(0, io.SeekStart) // rewind to output whole file
 			if err != nil {
 				return "", err
@@ -0,0 +1,46 @@
+package router_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/core/router"
+	"github.com/kataras/iris/httptest"
+)
+
+func TestStaticHandlerNoSniff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "upload.dat"), []byte("<html><script>alert(1)</script></html>"), os.FileMode(0644)); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "page.html"), []byte("<html></html>"), os.FileMode(0644)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the contents are sniffed by default, as before.
+	app := iris.New()
+	app.Get("/{file:path}", router.NewStaticHandlerBuilder(dir).Build())
+	e := httptest.New(t, app)
+	r := e.GET("/upload.dat").Expect().Status(iris.StatusOK)
+	r.Header("Content-Type").Equal("text/html; charset=utf-8")
+	r.Header("X-Content-Type-Options").Empty()
+
+	app = iris.New()
+	app.Get("/{file:path}", router.NewStaticHandlerBuilder(dir).NoSniff(true).Build())
+	e = httptest.New(t, app)
+	r = e.GET("/upload.dat").Expect().Status(iris.StatusOK)
+	r.Header("Content-Type").Equal("text/plain; charset=utf-8")
+	r.Header("X-Content-Type-Options").Equal("nosniff")
+	// the files with a known html extension are still served as html documents.
+	r = e.GET("/page.html").Expect().Status(iris.StatusOK)
+	r.Header("Content-Type").Equal("text/html; charset=utf-8")
+	r.Header("X-Content-Type-Options").Equal("nosniff")
+}
@@ -31,12 +31,23 @@ type tree struct {
 	// subdomain is empty for default-hostname routes,
 	// ex: mysubdomain.
 	Subdomain string
-	Nodes     *node.Nodes
+	// Host is true when the subdomain is the full hostname of a `Party#Host`,
+	// it's matched against the whole request's host.
+	Host  bool
+	Nodes *node.Nodes
+}
+
+// hostsProvider is implemented by the `APIBuilder`,
+// it reports whether a routes' subdomain was registered through the `Party#Host`.
+type hostsProvider interface {
+	hostParty(subdomain string) bool
 }
 
 type routerHandler struct {
 	trees []*tree
 	hosts bool // true if at least one route contains a Subdomain.
+	// the parameters' types of the routes, by their names, see `RequestParams#GetType`.
+	paramTypes map[string]map[string]string
 }
 
 var _ RequestHandler = &routerHandler{}
@@ -52,7 +63,7 @@ func (h *routerHandler) getTree(method, subdomain string) *tree {
 	return nil
 }
 
-func (h *routerHandler) addRoute(r *Route) error {
+func (h *routerHandler) addRoute(r *Route, hosts hostsProvider) error {
 	var (
 		routeName = r.Name
 		method    = r.Method
@@ -67,11 +78,27 @@ func (h *routerHandler) addRoute(r *Route) error {
 		n := node.Nodes{}
 		// first time we register a route to this method with this subdomain
 		t = &tree{Method: method, Subdomain: subdomain, Nodes: &n}
+		t.Host = hosts != nil && hosts.hostParty(subdomain)
 		h.trees = append(h.trees, t)
 	}
+
+	if params := r.Tmpl().Params; len(params) > 0 {
+		types := make(map[string]string, len(params))
+		for _, p := range params {
+			types[p.Name] = p.Type.String()
+		}
+		h.paramTypes[routeName] = types
+	}
+
 	return t.Nodes.Add(routeName, path, handlers)
 }
 
+// isHost reports whether the "requestHost" is the full hostname
+// of a tree which was registered through the `Party#Host`.
+func isHost(requestHost, subdomain string) bool {
+	return strings.EqualFold(netutil.ResolveHostname(requestHost)+".", subdomain)
+}
+
 // NewDefaultHandler returns the handler which is responsible
 // to map the request with a route (aka mux implementation).
 func NewDefaultHandler() RequestHandler {
@@ -89,6 +116,7 @@ type RoutesProvider interface { // api builder
 func (h *routerHandler) Build(provider RoutesProvider) error {
 	registeredRoutes := provider.GetRoutes()
 	h.trees = h.trees[0:0] // reset, inneed when rebuilding.
+	h.paramTypes = make(map[string]map[string]string)
 
 	// sort, subdomains goes first.
 	sort.Slice(registeredRoutes, func(i, j int) bool {
@@ -122,9 +150,24 @@ func (h *routerHandler) Build(provider RoutesProvider) error {
 
 	})
 
+	hosts, _ := provider.(hostsProvider)
+
 	rp := errors.NewReporter()
+	// route names should be unique, they are used by the reverse routing and the `ctx.Exec`.
+	names := make(map[string]*Route, len(registeredRoutes))
 
 	for _, r := range registeredRoutes {
+		if prev, exists := names[r.Name]; exists {
+			rp.Add("route name '%s' is already used by '%s' -> %s", r.Name, prev.String(), r.String())
+			continue
+		}
+		names[r.Name] = r
+
+		if r.err != nil {
+			rp.Add("%v -> %s", r.err, r.String())
+			continue
+		}
+
 		// build the r.Handlers based on begin and done handlers, if any.
 		r.BuildHandlers()
 
@@ -136,7 +179,7 @@ func (h *routerHandler) Build(provider RoutesProvider) error {
 		// on route, it will be stacked shown in this build state
 		// and no in the lines of the user's action, they should read
 		// the docs better. Or TODO: add a link here in order to help new users.
-		if err := h.addRoute(r); err != nil {
+		if err := h.addRoute(r, hosts); err != nil {
 			// node errors:
 			rp.Add("%v -> %s", err, r.String())
 			continue
@@ -144,12 +187,58 @@ func (h *routerHandler) Build(provider RoutesProvider) error {
 		golog.Debugf(r.Trace())
 	}
 
+	if api, ok := provider.(*APIBuilder); ok {
+		// update the name->route index, before serve.
+		api.routes.names = names
+	}
+
 	return rp.Return()
 }
 
+// redirectCorrectedPath redirects the client to the "url" of a corrected or normalized request path.
+func redirectCorrectedPath(ctx context.Context, method, url string) {
+	// Fixes https://github.com/kataras/iris/issues/921
+	// This is caused for security reasons, imagine a payment shop,
+	// you can't just permantly redirect a POST request, so just 307 (RFC 7231, 6.4.7).
+	if method == http.MethodPost || method == http.MethodPut {
+		ctx.Redirect(url, http.StatusTemporaryRedirect)
+		return
+	}
+
+	ctx.Redirect(url, http.StatusMovedPermanently)
+
+	// RFC2616 recommends that a short note "SHOULD" be included in the
+	// response because older user agents may not understand 301/307.
+	// Shouldn't send the response for POST or HEAD; that leaves GET.
+	if method == http.MethodGet {
+		note := "<a href=\"" +
+			html.EscapeString(url) +
+			"\">" + html.EscapeString(ctx.StatusText(http.StatusMovedPermanently)) + "</a>.\n"
+
+		ctx.ResponseWriter().WriteString(note)
+	}
+}
+
 func (h *routerHandler) HandleRequest(ctx context.Context) {
 	method := ctx.Method()
 	path := ctx.Path()
+
+	if opts := ctx.Application().ConfigurationReadOnly().GetPathNormalization(); opts.Enabled() {
+		if normalized := context.NormalizePath(path, opts); normalized != path {
+			r := ctx.Request()
+			r.URL.RawPath = ""
+			if opts.Redirect {
+				// use Trim to ensure there is no open redirect due to two leading slashes
+				r.URL.Path = "/" + strings.TrimLeft(normalized, "/")
+				redirectCorrectedPath(ctx, method, r.URL.String())
+				return
+			}
+
+			r.URL.Path = normalized
+			path = ctx.Path()
+		}
+	}
+
 	if !ctx.Application().ConfigurationReadOnly().GetDisablePathCorrection() {
 
 		if len(path) > 1 && strings.HasSuffix(path, "/") {
@@ -161,28 +250,7 @@ func (h *routerHandler) HandleRequest(ctx context.Context) {
 			// use Trim to ensure there is no open redirect due to two leading slashes
 			path = "/" + strings.Trim(path, "/")
 			r.URL.Path = path
-			url := r.URL.String()
-
-			// Fixes https://github.com/kataras/iris/issues/921
-			// This is caused for security reasons, imagine a payment shop,
-			// you can't just permantly redirect a POST request, so just 307 (RFC 7231, 6.4.7).
-			if method == http.MethodPost || method == http.MethodPut {
-				ctx.Redirect(url, http.StatusTemporaryRedirect)
-				return
-			}
-
-			ctx.Redirect(url, http.StatusMovedPermanently)
-
-			// RFC2616 recommends that a short note "SHOULD" be included in the
-			// response because older user agents may not understand 301/307.
-			// Shouldn't send the response for POST or HEAD; that leaves GET.
-			if method == http.MethodGet {
-				note := "<a href=\"" +
-					html.EscapeString(url) +
-					"\">Moved Permanently</a>.\n"
-
-				ctx.ResponseWriter().WriteString(note)
-			}
+			redirectCorrectedPath(ctx, method, r.URL.String())
 			return
 		}
 	}
@@ -220,6 +288,10 @@ func (h *routerHandler) HandleRequest(ctx context.Context) {
 					continue
 				}
 				// continue to that, any subdomain is valid.
+			} else if t.Host {
+				if !isHost(requestHost, t.Subdomain) {
+					continue
+				}
 			} else if !strings.HasPrefix(requestHost, t.Subdomain) { // t.Subdomain contains the dot.
 				continue
 			}
@@ -227,6 +299,7 @@ func (h *routerHandler) HandleRequest(ctx context.Context) {
 		routeName, handlers := t.Nodes.Find(path, ctx.Params())
 		if len(handlers) > 0 {
 			ctx.SetCurrentRouteName(routeName)
+			ctx.Params().SetTypes(h.paramTypes[routeName])
 			ctx.Do(handlers)
 			// found
 			return
@@ -292,6 +365,10 @@ func (h *routerHandler) RouteExists(ctx context.Context, method, path string) bo
 					continue
 				}
 				// continue to that, any subdomain is valid.
+			} else if t.Host {
+				if !isHost(requestHost, t.Subdomain) {
+					continue
+				}
 			} else if !strings.HasPrefix(requestHost, t.Subdomain) { // t.Subdomain contains the dot.
 				continue
 			}
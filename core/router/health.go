@@ -0,0 +1,115 @@
+package router
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+)
+
+// HealthCheck is a named check which is executed by the `RegisterHealthChecks` route.
+// A non-nil error returned by its `Check` marks the check, and the whole service, as "down".
+// The `Check` is required.
+type HealthCheck struct {
+	Name  string
+	Check func() error
+}
+
+// errHealthCheckMissing is reported by the `RegisterHealthChecks` and returned by a `HealthCheck` without its `Check`.
+var errHealthCheckMissing = errors.New("health check '%s' has no Check function")
+
+// Health check statuses, see `HealthCheckResult` and `HealthReport`.
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// HealthCheckResult is the result of a single `HealthCheck`, it's part of the `HealthReport`.
+type HealthCheckResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Latency string `json:"latency"` // i.e "1.2ms".
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthReport is the JSON response of the `RegisterHealthChecks` route.
+type HealthReport struct {
+	Status string              `json:"status"`
+	Checks []HealthCheckResult `json:"checks"`
+}
+
+// RunHealthChecks executes all "checks" concurrently and returns their aggregated report.
+// The report's Status is "up" only if all checks passed.
+func RunHealthChecks(checks ...HealthCheck) HealthReport {
+	report := HealthReport{
+		Status: HealthStatusUp,
+		Checks: make([]HealthCheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(checks))
+	for i, c := range checks {
+		go func(i int, c HealthCheck) {
+			defer wg.Done()
+			result := HealthCheckResult{Name: c.Name, Status: HealthStatusUp}
+			start := time.Now()
+			if c.Check == nil {
+				result.Status = HealthStatusDown
+				result.Error = errHealthCheckMissing.Format(c.Name).Error()
+			} else if err := c.Check(); err != nil {
+				result.Status = HealthStatusDown
+				result.Error = err.Error()
+			}
+			result.Latency = time.Since(start).String()
+			report.Checks[i] = result
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != HealthStatusUp {
+			report.Status = HealthStatusDown
+			break
+		}
+	}
+
+	return report
+}
+
+// HealthChecksHandler returns a handler which writes the `HealthReport` of the "checks" as JSON,
+// the status code is 200 when all checks passed, otherwise 503.
+func HealthChecksHandler(checks ...HealthCheck) context.Handler {
+	return func(ctx context.Context) {
+		report := RunHealthChecks(checks...)
+		if report.Status != HealthStatusUp {
+			ctx.StatusCode(http.StatusServiceUnavailable)
+		}
+
+		ctx.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+		ctx.JSON(report)
+	}
+}
+
+// RegisterHealthChecks registers a GET route to the "relativePath"
+// which runs the "checks" on each request and responds with their `HealthReport` as JSON,
+// the status code is 200 when all checks passed, otherwise 503.
+//
+// A check without its `Check` function is reported as an error of the application's build
+// and the route is not registered.
+//
+// Usage:
+// app.RegisterHealthChecks("/healthz", router.HealthCheck{Name: "db", Check: db.Ping})
+//
+// Returns the new route.
+func (api *APIBuilder) RegisterHealthChecks(relativePath string, checks ...HealthCheck) *Route {
+	for _, c := range checks {
+		if c.Check == nil {
+			api.reporter.Add("%v -> GET %s", errHealthCheckMissing.Format(c.Name), relativePath)
+			return nil
+		}
+	}
+
+	return api.Get(relativePath, HealthChecksHandler(checks...))
+}
@@ -0,0 +1,88 @@
+package router
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+)
+
+var errInvalidIP = errors.New("invalid ip address or cidr '%s'")
+
+// ipFilter is the route's filter of the client IP addresses,
+// see `Route#AllowIP`, `Route#DenyIP` and `Route#OnIPDenied`.
+type ipFilter struct {
+	allow    []*net.IPNet
+	deny     []*net.IPNet
+	onDenied context.Handler
+}
+
+func (f *ipFilter) allowed(ip net.IP) bool {
+	if ip == nil {
+		return len(f.allow) == 0 && len(f.deny) == 0
+	}
+
+	if containsIP(f.deny, ip) {
+		return false
+	}
+
+	return len(f.allow) == 0 || containsIP(f.allow, ip)
+}
+
+func (f *ipFilter) handler(ctx context.Context) {
+	// the forwarded headers are trusted only when they are sent by the trusted proxies.
+	if f.allowed(net.ParseIP(ctx.ClientIP())) {
+		ctx.Next()
+		return
+	}
+
+	if f.onDenied != nil {
+		f.onDenied(ctx)
+	} else {
+		ctx.StatusCode(http.StatusForbidden)
+	}
+
+	ctx.StopExecution()
+}
+
+func containsIP(ipNets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIPNets parses CIDRs, i.e "10.0.0.0/8", and single IP addresses, i.e "192.168.1.2".
+func parseIPNets(cidrs []string) ([]*net.IPNet, error) {
+	ipNets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, errInvalidIP.Format(cidr)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+
+			ipNets = append(ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errInvalidIP.Format(cidr)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+
+	return ipNets, nil
+}
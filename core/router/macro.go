@@ -2,6 +2,7 @@ package router
 
 import (
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -37,6 +38,7 @@ func registerBuiltinsMacroFuncs(out *macro.Map) {
 	registerStringMacroFuncs(out.String)
 	registerIntMacroFuncs(out.Int)
 	registerIntMacroFuncs(out.Long)
+	registerUint64MacroFuncs(out.Uint64)
 	registerAlphabeticalMacroFuncs(out.Alphabetical)
 	registerFileMacroFuncs(out.File)
 	registerPathMacroFuncs(out.Path)
@@ -66,6 +68,19 @@ func registerStringMacroFuncs(out *macro.Macro) {
 		}
 	})
 
+	// checks if param value is one of the comma separated 'values' arg, i.e within(json,csv,xml)
+	out.RegisterFunc("within", func(values string) macro.EvaluatorFunc {
+		valid := strings.Split(values, ",")
+		return func(paramValue string) bool {
+			for _, v := range valid {
+				if strings.TrimSpace(v) == paramValue {
+					return true
+				}
+			}
+			return false
+		}
+	})
+
 	// checks if param value contains the 's' arg
 	out.RegisterFunc("contains", func(s string) macro.EvaluatorFunc {
 		return func(paramValue string) bool {
@@ -131,6 +146,57 @@ func registerIntMacroFuncs(out *macro.Macro) {
 	})
 }
 
+// Uint64
+// only numbers (0-9) which fit to an uint64
+func registerUint64MacroFuncs(out *macro.Macro) {
+	// the macro function's arguments are ints,
+	// a negative "min" is always passed and a negative "max" is never passed.
+	inRange := func(paramValue string, min, max int) bool {
+		n, err := strconv.ParseUint(paramValue, 10, 64)
+		if err != nil {
+			return false
+		}
+
+		if min > 0 && n < uint64(min) {
+			return false
+		}
+
+		return max < 0 || n <= uint64(max)
+	}
+
+	// checks if the param value's uint64 representation is
+	// bigger or equal than 'min'
+	out.RegisterFunc("min", func(min int) macro.EvaluatorFunc {
+		return func(paramValue string) bool {
+			return inRange(paramValue, min, -1) // -1 means no max.
+		}
+	})
+
+	// checks if the param value's uint64 representation is
+	// smaller or equal than 'max'
+	out.RegisterFunc("max", func(max int) macro.EvaluatorFunc {
+		if max < 0 {
+			return func(string) bool { return false }
+		}
+
+		return func(paramValue string) bool {
+			return inRange(paramValue, 0, max)
+		}
+	})
+
+	// checks if the param value's uint64 representation is
+	// between min and max, including 'min' and 'max'
+	out.RegisterFunc("range", func(min, max int) macro.EvaluatorFunc {
+		if max < 0 {
+			return func(string) bool { return false }
+		}
+
+		return func(paramValue string) bool {
+			return inRange(paramValue, min, max)
+		}
+	})
+}
+
 // Alphabetical
 // letters only (upper or lowercase)
 func registerAlphabeticalMacroFuncs(out *macro.Macro) {
@@ -186,6 +252,12 @@ func convertTmplToNodePath(tmpl *macro.Template) (string, error) {
 		}
 	}
 
+	// a segment with more than one parameters, i.e {name}.{format},
+	// is registered as a single parameter and it's split by the macro handler.
+	for _, seg := range compoundSegments(tmpl) {
+		routePath = strings.Replace(routePath, seg.src, Param(seg.params[0].Name), 1)
+	}
+
 	// if it has started with {} and it's valid
 	// then the tmpl.Params will be filled,
 	// so no any further check needed
@@ -208,6 +280,11 @@ func convertTmplToHandler(tmpl *macro.Template) context.Handler {
 
 	needMacroHandler := false
 
+	segments := compoundSegments(tmpl)
+	if len(segments) > 0 {
+		needMacroHandler = true
+	}
+
 	// check if we have params like: {name:string} or {name} or {anything:path} without else keyword or any functions used inside these params.
 	// 1. if we don't have, then we don't need to add a handler before the main route's handler (as I said, no performance if macro is not really used)
 	// 2. if we don't have any named params then we don't need a handler too.
@@ -226,10 +303,27 @@ func convertTmplToHandler(tmpl *macro.Template) context.Handler {
 
 	return func(tmpl macro.Template) context.Handler {
 		return func(ctx context.Context) {
+			for _, seg := range segments {
+				if !seg.split(ctx.Params()) {
+					ctx.StatusCode(seg.params[0].ErrCode)
+					ctx.StopExecution()
+					return
+				}
+			}
+
 			for _, p := range tmpl.Params {
 				paramValue := ctx.Params().Get(p.Name)
-				// first, check for type evaluator
-				if !p.TypeEvaluator(paramValue) {
+				// first, check for type evaluator,
+				// the converter is the evaluator of the types with typed values.
+				if p.TypeConverter != nil {
+					v, ok := p.TypeConverter(paramValue)
+					if !ok {
+						ctx.StatusCode(p.ErrCode)
+						ctx.StopExecution()
+						return
+					}
+					ctx.Params().SetValue(p.Name, v)
+				} else if !p.TypeEvaluator(paramValue) {
 					ctx.StatusCode(p.ErrCode)
 					ctx.StopExecution()
 					return
@@ -251,3 +345,63 @@ func convertTmplToHandler(tmpl *macro.Template) context.Handler {
 	}(*tmpl)
 
 }
+
+// compoundSegment is a path segment with more than one parameters,
+// i.e "{name}.{format:string within(json,csv,xml)}".
+type compoundSegment struct {
+	src    string
+	params []macro.TemplateParam
+	re     *regexp.Regexp
+}
+
+// split sets the parameters' values from the segment's value,
+// which is stored as the first parameter's value by the router.
+// The last parameter takes the value after the last separator,
+// i.e "report.2018.csv" gives "report.2018" and "csv".
+//
+// Returns false if the value does not match the segment.
+func (seg compoundSegment) split(params *context.RequestParams) bool {
+	matches := seg.re.FindStringSubmatch(params.Get(seg.params[0].Name))
+	if len(matches) != len(seg.params)+1 {
+		return false
+	}
+
+	for i, p := range seg.params {
+		params.Set(p.Name, matches[i+1])
+	}
+
+	return true
+}
+
+func compoundSegments(tmpl *macro.Template) (segments []compoundSegment) {
+	for _, s := range strings.Split(tmpl.Src, "/") {
+		var (
+			params []macro.TemplateParam
+			expr   = "^"
+			rest   = s
+		)
+
+		for _, p := range tmpl.Params {
+			idx := strings.Index(rest, p.Src)
+			if idx == -1 {
+				continue
+			}
+
+			expr += regexp.QuoteMeta(rest[:idx]) + "(.+)"
+			rest = rest[idx+len(p.Src):]
+			params = append(params, p)
+		}
+
+		if len(params) < 2 {
+			continue
+		}
+
+		segments = append(segments, compoundSegment{
+			src:    s,
+			params: params,
+			re:     regexp.MustCompile(expr + regexp.QuoteMeta(rest) + "$"),
+		})
+	}
+
+	return
+}
@@ -49,6 +49,23 @@ const (
 	// Allows anything, should be the last part
 	// Declaration: /mypath/{myparam:path}
 	ParamTypePath
+	// ParamTypeUint64 is the unsigned 64-bit integer, a number type.
+	// Allows only positive numbers (0-9) up to 18446744073709551615
+	// Declaration: /mypath/{myparam:uint64}
+	ParamTypeUint64
+	// ParamTypeUUID is the UUID type, i.e "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+	// Declaration: /mypath/{myparam:uuid}
+	ParamTypeUUID
+	// ParamTypeTime is the date or the date-time type,
+	// i.e "2018-05-02" or the RFC 3339 "2018-05-02T15:04:05Z".
+	// Declaration: /mypath/{myparam:time}
+	ParamTypeTime
+	// ParamTypeDecimal is the exact decimal number type, i.e "-12.30".
+	// Declaration: /mypath/{myparam:decimal}
+	ParamTypeDecimal
+
+	// the custom parameter types, see `RegisterParamType`, start from here.
+	paramTypeCustom
 )
 
 func (pt ParamType) String() string {
@@ -79,13 +96,23 @@ func (pt ParamType) Kind() reflect.Kind {
 		fallthrough
 	case ParamTypeString:
 		return reflect.String
+	case ParamTypeUUID, ParamTypeTime, ParamTypeDecimal:
+		// their typed values are read by the `context#RequestParams`.
+		return reflect.String
 	case ParamTypeInt:
 		return reflect.Int
 	case ParamTypeLong:
 		return reflect.Int64
+	case ParamTypeUint64:
+		return reflect.Uint64
 	case ParamTypeBoolean:
 		return reflect.Bool
 	}
+
+	if pt >= paramTypeCustom {
+		return reflect.String
+	}
+
 	return reflect.Invalid // 0
 }
 
@@ -99,6 +126,8 @@ func ValidKind(k reflect.Kind) bool {
 		fallthrough
 	case reflect.Int64:
 		fallthrough
+	case reflect.Uint64:
+		fallthrough
 	case reflect.Bool:
 		return true
 	default:
@@ -120,6 +149,10 @@ var paramTypes = map[string]ParamType{
 	"alphabetical": ParamTypeAlphabetical,
 	"file":         ParamTypeFile,
 	"path":         ParamTypePath,
+	"uint64":       ParamTypeUint64,
+	"uuid":         ParamTypeUUID,
+	"time":         ParamTypeTime,
+	"decimal":      ParamTypeDecimal,
 	// could be named also:
 	// "tail":
 	// "wild"
@@ -133,6 +166,10 @@ var paramTypes = map[string]ParamType{
 // "string"
 // "int"
 // "long"
+// "uint64"
+// "uuid"
+// "time"
+// "decimal"
 // "alphabetical"
 // "file"
 // "path"
@@ -143,6 +180,25 @@ func LookupParamType(ident string) ParamType {
 	return ParamTypeUnExpected
 }
 
+var nextParamTypeCustom = paramTypeCustom
+
+// RegisterParamType registers a custom parameter type by its "ident", i.e "slug",
+// and returns its `ParamType`, if the "ident" is already registered then it returns its type.
+// Its values are strings for the `Kind`.
+//
+// It should be called before the routes' registration, it's not safe for concurrent use.
+// Use the `macro#Map#Register` instead.
+func RegisterParamType(ident string) ParamType {
+	if typ, ok := paramTypes[ident]; ok {
+		return typ
+	}
+
+	typ := nextParamTypeCustom
+	nextParamTypeCustom++
+	paramTypes[ident] = typ
+	return typ
+}
+
 // LookupParamTypeFromStd accepts the string representation of a standard go type.
 // It returns a ParamType, but it may differs for example
 // the alphabetical, file, path and string are all string go types, so
@@ -151,6 +207,7 @@ func LookupParamType(ident string) ParamType {
 // string matches to string
 // int matches to int
 // int64 matches to long
+// uint64 matches to uint64
 // bool matches to boolean
 func LookupParamTypeFromStd(goType string) ParamType {
 	switch goType {
@@ -160,6 +217,8 @@ func LookupParamTypeFromStd(goType string) ParamType {
 		return ParamTypeInt
 	case "int64":
 		return ParamTypeLong
+	case "uint64":
+		return ParamTypeUint64
 	case "bool":
 		return ParamTypeBoolean
 	default:
@@ -179,12 +179,25 @@ func (l *Lexer) skipWhitespace() {
 
 func (l *Lexer) readIdentifier() string {
 	pos := l.pos
-	for isLetter(l.ch) {
+	// parameter names are letters only but
+	// a parameter type may contain digits, i.e "uint64" or "id: uint64".
+	isType := l.prevNonWhitespace(pos) == ':'
+	for isLetter(l.ch) || (isType && l.pos > pos && isDigit(l.ch)) {
 		l.readChar()
 	}
 	return l.input[pos:l.pos]
 }
 
+// prevNonWhitespace returns the last non-whitespace character before the "pos", if any.
+func (l *Lexer) prevNonWhitespace(pos int) byte {
+	for i := pos - 1; i >= 0; i-- {
+		if ch := l.input[i]; ch != ' ' && ch != '\t' && ch != '\n' && ch != '\r' {
+			return ch
+		}
+	}
+	return 0
+}
+
 func isLetter(ch byte) bool {
 	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
 }
@@ -48,6 +48,25 @@ func TestNextToken(t *testing.T) {
 	}
 }
 
+func TestNextTokenType(t *testing.T) {
+	tests := map[string][]string{
+		"{id:uint64}":          {"{", "id", ":", "uint64", "}"},
+		"{id: uint64}":         {"{", "id", ":", "uint64", "}"},
+		"{id : uint64 min(1)}": {"{", "id", ":", "uint64", "min", "(", "1", ")", "}"},
+		// the parameter names are letters only.
+		"{id2:int}": {"{", "id", "2", ":", "int", "}"},
+	}
+
+	for input, expected := range tests {
+		l := New(input)
+		for i, lit := range expected {
+			if tok := l.NextToken(); tok.Literal != lit {
+				t.Fatalf("%s: tests[%d] - literal wrong. expected=%q, got=%q", input, i, lit, tok.Literal)
+			}
+		}
+	}
+}
+
 // EMEINA STO:
 // 30/232 selida apto making a interpeter in Go.
 // den ekana to skipWhitespaces giati skeftomai
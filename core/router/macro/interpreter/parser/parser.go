@@ -27,21 +27,58 @@ func Parse(fullpath string) ([]*ast.ParamStatement, error) {
 			continue
 		}
 
-		p.Reset(s)
-		stmt, err := p.Parse()
-		if err != nil {
-			// exit on first error
-			return nil, err
+		// a segment may contain more than one parameters, i.e {name}.{format}.
+		for _, src := range splitSegmentParams(s) {
+			p.Reset(src)
+			stmt, err := p.Parse()
+			if err != nil {
+				// exit on first error
+				return nil, err
+			}
+			// if we have param type path but it's not the last path part
+			if stmt.Type == ast.ParamTypePath && (i < len(pathParts)-1 || src != s) {
+				return nil, fmt.Errorf("param type 'path' should be lived only inside the last path segment, but was inside: %s", s)
+			}
+
+			statements = append(statements, stmt)
 		}
-		// if we have param type path but it's not the last path part
-		if stmt.Type == ast.ParamTypePath && i < len(pathParts)-1 {
-			return nil, fmt.Errorf("param type 'path' should be lived only inside the last path segment, but was inside: %s", s)
+	}
+
+	return statements, nil
+}
+
+// splitSegmentParams returns the parameters' sources of a path segment,
+// i.e "{name}.{format:string}" returns "{name}" and "{format:string}".
+// The braces inside a parameter function's parenthesis, i.e regexp(^[a-z]{3}$),
+// are part of the parameter.
+func splitSegmentParams(segment string) (params []string) {
+	start, depth := -1, 0
+	for i := 0; i < len(segment); i++ {
+		switch segment[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case lexer.Begin:
+			if depth == 0 && start == -1 {
+				start = i
+			}
+		case lexer.End:
+			if depth == 0 && start != -1 {
+				params = append(params, segment[start:i+1])
+				start = -1
+			}
 		}
+	}
 
-		statements = append(statements, stmt)
+	if len(params) == 0 || start != -1 {
+		// let the parser to report the error.
+		return []string{segment}
 	}
 
-	return statements, nil
+	return
 }
 
 // ParamParser is the parser
@@ -138,7 +138,13 @@ func TestParseParam(t *testing.T) {
 				Type:      ast.ParamTypeBoolean,
 				ErrorCode: 404,
 			}}, // 9
-
+		{true,
+			ast.ParamStatement{
+				Src:       "{id: uint64}",
+				Name:      "id",
+				Type:      ast.ParamTypeUint64,
+				ErrorCode: 404,
+			}}, // 10
 	}
 
 	p := new(ParamParser)
@@ -2,12 +2,16 @@ package macro
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"regexp"
 	"strconv"
+	"time"
 	"unicode"
 
 	"github.com/kataras/iris/core/router/macro/interpreter/ast"
+
+	"github.com/satori/go.uuid"
 )
 
 // EvaluatorFunc is the signature for both param types and param funcs.
@@ -141,9 +145,18 @@ type (
 	// to that macro which maps to a parameter type.
 	Macro struct {
 		Evaluator EvaluatorFunc
+		// Converter, if not nil, validates and converts the parameter's value
+		// to its typed value, which is stored to the request's parameters,
+		// the handlers read it by the `context#RequestParams#GetValue` or its typed getters,
+		// so the value is not parsed twice.
+		Converter ConverterFunc
 		funcs     []ParamFunc
 	}
 
+	// ConverterFunc is the signature of the parameter types which convert their values,
+	// it returns false if the "paramValue" is not valid.
+	ConverterFunc func(paramValue string) (interface{}, bool)
+
 	// ParamEvaluatorBuilder is a func
 	// which accepts a param function's arguments (values)
 	// and returns an EvaluatorFunc, its job
@@ -166,6 +179,18 @@ func newMacro(evaluator EvaluatorFunc) *Macro {
 	return &Macro{Evaluator: evaluator}
 }
 
+// newConverterMacro returns a new macro of a type which converts its values,
+// its evaluator is the "converter" too.
+func newConverterMacro(converter ConverterFunc) *Macro {
+	return &Macro{
+		Evaluator: func(paramValue string) bool {
+			_, ok := converter(paramValue)
+			return ok
+		},
+		Converter: converter,
+	}
+}
+
 // RegisterFunc registers a parameter function
 // to that macro.
 // Accepts the func name ("range")
@@ -209,7 +234,8 @@ func (m *Macro) getFunc(funcName string) ParamEvaluatorBuilder {
 
 // Map contains the default macros mapped to their types.
 // This is the manager which is used by the caller to register custom
-// parameter functions per param-type (String, Int, Long, Boolean, Alphabetical, File, Path).
+// parameter functions per param-type (String, Int, Long, Uint64, UUID, Time, Decimal, Boolean, Alphabetical, File, Path)
+// and custom param-types, see `Register`.
 type Map struct {
 	// string type
 	// anything
@@ -222,6 +248,18 @@ type Map struct {
 	// only positive numbers (+0-9)
 	// it could be uint64 but we keep int64 for simplicity
 	Long *Macro
+	// uint64 type
+	// only positive numbers (+0-9) which fit to an uint64
+	Uint64 *Macro
+	// uuid type, the typed value is an uuid.UUID
+	// i.e "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	UUID *Macro
+	// time type, the typed value is a time.Time
+	// a date "2006-01-02" or an RFC 3339 date-time "2006-01-02T15:04:05Z07:00"
+	Time *Macro
+	// decimal type, the typed value is an exact *big.Rat
+	// i.e "-12.30"
+	Decimal *Macro
 	// boolean as bool type
 	// a string which is "1" or "t" or "T" or "TRUE" or "true" or "True"
 	// or "0" or "f" or "F" or "FALSE" or "false" or "False".
@@ -240,8 +278,15 @@ type Map struct {
 	// path type
 	// anything, should be the last part
 	Path *Macro
+
+	custom map[ast.ParamType]*Macro
 }
 
+var decimalExpr = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+
+// ParamTimeLayouts are the accepted layouts of the "time" parameter type.
+var ParamTimeLayouts = []string{time.RFC3339Nano, "2006-01-02"}
+
 // NewMap returns a new macro Map with default
 // type evaluators.
 //
@@ -252,12 +297,34 @@ func NewMap() *Map {
 		String: newMacro(func(string) bool { return true }),
 		Int:    newMacro(MustNewEvaluatorFromRegexp("^[0-9]+$")),
 		Long:   newMacro(MustNewEvaluatorFromRegexp("^[0-9]+$")),
+		Uint64: newMacro(func(paramValue string) bool {
+			_, err := strconv.ParseUint(paramValue, 10, 64)
+			return err == nil
+		}),
 		Boolean: newMacro(func(paramValue string) bool {
 			// a simple if statement is faster than regex ^(true|false|True|False|t|0|f|FALSE|TRUE)$
 			// in this case.
 			_, err := strconv.ParseBool(paramValue)
 			return err == nil
 		}),
+		UUID: newConverterMacro(func(paramValue string) (interface{}, bool) {
+			id, err := uuid.FromString(paramValue)
+			return id, err == nil
+		}),
+		Time: newConverterMacro(func(paramValue string) (interface{}, bool) {
+			for _, layout := range ParamTimeLayouts {
+				if t, err := time.Parse(layout, paramValue); err == nil {
+					return t, true
+				}
+			}
+			return nil, false
+		}),
+		Decimal: newConverterMacro(func(paramValue string) (interface{}, bool) {
+			if !decimalExpr.MatchString(paramValue) {
+				return nil, false
+			}
+			return new(big.Rat).SetString(paramValue)
+		}),
 		Alphabetical: newMacro(MustNewEvaluatorFromRegexp("^[a-zA-Z ]+$")),
 		File:         newMacro(MustNewEvaluatorFromRegexp("^[a-zA-Z0-9_.-]*$")),
 		// it allows everything, we have String and Path as different
@@ -278,6 +345,14 @@ func (m *Map) Lookup(typ ast.ParamType) *Macro {
 		return m.Int
 	case ast.ParamTypeLong:
 		return m.Long
+	case ast.ParamTypeUint64:
+		return m.Uint64
+	case ast.ParamTypeUUID:
+		return m.UUID
+	case ast.ParamTypeTime:
+		return m.Time
+	case ast.ParamTypeDecimal:
+		return m.Decimal
 	case ast.ParamTypeBoolean:
 		return m.Boolean
 	case ast.ParamTypeAlphabetical:
@@ -287,6 +362,33 @@ func (m *Map) Lookup(typ ast.ParamType) *Macro {
 	case ast.ParamTypePath:
 		return m.Path
 	default:
+		if macro, ok := m.custom[typ]; ok {
+			return macro
+		}
 		return m.String
 	}
 }
+
+// Register registers a custom parameter type by its "ident", i.e "slug",
+// the "converter" validates and converts its values, the handlers read the typed values
+// by the `context#RequestParams#GetValue`. It returns the new macro,
+// parameter functions can be registered to it, like the built-in ones.
+//
+// Example:
+// app.Macros().Register("version", func(paramValue string) (interface{}, bool) {
+// 	v, err := version.NewVersion(paramValue)
+// 	return v, err == nil
+// })
+// app.Get("/api/{v:version}", func(ctx iris.Context) {
+// 	v, _ := ctx.Params().GetValue("v")
+// 	...
+// })
+func (m *Map) Register(ident string, converter ConverterFunc) *Macro {
+	if m.custom == nil {
+		m.custom = make(map[ast.ParamType]*Macro)
+	}
+
+	macro := newConverterMacro(converter)
+	m.custom[ast.RegisterParamType(ident)] = macro
+	return macro
+}
@@ -27,6 +27,8 @@ type TemplateParam struct {
 	Name          string
 	ErrCode       int
 	TypeEvaluator EvaluatorFunc
+	// TypeConverter is the converter of the param types which store typed values, if any.
+	TypeConverter ConverterFunc
 	Funcs         []EvaluatorFunc
 }
 
@@ -52,6 +54,7 @@ func Parse(src string, macros *Map) (*Template, error) {
 			Name:          p.Name,
 			ErrCode:       p.ErrorCode,
 			TypeEvaluator: typEval,
+			TypeConverter: funcMap.Converter,
 		}
 		for _, paramfn := range p.Funcs {
 			tmplFn := funcMap.getFunc(paramfn.Name)
@@ -52,10 +52,30 @@ type Party interface {
 	// If called from a child party then the subdomain will be prepended to the path instead of appended.
 	// So if app.Subdomain("admin").Subdomain("panel") then the result is: "panel.admin.".
 	Subdomain(subdomain string, middleware ...context.Handler) Party
+	// Host returns a new party which is responsible to register routes to
+	// a specific, full, "hostname", i.e "api.example.com" or "admin.example.com",
+	// it's not relative to the application's root domain like the `Subdomain`,
+	// so many different domains can be served by the same listener.
+	//
+	// A host party is isolated from the rest of the application,
+	// it does not inherit the middleware and the done handlers of its parent
+	// (the `UseGlobal` and `DoneGlobal` ones are still executed)
+	// and it has its own http error code handlers, registered through its `OnErrorCode`.
+	//
+	// Host can be called only from the root Party.
+	Host(hostname string, middleware ...context.Handler) Party
 
 	// Use appends Handler(s) to the current Party's routes and child routes.
 	// If the current Party is the root, then it registers the middleware to all child Parties' routes too.
 	Use(middleware ...context.Handler)
+	// ResetMiddleware removes all the middleware of this Party,
+	// including the ones inherited by its parent Party, so the next routes
+	// of this Party and its children will not execute them.
+	//
+	// The `UseGlobal` handlers are not affected, they are still executed.
+	//
+	// Returns itself.
+	ResetMiddleware() Party
 
 	// Done appends to the very end, Handler(s) to the current Party's routes and child routes.
 	// The difference from .Use is that this/or these Handler(s) are being always running last.
@@ -74,6 +94,21 @@ type Party interface {
 	// Call of `AllowMethod` will override any previous allow methods.
 	AllowMethods(methods ...string) Party
 
+	// Compress sets the default compression of this Party's (and its children) routes,
+	// the routes that will be registered after this call.
+	// A route can override it by its `Route#Compress`.
+	//
+	// Returns this Party.
+	Compress(enable bool) Party
+
+	// RegisterHealthChecks registers a GET route to the "relativePath"
+	// which runs the "checks" on each request and responds with their `HealthReport` as JSON,
+	// the status code is 200 when all checks passed, otherwise 503.
+	// A check without its `Check` function is reported as an error of the application's build.
+	//
+	// Returns the new route.
+	RegisterHealthChecks(relativePath string, checks ...HealthCheck) *Route
+
 	// SetExecutionRules alters the execution flow of the route handlers outside of the handlers themselves.
 	//
 	// For example, if for some reason the desired result is the (done or all) handlers to be executed no matter what
@@ -119,6 +154,16 @@ type Party interface {
 	// This method is used behind the scenes at the `Controller` function
 	// in order to handle more than one paths for the same controller instance.
 	HandleMany(method string, relativePath string, handlers ...context.Handler) []*Route
+	// HandleIf works like `Handle` but the route is served only when the "flag" returns true,
+	// otherwise the client receives a 404 Not Found, as the route was never registered.
+	// The "flag" is evaluated on each request, so a feature can be toggled on and off
+	// at serve-time without a redeploy.
+	//
+	// Usage:
+	// 	app.HandleIf(func(ctx iris.Context) bool { return betaEnabled.Load().(bool) }, "GET", "/beta", betaHandler)
+	//
+	// Returns the read-only route information.
+	HandleIf(flag func(context.Context) bool, method string, registeredPath string, handlers ...context.Handler) *Route
 
 	// None registers an "offline" route
 	// see context.ExecRoute(routeName) and
@@ -253,6 +298,18 @@ type Party interface {
 	// Returns the GET *Route.
 	StaticWeb(requestPath string, systemPath string) *Route
 
+	// OnErrorCode registers an error http status code
+	// based on the "statusCode" < 200 || >= 400 (came from `context.StatusCodeNotSuccessful`).
+	// The handler is being wrapepd by a generic
+	// handler which will try to reset
+	// the body if recorder was enabled
+	// and/or disable the gzip if gzip response recorder
+	// was active.
+	//
+	// The error code handlers are application-wide
+	// except those registered through a `Host` party and its children.
+	OnErrorCode(statusCode int, handlers ...context.Handler)
+
 	// Layout overrides the parent template layout with a more specific layout for this Party.
 	// It returns the current Party.
 	//
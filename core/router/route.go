@@ -1,10 +1,16 @@
 package router
 
 import (
+	stdContext "context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
 	"github.com/kataras/iris/core/router/macro"
 )
 
@@ -24,12 +30,38 @@ type Route struct {
 	// Cannot be empty.
 	Handlers        context.Handlers
 	MainHandlerName string
+	// mainHandlersLen is the number of the handlers that were passed on the route's registration,
+	// starting by the `MainHandlerName` one.
+	mainHandlersLen int
 	// temp storage, they're appended to the Handlers on build.
 	// Execution happens after Begin and main Handler(s), can be empty.
 	doneHandlers context.Handlers
 	// FormattedPath all dynamic named parameters (if any) replaced with %v,
 	// used by Application to validate param values of a Route based on its name.
 	FormattedPath string
+	// compress is nil when the route does not care about compression,
+	// otherwise it's the value of the `Compress`.
+	compress *bool
+	// maxBodySize is the value of the `MaxBodySize`, zero means no limit.
+	maxBodySize int64
+	// decompress is nil when the request bodies are not decompressed,
+	// otherwise it's the value of the `Decompress`.
+	decompress *context.DecompressOptions
+	// timeout is the value of the `Timeout`, zero means no timeout.
+	timeout time.Duration
+	// bufferResponse is nil when the response is not buffered,
+	// otherwise it's the value of the `BufferResponse`.
+	bufferResponse *int64
+	// ipFilter is created by the `AllowIP`, `DenyIP` and `OnIPDenied`.
+	ipFilter *ipFilter
+	// flag is the predicate of the `Party#HandleIf`.
+	flag func(context.Context) bool
+	// shadow is the value of the `Shadow`.
+	shadow context.Handler
+	// canary is created by the `Canary`.
+	canary *canary
+	// err is reported on build, i.e an invalid `AllowIP` address.
+	err error
 }
 
 // NewRoute returns a new route based on its method,
@@ -93,6 +125,158 @@ func (r *Route) done(handlers context.Handlers) {
 	r.doneHandlers = append(r.doneHandlers, handlers...)
 }
 
+// Compress enables or disables the compression of this route's responses,
+// if enabled the `context#Compress` runs before any other handler of the route,
+// the encoding is negotiated by the "Accept-Encoding" header, see `context#RegisterCompressor`,
+// and if disabled the `context#NoGzip` does, so even a global `iris.Gzip` has no effect.
+// Useful to compress large JSON responses but not the already-compressed binaries
+// or the server-sent events.
+//
+// Defaults to the Party's `Compress`, if any.
+//
+// Returns itself.
+func (r *Route) Compress(enable bool) *Route {
+	r.compress = &enable
+	return r
+}
+
+// MaxBodySize sets a limit, in bytes, to the request body of this route.
+// Requests with a greater "Content-Length" are rejected with a 413 status code
+// before any of the route's handlers run,
+// streamed bodies fail on read after "n" bytes with the same status code.
+//
+// Use it to let an upload endpoint to accept large files
+// while the rest of the API is capped to a few kilobytes.
+//
+// Returns itself.
+func (r *Route) MaxBodySize(n int64) *Route {
+	r.maxBodySize = n
+	return r
+}
+
+// Decompress decompresses the gzip and deflate request bodies of this route
+// with the `context#DecompressBody` and the optional "opts" limits,
+// defaults to the `context#DefaultDecompressOptions`.
+// The `MaxBodySize`, if any, limits the compressed body
+// and the "opts" limit its decompressed size and its expansion ratio.
+//
+// Use the `Party#Use(iris.DecompressBody(...))` for a group of routes instead.
+//
+// Returns itself.
+func (r *Route) Decompress(opts ...context.DecompressOptions) *Route {
+	options := context.DefaultDecompressOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	r.decompress = &options
+	return r
+}
+
+// Timeout sets a timeout to the standard context of this route's requests,
+// the `Context#Done`, `ctx.Request().Context().Done()` and the contexts derived from them
+// are closed when it fires, so the database queries and the outgoing requests
+// of the handlers are canceled too.
+// Note that the handlers are not interrupted, they should check the `Context#Err`.
+//
+// Returns itself.
+func (r *Route) Timeout(d time.Duration) *Route {
+	r.timeout = d
+	return r
+}
+
+// BufferResponse records the whole response of this route, up to "maxSize" bytes, zero means no limit,
+// so its middleware and done handlers can change the status code and the headers
+// after the main handler wrote the body. It costs memory and time to the first byte,
+// a response which exceeds the "maxSize" is sent directly from that point.
+//
+// See `Context#BufferResponse` for more.
+//
+// Returns itself.
+func (r *Route) BufferResponse(maxSize int64) *Route {
+	r.bufferResponse = &maxSize
+	return r
+}
+
+// AllowIP allows only the clients with an IP address inside the "cidrs" to access this route,
+// i.e "10.0.0.0/8" or a single address like "192.168.1.2".
+// The rest of the clients are denied with a 403 Forbidden, see `OnIPDenied` to change that.
+//
+// The client's IP is the `Context#ClientIP`, the connection's IP address,
+// set the `Configuration#TrustedProxies` to make it work behind a proxy.
+// Note that the `Configuration#RemoteAddrHeaders` are not used, they can be spoofed by any client.
+//
+// Returns itself.
+func (r *Route) AllowIP(cidrs ...string) *Route {
+	r.filterIP().allow = r.parseIPNets(cidrs)
+	return r
+}
+
+// DenyIP denies the clients with an IP address inside the "cidrs" to access this route,
+// i.e "10.0.0.0/8" or a single address like "192.168.1.2".
+// The denied clients receive a 403 Forbidden, see `OnIPDenied` to change that.
+// It takes precedence over the `AllowIP`.
+//
+// Returns itself.
+func (r *Route) DenyIP(cidrs ...string) *Route {
+	r.filterIP().deny = r.parseIPNets(cidrs)
+	return r
+}
+
+// OnIPDenied registers a handler which sends the response to the clients
+// that are denied by the `AllowIP` or `DenyIP`, instead of the default 403 Forbidden.
+//
+// Returns itself.
+func (r *Route) OnIPDenied(handler context.Handler) *Route {
+	r.filterIP().onDenied = handler
+	return r
+}
+
+// Canary splits the traffic of this route between its handlers and the "handlers",
+// the canary version of the route's main handler, by the "opts.Percent" of the requests.
+// The route's middleware and done handlers are executed for both versions.
+//
+// Set the "opts.Cookie" or "opts.Header" to serve a client by the same version on its next requests,
+// useful to release a new version of an endpoint to a small part of the clients first.
+//
+// Returns itself.
+func (r *Route) Canary(opts CanaryOptions, handlers ...context.Handler) *Route {
+	r.canary = newCanary(opts, handlers)
+	return r
+}
+
+// Shadow replays each request of this route, including its body,
+// to the "handler" asynchronously, its response is discarded.
+// The route's handlers are executed as usual.
+//
+// Use it to test a rewritten endpoint against the production traffic.
+//
+// The body is buffered up to the `MaxBodySize` of the route or the `Configuration#PostMaxMemory`,
+// the larger requests are not replayed, neither the requests which exceed the `MaxShadowRequests`.
+//
+// Returns itself.
+func (r *Route) Shadow(handler context.Handler) *Route {
+	r.shadow = handler
+	return r
+}
+
+func (r *Route) filterIP() *ipFilter {
+	if r.ipFilter == nil {
+		r.ipFilter = new(ipFilter)
+	}
+
+	return r.ipFilter
+}
+
+func (r *Route) parseIPNets(cidrs []string) []*net.IPNet {
+	ipNets, err := parseIPNets(cidrs)
+	if err != nil {
+		r.err = err
+	}
+
+	return ipNets
+}
+
 // BuildHandlers is executed automatically by the router handler
 // at the `Application#Build` state. Do not call it manually, unless
 // you were defined your own request mux handler.
@@ -102,10 +286,61 @@ func (r *Route) BuildHandlers() {
 		r.beginHandlers = r.beginHandlers[0:0]
 	}
 
+	if r.compress != nil {
+		compressHandler := context.NoGzip
+		if *r.compress {
+			compressHandler = context.Compress
+		}
+		r.Handlers = append(context.Handlers{compressHandler}, r.Handlers...)
+		r.compress = nil // do not prepend it again on rebuild.
+	}
+
+	// the shadow handler reads the body after the `MaxBodySize` limit is applied.
+	if r.shadow != nil {
+		r.Handlers = append(context.Handlers{shadowHandler(r.shadow, r.maxBodySize)}, r.Handlers...)
+		r.shadow = nil // do not prepend it again on rebuild.
+	}
+
+	if r.decompress != nil {
+		r.Handlers = append(context.Handlers{context.DecompressBody(*r.decompress)}, r.Handlers...)
+		r.decompress = nil // do not prepend it again on rebuild.
+	}
+
+	if r.maxBodySize > 0 {
+		r.Handlers = append(context.Handlers{maxBodySizeHandler(r.maxBodySize)}, r.Handlers...)
+		r.maxBodySize = 0 // do not prepend it again on rebuild.
+	}
+
+	if r.timeout > 0 {
+		r.Handlers = append(context.Handlers{timeoutHandler(r.timeout)}, r.Handlers...)
+		r.timeout = 0 // do not prepend it again on rebuild.
+	}
+
+	if r.bufferResponse != nil {
+		r.Handlers = append(context.Handlers{bufferResponseHandler(*r.bufferResponse)}, r.Handlers...)
+		r.bufferResponse = nil // do not prepend it again on rebuild.
+	}
+
+	if r.ipFilter != nil {
+		r.Handlers = append(context.Handlers{r.ipFilter.handler}, r.Handlers...)
+		r.ipFilter = nil // do not prepend it again on rebuild.
+	}
+
+	if r.flag != nil {
+		r.Handlers = append(context.Handlers{flagHandler(r.flag)}, r.Handlers...)
+		r.flag = nil // do not prepend it again on rebuild.
+	}
+
 	if len(r.doneHandlers) > 0 {
 		r.Handlers = append(r.Handlers, r.doneHandlers...)
 		r.doneHandlers = r.doneHandlers[0:0]
 	} // note: no mutex needed, this should be called in-sync when server is not running of course.
+
+	if r.canary != nil && len(r.canary.handlers) > 0 {
+		canaryHandlers := r.canary.build(r.Handlers, r.MainHandlerName, r.mainHandlersLen)
+		r.Handlers = context.Handlers{r.canary.handler(r.Handlers, canaryHandlers)}
+		r.canary = nil // do not split it again on rebuild.
+	}
 }
 
 // String returns the form of METHOD, SUBDOMAIN, TMPL PATH.
@@ -249,3 +484,85 @@ func (rd routeReadOnlyWrapper) Path() string {
 func (rd routeReadOnlyWrapper) Trace() string {
 	return rd.Route.Trace()
 }
+
+var errBodyTooLarge = errors.New("request body too large, limit is %d bytes")
+
+func maxBodySizeHandler(n int64) context.Handler {
+	return func(ctx context.Context) {
+		if ctx.GetContentLength() > n {
+			ctx.StatusCode(http.StatusRequestEntityTooLarge)
+			ctx.StopExecution()
+			return
+		}
+
+		if r := ctx.Request(); r.Body != nil {
+			r.Body = &maxBodyReader{ReadCloser: r.Body, ctx: ctx, remaining: n, limit: n}
+		}
+
+		ctx.Next()
+	}
+}
+
+func timeoutHandler(d time.Duration) context.Handler {
+	return func(ctx context.Context) {
+		r := ctx.Request()
+		c, cancel := stdContext.WithTimeout(r.Context(), d)
+		defer cancel()
+
+		ctx.ResetRequest(r.WithContext(c))
+		ctx.Next()
+	}
+}
+
+func bufferResponseHandler(maxSize int64) context.Handler {
+	return func(ctx context.Context) {
+		ctx.BufferResponse(maxSize)
+		ctx.Next()
+	}
+}
+
+func flagHandler(flag func(context.Context) bool) context.Handler {
+	return func(ctx context.Context) {
+		if !flag(ctx) {
+			ctx.NotFound()
+			ctx.StopExecution()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// maxBodyReader is like the `http#MaxBytesReader`
+// but it sets the 413 status code to the context when the limit exceeded.
+type maxBodyReader struct {
+	io.ReadCloser
+	ctx       context.Context
+	remaining int64
+	limit     int64
+	err       error
+}
+
+func (m *maxBodyReader) Read(p []byte) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	// read one more byte to know if the limit is exceeded.
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+
+	n, err := m.ReadCloser.Read(p)
+	if int64(n) <= m.remaining {
+		m.remaining -= int64(n)
+		m.err = err
+		return n, err
+	}
+
+	n = int(m.remaining)
+	m.remaining = 0
+	m.err = errBodyTooLarge.Format(m.limit)
+	m.ctx.StatusCode(http.StatusRequestEntityTooLarge)
+	return n, m.err
+}
@@ -1,10 +1,23 @@
 package router_test
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/iris-contrib/httpexpect"
 	"github.com/kataras/iris"
 	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/router"
 	"github.com/kataras/iris/httptest"
 )
 
@@ -39,3 +52,661 @@ func TestRouteExists(t *testing.T) {
 	// run the tests
 	httptest.New(t, app, httptest.Debug(false)).Request("GET", "/route-test").Expect().Status(iris.StatusOK)
 }
+
+func TestRouterHost(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx context.Context) {
+		ctx.Header("X-Root", "1")
+		ctx.Next()
+	})
+	app.Get("/", func(ctx context.Context) {
+		ctx.WriteString("root")
+	})
+
+	api := app.Host("api.example.com")
+	api.OnErrorCode(iris.StatusNotFound, func(ctx context.Context) {
+		ctx.WriteString("api not found")
+	})
+	api.Get("/", func(ctx context.Context) {
+		ctx.WriteString("api")
+	})
+
+	admin := app.Host("admin.example.com:8080")
+	admin.Get("/", func(ctx context.Context) {
+		ctx.WriteString("admin")
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("root")
+	e.GET("/").WithHeader("Host", "api.example.com").Expect().Status(iris.StatusOK).
+		Header("X-Root").Empty()
+	e.GET("/").WithHeader("Host", "api.example.com:443").Expect().Status(iris.StatusOK).Body().Equal("api")
+	e.GET("/").WithHeader("Host", "admin.example.com").Expect().Status(iris.StatusOK).Body().Equal("admin")
+	e.GET("/notfound").WithHeader("Host", "api.example.com").Expect().Status(iris.StatusNotFound).
+		Body().Equal("api not found")
+	e.GET("/notfound").Expect().Status(iris.StatusNotFound).Body().Equal("Not Found")
+	// the hosts are matched as a whole, not by their prefix.
+	e.GET("/").WithHeader("Host", "api.example.com.evil.com").Expect().Status(iris.StatusOK).Body().Equal("root")
+	e.GET("/").WithHeader("Host", "api.example.community").Expect().Status(iris.StatusOK).Body().Equal("root")
+}
+
+func TestRouteNamesUnique(t *testing.T) {
+	app := iris.New()
+	emptyHandler := func(context.Context) {}
+
+	app.Get("/users", emptyHandler).Name = "users"
+	app.Post("/users", emptyHandler).Name = "users"
+	app.Get("/user/{id:int}", emptyHandler).Name = "user"
+
+	if err := app.Build(); err == nil {
+		t.Fatalf("expected an error for the duplicated route name")
+	}
+
+	if r := app.GetRoute("user"); r == nil || r.String() != "GET /user/{id:int}" {
+		t.Fatalf("expected to find the route by its name but got: %v", r)
+	}
+}
+
+func TestRouteCompress(t *testing.T) {
+	app := iris.New()
+	app.Use(iris.Gzip)
+
+	writeHandler := func(ctx context.Context) {
+		ctx.WriteString("data")
+	}
+
+	app.Get("/default", writeHandler)
+	app.Get("/off", writeHandler).Compress(false)
+
+	p := app.Party("/p").Compress(false)
+	p.Get("/off", writeHandler)
+	p.Get("/on", writeHandler).Compress(true)
+
+	e := httptest.New(t, app)
+	for path, expected := range map[string]string{
+		"/default": "gzip",
+		"/off":     "",
+		"/p/off":   "",
+		"/p/on":    "gzip",
+	} {
+		e.GET(path).WithHeader("Accept-Encoding", "gzip").Expect().Status(iris.StatusOK).
+			Header("Content-Encoding").Equal(expected)
+	}
+
+	// the compressed routes negotiate the encoding, "deflate" is the zlib format.
+	resp := e.GET("/p/on").WithHeader("Accept-Encoding", "gzip;q=0.5, deflate").Expect().Status(iris.StatusOK)
+	resp.Header("Content-Encoding").Equal("deflate")
+
+	r, err := zlib.NewReader(strings.NewReader(resp.Body().Raw()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := ioutil.ReadAll(r); err != nil || string(b) != "data" {
+		t.Fatalf("expected the deflate body to be 'data' but got: '%s', %v", b, err)
+	}
+}
+
+func TestRouteMaxBodySize(t *testing.T) {
+	app := iris.New()
+	app.Post("/", func(ctx context.Context) {
+		b, err := ioutil.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return
+		}
+		ctx.Write(b)
+	}).MaxBodySize(5)
+
+	e := httptest.New(t, app)
+	e.POST("/").WithText("12345").Expect().Status(iris.StatusOK).Body().Equal("12345")
+	e.POST("/").WithText("123456").Expect().Status(iris.StatusRequestEntityTooLarge)
+}
+
+func TestRouteDecompress(t *testing.T) {
+	handler := func(ctx context.Context) {
+		b, err := ioutil.ReadAll(ctx.Request().Body)
+		if err != nil {
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.Write(b)
+	}
+
+	app := iris.New()
+	app.Post("/", handler).Decompress(context.DecompressOptions{MaxSize: 5})
+	app.Post("/limited", handler).Decompress().MaxBodySize(10)
+	app.Post("/raw", handler)
+
+	deflate := func(s string) []byte {
+		var b bytes.Buffer
+		w, _ := flate.NewWriter(&b, flate.BestCompression)
+		w.Write([]byte(s))
+		w.Close()
+		return b.Bytes()
+	}
+
+	e := httptest.New(t, app)
+	post := func(path string, body []byte) *httpexpect.Response {
+		return e.POST(path).WithHeader("Content-Encoding", "deflate").WithBytes(body).Expect()
+	}
+
+	post("/", deflate("12345")).Status(iris.StatusOK).Body().Equal("12345")
+	post("/", deflate("123456")).Status(iris.StatusRequestEntityTooLarge).
+		Body().Equal("request body: decompression limit exceeded: size")
+	post("/raw", deflate("12345")).Status(iris.StatusOK).Body().Equal(string(deflate("12345")))
+	// the MaxBodySize limits the compressed body.
+	post("/limited", deflate(strings.Repeat("1", 100))).Status(iris.StatusOK).Body().Equal(strings.Repeat("1", 100))
+	post("/limited", deflate("a random text which does not compress well")).Status(iris.StatusRequestEntityTooLarge)
+}
+
+func TestRouteTimeout(t *testing.T) {
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		if _, ok := ctx.Deadline(); !ok {
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			ctx.WriteString(ctx.Err().Error())
+		case <-time.After(time.Second):
+		}
+	}).Timeout(10 * time.Millisecond)
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("context deadline exceeded")
+}
+
+func TestETag(t *testing.T) {
+	app := iris.New()
+	app.Get("/", iris.ETag, func(ctx context.Context) { ctx.WriteString("body") })
+	app.Get("/write", func(ctx context.Context) { ctx.WriteWithEtag([]byte("body")) })
+	// the body which is transformed by the hooks of the next handlers is hashed.
+	app.Get("/hooks", iris.ETag, func(ctx context.Context) {
+		ctx.Recorder().OnFlush(bytes.ToUpper)
+		ctx.WriteString("body")
+	})
+
+	e := httptest.New(t, app)
+	for path, body := range map[string]string{"/": "body", "/write": "body", "/hooks": "BODY"} {
+		r := e.GET(path).Expect().Status(iris.StatusOK)
+		r.Body().Equal(body)
+		etag := r.Header(context.ETagHeaderKey).NotEmpty().Raw()
+		if expected := context.ComputeETag([]byte(body)); etag != expected {
+			t.Fatalf("%s: expected the ETag of the final body %s but got %s", path, expected, etag)
+		}
+		e.GET(path).WithHeader("If-None-Match", etag).Expect().Status(iris.StatusNotModified).Body().Empty()
+		e.GET(path).WithHeader("If-None-Match", `"other"`).Expect().Status(iris.StatusOK).Body().Equal(body)
+	}
+}
+
+func TestRecorderOnFlush(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx context.Context) {
+		ctx.Recorder().OnFlush(bytes.ToUpper)
+		ctx.Recorder().OnFlush(func(body []byte) []byte { return append(body, '!') })
+		ctx.Next()
+	})
+	app.Get("/", func(ctx context.Context) { ctx.WriteString("body") })
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("BODY!")
+}
+
+func TestRouteBufferResponse(t *testing.T) {
+	app := iris.New()
+	app.Done(func(ctx context.Context) {
+		ctx.StatusCode(iris.StatusAccepted)
+		ctx.Header("X-Late", "yes")
+	})
+	handler := func(ctx context.Context) {
+		ctx.WriteString(ctx.URLParamDefault("body", "body"))
+		ctx.Next()
+	}
+	app.Get("/", handler).BufferResponse(5)
+	app.Get("/direct", handler)
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusAccepted).Header("X-Late").Equal("yes")
+	e.GET("/").WithQuery("body", "larger than five").Expect().Status(iris.StatusOK).
+		Body().Equal("larger than five")
+	e.GET("/direct").Expect().Status(iris.StatusOK).Header("X-Late").Empty()
+}
+
+func TestRegisterHealthChecks(t *testing.T) {
+	app := iris.New()
+	app.RegisterHealthChecks("/healthz", router.HealthCheck{Name: "ok", Check: func() error { return nil }})
+	app.RegisterHealthChecks("/healthz/all",
+		router.HealthCheck{Name: "ok", Check: func() error { return nil }},
+		router.HealthCheck{Name: "db", Check: func() error { return errors.New("unreachable") }})
+
+	e := httptest.New(t, app)
+	e.GET("/healthz").Expect().Status(iris.StatusOK).
+		JSON().Object().ValueEqual("status", router.HealthStatusUp)
+	e.GET("/healthz/all").Expect().Status(iris.StatusServiceUnavailable).
+		JSON().Object().ValueEqual("status", router.HealthStatusDown)
+
+	// a check without its function is rejected when it's registered.
+	app = iris.New()
+	if r := app.RegisterHealthChecks("/healthz", router.HealthCheck{Name: "cache"}); r != nil {
+		t.Fatalf("expected a health check without its function to not be registered but got: %s", r)
+	}
+	if err := app.Build(); err == nil || !strings.Contains(err.Error(), "health check 'cache' has no Check function -> GET /healthz") {
+		t.Fatalf("expected the missing health check function to be reported but got: %v", err)
+	}
+
+	// and it's "down" when it's executed by the handler.
+	report := router.RunHealthChecks(router.HealthCheck{Name: "cache"})
+	if report.Status != router.HealthStatusDown || report.Checks[0].Error != "health check 'cache' has no Check function" {
+		t.Fatalf("expected a health check without its function to be down but got: %#v", report)
+	}
+}
+
+func TestPartyResetMiddleware(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx context.Context) {
+		ctx.StatusCode(iris.StatusUnauthorized)
+	})
+	writePath := func(ctx context.Context) { ctx.WriteString(ctx.Path()) }
+
+	app.Get("/", writePath)
+	webhooks := app.Party("/webhooks").ResetMiddleware()
+	webhooks.Post("/github", writePath)
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusUnauthorized)
+	e.POST("/webhooks/github").Expect().Status(iris.StatusOK).Body().Equal("/webhooks/github")
+}
+
+func TestPathNormalization(t *testing.T) {
+	app := iris.New()
+	app.Get("/a/b", func(ctx context.Context) { ctx.WriteString(ctx.Path()) })
+
+	opts := context.PathNormalization{CollapseSlashes: true, ResolveDotSegments: true}
+	app.Configure(iris.WithPathNormalization(opts))
+
+	e := httptest.New(t, app)
+	e.GET("/a//b").Expect().Status(iris.StatusOK).Body().Equal("/a/b")
+	e.GET("/a/c/../b").Expect().Status(iris.StatusOK).Body().Equal("/a/b")
+	e.GET("/a/./b").Expect().Status(iris.StatusOK).Body().Equal("/a/b")
+
+	opts.Redirect = true
+	app.Configure(iris.WithPathNormalization(opts))
+	// the client follows the redirect.
+	if expected, got := "/a/b", e.GET("/a//b").Expect().Status(iris.StatusOK).Raw().Request.URL.Path; expected != got {
+		t.Fatalf("expected to be redirected to %s but got %s", expected, got)
+	}
+}
+
+func TestSPAFileSystem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("index"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("app"), 0644)
+
+	app := iris.New()
+	app.SPAFileSystem(http.Dir(dir), router.SPAOptions{APIPrefixes: []string{"/api"}, CacheControl: "max-age=60"})
+	app.Get("/api/users", func(ctx context.Context) { ctx.WriteString("users") })
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("index")
+	e.GET("/users/42").Expect().Status(iris.StatusOK).Body().Equal("index")
+	e.GET("/app.js").Expect().Status(iris.StatusOK).Header("Cache-Control").Equal("max-age=60")
+	e.GET("/missing.js").Expect().Status(iris.StatusNotFound)
+	e.GET("/api/users").Expect().Status(iris.StatusOK).Body().Equal("users")
+	e.GET("/api/unknown").Expect().Status(iris.StatusNotFound)
+}
+
+func TestSPA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("index"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("app"), 0644)
+
+	// a file system, the api routes take precedence even if they are registered after it.
+	app := iris.New()
+	app.SPA(http.Dir(dir), router.SPAOptions{APIPrefixes: []string{"/api"}, CacheControl: "max-age=60"})
+	app.Get("/api/users", func(ctx context.Context) { ctx.WriteString("users") })
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("index")
+	e.GET("/index.html").Expect().Status(iris.StatusOK).Header("Cache-Control").Equal("no-cache")
+	e.GET("/users/42").Expect().Status(iris.StatusOK).Body().Equal("index")
+	e.GET("/app.js").Expect().Status(iris.StatusOK).Header("Cache-Control").Equal("max-age=60")
+	e.HEAD("/app.js").Expect().Status(iris.StatusOK)
+	e.GET("/missing.js").Expect().Status(iris.StatusNotFound)
+	e.GET("/api/users").Expect().Status(iris.StatusOK).Body().Equal("users")
+	e.GET("/api/unknown").Expect().Status(iris.StatusNotFound)
+
+	// without options, the index is the fallback of all the non-file paths.
+	app = iris.New()
+	app.SPA(http.Dir(dir))
+	e = httptest.New(t, app)
+	e.GET("/api/unknown").Expect().Status(iris.StatusOK).Body().Equal("index")
+
+	// an asset handler, as before.
+	app = iris.New()
+	app.SPA(func(ctx context.Context) { ctx.WriteString("asset " + ctx.Path()) })
+	e = httptest.New(t, app)
+	e.GET("/app.js").Expect().Status(iris.StatusOK).Body().Equal("asset /app.js")
+
+	app = iris.New()
+	app.SPA(dir)
+	if err := app.Build(); err == nil || !strings.Contains(err.Error(), "SPA: expected an asset handler or an http.FileSystem but got: string") {
+		t.Fatalf("expected the SPA of an unexpected assets type to be reported but got: %v", err)
+	}
+}
+
+func TestRouteIPFilter(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithTrustedProxies("127.0.0.1"))
+	// the test requests have no connection, the peer's address is sent by a header.
+	app.WrapRouter(func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+		r.RemoteAddr = r.Header.Get("X-Test-Peer") + ":1234"
+		router(w, r)
+	})
+	ok := func(ctx context.Context) { ctx.WriteString("ok") }
+
+	app.Get("/admin", ok).AllowIP("10.0.0.0/8").DenyIP("10.0.0.5")
+	app.Get("/webhook", ok).AllowIP("192.168.1.2").OnIPDenied(func(ctx context.Context) {
+		ctx.StatusCode(iris.StatusNotFound)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/admin").WithHeader("X-Test-Peer", "10.1.2.3").Expect().Status(iris.StatusOK).Body().Equal("ok")
+	e.GET("/admin").WithHeader("X-Test-Peer", "10.0.0.5").Expect().Status(iris.StatusForbidden)
+	e.GET("/admin").WithHeader("X-Test-Peer", "8.8.8.8").Expect().Status(iris.StatusForbidden)
+	// forwarded by a trusted proxy.
+	e.GET("/admin").WithHeader("X-Test-Peer", "127.0.0.1").WithHeader("X-Forwarded-For", "10.1.2.3").
+		Expect().Status(iris.StatusOK)
+	e.GET("/webhook").WithHeader("X-Test-Peer", "192.168.1.2").Expect().Status(iris.StatusOK)
+	e.GET("/webhook").WithHeader("X-Test-Peer", "192.168.1.3").Expect().Status(iris.StatusNotFound)
+
+	// the forwarded headers of the untrusted clients are ignored.
+	app = iris.New()
+	app.Configure(iris.WithRemoteAddrHeader("X-Real-Ip"), iris.WithRemoteAddrHeader("X-Forwarded-For"))
+	app.WrapRouter(func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+		r.RemoteAddr = "8.8.8.8:1234"
+		router(w, r)
+	})
+	app.Get("/admin", ok).AllowIP("10.0.0.0/8")
+
+	e = httptest.New(t, app)
+	e.GET("/admin").WithHeader("X-Real-Ip", "10.1.2.3").Expect().Status(iris.StatusForbidden)
+	e.GET("/admin").WithHeader("X-Forwarded-For", "10.1.2.3").Expect().Status(iris.StatusForbidden)
+
+	app = iris.New()
+	app.Get("/", ok).AllowIP("not-an-ip")
+	if err := app.Build(); err == nil {
+		t.Fatalf("expected an error on invalid ip address")
+	}
+}
+
+func TestRouteCanary(t *testing.T) {
+	app := iris.New()
+	mw := func(ctx context.Context) {
+		ctx.Header("X-Middleware", "1")
+		ctx.Next()
+	}
+	stable := func(ctx context.Context) { ctx.WriteString("stable") }
+	canary := func(ctx context.Context) { ctx.WriteString("canary") }
+
+	p := app.Party("/", mw)
+	p.Get("/all", stable).Canary(iris.CanaryOptions{Percent: 100}, canary)
+	p.Get("/none", stable).Canary(iris.CanaryOptions{Percent: 0}, canary)
+	app.Get("/sticky", stable).Canary(iris.CanaryOptions{Percent: 50, Cookie: "version"}, canary)
+
+	e := httptest.New(t, app)
+	e.GET("/all").Expect().Status(iris.StatusOK).Header("X-Middleware").Equal("1")
+	e.GET("/all").Expect().Body().Equal("canary")
+	e.GET("/none").Expect().Status(iris.StatusOK).Header("X-Middleware").Equal("1")
+	e.GET("/none").Expect().Body().Equal("stable")
+	e.GET("/sticky").WithCookie("version", "canary").Expect().Body().Equal("canary")
+	e.GET("/sticky").WithCookie("version", "stable").Expect().Body().Equal("stable")
+}
+
+func TestRouteShadow(t *testing.T) {
+	app := iris.New()
+	shadowed := make(chan string, 1)
+
+	app.Post("/users/{id}", func(ctx context.Context) {
+		b, _ := ioutil.ReadAll(ctx.Request().Body)
+		ctx.Writef("%s:%s", ctx.Params().Get("id"), b)
+	}).Shadow(func(ctx context.Context) {
+		b, _ := ioutil.ReadAll(ctx.Request().Body)
+		ctx.Writef("shadow")
+		shadowed <- ctx.Params().Get("id") + ":" + string(b)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/users/42").WithBytes([]byte("body")).Expect().Status(iris.StatusOK).Body().Equal("42:body")
+
+	select {
+	case got := <-shadowed:
+		if expected := "42:body"; got != expected {
+			t.Fatalf("expected shadow request to be '%s' but got '%s'", expected, got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the shadow handler to be executed")
+	}
+}
+
+func TestRouteShadowBodyLimit(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithPostMaxMemory(8))
+	shadowed := make(chan string, 2)
+
+	handler := func(ctx context.Context) {
+		b, _ := ioutil.ReadAll(ctx.Request().Body)
+		ctx.Write(b)
+	}
+	shadow := func(ctx context.Context) {
+		b, _ := ioutil.ReadAll(ctx.Request().Body)
+		shadowed <- string(b)
+	}
+
+	app.Post("/limited", handler).MaxBodySize(4).Shadow(shadow)
+	app.Post("/large", handler).Shadow(shadow)
+
+	e := httptest.New(t, app)
+	e.POST("/limited").WithBytes([]byte("oversized")).Expect().Status(iris.StatusRequestEntityTooLarge)
+	// larger than the post max memory, served but not replayed.
+	e.POST("/large").WithBytes([]byte("large body")).Expect().Status(iris.StatusOK).Body().Equal("large body")
+
+	select {
+	case got := <-shadowed:
+		t.Fatalf("expected the oversized bodies to not be replayed but got '%s'", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestHandleIf(t *testing.T) {
+	app := iris.New()
+	enabled := false
+	app.HandleIf(func(context.Context) bool { return enabled }, "GET", "/beta", func(ctx context.Context) {
+		ctx.WriteString("beta")
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/beta").Expect().Status(iris.StatusNotFound)
+	enabled = true
+	e.GET("/beta").Expect().Status(iris.StatusOK).Body().Equal("beta")
+}
+
+func TestFormatParam(t *testing.T) {
+	app := iris.New()
+	app.Get("/reports/{name}.{format:string within(json,csv,xml)}", func(ctx context.Context) {
+		ctx.Writef("%s as %s", ctx.Params().Get("name"), ctx.Params().Get("format"))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/reports/sales.csv").Expect().Status(iris.StatusOK).Body().Equal("sales as csv")
+	e.GET("/reports/sales.2018.json").Expect().Status(iris.StatusOK).Body().Equal("sales.2018 as json")
+	e.GET("/reports/sales.pdf").Expect().Status(iris.StatusNotFound)
+	e.GET("/reports/sales").Expect().Status(iris.StatusNotFound)
+}
+
+func TestTypedParams(t *testing.T) {
+	app := iris.New()
+	app.Macros().Register("even", func(paramValue string) (interface{}, bool) {
+		n, err := strconv.Atoi(paramValue)
+		return n, err == nil && n%2 == 0
+	})
+
+	app.Get("/orders/{id:uuid}/{date:time}/{total:decimal}", func(ctx context.Context) {
+		id, _ := ctx.Params().GetUUID("id")
+		date, _ := ctx.Params().GetTime("date")
+		total, _ := ctx.Params().GetDecimal("total")
+		ctx.Writef("%s %s %s", id, date.Format("Jan 2"), total.FloatString(2))
+	})
+	app.Get("/even/{n:even}", func(ctx context.Context) {
+		n, _ := ctx.Params().GetValue("n")
+		ctx.Writef("%d", n.(int)/2)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8/2018-05-02/12.3").Expect().Status(iris.StatusOK).
+		Body().Equal("6ba7b810-9dad-11d1-80b4-00c04fd430c8 May 2 12.30")
+	e.GET("/orders/notanuuid/2018-05-02/12.3").Expect().Status(iris.StatusNotFound)
+	e.GET("/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8/yesterday/12.3").Expect().Status(iris.StatusNotFound)
+	e.GET("/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8/2018-05-02/1e3").Expect().Status(iris.StatusNotFound)
+	e.GET("/even/42").Expect().Status(iris.StatusOK).Body().Equal("21")
+	e.GET("/even/7").Expect().Status(iris.StatusNotFound)
+}
+
+func TestVisitTypedParams(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx context.Context) {
+		ctx.Params().VisitTyped(func(key, typ, value string) {
+			ctx.Header("X-Param-"+key, typ+"="+value)
+		})
+		ctx.Next()
+	})
+	app.Get("/users/{id:int}/files/{file}", func(ctx context.Context) {})
+
+	e := httptest.New(t, app)
+	r := e.GET("/users/42/files/a.txt").Expect().Status(iris.StatusOK)
+	r.Header("X-Param-Id").Equal("int=42")
+	r.Header("X-Param-File").Equal("string=a.txt")
+}
+
+func TestAutoOptions(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithAutoOptions)
+	ok := func(ctx context.Context) { ctx.WriteString("ok") }
+
+	app.Get("/users/{id:int}", ok)
+	app.Delete("/users/{id:int}", ok)
+	app.Get("/explicit", ok)
+	app.Options("/explicit", func(ctx context.Context) { ctx.WriteString("explicit") })
+
+	e := httptest.New(t, app)
+	e.OPTIONS("/users/42").Expect().Status(iris.StatusNoContent).Header("Allow").Equal("DELETE, GET, OPTIONS")
+	e.OPTIONS("/users/notanumber").Expect().Status(iris.StatusNotFound)
+	e.OPTIONS("/explicit").Expect().Status(iris.StatusOK).Body().Equal("explicit")
+}
+
+func TestAutoOptionsBuildError(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithAutoOptions)
+	route := app.Get("/users/{id:int}", func(ctx context.Context) {})
+	router.SetRouteTmplSrc(route, "/users/{id:path}/posts")
+
+	// the automatic routes are registered before the api builder's errors are reported.
+	err := app.Build()
+	if err == nil || !strings.Contains(err.Error(), "OPTIONS::/users/{id:path}/posts") {
+		t.Fatalf("expected the error of the automatic OPTIONS route but got: %v", err)
+	}
+}
+
+func TestAutoHead(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithAutoHead)
+
+	app.Get("/", func(ctx context.Context) {
+		ctx.Header("X-Custom", "value")
+		ctx.WriteString("body")
+	})
+	app.Get("/explicit", func(ctx context.Context) { ctx.WriteString("get") })
+	app.Head("/explicit", func(ctx context.Context) { ctx.StatusCode(iris.StatusAccepted) })
+
+	e := httptest.New(t, app)
+	e.HEAD("/").Expect().Status(iris.StatusOK).Header("X-Custom").Equal("value")
+	e.HEAD("/").Expect().Body().Empty()
+	e.HEAD("/").Expect().Header("Content-Length").Equal("4")
+	e.HEAD("/explicit").Expect().Status(iris.StatusAccepted)
+}
+
+func TestAutoHeadWithAutoOptions(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithAutoHead, iris.WithAutoOptions)
+	app.Get("/", func(ctx context.Context) { ctx.WriteString("body") })
+
+	e := httptest.New(t, app)
+	// the automatic HEAD routes are registered first, so they are allowed too.
+	e.OPTIONS("/").Expect().Status(iris.StatusNoContent).Header("Allow").Equal("GET, HEAD, OPTIONS")
+	e.HEAD("/").Expect().Status(iris.StatusOK).Body().Empty()
+}
+
+func TestAutoHeadBuildError(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithAutoHead)
+	app.Get("/", func(ctx context.Context) {})
+	// the name of the automatic HEAD route of the "/".
+	app.Post("/users", func(ctx context.Context) {}).Name = "HEAD/"
+
+	err := app.Build()
+	if err == nil || !strings.Contains(err.Error(), "route name 'HEAD/' is already used by 'POST /users' -> HEAD /") {
+		t.Fatalf("expected the name conflict of the automatic HEAD route but got: %v", err)
+	}
+}
+
+func TestCharsetDecoding(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithContentTypeCharset("text/csv", "ISO-8859-1"))
+	app.Post("/json", func(ctx context.Context) {
+		var v map[string]string
+		if err := ctx.ReadJSON(&v); err != nil {
+			ctx.WriteString(err.Error())
+			return
+		}
+		ctx.WriteString(v["name"])
+	})
+	app.Post("/form", func(ctx context.Context) {
+		ctx.WriteString(ctx.FormValue("name") + ctx.FormValue("q"))
+	})
+	app.Get("/csv", func(ctx context.Context) {
+		ctx.ContentType("text/csv")
+	})
+
+	e := httptest.New(t, app)
+	// "é" is 0xE9 in ISO-8859-1.
+	e.POST("/json").WithHeader("Content-Type", "application/json; charset=ISO-8859-1").
+		WithBytes([]byte("{\"name\":\"caf\xe9\"}")).Expect().Body().Equal("café")
+	e.POST("/form").WithQuery("q", "!").WithHeader("Content-Type", "application/x-www-form-urlencoded; charset=ISO-8859-1").
+		WithBytes([]byte("name=caf%E9")).Expect().Body().Equal("café!")
+	e.POST("/json").WithHeader("Content-Type", "application/json; charset=unknown").
+		WithBytes([]byte("{}")).Expect().Status(iris.StatusUnsupportedMediaType)
+	e.GET("/csv").Expect().Header("Content-Type").Equal("text/csv; charset=ISO-8859-1")
+}
+
+func TestURLWithParams(t *testing.T) {
+	app := iris.New()
+	app.Get("/posts", func(ctx context.Context) {
+		ctx.Writef("%s|%s", ctx.URLWithParams(map[string]string{"page": "2", "q": ""}), ctx.CanonicalQuery())
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/posts").WithQueryString("sort=date&page=1&q=go&empty=").Expect().
+		Body().Equal("/posts?page=2&sort=date|page=1&q=go&sort=date")
+	e.GET("/posts").Expect().Body().Equal("/posts?page=2|")
+}
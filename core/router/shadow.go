@@ -0,0 +1,140 @@
+package router
+
+import (
+	"bytes"
+	stdContext "context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/kataras/iris/context"
+)
+
+// MaxShadowRequests is the maximum number of the in-flight shadow requests of a route,
+// the requests which exceed it are served as usual but they are not replayed to the shadow handler.
+// See `Route#Shadow`.
+var MaxShadowRequests = 64
+
+// shadowResponseWriter is the `http.ResponseWriter` of the shadow requests,
+// their response is discarded.
+type shadowResponseWriter struct {
+	header http.Header
+}
+
+func (w *shadowResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *shadowResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *shadowResponseWriter) WriteHeader(int) {}
+
+// shadowBody is the request body which was partially read by the shadow handler,
+// it's used when the body exceeds the limit, so the route's handlers still read all of it.
+type shadowBody struct {
+	io.Reader
+	io.Closer
+}
+
+// shadowHandler returns a handler which replays the request
+// to the "shadow" handler in its own goroutine and continues with the next handler.
+//
+// The body is buffered up to the route's `MaxBodySize` or the `Configuration#PostMaxMemory`,
+// the larger requests are not replayed, and at most `MaxShadowRequests` shadow requests
+// run at the same time, the rest are dropped.
+func shadowHandler(shadow context.Handler, maxBodySize int64) context.Handler {
+	var (
+		workers = make(chan struct{}, MaxShadowRequests)
+		once    sync.Once
+		pool    *context.Pool
+	)
+
+	return func(ctx context.Context) {
+		app := ctx.Application()
+		once.Do(func() {
+			pool = context.New(func() context.Context { return context.NewContext(app) })
+		})
+
+		select {
+		case workers <- struct{}{}:
+		default:
+			app.Logger().Debugf("shadow request %s %s: dropped, too many in-flight shadow requests", ctx.Method(), ctx.Path())
+			ctx.Next()
+			return
+		}
+
+		release := func() { <-workers }
+
+		r := ctx.Request()
+
+		limit := maxBodySize
+		if limit <= 0 {
+			limit = app.ConfigurationReadOnly().GetPostMaxMemory()
+		}
+
+		var body []byte
+		if r.Body != nil {
+			b, err := ioutil.ReadAll(io.LimitReader(r.Body, limit+1))
+			if err != nil {
+				release()
+				r.Body.Close()
+				// the route's `MaxBodySize` sets the 413 status code on its limit.
+				if ctx.GetStatusCode() != http.StatusRequestEntityTooLarge {
+					ctx.StatusCode(http.StatusBadRequest)
+				}
+				ctx.StopExecution()
+				return
+			}
+
+			if int64(len(b)) > limit {
+				// too large to be buffered, serve it without the shadow request.
+				release()
+				r.Body = shadowBody{Reader: io.MultiReader(bytes.NewReader(b), r.Body), Closer: r.Body}
+				ctx.Next()
+				return
+			}
+
+			r.Body.Close()
+			body = b
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		// the shadow request should not be canceled when the client's one is done.
+		req := r.WithContext(stdContext.Background())
+		req.Header = make(http.Header, len(r.Header))
+		for k, v := range r.Header {
+			req.Header[k] = append([]string(nil), v...)
+		}
+		if r.URL != nil {
+			u := *r.URL
+			req.URL = &u
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var params [][2]string
+		ctx.Params().Visit(func(key, value string) {
+			params = append(params, [2]string{key, value})
+		})
+
+		go func() {
+			defer release()
+			defer func() {
+				if rec := recover(); rec != nil {
+					app.Logger().Warnf("shadow request %s %s: %v", req.Method, req.URL.Path, rec)
+				}
+			}()
+
+			shadowCtx := pool.Acquire(&shadowResponseWriter{header: make(http.Header)}, req)
+			for _, p := range params {
+				shadowCtx.Params().Set(p[0], p[1])
+			}
+			shadowCtx.Do(context.Handlers{shadow})
+			pool.Release(shadowCtx)
+		}()
+
+		ctx.Next()
+	}
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"net/http"
+	"path"
 	"strings"
 
 	"github.com/kataras/iris/context"
@@ -165,3 +167,89 @@ func (s *SPABuilder) Handler(ctx context.Context) {
 
 	}
 }
+
+// SPAOptions contains the options of the `SPAFileSystemHandler`.
+type SPAOptions struct {
+	// APIPrefixes are the request path prefixes that are never served by the single page application,
+	// i.e "/api", an unknown route under these prefixes fires a 404 Not Found
+	// instead of sending the index file, so the API parties always take precedence.
+	APIPrefixes []string
+	// IndexPath is the path of the index file inside the file system,
+	// it's served for all the non-file requests (history-API fallback).
+	//
+	// Defaults to "/index.html".
+	IndexPath string
+	// CacheControl is the "Cache-Control" header value of the files except the index,
+	// i.e "public, max-age=31536000" for built, hashed, assets.
+	// The index file is always sent with "no-cache".
+	//
+	// Defaults to empty, no header.
+	CacheControl string
+}
+
+// SPAFileSystemHandler returns a handler which serves a built, single page application, frontend
+// from the "fs", i.e an embedded file system.
+// The files are served by their request path and any other path,
+// except the ones that look like a file (they have an extension),
+// is served by the index file, so the client-side router can handle it.
+//
+// Register it to a "/{f:path}" route of GET and HEAD methods,
+// the static routes and the `SPAOptions#APIPrefixes` take precedence,
+// see the `Application#SPA` and `Application#SPAFileSystem` too.
+func SPAFileSystemHandler(fs http.FileSystem, opts SPAOptions) context.Handler {
+	indexPath := opts.IndexPath
+	if indexPath == "" {
+		indexPath = "/index.html"
+	}
+	indexPath = path.Clean("/" + indexPath)
+
+	apiPrefixes := make([]string, 0, len(opts.APIPrefixes))
+	for _, prefix := range opts.APIPrefixes {
+		apiPrefixes = append(apiPrefixes, "/"+strings.Trim(prefix, "/"))
+	}
+
+	serveFile := func(ctx context.Context, name string, cacheControl string) bool {
+		f, err := fs.Open(name)
+		if err != nil {
+			return false
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil || info.IsDir() {
+			return false
+		}
+
+		if cacheControl != "" {
+			ctx.Header("Cache-Control", cacheControl)
+		}
+		ctx.Header(contentTypeOptionsHeaderKey, "nosniff")
+		http.ServeContent(ctx.ResponseWriter(), ctx.Request(), info.Name(), info.ModTime(), f)
+		return true
+	}
+
+	return func(ctx context.Context) {
+		reqPath := ctx.Path()
+		for _, prefix := range apiPrefixes {
+			if reqPath == prefix || strings.HasPrefix(reqPath, prefix+"/") {
+				ctx.NotFound()
+				return
+			}
+		}
+
+		name := path.Clean("/" + reqPath)
+		if name != indexPath && serveFile(ctx, name, opts.CacheControl) {
+			return
+		}
+
+		if name != indexPath && path.Ext(name) != "" {
+			// a missing asset, don't send the index as javascript or css.
+			ctx.NotFound()
+			return
+		}
+
+		if !serveFile(ctx, indexPath, "no-cache") {
+			ctx.NotFound()
+		}
+	}
+}
@@ -97,7 +97,7 @@ func defaultErrorCodeHandlers() *ErrorCodeHandlers {
 
 func statusText(statusCode int) context.Handler {
 	return func(ctx context.Context) {
-		ctx.WriteString(http.StatusText(statusCode))
+		ctx.WriteString(ctx.StatusText(statusCode))
 	}
 }
 
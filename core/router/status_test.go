@@ -3,7 +3,9 @@ package router_test
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net/http"
+	stdhttptest "net/http/httptest"
 	"testing"
 
 	"github.com/kataras/iris"
@@ -71,3 +73,117 @@ func checkAndClearBuf(t *testing.T, buff *bytes.Buffer, expected string) {
 
 	buff.Reset()
 }
+
+func TestStatusTextTranslation(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx context.Context) {
+		translateFuncKey := ctx.Application().ConfigurationReadOnly().GetTranslateFunctionContextKey()
+		ctx.Values().Set(translateFuncKey, func(format string, args ...interface{}) string {
+			if format == "iris.status_500" {
+				return "Εσωτερικό σφάλμα"
+			}
+			return format
+		})
+		ctx.Next()
+	})
+	app.Get("/", func(ctx context.Context) { ctx.StatusCode(iris.StatusInternalServerError) })
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusInternalServerError).Body().Equal("Εσωτερικό σφάλμα")
+	e.GET("/notfound").Expect().Status(iris.StatusNotFound).Body().Equal(http.StatusText(iris.StatusNotFound))
+}
+
+func TestProblem(t *testing.T) {
+	app := iris.New()
+	app.OnAnyErrorCode(iris.ProblemHandler)
+	app.Get("/", func(ctx context.Context) {
+		ctx.Problem(iris.NewProblem(iris.StatusBadRequest, "name is required").Key("field", "name"))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusBadRequest).
+		ContentType(context.ContentProblemJSONHeaderValue).
+		JSON().Object().Equal(iris.Map{
+		"type":   "about:blank",
+		"title":  http.StatusText(iris.StatusBadRequest),
+		"status": iris.StatusBadRequest,
+		"detail": "name is required",
+		"field":  "name",
+	})
+	e.GET("/notfound").Expect().Status(iris.StatusNotFound).
+		JSON().Object().Equal(iris.Map{
+		"type":     "about:blank",
+		"title":    http.StatusText(iris.StatusNotFound),
+		"status":   iris.StatusNotFound,
+		"instance": "/notfound",
+	})
+}
+
+func TestStopWithStatus(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx context.Context) {
+		switch ctx.URLParam("stop") {
+		case "status":
+			ctx.StopWithStatus(iris.StatusUnauthorized)
+			return
+		case "json":
+			ctx.StopWithJSON(iris.StatusConflict, iris.Map{"error": "conflict"})
+			return
+		}
+		ctx.Next()
+	})
+	app.OnErrorCode(iris.StatusUnauthorized, func(ctx context.Context) {
+		ctx.WriteString("unauthorized")
+	})
+	app.Get("/", func(ctx context.Context) { ctx.WriteString("ok") })
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("ok")
+	e.GET("/").WithQuery("stop", "status").Expect().Status(iris.StatusUnauthorized).Body().Equal("unauthorized")
+	e.GET("/").WithQuery("stop", "json").Expect().Status(iris.StatusConflict).
+		JSON().Object().Equal(iris.Map{"error": "conflict"})
+}
+
+func TestHijack(t *testing.T) {
+	app := iris.New()
+	app.Get("/", func(ctx context.Context) {
+		conn, buf, err := ctx.Hijack()
+		if err != nil {
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		buf.Flush()
+	})
+	// the in-memory test server does not support hijacking.
+	app.Get("/unsupported", func(ctx context.Context) {
+		if _, _, err := ctx.Hijack(); err != nil && err.Error() == context.ErrHijackNotSupported.Error() {
+			ctx.WriteString("not supported")
+		}
+	})
+	// it should not be fired for the hijacked connection.
+	app.OnErrorCode(iris.StatusNotFound, func(ctx context.Context) {
+		ctx.WriteString("not found")
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, _ := ioutil.ReadAll(res.Body)
+	if expected, got := "hijacked", string(body); expected != got {
+		t.Fatalf("expected body: '%s' but got: '%s'", expected, got)
+	}
+
+	httptest.New(t, app).GET("/unsupported").Expect().Status(iris.StatusOK).Body().Equal("not supported")
+}
@@ -44,6 +44,14 @@ type (
 	Handler = context.Handler
 	// A Map is a shortcut of the map[string]interface{}.
 	Map = context.Map
+	// N is the options of the `Context#Negotiate`.
+	//
+	// A shortcut for the `context#N`.
+	N = context.N
+	// Problem is an RFC 7807 Problem Details response body, see `Context#Problem`.
+	//
+	// A shortcut for the `context#Problem`.
+	Problem = context.Problem
 
 	// Supervisor is a shortcut of the `host#Supervisor`.
 	// Used to add supervisor configurators on common Runners
@@ -57,6 +65,18 @@ type (
 	//
 	// A shortcut for the `core/router#Party`, useful when `PartyFunc` is being used.
 	Party = router.Party
+	// HealthCheck is a named check which is executed by the `Party#RegisterHealthChecks` route.
+	//
+	// A shortcut for the `core/router#HealthCheck`.
+	HealthCheck = router.HealthCheck
+	// SPAOptions contains the options of the `Application#SPA` and `Application#SPAFileSystem`.
+	//
+	// A shortcut for the `core/router#SPAOptions`.
+	SPAOptions = router.SPAOptions
+	// CanaryOptions contains the options of the `Route#Canary`.
+	//
+	// A shortcut for the `core/router#CanaryOptions`.
+	CanaryOptions = router.CanaryOptions
 
 	// ExecutionRules gives control to the execution of the route handlers outside of the handlers themselves.
 	// Usage:
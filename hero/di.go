@@ -8,7 +8,7 @@ import (
 
 func init() {
 	di.DefaultHijacker = func(fieldOrFuncInput reflect.Type) (*di.BindObject, bool) {
-		if !IsContext(fieldOrFuncInput) {
+		if !IsContext(fieldOrFuncInput) && !isStdContext(fieldOrFuncInput) {
 			return nil, false
 		}
 		// this is being used on both func injector and struct injector.
@@ -117,6 +117,8 @@ func DispatchCommon(ctx context.Context,
 			_, err = ctx.JSONP(v)
 		} else if strings.HasPrefix(contentType, context.ContentXMLHeaderValue) {
 			_, err = ctx.XML(v, context.XML{Indent: " "})
+		} else if strings.HasPrefix(contentType, context.ContentMsgPackHeaderValue) {
+			_, err = ctx.MsgPack(v)
 		} else {
 			// defaults to json if content type is missing or its application/json.
 			_, err = ctx.JSON(v, context.JSON{Indent: " "})
@@ -1,6 +1,7 @@
 package hero
 
 import (
+	stdContext "context"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -11,13 +12,22 @@ import (
 	"github.com/kataras/iris/context"
 )
 
-var contextTyp = reflect.TypeOf((*context.Context)(nil)).Elem()
+var (
+	contextTyp    = reflect.TypeOf((*context.Context)(nil)).Elem()
+	stdContextTyp = reflect.TypeOf((*stdContext.Context)(nil)).Elem()
+)
 
 // IsContext returns true if the "inTyp" is a type of Context.
 func IsContext(inTyp reflect.Type) bool {
 	return inTyp.Implements(contextTyp)
 }
 
+// isStdContext returns true if the "inTyp" is the standard `context.Context`,
+// it's bound to the Context too, which is canceled when the client disconnects or the route's timeout fires.
+func isStdContext(inTyp reflect.Type) bool {
+	return inTyp == stdContextTyp
+}
+
 // checks if "handler" is context.Handler: func(context.Context).
 func isContextHandler(handler interface{}) (context.Handler, bool) {
 	h, is := handler.(context.Handler)
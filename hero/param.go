@@ -44,6 +44,12 @@ func resolveParam(currentParamIndex int, typ reflect.Type) (reflect.Value, bool)
 			entry, _ := ctx.Params().GetEntryAt(currentParamIndex)
 			v, _ := entry.Int64Default(0)
 
+			return v
+		}
+	case reflect.Uint64:
+		fn = func(ctx context.Context) uint64 {
+			entry, _ := ctx.Params().GetEntryAt(currentParamIndex)
+			v, _ := entry.Uint64Default(0)
 			return v
 		}
 	case reflect.Bool:
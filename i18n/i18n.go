@@ -0,0 +1,515 @@
+// Package i18n provides the built-in localization of the `Context#Tr`,
+// the framework's client-facing messages and the "tr" template function.
+//
+// The translations are loaded from JSON, YAML or TOML files, one directory per language:
+//
+// ./locales/en-US/messages.yml
+// ./locales/el-GR/messages.yml
+//
+// i18n := i18n.New()
+// i18n.Load("./locales/*/*.yml")
+// app.Configure(iris.WithLocalizer(i18n))
+//
+// A message can be a plain text, a format string, i.e "Hello %s", a text/template,
+// i.e "Hello {{.Name}}", or a map of plural forms ("zero", "one", "two", "few", "many" and "other")
+// which are selected by the first integer argument, i.e:
+//
+// cart:
+//   items:
+//     zero: "Your cart is empty"
+//     one: "One item in your cart"
+//     other: "%d items in your cart"
+//
+// ctx.Tr("cart.items", 3)
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/kataras/iris/context"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// Config contains the options of the `I18n`.
+type Config struct {
+	// URLParameter is the URL query parameter which selects the language, i.e "?lang=el-GR".
+	// "-" disables it.
+	//
+	// Defaults to "lang".
+	URLParameter string
+	// Cookie is the name of the cookie which selects the language,
+	// it's set when the language is selected by the URL parameter.
+	// "-" disables it.
+	//
+	// Defaults to "lang".
+	Cookie string
+	// Default is the language of the requests which do not ask for a loaded one
+	// and the fallback of the missing messages.
+	//
+	// Defaults to the first loaded language.
+	Default string
+	// PluralForm returns the plural form of the "n" count for the "lang" language,
+	// one of the "zero", "one", "two", "few", "many" and "other".
+	//
+	// Defaults to `DefaultPluralForm`.
+	PluralForm func(lang string, n int) string
+}
+
+// DefaultPluralForm returns "zero" for 0, "one" for 1 and "other" for the rest of the counts,
+// a missing form falls back to the "other".
+func DefaultPluralForm(lang string, n int) string {
+	switch n {
+	case 0:
+		return "zero"
+	case 1:
+		return "one"
+	default:
+		return "other"
+	}
+}
+
+var pluralForms = map[string]struct{}{
+	"zero":  {},
+	"one":   {},
+	"two":   {},
+	"few":   {},
+	"many":  {},
+	"other": {},
+}
+
+// I18n is the `context.Localizer` of the translation files, see `New`.
+//
+// The messages should be loaded before the server's start,
+// it's not safe to load them while serving.
+type I18n struct {
+	config    Config
+	languages []string
+	messages  map[string]map[string]*message
+}
+
+var _ context.Localizer = (*I18n)(nil)
+
+// New returns a new, empty, `I18n`, use its `Load` to add the languages.
+//
+// Receives an optional configuration.
+func New(cfg ...Config) *I18n {
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if c.URLParameter == "" {
+		c.URLParameter = "lang"
+	}
+
+	if c.Cookie == "" {
+		c.Cookie = "lang"
+	}
+
+	if c.PluralForm == nil {
+		c.PluralForm = DefaultPluralForm
+	}
+
+	return &I18n{
+		config:   c,
+		messages: make(map[string]map[string]*message),
+	}
+}
+
+// Load loads the translation files which match the "globPattern",
+// the language of each file is the name of its directory, i.e "./locales/*/*.yml".
+// The files are decoded by their extension, ".json", ".yml", ".yaml" or ".toml",
+// the nested keys are joined by dots, i.e "nav.home".
+//
+// If "languages" are passed then only these are loaded, in that order.
+func (i *I18n) Load(globPattern string, languages ...string) error {
+	filenames, err := filepath.Glob(globPattern)
+	if err != nil {
+		return err
+	}
+
+	if len(filenames) == 0 {
+		return fmt.Errorf("i18n: no files found for '%s'", globPattern)
+	}
+
+	byLanguage := make(map[string][]string)
+	var found []string
+	for _, filename := range filenames {
+		lang := filepath.Base(filepath.Dir(filename))
+		if _, ok := byLanguage[lang]; !ok {
+			found = append(found, lang)
+		}
+		byLanguage[lang] = append(byLanguage[lang], filename)
+	}
+
+	if len(languages) == 0 {
+		languages = found
+	}
+
+	for _, lang := range languages {
+		filenames, ok := byLanguage[lang]
+		if !ok {
+			return fmt.Errorf("i18n: no files found for the '%s' language", lang)
+		}
+
+		for _, filename := range filenames {
+			m, err := decodeFile(filename)
+			if err != nil {
+				return err
+			}
+
+			if err = i.LoadMap(lang, m); err != nil {
+				return fmt.Errorf("%v for '%s'", err, filename)
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeFile(filename string) (map[string]interface{}, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".json":
+		err = json.Unmarshal(b, &m)
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(b, &m)
+	case ".toml":
+		err = toml.Unmarshal(b, &m)
+	default:
+		return nil, fmt.Errorf("i18n: unsupported file extension '%s' of '%s'", ext, filename)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("i18n: %v for '%s'", err, filename)
+	}
+
+	return m, nil
+}
+
+// LoadMap adds the "messages" of the "lang" language,
+// the values can be texts, plural forms or nested messages.
+func (i *I18n) LoadMap(lang string, messages map[string]interface{}) error {
+	if _, ok := i.messages[lang]; !ok {
+		i.messages[lang] = make(map[string]*message)
+		i.languages = append(i.languages, lang)
+	}
+
+	return i.addMessages(lang, "", messages)
+}
+
+func (i *I18n) addMessages(lang, prefix string, messages map[string]interface{}) error {
+	for k, v := range messages {
+		key := prefix + k
+		if nested, ok := toStringMap(v); ok {
+			if !isPlural(nested) {
+				if err := i.addMessages(lang, key+".", nested); err != nil {
+					return err
+				}
+				continue
+			}
+
+			msg := &message{plurals: make(map[string]*message, len(nested))}
+			for form, text := range nested {
+				pm, err := newMessage(key, fmt.Sprint(text))
+				if err != nil {
+					return err
+				}
+				msg.plurals[form] = pm
+			}
+			i.messages[lang][key] = msg
+			continue
+		}
+
+		msg, err := newMessage(key, fmt.Sprint(v))
+		if err != nil {
+			return err
+		}
+		i.messages[lang][key] = msg
+	}
+
+	return nil
+}
+
+// toStringMap returns the "v" as a map of the JSON, TOML or YAML nested objects.
+func toStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		sm := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			sm[fmt.Sprint(k)] = v
+		}
+		return sm, true
+	default:
+		return nil, false
+	}
+}
+
+// isPlural reports whether the "m" is a map of plural forms, it should contain the "other".
+func isPlural(m map[string]interface{}) bool {
+	if _, ok := m["other"]; !ok {
+		return false
+	}
+
+	for k, v := range m {
+		if _, ok := pluralForms[k]; !ok {
+			return false
+		}
+
+		if _, nested := toStringMap(v); nested {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Languages returns the loaded languages, in the order they were loaded.
+func (i *I18n) Languages() []string {
+	return i.languages
+}
+
+func (i *I18n) defaultLanguage() string {
+	if lang, ok := i.match(i.config.Default); ok {
+		return lang
+	}
+
+	if len(i.languages) > 0 {
+		return i.languages[0]
+	}
+
+	return ""
+}
+
+// match returns the loaded language of the "lang", the comparison is case-insensitive
+// and a language without a region, i.e "en", matches the first loaded region, i.e "en-US".
+func (i *I18n) match(lang string) (string, bool) {
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		return "", false
+	}
+
+	for _, l := range i.languages {
+		if strings.EqualFold(l, lang) {
+			return l, true
+		}
+	}
+
+	base := baseLanguage(lang)
+	for _, l := range i.languages {
+		if strings.EqualFold(baseLanguage(l), base) {
+			return l, true
+		}
+	}
+
+	return "", false
+}
+
+func baseLanguage(lang string) string {
+	if idx := strings.IndexAny(lang, "-_"); idx > 0 {
+		return lang[:idx]
+	}
+
+	return lang
+}
+
+// GetLanguage returns the language of the request, one of the loaded ones, from:
+// the context's value of the `Configuration#TranslateLanguageContextKey`, set by a previous handler,
+// the URL parameter, the cookie or the "Accept-Language" header, otherwise the default language.
+// It's stored to the context's values, so it's resolved once per request.
+func (i *I18n) GetLanguage(ctx context.Context) string {
+	langKey := ctx.Application().ConfigurationReadOnly().GetTranslateLanguageContextKey()
+	if lang, ok := i.match(ctx.Values().GetString(langKey)); ok {
+		return lang
+	}
+
+	lang, ok := "", false
+	if i.config.URLParameter != "-" {
+		if lang, ok = i.match(ctx.URLParam(i.config.URLParameter)); ok && i.config.Cookie != "-" {
+			ctx.SetCookieKV(i.config.Cookie, lang)
+		}
+	}
+
+	if !ok && i.config.Cookie != "-" {
+		lang, ok = i.match(ctx.GetCookie(i.config.Cookie))
+	}
+
+	if !ok {
+		for _, accepted := range acceptedLanguages(ctx.GetHeader("Accept-Language")) {
+			if lang, ok = i.match(accepted); ok {
+				break
+			}
+		}
+	}
+
+	if !ok {
+		lang = i.defaultLanguage()
+	}
+
+	ctx.Values().Set(langKey, lang)
+	return lang
+}
+
+// acceptedLanguages returns the languages of an "Accept-Language" header value,
+// sorted by their quality, i.e "el-GR,en;q=0.8".
+func acceptedLanguages(header string) []string {
+	type accepted struct {
+		lang string
+		q    float64
+	}
+
+	var list []accepted
+	for _, entry := range strings.Split(header, ",") {
+		parts := strings.Split(entry, ";")
+		lang := strings.TrimSpace(parts[0])
+		if lang == "" || lang == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if q > 0 {
+			list = append(list, accepted{lang, q})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].q > list[j].q })
+
+	langs := make([]string, len(list))
+	for idx, a := range list {
+		langs[idx] = a.lang
+	}
+
+	return langs
+}
+
+// Tr returns the "key" message of the "lang" language, or of the default language if it's missing,
+// the first integer of the "args" selects the plural form and the "args" format the message.
+// It returns an empty string if the message is missing.
+func (i *I18n) Tr(lang, key string, args ...interface{}) string {
+	msg, ok := i.messages[lang][key]
+	if !ok {
+		if msg, ok = i.messages[i.defaultLanguage()][key]; !ok {
+			return ""
+		}
+	}
+
+	count, hasCount := countOf(args)
+	if msg.plurals != nil {
+		n := 0
+		if hasCount {
+			n = count
+		}
+
+		pm, ok := msg.plurals[i.config.PluralForm(lang, n)]
+		if !ok {
+			pm = msg.plurals["other"]
+		}
+		msg = pm
+	}
+
+	return msg.render(args, count, hasCount)
+}
+
+// countOf returns the first integer of the "args".
+func countOf(args []interface{}) (int, bool) {
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case int:
+			return v, true
+		case int8:
+			return int(v), true
+		case int16:
+			return int(v), true
+		case int32:
+			return int(v), true
+		case int64:
+			return int(v), true
+		case uint:
+			return int(v), true
+		case uint8:
+			return int(v), true
+		case uint16:
+			return int(v), true
+		case uint32:
+			return int(v), true
+		case uint64:
+			return int(v), true
+		}
+	}
+
+	return 0, false
+}
+
+type message struct {
+	text    string
+	tmpl    *template.Template
+	plurals map[string]*message
+}
+
+func newMessage(key, text string) (*message, error) {
+	msg := &message{text: text}
+	if strings.Contains(text, "{{") {
+		tmpl, err := template.New(key).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("i18n: %v", err)
+		}
+		msg.tmpl = tmpl
+	}
+
+	return msg, nil
+}
+
+// render executes the template with the first non-integer argument, or the count,
+// or it formats the text with the "args" if it contains a verb.
+func (m *message) render(args []interface{}, count int, hasCount bool) string {
+	if m.tmpl != nil {
+		var data interface{}
+		if hasCount {
+			data = count
+		}
+		for _, arg := range args {
+			if _, isCount := countOf([]interface{}{arg}); !isCount {
+				data = arg
+				break
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := m.tmpl.Execute(&buf, data); err != nil {
+			return m.text
+		}
+		return buf.String()
+	}
+
+	if len(args) > 0 && strings.Contains(m.text, "%") {
+		return fmt.Sprintf(m.text, args...)
+	}
+
+	return m.text
+}
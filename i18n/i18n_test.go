@@ -0,0 +1,181 @@
+package i18n
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+var testFiles = map[string]string{
+	"en-US/messages.yml": `
+hello: "Hello %s"
+welcome: "Welcome {{.Name}}"
+nav:
+  home: "Home"
+cart:
+  items:
+    zero: "Your cart is empty"
+    one: "One item in your cart"
+    other: "%d items in your cart"
+`,
+	"el-GR/messages.json": `{"hello": "Γειά σου %s", "nav": {"home": "Αρχική"}}`,
+	"fr-FR/messages.toml": `hello = "Bonjour %s"
+[cart.items]
+one = "Un article"
+other = "%d articles"
+`,
+}
+
+func testLoad(t *testing.T, languages ...string) *I18n {
+	dir, err := ioutil.TempDir("", "iris-i18n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, contents := range testFiles {
+		filename := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		if err = ioutil.WriteFile(filename, []byte(contents), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	i := New()
+	if err = i.Load(filepath.Join(dir, "*", "*.*"), languages...); err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestLoad(t *testing.T) {
+	i := testLoad(t, "en-US", "el-GR", "fr-FR")
+	if expected := []string{"en-US", "el-GR", "fr-FR"}; !reflect.DeepEqual(i.Languages(), expected) {
+		t.Fatalf("expected the languages %v but got: %v", expected, i.Languages())
+	}
+
+	tests := []struct {
+		lang, key string
+		args      []interface{}
+		expected  string
+	}{
+		{"en-US", "hello", []interface{}{"iris"}, "Hello iris"},
+		{"el-GR", "hello", []interface{}{"iris"}, "Γειά σου iris"},
+		{"fr-FR", "hello", []interface{}{"iris"}, "Bonjour iris"},
+		{"en-US", "welcome", []interface{}{map[string]string{"Name": "iris"}}, "Welcome iris"},
+		{"el-GR", "nav.home", nil, "Αρχική"},
+		// the missing messages fall back to the default language.
+		{"fr-FR", "nav.home", nil, "Home"},
+		{"en-US", "missing", nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := i.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("expected the '%s' of '%s' to be '%s' but got: '%s'", tt.key, tt.lang, tt.expected, got)
+		}
+	}
+
+	// only the passed languages are loaded.
+	if i = testLoad(t, "el-GR"); !reflect.DeepEqual(i.Languages(), []string{"el-GR"}) {
+		t.Fatalf("expected only the el-GR to be loaded but got: %v", i.Languages())
+	}
+
+	if err := New().Load(filepath.Join(os.TempDir(), "iris-i18n-missing", "*", "*.yml")); err == nil {
+		t.Fatalf("expected an error for the missing files")
+	}
+}
+
+func TestPlurals(t *testing.T) {
+	i := testLoad(t, "en-US", "fr-FR")
+
+	tests := []struct {
+		lang     string
+		count    interface{}
+		expected string
+	}{
+		{"en-US", 0, "Your cart is empty"},
+		{"en-US", 1, "One item in your cart"},
+		{"en-US", uint8(5), "5 items in your cart"},
+		// the missing "zero" form falls back to the "other".
+		{"fr-FR", 0, "0 articles"},
+		{"fr-FR", int64(1), "Un article"},
+		{"fr-FR", 3, "3 articles"},
+	}
+
+	for _, tt := range tests {
+		if got := i.Tr(tt.lang, "cart.items", tt.count); got != tt.expected {
+			t.Fatalf("expected the plural of %v in '%s' to be '%s' but got: '%s'", tt.count, tt.lang, tt.expected, got)
+		}
+	}
+
+	// a custom plural form.
+	i.config.PluralForm = func(lang string, n int) string { return "one" }
+	if got := i.Tr("en-US", "cart.items", 42); got != "One item in your cart" {
+		t.Fatalf("expected the custom plural form to be used but got: '%s'", got)
+	}
+}
+
+func TestAcceptedLanguages(t *testing.T) {
+	tests := map[string][]string{
+		"":                                {},
+		"el-GR":                           {"el-GR"},
+		"fr;q=0.5, el-GR, en;q=0.8":       {"el-GR", "en", "fr"},
+		"en;q=0.8, fr;q=0.8, de;q=0.9, *": {"de", "en", "fr"},
+		"en;q=0, el":                      {"el"},
+		"en;q=invalid":                    {"en"},
+	}
+
+	for header, expected := range tests {
+		if got := acceptedLanguages(header); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected the languages of '%s' to be %v but got: %v", header, expected, got)
+		}
+	}
+}
+
+func TestGetLanguage(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithLocalizer(testLoad(t, "en-US", "el-GR", "fr-FR")))
+	app.Get("/", func(ctx context.Context) {
+		ctx.WriteString(ctx.Tr("hello", "iris"))
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/").Expect().Body().Equal("Hello iris")
+	// the languages without a region match the loaded ones.
+	e.GET("/").WithHeader("Accept-Language", "el").Expect().Body().Equal("Γειά σου iris")
+	e.GET("/").WithHeader("Accept-Language", "de, fr;q=0.5, el;q=0.8").Expect().Body().Equal("Γειά σου iris")
+	e.GET("/").WithHeader("Accept-Language", "de-DE").Expect().Body().Equal("Hello iris")
+	e.GET("/").WithHeader("Accept-Language", "el;q=0, fr").Expect().Body().Equal("Bonjour iris")
+
+	// the URL parameter sets the cookie.
+	resp := e.GET("/").WithQuery("lang", "fr").WithHeader("Accept-Language", "el").Expect()
+	resp.Body().Equal("Bonjour iris")
+	resp.Cookie("lang").Value().Equal("fr-FR")
+
+	e.GET("/").WithCookie("lang", "el-gr").Expect().Body().Equal("Γειά σου iris")
+}
+
+func TestDefaultLanguage(t *testing.T) {
+	i := testLoad(t, "en-US", "el-GR")
+	i.config.Default = "el"
+
+	if got := i.Tr("fr-FR", "nav.home"); got != "Αρχική" {
+		t.Fatalf("expected the message of the default language but got: '%s'", got)
+	}
+
+	if got := i.defaultLanguage(); got != "el-GR" {
+		t.Fatalf("expected the default language to be 'el-GR' but got: '%s'", got)
+	}
+
+	i.config.Default = "de"
+	if got := i.defaultLanguage(); got != "en-US" {
+		t.Fatalf("expected the first loaded language to be the default but got: '%s'", got)
+	}
+}
@@ -334,6 +334,25 @@ func (app *Application) View(writer io.Writer, filename string, layout string, b
 	return err
 }
 
+// ViewFuncs is like `View` but the "funcs" are available to this template execution only,
+// the registered view engines' functions are not modified.
+// The view engines which do not support per-execution functions ignore them.
+//
+// Use context.ViewFunc and context.View instead.
+func (app *Application) ViewFuncs(writer io.Writer, filename string, layout string, bindingData interface{}, funcs map[string]interface{}) error {
+	if app.view.Len() == 0 {
+		err := errors.New("view engine is missing, use `RegisterView`")
+		app.Logger().Error(err)
+		return err
+	}
+
+	err := app.view.ExecuteWriterFuncs(writer, filename, layout, bindingData, funcs)
+	if err != nil {
+		app.Logger().Error(err)
+	}
+	return err
+}
+
 var (
 	// LimitRequestBodySize is a middleware which sets a request body size limit
 	// for all next handlers in the chain.
@@ -363,6 +382,36 @@ var (
 	//
 	// A shortcut for the `context#Gzip`.
 	Gzip = context.Gzip
+	// Compress is a middleware which enables the compression of the response
+	// by the best encoding that the client accepts, i.e "br", "zstd", "gzip" or "deflate".
+	//
+	// A shortcut for the `context#Compress`.
+	Compress = context.Compress
+	// NoGzip is a middleware which disables the gzip compression
+	// for the rest of the request, even if a next handler calls the `Gzip(true)`.
+	//
+	// A shortcut for the `context#NoGzip`.
+	NoGzip = context.NoGzip
+	// DecompressBody is a middleware which decompresses the gzip or deflate request bodies,
+	// with limits for the decompressed size and the expansion ratio.
+	//
+	// A shortcut for the `context#DecompressBody`.
+	DecompressBody = context.DecompressBody
+	// ETag is a middleware which computes the ETag of the successful GET and HEAD responses
+	// and responds with a 304 Not Modified when it matches the request's "If-None-Match" header.
+	//
+	// A shortcut for the `context#ETag`.
+	ETag = context.ETag
+	// NewProblem returns a new `Problem` of the "status" with a "detail" message.
+	//
+	// A shortcut for the `context#NewProblem`.
+	NewProblem = context.NewProblem
+	// ProblemHandler renders the response's error status code as a `Problem`,
+	// register it as the error code handler to respond with consistent API error bodies, i.e:
+	// app.OnAnyErrorCode(iris.ProblemHandler)
+	//
+	// A shortcut for the `context#ProblemHandler`.
+	ProblemHandler = context.ProblemHandler
 	// FromStd converts native http.Handler, http.HandlerFunc & func(w, r, next) to context.Handler.
 	//
 	// Supported form types:
@@ -430,13 +479,52 @@ var (
 // it's a helper function which just makes some checks based on the `IndexNames` and `AssetValidators`
 // before the assetHandler call.
 //
+// The "assets" can be an `http.FileSystem` too, i.e an embedded file system of a built frontend,
+// then it's served like the `SPAFileSystem` with the optional "opts", the history-API fallback
+// to the index file is done by the file system's handler, so the returned builder has an empty `Root`.
+//
+// Usage:
+// app.SPA(assetFS, iris.SPAOptions{APIPrefixes: []string{"/api"}, CacheControl: "public, max-age=31536000"})
+//
 // Example: https://github.com/kataras/iris/tree/master/_examples/file-server/single-page-application
-func (app *Application) SPA(assetHandler context.Handler) *router.SPABuilder {
-	s := router.NewSPABuilder(assetHandler)
+func (app *Application) SPA(assets interface{}, opts ...router.SPAOptions) *router.SPABuilder {
+	var s *router.SPABuilder
+
+	switch v := assets.(type) {
+	case context.Handler:
+		s = router.NewSPABuilder(v)
+	case func(context.Context):
+		s = router.NewSPABuilder(v)
+	case http.FileSystem:
+		var options router.SPAOptions
+		if len(opts) > 0 {
+			options = opts[0]
+		}
+		s = router.NewSPABuilder(router.SPAFileSystemHandler(v, options)).ChangeRoot("")
+	default:
+		app.APIBuilder.GetReporter().Add("SPA: expected an asset handler or an http.FileSystem but got: %T", assets)
+		return router.NewSPABuilder(nil)
+	}
+
 	app.APIBuilder.HandleMany("GET HEAD", "/{f:path}", s.Handler)
 	return s
 }
 
+// SPAFileSystem serves a built, single page application, frontend from the "fs",
+// i.e an embedded file system, with history-API fallback to the index file.
+// The routes that are registered on the same paths, before or after it, take precedence
+// and the `SPAOptions#APIPrefixes` are never served by the index file,
+// so an unknown API route fires a 404 Not Found instead, it doesn't depend on the routes' registration order.
+//
+// Usage:
+// app.SPAFileSystem(assetFS, iris.SPAOptions{APIPrefixes: []string{"/api"}})
+// api := app.Party("/api")
+//
+// See `router#SPAFileSystemHandler` and `SPA` for more.
+func (app *Application) SPAFileSystem(fs http.FileSystem, opts router.SPAOptions) []*router.Route {
+	return app.APIBuilder.HandleMany("GET HEAD", "/{f:path}", router.SPAFileSystemHandler(fs, opts))
+}
+
 // ConfigureHost accepts one or more `host#Configuration`, these configurators functions
 // can access the host created by `app.Run`,
 // they're being executed when application is ready to being served to the public.
@@ -542,6 +630,34 @@ func (app *Application) Shutdown(ctx stdContext.Context) error {
 	return nil
 }
 
+// Upgrade starts a new process of the current executable which takes over
+// the listeners of the application's hosts, without dropping connections,
+// and, when the new process is ready to serve, it gracefully shuts down the hosts of this process.
+// The "ctx" limits both the time to wait for the new process and the graceful shutdown.
+//
+// It's usually called on a signal, i.e SIGUSR2, when the binary was replaced by a newer version.
+//
+// Usage:
+// sig := make(chan os.Signal, 1)
+// signal.Notify(sig, syscall.SIGUSR2)
+// go func() {
+//     <-sig
+//     ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//     defer cancel()
+//     if err := app.Upgrade(ctx); err != nil { app.Logger().Error(err) }
+// }()
+//
+// A shortcut for the `host#Upgrade` plus `Shutdown`, it's not supported on windows.
+func (app *Application) Upgrade(ctx stdContext.Context) error {
+	app.logger.Debugf("Upgrade: start new process")
+	if err := host.Upgrade(ctx, app.Hosts...); err != nil {
+		return err
+	}
+
+	app.logger.Debugf("Upgrade: new process is ready, shutdown now")
+	return app.Shutdown(ctx)
+}
+
 // Runner is just an interface which accepts the framework instance
 // and returns an error.
 //
@@ -707,6 +823,34 @@ func Raw(f func() error) Runner {
 	}
 }
 
+// Service can be used as an argument for the `Run` method.
+// It runs the "runner" as the Windows service "name"
+// when the process is started by the service control manager,
+// the service's stop and system's shutdown events gracefully shutdown the application,
+// like the CTRL+C/CMD+C and the SIGINT and SIGTERM signals do.
+// Otherwise, i.e from a console or on unix, it just runs the "runner".
+//
+// Use the `host#InstallService` and `host#UninstallService` to manage the service.
+//
+// Usage:
+// app.Run(iris.Service("myapp", iris.Addr(":8080")))
+//
+// See `Run` for more.
+func Service(name string, runner Runner) Runner {
+	return func(app *Application) error {
+		return host.RunService(name, func() error { return runner(app) }, func() {
+			if app.config.DisableInterruptHandler {
+				ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 5*time.Second)
+				defer cancel()
+				app.Shutdown(ctx)
+				return
+			}
+
+			host.Interrupt.FireNow()
+		})
+	}
+}
+
 // Build sets up, once, the framework.
 // It builds the default router with its default macros
 // and the template functions that are very-closed to iris.
@@ -714,6 +858,16 @@ func (app *Application) Build() error {
 	rp := errors.NewReporter()
 
 	app.once.Do(func() {
+		// the automatic routes are registered first, so their errors are reported too,
+		// the HEAD ones before the OPTIONS, so they are part of the "Allow" header.
+		if app.config.AutoHead {
+			app.APIBuilder.RegisterAutoHead()
+		}
+
+		if app.config.AutoOptions {
+			app.APIBuilder.RegisterAutoOptions()
+		}
+
 		rp.Describe("api builder: %v", app.APIBuilder.GetReport())
 
 		if !app.Router.Downgraded() {
@@ -733,6 +887,10 @@ func (app *Application) Build() error {
 			// Each engine has their defaults, i.e yield,render,render_r,partial, params...
 			rv := router.NewRoutePathReverser(app.APIBuilder)
 			app.view.AddFunc("urlpath", rv.Path)
+			if app.config.Localizer != nil {
+				// declares the "tr", the `Context#View` passes the request's one.
+				app.view.AddFunc("tr", func(key string, args ...interface{}) string { return key })
+			}
 			// app.view.AddFunc("url", rv.URL)
 			rp.Describe("view: %v", app.view.Load())
 		}
@@ -0,0 +1,34 @@
+package iris
+
+import (
+	stdContext "context"
+	stdErrors "errors"
+	"runtime"
+	"testing"
+
+	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/core/host"
+)
+
+func TestService(t *testing.T) {
+	expected := stdErrors.New("run")
+
+	app := New()
+	err := app.Run(Service("iris", Raw(func() error { return expected })), WithoutStartupLog)
+
+	// not started by the service control manager, the runner runs as it is.
+	if err != expected {
+		t.Fatalf("expected the runner to run and its error to be returned but got: %v", err)
+	}
+}
+
+func TestUpgradeWithoutHosts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("upgrade is not supported on windows")
+	}
+
+	err := New().Upgrade(stdContext.Background())
+	if e, ok := err.(errors.Error); !ok || !e.Equal(host.ErrUpgradeFailed) {
+		t.Fatalf("expected the upgrade to fail without any listeners but got: %v", err)
+	}
+}
@@ -1,5 +1,10 @@
 // Package i18n provides internalization and localization via middleware.
 // See _examples/miscellaneous/i18n
+//
+// The framework's client-facing messages, i.e the default http error texts
+// and the validation errors, are translated too, add their IDs (see the `context#MessageStatusText`
+// and the `context#MessageValidationError`) to the locale files,
+// i.e "status_404" and "validation_required" under the "[iris]" section.
 package i18n
 
 import (
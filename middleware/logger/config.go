@@ -36,6 +36,12 @@ type Config struct {
 	// Defaults to false.
 	Query bool
 
+	// RequestID appends the ID of the request, which is set by the `middleware/requestid`,
+	// to the message.
+	//
+	// Defaults to false.
+	RequestID bool
+
 	// Columns will display the logs as a formatted columns-rows text (bool).
 	// If custom `LogFunc` has been provided then this field is useless and users should
 	// use the `Columinize` function of the logger to get the output result as columns.
@@ -83,15 +89,16 @@ type Config struct {
 // LogFunc and Skippers to nil as well.
 func DefaultConfig() Config {
 	return Config{
-		Status:   true,
-		IP:       true,
-		Method:   true,
-		Path:     true,
-		Query:    false,
-		Columns:  false,
-		LogFunc:  nil,
-		Skippers: nil,
-		skip:     nil,
+		Status:    true,
+		IP:        true,
+		Method:    true,
+		Path:      true,
+		Query:     false,
+		RequestID: false,
+		Columns:   false,
+		LogFunc:   nil,
+		Skippers:  nil,
+		skip:      nil,
 	}
 }
 
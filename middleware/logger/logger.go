@@ -74,6 +74,12 @@ func (l *requestLoggerMiddleware) ServeHTTP(ctx context.Context) {
 	}
 
 	var message interface{}
+	if l.config.RequestID {
+		if id := ctx.RequestID(); id != "" {
+			message = id
+		}
+	}
+
 	if ctxKeys := l.config.MessageContextKeys; len(ctxKeys) > 0 {
 		for _, key := range ctxKeys {
 			msg := ctx.Values().Get(key)
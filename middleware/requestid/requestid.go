@@ -0,0 +1,130 @@
+// Package requestid provides the request ID generation and propagation via middleware.
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+
+	"github.com/kataras/iris/context"
+
+	"github.com/satori/go.uuid"
+)
+
+// Generator is the function which generates a new request ID.
+type Generator func(ctx context.Context) string
+
+// UUID generates a random UUID (version 4), it's the default `Generator`.
+func UUID(ctx context.Context) string {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID generates a ULID, the 26 characters are sorted lexicographically by the time of the request.
+func ULID(ctx context.Context) string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	binary.BigEndian.PutUint16(b[:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	if _, err := rand.Read(b[6:]); err != nil {
+		return ""
+	}
+
+	// 128 bits encoded as 26 base32 characters, 5 bits each, the first one has 3 bits.
+	var id [26]byte
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	for i := 25; i >= 0; i-- {
+		id[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(id[:])
+}
+
+// Config contains the options of the request ID middleware.
+type Config struct {
+	// HeaderKey is the request and response header of the ID.
+	//
+	// Defaults to "X-Request-Id".
+	HeaderKey string
+	// Generator generates the ID of the requests which do not send a valid one.
+	//
+	// Defaults to `UUID`.
+	Generator Generator
+	// IgnoreIncoming does not read the ID from the request header,
+	// a new one is always generated, i.e for the public facing servers.
+	//
+	// Defaults to false.
+	IgnoreIncoming bool
+	// MaxLength is the maximum length of a received ID, the longer ones are replaced by a generated one.
+	//
+	// Defaults to 128.
+	MaxLength int
+}
+
+// New returns a new request ID middleware.
+// It reads the ID from the "X-Request-Id" request header, if it's missing
+// or it's not valid then it generates a new one, and it sets it
+// to the "X-Request-Id" response header and to the request's values,
+// the next handlers can read it by the `ctx.RequestID()` and the request logger
+// middleware logs it too when its `Config#RequestID` is true.
+//
+// Receives an optional configuation.
+func New(cfg ...Config) context.Handler {
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if c.HeaderKey == "" {
+		c.HeaderKey = context.RequestIDHeaderKey
+	}
+
+	if c.Generator == nil {
+		c.Generator = UUID
+	}
+
+	if c.MaxLength <= 0 {
+		c.MaxLength = 128
+	}
+
+	return func(ctx context.Context) {
+		var id string
+		if !c.IgnoreIncoming {
+			if id = ctx.GetHeader(c.HeaderKey); !valid(id, c.MaxLength) {
+				id = ""
+			}
+		}
+
+		if id == "" {
+			id = c.Generator(ctx)
+		}
+
+		ctx.Header(c.HeaderKey, id)
+		context.RequestIDKey.Set(ctx, id)
+		ctx.Next()
+	}
+}
+
+// valid reports whether the received "id" can be logged and sent back as it's,
+// it accepts only the printable ASCII characters, except the spaces.
+func valid(id string, maxLength int) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
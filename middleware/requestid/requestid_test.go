@@ -0,0 +1,123 @@
+package requestid
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+	"github.com/kataras/iris/middleware/logger"
+)
+
+var (
+	uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidRegexp = regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+)
+
+func expectUUID(t *testing.T, id string) {
+	t.Helper()
+	if !uuidRegexp.MatchString(id) {
+		t.Fatalf("expected a generated UUID but got '%s'", id)
+	}
+}
+
+func writeRequestID(ctx context.Context) {
+	ctx.WriteString(ctx.RequestID())
+}
+
+func TestRequestID(t *testing.T) {
+	app := iris.New()
+	app.Get("/", New(), writeRequestID)
+	app.Get("/ignore", New(Config{IgnoreIncoming: true}), writeRequestID)
+	app.Get("/none", writeRequestID)
+
+	e := httptest.New(t, app)
+
+	// a new ID is generated when the request does not send one.
+	resp := e.GET("/").Expect().Status(iris.StatusOK)
+	id := resp.Header(context.RequestIDHeaderKey).Raw()
+	expectUUID(t, id)
+	resp.Body().Equal(id)
+	if other := e.GET("/").Expect().Header(context.RequestIDHeaderKey).Raw(); other == id {
+		t.Fatalf("expected a different ID for each request but got '%s' twice", id)
+	}
+
+	// the valid incoming ID is trusted and propagated.
+	e.GET("/").WithHeader(context.RequestIDHeaderKey, "upstream-42").Expect().Status(iris.StatusOK).
+		Header(context.RequestIDHeaderKey).Equal("upstream-42")
+	e.GET("/").WithHeader(context.RequestIDHeaderKey, "upstream-42").Expect().
+		Body().Equal("upstream-42")
+
+	// the invalid ones are replaced by a generated one.
+	for _, invalid := range []string{"with space", "new\tline", "π", strings.Repeat("a", 129)} {
+		expectUUID(t, e.GET("/").WithHeader(context.RequestIDHeaderKey, invalid).Expect().Status(iris.StatusOK).
+			Header(context.RequestIDHeaderKey).Raw())
+	}
+	e.GET("/").WithHeader(context.RequestIDHeaderKey, strings.Repeat("a", 128)).Expect().
+		Header(context.RequestIDHeaderKey).Equal(strings.Repeat("a", 128))
+
+	expectUUID(t, e.GET("/ignore").WithHeader(context.RequestIDHeaderKey, "upstream-42").Expect().Status(iris.StatusOK).
+		Header(context.RequestIDHeaderKey).Raw())
+
+	// without the middleware the ID is empty.
+	e.GET("/none").WithHeader(context.RequestIDHeaderKey, "upstream-42").Expect().Status(iris.StatusOK).
+		Body().Empty()
+}
+
+func TestRequestIDConfig(t *testing.T) {
+	app := iris.New()
+	app.Use(New(Config{
+		HeaderKey: "X-Correlation-Id",
+		Generator: func(ctx context.Context) string { return "generated-" + ctx.Path()[1:] },
+		MaxLength: 8,
+	}))
+	app.Get("/{name}", writeRequestID)
+
+	e := httptest.New(t, app)
+	e.GET("/a").Expect().Status(iris.StatusOK).
+		Header("X-Correlation-Id").Equal("generated-a")
+	e.GET("/a").WithHeader("X-Correlation-Id", "12345678").Expect().Status(iris.StatusOK).
+		Body().Equal("12345678")
+	e.GET("/a").WithHeader("X-Correlation-Id", "123456789").Expect().Status(iris.StatusOK).
+		Body().Equal("generated-a")
+	// the default header is not read.
+	e.GET("/b").WithHeader(context.RequestIDHeaderKey, "upstream").Expect().Status(iris.StatusOK).
+		Body().Equal("generated-b")
+}
+
+func TestRequestIDLogger(t *testing.T) {
+	var messages []interface{}
+
+	app := iris.New()
+	app.Use(New())
+	app.Use(logger.New(logger.Config{
+		RequestID: true,
+		LogFunc: func(now time.Time, latency time.Duration, status, ip, method, path string, message interface{}, headerMessage interface{}) {
+			messages = append(messages, message)
+		},
+	}))
+	app.Get("/", writeRequestID)
+
+	e := httptest.New(t, app)
+	e.GET("/").WithHeader(context.RequestIDHeaderKey, "upstream-42").Expect().Status(iris.StatusOK)
+
+	if len(messages) != 1 || messages[0] != "upstream-42" {
+		t.Fatalf("expected the request ID to be logged but got: %v", messages)
+	}
+}
+
+func TestULID(t *testing.T) {
+	prev := ULID(nil)
+	if !ulidRegexp.MatchString(prev) {
+		t.Fatalf("expected a valid ULID but got '%s'", prev)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	// the ULIDs are sorted by their time.
+	if next := ULID(nil); next <= prev {
+		t.Fatalf("expected '%s' to be sorted after '%s'", next, prev)
+	}
+}
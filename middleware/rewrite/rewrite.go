@@ -0,0 +1,207 @@
+// Package rewrite provides a rules engine for redirects and internal rewrites
+// which runs before the router, i.e force HTTPS, www to root domain, legacy paths.
+package rewrite
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/core/router"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Options holds the rewrite engine's rules.
+// It can be set by code or loaded from a YAML or JSON file through the `Load` function.
+type Options struct {
+	// RedirectMatch accepts a slice of lines
+	// of form:
+	// REDIRECT_CODE PATH_PATTERN TARGET_PATH
+	// Example: []{"301 /seo/(.*) /$1"}.
+	//
+	// The PATH_PATTERN is a regular expression which is matched against the request's path,
+	// the TARGET_PATH can be a path or a full url and it may contain the pattern's submatches, i.e $1.
+	// The matched part of the path is replaced, use the ^ and $ anchors for exact matches.
+	// A REDIRECT_CODE of 0 means that the request is internally rewritten
+	// to the TARGET_PATH and no redirect response is sent to the client,
+	// the TARGET_PATH's query, if any, replaces the request's one, i.e "0 /old/(.*) /new?id=$1".
+	RedirectMatch []string `json:"redirectMatch" yaml:"RedirectMatch"`
+	// ForceHTTPS redirects (301) all plain HTTP requests to their HTTPS equivalent.
+	// The "X-Forwarded-Proto" header is respected
+	// for applications that run behind a TLS-terminating proxy.
+	ForceHTTPS bool `json:"forceHTTPS" yaml:"ForceHTTPS"`
+	// PrimarySubdomain if not empty, redirects (301) requests to the primary (sub)domain.
+	// Set it to "www" to redirect mydomain.com to www.mydomain.com
+	// or to "." to redirect www.mydomain.com to mydomain.com.
+	// Note that any host without the primary subdomain is redirected, including IPs and localhost.
+	PrimarySubdomain string `json:"primarySubdomain" yaml:"PrimarySubdomain"`
+}
+
+type rule struct {
+	code    int
+	pattern *regexp.Regexp
+	target  string
+}
+
+var errRule = errors.New("rewrite: invalid rule '%s': %s")
+
+func parseRule(line string) (*rule, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return nil, errRule.Format(line, "expected REDIRECT_CODE PATH_PATTERN TARGET_PATH")
+	}
+
+	code, err := strconv.Atoi(fields[0])
+	if err != nil || (code != 0 && (code < 300 || code > 399)) {
+		return nil, errRule.Format(line, "redirect code should be 0 or a 3xx status code")
+	}
+
+	pattern, err := regexp.Compile(fields[1])
+	if err != nil {
+		return nil, errRule.Format(line, err.Error())
+	}
+
+	return &rule{code: code, pattern: pattern, target: fields[2]}, nil
+}
+
+type engine struct {
+	rules            []*rule
+	forceHTTPS       bool
+	primarySubdomain string
+}
+
+// New returns a router wrapper which executes the "opts" rules
+// before the router, register it through `app.WrapRouter`.
+//
+// Usage:
+// rw, err := rewrite.New(rewrite.Options{
+//   ForceHTTPS: true,
+//   PrimarySubdomain: ".",
+//   RedirectMatch: []string{"301 /docs/v1/(.*) /docs/$1", "0 /old-api/(.*) /api/$1"},
+// })
+// app.WrapRouter(rw)
+func New(opts Options) (router.WrapperFunc, error) {
+	e := &engine{forceHTTPS: opts.ForceHTTPS}
+
+	switch sub := opts.PrimarySubdomain; sub {
+	case "", ".", "/":
+		e.primarySubdomain = sub
+	default:
+		e.primarySubdomain = strings.TrimSuffix(sub, ".") + "."
+	}
+
+	for _, line := range opts.RedirectMatch {
+		r, err := parseRule(line)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, r)
+	}
+
+	return e.Wrapper, nil
+}
+
+// Load decodes the `Options` from a YAML or JSON file
+// and returns a router wrapper, see `New` for more.
+//
+// It panics on errors, it is designed to be used on the initialization of the application.
+//
+// Usage:
+// app.WrapRouter(rewrite.Load("redirects.yml"))
+func Load(filename string) router.WrapperFunc {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(fmt.Sprintf("rewrite: %v", err))
+	}
+
+	var opts Options
+	// JSON is valid YAML.
+	if err = yaml.Unmarshal(b, &opts); err != nil {
+		panic(fmt.Sprintf("rewrite: %s: %v", filename, err))
+	}
+
+	w, err := New(opts)
+	if err != nil {
+		panic(err)
+	}
+
+	return w
+}
+
+func isSecure(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// Wrapper is the function that is being used to wrap the router,
+// it fires the redirects or rewrites the request's path before the router's execution.
+func (e *engine) Wrapper(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+	scheme, host := "http://", context.GetHost(r)
+	if isSecure(r) {
+		scheme = "https://"
+	}
+
+	redirect := false
+	if e.forceHTTPS && scheme == "http://" {
+		scheme, redirect = "https://", true
+	}
+
+	switch e.primarySubdomain {
+	case "":
+	case ".", "/":
+		if strings.HasPrefix(host, "www.") {
+			host, redirect = host[4:], true
+		}
+	default:
+		if !strings.HasPrefix(host, e.primarySubdomain) {
+			host, redirect = e.primarySubdomain+host, true
+		}
+	}
+
+	if redirect {
+		http.Redirect(w, r, scheme+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+		return
+	}
+
+	path := r.URL.Path
+	for _, rl := range e.rules {
+		if !rl.pattern.MatchString(path) {
+			continue
+		}
+
+		target := rl.pattern.ReplaceAllString(path, rl.target)
+
+		if rl.code == 0 {
+			// internal rewrite, the router will serve the new path
+			// and the target's query, if any, replaces the request's one.
+			u, err := url.Parse(target)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			r.URL.Path = u.Path
+			r.URL.RawPath = u.RawPath
+			if u.RawQuery != "" {
+				r.URL.RawQuery = u.RawQuery
+			}
+			r.RequestURI = r.URL.RequestURI()
+			break
+		}
+
+		if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+			target += "?" + r.URL.RawQuery
+		}
+
+		http.Redirect(w, r, target, rl.code)
+		return
+	}
+
+	router(w, r)
+}
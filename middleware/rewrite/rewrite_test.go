@@ -0,0 +1,109 @@
+package rewrite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	w, err := New(Options{
+		RedirectMatch: []string{
+			"301 ^/seo/(.*) /$1",
+			"0 ^/old-api/(.*)$ /api/$1",
+			"0 ^/users/([0-9]+)$ /user?id=$1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url string
+		// the status code and the location of a redirect
+		// or the path and the query which the router serves.
+		code                int
+		location            string
+		path, query, reqURI string
+	}{
+		{url: "/seo/about?lang=en", code: http.StatusMovedPermanently, location: "/about?lang=en"},
+		{url: "/old-api/users?page=2", code: http.StatusOK, path: "/api/users", query: "page=2", reqURI: "/api/users?page=2"},
+		// the target's query is not escaped into the path.
+		{url: "/users/42", code: http.StatusOK, path: "/user", query: "id=42", reqURI: "/user?id=42"},
+		{url: "/users/42?id=1", code: http.StatusOK, path: "/user", query: "id=42", reqURI: "/user?id=42"},
+		{url: "/api/users", code: http.StatusOK, path: "/api/users", reqURI: "/api/users"},
+	}
+
+	for _, tt := range tests {
+		var served *http.Request
+		rec := httptest.NewRecorder()
+		w(rec, httptest.NewRequest(http.MethodGet, tt.url, nil), func(_ http.ResponseWriter, r *http.Request) {
+			served = r
+		})
+
+		if rec.Code != tt.code {
+			t.Fatalf("%s: expected status code %d but got %d", tt.url, tt.code, rec.Code)
+		}
+
+		if tt.location != "" {
+			if location := rec.Header().Get("Location"); location != tt.location {
+				t.Fatalf("%s: expected a redirect to '%s' but got '%s'", tt.url, tt.location, location)
+			}
+			continue
+		}
+
+		if served == nil {
+			t.Fatalf("%s: expected the router to be executed", tt.url)
+		}
+
+		if served.URL.Path != tt.path || served.URL.RawQuery != tt.query || served.RequestURI != tt.reqURI {
+			t.Fatalf("%s: expected the router to serve '%s' with query '%s' ('%s') but got '%s' with query '%s' ('%s')",
+				tt.url, tt.path, tt.query, tt.reqURI, served.URL.Path, served.URL.RawQuery, served.RequestURI)
+		}
+	}
+}
+
+func TestRewriteHosts(t *testing.T) {
+	tests := []struct {
+		opts     Options
+		url      string
+		header   string
+		location string
+	}{
+		{Options{ForceHTTPS: true}, "http://example.com/path?q=1", "", "https://example.com/path?q=1"},
+		{Options{ForceHTTPS: true}, "http://example.com/path", "https", ""},
+		{Options{PrimarySubdomain: "."}, "http://www.example.com/path", "", "http://example.com/path"},
+		{Options{PrimarySubdomain: "."}, "http://example.com/path", "", ""},
+		{Options{PrimarySubdomain: "www"}, "http://example.com/path", "", "http://www.example.com/path"},
+		{Options{ForceHTTPS: true, PrimarySubdomain: "www"}, "http://example.com/", "", "https://www.example.com/"},
+	}
+
+	for i, tt := range tests {
+		w, err := New(tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if tt.header != "" {
+			req.Header.Set("X-Forwarded-Proto", tt.header)
+		}
+
+		rec := httptest.NewRecorder()
+		w(rec, req, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		if location := rec.Header().Get("Location"); location != tt.location {
+			t.Fatalf("[%d] %s: expected the location '%s' but got '%s'", i, tt.url, tt.location, location)
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	for _, line := range []string{"301 /a", "200 /a /b", "abc /a /b", "301 /(a /b"} {
+		if _, err := New(Options{RedirectMatch: []string{line}}); err == nil {
+			t.Fatalf("expected an error for the rule '%s'", line)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package routedebug
+
+import (
+	"github.com/kataras/iris/context"
+)
+
+// Config contains the options for the route debug middleware
+// can be optionally be passed to the `New`.
+type Config struct {
+	// Log logs the matched route, its parameters and its handlers chain
+	// to the application's logger on "Debug" level.
+	//
+	// Defaults to true.
+	Log bool
+	// Header if not empty, i.e "X-Route", the matched route's description
+	// is sent to the client through a response header
+	// and the handlers chain through the `Header + "-Handlers"` one.
+	// Enable it only on trusted environments, the headers expose
+	// the handlers' source files to every client.
+	//
+	// Defaults to empty, no headers are sent.
+	Header string
+	// LogFunc if not nil it is used instead of the application's logger.
+	LogFunc func(ctx context.Context, info Info)
+}
+
+// DefaultConfig returns the default configuration for the route debug middleware,
+// the logs are enabled and the headers are disabled.
+func DefaultConfig() Config {
+	return Config{
+		Log: true,
+	}
+}
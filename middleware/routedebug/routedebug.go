@@ -0,0 +1,89 @@
+// Package routedebug provides a development middleware which reports the
+// matched route, its parameters and the file:line of each handler that serves the request.
+package routedebug
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kataras/iris/context"
+)
+
+// Info describes how a request was matched.
+type Info struct {
+	// Route is the matched route's description, i.e "GET /user/{id:int}".
+	Route string
+	// Name is the matched route's name.
+	Name string
+	// Params are the matched route's dynamic path parameters.
+	Params map[string]string
+	// Handlers are the "name file:line" of each handler of the chain, in order of execution.
+	Handlers []string
+}
+
+// String returns a human-readable, multi-line form of the "info".
+func (info Info) String() string {
+	s := fmt.Sprintf("route: %s (%s)", info.Route, info.Name)
+	if len(info.Params) > 0 {
+		s += fmt.Sprintf("\nparams: %v", info.Params)
+	}
+	for i, h := range info.Handlers {
+		s += fmt.Sprintf("\n  %d. %s", i+1, h)
+	}
+	return s
+}
+
+// Get returns the route debug information of the current request.
+// It can be called from any handler, it does not depend on the middleware itself.
+func Get(ctx context.Context) Info {
+	info := Info{Params: make(map[string]string, ctx.Params().Len())}
+
+	if r := ctx.GetCurrentRoute(); r != nil {
+		info.Route = r.String()
+		info.Name = r.Name()
+	}
+
+	ctx.Params().Visit(func(key, value string) {
+		info.Params[key] = value
+	})
+
+	for _, h := range ctx.Handlers() {
+		file, line := context.HandlerFileLine(h)
+		info.Handlers = append(info.Handlers, fmt.Sprintf("%s %s:%d", context.HandlerName(h), file, line))
+	}
+
+	return info
+}
+
+// New returns a new route debug middleware,
+// it should be used only while developing because
+// it exposes internal details of the application.
+//
+// Register it with `app.UseGlobal` in order to run before any other handler.
+//
+// Receives an optional configuation.
+func New(cfg ...Config) context.Handler {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return func(ctx context.Context) {
+		info := Get(ctx)
+
+		if c.Header != "" {
+			ctx.Header(c.Header, info.Route)
+			ctx.Header(c.Header+"-Handlers", strings.Join(info.Handlers, ", "))
+		}
+
+		if c.Log {
+			if c.LogFunc != nil {
+				c.LogFunc(ctx, info)
+			} else {
+				ctx.Application().Logger().Debug(info.String())
+			}
+		}
+
+		ctx.Next()
+	}
+}
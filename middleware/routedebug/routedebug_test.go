@@ -0,0 +1,73 @@
+package routedebug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+func TestRouteDebug(t *testing.T) {
+	var logged []Info
+
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	app.UseGlobal(New(Config{
+		Log: true,
+		LogFunc: func(ctx context.Context, info Info) {
+			logged = append(logged, info)
+		},
+	}))
+	app.Get("/users/{id:int}", func(ctx context.Context) {
+		info := Get(ctx)
+		ctx.Writef("%s %s %s", info.Route, info.Name, info.Params["id"])
+	})
+
+	e := httptest.New(t, app)
+
+	// the headers are disabled by default.
+	resp := e.GET("/users/42").Expect().Status(httptest.StatusOK)
+	resp.Body().Equal("GET /users/{id:int} GET/users/{id:int} 42")
+	resp.Header("X-Route").Empty()
+	resp.Header("X-Route-Handlers").Empty()
+
+	if len(logged) != 1 {
+		t.Fatalf("expected one logged info but got %d", len(logged))
+	}
+	info := logged[0]
+	if info.Route != "GET /users/{id:int}" || info.Params["id"] != "42" {
+		t.Fatalf("unexpected logged info: %#v", info)
+	}
+
+	// the chain starts with the middleware itself and ends with the route's main handler.
+	if n := len(info.Handlers); n < 2 ||
+		!strings.Contains(info.Handlers[0], "routedebug.go:") || !strings.Contains(info.Handlers[n-1], "routedebug_test.go:") {
+		t.Fatalf("unexpected logged handlers: %v", info.Handlers)
+	}
+
+	if !strings.Contains(info.String(), "params: map[id:42]") {
+		t.Fatalf("expected the info's string to contain its params but got: %s", info.String())
+	}
+}
+
+func TestRouteDebugHeader(t *testing.T) {
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	app.UseGlobal(New(Config{Header: "X-Route"}))
+	app.Get("/", func(ctx context.Context) {})
+
+	e := httptest.New(t, app)
+
+	resp := e.GET("/").Expect().Status(httptest.StatusOK)
+	resp.Header("X-Route").Equal("GET /")
+	resp.Header("X-Route-Handlers").Contains("routedebug_test.go")
+}
+
+func TestDefaultConfig(t *testing.T) {
+	c := DefaultConfig()
+	if !c.Log || c.Header != "" {
+		t.Fatalf("expected the logs to be enabled and the headers to be disabled by default but got: %#v", c)
+	}
+}
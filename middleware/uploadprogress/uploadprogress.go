@@ -0,0 +1,185 @@
+// Package uploadprogress provides the progress of the uploads, keyed by an upload ID,
+// to be polled by the clients, i.e to render progress bars for large uploads.
+package uploadprogress
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/context"
+)
+
+// Progress is the state of an upload, it's the response body of the `Tracker#Handler`.
+type Progress struct {
+	// Received is the number of the received bytes.
+	Received int64 `json:"received"`
+	// Size is the size of the upload, it's negative when the client does not send the "Content-Length".
+	Size int64 `json:"size"`
+	// Done reports whether the upload's request is completed.
+	Done bool `json:"done"`
+}
+
+// upload is the tracked state of an upload, its fields are updated atomically
+// by the reads of the request's body.
+type upload struct {
+	received int64
+	size     int64
+	done     uint32
+	updated  int64 // unix nanoseconds.
+}
+
+func (u *upload) touch(now time.Time) {
+	atomic.StoreInt64(&u.updated, now.UnixNano())
+}
+
+func (u *upload) expired(now time.Time, expiration time.Duration) bool {
+	return now.UnixNano()-atomic.LoadInt64(&u.updated) > int64(expiration)
+}
+
+func (u *upload) progress() Progress {
+	return Progress{
+		Received: atomic.LoadInt64(&u.received),
+		Size:     atomic.LoadInt64(&u.size),
+		Done:     atomic.LoadUint32(&u.done) == 1,
+	}
+}
+
+// Config contains the options of the `Tracker`.
+type Config struct {
+	// HeaderKey is the request header of the upload ID.
+	//
+	// Defaults to "X-Upload-Id".
+	HeaderKey string
+	// URLParam is the url query parameter of the upload ID,
+	// it's checked when the header is missing, i.e for the html forms.
+	//
+	// Defaults to "upload_id".
+	URLParam string
+	// Expiration is the duration that a completed, or an idle, upload's progress
+	// is kept after its last update, so the clients can poll its final state.
+	//
+	// Defaults to 1 minute.
+	Expiration time.Duration
+}
+
+// Tracker keeps the progress of the uploads.
+type Tracker struct {
+	config Config
+
+	mu       sync.RWMutex
+	uploads  map[string]*upload
+	lastScan time.Time
+}
+
+// New returns a new upload progress tracker.
+// Register its `Middleware` to the upload routes and its `Handler` to the progress endpoint, i.e:
+//
+// tracker := uploadprogress.New()
+// app.Post("/upload", tracker.Middleware, uploadHandler)
+// app.Get("/upload/progress", tracker.Handler)
+//
+// The clients send the same, unique, upload ID to both of them,
+// with the "X-Upload-Id" header or the "upload_id" url parameter.
+// The ID is the only key of the progress, anyone who knows it can poll it
+// and a request with the same ID replaces it, so it should be unguessable,
+// i.e a random UUID of the client or one generated by the server when it renders the upload form.
+//
+// Receives an optional configuration.
+func New(cfg ...Config) *Tracker {
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if c.HeaderKey == "" {
+		c.HeaderKey = "X-Upload-Id"
+	}
+
+	if c.URLParam == "" {
+		c.URLParam = "upload_id"
+	}
+
+	if c.Expiration <= 0 {
+		c.Expiration = time.Minute
+	}
+
+	return &Tracker{config: c, uploads: make(map[string]*upload)}
+}
+
+func (t *Tracker) uploadID(ctx context.Context) string {
+	if id := ctx.GetHeader(t.config.HeaderKey); id != "" {
+		return id
+	}
+
+	return ctx.URLParam(t.config.URLParam)
+}
+
+// Middleware tracks the progress of the request body, of the requests with an upload ID,
+// while the next handlers read it.
+func (t *Tracker) Middleware(ctx context.Context) {
+	id := t.uploadID(ctx)
+	if id == "" {
+		ctx.Next()
+		return
+	}
+
+	u := t.add(id, ctx.Request().ContentLength)
+	ctx.TrackUploadProgress(func(received, size int64) {
+		atomic.StoreInt64(&u.received, received)
+		atomic.StoreInt64(&u.size, size)
+		u.touch(time.Now())
+	})
+
+	ctx.Next()
+
+	atomic.StoreUint32(&u.done, 1)
+	u.touch(time.Now())
+}
+
+// add starts the tracking of the upload of the "id", it replaces the previous one of that id, if any.
+func (t *Tracker) add(id string, size int64) *upload {
+	now := time.Now()
+	u := &upload{size: size, updated: now.UnixNano()}
+
+	t.mu.Lock()
+	t.uploads[id] = u
+	// remove the expired uploads, at most once per expiration.
+	if now.Sub(t.lastScan) > t.config.Expiration {
+		t.lastScan = now
+		for k, p := range t.uploads {
+			if p.expired(now, t.config.Expiration) {
+				delete(t.uploads, k)
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	return u
+}
+
+// Get returns the progress of the upload of the "id".
+func (t *Tracker) Get(id string) (Progress, bool) {
+	t.mu.RLock()
+	u, ok := t.uploads[id]
+	t.mu.RUnlock()
+
+	if !ok || u.expired(time.Now(), t.config.Expiration) {
+		return Progress{}, false
+	}
+
+	return u.progress(), true
+}
+
+// Handler is the progress endpoint, it writes the `Progress` of the request's upload ID as JSON
+// or it fires the 404 Not Found status code if that upload is not tracked.
+func (t *Tracker) Handler(ctx context.Context) {
+	p, ok := t.Get(t.uploadID(ctx))
+	if !ok {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(p)
+}
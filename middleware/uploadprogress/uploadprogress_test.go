@@ -0,0 +1,109 @@
+package uploadprogress
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/httptest"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := New()
+
+	app := iris.New()
+	app.Post("/upload", tracker.Middleware, func(ctx context.Context) {
+		id := ctx.GetHeader("X-Upload-Id")
+
+		// poll the progress while the body is read.
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					tracker.Get(id)
+				}
+			}
+		}()
+
+		var steps []string
+		buf := make([]byte, 10)
+		for {
+			_, err := io.ReadFull(ctx.Request().Body, buf)
+			p, _ := tracker.Get(id)
+			steps = append(steps, fmt.Sprintf("%d/%d", p.Received, p.Size))
+			if err != nil {
+				break
+			}
+		}
+
+		close(stop)
+		wg.Wait()
+		ctx.WriteString(strings.Join(steps, ","))
+	})
+	app.Post("/form", tracker.Middleware, func(ctx context.Context) {
+		ioutil.ReadAll(ctx.Request().Body)
+	})
+	app.Get("/progress", tracker.Handler)
+
+	e := httptest.New(t, app)
+
+	e.POST("/upload").WithHeader("X-Upload-Id", "a").WithText(strings.Repeat("a", 25)).Expect().
+		Status(iris.StatusOK).Body().Equal("10/25,20/25,25/25")
+
+	e.GET("/progress").WithHeader("X-Upload-Id", "a").Expect().Status(iris.StatusOK).
+		Header("Cache-Control").Equal("no-store")
+	e.GET("/progress").WithQuery("upload_id", "a").Expect().Status(iris.StatusOK).
+		JSON().Equal(Progress{Received: 25, Size: 25, Done: true})
+
+	// the url parameter, for the html forms.
+	e.POST("/form").WithQuery("upload_id", "b").WithText("form").Expect().Status(iris.StatusOK)
+	e.GET("/progress").WithQuery("upload_id", "b").Expect().Status(iris.StatusOK).
+		JSON().Equal(Progress{Received: 4, Size: 4, Done: true})
+
+	// the requests without an upload ID are not tracked.
+	e.POST("/form").WithText("form").Expect().Status(iris.StatusOK)
+	e.GET("/progress").Expect().Status(iris.StatusNotFound)
+	e.GET("/progress").WithQuery("upload_id", "unknown").Expect().Status(iris.StatusNotFound)
+}
+
+func TestTrackerExpiration(t *testing.T) {
+	tracker := New(Config{Expiration: 20 * time.Millisecond})
+
+	u := tracker.add("a", 10)
+	if p, ok := tracker.Get("a"); !ok || p.Size != 10 || p.Done {
+		t.Fatalf("expected the progress of the upload but got: %#v", p)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := tracker.Get("a"); ok {
+		t.Fatalf("expected the idle upload to be expired")
+	}
+
+	// the expired uploads are removed on the next upload.
+	tracker.add("b", 10)
+	tracker.mu.RLock()
+	_, ok := tracker.uploads["a"]
+	n := len(tracker.uploads)
+	tracker.mu.RUnlock()
+	if ok || n != 1 {
+		t.Fatalf("expected the expired upload to be removed but got %d uploads", n)
+	}
+
+	// the late updates of a removed upload do not track it again.
+	u.touch(time.Now())
+	if _, ok = tracker.Get("a"); ok {
+		t.Fatalf("expected the removed upload to not be tracked")
+	}
+}
@@ -87,6 +87,9 @@ type ControllerActivator struct {
 
 	// initialized on the first `Handle`.
 	injector *di.StructInjector
+
+	// see `Application#BindPartyParams`.
+	bindPartyParams bool
 }
 
 // NameOf returns the package name + the struct type's name,
@@ -247,7 +250,7 @@ func (c *ControllerActivator) parseMethods() {
 }
 
 func (c *ControllerActivator) parseMethod(m reflect.Method) {
-	httpMethod, httpPath, err := parseMethod(m, c.isReservedMethod)
+	httpMethod, httpPath, err := parseMethod(m, c.isReservedMethod, c.partyArgs(m))
 	if err != nil {
 		if err != errSkip {
 			c.addErr(fmt.Errorf("MVC: fail to parse the route path and HTTP method for '%s.%s': %v", c.fullName, m.Name, err))
@@ -259,6 +262,49 @@ func (c *ControllerActivator) parseMethod(m reflect.Method) {
 	c.Handle(httpMethod, httpPath, m.Name)
 }
 
+// partyParams returns the path parameters that are declared on the controller's party,
+// if the `Application#BindPartyParams` is true.
+func (c *ControllerActivator) partyParams() []macro.TemplateParam {
+	if !c.bindPartyParams {
+		return nil
+	}
+
+	relPath := c.router.GetRelPath()
+	// remove the subdomain part, if any.
+	if idx := strings.IndexByte(relPath, '/'); idx > 0 {
+		relPath = relPath[idx:]
+	}
+
+	if !strings.Contains(relPath, "{") {
+		return nil
+	}
+
+	tmpl, err := macro.Parse(relPath, c.router.Macros())
+	if err != nil {
+		return nil
+	}
+
+	return tmpl.Params
+}
+
+// partyArgs returns the number of the method's first input arguments
+// that are bound to the party's path parameters, if the method accepts all of them.
+func (c *ControllerActivator) partyArgs(m reflect.Method) int {
+	params := c.partyParams()
+	// the first input argument is the receiver.
+	if len(params) == 0 || m.Type.NumIn()-1 < len(params) {
+		return 0
+	}
+
+	for i, p := range params {
+		if !p.Type.Assignable(m.Type.In(i + 1).Kind()) {
+			return 0
+		}
+	}
+
+	return len(params)
+}
+
 // Handle registers a route based on a http method, the route's path
 // and a function name that belongs to the controller, it accepts
 // a forth, optionally, variadic parameter which is the before handlers.
@@ -295,6 +341,15 @@ func (c *ControllerActivator) Handle(method, path, funcName string, middleware .
 	// use the function's input except the receiver which is the
 	// end-dev's controller pointer.
 	pathParams := getPathParamsForInput(tmpl.Params, funcIn[1:]...)
+	// the parameters that are declared on the party, i.e app.Party("/orgs/{orgID:uint64}"),
+	// are bound as well, before the method's ones, if the function accepts all of them
+	// and the `Application#BindPartyParams` is true.
+	if partyParams := c.partyParams(); len(partyParams) > 0 {
+		allParams := append(partyParams, tmpl.Params...)
+		if values := getPathParamsForInput(allParams, funcIn[1:]...); len(values) == len(allParams) {
+			pathParams = values
+		}
+	}
 	// get the function's input arguments' bindings.
 	funcDependencies := c.dependencies.Clone()
 	funcDependencies.AddValues(pathParams...)
@@ -126,16 +126,20 @@ func genParamKey(argIdx int) string {
 type methodParser struct {
 	lexer *methodLexer
 	fn    reflect.Method
+	// the number of the function's first input arguments
+	// that are not part of the method's path, i.e party's parameters.
+	skipArgs int
 }
 
-func parseMethod(fn reflect.Method, skipper func(string) bool) (method, path string, err error) {
+func parseMethod(fn reflect.Method, skipper func(string) bool, skipArgs int) (method, path string, err error) {
 	if skipper(fn.Name) {
 		return "", "", errSkip
 	}
 
 	p := &methodParser{
-		fn:    fn,
-		lexer: newMethodLexer(fn.Name),
+		fn:       fn,
+		lexer:    newMethodLexer(fn.Name),
+		skipArgs: skipArgs,
 	}
 	return p.parse()
 }
@@ -150,7 +154,7 @@ var errSkip = errors.New("skip")
 var allMethods = append(router.AllMethods[0:], []string{"ALL", "ANY"}...)
 
 func (p *methodParser) parse() (method, path string, err error) {
-	funcArgPos := 0
+	funcArgPos := p.skipArgs
 	path = "/"
 	// take the first word and check for the method.
 	w := p.lexer.next()
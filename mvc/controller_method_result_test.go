@@ -1,11 +1,13 @@
 package mvc_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/kataras/iris"
 	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/msgpack"
 	"github.com/kataras/iris/httptest"
 
 	. "github.com/kataras/iris/mvc"
@@ -160,6 +162,10 @@ func (c *testControllerMethodResultTypes) GetCustomStructWithContentType() (test
 	return testCustomStruct{"Iris", 2}, "text/xml"
 }
 
+func (c *testControllerMethodResultTypes) GetCustomStructWithMsgpack() (testCustomStruct, string) {
+	return testCustomStruct{"Iris", 2}, "application/msgpack"
+}
+
 func (c *testControllerMethodResultTypes) GetCustomStructWithError() (s testCustomStruct, err error) {
 	s = testCustomStruct{"Iris", 2}
 	if c.Ctx.URLParamExists("err") {
@@ -214,6 +220,14 @@ func TestControllerMethodResultTypes(t *testing.T) {
 		JSON().Equal(expectedResultFromCustomStruct)
 	e.GET("/custom/struct/with/content/type").Expect().Status(iris.StatusOK).
 		ContentType("text/xml", "utf-8")
+
+	var msgpackResult testCustomStruct
+	msgpackBody := e.GET("/custom/struct/with/msgpack").Expect().Status(iris.StatusOK).
+		ContentType("application/msgpack").Body().Raw()
+	if err := msgpack.Unmarshal([]byte(msgpackBody), &msgpackResult); err != nil || msgpackResult.Name != "Iris" {
+		t.Fatalf("expected a MessagePack body of the custom struct but got %#v, %v", msgpackResult, err)
+	}
+
 	e.GET("/custom/struct/with/error").Expect().Status(iris.StatusOK).
 		JSON().Equal(expectedResultFromCustomStruct)
 	e.GET("/custom/struct/with/error").WithQuery("err", true).Expect().
@@ -269,3 +283,34 @@ func TestControllerViewResultRespectCtxViewData(t *testing.T) {
 
 	e.GET("/").Expect().Status(iris.StatusInternalServerError)
 }
+
+type testControllerJSONCodec struct{}
+
+func (c *testControllerJSONCodec) Get() testCustomStruct {
+	return testCustomStruct{Name: "iris", Age: 2}
+}
+
+func (c *testControllerJSONCodec) GetResponse() Result {
+	return Response{Object: testCustomStruct{Name: "iris", Age: 2}}
+}
+
+func TestControllerMethodResultJSONCodec(t *testing.T) {
+	var marshals int
+	app := iris.New()
+	app.Configure(iris.WithJSONCodec(context.JSONCodec{
+		Marshal: func(v interface{}) ([]byte, error) {
+			marshals++
+			return json.Marshal(v)
+		},
+		Unmarshal: json.Unmarshal,
+	}))
+	New(app).Handle(new(testControllerJSONCodec))
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).JSON().Equal(testCustomStruct{Name: "iris", Age: 2})
+	e.GET("/response").Expect().Status(iris.StatusOK).JSON().Equal(testCustomStruct{Name: "iris", Age: 2})
+
+	if marshals != 2 {
+		t.Fatalf("expected the results to be encoded by the custom JSON codec but got %d calls", marshals)
+	}
+}
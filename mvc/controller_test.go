@@ -2,12 +2,14 @@
 package mvc_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/kataras/iris"
 	"github.com/kataras/iris/context"
 	"github.com/kataras/iris/core/router"
 	"github.com/kataras/iris/httptest"
+	"github.com/kataras/iris/sessions"
 
 	. "github.com/kataras/iris/mvc"
 )
@@ -493,3 +495,103 @@ func TestControllerNotCreateNewDueManuallySettingAllFields(t *testing.T) {
 	e.GET("/").Expect().Status(iris.StatusOK).
 		Body().Equal("my title")
 }
+
+type testControllerPartyParams struct{}
+
+func (c *testControllerPartyParams) Get(orgID uint64) string {
+	return fmt.Sprintf("org %d", orgID)
+}
+
+func (c *testControllerPartyParams) GetBy(orgID uint64, repoID int64) string {
+	return fmt.Sprintf("org %d repo %d", orgID, repoID)
+}
+
+func (c *testControllerPartyParams) GetMembersBy(memberID int64) string {
+	return fmt.Sprintf("member %d", memberID)
+}
+
+func TestControllerPartyParams(t *testing.T) {
+	app := iris.New()
+	m := New(app.Party("/orgs/{orgID:uint64 min(1)}"))
+	m.BindPartyParams = true
+	m.Handle(new(testControllerPartyParams))
+
+	e := httptest.New(t, app)
+	e.GET("/orgs/18446744073709551615").Expect().Status(iris.StatusOK).
+		Body().Equal("org 18446744073709551615")
+	e.GET("/orgs/42/7").Expect().Status(iris.StatusOK).
+		Body().Equal("org 42 repo 7")
+	// the method does not accept the party's parameter, only the method's one is bound.
+	e.GET("/orgs/42/members/3").Expect().Status(iris.StatusOK).
+		Body().Equal("member 3")
+	// the validation of the party's parameter.
+	e.GET("/orgs/0").Expect().Status(iris.StatusNotFound)
+	e.GET("/orgs/-1/7").Expect().Status(iris.StatusNotFound)
+}
+
+type testControllerSessionStore struct {
+	Session sessions.Store
+}
+
+func (c *testControllerSessionStore) Get() string {
+	visits, _ := c.Session.Get("visits").(int)
+	c.Session.Set("visits", visits+1)
+	return fmt.Sprintf("%d", visits+1)
+}
+
+func (c *testControllerSessionStore) GetLogout() {
+	c.Session.Destroy()
+}
+
+type testFakeSessionStore map[string]interface{}
+
+func (s testFakeSessionStore) ID() string                        { return "fake" }
+func (s testFakeSessionStore) Get(key string) interface{}        { return s[key] }
+func (s testFakeSessionStore) Set(key string, value interface{}) { s[key] = value }
+func (s testFakeSessionStore) Delete(key string) bool            { _, ok := s[key]; delete(s, key); return ok }
+func (s testFakeSessionStore) Destroy()                          {}
+
+func TestControllerSessionStore(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "sid"})
+	New(app.Party("/")).Register(sess.Store).Handle(new(testControllerSessionStore))
+
+	// a fake store, i.e in the controller's tests.
+	fake := testFakeSessionStore{"visits": 41}
+	New(app.Party("/fake")).Register(func(ctx context.Context) sessions.Store { return fake }).
+		Handle(new(testControllerSessionStore))
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("1")
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("2")
+	// the `Destroy` of the store removes the cookie too, a new session is started.
+	e.GET("/logout").Expect().Status(iris.StatusOK)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal("1")
+
+	e.GET("/fake").Expect().Status(iris.StatusOK).Body().Equal("42")
+}
+
+type testControllerPartyPosts struct{}
+
+func (c *testControllerPartyPosts) GetBy(postID int) string {
+	return fmt.Sprintf("post %d", postID)
+}
+
+func (c *testControllerPartyPosts) GetCommentsBy(userID, commentID int) string {
+	return fmt.Sprintf("user %d comment %d", userID, commentID)
+}
+
+func TestControllerPartyParamsNotBound(t *testing.T) {
+	app := iris.New()
+	// the party's parameters are not bound by default,
+	// the methods' arguments are bound to their own path.
+	New(app.Party("/users/{userID:int}")).Handle(new(testControllerPartyPosts))
+
+	e := httptest.New(t, app)
+	e.GET("/users/1/42").Expect().Status(iris.StatusOK).
+		Body().Equal("post 42")
+	e.GET("/users/1").Expect().Status(iris.StatusNotFound)
+	e.GET("/users/1/by").Expect().Status(iris.StatusNotFound)
+	e.GET("/users/1/comments/2/3").Expect().Status(iris.StatusOK).
+		Body().Equal("user 2 comment 3")
+}
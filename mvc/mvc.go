@@ -37,6 +37,12 @@ var (
 type Application struct {
 	Dependencies di.Values
 	Router       router.Party
+	// BindPartyParams if true, the path parameters of the Router, i.e app.Party("/orgs/{orgID:uint64}"),
+	// are bound to the first input arguments of the controllers' methods that accept all of them,
+	// before the methods' own path parameters, i.e `GetBy(orgID uint64, repoID int64)` serves the "/orgs/{orgID}/{repoID}".
+	//
+	// Defaults to false, the input arguments are bound to the methods' own path parameters only.
+	BindPartyParams bool
 }
 
 func newApp(subRouter router.Party, values di.Values) *Application {
@@ -156,6 +162,7 @@ func (app *Application) Register(values ...interface{}) *Application {
 func (app *Application) Handle(controller interface{}) *Application {
 	// initialize the controller's activator, nothing too magical so far.
 	c := newControllerActivator(app.Router, controller, app.Dependencies)
+	c.bindPartyParams = app.BindPartyParams
 
 	// check the controller's "BeforeActivation" or/and "AfterActivation" method(s) between the `activate`
 	// call, which is simply parses the controller's methods, end-dev can register custom controller's methods
@@ -181,7 +188,9 @@ func (app *Application) Handle(controller interface{}) *Application {
 //
 // Example: `.Clone(app.Party("/path")).Handle(new(TodoSubController))`.
 func (app *Application) Clone(party router.Party) *Application {
-	return newApp(party, app.Dependencies.Clone())
+	clone := newApp(party, app.Dependencies.Clone())
+	clone.BindPartyParams = app.BindPartyParams
+	return clone
 }
 
 // Party returns a new child mvc Application based on the current path + "relativePath".
@@ -47,6 +47,11 @@ func makeFuncParamGetter(paramType ast.ParamType, paramName string) reflect.Valu
 			v, _ := ctx.Params().GetInt64(paramName)
 			return v
 		}
+	case ast.ParamTypeUint64:
+		fn = func(ctx context.Context) uint64 {
+			v, _ := ctx.Params().GetUint64(paramName)
+			return v
+		}
 	case ast.ParamTypeBoolean:
 		fn = func(ctx context.Context) bool {
 			v, _ := ctx.Params().GetBool(paramName)
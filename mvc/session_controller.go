@@ -12,7 +12,8 @@ var defaultSessionManager = sessions.New(sessions.Config{})
 // direct access to the current client's session via its `Session` field.
 //
 // SessionController is deprecated please use the new dependency injection's methods instead,
-// i.e `mvcApp.Register(sessions.New(sessions.Config{}).Start)`.
+// i.e `mvcApp.Register(sessions.New(sessions.Config{}).Start)`
+// or `mvcApp.Register(sess.Store)` for a `sessions.Store` field, which can be replaced by a fake one on tests.
 // It's more controlled by you,
 // also *sessions.Session type can now `Destroy` itself without the need of the manager, embrace it.
 type SessionController struct {
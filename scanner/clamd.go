@@ -0,0 +1,134 @@
+// Package scanner provides upload scanners, see `iris#WithUploadScanner`.
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+)
+
+var (
+	// ErrInfected is returned by the `Clamd#Scan` when the daemon found a virus,
+	// the virus signature is part of the message.
+	ErrInfected = errors.New("clamd: infected: %s")
+	// ErrClamd is returned by the `Clamd#Scan` when the daemon responded with an error.
+	ErrClamd = errors.New("clamd: %s")
+)
+
+// Clamd is a `context.Scanner` which streams the contents
+// to a ClamAV daemon through its INSTREAM command.
+type Clamd struct {
+	// Network is the network of the clamd, "tcp" or "unix".
+	//
+	// Defaults to "tcp".
+	Network string
+	// Address is the address of the clamd,
+	// i.e "localhost:3310" or "/var/run/clamav/clamd.ctl".
+	//
+	// Defaults to "localhost:3310".
+	Address string
+	// Timeout is the maximum duration of a scan, including the dial.
+	//
+	// Defaults to 1 minute.
+	Timeout time.Duration
+	// ChunkSize is the size of each chunk which is sent to the clamd,
+	// it should be lower than the clamd's "StreamMaxLength".
+	//
+	// Defaults to 32KB.
+	ChunkSize int
+}
+
+var _ context.Scanner = (*Clamd)(nil)
+
+// dial connects to the clamd, it's replaced by the tests.
+var dial = net.DialTimeout
+
+// NewClamd returns a new clamd scanner based on the "network" and "address".
+//
+// Usage:
+// app.Configure(iris.WithUploadScanner(scanner.NewClamd("tcp", "localhost:3310")))
+func NewClamd(network, address string) *Clamd {
+	return &Clamd{
+		Network:   network,
+		Address:   address,
+		Timeout:   time.Minute,
+		ChunkSize: 32 << 10,
+	}
+}
+
+// Scan streams the "r" to the clamd and returns an `ErrInfected` error
+// if a virus was found.
+func (c *Clamd) Scan(r io.Reader) error {
+	network, address, timeout, chunkSize := c.Network, c.Address, c.Timeout, c.ChunkSize
+	if network == "" {
+		network = "tcp"
+	}
+	if address == "" {
+		address = "localhost:3310"
+	}
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	if chunkSize <= 0 {
+		chunkSize = 32 << 10
+	}
+
+	conn, err := dial(network, address, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// the "z" prefix means that the command and its response are null-terminated.
+	if _, err = conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4+chunkSize)
+	for {
+		n, rerr := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err = conn.Write(buf[:4+n]); err != nil {
+				return err
+			}
+		}
+
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+
+	// zero-length chunk ends the stream.
+	if _, err = conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	// stream: OK
+	// stream: Eicar-Test-Signature FOUND
+	// INSTREAM size limit exceeded. ERROR
+	reply = strings.TrimPrefix(strings.TrimSuffix(reply, "\x00"), "stream: ")
+	switch {
+	case reply == "OK":
+		return nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return ErrInfected.Format(strings.TrimSuffix(reply, " FOUND"))
+	default:
+		return ErrClamd.Format(reply)
+	}
+}
@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// fakeClamd serves a single INSTREAM command over a `net.Pipe`,
+// it responds with the "reply" and sends the received stream to the returned channel.
+func fakeClamd(t *testing.T, reply string) <-chan []byte {
+	received := make(chan []byte, 1)
+
+	dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			r := bufio.NewReader(server)
+
+			cmd, err := r.ReadString(0)
+			if err != nil || cmd != "zINSTREAM\x00" {
+				t.Errorf("expected the INSTREAM command but got: %q (%v)", cmd, err)
+				return
+			}
+
+			var stream bytes.Buffer
+			for {
+				var size uint32
+				if err = binary.Read(r, binary.BigEndian, &size); err != nil {
+					t.Error(err)
+					return
+				}
+				if size == 0 {
+					break
+				}
+				if _, err = io.CopyN(&stream, r, int64(size)); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+
+			received <- stream.Bytes()
+			server.Write([]byte(reply + "\x00"))
+		}()
+		return client, nil
+	}
+
+	return received
+}
+
+func TestClamdScan(t *testing.T) {
+	defer func() { dial = net.DialTimeout }()
+
+	contents := strings.Repeat("contents", 10)
+
+	tests := []struct {
+		reply    string
+		expected errors.Error
+		message  string // empty for a clean file.
+	}{
+		{"stream: OK", errors.Error{}, ""},
+		{"stream: Eicar-Test-Signature FOUND", ErrInfected, "clamd: infected: Eicar-Test-Signature"},
+		{"INSTREAM size limit exceeded. ERROR", ErrClamd, "clamd: INSTREAM size limit exceeded. ERROR"},
+	}
+
+	for _, tt := range tests {
+		received := fakeClamd(t, tt.reply)
+
+		c := NewClamd("tcp", "clamd:3310")
+		c.ChunkSize = 7 // the contents are sent in more than one chunk.
+		err := c.Scan(strings.NewReader(contents))
+
+		if got := string(<-received); got != contents {
+			t.Fatalf("[%s] expected the clamd to receive '%s' but got '%s'", tt.reply, contents, got)
+		}
+
+		if tt.message == "" {
+			if err != nil {
+				t.Fatalf("[%s] expected no error but got: %v", tt.reply, err)
+			}
+			continue
+		}
+
+		if e, ok := err.(errors.Error); !ok || !e.Equal(tt.expected) || err.Error() != tt.message {
+			t.Fatalf("[%s] expected error '%s' but got: %v", tt.reply, tt.message, err)
+		}
+	}
+}
+
+func TestClamdScanDialError(t *testing.T) {
+	defer func() { dial = net.DialTimeout }()
+
+	expected := io.ErrClosedPipe
+	dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		if network != "tcp" || address != "localhost:3310" || timeout != time.Minute {
+			t.Fatalf("expected the default network, address and timeout but got: %s %s %s", network, address, timeout)
+		}
+		return nil, expected
+	}
+
+	if err := new(Clamd).Scan(strings.NewReader("contents")); err != expected {
+		t.Fatalf("expected the dial error but got: %v", err)
+	}
+}
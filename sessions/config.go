@@ -1,9 +1,12 @@
 package sessions
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/satori/go.uuid"
+
+	"github.com/kataras/iris/context"
 )
 
 const (
@@ -56,6 +59,42 @@ type (
 		// Defaults to false.
 		CookieSecureTLS bool
 
+		// CookieDomain is the "Domain" attribute of the session's cookie,
+		// i.e ".example.com" to share the session between the subdomains of the "example.com".
+		//
+		// Defaults to empty, the domain of the request including its first-level subdomains,
+		// unless the "DisableSubdomainPersistence" is true.
+		CookieDomain string
+		// CookiePath is the "Path" attribute of the session's cookie.
+		//
+		// Defaults to "/".
+		CookiePath string
+		// CookieSameSite is the "SameSite" attribute of the session's cookie,
+		// i.e the `context.SameSiteNone` for a session of an application which is embedded to another site,
+		// it sets the "Secure" attribute too.
+		//
+		// Defaults to `context.SameSiteDefault`, the attribute is not sent.
+		CookieSameSite context.SameSite
+		// CookieSecure sets the "Secure" attribute of the session's cookie on all of the requests,
+		// i.e behind a proxy which terminates the TLS, see "CookieSecureTLS" too.
+		//
+		// Defaults to false.
+		CookieSecure bool
+		// CookiePartitioned sets the "Partitioned" (CHIPS) and the "Secure" attributes of the session's cookie,
+		// so an embedded application keeps a different session for each one of the top-level sites.
+		//
+		// Defaults to false.
+		CookiePartitioned bool
+
+		// CookieOptions set the rest of the session cookie's attributes,
+		// i.e `context.CookieSameSite(context.SameSiteLax)`, `context.CookiePartitioned`
+		// and `context.CookieHost`, they are applied after the defaults (path, domain and expiration)
+		// and the cookie's fields of the configuration.
+		// A Party can override them, see `Sessions#Handler`.
+		//
+		// Defaults to nil.
+		CookieOptions []context.CookieOption
+
 		// AllowReclaim will allow to
 		// Destroy and Start a session in the same request handler.
 		// All it does is that it removes the cookie for both `Request` and `ResponseWriter` while `Destroy`
@@ -103,15 +142,126 @@ type (
 		// Defaults to infinitive/unlimited life duration(0).
 		Expires time.Duration
 
+		// SlidingExpiration resets the "Expires" of a session on each of its requests,
+		// so it expires after "Expires" of inactivity instead of "Expires" after its creation.
+		//
+		// Defaults to false.
+		SlidingExpiration bool
+		// AbsoluteExpiration is the maximum lifetime of a session since its creation,
+		// even if it's extended by the "SlidingExpiration" or the `ShiftExpiration`,
+		// then it's destroyed and the client starts a new one.
+		//
+		// Defaults to 0, no limit.
+		AbsoluteExpiration time.Duration
+		// IdleTimeout destroys a session when there were no requests of it for this duration,
+		// then the client starts a new one.
+		//
+		// Defaults to 0, no idle timeout.
+		IdleTimeout time.Duration
+
+		// LockTimeout is the maximum duration of the wait for the lock of a session, see `Session#Lock`,
+		// then the `ErrLockTimeout` is returned.
+		//
+		// Defaults to 10 seconds.
+		LockTimeout time.Duration
+		// LockTTL is the expiration of the lock of a session in a database which implements the `Locker`,
+		// so the lock is released if the instance which acquired it fails to release it.
+		// It should be longer than the requests which lock the session.
+		//
+		// Defaults to 30 seconds.
+		LockTTL time.Duration
+
+		// GCInterval is the interval of the `Sessions#Sweep` of the expired server-side sessions.
+		// When it's positive the sessions do not start a timer each, they are removed by the periodic sweep instead,
+		// which saves memory on a large number of sessions, but a session is removed up to "GCInterval" after its expiration.
+		//
+		// Defaults to 0, each session is removed by its own timer.
+		GCInterval time.Duration
+
+		// RotateIDOnAuthentication rotates the session's id, see `Session#RotateID`,
+		// when the user is authenticated by the `Session#Authenticate`,
+		// it protects from session fixation attacks.
+		//
+		// Defaults to false.
+		RotateIDOnAuthentication bool
+
 		// SessionIDGenerator should returns a random session id.
 		// By default we will use a uuid impl package to generate
-		// that, but developers can change that with simple assignment.
+		// that, but developers can change that with simple assignment,
+		// i.e to a ULID generator or to the `NewSessionIDGenerator`.
+		// Its ids should be unique, unpredictable and safe for a cookie's value,
+		// they are not checked by the `New`, prefer the "SessionIDLength" and the "SessionIDAlphabet"
+		// for the random ids of a custom length or alphabet.
 		SessionIDGenerator func() string
+		// SessionIDLength is the number of the characters of the random session ids,
+		// when it or the "SessionIDAlphabet" is set the ids are generated by the `NewSessionIDGenerator`
+		// instead of the "SessionIDGenerator".
+		//
+		// Defaults to 32, if the "SessionIDAlphabet" is set.
+		SessionIDLength int
+		// SessionIDAlphabet is the characters of the random session ids, see "SessionIDLength",
+		// i.e the `SessionIDAlphabetBase62` or the `SessionIDAlphabetHex`.
+		//
+		// Defaults to the `SessionIDAlphabetURL`, if the "SessionIDLength" is set.
+		SessionIDAlphabet string
+		// SessionIDMinEntropy is the minimum entropy, in bits, of the ids of the "SessionIDLength"
+		// and the "SessionIDAlphabet", the `New` panics if it's not reached, see `SessionIDEntropy`.
+		//
+		// Defaults to 64.
+		SessionIDMinEntropy int
 
 		// DisableSubdomainPersistence set it to true in order dissallow your subdomains to have access to the session cookie
 		//
 		// Defaults to false.
 		DisableSubdomainPersistence bool
+
+		// Stateless stores the session's values and flash messages to the session's cookie,
+		// instead of the server's memory or a database, encrypted and authenticated (AES-GCM)
+		// by the "StatelessKeys", so any instance of the application can read them.
+		// The `Session` API stays the same, but its values should be set before the response body is written,
+		// the cookie is sent with the headers.
+		// The "Encode", "Decode", "Encoding" and the `UseDatabase` are not used
+		// and the `DestroyByID` and `DestroyAll` can not reach the stateless sessions.
+		//
+		// Defaults to false.
+		Stateless bool
+		// StatelessKeys the keys of the stateless sessions' cookies,
+		// 16, 24 or 32 bytes long for the AES-128, AES-192 or AES-256.
+		// The first one encrypts the cookies and all of them decrypt them, so a new key can be
+		// prepended and the old ones can be removed after the "Expires",
+		// the cookies of the old keys are re-encrypted by the first one on their next request.
+		//
+		// Required if "Stateless" is true.
+		StatelessKeys [][]byte
+		// StatelessMaxSize the maximum size of the stateless session's cookie value,
+		// the browsers keep cookies up to 4096 bytes, including their name and attributes.
+		// A value which does not fit is not stored and the error is logged.
+		//
+		// Defaults to 4000.
+		StatelessMaxSize int
+
+		// JWT reads the session's id from the subject ("sub") of a JSON Web Token (HS256),
+		// which is sent by the "Authorization: Bearer" header or by the session's cookie,
+		// instead of the plain id of the cookie.
+		// The token is verified on each request, including its "exp" and "nbf",
+		// and a new session issues a new token, it's sent by the cookie and it's returned by the `Sessions#Token`.
+		// The `Session` API stays the same, its values are stored to the memory or to the database, see `UseDatabase`,
+		// under the token's subject, so the tokens can be issued and verified by other services too.
+		// Note that a token of a destroyed session is still valid until its expiration, but its values are removed.
+		// The "Encode", "Decode" and "Encoding" are not used.
+		//
+		// Defaults to false.
+		JWT bool
+		// JWTKeys the HMAC-SHA256 keys of the tokens, the first one signs them and all of them verify them,
+		// so a new key can be prepended and the old ones can be removed after the "Expires".
+		//
+		// Required if "JWT" is true.
+		JWTKeys [][]byte
+		// JWTIssuer is the issuer ("iss") of the new tokens, if not empty,
+		// the tokens of a different issuer are not accepted.
+		//
+		// Defaults to empty.
+		JWTIssuer string
 	}
 )
 
@@ -122,6 +272,17 @@ func (c Config) Validate() Config {
 		c.Cookie = DefaultCookieName
 	}
 
+	if c.SessionIDMinEntropy <= 0 {
+		c.SessionIDMinEntropy = DefaultSessionIDMinEntropy
+	}
+
+	if c.SessionIDLength > 0 || c.SessionIDAlphabet != "" {
+		// an invalid one is reported by the `New`.
+		if generator, err := NewSessionIDGenerator(c.sessionIDLength(), c.sessionIDAlphabet(), c.SessionIDMinEntropy); err == nil {
+			c.SessionIDGenerator = generator
+		}
+	}
+
 	if c.SessionIDGenerator == nil {
 		c.SessionIDGenerator = func() string {
 			id, _ := uuid.NewV4()
@@ -129,6 +290,18 @@ func (c Config) Validate() Config {
 		}
 	}
 
+	if c.LockTimeout <= 0 {
+		c.LockTimeout = DefaultLockTimeout
+	}
+
+	if c.LockTTL <= 0 {
+		c.LockTTL = DefaultLockTTL
+	}
+
+	if c.StatelessMaxSize <= 0 {
+		c.StatelessMaxSize = DefaultStatelessMaxSize
+	}
+
 	if c.Encoding != nil {
 		c.Encode = c.Encoding.Encode
 		c.Decode = c.Encoding.Decode
@@ -136,3 +309,50 @@ func (c Config) Validate() Config {
 
 	return c
 }
+
+// cookieOptionsContextKey is the prefix of the context's key of the cookie options of a Party, see `Sessions#Handler`.
+const cookieOptionsContextKey = "iris.session.cookie."
+
+// cookieOptions returns the options of the session's cookie: its attributes of the configuration,
+// the "CookieOptions" and the ones of the request's Party, see `Sessions#Handler`.
+func (c Config) cookieOptions(ctx context.Context) []context.CookieOption {
+	var options []context.CookieOption
+	if c.CookieDomain != "" {
+		options = append(options, context.CookieDomain(c.CookieDomain))
+	}
+
+	if c.CookiePath != "" {
+		options = append(options, context.CookiePath(c.CookiePath))
+	}
+
+	if c.CookieSameSite != context.SameSiteDefault {
+		options = append(options, context.CookieSameSite(c.CookieSameSite))
+	}
+
+	if c.CookieSecure {
+		options = append(options, context.CookieSecure)
+	}
+
+	if c.CookiePartitioned {
+		options = append(options, context.CookiePartitioned)
+	}
+
+	options = append(options, c.CookieOptions...)
+
+	if partyOptions, ok := ctx.Values().Get(cookieOptionsContextKey + c.Cookie).([]context.CookieOption); ok {
+		options = append(options, partyOptions...)
+	}
+
+	return options
+}
+
+// cookieName returns the name of the session cookie
+// as it's modified by the cookie options, i.e the "__Host-" prefix.
+func (c Config) cookieName(ctx context.Context) string {
+	options := c.cookieOptions(ctx)
+	if len(options) == 0 {
+		return c.Cookie
+	}
+
+	return context.NewCookie(&http.Cookie{Name: c.Cookie}, options...).Name
+}
@@ -29,18 +29,53 @@ func GetCookie(ctx context.Context, name string) string {
 	return c.Value
 }
 
-// AddCookie adds a cookie
-func AddCookie(ctx context.Context, cookie *http.Cookie, reclaim bool) {
+// AddCookie adds a cookie, the optional "options" set the rest of its attributes.
+func AddCookie(ctx context.Context, cookie *http.Cookie, reclaim bool, options ...context.CookieOption) {
+	ctx.SetCookie(cookie, options...)
 	if reclaim {
 		ctx.Request().AddCookie(cookie)
 	}
-	ctx.SetCookie(cookie)
+}
+
+// removeResponseCookie removes the cookie, of the "name", which is set to the response,
+// so the next one replaces it, the response should be sent with a single session cookie.
+func removeResponseCookie(ctx context.Context, name string) {
+	prefix := name + "="
+	header := ctx.ResponseWriter().Header()
+	setCookies := header["Set-Cookie"][:0]
+	for _, v := range header["Set-Cookie"] {
+		if !strings.HasPrefix(v, prefix) {
+			setCookies = append(setCookies, v)
+		}
+	}
+	header["Set-Cookie"] = setCookies
+}
+
+// replaceRequestCookie replaces, or adds, the value of the request's cookie of the "name",
+// so the next reads of the request see the new value.
+func replaceRequestCookie(ctx context.Context, name, value string) {
+	r := ctx.Request()
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+
+	found := false
+	for _, c := range cookies {
+		if c.Name == name {
+			c.Value = value
+			found = true
+		}
+		r.AddCookie(c)
+	}
+
+	if !found {
+		r.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
 }
 
 // RemoveCookie deletes a cookie by it's name/key
 // If "purge" is true then it removes the, temp, cookie from the request as well.
 func RemoveCookie(ctx context.Context, config Config) {
-	cookie, err := ctx.Request().Cookie(config.Cookie)
+	cookie, err := ctx.Request().Cookie(config.cookieName(ctx))
 	if err != nil {
 		return
 	}
@@ -52,7 +87,9 @@ func RemoveCookie(ctx context.Context, config Config) {
 	cookie.Path = "/"
 	cookie.Domain = formatCookieDomain(ctx, config.DisableSubdomainPersistence)
 
-	AddCookie(ctx, cookie, config.AllowReclaim)
+	// the options may modify the expiration, delete it after them.
+	options := append(config.cookieOptions(ctx), context.CookieMaxAge(-1))
+	AddCookie(ctx, cookie, config.AllowReclaim, options...)
 
 	if config.AllowReclaim {
 		// delete request's cookie also, which is temporary available.
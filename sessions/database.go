@@ -38,24 +38,82 @@ type Database interface {
 	Release(sid string)
 }
 
+// ExpirationUpdater can be implemented by a `Database`,
+// its `OnUpdateExpiration` is called when the lifetime of a session is updated,
+// i.e by the `Sessions#ShiftExpiration`, so the database can update the lifetime of the session's values.
+type ExpirationUpdater interface {
+	OnUpdateExpiration(sid string, newExpires time.Duration)
+}
+
+// IDRotator can be implemented by a `Database` which can move the values of a session
+// to a new session id atomically, it's used by the `Session#RotateID`,
+// otherwise the values are copied to the new id and the old one is released.
+type IDRotator interface {
+	RotateID(oldSid, newSid string) error
+}
+
+// Sweeper can be implemented by a `Database` which removes its expired sessions on demand,
+// its `Sweep` is called by the `Sessions#Sweep`.
+type Sweeper interface {
+	Sweep() error
+}
+
+// ErrorCounter can be implemented by a `Database` which counts its errors,
+// i.e of its connection, they are reported by the `Sessions#Stats`.
+type ErrorCounter interface {
+	Errors() uint64
+}
+
+// Ranger can be implemented by a `Database` which can list its sessions,
+// it's required by the source database of the `Migrate`.
+type Ranger interface {
+	// Range calls the "cb" for each one of the sessions with its id and its lifetime,
+	// the lifetime is zero for a session which does not expire, it stops when the "cb" returns false.
+	Range(cb func(sid string, lifetime LifeTime) bool) error
+}
+
 type mem struct {
 	values map[string]*memstore.Store
-	mu     sync.RWMutex
+	// the expiration of the sessions, kept for the `Range`.
+	expires map[string]time.Time
+	mu      sync.RWMutex
 }
 
-var _ Database = (*mem)(nil)
+var (
+	_ Database          = (*mem)(nil)
+	_ IDRotator         = (*mem)(nil)
+	_ ExpirationUpdater = (*mem)(nil)
+	_ Ranger            = (*mem)(nil)
+)
 
-func newMemDB() Database { return &mem{values: make(map[string]*memstore.Store)} }
+func newMemDB() Database {
+	return &mem{values: make(map[string]*memstore.Store), expires: make(map[string]time.Time)}
+}
 
 func (s *mem) Acquire(sid string, expires time.Duration) LifeTime {
 	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.values[sid]; ok {
+		// i.e a migrated session, see `Migrate`.
+		return LifeTime{Time: s.expires[sid]}
+	}
+
 	s.values[sid] = new(memstore.Store)
-	s.mu.Unlock()
+	if expires > 0 {
+		s.expires[sid] = time.Now().Add(expires)
+	}
 	return LifeTime{}
 }
 
 // immutable depends on the store, it may not implement it at all.
 func (s *mem) Set(sid string, lifetime LifeTime, key string, value interface{}, immutable bool) {
+	if !lifetime.IsZero() {
+		s.mu.Lock()
+		s.expires[sid] = lifetime.Time
+		s.mu.Unlock()
+	}
+
 	s.mu.RLock()
 	s.values[sid].Save(key, value, immutable)
 	s.mu.RUnlock()
@@ -70,7 +128,13 @@ func (s *mem) Get(sid string, key string) interface{} {
 }
 
 func (s *mem) Visit(sid string, cb func(key string, value interface{})) {
-	s.values[sid].Visit(cb)
+	s.mu.RLock()
+	store := s.values[sid]
+	s.mu.RUnlock()
+
+	if store != nil {
+		store.Visit(cb)
+	}
 }
 
 func (s *mem) Len(sid string) int {
@@ -94,8 +158,60 @@ func (s *mem) Clear(sid string) {
 	s.mu.Unlock()
 }
 
+func (s *mem) RotateID(oldSid, newSid string) error {
+	s.mu.Lock()
+	store, ok := s.values[oldSid]
+	if !ok {
+		store = new(memstore.Store)
+	}
+	s.values[newSid] = store
+	delete(s.values, oldSid)
+	if expires, ok := s.expires[oldSid]; ok {
+		s.expires[newSid] = expires
+		delete(s.expires, oldSid)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *mem) OnUpdateExpiration(sid string, newExpires time.Duration) {
+	if newExpires <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.values[sid]; ok {
+		s.expires[sid] = time.Now().Add(newExpires)
+	}
+	s.mu.Unlock()
+}
+
+func (s *mem) Range(cb func(sid string, lifetime LifeTime) bool) error {
+	type entry struct {
+		sid     string
+		expires time.Time
+	}
+
+	// copy them, so the "cb" can use the database.
+	s.mu.RLock()
+	entries := make([]entry, 0, len(s.values))
+	for sid := range s.values {
+		entries = append(entries, entry{sid, s.expires[sid]})
+	}
+	s.mu.RUnlock()
+
+	for _, e := range entries {
+		if !cb(e.sid, LifeTime{Time: e.expires}) {
+			break
+		}
+	}
+
+	return nil
+}
+
 func (s *mem) Release(sid string) {
 	s.mu.Lock()
 	delete(s.values, sid)
+	delete(s.expires, sid)
 	s.mu.Unlock()
 }
@@ -0,0 +1,66 @@
+package sessions
+
+import (
+	"github.com/kataras/iris/context"
+)
+
+// Listener is the form of a session's lifecycle listener, look `OnCreate`, `OnUpdate` and `OnDestroy` for more.
+//
+// The "ctx" is nil when the event is not caused by a request,
+// i.e on the session's expiration, on `DestroyByID` and `DestroyAll`,
+// and when the values of a server-side session are changed, because its `Session` is shared between the client's requests.
+type Listener func(ctx context.Context, sess *Session)
+
+// OnCreate registers one or more create listeners.
+// A create listener is fired when a new session is started for a client,
+// including the ones which replace an expired or destroyed session.
+func (s *Sessions) OnCreate(listeners ...Listener) {
+	s.provider.createListeners = appendListeners(s.provider.createListeners, listeners)
+}
+
+// OnUpdate registers one or more update listeners.
+// An update listener is fired when the session's values are changed by its `Set`, `SetImmutable`, `Increment`,
+// `Decrement`, `Delete` and `Clear`, when its expiration is updated and when its id is rotated.
+// The flash messages do not fire it and the `Config#SlidingExpiration` fires it on each request.
+func (s *Sessions) OnUpdate(listeners ...Listener) {
+	s.provider.updateListeners = appendListeners(s.provider.updateListeners, listeners)
+}
+
+// OnDestroy registers one or more destroy listeners.
+// A destroy listener is fired when a session is destroyed or expired, right before its values are removed,
+// so they can still be read by the listener.
+// The stateless sessions expire on the client-side, so only their destroy is reported.
+// Note that if a destroy listener is blocking, then the session manager will delay respectfully,
+// use a goroutine inside the listener to avoid that behavior.
+func (s *Sessions) OnDestroy(listeners ...Listener) {
+	s.provider.destroyListeners = appendListeners(s.provider.destroyListeners, listeners)
+}
+
+func appendListeners(dest []Listener, listeners []Listener) []Listener {
+	for _, ln := range listeners {
+		if ln != nil {
+			dest = append(dest, ln)
+		}
+	}
+
+	return dest
+}
+
+func fire(listeners []Listener, ctx context.Context, sess *Session) {
+	for _, ln := range listeners {
+		ln(ctx, sess)
+	}
+}
+
+// context returns the request's context of a stateless session, the server-side sessions have none.
+func (s *Session) context() context.Context {
+	if s.store != nil {
+		return s.store.ctx
+	}
+
+	return nil
+}
+
+func (s *Session) fireUpdate() {
+	fire(s.provider.updateListeners, s.context(), s)
+}
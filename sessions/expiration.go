@@ -0,0 +1,154 @@
+package sessions
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/context"
+)
+
+// metaKey is the key of the session's value which keeps its creation and its last access time,
+// it's used by the expiration policies, see `Config#AbsoluteExpiration` and `Config#IdleTimeout`.
+// It's stored like the rest of the values, so all of the databases and the stateless sessions keep it,
+// but it's hidden from the `Session#Visit` and `Session#GetAll`.
+const metaKey = "_iris_session_meta"
+
+// touchedContextKey is the prefix of the context's key of the session which its expiration policies are applied.
+const touchedContextKey = "iris.session.touched."
+
+func (c Config) hasExpirationPolicies() bool {
+	return c.SlidingExpiration || c.AbsoluteExpiration > 0 || c.IdleTimeout > 0
+}
+
+// sessionExpires returns the expiration of a new session, the "Expires" limited by the "AbsoluteExpiration".
+func (c Config) sessionExpires() time.Duration {
+	if c.AbsoluteExpiration > 0 && (c.Expires == 0 || c.Expires > c.AbsoluteExpiration) {
+		return c.AbsoluteExpiration
+	}
+
+	return c.Expires
+}
+
+// meta returns the creation and the last access time of the session as unix seconds.
+func (s *Session) meta() (created, accessed int64, ok bool) {
+	// a string survives the transcoding of the databases.
+	v, isString := s.database().Get(s.sid, metaKey).(string)
+	if !isString {
+		return
+	}
+
+	sep := strings.IndexByte(v, ' ')
+	if sep == -1 {
+		return
+	}
+
+	created, err := strconv.ParseInt(v[:sep], 10, 64)
+	if err != nil {
+		return
+	}
+
+	accessed, err = strconv.ParseInt(v[sep+1:], 10, 64)
+	return created, accessed, err == nil
+}
+
+func (s *Session) setMeta(created, accessed int64) {
+	s.database().Set(s.sid, s.Lifetime, metaKey, strconv.FormatInt(created, 10)+" "+strconv.FormatInt(accessed, 10), false)
+}
+
+// capExpiration limits the "expires" of the session by the "AbsoluteExpiration" since its creation.
+func (s *Sessions) capExpiration(sess *Session, expires time.Duration) time.Duration {
+	if s.config.AbsoluteExpiration <= 0 || expires < 0 {
+		return expires
+	}
+
+	created, _, ok := sess.meta()
+	if !ok {
+		return expires
+	}
+
+	remaining := time.Until(time.Unix(created, 0).Add(s.config.AbsoluteExpiration))
+	if remaining < time.Second {
+		// it's destroyed on its next request.
+		remaining = time.Second
+	}
+
+	if expires == 0 || expires > remaining {
+		return remaining
+	}
+
+	return expires
+}
+
+// applyExpirationPolicies applies the "SlidingExpiration", "AbsoluteExpiration" and the "IdleTimeout",
+// once per request, it returns a new session if the "sess" is expired by them.
+func (s *Sessions) applyExpirationPolicies(ctx context.Context, sess *Session) *Session {
+	if !s.config.hasExpirationPolicies() {
+		return sess
+	}
+
+	key := touchedContextKey + s.config.Cookie
+	if ctx.Values().GetString(key) == sess.sid {
+		return sess
+	}
+
+	now := time.Now().Unix()
+	created, accessed, ok := sess.meta()
+
+	if ok && s.expiredByPolicies(created, accessed, now) {
+		sess = s.renew(ctx, sess)
+		ok = false
+	}
+
+	ctx.Values().Set(key, sess.sid)
+
+	if !ok {
+		sess.setMeta(now, now)
+		return sess
+	}
+
+	if s.config.IdleTimeout > 0 && accessed != now {
+		sess.setMeta(created, now)
+	}
+
+	if s.config.SlidingExpiration && s.config.Expires > 0 {
+		s.UpdateExpiration(ctx, s.config.Expires)
+	}
+
+	return sess
+}
+
+func (s *Sessions) expiredByPolicies(created, accessed, now int64) bool {
+	if abs := s.config.AbsoluteExpiration; abs > 0 && now-created >= int64(abs/time.Second) {
+		return true
+	}
+
+	if idle := s.config.IdleTimeout; idle > 0 && now-accessed >= int64(idle/time.Second) {
+		return true
+	}
+
+	return false
+}
+
+// renew destroys the expired "sess" and it starts a new one for the client.
+func (s *Sessions) renew(ctx context.Context, sess *Session) *Session {
+	atomic.AddUint64(&s.provider.expired, 1)
+
+	if s.config.Stateless {
+		sess.Destroy()
+		return s.startStateless(ctx)
+	}
+
+	// the client keeps its session id.
+	sid := sess.sid
+	s.provider.destroy(ctx, sid)
+
+	expires := s.config.sessionExpires()
+	sess = s.provider.Init(sid, expires)
+	sess.isNew = true
+	s.updateCookie(ctx, sid, expires)
+	fire(s.provider.createListeners, ctx, sess)
+
+	return sess
+}
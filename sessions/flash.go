@@ -0,0 +1,164 @@
+package sessions
+
+import (
+	"strconv"
+
+	"github.com/kataras/iris/context"
+)
+
+// GetFlashInt same as `GetFlash` but returns its int representation,
+// if key doesn't exist then it returns -1 and a non-nil error.
+func (s *Session) GetFlashInt(key string) (int, error) {
+	v, err := s.GetFlashInt64(key)
+	if err != nil {
+		return -1, err
+	}
+
+	return int(v), nil
+}
+
+// GetFlashIntDefault same as `GetFlash` but returns its int representation,
+// if key doesn't exist then it returns the "defaultValue".
+func (s *Session) GetFlashIntDefault(key string, defaultValue int) int {
+	if v, err := s.GetFlashInt(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+// GetFlashInt64 same as `GetFlash` but returns its int64 representation,
+// if key doesn't exist then it returns -1 and a non-nil error.
+func (s *Session) GetFlashInt64(key string) (int64, error) {
+	value := s.GetFlash(key)
+
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		// the flash messages of the stateless sessions are decoded as JSON.
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+
+	return -1, errFindParse.Format("int64", key, value)
+}
+
+// GetFlashInt64Default same as `GetFlash` but returns its int64 representation,
+// if key doesn't exist then it returns the "defaultValue".
+func (s *Session) GetFlashInt64Default(key string, defaultValue int64) int64 {
+	if v, err := s.GetFlashInt64(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+// GetFlashFloat64 same as `GetFlash` but returns its float64 representation,
+// if key doesn't exist then it returns -1 and a non-nil error.
+func (s *Session) GetFlashFloat64(key string) (float64, error) {
+	value := s.GetFlash(key)
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return -1, errFindParse.Format("float64", key, value)
+}
+
+// GetFlashFloat64Default same as `GetFlash` but returns its float64 representation,
+// if key doesn't exist then it returns the "defaultValue".
+func (s *Session) GetFlashFloat64Default(key string, defaultValue float64) float64 {
+	if v, err := s.GetFlashFloat64(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+// GetFlashBoolean same as `GetFlash` but returns its boolean representation,
+// if key doesn't exist then it returns false and a non-nil error.
+func (s *Session) GetFlashBoolean(key string) (bool, error) {
+	value := s.GetFlash(key)
+
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+
+	return false, errFindParse.Format("bool", key, value)
+}
+
+// GetFlashBooleanDefault same as `GetFlash` but returns its boolean representation,
+// if key doesn't exist then it returns the "defaultValue".
+func (s *Session) GetFlashBooleanDefault(key string, defaultValue bool) bool {
+	if v, err := s.GetFlashBoolean(key); err == nil {
+		return v
+	}
+
+	return defaultValue
+}
+
+// DecodeFlash same as `Decode` but it decodes the flash message of the "key",
+// which will be removed on the next request.
+func (s *Session) DecodeFlash(key string, outPtr interface{}) error {
+	return decode(s.GetFlash(key), key, outPtr)
+}
+
+// PeekFlashString same as `PeekFlash` but returns its string representation,
+// if key doesn't exist then it returns an empty string.
+func (s *Session) PeekFlashString(key string) string {
+	if v, ok := s.PeekFlash(key).(string); ok {
+		return v
+	}
+
+	return ""
+}
+
+// FlashFuncs are the placeholders of the flash messages' template functions,
+// register them to the view engine before its templates are loaded, i.e
+// for name, fn := range sessions.FlashFuncs { tmpl.AddFunc(name, fn) }
+// and use the `Sessions#FlashHandler` to bind them to the request's session:
+//
+// {{ range $key, $message := flashes }} <p class="{{ $key }}">{{ $message }}</p> {{ end }}
+// {{ if hasFlash }} {{ flash "notice" }} {{ end }}
+//
+// The "flash" and "flashes" remove the messages on the next request, like the `Session#GetFlash`.
+var FlashFuncs = map[string]interface{}{
+	"flash":    func(key string) interface{} { return nil },
+	"flashes":  func() map[string]interface{} { return nil },
+	"hasFlash": func() bool { return false },
+}
+
+// FlashHandler is a middleware which binds the template functions of the `FlashFuncs`
+// to the request's session, the session is started when a function is called.
+//
+// Note that the stateless sessions write their cookie when a message is read,
+// so their messages should be rendered before the response body is written, i.e by the `Context#Record`.
+func (s *Sessions) FlashHandler(ctx context.Context) {
+	ctx.ViewFunc("flash", func(key string) interface{} {
+		return s.Get(ctx).GetFlash(key)
+	})
+	ctx.ViewFunc("flashes", func() map[string]interface{} {
+		return s.Get(ctx).GetFlashes()
+	})
+	ctx.ViewFunc("hasFlash", func() bool {
+		return s.Get(ctx).HasFlash()
+	})
+
+	ctx.Next()
+}
@@ -0,0 +1,84 @@
+package sessions
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+// Stats are the counters of a sessions manager, see `Sessions#Stats`.
+type Stats struct {
+	// Active is the number of the server-side sessions which are loaded to the memory,
+	// the stateless sessions are not counted.
+	Active int `json:"active"`
+	// Expired is the number of the sessions which were removed because of their expiration,
+	// including the ones expired by the `Config#AbsoluteExpiration` and `Config#IdleTimeout`.
+	Expired uint64 `json:"expired"`
+	// Errors is the number of the errors of the database, if it implements the `ErrorCounter`,
+	// its sweeps and the cookies of the stateless sessions.
+	Errors uint64 `json:"errors"`
+}
+
+// Stats returns the current counters of the sessions.
+func (s *Sessions) Stats() Stats {
+	stats := Stats{
+		Active:  s.provider.len(),
+		Expired: atomic.LoadUint64(&s.provider.expired),
+		Errors:  atomic.LoadUint64(&s.provider.errors),
+	}
+
+	if c, ok := s.provider.database().(ErrorCounter); ok {
+		stats.Errors += c.Errors()
+	}
+
+	return stats
+}
+
+// StatsVar is an `expvar.Var` of the sessions' counters,
+// publish it by expvar.Publish("sessions", sess.StatsVar()).
+type StatsVar struct {
+	sessions *Sessions
+}
+
+// StatsVar returns an `expvar.Var` of the `Stats`.
+func (s *Sessions) StatsVar() StatsVar {
+	return StatsVar{sessions: s}
+}
+
+// String returns the `Stats` as JSON, it implements the `expvar.Var`.
+func (v StatsVar) String() string {
+	b, _ := json.Marshal(v.sessions.Stats())
+	return string(b)
+}
+
+// Sweep destroys the expired server-side sessions which are loaded to the memory,
+// and it calls the database's `Sweep`, if it implements the `Sweeper`.
+// It returns the number of the destroyed sessions.
+//
+// It's called every `Config#GCInterval`, if it's positive,
+// otherwise the loaded sessions are removed by their timers and it can be used to clean the database manually.
+func (s *Sessions) Sweep() (int, error) {
+	n := s.provider.sweep()
+
+	if sw, ok := s.provider.database().(Sweeper); ok {
+		if err := sw.Sweep(); err != nil {
+			atomic.AddUint64(&s.provider.errors, 1)
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+func (s *Sessions) gc(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := s.Sweep(); err != nil {
+			golog.Debugf("sessions: sweep: %v", err)
+		}
+	}
+}
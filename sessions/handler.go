@@ -0,0 +1,76 @@
+package sessions
+
+import (
+	"github.com/kataras/iris/context"
+)
+
+const (
+	// managerContextKey is the context's key of the last manager which is bound by the `Sessions#Handler`.
+	managerContextKey = "iris.session.manager"
+	// sessionContextKey is the prefix of the context's key of the session which is started by the `Get`.
+	sessionContextKey = "iris.session.started."
+)
+
+// Handler returns a middleware which binds the manager to the request, so the next handlers
+// can get its session by the `Get`, without a reference to the manager.
+//
+// An application may use several managers with different cookies, expirations and databases,
+// i.e a short-lived session and a long-lived preferences cookie, each one registered to its own Party,
+// or to the same one and get their sessions by the `GetByCookie`:
+//
+// app.Use(preferences.Handler())
+// admin := app.Party("/admin", adminSessions.Handler())
+// admin.Get("/", func(ctx iris.Context) { session := sessions.Get(ctx) })
+//
+// Note that each manager should have a different `Config#Cookie`.
+//
+// The optional "cookieOptions" override the attributes of the session's cookie on the Party's routes,
+// they are applied after the `Config#CookieOptions`, i.e for an embedded widget:
+//
+// widget := app.Party("/widget", sess.Handler(context.CookieSameSite(context.SameSiteNone), context.CookiePartitioned))
+func (s *Sessions) Handler(cookieOptions ...context.CookieOption) context.Handler {
+	return func(ctx context.Context) {
+		ctx.Values().Set(managerContextKey, s)
+		ctx.Values().Set(managerContextKey+"."+s.config.Cookie, s)
+		if len(cookieOptions) > 0 {
+			ctx.Values().Set(cookieOptionsContextKey+s.config.Cookie, cookieOptions)
+		}
+		ctx.Next()
+	}
+}
+
+// Get returns the session of the manager which is bound to the request by the last `Sessions#Handler`,
+// the session is started on its first call, it returns nil if there is no bound manager.
+func Get(ctx context.Context) *Session {
+	s, ok := ctx.Values().Get(managerContextKey).(*Sessions)
+	if !ok {
+		return nil
+	}
+
+	return s.Get(ctx)
+}
+
+// GetByCookie same as `Get` but it returns the session of the bound manager of the "cookie" name,
+// see `Config#Cookie`.
+func GetByCookie(ctx context.Context, cookie string) *Session {
+	s, ok := ctx.Values().Get(managerContextKey + "." + cookie).(*Sessions)
+	if !ok {
+		return nil
+	}
+
+	return s.Get(ctx)
+}
+
+// Get starts the session of the manager once per request and returns the same one on the next calls,
+// unlike the `Start`, which starts a different one when the cookie of a new session is not reclaimed,
+// see `Config#AllowReclaim`, and which removes the flash messages which are read.
+func (s *Sessions) Get(ctx context.Context) *Session {
+	key := sessionContextKey + s.config.Cookie
+	if sess, ok := ctx.Values().Get(key).(*Session); ok {
+		return sess
+	}
+
+	sess := s.Start(ctx)
+	ctx.Values().Set(key, sess)
+	return sess
+}
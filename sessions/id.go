@@ -0,0 +1,129 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"math"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+const (
+	// SessionIDAlphabetURL is the alphabet of the URL-safe base64, the default `Config#SessionIDAlphabet`.
+	SessionIDAlphabetURL = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	// SessionIDAlphabetBase62 is the alphabet of the letters and the digits.
+	SessionIDAlphabetBase62 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	// SessionIDAlphabetHex is the alphabet of the lowercase hexadecimal digits.
+	SessionIDAlphabetHex = "0123456789abcdef"
+
+	// DefaultSessionIDLength is the default `Config#SessionIDLength`.
+	DefaultSessionIDLength = 32
+	// DefaultSessionIDMinEntropy is the default `Config#SessionIDMinEntropy`, in bits.
+	DefaultSessionIDMinEntropy = 64
+)
+
+var (
+	errSessionIDAlphabet = errors.New("session: id: the alphabet should have 2 to 256 unique cookie-safe characters")
+	errSessionIDEntropy  = errors.New("session: id: %d characters of an alphabet of %d have %.1f bits of entropy, at least %d are required")
+)
+
+// SessionIDEntropy returns the entropy, in bits, of a random id of "length" characters of the "alphabet".
+func SessionIDEntropy(length int, alphabet string) float64 {
+	if length <= 0 || len(alphabet) < 2 {
+		return 0
+	}
+
+	return float64(length) * math.Log2(float64(len(alphabet)))
+}
+
+// NewSessionIDGenerator returns a `Config#SessionIDGenerator` of cryptographically random ids
+// of "length" characters of the "alphabet", each character is equally likely.
+// It returns an error if the alphabet is invalid or if the entropy of the ids is less than the "minEntropy" bits,
+// see `SessionIDEntropy`.
+func NewSessionIDGenerator(length int, alphabet string, minEntropy int) (func() string, error) {
+	if !validSessionIDAlphabet(alphabet) {
+		return nil, errSessionIDAlphabet
+	}
+
+	if entropy := SessionIDEntropy(length, alphabet); entropy < float64(minEntropy) {
+		return nil, errSessionIDEntropy.Format(length, len(alphabet), entropy, minEntropy)
+	}
+
+	// the random bytes are masked to the smallest power of two which fits the alphabet
+	// and the ones out of it are rejected, so there is no modulo bias.
+	mask := byte(1)
+	for int(mask) < len(alphabet)-1 {
+		mask = mask<<1 | 1
+	}
+
+	return func() string {
+		id := make([]byte, 0, length)
+		buf := make([]byte, length+length/2)
+		for len(id) < length {
+			if _, err := rand.Read(buf); err != nil {
+				// the system's random generator is broken, a predictable id must not be returned.
+				panic("session: id: " + err.Error())
+			}
+
+			for _, b := range buf {
+				if b &= mask; int(b) < len(alphabet) {
+					id = append(id, alphabet[b])
+					if len(id) == length {
+						break
+					}
+				}
+			}
+		}
+
+		return string(id)
+	}, nil
+}
+
+func validSessionIDAlphabet(alphabet string) bool {
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		return false
+	}
+
+	var seen [256]bool
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if seen[c] || !validCookieValueByte(c) {
+			return false
+		}
+		seen[c] = true
+	}
+
+	return true
+}
+
+// validCookieValueByte reports whether the "c" is allowed in a cookie's value, see the RFC 6265.
+func validCookieValueByte(c byte) bool {
+	return 0x20 < c && c < 0x7f && c != '"' && c != ';' && c != '\\' && c != ','
+}
+
+// validateSessionID reports an error if the "SessionIDLength" and the "SessionIDAlphabet" of the configuration,
+// if any, do not generate safe session ids. A custom `Config#SessionIDGenerator` is not called,
+// its ids are its own responsibility.
+func (c Config) validateSessionID() error {
+	if c.SessionIDLength > 0 || c.SessionIDAlphabet != "" {
+		_, err := NewSessionIDGenerator(c.sessionIDLength(), c.sessionIDAlphabet(), c.SessionIDMinEntropy)
+		return err
+	}
+
+	return nil
+}
+
+func (c Config) sessionIDLength() int {
+	if c.SessionIDLength > 0 {
+		return c.SessionIDLength
+	}
+
+	return DefaultSessionIDLength
+}
+
+func (c Config) sessionIDAlphabet() string {
+	if c.SessionIDAlphabet != "" {
+		return c.SessionIDAlphabet
+	}
+
+	return SessionIDAlphabetURL
+}
@@ -0,0 +1,226 @@
+package sessions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/context"
+)
+
+// jwtContextKey is the prefix of the context's key of the request's token, see `Config#JWT`.
+const jwtContextKey = "iris.session.jwt."
+
+// jwtHeader is the encoded header of the issued tokens.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// jwtToken is the request's token, an empty "raw" for none or for an invalid one.
+type jwtToken struct {
+	raw    string
+	claims map[string]interface{}
+}
+
+func (t *jwtToken) subject() string {
+	if t.raw == "" {
+		return ""
+	}
+
+	sub, _ := t.claims["sub"].(string)
+	return sub
+}
+
+// sign returns the token of the "claims", signed by the first of the `Config#JWTKeys`.
+func (s *Sessions) sign(claims map[string]interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, s.config.JWTKeys[0])
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verify returns the claims of the "token" if it's signed by any of the `Config#JWTKeys`,
+// it's not expired, it's valid already, its issuer is the `Config#JWTIssuer` and it has a subject.
+func (s *Sessions) verify(token string) (map[string]interface{}, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	// the algorithm is not taken from the token, the rest of them, including the "none", are rejected.
+	if b, err := base64.RawURLEncoding.DecodeString(parts[0]); err != nil || json.Unmarshal(b, &header) != nil || header.Alg != "HS256" {
+		return nil, false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, false
+	}
+
+	valid := false
+	for _, key := range s.config.JWTKeys {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(parts[0] + "." + parts[1]))
+		if hmac.Equal(signature, mac.Sum(nil)) {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		return nil, false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, false
+	}
+
+	var claims map[string]interface{}
+	if err = json.Unmarshal(b, &claims); err != nil {
+		return nil, false
+	}
+
+	now := float64(time.Now().Unix())
+	if exp, ok := claims["exp"]; ok {
+		if v, isNumber := exp.(float64); !isNumber || v <= now {
+			return nil, false
+		}
+	}
+
+	if nbf, ok := claims["nbf"]; ok {
+		if v, isNumber := nbf.(float64); !isNumber || v > now {
+			return nil, false
+		}
+	}
+
+	if iss := s.config.JWTIssuer; iss != "" && claims["iss"] != iss {
+		return nil, false
+	}
+
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// requestToken returns the token of the request, from the "Authorization: Bearer" header
+// or from the session's cookie, it's verified once per request.
+func (s *Sessions) requestToken(ctx context.Context) *jwtToken {
+	key := jwtContextKey + s.config.Cookie
+	if t, ok := ctx.Values().Get(key).(*jwtToken); ok {
+		return t
+	}
+
+	raw := GetCookie(ctx, s.config.cookieName(ctx))
+	if auth := ctx.GetHeader("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		raw = auth[7:]
+	}
+
+	t := new(jwtToken)
+	if raw != "" {
+		if claims, ok := s.verify(raw); ok {
+			t.raw, t.claims = raw, claims
+		}
+	}
+
+	ctx.Values().Set(key, t)
+	return t
+}
+
+// issueToken returns a new token of the "sid" which expires after the "expires",
+// the custom claims of the request's token are kept, i.e when its id is rotated.
+func (s *Sessions) issueToken(ctx context.Context, sid string, expires time.Duration) string {
+	claims := make(map[string]interface{})
+	if t := s.requestToken(ctx); t.raw != "" {
+		for k, v := range t.claims {
+			claims[k] = v
+		}
+	}
+
+	now := time.Now()
+	claims["sub"] = sid
+	claims["iat"] = now.Unix()
+	delete(claims, "exp")
+	if expires > 0 {
+		claims["exp"] = now.Add(expires).Unix()
+	}
+	if s.config.JWTIssuer != "" {
+		claims["iss"] = s.config.JWTIssuer
+	}
+
+	token, err := s.sign(claims)
+	if err != nil {
+		atomic.AddUint64(&s.provider.errors, 1)
+		ctx.Logger().Errorf("session: token: %v", err)
+		return ""
+	}
+
+	// the next `Start` of the request reads the new token.
+	ctx.Values().Set(jwtContextKey+s.config.Cookie, &jwtToken{raw: token, claims: claims})
+	return token
+}
+
+// Token returns the signed token of the request's session, see `Config#JWT`,
+// send it to the clients which use the "Authorization: Bearer" header instead of the cookie.
+// The session is started, if it's not already.
+func (s *Sessions) Token(ctx context.Context) string {
+	if !s.config.JWT {
+		return ""
+	}
+
+	s.Start(ctx)
+	return s.requestToken(ctx).raw
+}
+
+// Claims returns the claims of the request's token, see `Config#JWT` and `Token`.
+func (s *Sessions) Claims(ctx context.Context) map[string]interface{} {
+	if !s.config.JWT {
+		return nil
+	}
+
+	s.Start(ctx)
+	return s.requestToken(ctx).claims
+}
+
+// SetClaims adds custom claims to the request's token, i.e "roles", a new token is issued
+// and sent by the session's cookie, the "sub", "iat", "exp" and "iss" can not be modified.
+// It returns the new token, see `Token`.
+func (s *Sessions) SetClaims(ctx context.Context, claims map[string]interface{}) string {
+	if !s.config.JWT {
+		return ""
+	}
+
+	sess := s.Start(ctx)
+	t := s.requestToken(ctx)
+
+	merged := make(map[string]interface{}, len(t.claims)+len(claims))
+	for k, v := range t.claims {
+		merged[k] = v
+	}
+	for k, v := range claims {
+		merged[k] = v
+	}
+
+	ctx.Values().Set(jwtContextKey+s.config.Cookie, &jwtToken{raw: t.raw, claims: merged})
+
+	expires := s.config.Expires
+	if !sess.Lifetime.IsZero() {
+		expires = sess.Lifetime.DurationUntilExpiration()
+	}
+
+	s.updateCookie(ctx, sess.ID(), expires)
+	return s.requestToken(ctx).raw
+}
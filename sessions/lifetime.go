@@ -17,13 +17,16 @@ type LifeTime struct {
 
 // Begin will begin the life based on the time.Now().Add(d).
 // Use `Continue` to continue from a stored time(database-based session does that).
+// A nil "onExpire" sets the expiration without a timer, see `Config#GCInterval`.
 func (lt *LifeTime) Begin(d time.Duration, onExpire func()) {
 	if d <= 0 {
 		return
 	}
 
 	lt.Time = time.Now().Add(d)
-	lt.timer = time.AfterFunc(d, onExpire)
+	if onExpire != nil {
+		lt.timer = time.AfterFunc(d, onExpire)
+	}
 }
 
 // Revive will continue the life based on the stored Time.
@@ -34,7 +37,7 @@ func (lt *LifeTime) Revive(onExpire func()) {
 	}
 
 	now := time.Now()
-	if lt.Time.After(now) {
+	if onExpire != nil && lt.Time.After(now) {
 		d := lt.Time.Sub(now)
 		lt.timer = time.AfterFunc(d, onExpire)
 	}
@@ -42,7 +45,12 @@ func (lt *LifeTime) Revive(onExpire func()) {
 
 // Shift resets the lifetime based on "d".
 func (lt *LifeTime) Shift(d time.Duration) {
-	if d > 0 && lt.timer != nil {
+	if d <= 0 || lt.Time.IsZero() {
+		return
+	}
+
+	lt.Time = time.Now().Add(d)
+	if lt.timer != nil {
 		lt.timer.Reset(d)
 	}
 }
@@ -0,0 +1,172 @@
+package sessions
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+)
+
+const (
+	// DefaultLockTimeout is the default `Config#LockTimeout`.
+	DefaultLockTimeout = 10 * time.Second
+	// DefaultLockTTL is the default `Config#LockTTL`.
+	DefaultLockTTL = 30 * time.Second
+)
+
+// ErrLockTimeout is returned by the `Session#Lock` when the session
+// is locked by another request for longer than the `Config#LockTimeout`.
+var ErrLockTimeout = errors.New("session: lock: timeout")
+
+// Locker can be implemented by a `Database` which can lock a session across the instances of the application,
+// it's used by the `Session#Lock`, otherwise the session is locked in the current instance only.
+type Locker interface {
+	// TryLock acquires the lock of the session if it's free, the "token" identifies its owner.
+	// The lock should expire after the "ttl", so it's released if the owner fails to unlock it.
+	TryLock(sid, token string, ttl time.Duration) (bool, error)
+	// Unlock releases the lock of the session if it's owned by the "token".
+	Unlock(sid, token string) error
+}
+
+// localLocks are the locks of the sessions in the current instance.
+type localLocks struct {
+	mu    sync.Mutex
+	locks map[string]*localLock
+}
+
+type localLock struct {
+	ch chan struct{}
+	// the number of the owner and the waiters, the lock is removed when it's zero.
+	refs int
+}
+
+func (l *localLocks) get(sid string) *localLock {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*localLock)
+	}
+
+	lock, ok := l.locks[sid]
+	if !ok {
+		lock = &localLock{ch: make(chan struct{}, 1)}
+		l.locks[sid] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	return lock
+}
+
+func (l *localLocks) put(sid string, lock *localLock) {
+	l.mu.Lock()
+	if lock.refs--; lock.refs == 0 {
+		delete(l.locks, sid)
+	}
+	l.mu.Unlock()
+}
+
+// lock acquires the lock of the "sid", it reports false if it's not acquired after the "timeout".
+func (l *localLocks) lock(sid string, timeout time.Duration) (unlock func(), ok bool) {
+	lock := l.get(sid)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case lock.ch <- struct{}{}:
+		return func() {
+			<-lock.ch
+			l.put(sid, lock)
+		}, true
+	case <-timer.C:
+		l.put(sid, lock)
+		return nil, false
+	}
+}
+
+// Lock locks the session, so the concurrent requests of the same client
+// can read and modify its values without a race, the session is unlocked by the "unlock".
+// It's locked across the instances of the application if the database implements the `Locker`,
+// otherwise in the current instance only.
+//
+// It waits for the lock up to the `Config#LockTimeout`, then it returns the `ErrLockTimeout`.
+// See `Update` and `Sessions#LockHandler` too.
+func (s *Session) Lock() (unlock func(), err error) {
+	config := s.provider.manager.config
+	deadline := time.Now().Add(config.LockTimeout)
+
+	// the requests of this instance wait for each other without polling the database.
+	sid := s.ID()
+	unlockLocal, ok := s.provider.locks.lock(sid, config.LockTimeout)
+	if !ok {
+		return nil, ErrLockTimeout
+	}
+
+	locker, ok := s.provider.database().(Locker)
+	if !ok || s.store != nil {
+		return unlockLocal, nil
+	}
+
+	token := config.SessionIDGenerator()
+	for wait := 10 * time.Millisecond; ; wait *= 2 {
+		locked, err := locker.TryLock(sid, token, config.LockTTL)
+		if err != nil {
+			unlockLocal()
+			return nil, err
+		}
+
+		if locked {
+			break
+		}
+
+		if time.Now().Add(wait).After(deadline) {
+			unlockLocal()
+			return nil, ErrLockTimeout
+		}
+
+		if wait > 200*time.Millisecond {
+			wait = 200 * time.Millisecond
+		}
+		time.Sleep(wait)
+	}
+
+	return func() {
+		if err := locker.Unlock(sid, token); err != nil {
+			// it expires after the "LockTTL".
+			atomic.AddUint64(&s.provider.errors, 1)
+			golog.Debugf("session: unlock: %v", err)
+		}
+		unlockLocal()
+	}, nil
+}
+
+// Update calls the "fn" while the session is locked, see `Lock`,
+// i.e to increment a value of the session by concurrent requests.
+func (s *Session) Update(fn func() error) error {
+	unlock, err := s.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn()
+}
+
+// LockHandler is a middleware which locks the request's session until the next handlers are executed,
+// see `Session#Lock`. It responds with 503 Service Unavailable if the session is not locked.
+func (s *Sessions) LockHandler(ctx context.Context) {
+	unlock, err := s.Start(ctx).Lock()
+	if err != nil {
+		ctx.Logger().Debugf("session: %v", err)
+		ctx.StatusCode(http.StatusServiceUnavailable)
+		ctx.StopExecution()
+		return
+	}
+	defer unlock()
+
+	ctx.Next()
+}
@@ -0,0 +1,101 @@
+package sessions
+
+import (
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// ErrNotRanger is returned by the `Migrate` when its source database can not list its sessions, see `Ranger`.
+var ErrNotRanger = errors.New("session: the source database does not implement the Ranger")
+
+// MigrateOptions are the options of the `Migrate`.
+type MigrateOptions struct {
+	// Overwrite replaces the sessions which already exist on the destination database,
+	// otherwise they are skipped, so a migration can be resumed.
+	//
+	// Defaults to false.
+	Overwrite bool
+	// ReleaseSource removes each one of the migrated sessions from the source database.
+	//
+	// Defaults to false.
+	ReleaseSource bool
+	// OnSession is called after the migration of each session, i.e to report the progress.
+	//
+	// Defaults to nil.
+	OnSession func(sid string, lifetime LifeTime)
+}
+
+// MigrateResult is the report of the `Migrate`.
+type MigrateResult struct {
+	// Migrated is the number of the sessions which are copied to the destination database.
+	Migrated int
+	// Skipped is the number of the sessions which are expired or exist on the destination database already.
+	Skipped int
+}
+
+// Migrate copies the live sessions of the "src" database, with their values and their remaining lifetime,
+// to the "dst" database, so the clients are not logged out when the sessions' database of the application is replaced,
+// i.e from the memory to the redis or from the badger to the redis.
+//
+// The "src" should implement the `Ranger`, the memory, badger, boltdb and sql databases do,
+// any database can be the "dst". The in-memory database of a manager is returned by its `Sessions#Database`.
+//
+// Example:
+// redisDB := redis.New(service.Config{Addr: "127.0.0.1:6379"})
+// result, err := sessions.Migrate(sess.Database(), redisDB, sessions.MigrateOptions{})
+// sess.UseDatabase(redisDB)
+func Migrate(src, dst Database, opts ...MigrateOptions) (MigrateResult, error) {
+	var (
+		result MigrateResult
+		o      MigrateOptions
+	)
+
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	ranger, ok := src.(Ranger)
+	if !ok {
+		return result, ErrNotRanger
+	}
+
+	err := ranger.Range(func(sid string, lifetime LifeTime) bool {
+		if lifetime.HasExpired() {
+			result.Skipped++
+			return true
+		}
+
+		var expires time.Duration // does not expire.
+		if !lifetime.IsZero() {
+			expires = lifetime.DurationUntilExpiration()
+		}
+
+		if existing := dst.Acquire(sid, expires); !existing.IsZero() || dst.Len(sid) > 0 {
+			if !o.Overwrite {
+				result.Skipped++
+				return true
+			}
+
+			dst.Release(sid)
+			dst.Acquire(sid, expires)
+		}
+
+		src.Visit(sid, func(key string, value interface{}) {
+			dst.Set(sid, lifetime, key, value, false)
+		})
+
+		if o.ReleaseSource {
+			src.Release(sid)
+		}
+
+		result.Migrated++
+		if o.OnSession != nil {
+			o.OnSession(sid, lifetime)
+		}
+
+		return true
+	})
+
+	return result, err
+}
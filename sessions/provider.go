@@ -2,20 +2,34 @@ package sessions
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/kataras/iris/context"
 )
 
 type (
 	// provider contains the sessions and external databases (load and update).
 	// It's the session memory manager
 	provider struct {
+		// the counters of the `Sessions#Stats`, first for the alignment of their atomic operations.
+		expired uint64
+		errors  uint64
+
 		// we don't use RWMutex because all actions have read and write at the same action function.
 		// (or write to a *Session's value which is race if we don't lock)
 		// narrow locks are fasters but are useless here.
-		mu               sync.Mutex
-		sessions         map[string]*Session
-		db               Database
-		destroyListeners []DestroyListener
+		mu       sync.Mutex
+		sessions map[string]*Session
+		db       Database
+		// the lifecycle listeners, see `Sessions#OnCreate`, `OnUpdate` and `OnDestroy`.
+		createListeners  []Listener
+		updateListeners  []Listener
+		destroyListeners []Listener
+		// the locks of the sessions in this instance, see `Session#Lock`.
+		locks localLocks
+		// manager is the sessions manager of the provider.
+		manager *Sessions
 	}
 )
 
@@ -34,10 +48,24 @@ func (p *provider) RegisterDatabase(db Database) {
 	p.mu.Unlock()
 }
 
+// database returns the registered session database.
+func (p *provider) database() Database {
+	p.mu.Lock()
+	db := p.db
+	p.mu.Unlock()
+	return db
+}
+
 // newSession returns a new session from sessionid
 func (p *provider) newSession(sid string, expires time.Duration) *Session {
 	onExpire := func() {
-		p.Destroy(sid)
+		if p.destroy(nil, sid) {
+			atomic.AddUint64(&p.expired, 1)
+		}
+	}
+	if p.manager != nil && p.manager.config.GCInterval > 0 {
+		// removed by the `Sessions#Sweep`.
+		onExpire = nil
 	}
 
 	lifetime := p.db.Acquire(sid, expires)
@@ -76,6 +104,45 @@ func (p *provider) Init(sid string, expires time.Duration) *Session {
 	return newSession
 }
 
+// rotate moves the values of the "sess" to the "newSid" and it releases its old id, see `Session#RotateID`.
+// The destroy listeners are not fired.
+func (p *provider) rotate(sess *Session, newSid string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	oldSid := sess.sid
+	if r, ok := p.db.(IDRotator); ok {
+		if err := r.RotateID(oldSid, newSid); err != nil {
+			return err
+		}
+	} else {
+		var expires time.Duration
+		if !sess.Lifetime.IsZero() {
+			expires = sess.Lifetime.DurationUntilExpiration()
+		}
+
+		p.db.Acquire(newSid, expires)
+		p.db.Visit(oldSid, func(key string, value interface{}) {
+			p.db.Set(newSid, sess.Lifetime, key, value, false)
+		})
+		p.db.Release(oldSid)
+	}
+
+	delete(p.sessions, oldSid)
+	sess.sid = newSid
+	p.sessions[newSid] = sess
+
+	// the expiration destroys the new id.
+	if sess.Lifetime.timer != nil {
+		sess.Lifetime.timer.Stop()
+		sess.Lifetime.Revive(func() {
+			p.Destroy(newSid)
+		})
+	}
+
+	return nil
+}
+
 // UpdateExpiration update expire date of a session.
 // if expires > 0 then it updates the destroy task.
 // if expires <=0 then it does nothing, to destroy a session call the `Destroy` func instead.
@@ -86,51 +153,85 @@ func (p *provider) UpdateExpiration(sid string, expires time.Duration) bool {
 
 	p.mu.Lock()
 	sess, found := p.sessions[sid]
+	if found {
+		sess.Lifetime.Shift(expires)
+	}
 	p.mu.Unlock()
+
 	if !found {
 		return false
 	}
 
-	sess.Lifetime.Shift(expires)
+	if u, ok := p.db.(ExpirationUpdater); ok {
+		u.OnUpdateExpiration(sid, expires)
+	}
 	return true
 }
 
-// Read returns the store which sid parameter belongs
-func (p *provider) Read(sid string, expires time.Duration) *Session {
+// get returns the session of the "sid", if it's loaded.
+func (p *provider) get(sid string) (*Session, bool) {
+	p.mu.Lock()
+	sess, found := p.sessions[sid]
+	p.mu.Unlock()
+	return sess, found
+}
+
+// Read returns the store which sid parameter belongs,
+// "created" reports whether the session was not loaded and the database has no values for it.
+func (p *provider) Read(sid string, expires time.Duration) (sess *Session, created bool) {
 	p.mu.Lock()
 	if sess, found := p.sessions[sid]; found {
 		sess.runFlashGC() // run the flash messages GC, new request here of existing session
 		p.mu.Unlock()
 
-		return sess
+		return sess, false
 	}
 	p.mu.Unlock()
 
-	return p.Init(sid, expires) // if not found create new
+	sess = p.Init(sid, expires) // if not found create new
+	return sess, p.db.Len(sid) == 0
 }
 
-func (p *provider) registerDestroyListener(ln DestroyListener) {
-	if ln == nil {
-		return
-	}
-	p.destroyListeners = append(p.destroyListeners, ln)
+// Destroy destroys the session, removes all sessions and flash values,
+// the session itself and updates the registered session databases,
+// this called from sessionManager which removes the client's cookie also.
+func (p *provider) Destroy(sid string) {
+	p.destroy(nil, sid)
 }
 
-func (p *provider) fireDestroy(sid string) {
-	for _, ln := range p.destroyListeners {
-		ln(sid)
+// destroy same as `Destroy` but the "ctx" of the request is passed to the destroy listeners,
+// it reports whether the session was found.
+func (p *provider) destroy(ctx context.Context, sid string) bool {
+	p.mu.Lock()
+	sess, found := p.sessions[sid]
+	if found {
+		p.deleteSession(ctx, sess)
 	}
+	p.mu.Unlock()
+	return found
 }
 
-// Destroy destroys the session, removes all sessions and flash values,
-// the session itself and updates the registered session databases,
-// this called from sessionManager which removes the client's cookie also.
-func (p *provider) Destroy(sid string) {
+// sweep destroys the expired sessions, it returns their number.
+func (p *provider) sweep() (n int) {
 	p.mu.Lock()
-	if sess, found := p.sessions[sid]; found {
-		p.deleteSession(sess)
+	for _, sess := range p.sessions {
+		if sess.Lifetime.HasExpired() {
+			p.deleteSession(nil, sess)
+			n++
+		}
 	}
 	p.mu.Unlock()
+
+	atomic.AddUint64(&p.expired, uint64(n))
+	return
+}
+
+// len returns the number of the loaded sessions.
+func (p *provider) len() int {
+	p.mu.Lock()
+	n := len(p.sessions)
+	p.mu.Unlock()
+	return n
 }
 
 // DestroyAll removes all sessions
@@ -139,15 +240,15 @@ func (p *provider) Destroy(sid string) {
 func (p *provider) DestroyAll() {
 	p.mu.Lock()
 	for _, sess := range p.sessions {
-		p.deleteSession(sess)
+		p.deleteSession(nil, sess)
 	}
 	p.mu.Unlock()
 }
 
-func (p *provider) deleteSession(sess *Session) {
+func (p *provider) deleteSession(ctx context.Context, sess *Session) {
 	sid := sess.sid
 
 	delete(p.sessions, sid)
+	fire(p.destroyListeners, ctx, sess)
 	p.db.Release(sid)
-	p.fireDestroy(sid)
 }
@@ -0,0 +1,262 @@
+// Package remember provides the persistent login ("remember me") tokens of the sessions.
+//
+// The client keeps a cookie of a series and a secret, the server keeps the hash of the secret,
+// when the session of the client is not authenticated the cookie re-establishes it and its secret is rotated.
+// A wrong secret of an existing series means that the cookie was stolen and used by another client,
+// then all of the user's tokens are removed.
+package remember
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/sessions"
+)
+
+// Config is the configuration of the remember-me tokens.
+type Config struct {
+	// Cookie is the name of the token's cookie.
+	//
+	// Defaults to "iris_remember".
+	Cookie string
+	// Expires is the lifetime of a token, it's extended on each use.
+	//
+	// Defaults to 30 days.
+	Expires time.Duration
+	// Key is the key of the session's value of the user's id, which is set by the `Session#Authenticate`,
+	// a session without it is re-established by the token.
+	//
+	// Defaults to "user_id".
+	Key string
+	// GracePeriod is the duration which the previous secret of a token is accepted after its rotation,
+	// so the concurrent requests of the same client, with the same cookie, are not considered a theft.
+	//
+	// Defaults to 30 seconds.
+	GracePeriod time.Duration
+	// CookieSecureTLS set to true if the cookie should be sent over HTTPS only.
+	//
+	// Defaults to false.
+	CookieSecureTLS bool
+	// CookieOptions set the rest of the cookie's attributes, i.e `context.CookieSameSite(context.SameSiteLax)`.
+	//
+	// Defaults to nil.
+	CookieOptions []context.CookieOption
+	// OnTheft is called when a stolen token is detected, after the removal of all of the user's tokens,
+	// i.e to destroy the user's sessions and to notify the user.
+	//
+	// Defaults to nil.
+	OnTheft func(ctx context.Context, userID string)
+}
+
+func (c Config) validate() Config {
+	if c.Cookie == "" {
+		c.Cookie = "iris_remember"
+	}
+
+	if c.Expires <= 0 {
+		c.Expires = 30 * 24 * time.Hour
+	}
+
+	if c.Key == "" {
+		c.Key = "user_id"
+	}
+
+	if c.GracePeriod <= 0 {
+		c.GracePeriod = 30 * time.Second
+	}
+
+	return c
+}
+
+// Remember re-establishes the sessions of the remembered users, see `New`.
+type Remember struct {
+	sessions *sessions.Sessions
+	store    Store
+	config   Config
+}
+
+// New returns a new remember-me manager of the "sess" sessions manager,
+// the tokens are kept by the "store", i.e the `NewMemStore`.
+// Register its `Handler` before the handlers which read the user from the session,
+// they should get the session by the `Sessions#Get`, so they read the re-established one.
+func New(sess *sessions.Sessions, store Store, cfg ...Config) *Remember {
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	return &Remember{sessions: sess, store: store, config: c.validate()}
+}
+
+// Remember issues a new token of the user, call it after the login, i.e when the "remember me" is checked.
+func (r *Remember) Remember(ctx context.Context, userID string) error {
+	series, err := random(16)
+	if err != nil {
+		return err
+	}
+
+	token, secret, err := r.rotate(Token{Series: series, UserID: userID})
+	if err != nil {
+		return err
+	}
+
+	if err = r.store.Save(token); err != nil {
+		return err
+	}
+
+	r.setCookie(ctx, token.Series+":"+secret, token.Expires)
+	return nil
+}
+
+// Forget removes the request's token, call it on logout.
+func (r *Remember) Forget(ctx context.Context) error {
+	r.removeCookie(ctx)
+
+	series, _, ok := parse(sessions.GetCookie(ctx, r.config.Cookie))
+	if !ok {
+		return nil
+	}
+
+	return r.store.Delete(series)
+}
+
+// ForgetUser removes all of the user's tokens, i.e on a password change.
+func (r *Remember) ForgetUser(userID string) error {
+	return r.store.DeleteUser(userID)
+}
+
+// Handler is a middleware which authenticates the request's session by the token's user,
+// if it's not already and the request has a valid token, the token's secret is rotated.
+func (r *Remember) Handler(ctx context.Context) {
+	if cookie := sessions.GetCookie(ctx, r.config.Cookie); cookie != "" {
+		if sess := r.sessions.Get(ctx); sess.Get(r.config.Key) == nil {
+			if err := r.login(ctx, sess, cookie); err != nil {
+				ctx.Logger().Errorf("remember: %v", err)
+			}
+		}
+	}
+
+	ctx.Next()
+}
+
+func (r *Remember) login(ctx context.Context, sess *sessions.Session, cookie string) error {
+	series, secret, ok := parse(cookie)
+	if !ok {
+		r.removeCookie(ctx)
+		return nil
+	}
+
+	token, found, err := r.store.Get(series)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !found || token.Expires.Before(now) {
+		r.removeCookie(ctx)
+		if found {
+			return r.store.Delete(series)
+		}
+		return nil
+	}
+
+	hash := hashOf(secret)
+	switch {
+	case hmac.Equal(hash, token.Hash):
+		rotated, secret, err := r.rotate(token)
+		if err != nil {
+			return err
+		}
+
+		ok, err := r.store.Rotate(rotated, token.Hash)
+		if err != nil {
+			return err
+		}
+
+		// if not ok, a concurrent request of the same secret rotated it first,
+		// its client receives the new one by the other response, like the grace period below.
+		if ok {
+			r.setCookie(ctx, rotated.Series+":"+secret, rotated.Expires)
+		}
+	case hmac.Equal(hash, token.PreviousHash) && now.Sub(token.RotatedAt) < r.config.GracePeriod:
+		// a concurrent request of the rotated secret, its client receives the new one by the other response.
+	default:
+		// the series is valid but the secret is not, it's used by another client.
+		r.removeCookie(ctx)
+		if err = r.store.DeleteUser(token.UserID); err != nil {
+			return err
+		}
+
+		if r.config.OnTheft != nil {
+			r.config.OnTheft(ctx, token.UserID)
+		}
+		return nil
+	}
+
+	return sess.Authenticate(ctx, r.config.Key, token.UserID)
+}
+
+// rotate returns the "token" with a new secret, its previous one is kept for the grace period.
+func (r *Remember) rotate(token Token) (Token, string, error) {
+	secret, err := random(32)
+	if err != nil {
+		return token, "", err
+	}
+
+	now := time.Now()
+	token.PreviousHash, token.Hash = token.Hash, hashOf(secret)
+	token.RotatedAt = now
+	token.Expires = now.Add(r.config.Expires)
+	return token, secret, nil
+}
+
+func (r *Remember) setCookie(ctx context.Context, value string, expires time.Time) {
+	cookie := &http.Cookie{
+		Name:     r.config.Cookie,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Expires:  expires,
+		MaxAge:   int(time.Until(expires).Seconds()),
+	}
+
+	if ctx.Request().TLS != nil && r.config.CookieSecureTLS {
+		cookie.Secure = true
+	}
+
+	sessions.AddCookie(ctx, cookie, false, r.config.CookieOptions...)
+}
+
+func (r *Remember) removeCookie(ctx context.Context) {
+	r.setCookie(ctx, "", sessions.CookieExpireDelete)
+}
+
+// parse returns the series and the secret of the cookie's value.
+func parse(cookie string) (series, secret string, ok bool) {
+	sep := strings.IndexByte(cookie, ':')
+	if sep <= 0 || sep == len(cookie)-1 {
+		return "", "", false
+	}
+
+	return cookie[:sep], cookie[sep+1:], true
+}
+
+func random(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashOf(secret string) []byte {
+	h := sha256.Sum256([]byte(secret))
+	return h[:]
+}
@@ -0,0 +1,197 @@
+package remember
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/sessions"
+)
+
+func newTestApp(t *testing.T, store Store, cfg Config) *iris.Application {
+	sess := sessions.New(sessions.Config{Cookie: "sid"})
+	r := New(sess, store, cfg)
+
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	app.Use(r.Handler)
+	app.Get("/login", func(ctx context.Context) {
+		sess.Get(ctx).Authenticate(ctx, "user_id", "kataras")
+		if err := r.Remember(ctx, "kataras"); err != nil {
+			t.Error(err)
+		}
+	})
+	app.Get("/", func(ctx context.Context) {
+		userID := sess.Get(ctx).GetString("user_id")
+		if userID == "" {
+			ctx.StatusCode(iris.StatusUnauthorized)
+			return
+		}
+		ctx.WriteString(userID)
+	})
+	app.Get("/logout", func(ctx context.Context) {
+		r.Forget(ctx)
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	return app
+}
+
+// serve sends a request of the "path" with the "cookie" as the remember-me cookie, if not empty,
+// and it returns the response and the new remember-me cookie, if any.
+func serve(app *iris.Application, path, cookie string) (*httptest.ResponseRecorder, string, bool) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "iris_remember", Value: cookie})
+	}
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "iris_remember" {
+			return rec, c.Value, true
+		}
+	}
+
+	return rec, "", false
+}
+
+func TestRemember(t *testing.T) {
+	var thefts uint32
+	app := newTestApp(t, NewMemStore(), Config{
+		GracePeriod: 50 * time.Millisecond,
+		OnTheft:     func(ctx context.Context, userID string) { atomic.AddUint32(&thefts, 1) },
+	})
+
+	_, first, ok := serve(app, "/login", "")
+	if !ok || first == "" {
+		t.Fatalf("expected the remember-me cookie after the login")
+	}
+
+	rec, second, ok := serve(app, "/", first)
+	if rec.Code != iris.StatusOK || rec.Body.String() != "kataras" || !ok || second == first {
+		t.Fatalf("expected the session to be re-established and the secret to be rotated but got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// the previous secret is accepted for the grace period, without a new cookie.
+	if rec, _, ok = serve(app, "/", first); rec.Code != iris.StatusOK || ok {
+		t.Fatalf("expected the previous secret to be accepted in the grace period but got %d", rec.Code)
+	}
+
+	// an invalid cookie is removed.
+	if rec, cookie, ok := serve(app, "/", "invalid"); rec.Code != iris.StatusUnauthorized || !ok || cookie != "" {
+		t.Fatalf("expected the invalid cookie to be removed but got %d", rec.Code)
+	}
+
+	// after the grace period the previous secret is a theft, all of the user's tokens are removed.
+	time.Sleep(60 * time.Millisecond)
+	if rec, _, _ = serve(app, "/", first); rec.Code != iris.StatusUnauthorized || atomic.LoadUint32(&thefts) != 1 {
+		t.Fatalf("expected the theft to be detected but got %d and %d thefts", rec.Code, atomic.LoadUint32(&thefts))
+	}
+
+	if rec, _, _ = serve(app, "/", second); rec.Code != iris.StatusUnauthorized {
+		t.Fatalf("expected the tokens of the user to be removed after the theft but got %d", rec.Code)
+	}
+
+	// the logout removes the token.
+	_, cookie, _ := serve(app, "/login", "")
+	if rec, _, ok = serve(app, "/logout", cookie); !ok {
+		t.Fatalf("expected the cookie to be removed on logout")
+	}
+
+	if rec, _, _ = serve(app, "/", cookie); rec.Code != iris.StatusUnauthorized {
+		t.Fatalf("expected the token to be removed on logout but got %d", rec.Code)
+	}
+}
+
+func TestRememberExpired(t *testing.T) {
+	app := newTestApp(t, NewMemStore(), Config{Expires: 10 * time.Millisecond})
+
+	_, cookie, _ := serve(app, "/login", "")
+	time.Sleep(20 * time.Millisecond)
+
+	if rec, _, ok := serve(app, "/", cookie); rec.Code != iris.StatusUnauthorized || !ok {
+		t.Fatalf("expected the expired token to be rejected and its cookie removed but got %d", rec.Code)
+	}
+}
+
+// barrierStore waits for "n" concurrent `Get` calls, so they all read the same token.
+type barrierStore struct {
+	Store
+	wg sync.WaitGroup
+}
+
+func (s *barrierStore) Get(series string) (Token, bool, error) {
+	token, found, err := s.Store.Get(series)
+	s.wg.Done()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+
+	return token, found, err
+}
+
+func TestRememberConcurrent(t *testing.T) {
+	const n = 5
+
+	var thefts uint32
+	store := &barrierStore{Store: NewMemStore()}
+	app := newTestApp(t, store, Config{
+		OnTheft: func(ctx context.Context, userID string) { atomic.AddUint32(&thefts, 1) },
+	})
+
+	_, cookie, _ := serve(app, "/login", "")
+
+	// i.e the tabs of a browser which are reopened at once with the same cookie.
+	store.wg.Add(n)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		cookies []string
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec, newCookie, ok := serve(app, "/", cookie)
+			if rec.Code != iris.StatusOK {
+				t.Errorf("expected all of the concurrent requests to be authenticated but got %d", rec.Code)
+			}
+
+			if ok {
+				mu.Lock()
+				cookies = append(cookies, newCookie)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(cookies) != 1 {
+		t.Fatalf("expected the secret to be rotated once but got %d new cookies", len(cookies))
+	}
+
+	// the new secret is valid, it's not a theft.
+	store.wg.Add(1)
+	if rec, _, _ := serve(app, "/", cookies[0]); rec.Code != iris.StatusOK || atomic.LoadUint32(&thefts) != 0 {
+		t.Fatalf("expected the rotated secret to be valid but got %d and %d thefts", rec.Code, atomic.LoadUint32(&thefts))
+	}
+}
@@ -0,0 +1,105 @@
+package remember
+
+import (
+	"crypto/hmac"
+	"sync"
+	"time"
+)
+
+// Token is a persistent login token of a user, the "series" identifies it for its lifetime
+// and its secret is rotated on each use, only the hash of the secret is stored.
+type Token struct {
+	Series string
+	// Hash is the SHA-256 of the current secret.
+	Hash []byte
+	// PreviousHash is the SHA-256 of the previous secret,
+	// it's accepted for the `Config#GracePeriod` after the rotation, i.e by the concurrent requests.
+	PreviousHash []byte
+	RotatedAt    time.Time
+	UserID       string
+	Expires      time.Time
+}
+
+// Store is the server-side storage of the tokens, i.e a database table of the above fields.
+type Store interface {
+	// Get returns the token of the "series", "found" is false if it does not exist.
+	Get(series string) (token Token, found bool, err error)
+	// Save inserts or updates the token.
+	Save(token Token) error
+	// Rotate updates the token only if its stored `Token#Hash` is still the "expectedHash",
+	// "ok" is false if it was rotated or removed by a concurrent request meanwhile.
+	// It should be atomic, i.e an UPDATE ... WHERE series = ? AND hash = ? of a database.
+	Rotate(token Token, expectedHash []byte) (ok bool, err error)
+	// Delete removes the token of the "series".
+	Delete(series string) error
+	// DeleteUser removes all of the tokens of the user, i.e on a theft.
+	DeleteUser(userID string) error
+}
+
+type memStore struct {
+	mu     sync.RWMutex
+	tokens map[string]Token
+}
+
+var _ Store = (*memStore)(nil)
+
+// NewMemStore returns a `Store` which keeps the tokens in memory,
+// they are lost on restart and they are not shared between the instances of the application.
+func NewMemStore() Store {
+	return &memStore{tokens: make(map[string]Token)}
+}
+
+func (s *memStore) Get(series string) (Token, bool, error) {
+	s.mu.RLock()
+	token, found := s.tokens[series]
+	s.mu.RUnlock()
+	return token, found, nil
+}
+
+func (s *memStore) Save(token Token) error {
+	s.mu.Lock()
+	s.save(token)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memStore) save(token Token) {
+	now := time.Now()
+	for series, t := range s.tokens {
+		// remove the expired ones too.
+		if t.Expires.Before(now) {
+			delete(s.tokens, series)
+		}
+	}
+	s.tokens[token.Series] = token
+}
+
+func (s *memStore) Rotate(token Token, expectedHash []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, found := s.tokens[token.Series]; !found || !hmac.Equal(t.Hash, expectedHash) {
+		return false, nil
+	}
+
+	s.save(token)
+	return true, nil
+}
+
+func (s *memStore) Delete(series string) error {
+	s.mu.Lock()
+	delete(s.tokens, series)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memStore) DeleteUser(userID string) error {
+	s.mu.Lock()
+	for series, t := range s.tokens {
+		if t.UserID == userID {
+			delete(s.tokens, series)
+		}
+	}
+	s.mu.Unlock()
+	return nil
+}
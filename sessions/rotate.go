@@ -0,0 +1,69 @@
+package sessions
+
+import (
+	"github.com/kataras/iris/context"
+)
+
+// RotateID issues a new id for the session, its values and flash messages are moved to it,
+// the old id is invalidated and the session's cookie is updated.
+// Call it after the authentication of the user, or any change of its privileges,
+// to protect from session fixation attacks, see `Authenticate` too.
+//
+// The values are moved atomically if the database implements the `IDRotator`.
+// The old cookies of the stateless sessions can not be invalidated, their values are kept by the clients.
+func (s *Session) RotateID(ctx context.Context) error {
+	return s.provider.manager.rotateID(ctx, s)
+}
+
+// Authenticate sets the "value" of the "key" which authenticates the user, i.e "user_id",
+// the session's id is rotated before, if the `Config#RotateIDOnAuthentication` is true.
+func (s *Session) Authenticate(ctx context.Context, key string, value interface{}) error {
+	if s.provider.manager.config.RotateIDOnAuthentication {
+		if err := s.RotateID(ctx); err != nil {
+			return err
+		}
+	}
+
+	s.Set(key, value)
+	return nil
+}
+
+func (s *Sessions) rotateID(ctx context.Context, sess *Session) error {
+	oldSid, newSid := sess.sid, s.config.SessionIDGenerator()
+
+	if s.config.Stateless {
+		sess.sid = newSid
+		sess.store.rotate(newSid)
+	} else {
+		if err := s.provider.rotate(sess, newSid); err != nil {
+			return err
+		}
+
+		expires := s.config.Expires
+		if !sess.Lifetime.IsZero() {
+			expires = sess.Lifetime.DurationUntilExpiration()
+		}
+
+		s.updateCookie(ctx, newSid, expires)
+		// the next `Start` of the request reads the new id.
+		replaceRequestCookie(ctx, s.config.cookieName(ctx), s.encodeCookieValue(newSid))
+	}
+
+	// the expiration policies are already applied to this request.
+	if key := touchedContextKey + s.config.Cookie; ctx.Values().GetString(key) == oldSid {
+		ctx.Values().Set(key, newSid)
+	}
+
+	fire(s.provider.updateListeners, ctx, sess)
+	return nil
+}
+
+// rotate changes the id of the stateless session and it writes its cookie.
+func (c *cookieStore) rotate(newSid string) {
+	c.mu.Lock()
+	c.id = newSid
+	if err := c.saveLocked(); err != nil {
+		c.logError("session: %v", err)
+	}
+	c.mu.Unlock()
+}
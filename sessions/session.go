@@ -1,6 +1,7 @@
 package sessions
 
 import (
+	"reflect"
 	"strconv"
 	"sync"
 
@@ -20,6 +21,8 @@ type (
 		mu       sync.RWMutex // for flashes.
 		Lifetime LifeTime
 		provider *provider
+		// store keeps the values of a stateless session, see `Config#Stateless`.
+		store *cookieStore
 	}
 
 	flashMessage struct {
@@ -38,7 +41,42 @@ type (
 //
 // Use the session's manager `Destroy(ctx)` in order to remove the cookie as well.
 func (s *Session) Destroy() {
-	s.provider.deleteSession(s)
+	if s.store != nil {
+		fire(s.provider.destroyListeners, s.store.ctx, s)
+		s.store.Release(s.sid)
+		return
+	}
+
+	s.provider.deleteSession(nil, s)
+}
+
+// database returns the storage of the session's values,
+// the session's cookie for stateless sessions or the provider's database.
+func (s *Session) database() Database {
+	if s.store != nil {
+		return s.store
+	}
+
+	return s.provider.db
+}
+
+// saveFlashes writes the flash messages of a stateless session to its cookie,
+// the ones which are removed on the next request are not included.
+func (s *Session) saveFlashes() {
+	if s.store == nil {
+		return
+	}
+
+	s.mu.RLock()
+	flashes := make(map[string]interface{}, len(s.flashes))
+	for key, v := range s.flashes {
+		if !v.shouldRemove {
+			flashes[key] = v.value
+		}
+	}
+	s.mu.RUnlock()
+
+	s.store.setFlashes(flashes)
 }
 
 // ID returns the session's ID.
@@ -54,7 +92,7 @@ func (s *Session) IsNew() bool {
 
 // Get returns a value based on its "key".
 func (s *Session) Get(key string) interface{} {
-	return s.provider.db.Get(s.sid, key)
+	return s.database().Get(s.sid, key)
 }
 
 // when running on the session manager removes any 'old' flash messages.
@@ -91,6 +129,7 @@ func (s *Session) GetFlash(key string) interface{} {
 		return nil
 	}
 	fv.shouldRemove = true
+	s.saveFlashes()
 	return fv.value
 }
 
@@ -117,6 +156,31 @@ func (s *Session) peekFlashMessage(key string) (*flashMessage, bool) {
 	return fv, true
 }
 
+// Decode decodes the value of the "key" to the "outPtr", i.e a pointer to a struct.
+// The value is converted by the `DefaultTranscoder` when it's not of the same type,
+// i.e when it's decoded to a map by the JSON transcoder of a database or of a stateless session,
+// so the structs are read the same way from all of the databases.
+func (s *Session) Decode(key string, outPtr interface{}) error {
+	return decode(s.Get(key), key, outPtr)
+}
+
+func decode(value interface{}, key string, outPtr interface{}) error {
+	if value == nil {
+		return errFindParse.Format(reflect.TypeOf(outPtr), key, value)
+	}
+
+	if assign(value, outPtr) == nil {
+		return nil
+	}
+
+	b, err := DefaultTranscoder.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return DefaultTranscoder.Unmarshal(b, outPtr)
+}
+
 // GetString same as Get but returns its string representation,
 // if key doesn't exist then it returns an empty string.
 func (s *Session) GetString(key string) string {
@@ -364,10 +428,12 @@ func (s *Session) GetBooleanDefault(key string, defaultValue bool) bool {
 
 // GetAll returns a copy of all session's values.
 func (s *Session) GetAll() map[string]interface{} {
-	items := make(map[string]interface{}, s.provider.db.Len(s.sid))
+	items := make(map[string]interface{}, s.database().Len(s.sid))
 	s.mu.RLock()
-	s.provider.db.Visit(s.sid, func(key string, value interface{}) {
-		items[key] = value
+	s.database().Visit(s.sid, func(key string, value interface{}) {
+		if key != metaKey {
+			items[key] = value
+		}
 	})
 	s.mu.RUnlock()
 	return items
@@ -383,20 +449,27 @@ func (s *Session) GetFlashes() map[string]interface{} {
 		v.shouldRemove = true
 	}
 	s.mu.Unlock()
+	s.saveFlashes()
 	return flashes
 }
 
 // Visit loops each of the entries and calls the callback function func(key, value).
 func (s *Session) Visit(cb func(k string, v interface{})) {
-	s.provider.db.Visit(s.sid, cb)
+	s.database().Visit(s.sid, func(key string, value interface{}) {
+		if key != metaKey {
+			cb(key, value)
+		}
+	})
 }
 
 func (s *Session) set(key string, value interface{}, immutable bool) {
-	s.provider.db.Set(s.sid, s.Lifetime, key, value, immutable)
+	s.database().Set(s.sid, s.Lifetime, key, value, immutable)
 
 	s.mu.Lock()
 	s.isNew = false
 	s.mu.Unlock()
+
+	s.fireUpdate()
 }
 
 // Set fills the session with an entry "value", based on its "key".
@@ -438,16 +511,19 @@ func (s *Session) SetFlash(key string, value interface{}) {
 	s.mu.Lock()
 	s.flashes[key] = &flashMessage{value: value}
 	s.mu.Unlock()
+	s.saveFlashes()
 }
 
 // Delete removes an entry by its key,
 // returns true if actually something was removed.
 func (s *Session) Delete(key string) bool {
-	removed := s.provider.db.Delete(s.sid, key)
+	removed := s.database().Delete(s.sid, key)
 	if removed {
 		s.mu.Lock()
 		s.isNew = false
 		s.mu.Unlock()
+
+		s.fireUpdate()
 	}
 
 	return removed
@@ -458,14 +534,24 @@ func (s *Session) DeleteFlash(key string) {
 	s.mu.Lock()
 	delete(s.flashes, key)
 	s.mu.Unlock()
+	s.saveFlashes()
 }
 
 // Clear removes all entries.
 func (s *Session) Clear() {
+	// keep the creation and the last access time of the expiration policies.
+	created, accessed, hasMeta := s.meta()
+
 	s.mu.Lock()
-	s.provider.db.Clear(s.sid)
+	s.database().Clear(s.sid)
 	s.isNew = false
 	s.mu.Unlock()
+
+	if hasMeta {
+		s.setMeta(created, accessed)
+	}
+
+	s.fireUpdate()
 }
 
 // ClearFlashes removes all flash messages.
@@ -475,4 +561,5 @@ func (s *Session) ClearFlashes() {
 		delete(s.flashes, key)
 	}
 	s.mu.Unlock()
+	s.saveFlashes()
 }
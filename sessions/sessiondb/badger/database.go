@@ -20,6 +20,67 @@ var (
 	DefaultFileMode = 0755
 )
 
+// Config contains the options of the badger session database, the zero values are the defaults.
+type Config struct {
+	// GCInterval is the interval of the garbage collection of the value log files,
+	// which reclaims the disk space of the removed and the expired sessions, a negative value disables it.
+	//
+	// Defaults to 10 minutes.
+	GCInterval time.Duration
+	// GCDiscardRatio is the minimum ratio of the stale data of a value log file to be rewritten by the garbage collection,
+	// a lower one reclaims more disk space with more disk writes, it should be between 0 and 1.
+	//
+	// Defaults to 0.5.
+	GCDiscardRatio float64
+	// ValueLogFileSize is the maximum size of a value log file in bytes,
+	// the garbage collection rewrites whole files, so the smaller ones are reclaimed sooner.
+	//
+	// Defaults to 64MB.
+	ValueLogFileSize int64
+	// ValueThreshold is the size, in bytes, of the values which are stored in the value log instead of the LSM tree.
+	//
+	// Defaults to the badger's 20 bytes.
+	ValueThreshold int
+	// MaxTableSize is the maximum size of a table of the LSM tree in bytes.
+	//
+	// Defaults to the badger's 64MB.
+	MaxTableSize int64
+	// NumCompactors is the number of the goroutines of the LSM tree's compaction.
+	//
+	// Defaults to the badger's 3.
+	NumCompactors int
+	// NoSyncWrites does not sync the writes to the disk, faster but the last ones can be lost on a crash.
+	//
+	// Defaults to false.
+	NoSyncWrites bool
+	// ReadOnly does not modify the database, the sessions are only read, i.e for a source of the `sessions.Migrate`.
+	// Note that the directory is still locked by this process.
+	//
+	// Defaults to false.
+	ReadOnly bool
+	// EncryptionKeys encrypt the sessions' values at rest, see `sessions.NewEncryptedTranscoder`,
+	// the sessions' ids are not encrypted.
+	//
+	// Defaults to nil, the values are not encrypted.
+	EncryptionKeys [][]byte
+}
+
+func (c Config) validate() Config {
+	if c.GCInterval == 0 {
+		c.GCInterval = 10 * time.Minute
+	}
+
+	if c.GCDiscardRatio <= 0 || c.GCDiscardRatio >= 1 {
+		c.GCDiscardRatio = 0.5
+	}
+
+	if c.ValueLogFileSize <= 0 {
+		c.ValueLogFileSize = 64 << 20
+	}
+
+	return c
+}
+
 // Database the badger(key-value file-based) session storage.
 type Database struct {
 	// Service is the underline badger database connection,
@@ -27,22 +88,38 @@ type Database struct {
 	// Can be used to get stats.
 	Service *badger.DB
 
+	config Config
+	// transcoder is the encrypted transcoder of the `Config#EncryptionKeys`, if any.
+	transcoder sessions.Transcoder
+
 	closed uint32 // if 1 is closed.
+	done   chan struct{}
 }
 
-var _ sessions.Database = (*Database)(nil)
+var (
+	_ sessions.Database = (*Database)(nil)
+	_ sessions.Ranger   = (*Database)(nil)
+)
 
 // New creates and returns a new badger(key-value file-based) storage
 // instance based on the "directoryPath".
 // DirectoryPath should is the directory which the badger database will store the sessions,
 // i.e ./sessions
 //
+// The optional "cfg" tunes the database, see `Config`.
+//
 // It will remove any old session files.
-func New(directoryPath string) (*Database, error) {
+func New(directoryPath string, cfg ...Config) (*Database, error) {
 	if directoryPath == "" {
 		return nil, errors.New("directoryPath is missing")
 	}
 
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	c = c.validate()
+
 	lindex := directoryPath[len(directoryPath)-1]
 	if lindex != os.PathSeparator && lindex != '/' {
 		directoryPath += string(os.PathSeparator)
@@ -55,6 +132,17 @@ func New(directoryPath string) (*Database, error) {
 	opts := badger.DefaultOptions
 	opts.Dir = directoryPath
 	opts.ValueDir = directoryPath
+	opts.ValueLogFileSize = c.ValueLogFileSize
+	opts.SyncWrites = !c.NoSyncWrites
+	if c.ValueThreshold > 0 {
+		opts.ValueThreshold = c.ValueThreshold
+	}
+	if c.MaxTableSize > 0 {
+		opts.MaxTableSize = c.MaxTableSize
+	}
+	if c.NumCompactors > 0 {
+		opts.NumCompactors = c.NumCompactors
+	}
 
 	service, err := badger.Open(opts)
 
@@ -63,17 +151,82 @@ func New(directoryPath string) (*Database, error) {
 		return nil, err
 	}
 
-	return NewFromDB(service), nil
+	db, err := newDB(service, c)
+	if err != nil {
+		service.Close()
+		return nil, err
+	}
+
+	return db, nil
 }
 
-// NewFromDB same as `New` but accepts an already-created custom badger connection instead.
-func NewFromDB(service *badger.DB) *Database {
-	db := &Database{Service: service}
+// NewFromDB same as `New` but accepts an already-created custom badger connection instead,
+// the options of its opening are not modified, the rest of the "cfg" are used.
+// It panics if the `Config#EncryptionKeys` are invalid.
+func NewFromDB(service *badger.DB, cfg ...Config) *Database {
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	db, err := newDB(service, c.validate())
+	if err != nil {
+		// the values must not be stored unencrypted.
+		panic("badger session database: " + err.Error())
+	}
 
-	runtime.SetFinalizer(db, closeDB)
 	return db
 }
 
+func newDB(service *badger.DB, c Config) (*Database, error) {
+	db := &Database{Service: service, config: c, done: make(chan struct{})}
+
+	if len(c.EncryptionKeys) > 0 {
+		transcoder, err := sessions.NewEncryptedTranscoder(nil, c.EncryptionKeys...)
+		if err != nil {
+			return nil, err
+		}
+		db.transcoder = transcoder
+	}
+
+	if c.GCInterval > 0 && !c.ReadOnly {
+		// the loop does not reference the "db", so its finalizer can close it.
+		go gc(service, db.done, c.GCInterval, c.GCDiscardRatio)
+	}
+
+	runtime.SetFinalizer(db, closeDB)
+	return db, nil
+}
+
+// gc runs the garbage collection of the value log files every "interval", until the "done" is closed.
+func gc(service *badger.DB, done chan struct{}, interval time.Duration, discardRatio float64) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			// each call rewrites one file, repeat until there is none to rewrite (badger.ErrNoRewrite).
+			for {
+				if err := service.RunValueLogGC(discardRatio); err != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// getTranscoder returns the transcoder of the values, the `sessions.DefaultTranscoder` if they are not encrypted.
+func (db *Database) getTranscoder() sessions.Transcoder {
+	if db.transcoder != nil {
+		return db.transcoder
+	}
+
+	return sessions.DefaultTranscoder
+}
+
 // Acquire receives a session's lifetime from the database,
 // if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
 func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
@@ -87,6 +240,10 @@ func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime
 		return sessions.LifeTime{Time: time.Unix(int64(item.ExpiresAt()), 0)}
 	}
 
+	if db.config.ReadOnly {
+		return sessions.LifeTime{}
+	}
+
 	// not found, create an entry with ttl and return an empty lifetime, session manager will do its job.
 	if err != nil {
 		if err == badger.ErrKeyNotFound {
@@ -115,7 +272,11 @@ func makeKey(sid, key string) []byte {
 // Set sets a key value of a specific session.
 // Ignore the "immutable".
 func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
-	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
+	if db.config.ReadOnly {
+		return
+	}
+
+	valueBytes, err := db.getTranscoder().Marshal(value)
 	if err != nil {
 		golog.Error(err)
 		return
@@ -144,7 +305,7 @@ func (db *Database) Get(sid string, key string) (value interface{}) {
 			return err
 		}
 
-		return sessions.DefaultTranscoder.Unmarshal(valueBytes, &value)
+		return db.getTranscoder().Unmarshal(valueBytes, &value)
 	})
 
 	if err != nil && err != badger.ErrKeyNotFound {
@@ -174,7 +335,7 @@ func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
 		}
 
 		var value interface{}
-		if err = sessions.DefaultTranscoder.Unmarshal(valueBytes, &value); err != nil {
+		if err = db.getTranscoder().Unmarshal(valueBytes, &value); err != nil {
 			golog.Error(err)
 			continue
 		}
@@ -206,8 +367,64 @@ func (db *Database) Len(sid string) (n int) {
 	return
 }
 
+// Range calls the "cb" for each one of the sessions with its id and its lifetime,
+// it implements the `sessions.Ranger`, see `sessions.Migrate`.
+func (db *Database) Range(cb func(sid string, lifetime sessions.LifeTime) bool) error {
+	type entry struct {
+		sid       string
+		expiresAt uint64
+	}
+
+	var entries []entry
+	err := db.Service.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(iterOptionsNoValues)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			item := iter.Item()
+			key := item.Key()
+			// the entry of a session is the "$sid_" key with the same value, see `Acquire`.
+			if len(key) < 2 || key[len(key)-1] != delim {
+				continue
+			}
+
+			value, err := item.Value()
+			if err != nil {
+				return err
+			}
+
+			if bytes.Equal(key, value) {
+				entries = append(entries, entry{string(key[:len(key)-1]), item.ExpiresAt()})
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		var lifetime sessions.LifeTime
+		if e.expiresAt > 0 {
+			lifetime.Time = time.Unix(int64(e.expiresAt), 0)
+		}
+
+		if !cb(e.sid, lifetime) {
+			break
+		}
+	}
+
+	return nil
+}
+
 // Delete removes a session key value based on its key.
 func (db *Database) Delete(sid string, key string) (deleted bool) {
+	if db.config.ReadOnly {
+		return false
+	}
+
 	txn := db.Service.NewTransaction(true)
 	err := txn.Delete(makeKey(sid, key))
 	if err != nil {
@@ -219,6 +436,10 @@ func (db *Database) Delete(sid string, key string) (deleted bool) {
 
 // Clear removes all session key values but it keeps the session entry.
 func (db *Database) Clear(sid string) {
+	if db.config.ReadOnly {
+		return
+	}
+
 	prefix := makePrefix(sid)
 
 	txn := db.Service.NewTransaction(true)
@@ -235,6 +456,10 @@ func (db *Database) Clear(sid string) {
 // Release destroys the session, it clears and removes the session entry,
 // session manager will create a new session ID on the next request after this call.
 func (db *Database) Release(sid string) {
+	if db.config.ReadOnly {
+		return
+	}
+
 	// clear all $sid-$key.
 	db.Clear(sid)
 	// and remove the $sid.
@@ -249,14 +474,13 @@ func (db *Database) Close() error {
 }
 
 func closeDB(db *Database) error {
-	if atomic.LoadUint32(&db.closed) > 0 {
+	if !atomic.CompareAndSwapUint32(&db.closed, 0, 1) {
 		return nil
 	}
+	close(db.done)
 	err := db.Service.Close()
 	if err != nil {
 		golog.Warnf("closing the badger connection: %v", err)
-	} else {
-		atomic.StoreUint32(&db.closed, 1)
 	}
 	return err
 }
@@ -1,9 +1,11 @@
 package boltdb
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/coreos/bbolt"
@@ -19,6 +21,51 @@ var (
 	DefaultFileMode = 0755
 )
 
+// Config contains the options of the BoltDB session database, the zero values are the defaults.
+type Config struct {
+	// Timeout is the maximum duration of the wait for the file's lock, which is held by another process.
+	//
+	// Defaults to 20 seconds.
+	Timeout time.Duration
+	// GCInterval is the interval of the removal of the expired sessions, a negative value disables it,
+	// then they are removed on the next `New` only.
+	// The file does not shrink but the pages of the removed sessions are reused.
+	//
+	// Defaults to 10 minutes.
+	GCInterval time.Duration
+	// NoSync does not sync the file after each write, faster but the last ones can be lost on a crash.
+	//
+	// Defaults to false.
+	NoSync bool
+	// InitialMmapSize is the initial size, in bytes, of the memory map of the file,
+	// a large enough one avoids the blocking of the readers when the file grows.
+	//
+	// Defaults to 0, the size of the file.
+	InitialMmapSize int
+	// ReadOnly opens the file with a shared lock and does not modify it, so other processes
+	// with a read-only database can read it too, i.e for a source of the `sessions.Migrate`.
+	//
+	// Defaults to false.
+	ReadOnly bool
+	// EncryptionKeys encrypt the sessions' values and expirations at rest, see `sessions.NewEncryptedTranscoder`,
+	// the sessions' ids and the values' keys are not encrypted.
+	//
+	// Defaults to nil, the values are not encrypted.
+	EncryptionKeys [][]byte
+}
+
+func (c Config) validate() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = 20 * time.Second
+	}
+
+	if c.GCInterval == 0 {
+		c.GCInterval = 10 * time.Minute
+	}
+
+	return c
+}
+
 // Database the BoltDB(file-based) session storage.
 type Database struct {
 	table []byte
@@ -26,16 +73,31 @@ type Database struct {
 	// it's initialized at `New` or `NewFromDB`.
 	// Can be used to get stats.
 	Service *bolt.DB
+
+	config Config
+	// transcoder is the encrypted transcoder of the `Config#EncryptionKeys`, if any.
+	transcoder sessions.Transcoder
+
+	closeOnce sync.Once
+	done      chan struct{}
 }
 
+var (
+	_ sessions.Database = (*Database)(nil)
+	_ sessions.Ranger   = (*Database)(nil)
+	_ sessions.Sweeper  = (*Database)(nil)
+)
+
 var errPathMissing = errors.New("path is required")
 
 // New creates and returns a new BoltDB(file-based) storage
 // instance based on the "path".
 // Path should include the filename and the directory(aka fullpath), i.e sessions/store.db.
 //
+// The optional "cfg" tunes the database, see `Config`.
+//
 // It will remove any old session files.
-func New(path string, fileMode os.FileMode) (*Database, error) {
+func New(path string, fileMode os.FileMode, cfg ...Config) (*Database, error) {
 	if path == "" {
 		golog.Error(errPathMissing)
 		return nil, errPathMissing
@@ -45,39 +107,99 @@ func New(path string, fileMode os.FileMode) (*Database, error) {
 		fileMode = os.FileMode(DefaultFileMode)
 	}
 
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	c = c.validate()
+
 	// create directories if necessary
 	if err := os.MkdirAll(filepath.Dir(path), fileMode); err != nil {
 		golog.Errorf("error while trying to create the necessary directories for %s: %v", path, err)
 		return nil, err
 	}
 
-	service, err := bolt.Open(path, fileMode,
-		&bolt.Options{Timeout: 20 * time.Second},
-	)
+	service, err := bolt.Open(path, fileMode, &bolt.Options{
+		Timeout:         c.Timeout,
+		NoSync:          c.NoSync,
+		InitialMmapSize: c.InitialMmapSize,
+		ReadOnly:        c.ReadOnly,
+	})
 
 	if err != nil {
 		golog.Errorf("unable to initialize the BoltDB-based session database: %v", err)
 		return nil, err
 	}
 
-	return NewFromDB(service, "sessions")
+	return NewFromDB(service, "sessions", c)
 }
 
-// NewFromDB same as `New` but accepts an already-created custom boltdb connection instead.
-func NewFromDB(service *bolt.DB, bucketName string) (*Database, error) {
+// NewFromDB same as `New` but accepts an already-created custom boltdb connection instead,
+// the options of its opening are not modified, the rest of the "cfg" are used.
+func NewFromDB(service *bolt.DB, bucketName string, cfg ...Config) (*Database, error) {
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	c = c.validate()
+
 	bucket := []byte(bucketName)
 
+	db := &Database{table: bucket, Service: service, config: c, done: make(chan struct{})}
+
+	if len(c.EncryptionKeys) > 0 {
+		transcoder, err := sessions.NewEncryptedTranscoder(nil, c.EncryptionKeys...)
+		if err != nil {
+			return nil, err
+		}
+		db.transcoder = transcoder
+	}
+
+	if c.ReadOnly {
+		runtime.SetFinalizer(db, closeDB)
+		return db, nil
+	}
+
 	service.Update(func(tx *bolt.Tx) (err error) {
 		_, err = tx.CreateBucketIfNotExists(bucket)
 		return
 	})
 
-	db := &Database{table: bucket, Service: service}
+	if c.GCInterval > 0 {
+		// the loop does not reference the "db", so its finalizer can close it.
+		go gc(db.done, c.GCInterval, (&Database{table: bucket, Service: service, transcoder: db.transcoder}).cleanup)
+	}
 
 	runtime.SetFinalizer(db, closeDB)
 	return db, db.cleanup()
 }
 
+// gc calls the "cleanup" every "interval", until the "done" is closed.
+func gc(done chan struct{}, interval time.Duration, cleanup func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := cleanup(); err != nil {
+				golog.Debugf("unable to remove the expired sessions: %v", err)
+			}
+		}
+	}
+}
+
+// getTranscoder returns the transcoder of the values, the `sessions.DefaultTranscoder` if they are not encrypted.
+func (db *Database) getTranscoder() sessions.Transcoder {
+	if db.transcoder != nil {
+		return db.transcoder
+	}
+
+	return sessions.DefaultTranscoder
+}
+
 func (db *Database) getBucket(tx *bolt.Tx) *bolt.Bucket {
 	return tx.Bucket(db.table)
 }
@@ -103,18 +225,21 @@ func getExpirationBucketName(bsid []byte) []byte {
 	return append(bsid, append(delim, expirationBucketName...)...)
 }
 
-// Cleanup removes any invalid(have expired) session entries on initialization.
+// Cleanup removes any invalid(have expired) session entries on initialization
+// and every `Config#GCInterval`.
 func (db *Database) cleanup() error {
 	return db.Service.Update(func(tx *bolt.Tx) error {
 		b := db.getBucket(tx)
 		c := b.Cursor()
+		// the buckets are removed after the loop, the cursor would skip entries otherwise.
+		var expired [][]byte
 		// loop through all buckets, find one with expiration.
 		for bsid, v := c.First(); bsid != nil; bsid, v = c.Next() {
 			if len(bsid) == 0 { // empty key, continue to the next session bucket.
 				continue
 			}
 
-			expirationName := getExpirationBucketName(bsid)
+			expirationName := getExpirationBucketName([]byte(string(bsid)))
 			if bExp := b.Bucket(expirationName); bExp != nil { // has expiration.
 				_, expValue := bExp.Cursor().First() // the expiration bucket contains only one key(we don't care, see `Acquire`) value(time.Time) pair.
 				if expValue == nil {
@@ -123,34 +248,61 @@ func (db *Database) cleanup() error {
 				}
 
 				var expirationTime time.Time
-				if err := sessions.DefaultTranscoder.Unmarshal(expValue, &expirationTime); err != nil {
+				if err := db.getTranscoder().Unmarshal(expValue, &expirationTime); err != nil {
 					golog.Debugf("cleanup: unable to retrieve expiration value for '%s'", v)
 					continue
 				}
 
 				if expirationTime.Before(time.Now()) {
-					// expired, delete the expiration bucket.
-					if err := b.DeleteBucket(expirationName); err != nil {
-						golog.Debugf("cleanup: unable to destroy a session '%s'", bsid)
-						return err
-					}
-
-					// and the session bucket, if any.
-					return b.DeleteBucket(bsid)
+					expired = append(expired, []byte(string(bsid)))
 				}
 			}
 		}
 
+		for _, bsid := range expired {
+			// delete the expiration bucket.
+			if err := b.DeleteBucket(getExpirationBucketName(bsid)); err != nil {
+				golog.Debugf("cleanup: unable to destroy a session '%s'", bsid)
+				return err
+			}
+
+			// and the session bucket, if any.
+			b.DeleteBucket(bsid)
+		}
+
 		return nil
 	})
 }
 
+// Sweep removes the expired sessions, it's called every `Config#GCInterval` too,
+// it implements the `sessions.Sweeper`.
+func (db *Database) Sweep() error {
+	if db.config.ReadOnly {
+		return nil
+	}
+
+	return db.cleanup()
+}
+
 var expirationKey = []byte("exp") // it can be random.
 
 // Acquire receives a session's lifetime from the database,
 // if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
 func (db *Database) Acquire(sid string, expires time.Duration) (lifetime sessions.LifeTime) {
 	bsid := []byte(sid)
+	if db.config.ReadOnly {
+		// read the expiration of an existing session, a new one is not stored.
+		db.Service.View(func(tx *bolt.Tx) error {
+			if b := db.getBucket(tx).Bucket(getExpirationBucketName(bsid)); b != nil {
+				if _, expValue := b.Cursor().First(); expValue != nil {
+					return db.getTranscoder().Unmarshal(expValue, &lifetime.Time)
+				}
+			}
+			return nil
+		})
+		return
+	}
+
 	err := db.Service.Update(func(tx *bolt.Tx) (err error) {
 		root := db.getBucket(tx)
 
@@ -167,7 +319,7 @@ func (db *Database) Acquire(sid string, expires time.Duration) (lifetime session
 				}
 
 				expirationTime := time.Now().Add(expires)
-				timeBytes, err := sessions.DefaultTranscoder.Marshal(expirationTime)
+				timeBytes, err := db.getTranscoder().Marshal(expirationTime)
 				if err != nil {
 					golog.Debugf("unable to set an expiration value on session expiration bucket for '%s': %v", sid, err)
 					return err
@@ -188,7 +340,7 @@ func (db *Database) Acquire(sid string, expires time.Duration) (lifetime session
 			}
 
 			var expirationTime time.Time
-			if err = sessions.DefaultTranscoder.Unmarshal(expValue, &expirationTime); err != nil {
+			if err = db.getTranscoder().Unmarshal(expValue, &expirationTime); err != nil {
 				golog.Debugf("acquire: unable to retrieve expiration value for '%s', value was: '%s': %v", sid, expValue, err)
 				return
 			}
@@ -217,7 +369,11 @@ func makeKey(key string) []byte {
 // Set sets a key value of a specific session.
 // Ignore the "immutable".
 func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
-	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
+	if db.config.ReadOnly {
+		return
+	}
+
+	valueBytes, err := db.getTranscoder().Marshal(value)
 	if err != nil {
 		golog.Debug(err)
 		return
@@ -254,7 +410,7 @@ func (db *Database) Get(sid string, key string) (value interface{}) {
 			return nil
 		}
 
-		return sessions.DefaultTranscoder.Unmarshal(valueBytes, &value)
+		return db.getTranscoder().Unmarshal(valueBytes, &value)
 	})
 
 	if err != nil {
@@ -274,7 +430,7 @@ func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
 
 		return b.ForEach(func(k []byte, v []byte) error {
 			var value interface{}
-			if err := sessions.DefaultTranscoder.Unmarshal(v, &value); err != nil {
+			if err := db.getTranscoder().Unmarshal(v, &value); err != nil {
 				golog.Debugf("unable to retrieve value of key '%s' of '%s': %v", k, sid, err)
 				return err
 			}
@@ -300,10 +456,67 @@ func (db *Database) Len(sid string) (n int) {
 	return
 }
 
+// Range calls the "cb" for each one of the sessions with its id and its lifetime,
+// it implements the `sessions.Ranger`, see `sessions.Migrate`.
+func (db *Database) Range(cb func(sid string, lifetime sessions.LifeTime) bool) error {
+	type entry struct {
+		sid      string
+		lifetime sessions.LifeTime
+	}
+
+	var entries []entry
+	err := db.Service.View(func(tx *bolt.Tx) error {
+		root := db.getBucket(tx)
+		c := root.Cursor()
+		for bsid, v := c.First(); bsid != nil; bsid, v = c.Next() {
+			if v != nil || len(bsid) == 0 { // not a bucket.
+				continue
+			}
+
+			// skip the expiration buckets, they are read with their session bucket.
+			if suffix := getExpirationBucketName(nil); bytes.HasSuffix(bsid, suffix) &&
+				root.Bucket(bsid[:len(bsid)-len(suffix)]) != nil {
+				continue
+			}
+
+			e := entry{sid: string(bsid)}
+			// copy the key, it's owned by the transaction.
+			if bExp := root.Bucket(getExpirationBucketName([]byte(e.sid))); bExp != nil {
+				if _, expValue := bExp.Cursor().First(); expValue != nil {
+					if err := db.getTranscoder().Unmarshal(expValue, &e.lifetime.Time); err != nil {
+						golog.Debugf("range: unable to retrieve expiration value for '%s': %v", bsid, err)
+						continue
+					}
+				}
+			}
+
+			entries = append(entries, e)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if !cb(e.sid, e.lifetime) {
+			break
+		}
+	}
+
+	return nil
+}
+
 var errNotFound = errors.New("not found")
 
 // Delete removes a session key value based on its key.
 func (db *Database) Delete(sid string, key string) (deleted bool) {
+	if db.config.ReadOnly {
+		return false
+	}
+
 	err := db.Service.Update(func(tx *bolt.Tx) error {
 		b := db.getBucketForSession(tx, sid)
 		if b == nil {
@@ -318,6 +531,10 @@ func (db *Database) Delete(sid string, key string) (deleted bool) {
 
 // Clear removes all session key values but it keeps the session entry.
 func (db *Database) Clear(sid string) {
+	if db.config.ReadOnly {
+		return
+	}
+
 	db.Service.Update(func(tx *bolt.Tx) error {
 		b := db.getBucketForSession(tx, sid)
 		if b == nil {
@@ -333,6 +550,10 @@ func (db *Database) Clear(sid string) {
 // Release destroys the session, it clears and removes the session entry,
 // session manager will create a new session ID on the next request after this call.
 func (db *Database) Release(sid string) {
+	if db.config.ReadOnly {
+		return
+	}
+
 	db.Service.Update(func(tx *bolt.Tx) error {
 		// delete the session bucket.
 		b := db.getBucket(tx)
@@ -353,6 +574,7 @@ func (db *Database) Close() error {
 }
 
 func closeDB(db *Database) error {
+	db.closeOnce.Do(func() { close(db.done) })
 	err := db.Service.Close()
 	if err != nil {
 		golog.Warnf("closing the BoltDB connection: %v", err)
@@ -0,0 +1,357 @@
+package dynamodb
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/sessions"
+	"github.com/kataras/iris/sessions/sessiondb/dynamodb/service"
+)
+
+// Database the dynamodb back-end session database for the sessions.
+//
+// Each session has an entry item which keeps its expiration and an item for each of its values,
+// all of them are stored under the session id, as the partition key,
+// and they are removed by the native time to live of the dynamodb after their expiration.
+type Database struct {
+	// nerrors is the number of the errors, see `Errors`,
+	// first for the alignment of its atomic operations.
+	nerrors  uint64
+	dynamodb *service.Service
+}
+
+var (
+	_ sessions.Database          = (*Database)(nil)
+	_ sessions.ErrorCounter      = (*Database)(nil)
+	_ sessions.ExpirationUpdater = (*Database)(nil)
+	_ sessions.Locker            = (*Database)(nil)
+)
+
+// New returns a new dynamodb database.
+// Call the `CreateTable` to create the sessions' table, if it's missing.
+func New(cfg ...service.Config) *Database {
+	db := &Database{dynamodb: service.New(cfg...)}
+	if err := db.dynamodb.Ping(); err != nil {
+		if e, ok := err.(*service.Error); !ok || e.Type != "ResourceNotFoundException" {
+			golog.Debugf("error connecting to dynamodb: %v", err)
+			return nil
+		}
+	}
+	return db
+}
+
+// Config returns the configuration for the dynamodb bridge, you can change them.
+func (db *Database) Config() *service.Config {
+	return db.dynamodb.Config
+}
+
+// CreateTable creates the sessions' table, if it's missing, and it enables the time to live of its items.
+func (db *Database) CreateTable() error {
+	return db.dynamodb.CreateTable()
+}
+
+const (
+	// entryKey is the sort key of the session's entry.
+	entryKey = "$entry"
+	// lockKey is the sort key of the session's lock.
+	lockKey = "$lock"
+	// valuePrefix is the prefix of the sort keys of the session's values.
+	valuePrefix = "v:"
+)
+
+var attributeNames = map[string]string{
+	"#sid": service.PartitionKey,
+	"#k":   service.SortKey,
+	"#exp": service.TTLAttribute,
+}
+
+func itemKey(sid, key string) service.Item {
+	return service.Item{
+		service.PartitionKey: service.String(sid),
+		service.SortKey:      service.String(key),
+	}
+}
+
+// expired reports whether the "item" is expired but not yet removed by the dynamodb.
+func expired(item service.Item, now int64) bool {
+	exp := item.Int(service.TTLAttribute)
+	return exp > 0 && exp <= now
+}
+
+// Acquire receives a session's lifetime from the database,
+// if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
+func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
+	item, err := db.dynamodb.GetItem(itemKey(sid, entryKey))
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debug(err)
+		return sessions.LifeTime{}
+	}
+
+	now := time.Now().Unix()
+	if item != nil {
+		if !expired(item, now) {
+			return lifetimeOf(item)
+		}
+		// the values of the expired session may still exist.
+		db.Clear(sid)
+	}
+
+	// not found or expired, create an entry and return an empty lifetime, session manager will do its job.
+	entry := itemKey(sid, entryKey)
+	if expires > 0 {
+		entry[service.TTLAttribute] = service.Number(time.Now().Add(expires).Unix())
+	}
+
+	err = db.dynamodb.PutItem(service.Input{
+		Item: entry,
+		// a concurrent request may have created it.
+		ConditionExpression:       "attribute_not_exists(#k) OR (attribute_exists(#exp) AND #exp <= :now)",
+		ExpressionAttributeNames:  map[string]string{"#k": service.SortKey, "#exp": service.TTLAttribute},
+		ExpressionAttributeValues: service.Item{":now": service.Number(now)},
+	})
+	if service.IsConditionFailed(err) {
+		if item, err = db.dynamodb.GetItem(itemKey(sid, entryKey)); err == nil && item != nil {
+			return lifetimeOf(item)
+		}
+	}
+
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debug(err)
+	}
+
+	return sessions.LifeTime{} // session manager will handle the rest.
+}
+
+func lifetimeOf(item service.Item) sessions.LifeTime {
+	exp := item.Int(service.TTLAttribute)
+	if exp == 0 {
+		return sessions.LifeTime{}
+	}
+
+	return sessions.LifeTime{Time: time.Unix(exp, 0)}
+}
+
+// Set sets a key value of a specific session.
+// Ignore the "immutable".
+func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
+	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Error(err)
+		return
+	}
+
+	item := itemKey(sid, valuePrefix+key)
+	item["v"] = service.Binary(valueBytes)
+	if !lifetime.IsZero() {
+		item[service.TTLAttribute] = service.Number(lifetime.Time.Unix())
+	}
+
+	if err = db.dynamodb.PutItem(service.Input{Item: item}); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debug(err)
+	}
+}
+
+// Get retrieves a session value based on the key.
+func (db *Database) Get(sid string, key string) (value interface{}) {
+	item, err := db.dynamodb.GetItem(itemKey(sid, valuePrefix+key))
+	if err != nil || item == nil || expired(item, time.Now().Unix()) {
+		// not found.
+		return nil
+	}
+
+	if err = sessions.DefaultTranscoder.Unmarshal(item.Bytes("v"), &value); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to unmarshal value of key: '%s': %v", key, err)
+	}
+
+	return
+}
+
+// valuesInput returns the query of the session's values which are not expired.
+func valuesInput(sid string) service.Input {
+	return service.Input{
+		KeyConditionExpression:   "#sid = :sid AND begins_with(#k, :prefix)",
+		FilterExpression:         "attribute_not_exists(#exp) OR #exp > :now",
+		ExpressionAttributeNames: attributeNames,
+		ExpressionAttributeValues: service.Item{
+			":sid":    service.String(sid),
+			":prefix": service.String(valuePrefix),
+			":now":    service.Number(time.Now().Unix()),
+		},
+	}
+}
+
+// Visit loops through all session keys and values.
+func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
+	err := db.dynamodb.Query(valuesInput(sid), func(item service.Item) bool {
+		key := item.String(service.SortKey)[len(valuePrefix):]
+
+		var value interface{} // new value each time, we don't know what user will do in "cb".
+		if err := sessions.DefaultTranscoder.Unmarshal(item.Bytes("v"), &value); err != nil {
+			atomic.AddUint64(&db.nerrors, 1)
+			golog.Debugf("unable to unmarshal value of key: '%s': %v", key, err)
+			return true
+		}
+
+		cb(key, value)
+		return true
+	})
+
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to visit the values of session '%s': %v", sid, err)
+	}
+}
+
+// Len returns the length of the session's entries (keys).
+func (db *Database) Len(sid string) (n int) {
+	n, err := db.dynamodb.Count(valuesInput(sid))
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to count the values of session '%s': %v", sid, err)
+	}
+
+	return
+}
+
+// Delete removes a session key value based on its key.
+func (db *Database) Delete(sid string, key string) (deleted bool) {
+	deleted, err := db.dynamodb.DeleteItem(service.Input{
+		Key: itemKey(sid, valuePrefix+key),
+		// report the concurrent deletions once.
+		ConditionExpression:      "attribute_exists(#k)",
+		ExpressionAttributeNames: map[string]string{"#k": service.SortKey},
+	})
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Error(err)
+	}
+
+	return
+}
+
+// keys returns the keys of the session's items, the "prefix" may be empty for all of them.
+func (db *Database) keys(sid, prefix string) (keys []service.Item, err error) {
+	in := service.Input{
+		KeyConditionExpression:    "#sid = :sid",
+		ProjectionExpression:      "#sid, #k",
+		ExpressionAttributeNames:  map[string]string{"#sid": service.PartitionKey, "#k": service.SortKey},
+		ExpressionAttributeValues: service.Item{":sid": service.String(sid)},
+	}
+
+	if prefix != "" {
+		in.KeyConditionExpression += " AND begins_with(#k, :prefix)"
+		in.ExpressionAttributeValues[":prefix"] = service.String(prefix)
+	}
+
+	err = db.dynamodb.Query(in, func(item service.Item) bool {
+		keys = append(keys, item)
+		return true
+	})
+
+	return
+}
+
+// Clear removes all session key values but it keeps the session entry.
+func (db *Database) Clear(sid string) {
+	keys, err := db.keys(sid, valuePrefix)
+	if err == nil {
+		err = db.dynamodb.BatchDelete(keys)
+	}
+
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to clear session '%s': %v", sid, err)
+	}
+}
+
+// Release destroys the session, it clears and removes the session entry,
+// session manager will create a new session ID on the next request after this call.
+func (db *Database) Release(sid string) {
+	keys, err := db.keys(sid, "")
+	if err == nil {
+		err = db.dynamodb.BatchDelete(keys)
+	}
+
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to release session '%s': %v", sid, err)
+	}
+}
+
+// OnUpdateExpiration updates the time to live of the session's items,
+// so they are not removed before the session expires.
+func (db *Database) OnUpdateExpiration(sid string, newExpires time.Duration) {
+	keys, err := db.keys(sid, "")
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to update the expiration of session '%s': %v", sid, err)
+		return
+	}
+
+	exp := service.Number(time.Now().Add(newExpires).Unix())
+	for _, key := range keys {
+		if key.String(service.SortKey) == lockKey {
+			// the lock expires by its own ttl.
+			continue
+		}
+
+		err = db.dynamodb.UpdateItem(service.Input{
+			Key:              key,
+			UpdateExpression: "SET #exp = :exp",
+			// do not re-create the items which are deleted concurrently.
+			ConditionExpression:       "attribute_exists(#k)",
+			ExpressionAttributeNames:  map[string]string{"#k": service.SortKey, "#exp": service.TTLAttribute},
+			ExpressionAttributeValues: service.Item{":exp": exp},
+		})
+
+		if err != nil && !service.IsConditionFailed(err) {
+			atomic.AddUint64(&db.nerrors, 1)
+			golog.Debugf("unable to update the expiration of session '%s': %v", sid, err)
+		}
+	}
+}
+
+// Errors returns the number of the errors of the database, i.e of its connection,
+// they are reported by the `sessions.Sessions#Stats`.
+func (db *Database) Errors() uint64 {
+	return atomic.LoadUint64(&db.nerrors)
+}
+
+// TryLock acquires the lock of the session if it's free or expired, it implements the `sessions.Locker`.
+func (db *Database) TryLock(sid, token string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	item := itemKey(sid, lockKey)
+	item["t"] = service.String(token)
+	// the expiration is in seconds, round it up.
+	item[service.TTLAttribute] = service.Number(now.Add(ttl + time.Second - 1).Unix())
+
+	err := db.dynamodb.PutItem(service.Input{
+		Item:                      item,
+		ConditionExpression:       "attribute_not_exists(#k) OR #exp <= :now",
+		ExpressionAttributeNames:  map[string]string{"#k": service.SortKey, "#exp": service.TTLAttribute},
+		ExpressionAttributeValues: service.Item{":now": service.Number(now.Unix())},
+	})
+	if service.IsConditionFailed(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Unlock releases the lock of the session if it's owned by the "token", it implements the `sessions.Locker`.
+func (db *Database) Unlock(sid, token string) error {
+	_, err := db.dynamodb.DeleteItem(service.Input{
+		Key:                       itemKey(sid, lockKey),
+		ConditionExpression:       "#t = :t",
+		ExpressionAttributeNames:  map[string]string{"#t": "t"},
+		ExpressionAttributeValues: service.Item{":t": service.String(token)},
+	})
+	return err
+}
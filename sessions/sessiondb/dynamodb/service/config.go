@@ -0,0 +1,59 @@
+package service
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	// DefaultDynamoDBRegion the dynamodb region option, "us-east-1"
+	DefaultDynamoDBRegion = "us-east-1"
+	// DefaultDynamoDBTable the dynamodb table option, "iris_sessions"
+	DefaultDynamoDBTable = "iris_sessions"
+	// DefaultDynamoDBTimeout the dynamodb timeout option, time.Duration(5) * time.Second
+	DefaultDynamoDBTimeout = time.Duration(5) * time.Second
+	// DefaultDynamoDBMaxRetries the dynamodb max retries option, 3
+	DefaultDynamoDBMaxRetries = 3
+)
+
+// Config the dynamodb configuration used inside sessions
+type Config struct {
+	// Region the AWS region of the table. Default the "AWS_REGION" environment variable or "us-east-1"
+	Region string
+	// Endpoint the URL of the dynamodb service, i.e "http://127.0.0.1:8000" for the DynamoDB Local.
+	// Default "https://dynamodb.{Region}.amazonaws.com"
+	Endpoint string
+	// AccessKeyID the AWS access key. Default the "AWS_ACCESS_KEY_ID" environment variable
+	AccessKeyID string
+	// SecretAccessKey the AWS secret key. Default the "AWS_SECRET_ACCESS_KEY" environment variable
+	SecretAccessKey string
+	// SessionToken the token of the temporary credentials, i.e of an AWS Lambda function.
+	// Default the "AWS_SESSION_TOKEN" environment variable
+	SessionToken string
+	// Table the name of the sessions' table, see `Service#CreateTable`. Default "iris_sessions"
+	Table string
+	// Timeout of each request, 0 no timeout. Default 5 seconds
+	Timeout time.Duration
+	// MaxRetries the retries of the throttled and of the failed, by the server, requests. Default 3
+	MaxRetries int
+}
+
+// DefaultConfig returns the default configuration for dynamodb service,
+// the credentials and the region are read by the standard AWS environment variables.
+func DefaultConfig() Config {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = DefaultDynamoDBRegion
+	}
+
+	return Config{
+		Region:          region,
+		Endpoint:        "",
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Table:           DefaultDynamoDBTable,
+		Timeout:         DefaultDynamoDBTimeout,
+		MaxRetries:      DefaultDynamoDBMaxRetries,
+	}
+}
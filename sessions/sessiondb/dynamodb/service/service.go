@@ -0,0 +1,449 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The key attributes of the sessions' table and its time to live attribute, see `CreateTable`.
+const (
+	// PartitionKey is the session id.
+	PartitionKey = "sid"
+	// SortKey is the key of the item inside its session.
+	SortKey = "k"
+	// TTLAttribute is the expiration of the item as unix seconds,
+	// the expired items are removed by the dynamodb, usually in a couple of days after their expiration.
+	TTLAttribute = "exp"
+)
+
+// ErrTableNotActive is returned by the `CreateTable` when the table was not created in time.
+var ErrTableNotActive = errors.New("dynamodb: table is not active")
+
+// Error is an error response of the dynamodb.
+type Error struct {
+	StatusCode int
+	// Type is the type of the error without its namespace, i.e "ConditionalCheckFailedException".
+	Type    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return "dynamodb: " + e.Type + ": " + e.Message
+}
+
+// IsConditionFailed reports whether the "err" is a failed condition of a conditional write.
+func IsConditionFailed(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.Type == "ConditionalCheckFailedException"
+}
+
+func (e *Error) retryable() bool {
+	switch e.Type {
+	case "ProvisionedThroughputExceededException", "ThrottlingException", "RequestLimitExceeded":
+		return true
+	}
+
+	return e.StatusCode >= http.StatusInternalServerError
+}
+
+// AttributeValue is a dynamodb value, only one of its fields is set.
+type AttributeValue struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// String returns a string attribute value.
+func String(s string) AttributeValue {
+	return AttributeValue{S: &s}
+}
+
+// Number returns a number attribute value.
+func Number(n int64) AttributeValue {
+	s := strconv.FormatInt(n, 10)
+	return AttributeValue{N: &s}
+}
+
+// Binary returns a binary attribute value.
+func Binary(b []byte) AttributeValue {
+	return AttributeValue{B: b}
+}
+
+// Item is the attributes of a dynamodb item, or of a key.
+type Item map[string]AttributeValue
+
+// String returns the string attribute of the item, or an empty string.
+func (item Item) String(name string) string {
+	if v := item[name].S; v != nil {
+		return *v
+	}
+
+	return ""
+}
+
+// Int returns the number attribute of the item, or zero.
+func (item Item) Int(name string) int64 {
+	if v := item[name].N; v != nil {
+		n, _ := strconv.ParseInt(*v, 10, 64)
+		return n
+	}
+
+	return 0
+}
+
+// Bytes returns the binary attribute of the item, or nil.
+func (item Item) Bytes(name string) []byte {
+	return item[name].B
+}
+
+// Input the parameters of the item operations, the TableName is filled by the service.
+// The expressions are documented at:
+// https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Expressions.html
+type Input struct {
+	TableName                 string            `json:"TableName"`
+	Key                       Item              `json:"Key,omitempty"`
+	Item                      Item              `json:"Item,omitempty"`
+	ConditionExpression       string            `json:"ConditionExpression,omitempty"`
+	UpdateExpression          string            `json:"UpdateExpression,omitempty"`
+	KeyConditionExpression    string            `json:"KeyConditionExpression,omitempty"`
+	FilterExpression          string            `json:"FilterExpression,omitempty"`
+	ProjectionExpression      string            `json:"ProjectionExpression,omitempty"`
+	ExpressionAttributeNames  map[string]string `json:"ExpressionAttributeNames,omitempty"`
+	ExpressionAttributeValues Item              `json:"ExpressionAttributeValues,omitempty"`
+	ConsistentRead            bool              `json:"ConsistentRead,omitempty"`
+	Select                    string            `json:"Select,omitempty"`
+	ExclusiveStartKey         Item              `json:"ExclusiveStartKey,omitempty"`
+	ReturnValues              string            `json:"ReturnValues,omitempty"`
+}
+
+type output struct {
+	Item             Item   `json:"Item"`
+	Attributes       Item   `json:"Attributes"`
+	Items            []Item `json:"Items"`
+	Count            int    `json:"Count"`
+	LastEvaluatedKey Item   `json:"LastEvaluatedKey"`
+}
+
+// Service the dynamodb service, contains the config and the http client.
+type Service struct {
+	// Config the dynamodb config for this dynamodb
+	Config   *Config
+	client   *http.Client
+	endpoint string
+}
+
+// New returns a dynamodb service filled by the passed config.
+func New(cfg ...Config) *Service {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if c.Region == "" {
+		c.Region = DefaultDynamoDBRegion
+	}
+
+	if c.Table == "" {
+		c.Table = DefaultDynamoDBTable
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://dynamodb." + c.Region + ".amazonaws.com"
+	}
+
+	return &Service{
+		Config:   &c,
+		client:   &http.Client{Timeout: c.Timeout},
+		endpoint: strings.TrimSuffix(endpoint, "/") + "/",
+	}
+}
+
+// Do sends the "operation", i.e "GetItem", with the "input" and it decodes its response to the "output",
+// the throttled and the failed, by the server, requests are retried.
+// The "output" may be nil.
+func (s *Service) Do(operation string, input interface{}, output interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = s.do(operation, body, output)
+		if e, ok := err.(*Error); !ok || !e.retryable() || attempt >= s.Config.MaxRetries {
+			return err
+		}
+
+		time.Sleep(time.Duration(50<<uint(attempt)) * time.Millisecond)
+	}
+}
+
+func (s *Service) do(operation string, body []byte, output interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "DynamoDB_20120810."+operation)
+	s.sign(req, body, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type         string `json:"__type"`
+			Message      string `json:"message"`
+			MessageUpper string `json:"Message"`
+		}
+		json.Unmarshal(b, &e)
+
+		// i.e "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException".
+		typ := e.Type[strings.LastIndexByte(e.Type, '#')+1:]
+		if typ == "" {
+			typ = http.StatusText(resp.StatusCode)
+		}
+
+		msg := e.Message
+		if msg == "" {
+			msg = e.MessageUpper
+		}
+
+		return &Error{StatusCode: resp.StatusCode, Type: typ, Message: msg}
+	}
+
+	if output == nil {
+		return nil
+	}
+
+	return json.Unmarshal(b, output)
+}
+
+func (s *Service) input(in Input) Input {
+	in.TableName = s.Config.Table
+	return in
+}
+
+// GetItem returns the item of the "key", it returns nil if it's not found.
+func (s *Service) GetItem(key Item) (Item, error) {
+	var out output
+	err := s.Do("GetItem", s.input(Input{Key: key, ConsistentRead: true}), &out)
+	if err != nil || len(out.Item) == 0 {
+		return nil, err
+	}
+
+	return out.Item, nil
+}
+
+// PutItem writes the "in.Item", if its "in.ConditionExpression" is true.
+// A failed condition returns an error which is reported by the `IsConditionFailed`.
+func (s *Service) PutItem(in Input) error {
+	return s.Do("PutItem", s.input(in), nil)
+}
+
+// UpdateItem updates, or creates, the item of the "in.Key" by its "in.UpdateExpression",
+// if its "in.ConditionExpression" is true.
+// A failed condition returns an error which is reported by the `IsConditionFailed`.
+func (s *Service) UpdateItem(in Input) error {
+	return s.Do("UpdateItem", s.input(in), nil)
+}
+
+// DeleteItem deletes the item of the "in.Key", if its "in.ConditionExpression" is true,
+// it reports whether the item existed.
+func (s *Service) DeleteItem(in Input) (bool, error) {
+	in.ReturnValues = "ALL_OLD"
+
+	var out output
+	if err := s.Do("DeleteItem", s.input(in), &out); err != nil {
+		if IsConditionFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(out.Attributes) > 0, nil
+}
+
+// Query loops through the items of the "in.KeyConditionExpression", page by page, until the "cb" returns false.
+func (s *Service) Query(in Input, cb func(item Item) bool) error {
+	in.ConsistentRead = true
+
+	for {
+		var out output
+		if err := s.Do("Query", s.input(in), &out); err != nil {
+			return err
+		}
+
+		for _, item := range out.Items {
+			if !cb(item) {
+				return nil
+			}
+		}
+
+		if len(out.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		in.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// Count returns the number of the items of the "in.KeyConditionExpression" and its "in.FilterExpression".
+func (s *Service) Count(in Input) (n int, err error) {
+	in.ConsistentRead = true
+	in.Select = "COUNT"
+
+	for {
+		var out output
+		if err = s.Do("Query", s.input(in), &out); err != nil {
+			return
+		}
+
+		n += out.Count
+		if len(out.LastEvaluatedKey) == 0 {
+			return
+		}
+		in.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// maxBatchWrite is the number of the requests of a "BatchWriteItem".
+const maxBatchWrite = 25
+
+// BatchDelete deletes the items of the "keys", 25 at a time.
+func (s *Service) BatchDelete(keys []Item) error {
+	type deleteRequest struct {
+		DeleteRequest struct {
+			Key Item `json:"Key"`
+		} `json:"DeleteRequest"`
+	}
+
+	type batch struct {
+		RequestItems map[string][]deleteRequest `json:"RequestItems"`
+	}
+
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > maxBatchWrite {
+			n = maxBatchWrite
+		}
+
+		requests := make([]deleteRequest, n)
+		for i, key := range keys[:n] {
+			requests[i].DeleteRequest.Key = key
+		}
+		keys = keys[n:]
+
+		in := batch{RequestItems: map[string][]deleteRequest{s.Config.Table: requests}}
+		for attempt := 0; len(in.RequestItems[s.Config.Table]) > 0; attempt++ {
+			if attempt > s.Config.MaxRetries {
+				return &Error{Type: "UnprocessedItems", Message: "batch delete was not completed"}
+			}
+
+			if attempt > 0 {
+				time.Sleep(time.Duration(50<<uint(attempt)) * time.Millisecond)
+			}
+
+			var out struct {
+				UnprocessedItems map[string][]deleteRequest `json:"UnprocessedItems"`
+			}
+			if err := s.Do("BatchWriteItem", in, &out); err != nil {
+				return err
+			}
+			in.RequestItems = out.UnprocessedItems
+		}
+	}
+
+	return nil
+}
+
+// Ping checks the connection and the credentials by describing the table.
+func (s *Service) Ping() error {
+	_, err := s.tableStatus()
+	return err
+}
+
+func (s *Service) tableStatus() (string, error) {
+	var out struct {
+		Table struct {
+			TableStatus string `json:"TableStatus"`
+		} `json:"Table"`
+	}
+
+	err := s.Do("DescribeTable", map[string]string{"TableName": s.Config.Table}, &out)
+	return out.Table.TableStatus, err
+}
+
+// CreateTable creates the sessions' table, on-demand billing, if it's missing
+// and it enables the time to live of its items, the expired sessions are removed by the dynamodb.
+func (s *Service) CreateTable() error {
+	type attr struct {
+		AttributeName string `json:"AttributeName"`
+		AttributeType string `json:"AttributeType,omitempty"`
+		KeyType       string `json:"KeyType,omitempty"`
+	}
+
+	in := map[string]interface{}{
+		"TableName":            s.Config.Table,
+		"AttributeDefinitions": []attr{{AttributeName: PartitionKey, AttributeType: "S"}, {AttributeName: SortKey, AttributeType: "S"}},
+		"KeySchema":            []attr{{AttributeName: PartitionKey, KeyType: "HASH"}, {AttributeName: SortKey, KeyType: "RANGE"}},
+		"BillingMode":          "PAY_PER_REQUEST",
+	}
+
+	if err := s.Do("CreateTable", in, nil); err != nil {
+		if e, ok := err.(*Error); !ok || e.Type != "ResourceInUseException" {
+			return err
+		}
+	}
+
+	for i := 0; ; i++ {
+		status, err := s.tableStatus()
+		if err != nil {
+			return err
+		}
+
+		if status == "ACTIVE" {
+			break
+		}
+
+		if i == 60 {
+			return ErrTableNotActive
+		}
+		time.Sleep(time.Second)
+	}
+
+	var ttl struct {
+		TimeToLiveDescription struct {
+			TimeToLiveStatus string `json:"TimeToLiveStatus"`
+		} `json:"TimeToLiveDescription"`
+	}
+	if err := s.Do("DescribeTimeToLive", map[string]string{"TableName": s.Config.Table}, &ttl); err != nil {
+		return err
+	}
+
+	if status := ttl.TimeToLiveDescription.TimeToLiveStatus; status == "ENABLED" || status == "ENABLING" {
+		return nil
+	}
+
+	return s.Do("UpdateTimeToLive", map[string]interface{}{
+		"TableName": s.Config.Table,
+		"TimeToLiveSpecification": map[string]interface{}{
+			"AttributeName": TTLAttribute,
+			"Enabled":       true,
+		},
+	}, nil)
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The requests of the AWS signature version 4 test suite:
+// https://docs.aws.amazon.com/general/latest/gr/signature-v4-test-suite.html
+func TestSignV4(t *testing.T) {
+	const (
+		accessKeyID     = "AKIDEXAMPLE"
+		secretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	)
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := []struct {
+		name, method, url string
+		headers           map[string]string
+		body              string
+		signedHeaders     string
+		signature         string
+	}{
+		{
+			name: "get-vanilla", method: http.MethodGet, url: "https://example.amazonaws.com/",
+			signedHeaders: "host;x-amz-date",
+			signature:     "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name: "post-vanilla", method: http.MethodPost, url: "https://example.amazonaws.com/",
+			signedHeaders: "host;x-amz-date",
+			signature:     "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name: "get-vanilla-query-order-key-case", method: http.MethodGet, url: "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			signedHeaders: "host;x-amz-date",
+			signature:     "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name: "post-x-www-form-urlencoded", method: http.MethodPost, url: "https://example.amazonaws.com/",
+			headers:       map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:          "Param1=value1",
+			signedHeaders: "content-type;host;x-amz-date",
+			signature:     "ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for name, value := range tt.headers {
+			req.Header.Set(name, value)
+		}
+
+		signV4(req, []byte(tt.body), now, accessKeyID, secretAccessKey, "us-east-1", "service")
+
+		expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+			"SignedHeaders=" + tt.signedHeaders + ", Signature=" + tt.signature
+		if got := req.Header.Get("Authorization"); got != expected {
+			t.Fatalf("[%s] expected the authorization:\n%s\nbut got:\n%s", tt.name, expected, got)
+		}
+	}
+}
+
+// testService returns a service of a fake dynamodb which replies to each request by the "handler".
+func testService(t *testing.T, handler func(operation string, body map[string]interface{}) (int, interface{})) (*Service, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), signAlgorithm+" Credential=id/") {
+			t.Errorf("expected the request to be signed but got: %s", r.Header.Get("Authorization"))
+		}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(b, &body)
+
+		status, reply := handler(strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810."), body)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(reply)
+	}))
+
+	s := New(Config{Endpoint: srv.URL, AccessKeyID: "id", SecretAccessKey: "secret", Table: "sessions", MaxRetries: 2})
+	return s, srv.Close
+}
+
+func TestServiceErrors(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+	)
+
+	s, closeFn := testService(t, func(operation string, body map[string]interface{}) (int, interface{}) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+
+		switch operation {
+		case "PutItem":
+			return http.StatusBadRequest, map[string]string{
+				"__type":  "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException",
+				"message": "The conditional request failed",
+			}
+		case "UpdateItem":
+			return http.StatusBadRequest, map[string]string{
+				"__type":  "com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException",
+				"Message": "Rate exceeded",
+			}
+		case "DeleteItem":
+			return http.StatusInternalServerError, nil
+		default:
+			return http.StatusOK, map[string]interface{}{}
+		}
+	})
+	defer closeFn()
+
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		n := attempts
+		attempts = 0
+		return n
+	}
+
+	err := s.PutItem(Input{Item: Item{PartitionKey: String("sid")}})
+	if !IsConditionFailed(err) {
+		t.Fatalf("expected a failed condition but got: %v", err)
+	}
+
+	if e := err.(*Error); e.StatusCode != http.StatusBadRequest || e.Message != "The conditional request failed" {
+		t.Fatalf("expected the error to be decoded but got: %#v", e)
+	}
+
+	if n := count(); n != 1 {
+		t.Fatalf("expected the failed condition to not be retried but got %d attempts", n)
+	}
+
+	err = s.UpdateItem(Input{Key: Item{PartitionKey: String("sid")}})
+	if e, ok := err.(*Error); !ok || e.Type != "ProvisionedThroughputExceededException" || e.Message != "Rate exceeded" {
+		t.Fatalf("expected a throttling error but got: %#v", err)
+	}
+
+	if n := count(); n != 3 {
+		t.Fatalf("expected the throttled request to be retried twice but got %d attempts", n)
+	}
+
+	_, err = s.DeleteItem(Input{Key: Item{PartitionKey: String("sid")}})
+	if e, ok := err.(*Error); !ok || e.StatusCode != http.StatusInternalServerError || e.Type != http.StatusText(http.StatusInternalServerError) {
+		t.Fatalf("expected a server error but got: %#v", err)
+	}
+
+	if n := count(); n != 3 {
+		t.Fatalf("expected the failed request to be retried twice but got %d attempts", n)
+	}
+}
+
+func TestServiceBatchDelete(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		deleted []string
+		batches []int
+		unproc  = 2 // the number of the unprocessed items of the first batch.
+	)
+
+	s, closeFn := testService(t, func(operation string, body map[string]interface{}) (int, interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests := body["RequestItems"].(map[string]interface{})["sessions"].([]interface{})
+		batches = append(batches, len(requests))
+
+		var unprocessed []interface{}
+		for i, r := range requests {
+			if i < unproc {
+				unprocessed = append(unprocessed, r)
+				continue
+			}
+
+			key := r.(map[string]interface{})["DeleteRequest"].(map[string]interface{})["Key"].(map[string]interface{})
+			deleted = append(deleted, key[SortKey].(map[string]interface{})["S"].(string))
+		}
+		unproc = 0
+
+		reply := map[string]interface{}{"UnprocessedItems": map[string]interface{}{}}
+		if len(unprocessed) > 0 {
+			reply["UnprocessedItems"] = map[string]interface{}{"sessions": unprocessed}
+		}
+		return http.StatusOK, reply
+	})
+	defer closeFn()
+
+	keys := make([]Item, 30)
+	for i := range keys {
+		keys[i] = Item{PartitionKey: String("sid"), SortKey: String(string(rune('a' + i)))}
+	}
+
+	if err := s.BatchDelete(keys); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if expected := []int{25, 2, 5}; len(batches) != len(expected) || batches[0] != expected[0] || batches[1] != expected[1] || batches[2] != expected[2] {
+		t.Fatalf("expected the batches %v but got: %v", expected, batches)
+	}
+
+	if len(deleted) != len(keys) {
+		t.Fatalf("expected %d deleted keys but got: %d", len(keys), len(deleted))
+	}
+
+	// the unprocessed items are retried until the max retries.
+	unproc = maxBatchWrite
+	s.Config.MaxRetries = 0
+	mu.Unlock()
+	err := s.BatchDelete(keys[:1])
+	mu.Lock()
+	if e, ok := err.(*Error); !ok || e.Type != "UnprocessedItems" {
+		t.Fatalf("expected the unprocessed items error but got: %v", err)
+	}
+}
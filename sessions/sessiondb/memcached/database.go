@@ -0,0 +1,385 @@
+package memcached
+
+import (
+	"bytes"
+	"math/rand"
+	"net/url"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/sessions"
+	"github.com/kataras/iris/sessions/sessiondb/memcached/service"
+)
+
+// Database the memcached back-end session database for the sessions.
+//
+// Memcached can not list its keys, so each session has an entry which keeps
+// its expiration and the keys of its values, it's updated by compare-and-swap.
+// All of the keys of a session are stored on the same server.
+type Database struct {
+	// nerrors is the number of the errors, see `Errors`,
+	// first for the alignment of its atomic operations.
+	nerrors   uint64
+	memcached *service.Service
+}
+
+var (
+	_ sessions.Database          = (*Database)(nil)
+	_ sessions.ErrorCounter      = (*Database)(nil)
+	_ sessions.ExpirationUpdater = (*Database)(nil)
+)
+
+// New returns a new memcached database.
+// The keys are distributed across the `service.Config#Servers` by consistent hashing.
+func New(cfg ...service.Config) *Database {
+	db := &Database{memcached: service.New(cfg...)}
+	if err := db.memcached.Ping(); err != nil {
+		golog.Debugf("error connecting to memcached: %v", err)
+		return nil
+	}
+	runtime.SetFinalizer(db, closeDB)
+	return db
+}
+
+// Config returns the configuration for the memcached server bridge, you can change them.
+func (db *Database) Config() *service.Config {
+	return db.memcached.Config
+}
+
+const delim = "_"
+
+// maxCASRetries is the number of the attempts to update the entry of a session.
+const maxCASRetries = 32
+
+// escapeKey escapes the spaces, the control characters and the new lines of the "key",
+// memcached keys can not contain them and they separate the keys of the `entry`.
+func escapeKey(key string) string {
+	return url.QueryEscape(key)
+}
+
+// sessionKey returns the key of the session's entry, the keys of its values start with it.
+// It's a hash tag, so all of the keys of a session are stored on the same server.
+func sessionKey(sid string) string {
+	return "{" + escapeKey(sid) + "}"
+}
+
+func makeKey(sid, key string) string {
+	return sessionKey(sid) + delim + escapeKey(key)
+}
+
+// entry is the value of the session's key, the first line is the expiration
+// as unix seconds, zero for none, and the rest of the lines are the escaped keys of the values.
+type entry struct {
+	expires int64
+	keys    []string
+}
+
+func parseEntry(b []byte) (e entry) {
+	lines := bytes.Split(b, []byte("\n"))
+	e.expires, _ = strconv.ParseInt(string(lines[0]), 10, 64)
+	for _, line := range lines[1:] {
+		if len(line) == 0 {
+			continue
+		}
+
+		key, err := url.QueryUnescape(string(line))
+		if err != nil {
+			// not escaped.
+			key = string(line)
+		}
+		e.keys = append(e.keys, key)
+	}
+
+	return
+}
+
+func (e entry) bytes() []byte {
+	var b bytes.Buffer
+	b.WriteString(strconv.FormatInt(e.expires, 10))
+	for _, key := range e.keys {
+		b.WriteByte('\n')
+		b.WriteString(escapeKey(key))
+	}
+
+	return b.Bytes()
+}
+
+// secondsLifetime returns the remaining lifetime of the entry, zero for none.
+func (e entry) secondsLifetime() int64 {
+	if e.expires <= 0 {
+		return 0
+	}
+
+	if seconds := e.expires - time.Now().Unix(); seconds > 0 {
+		return seconds
+	}
+
+	// it's expiring.
+	return 1
+}
+
+func (e *entry) add(key string) bool {
+	for _, k := range e.keys {
+		if k == key {
+			return false
+		}
+	}
+
+	e.keys = append(e.keys, key)
+	return true
+}
+
+func (e *entry) remove(key string) bool {
+	for i, k := range e.keys {
+		if k == key {
+			e.keys = append(e.keys[:i], e.keys[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// update changes the entry of the session by the "fn", it's not stored if the "fn" returns false.
+func (db *Database) update(sid string, fn func(e *entry) bool) error {
+	key := sessionKey(sid)
+
+	for i := 0; i < maxCASRetries; i++ {
+		if i > 0 {
+			// modified by a concurrent request, back off randomly so they don't collide again.
+			time.Sleep(time.Duration(rand.Int63n(int64(i)*int64(time.Millisecond))) + time.Millisecond)
+		}
+
+		item, err := db.memcached.Gets(key)
+		if err == service.ErrCacheMiss {
+			var e entry
+			if !fn(&e) {
+				return nil
+			}
+
+			if err = db.memcached.Add(key, e.bytes(), e.secondsLifetime()); err != service.ErrNotStored {
+				return err
+			}
+			// created by a concurrent request.
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		e := parseEntry(item.Value)
+		if !fn(&e) {
+			return nil
+		}
+
+		item.Value = e.bytes()
+		if err = db.memcached.CompareAndSwap(item, e.secondsLifetime()); err != service.ErrCASConflict && err != service.ErrCacheMiss {
+			return err
+		}
+	}
+
+	return service.ErrCASConflict
+}
+
+// Acquire receives a session's lifetime from the database,
+// if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
+func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
+	b, err := db.memcached.Get(sessionKey(sid))
+	if err == nil {
+		if e := parseEntry(b); e.expires > 0 {
+			return sessions.LifeTime{Time: time.Unix(e.expires, 0)}
+		}
+
+		return sessions.LifeTime{}
+	}
+
+	// not found, create an entry with ttl and return an empty lifetime, session manager will do its job.
+	var e entry
+	if expires > 0 {
+		e.expires = time.Now().Add(expires).Unix()
+	}
+
+	if err = db.memcached.Add(sessionKey(sid), e.bytes(), e.secondsLifetime()); err != nil && err != service.ErrNotStored {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debug(err)
+	}
+
+	return sessions.LifeTime{} // session manager will handle the rest.
+}
+
+// Set sets a key value of a specific session.
+// Ignore the "immutable".
+func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
+	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Error(err)
+		return
+	}
+
+	if err = db.memcached.Set(makeKey(sid, key), valueBytes, int64(lifetime.DurationUntilExpiration().Seconds())); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debug(err)
+		return
+	}
+
+	if err = db.update(sid, func(e *entry) bool { return e.add(key) }); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to add key: '%s' to session '%s': %v", key, sid, err)
+	}
+}
+
+// Get retrieves a session value based on the key.
+func (db *Database) Get(sid string, key string) (value interface{}) {
+	b, err := db.memcached.Get(makeKey(sid, key))
+	if err != nil {
+		// not found.
+		return
+	}
+
+	if err = sessions.DefaultTranscoder.Unmarshal(b, &value); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to unmarshal value of key: '%s': %v", key, err)
+	}
+
+	return
+}
+
+func (db *Database) keys(sid string) []string {
+	b, err := db.memcached.Get(sessionKey(sid))
+	if err != nil {
+		return nil
+	}
+
+	return parseEntry(b).keys
+}
+
+// Visit loops through all session keys and values,
+// the values are loaded with a single round trip.
+func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
+	keys := db.keys(sid)
+	if len(keys) == 0 {
+		return
+	}
+
+	memcachedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		memcachedKeys[i] = makeKey(sid, key)
+	}
+
+	values, err := db.memcached.GetMulti(memcachedKeys...)
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to get the values of session '%s': %v", sid, err)
+		return
+	}
+
+	for i, key := range keys {
+		b, ok := values[memcachedKeys[i]]
+		if !ok {
+			// expired or evicted.
+			continue
+		}
+
+		var value interface{} // new value each time, we don't know what user will do in "cb".
+		if err = sessions.DefaultTranscoder.Unmarshal(b, &value); err != nil {
+			atomic.AddUint64(&db.nerrors, 1)
+			golog.Debugf("unable to unmarshal value of key: '%s': %v", key, err)
+			continue
+		}
+
+		cb(key, value)
+	}
+}
+
+// Len returns the length of the session's entries (keys).
+func (db *Database) Len(sid string) (n int) {
+	return len(db.keys(sid))
+}
+
+// Delete removes a session key value based on its key.
+func (db *Database) Delete(sid string, key string) (deleted bool) {
+	err := db.memcached.Delete(makeKey(sid, key))
+	if err != nil && err != service.ErrCacheMiss {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Error(err)
+	}
+
+	if uErr := db.update(sid, func(e *entry) bool { return e.remove(key) }); uErr != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to remove key: '%s' from session '%s': %v", key, sid, uErr)
+	}
+
+	return err == nil
+}
+
+// Clear removes all session key values but it keeps the session entry.
+func (db *Database) Clear(sid string) {
+	for _, key := range db.keys(sid) {
+		if err := db.memcached.Delete(makeKey(sid, key)); err != nil && err != service.ErrCacheMiss {
+			atomic.AddUint64(&db.nerrors, 1)
+			golog.Debugf("unable to delete session '%s' value of key: '%s': %v", sid, key, err)
+		}
+	}
+
+	if err := db.update(sid, func(e *entry) bool {
+		e.keys = nil
+		return true
+	}); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to clear session '%s': %v", sid, err)
+	}
+}
+
+// Release destroys the session, it clears and removes the session entry,
+// session manager will create a new session ID on the next request after this call.
+func (db *Database) Release(sid string) {
+	for _, key := range db.keys(sid) {
+		db.memcached.Delete(makeKey(sid, key))
+	}
+
+	db.memcached.Delete(sessionKey(sid))
+}
+
+// OnUpdateExpiration updates the lifetime of the session's entry and of its values,
+// so they don't expire before the session.
+func (db *Database) OnUpdateExpiration(sid string, newExpires time.Duration) {
+	seconds := int64(newExpires.Seconds())
+	var keys []string
+	err := db.update(sid, func(e *entry) bool {
+		e.expires = time.Now().Add(newExpires).Unix()
+		keys = e.keys
+		return true
+	})
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to update the expiration of session '%s': %v", sid, err)
+		return
+	}
+
+	for _, key := range keys {
+		if err = db.memcached.Touch(makeKey(sid, key), seconds); err != nil && err != service.ErrCacheMiss {
+			atomic.AddUint64(&db.nerrors, 1)
+			golog.Debugf("unable to update the expiration of session '%s' value of key: '%s': %v", sid, key, err)
+		}
+	}
+}
+
+// Close terminates the memcached connections.
+func (db *Database) Close() error {
+	return closeDB(db)
+}
+
+func closeDB(db *Database) error {
+	return db.memcached.Close()
+}
+
+// Errors returns the number of the errors of the database, i.e of its connection,
+// they are reported by the `sessions.Sessions#Stats`.
+func (db *Database) Errors() uint64 {
+	return atomic.LoadUint64(&db.nerrors)
+}
@@ -0,0 +1,307 @@
+package memcached
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/sessions"
+	"github.com/kataras/iris/sessions/sessiondb/memcached/service"
+)
+
+// testServer is a fake memcached server which speaks the text protocol.
+type testServer struct {
+	ln net.Listener
+
+	mu    sync.Mutex
+	items map[string]*service.Item
+	cas   uint64
+	// dials is the number of the accepted connections.
+	dials int
+	// clientError makes the server to reply with a "CLIENT_ERROR" to the next command.
+	clientError bool
+}
+
+func newTestServer(t *testing.T) *testServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testServer{ln: ln, items: make(map[string]*service.Item)}
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.dials++
+			s.mu.Unlock()
+			go s.serve(nc)
+		}
+	}()
+
+	return s
+}
+
+func (s *testServer) addr() string { return s.ln.Addr().String() }
+
+func (s *testServer) Close() error { return s.ln.Close() }
+
+func (s *testServer) serve(nc net.Conn) {
+	defer nc.Close()
+	rw := bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc))
+
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+
+		s.mu.Lock()
+		clientError := s.clientError
+		s.clientError = false
+		s.mu.Unlock()
+
+		var data []byte
+		switch f[0] {
+		case "set", "add", "cas":
+			size, _ := strconv.Atoi(f[4])
+			data = make([]byte, size+2)
+			if _, err = io.ReadFull(rw, data); err != nil {
+				return
+			}
+			data = data[:size]
+		}
+
+		if clientError {
+			rw.WriteString("CLIENT_ERROR bad data chunk\r\n")
+		} else {
+			s.reply(rw, f, data)
+		}
+
+		if err = rw.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *testServer) reply(w io.Writer, f []string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch f[0] {
+	case "get", "gets":
+		for _, key := range f[1:] {
+			if it, ok := s.items[key]; ok {
+				if f[0] == "gets" {
+					fmt.Fprintf(w, "VALUE %s 0 %d %d\r\n%s\r\n", key, len(it.Value), it.CAS, it.Value)
+				} else {
+					fmt.Fprintf(w, "VALUE %s 0 %d\r\n%s\r\n", key, len(it.Value), it.Value)
+				}
+			}
+		}
+		io.WriteString(w, "END\r\n")
+	case "set", "add", "cas":
+		it, exists := s.items[f[1]]
+		switch {
+		case f[0] == "add" && exists:
+			io.WriteString(w, "NOT_STORED\r\n")
+			return
+		case f[0] == "cas" && !exists:
+			io.WriteString(w, "NOT_FOUND\r\n")
+			return
+		case f[0] == "cas" && f[5] != strconv.FormatUint(it.CAS, 10):
+			io.WriteString(w, "EXISTS\r\n")
+			return
+		}
+
+		s.cas++
+		s.items[f[1]] = &service.Item{Key: f[1], Value: data, CAS: s.cas}
+		io.WriteString(w, "STORED\r\n")
+	case "delete", "touch":
+		if _, ok := s.items[f[1]]; !ok {
+			io.WriteString(w, "NOT_FOUND\r\n")
+			return
+		}
+
+		if f[0] == "delete" {
+			delete(s.items, f[1])
+			io.WriteString(w, "DELETED\r\n")
+			return
+		}
+		io.WriteString(w, "TOUCHED\r\n")
+	case "version":
+		io.WriteString(w, "VERSION 1.5.0\r\n")
+	default:
+		io.WriteString(w, "ERROR\r\n")
+	}
+}
+
+func newTestService(s *testServer) *service.Service {
+	return service.New(service.Config{Servers: []string{s.addr()}, Timeout: time.Second})
+}
+
+func TestService(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+	m := newTestService(srv)
+	defer m.Close()
+
+	if _, err := m.Get("key"); err != service.ErrCacheMiss {
+		t.Fatalf("expected a cache miss but got: %v", err)
+	}
+
+	if err := m.Set("key", []byte("value"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Add("key", []byte("other"), 0); err != service.ErrNotStored {
+		t.Fatalf("expected the existing key to not be added but got: %v", err)
+	}
+
+	item, err := m.Gets("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(item.Value) != "value" {
+		t.Fatalf("expected the value to be 'value' but got: '%s'", item.Value)
+	}
+
+	if err = m.Set("key", []byte("modified"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	item.Value = []byte("swapped")
+	if err = m.CompareAndSwap(item, 0); err != service.ErrCASConflict {
+		t.Fatalf("expected a compare-and-swap conflict but got: %v", err)
+	}
+
+	values, err := m.GetMulti("key", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(values) != 1 || string(values["key"]) != "modified" {
+		t.Fatalf("expected only the existing key to be returned but got: %v", values)
+	}
+
+	if err = m.Touch("key", 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = m.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = m.Delete("key"); err != service.ErrCacheMiss {
+		t.Fatalf("expected a cache miss but got: %v", err)
+	}
+
+	for _, key := range []string{"with space", "with\nnew line", strings.Repeat("k", 251)} {
+		if err = m.Set(key, []byte("value"), 0); err != service.ErrMalformedKey {
+			t.Fatalf("expected the key %q to be malformed but got: %v", key, err)
+		}
+	}
+}
+
+func TestServiceClientError(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+	m := newTestService(srv)
+	defer m.Close()
+
+	if err := m.Set("key", []byte("value"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.mu.Lock()
+	srv.clientError = true
+	srv.mu.Unlock()
+
+	if err := m.Set("key", []byte("value"), 0); err == nil {
+		t.Fatalf("expected the client error to be returned")
+	}
+
+	if _, err := m.Get("key"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.mu.Lock()
+	dials := srv.dials
+	srv.mu.Unlock()
+	if dials != 2 {
+		t.Fatalf("expected the connection to be closed after the client error and a new one to be dialed but got %d dials", dials)
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	db := &Database{memcached: newTestService(srv)}
+	defer db.Close()
+
+	sid := "sid"
+	db.Acquire(sid, time.Hour)
+
+	keys := []string{"name", "with space", "with\nnew line", "%2B+"}
+	for i, key := range keys {
+		db.Set(sid, sessions.LifeTime{}, key, strconv.Itoa(i), false)
+	}
+
+	if n := db.Len(sid); n != len(keys) {
+		t.Fatalf("expected %d keys but got: %d", len(keys), n)
+	}
+
+	for i, key := range keys {
+		if got := db.Get(sid, key); got != strconv.Itoa(i) {
+			t.Fatalf("expected the value of %q to be '%d' but got: %v", key, i, got)
+		}
+	}
+
+	visited := make(map[string]interface{})
+	db.Visit(sid, func(key string, value interface{}) { visited[key] = value })
+	if len(visited) != len(keys) {
+		t.Fatalf("expected to visit %d values but got: %v", len(keys), visited)
+	}
+
+	if !db.Delete(sid, "with\nnew line") {
+		t.Fatalf("expected the value to be deleted")
+	}
+
+	if n := db.Len(sid); n != len(keys)-1 {
+		t.Fatalf("expected %d keys but got: %d", len(keys)-1, n)
+	}
+
+	db.Clear(sid)
+	if n := db.Len(sid); n != 0 {
+		t.Fatalf("expected the keys to be cleared but got: %d", n)
+	}
+
+	db.Release(sid)
+	srv.mu.Lock()
+	n := len(srv.items)
+	srv.mu.Unlock()
+	if n != 0 {
+		t.Fatalf("expected all of the items to be removed on release but got: %d", n)
+	}
+
+	if errors := db.Errors(); errors != 0 {
+		t.Fatalf("expected no errors but got: %d", errors)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"time"
+)
+
+const (
+	// DefaultMemcachedAddr the memcached server's address option, "127.0.0.1:11211"
+	DefaultMemcachedAddr = "127.0.0.1:11211"
+	// DefaultMemcachedTimeout the memcached timeout option, time.Duration(1) * time.Second
+	DefaultMemcachedTimeout = time.Duration(1) * time.Second
+	// DefaultMemcachedMaxIdle the memcached max idle connections option, 2
+	DefaultMemcachedMaxIdle = 2
+	// DefaultMemcachedVirtualNodes the memcached virtual nodes option, 160
+	DefaultMemcachedVirtualNodes = 160
+)
+
+// Config the memcached configuration used inside sessions
+type Config struct {
+	// Servers the addresses of the memcached servers, the keys are
+	// distributed across them by consistent hashing, so adding or removing a server
+	// moves only a part of the keys. Default []string{"127.0.0.1:11211"}
+	Servers []string
+	// Timeout of the dial and of each command, 0 no timeout. Default 1 second
+	Timeout time.Duration
+	// MaxIdle the idle connections of each server. Default 2
+	MaxIdle int
+	// VirtualNodes the points of each server on the hash ring,
+	// the more points the more evenly the keys are distributed. Default 160
+	VirtualNodes int
+	// Prefix "myprefix-for-this-website". Default ""
+	Prefix string
+}
+
+// DefaultConfig returns the default configuration for memcached service.
+func DefaultConfig() Config {
+	return Config{
+		Servers:      []string{DefaultMemcachedAddr},
+		Timeout:      DefaultMemcachedTimeout,
+		MaxIdle:      DefaultMemcachedMaxIdle,
+		VirtualNodes: DefaultMemcachedVirtualNodes,
+		Prefix:       "",
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ring is a consistent hashing ring of the servers.
+type ring struct {
+	points  []uint32
+	servers map[uint32]string
+}
+
+func newRing(servers []string, virtualNodes int) *ring {
+	r := &ring{servers: make(map[uint32]string, len(servers)*virtualNodes)}
+	for _, server := range servers {
+		for i := 0; i < virtualNodes; i++ {
+			p := crc32.ChecksumIEEE([]byte(server + "-" + strconv.Itoa(i)))
+			if _, exists := r.servers[p]; exists {
+				continue
+			}
+			r.servers[p] = server
+			r.points = append(r.points, p)
+		}
+	}
+
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// get returns the server of the "key", the first point of the ring after the key's hash.
+func (r *ring) get(key string) string {
+	h := crc32.ChecksumIEEE([]byte(hashTag(key)))
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.servers[r.points[i]]
+}
+
+// hashTag returns the part of the "key" which is hashed, if the key contains a hash tag,
+// i.e "{sid}_name", only the tag is hashed so the keys with the same tag are stored on the same server.
+func hashTag(key string) string {
+	if s := strings.IndexByte(key, '{'); s >= 0 {
+		if e := strings.IndexByte(key[s+1:], '}'); e > 0 {
+			return key[s+1 : s+1+e]
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,481 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCacheMiss is returned when the key is not found.
+	ErrCacheMiss = errors.New("memcached: cache miss")
+	// ErrNotStored is returned by the `Add` when the key already exists.
+	ErrNotStored = errors.New("memcached: item not stored")
+	// ErrCASConflict is returned by the `CompareAndSwap` when the item was modified after it was read.
+	ErrCASConflict = errors.New("memcached: compare-and-swap conflict")
+	// ErrMalformedKey is returned when the key is longer than 250 bytes or it contains spaces or control characters.
+	ErrMalformedKey = errors.New("memcached: malformed key")
+	// ErrClosed is returned when the service is closed.
+	ErrClosed = errors.New("memcached: service is closed")
+)
+
+// maxRelativeExpiration is the maximum expiration in seconds which is relative to the current time,
+// memcached reads the greater ones as unix timestamps.
+const maxRelativeExpiration = 60 * 60 * 24 * 30
+
+// expiration maps the "seconds" of the lifetime to the memcached's expiration, 0 means no expiration.
+func expiration(seconds int64) int64 {
+	if seconds <= 0 {
+		return 0
+	}
+
+	if seconds > maxRelativeExpiration {
+		return time.Now().Unix() + seconds
+	}
+
+	return seconds
+}
+
+// Item is a value of the memcached.
+type Item struct {
+	// Key is the key of the item, without the `Config#Prefix`.
+	Key string
+	// Value is the value of the item.
+	Value []byte
+	// CAS is the version of the item, it's filled by the `Gets`
+	// and it's checked by the `CompareAndSwap`.
+	CAS uint64
+}
+
+type conn struct {
+	nc   net.Conn
+	rw   *bufio.ReadWriter
+	addr string
+}
+
+// Service the memcached service, contains the config and the connections of the servers.
+type Service struct {
+	// Config the memcached config for this memcached
+	Config *Config
+	ring   *ring
+
+	mu     sync.Mutex
+	idle   map[string][]*conn
+	closed bool
+}
+
+// New returns a memcached service filled by the passed config,
+// the connections are opened on demand.
+func New(cfg ...Config) *Service {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if len(c.Servers) == 0 {
+		c.Servers = []string{DefaultMemcachedAddr}
+	}
+
+	if c.MaxIdle <= 0 {
+		c.MaxIdle = DefaultMemcachedMaxIdle
+	}
+
+	if c.VirtualNodes <= 0 {
+		c.VirtualNodes = DefaultMemcachedVirtualNodes
+	}
+
+	return &Service{
+		Config: &c,
+		ring:   newRing(c.Servers, c.VirtualNodes),
+		idle:   make(map[string][]*conn),
+	}
+}
+
+func (s *Service) getConn(addr string) (*conn, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrClosed
+	}
+
+	if conns := s.idle[addr]; len(conns) > 0 {
+		c := conns[len(conns)-1]
+		s.idle[addr] = conns[:len(conns)-1]
+		s.mu.Unlock()
+		return c, nil
+	}
+	s.mu.Unlock()
+
+	nc, err := net.DialTimeout("tcp", addr, s.Config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{nc: nc, rw: bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc)), addr: addr}, nil
+}
+
+// resumable reports whether the connection can be reused after the "err".
+// The connection is closed after a "CLIENT_ERROR" or an "ERROR" reply,
+// the server may still read the rest of the command as a new one and the replies would be out of sync.
+func resumable(err error) bool {
+	switch err {
+	case nil, ErrCacheMiss, ErrNotStored, ErrCASConflict:
+		return true
+	}
+
+	serverErr, isServerErr := err.(serverError)
+	return isServerErr && strings.HasPrefix(string(serverErr), "SERVER_ERROR")
+}
+
+func (s *Service) putConn(c *conn, err error) {
+	if !resumable(err) {
+		c.nc.Close()
+		return
+	}
+
+	s.mu.Lock()
+	if !s.closed && len(s.idle[c.addr]) < s.Config.MaxIdle {
+		s.idle[c.addr] = append(s.idle[c.addr], c)
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	c.nc.Close()
+}
+
+// with runs the "fn" with a connection to the "addr".
+func (s *Service) with(addr string, fn func(c *conn) error) error {
+	c, err := s.getConn(addr)
+	if err != nil {
+		return err
+	}
+
+	if s.Config.Timeout > 0 {
+		c.nc.SetDeadline(time.Now().Add(s.Config.Timeout))
+	}
+
+	err = fn(c)
+	s.putConn(c, err)
+	return err
+}
+
+// key returns the "key" with the prefix, it fails if the key is not valid.
+func (s *Service) key(key string) (string, error) {
+	key = s.Config.Prefix + key
+	if len(key) == 0 || len(key) > 250 {
+		return "", ErrMalformedKey
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return "", ErrMalformedKey
+		}
+	}
+
+	return key, nil
+}
+
+// serverError is an error reply of the memcached,
+// i.e "SERVER_ERROR out of memory storing object", see `resumable`.
+type serverError string
+
+func (e serverError) Error() string {
+	return "memcached: " + string(e)
+}
+
+func replyError(line []byte) error {
+	reply := strings.TrimSpace(string(line))
+	switch {
+	case strings.HasPrefix(reply, "SERVER_ERROR"), strings.HasPrefix(reply, "CLIENT_ERROR"), reply == "ERROR":
+		return serverError(reply)
+	default:
+		return fmt.Errorf("memcached: unexpected reply: %q", reply)
+	}
+}
+
+var (
+	crlf       = []byte("\r\n")
+	replyEnd   = []byte("END\r\n")
+	replyValue = []byte("VALUE ")
+)
+
+// readItems reads the items of a "get" or "gets" reply.
+func (s *Service) readItems(c *conn, cb func(*Item)) error {
+	for {
+		line, err := c.rw.ReadSlice('\n')
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(line, replyEnd) {
+			return nil
+		}
+
+		if !bytes.HasPrefix(line, replyValue) {
+			return replyError(line)
+		}
+
+		// VALUE <key> <flags> <bytes> [<cas unique>]\r\n
+		f := strings.Fields(string(line))
+		if len(f) < 4 {
+			return replyError(line)
+		}
+
+		size, err := strconv.Atoi(f[3])
+		if err != nil {
+			return replyError(line)
+		}
+
+		it := &Item{Key: strings.TrimPrefix(f[1], s.Config.Prefix), Value: make([]byte, size+2)}
+		if len(f) > 4 {
+			it.CAS, _ = strconv.ParseUint(f[4], 10, 64)
+		}
+
+		if _, err = io.ReadFull(c.rw, it.Value); err != nil {
+			return err
+		}
+
+		if !bytes.HasSuffix(it.Value, crlf) {
+			return replyError(it.Value)
+		}
+
+		it.Value = it.Value[:size]
+		cb(it)
+	}
+}
+
+func (s *Service) get(verb string, key string) (*Item, error) {
+	key, err := s.key(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var item *Item
+	err = s.with(s.ring.get(key), func(c *conn) error {
+		if _, err := fmt.Fprintf(c.rw, "%s %s\r\n", verb, key); err != nil {
+			return err
+		}
+
+		if err := c.rw.Flush(); err != nil {
+			return err
+		}
+
+		return s.readItems(c, func(it *Item) { item = it })
+	})
+
+	if err == nil && item == nil {
+		err = ErrCacheMiss
+	}
+
+	return item, err
+}
+
+// Get returns the value of the "key" or `ErrCacheMiss` if it's missing.
+func (s *Service) Get(key string) ([]byte, error) {
+	item, err := s.get("get", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.Value, nil
+}
+
+// Gets returns the item of the "key", with its version, or `ErrCacheMiss` if it's missing,
+// see `CompareAndSwap`.
+func (s *Service) Gets(key string) (*Item, error) {
+	return s.get("gets", key)
+}
+
+// GetMulti returns the values of the keys, the missing keys are omitted.
+// The keys of each server are loaded with a single round trip.
+func (s *Service) GetMulti(keys ...string) (map[string][]byte, error) {
+	byServer := make(map[string][]string)
+	for _, key := range keys {
+		key, err := s.key(key)
+		if err != nil {
+			return nil, err
+		}
+		addr := s.ring.get(key)
+		byServer[addr] = append(byServer[addr], key)
+	}
+
+	values := make(map[string][]byte, len(keys))
+	for addr, keys := range byServer {
+		err := s.with(addr, func(c *conn) error {
+			if _, err := fmt.Fprintf(c.rw, "get %s\r\n", strings.Join(keys, " ")); err != nil {
+				return err
+			}
+
+			if err := c.rw.Flush(); err != nil {
+				return err
+			}
+
+			return s.readItems(c, func(it *Item) { values[it.Key] = it.Value })
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (s *Service) store(verb string, key string, value []byte, secondsLifetime int64, cas uint64) error {
+	key, err := s.key(key)
+	if err != nil {
+		return err
+	}
+
+	return s.with(s.ring.get(key), func(c *conn) error {
+		var err error
+		if verb == "cas" {
+			_, err = fmt.Fprintf(c.rw, "cas %s 0 %d %d %d\r\n", key, expiration(secondsLifetime), len(value), cas)
+		} else {
+			_, err = fmt.Fprintf(c.rw, "%s %s 0 %d %d\r\n", verb, key, expiration(secondsLifetime), len(value))
+		}
+		if err != nil {
+			return err
+		}
+
+		c.rw.Write(value)
+		c.rw.Write(crlf)
+		if err = c.rw.Flush(); err != nil {
+			return err
+		}
+
+		line, err := c.rw.ReadSlice('\n')
+		if err != nil {
+			return err
+		}
+
+		switch string(line) {
+		case "STORED\r\n":
+			return nil
+		case "NOT_STORED\r\n":
+			return ErrNotStored
+		case "EXISTS\r\n":
+			return ErrCASConflict
+		case "NOT_FOUND\r\n":
+			return ErrCacheMiss
+		default:
+			return replyError(line)
+		}
+	})
+}
+
+// Set sets a key-value to the memcached, the key expires after the "secondsLifetime", 0 means no expiration.
+func (s *Service) Set(key string, value []byte, secondsLifetime int64) error {
+	return s.store("set", key, value, secondsLifetime, 0)
+}
+
+// Add is like the `Set` but it fails with `ErrNotStored` if the key already exists.
+func (s *Service) Add(key string, value []byte, secondsLifetime int64) error {
+	return s.store("add", key, value, secondsLifetime, 0)
+}
+
+// CompareAndSwap sets the value of the "item" if it's not modified since it was read by the `Gets`,
+// otherwise it fails with `ErrCASConflict`, or `ErrCacheMiss` if it was removed.
+func (s *Service) CompareAndSwap(item *Item, secondsLifetime int64) error {
+	return s.store("cas", item.Key, item.Value, secondsLifetime, item.CAS)
+}
+
+func (s *Service) command(key string, format string, expected string, args ...interface{}) error {
+	key, err := s.key(key)
+	if err != nil {
+		return err
+	}
+
+	return s.with(s.ring.get(key), func(c *conn) error {
+		if _, err := fmt.Fprintf(c.rw, format, append([]interface{}{key}, args...)...); err != nil {
+			return err
+		}
+
+		if err := c.rw.Flush(); err != nil {
+			return err
+		}
+
+		line, err := c.rw.ReadSlice('\n')
+		if err != nil {
+			return err
+		}
+
+		switch string(line) {
+		case expected:
+			return nil
+		case "NOT_FOUND\r\n":
+			return ErrCacheMiss
+		default:
+			return replyError(line)
+		}
+	})
+}
+
+// Delete removes the "key", it fails with `ErrCacheMiss` if it's missing.
+func (s *Service) Delete(key string) error {
+	return s.command(key, "delete %s\r\n", "DELETED\r\n")
+}
+
+// Touch updates the lifetime of the "key", it fails with `ErrCacheMiss` if it's missing.
+func (s *Service) Touch(key string, secondsLifetime int64) error {
+	return s.command(key, "touch %s %d\r\n", "TOUCHED\r\n", expiration(secondsLifetime))
+}
+
+// Ping checks that all of the servers are reachable.
+func (s *Service) Ping() error {
+	for _, addr := range s.Config.Servers {
+		err := s.with(addr, func(c *conn) error {
+			if _, err := c.rw.WriteString("version\r\n"); err != nil {
+				return err
+			}
+
+			if err := c.rw.Flush(); err != nil {
+				return err
+			}
+
+			line, err := c.rw.ReadSlice('\n')
+			if err != nil {
+				return err
+			}
+
+			if !bytes.HasPrefix(line, []byte("VERSION ")) {
+				return replyError(line)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the idle connections, the service can not be used after that.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	idle := s.idle
+	s.idle = nil
+	s.mu.Unlock()
+
+	var err error
+	for _, conns := range idle {
+		for _, c := range conns {
+			if cErr := c.nc.Close(); cErr != nil && err == nil {
+				err = cErr
+			}
+		}
+	}
+
+	return err
+}
@@ -2,6 +2,8 @@ package redis
 
 import (
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/kataras/golog"
@@ -11,12 +13,22 @@ import (
 
 // Database the redis back-end session database for the sessions.
 type Database struct {
-	redis *service.Service
+	// nerrors is the number of the errors, see `Errors`,
+	// first for the alignment of its atomic operations.
+	nerrors uint64
+	redis   *service.Service
 }
 
-var _ sessions.Database = (*Database)(nil)
+var (
+	_ sessions.Database          = (*Database)(nil)
+	_ sessions.ErrorCounter      = (*Database)(nil)
+	_ sessions.ExpirationUpdater = (*Database)(nil)
+	_ sessions.Locker            = (*Database)(nil)
+)
 
 // New returns a new redis database.
+// The `service.Config#SentinelAddrs` and `service.Config#ClusterAddrs` enable the sentinel
+// and the cluster modes, the pool's options are configurable by the `service.Config` too.
 func New(cfg ...service.Config) *Database {
 	db := &Database{redis: service.New(cfg...)}
 	db.redis.Connect()
@@ -37,10 +49,11 @@ func (db *Database) Config() *service.Config {
 // Acquire receives a session's lifetime from the database,
 // if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
 func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
-	seconds, hasExpiration, found := db.redis.TTL(sid)
+	seconds, hasExpiration, found := db.redis.TTL(db.sessionKey(sid))
 	if !found {
 		// not found, create an entry with ttl and return an empty lifetime, session manager will do its job.
-		if err := db.redis.Set(sid, sid, int64(expires.Seconds())); err != nil {
+		if err := db.redis.Set(db.sessionKey(sid), sid, int64(expires.Seconds())); err != nil {
+			atomic.AddUint64(&db.nerrors, 1)
 			golog.Debug(err)
 		}
 
@@ -57,8 +70,19 @@ func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime
 
 const delim = "_"
 
-func makeKey(sid, key string) string {
-	return sid + delim + key
+// sessionKey returns the key of the session's entry, the keys of its values start with it.
+// On cluster mode it's a hash tag, so all of the keys of a session are stored on the same node
+// and they can be loaded and removed with a single round trip.
+func (db *Database) sessionKey(sid string) string {
+	if len(db.redis.Config.ClusterAddrs) > 0 {
+		return "{" + sid + "}"
+	}
+
+	return sid
+}
+
+func (db *Database) makeKey(sid, key string) string {
+	return db.sessionKey(sid) + delim + key
 }
 
 // Set sets a key value of a specific session.
@@ -66,18 +90,20 @@ func makeKey(sid, key string) string {
 func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
 	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
 	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
 		golog.Error(err)
 		return
 	}
 
-	if err = db.redis.Set(makeKey(sid, key), valueBytes, int64(lifetime.DurationUntilExpiration().Seconds())); err != nil {
+	if err = db.redis.Set(db.makeKey(sid, key), valueBytes, int64(lifetime.DurationUntilExpiration().Seconds())); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
 		golog.Debug(err)
 	}
 }
 
 // Get retrieves a session value based on the key.
 func (db *Database) Get(sid string, key string) (value interface{}) {
-	db.get(makeKey(sid, key), &value)
+	db.get(db.makeKey(sid, key), &value)
 	return
 }
 
@@ -89,13 +115,15 @@ func (db *Database) get(key string, outPtr interface{}) {
 	}
 
 	if err = sessions.DefaultTranscoder.Unmarshal(data.([]byte), outPtr); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
 		golog.Debugf("unable to unmarshal value of key: '%s': %v", key, err)
 	}
 }
 
 func (db *Database) keys(sid string) []string {
-	keys, err := db.redis.GetKeys(sid + delim)
+	keys, err := db.redis.GetKeys(db.sessionKey(sid) + delim)
 	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
 		golog.Debugf("unable to get all redis keys of session '%s': %v", sid, err)
 		return nil
 	}
@@ -103,13 +131,32 @@ func (db *Database) keys(sid string) []string {
 	return keys
 }
 
-// Visit loops through all session keys and values.
+// Visit loops through all session keys and values,
+// the values are loaded with a single round trip.
 func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
 	keys := db.keys(sid)
-	for _, key := range keys {
+	values, err := db.redis.GetMultiBytes(keys...)
+	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to get the values of session '%s': %v", sid, err)
+		return
+	}
+
+	prefix := db.sessionKey(sid) + delim
+	for i, key := range keys {
+		if values[i] == nil {
+			// expired or removed after the scan.
+			continue
+		}
+
 		var value interface{} // new value each time, we don't know what user will do in "cb".
-		db.get(key, &value)
-		cb(key, value)
+		if err = sessions.DefaultTranscoder.Unmarshal(values[i], &value); err != nil {
+			atomic.AddUint64(&db.nerrors, 1)
+			golog.Debugf("unable to unmarshal value of key: '%s': %v", key, err)
+			continue
+		}
+
+		cb(strings.TrimPrefix(key, prefix), value)
 	}
 }
 
@@ -120,8 +167,9 @@ func (db *Database) Len(sid string) (n int) {
 
 // Delete removes a session key value based on its key.
 func (db *Database) Delete(sid string, key string) (deleted bool) {
-	err := db.redis.Delete(makeKey(sid, key))
+	err := db.redis.Delete(db.makeKey(sid, key))
 	if err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
 		golog.Error(err)
 	}
 	return err == nil
@@ -129,21 +177,30 @@ func (db *Database) Delete(sid string, key string) (deleted bool) {
 
 // Clear removes all session key values but it keeps the session entry.
 func (db *Database) Clear(sid string) {
-	keys := db.keys(sid)
-	for _, key := range keys {
-		if err := db.redis.Delete(key); err != nil {
-			golog.Debugf("unable to delete session '%s' value of key: '%s': %v", sid, key, err)
-		}
+	if err := db.redis.Delete(db.keys(sid)...); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to delete session '%s' values: %v", sid, err)
 	}
 }
 
 // Release destroys the session, it clears and removes the session entry,
 // session manager will create a new session ID on the next request after this call.
 func (db *Database) Release(sid string) {
-	// clear all $sid-$key.
-	db.Clear(sid)
-	// and remove the $sid.
-	db.redis.Delete(sid)
+	// remove all $sid-$key and the $sid.
+	if err := db.redis.Delete(append(db.keys(sid), db.sessionKey(sid))...); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to release session '%s': %v", sid, err)
+	}
+}
+
+// OnUpdateExpiration updates the lifetime of the session's entry and of its values,
+// with a single round trip, so they don't expire before the session.
+func (db *Database) OnUpdateExpiration(sid string, newExpires time.Duration) {
+	keys := append(db.keys(sid), db.sessionKey(sid))
+	if err := db.redis.Expire(int64(newExpires.Seconds()), keys...); err != nil {
+		atomic.AddUint64(&db.nerrors, 1)
+		golog.Debugf("unable to update the expiration of session '%s': %v", sid, err)
+	}
 }
 
 // Close terminates the redis connection.
@@ -154,3 +211,23 @@ func (db *Database) Close() error {
 func closeDB(db *Database) error {
 	return db.redis.CloseConnection()
 }
+
+// Errors returns the number of the errors of the database, i.e of its connection,
+// they are reported by the `sessions.Sessions#Stats`.
+func (db *Database) Errors() uint64 {
+	return atomic.LoadUint64(&db.nerrors)
+}
+
+// lockSuffix is the suffix of the key of the session's lock, it's not one of the session's values.
+const lockSuffix = ":lock"
+
+// TryLock acquires the lock of the session if it's free, it implements the `sessions.Locker`.
+func (db *Database) TryLock(sid, token string, ttl time.Duration) (bool, error) {
+	return db.redis.SetNX(db.sessionKey(sid)+lockSuffix, token, ttl)
+}
+
+// Unlock releases the lock of the session if it's owned by the "token", it implements the `sessions.Locker`.
+func (db *Database) Unlock(sid, token string) error {
+	_, err := db.redis.DeleteIfEqual(db.sessionKey(sid)+lockSuffix, token)
+	return err
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// clusterSlots is the number of the hash slots of a redis cluster.
+const clusterSlots = 16384
+
+// maxRedirects is the number of the "MOVED" and "ASK" redirections that a command follows.
+const maxRedirects = 3
+
+// cluster keeps a pool for each node of a redis cluster
+// and sends the commands to the node which owns their key.
+type cluster struct {
+	seeds   []string
+	newPool func(addr string) *redis.Pool
+
+	mu    sync.RWMutex
+	pools map[string]*redis.Pool
+	// the node's address of each slot.
+	slots []string
+}
+
+func newCluster(seeds []string, newPool func(addr string) *redis.Pool) *cluster {
+	return &cluster{seeds: seeds, newPool: newPool, pools: make(map[string]*redis.Pool)}
+}
+
+func (c *cluster) poolOf(addr string) *redis.Pool {
+	c.mu.RLock()
+	p, ok := c.pools[addr]
+	c.mu.RUnlock()
+	if ok {
+		return p
+	}
+
+	c.mu.Lock()
+	if p, ok = c.pools[addr]; !ok {
+		p = c.newPool(addr)
+		c.pools[addr] = p
+	}
+	c.mu.Unlock()
+	return p
+}
+
+// refresh loads the slots of the nodes by the "CLUSTER SLOTS" of the first reachable node.
+func (c *cluster) refresh() error {
+	c.mu.RLock()
+	addrs := append([]string(nil), c.seeds...)
+	for addr := range c.pools {
+		addrs = append(addrs, addr)
+	}
+	c.mu.RUnlock()
+
+	var err error = ErrClusterDown
+	for _, addr := range addrs {
+		var slots []string
+		if slots, err = c.loadSlots(addr); err == nil {
+			c.mu.Lock()
+			c.slots = slots
+			c.mu.Unlock()
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (c *cluster) loadSlots(addr string) ([]string, error) {
+	conn := c.poolOf(addr).Get()
+	defer conn.Close()
+
+	ranges, err := redis.Values(conn.Do("CLUSTER", "SLOTS"))
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]string, clusterSlots)
+	for _, r := range ranges {
+		// [start, end, [master's host, port, id], replicas...]
+		v, err := redis.Values(r, nil)
+		if err != nil || len(v) < 3 {
+			continue
+		}
+
+		master, err := redis.Values(v[2], nil)
+		if err != nil || len(master) < 2 {
+			continue
+		}
+
+		start, _ := redis.Int(v[0], nil)
+		end, _ := redis.Int(v[1], nil)
+		host, _ := redis.String(master[0], nil)
+		port, _ := redis.Int(master[1], nil)
+		if host == "" {
+			// the node which replied.
+			host, _, _ = net.SplitHostPort(addr)
+		}
+
+		nodeAddr := net.JoinHostPort(host, strconv.Itoa(port))
+		for s := start; s <= end && s < clusterSlots; s++ {
+			slots[s] = nodeAddr
+		}
+	}
+
+	return slots, nil
+}
+
+func (c *cluster) addrOf(key string) string {
+	slot := Slot(key)
+
+	for i := 0; i < 2; i++ {
+		c.mu.RLock()
+		var addr string
+		if c.slots != nil {
+			addr = c.slots[slot]
+		}
+		c.mu.RUnlock()
+
+		if addr != "" {
+			return addr
+		}
+
+		if c.refresh() != nil {
+			break
+		}
+	}
+
+	return c.seeds[0]
+}
+
+// conn returns a connection to the node of the "key".
+func (c *cluster) conn(key string) redis.Conn {
+	return c.poolOf(c.addrOf(key)).Get()
+}
+
+// do sends the command of the "key" to its node and it follows the redirections.
+func (c *cluster) do(key string, cmd string, args ...interface{}) (interface{}, error) {
+	addr := c.addrOf(key)
+	asking := false
+
+	for i := 0; ; i++ {
+		conn := c.poolOf(addr).Get()
+		if asking {
+			conn.Do("ASKING")
+		}
+		reply, err := conn.Do(cmd, args...)
+		conn.Close()
+
+		if isConnError(err) && i == 0 {
+			// the node may be down, i.e its master failed over to a replica,
+			// retry if its slot is moved to another node.
+			if c.refresh() == nil {
+				if to := c.addrOf(key); to != addr {
+					addr = to
+					continue
+				}
+			}
+			return reply, err
+		}
+
+		redirect, to := parseRedirect(err)
+		if redirect == "" || i == maxRedirects {
+			return reply, err
+		}
+
+		if redirect == "MOVED" {
+			c.refresh()
+		}
+		addr, asking = to, redirect == "ASK"
+	}
+}
+
+func (c *cluster) close() (err error) {
+	c.mu.Lock()
+	for addr, p := range c.pools {
+		if cErr := p.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+		delete(c.pools, addr)
+	}
+	c.mu.Unlock()
+	return
+}
+
+// isConnError reports whether the "err" is a connection's error, i.e a dial or a network error,
+// instead of an error reply of the server.
+func isConnError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	_, ok := err.(redis.Error)
+	return !ok
+}
+
+// parseRedirect returns the kind, "MOVED" or "ASK", and the node's address of a redirection error,
+// i.e "MOVED 3999 127.0.0.1:6381".
+func parseRedirect(err error) (kind, addr string) {
+	e, ok := err.(redis.Error)
+	if !ok {
+		return
+	}
+
+	f := strings.Fields(string(e))
+	if len(f) == 3 && (f[0] == "MOVED" || f[0] == "ASK") {
+		return f[0], f[2]
+	}
+
+	return
+}
+
+// Slot returns the cluster's hash slot of the "key".
+// If the key contains a hash tag, i.e "{sid}_name", only the tag is hashed,
+// so the keys with the same tag are stored on the same node.
+func Slot(key string) int {
+	if s := strings.IndexByte(key, '{'); s >= 0 {
+		if e := strings.IndexByte(key[s+1:], '}'); e > 0 {
+			key = key[s+1 : s+1+e]
+		}
+	}
+
+	return int(crc16(key) % clusterSlots)
+}
+
+// crc16 is the CRC16-CCITT (XModem) checksum of the redis cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
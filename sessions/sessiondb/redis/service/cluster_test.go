@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestSlot(t *testing.T) {
+	// the test vector of the redis cluster specification.
+	if got := crc16("123456789"); got != 0x31C3 {
+		t.Fatalf("expected crc16 to be 0x31C3 but got: 0x%X", got)
+	}
+
+	if got, expected := Slot("{user1000}.following"), Slot("user1000"); got != expected {
+		t.Fatalf("expected the hash tag to be hashed only: %d but got: %d", expected, got)
+	}
+
+	// an empty hash tag hashes the whole key.
+	if got, expected := Slot("foo{}{bar}"), int(crc16("foo{}{bar}")%clusterSlots); got != expected {
+		t.Fatalf("expected the whole key to be hashed: %d but got: %d", expected, got)
+	}
+
+	if got := Slot("123456789"); got != 0x31C3%clusterSlots {
+		t.Fatalf("expected the slot to be %d but got: %d", 0x31C3%clusterSlots, got)
+	}
+}
+
+func TestParseRedirect(t *testing.T) {
+	tests := []struct {
+		err        error
+		kind, addr string
+	}{
+		{redis.Error("MOVED 3999 127.0.0.1:6381"), "MOVED", "127.0.0.1:6381"},
+		{redis.Error("ASK 3999 127.0.0.1:6381"), "ASK", "127.0.0.1:6381"},
+		{redis.Error("ERR unknown command"), "", ""},
+		{errors.New("MOVED 3999 127.0.0.1:6381"), "", ""}, // not a reply of the server.
+		{nil, "", ""},
+	}
+
+	for i, tt := range tests {
+		if kind, addr := parseRedirect(tt.err); kind != tt.kind || addr != tt.addr {
+			t.Fatalf("[%d] expected '%s' '%s' but got '%s' '%s'", i, tt.kind, tt.addr, kind, addr)
+		}
+	}
+}
+
+// testNode is a fake node of a redis cluster.
+type testNode struct {
+	mu    sync.Mutex
+	down  bool
+	slots []interface{} // the reply of the "CLUSTER SLOTS".
+	calls []string
+}
+
+type testNodeConn struct {
+	node *testNode
+}
+
+func (c testNodeConn) Close() error                                       { return nil }
+func (c testNodeConn) Err() error                                         { return nil }
+func (c testNodeConn) Send(commandName string, args ...interface{}) error { return nil }
+func (c testNodeConn) Flush() error                                       { return nil }
+func (c testNodeConn) Receive() (interface{}, error)                      { return nil, nil }
+
+func (c testNodeConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	n := c.node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if commandName == "" {
+		return nil, nil
+	}
+
+	n.calls = append(n.calls, commandName)
+	if commandName == "CLUSTER" {
+		return n.slots, nil
+	}
+
+	return "OK", nil
+}
+
+type testCluster struct {
+	nodes map[string]*testNode
+}
+
+func (tc *testCluster) newPool(addr string) *redis.Pool {
+	return &redis.Pool{Dial: func() (redis.Conn, error) {
+		n, ok := tc.nodes[addr]
+		if !ok {
+			return nil, errors.New("dial tcp " + addr + ": connection refused")
+		}
+
+		n.mu.Lock()
+		down := n.down
+		n.mu.Unlock()
+		if down {
+			return nil, errors.New("dial tcp " + addr + ": connection refused")
+		}
+
+		return testNodeConn{node: n}, nil
+	}}
+}
+
+func slotsReply(ranges ...interface{}) []interface{} { return ranges }
+
+func slotRange(start, end int64, host string, port int64) interface{} {
+	return []interface{}{start, end, []interface{}{[]byte(host), port, []byte("id")}}
+}
+
+func TestClusterLoadSlots(t *testing.T) {
+	tc := &testCluster{nodes: map[string]*testNode{
+		"127.0.0.1:7000": {slots: slotsReply(
+			slotRange(0, 8191, "127.0.0.1", 7000),
+			slotRange(8192, 16383, "", 7001), // the empty host is the node which replied.
+			[]interface{}{int64(1)},          // malformed.
+		)},
+	}}
+
+	c := newCluster([]string{"127.0.0.1:7000"}, tc.newPool)
+	slots, err := c.loadSlots("127.0.0.1:7000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := slots[0]; got != "127.0.0.1:7000" {
+		t.Fatalf("expected the slot 0 to be on 127.0.0.1:7000 but got: %s", got)
+	}
+
+	if got := slots[clusterSlots-1]; got != "127.0.0.1:7001" {
+		t.Fatalf("expected the last slot to be on 127.0.0.1:7001 but got: %s", got)
+	}
+
+	if _, err = c.loadSlots("127.0.0.1:9999"); err == nil {
+		t.Fatalf("expected an error for an unreachable node")
+	}
+}
+
+func TestClusterRefreshOnConnError(t *testing.T) {
+	all := func(host string, port int64) []interface{} {
+		return slotsReply(slotRange(0, clusterSlots-1, host, port))
+	}
+
+	master := &testNode{slots: all("127.0.0.1", 7000)}
+	replica := &testNode{slots: all("127.0.0.1", 7000)}
+	tc := &testCluster{nodes: map[string]*testNode{"127.0.0.1:7000": master, "127.0.0.1:7001": replica}}
+
+	c := newCluster([]string{"127.0.0.1:7000", "127.0.0.1:7001"}, tc.newPool)
+	if _, err := c.do("key", "SET", "key", "value"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the master fails over to its replica.
+	master.mu.Lock()
+	master.down = true
+	master.mu.Unlock()
+	replica.mu.Lock()
+	replica.slots = all("127.0.0.1", 7001)
+	replica.mu.Unlock()
+
+	if _, err := c.do("key", "SET", "key", "value"); err != nil {
+		t.Fatalf("expected the command to be sent to the new master but got: %v", err)
+	}
+
+	replica.mu.Lock()
+	calls := replica.calls
+	replica.mu.Unlock()
+	if n := len(calls); n == 0 || calls[n-1] != "SET" {
+		t.Fatalf("expected the new master to receive the command but got: %v", calls)
+	}
+
+	// no node owns the slot.
+	replica.mu.Lock()
+	replica.down = true
+	replica.mu.Unlock()
+	if _, err := c.do("key", "SET", "key", "value"); !isConnError(err) {
+		t.Fatalf("expected a connection error but got: %v", err)
+	}
+}
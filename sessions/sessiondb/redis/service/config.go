@@ -22,6 +22,7 @@ type Config struct {
 	// Password string .If no password then no 'AUTH'. Default ""
 	Password string
 	// If Database is empty "" then no 'SELECT'. Default ""
+	// It's not supported by the cluster mode.
 	Database string
 	// MaxIdle 0 no limit
 	MaxIdle int
@@ -29,8 +30,33 @@ type Config struct {
 	MaxActive int
 	// IdleTimeout  time.Duration(5) * time.Minute
 	IdleTimeout time.Duration
+	// MaxConnLifetime closes the connections which are older than this duration, 0 no limit.
+	MaxConnLifetime time.Duration
+	// Wait if true then the callers wait for a free connection
+	// when the pool is at the MaxActive limit, otherwise they fail. Default false
+	Wait bool
+	// DialTimeout is the timeout of the new connections, 0 no timeout.
+	DialTimeout time.Duration
+	// ReadTimeout is the timeout of the replies, 0 no timeout.
+	ReadTimeout time.Duration
+	// WriteTimeout is the timeout of the commands, 0 no timeout.
+	WriteTimeout time.Duration
 	// Prefix "myprefix-for-this-website". Default ""
 	Prefix string
+
+	// SentinelAddrs enables the sentinel mode, the addresses of the sentinels,
+	// i.e []string{"10.0.0.1:26379", "10.0.0.2:26379"}.
+	// The master's address is asked by the sentinels, in order, on each new connection,
+	// so the connections follow the failovers, the Addr is not used. Default nil
+	SentinelAddrs []string
+	// MasterName is the name of the master which is monitored by the sentinels. Default ""
+	MasterName string
+
+	// ClusterAddrs enables the cluster mode, the addresses of one or more nodes of the cluster,
+	// the rest of them are discovered, the Addr is not used. Default nil
+	//
+	// The keys of a session are stored on the same node, see `Slot`.
+	ClusterAddrs []string
 }
 
 // DefaultConfig returns the default configuration for Redis service.
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// DefaultSubscribeRetryDelay is the delay between the attempts to restore a lost subscription, see `Subscribe`.
+var DefaultSubscribeRetryDelay = 2 * time.Second
+
+// Publish sends the "message" to the subscribers of the "channel", see `Subscribe`.
+// The channel is prefixed by the `Config#Prefix`.
+func (r *Service) Publish(channel string, message []byte) error {
+	channel = r.Config.Prefix + channel
+	_, err := r.do(channel, "PUBLISH", channel, message)
+	return err
+}
+
+// Subscribe calls the "onMessage", from a single goroutine, for each one of the messages of the "channel"
+// until the returned "unsubscribe" is called.
+// The subscription has its own connection, if it's lost then it's restored after the `DefaultSubscribeRetryDelay`,
+// the messages which are published meanwhile are lost.
+//
+// The channel is prefixed by the `Config#Prefix`, on cluster mode the subscription
+// is made to one of the `Config#ClusterAddrs`, the cluster sends the messages to all of its nodes.
+func (r *Service) Subscribe(channel string, onMessage func(message []byte)) (unsubscribe func(), err error) {
+	channel = r.Config.Prefix + channel
+
+	conn, err := r.subscribe(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+
+	unsubscribe = func() {
+		mu.Lock()
+		if !closed {
+			closed = true
+			conn.Close() // unblocks the receiver.
+		}
+		mu.Unlock()
+	}
+
+	go func() {
+		for {
+			mu.Lock()
+			c := conn
+			mu.Unlock()
+
+			switch m := c.Receive().(type) {
+			case redis.Message:
+				onMessage(m.Data)
+				continue
+			case error:
+			default: // subscription's confirmations.
+				continue
+			}
+
+			c.Close()
+			for {
+				mu.Lock()
+				stop := closed
+				mu.Unlock()
+				if stop {
+					return
+				}
+
+				time.Sleep(DefaultSubscribeRetryDelay)
+				newConn, err := r.subscribe(channel)
+				if err != nil {
+					continue
+				}
+
+				mu.Lock()
+				if closed {
+					mu.Unlock()
+					newConn.Close()
+					return
+				}
+				conn = newConn
+				mu.Unlock()
+				break
+			}
+		}
+	}()
+
+	return unsubscribe, nil
+}
+
+// subscribe dials a new connection, without a read timeout because it waits for the messages, and subscribes to the "channel".
+func (r *Service) subscribe(channel string) (redis.PubSubConn, error) {
+	c := r.Config
+	opts := []redis.DialOption{
+		redis.DialConnectTimeout(c.DialTimeout),
+		redis.DialWriteTimeout(c.WriteTimeout),
+	}
+
+	if c.Password != "" {
+		opts = append(opts, redis.DialPassword(c.Password))
+	}
+
+	var addrs []string
+	switch {
+	case len(c.ClusterAddrs) > 0:
+		addrs = c.ClusterAddrs
+	case len(c.SentinelAddrs) > 0:
+		addr, err := r.masterAddr()
+		if err != nil {
+			return redis.PubSubConn{}, err
+		}
+		addrs = []string{addr}
+	default:
+		addrs = []string{c.Addr}
+	}
+
+	var err error = ErrClusterDown
+	for _, addr := range addrs {
+		var conn redis.Conn
+		if conn, err = redis.Dial(c.Network, addr, opts...); err != nil {
+			continue
+		}
+
+		psc := redis.PubSubConn{Conn: conn}
+		if err = psc.Subscribe(channel); err != nil {
+			psc.Close()
+			continue
+		}
+
+		return psc, nil
+	}
+
+	return redis.PubSubConn{}, err
+}
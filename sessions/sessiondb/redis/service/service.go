@@ -1,7 +1,8 @@
 package service
 
 import (
-	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
@@ -13,6 +14,12 @@ var (
 	ErrRedisClosed = errors.New("Redis is already closed")
 	// ErrKeyNotFound an error with message 'Key $thekey doesn't found'
 	ErrKeyNotFound = errors.New("Key '%s' doesn't found")
+	// ErrMasterNotFound an error with message 'Master $name doesn't found by the sentinels'
+	ErrMasterNotFound = errors.New("Master '%s' doesn't found by the sentinels")
+	// ErrNotMaster an error with message 'Connection is not to a master', the connection is discarded
+	ErrNotMaster = errors.New("Connection is not to a master")
+	// ErrClusterDown an error with message 'No cluster node is reachable'
+	ErrClusterDown = errors.New("No cluster node is reachable")
 )
 
 // Service the Redis service, contains the config and the redis pool
@@ -22,13 +29,83 @@ type Service struct {
 	// Config the redis config for this redis
 	Config *Config
 	pool   *redis.Pool
+	// not nil on cluster mode, the pool is not used.
+	cluster *cluster
 }
 
-// PingPong sends a ping and receives a pong, if no pong received then returns false and filled error
-func (r *Service) PingPong() (bool, error) {
+// conn returns a connection to the server or, on cluster mode, to the node of the "key".
+func (r *Service) conn(key string) redis.Conn {
+	if r.cluster != nil {
+		return r.cluster.conn(key)
+	}
+
+	return r.pool.Get()
+}
+
+// do sends a command of the "key" and returns its reply,
+// on cluster mode it's sent to the node of the key.
+func (r *Service) do(key string, cmd string, args ...interface{}) (interface{}, error) {
+	if r.cluster != nil {
+		return r.cluster.do(key, cmd, args...)
+	}
+
 	c := r.pool.Get()
 	defer c.Close()
-	msg, err := c.Do("PING")
+	return c.Do(cmd, args...)
+}
+
+// pipeline sends the "cmd" for each of the "args" with a single round trip
+// and returns their replies, the first error reply is returned too.
+// On cluster mode all of the commands are sent to the node of the "key".
+func (r *Service) pipeline(key string, cmd string, args [][]interface{}) ([]interface{}, error) {
+	replies, err := r.send(key, cmd, args)
+	if kind, _ := parseRedirect(err); (kind != "" || isConnError(err)) && r.cluster != nil {
+		// the slot is moved or its node is down, the commands are idempotent, retry once.
+		if r.cluster.refresh() == nil {
+			replies, err = r.send(key, cmd, args)
+		}
+	}
+
+	return replies, err
+}
+
+func (r *Service) send(key string, cmd string, args [][]interface{}) ([]interface{}, error) {
+	c := r.conn(key)
+	defer c.Close()
+
+	for _, a := range args {
+		if err := c.Send(cmd, a...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	replies := make([]interface{}, len(args))
+	for i := range args {
+		reply, err := c.Receive()
+		if err != nil {
+			if _, ok := err.(redis.Error); !ok {
+				// connection's error.
+				return nil, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		replies[i] = reply
+	}
+
+	return replies, firstErr
+}
+
+// PingPong sends a ping and receives a pong, if no pong received then returns false and filled error
+func (r *Service) PingPong() (bool, error) {
+	msg, err := r.do("", "PING")
 	if err != nil || msg == nil {
 		return false, err
 	}
@@ -37,6 +114,10 @@ func (r *Service) PingPong() (bool, error) {
 
 // CloseConnection closes the redis connection
 func (r *Service) CloseConnection() error {
+	if r.cluster != nil {
+		return r.cluster.close()
+	}
+
 	if r.pool != nil {
 		return r.pool.Close()
 	}
@@ -46,32 +127,44 @@ func (r *Service) CloseConnection() error {
 // Set sets a key-value to the redis store.
 // The expiration is setted by the MaxAgeSeconds.
 func (r *Service) Set(key string, value interface{}, secondsLifetime int64) (err error) {
-	c := r.pool.Get()
-	defer c.Close()
-	if c.Err() != nil {
-		return c.Err()
-	}
-
+	key = r.Config.Prefix + key
 	// if has expiration, then use the "EX" to delete the key automatically.
 	if secondsLifetime > 0 {
-		_, err = c.Do("SETEX", r.Config.Prefix+key, secondsLifetime, value)
+		_, err = r.do(key, "SETEX", key, secondsLifetime, value)
 	} else {
-		_, err = c.Do("SET", r.Config.Prefix+key, value)
+		_, err = r.do(key, "SET", key, value)
 	}
 
 	return
 }
 
+// SetNX sets a key-value to the redis store if the key does not exist,
+// it reports whether it was set, the key is deleted after the "lifetime".
+func (r *Service) SetNX(key string, value interface{}, lifetime time.Duration) (bool, error) {
+	key = r.Config.Prefix + key
+	reply, err := r.do(key, "SET", key, value, "NX", "PX", int64(lifetime/time.Millisecond))
+	if err != nil {
+		return false, err
+	}
+
+	return reply != nil, nil
+}
+
+// deleteIfEqualScript deletes the key if its value is the argument, atomically.
+const deleteIfEqualScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`
+
+// DeleteIfEqual removes a redis entry by its key if its value is equal to the "value",
+// it reports whether it was removed.
+func (r *Service) DeleteIfEqual(key string, value interface{}) (bool, error) {
+	key = r.Config.Prefix + key
+	n, err := redis.Int(r.do(key, "EVAL", deleteIfEqualScript, 1, key, value))
+	return n == 1, err
+}
+
 // Get returns value, err by its key
 //returns nil and a filled error if something bad happened.
 func (r *Service) Get(key string) (interface{}, error) {
-	c := r.pool.Get()
-	defer c.Close()
-	if err := c.Err(); err != nil {
-		return nil, err
-	}
-
-	redisVal, err := c.Do("GET", r.Config.Prefix+key)
+	redisVal, err := r.do(r.Config.Prefix+key, "GET", r.Config.Prefix+key)
 
 	if err != nil {
 		return nil, err
@@ -85,29 +178,36 @@ func (r *Service) Get(key string) (interface{}, error) {
 // TTL returns the seconds to expire, if the key has expiration and error if action failed.
 // Read more at: https://redis.io/commands/ttl
 func (r *Service) TTL(key string) (seconds int64, hasExpiration bool, ok bool) {
-	c := r.pool.Get()
-	defer c.Close()
-	redisVal, err := c.Do("TTL", r.Config.Prefix+key)
+	seconds, err := redis.Int64(r.do(r.Config.Prefix+key, "TTL", r.Config.Prefix+key))
 	if err != nil {
 		return -2, false, false
 	}
-	seconds = redisVal.(int64)
 	// if -1 means the key has unlimited life time.
-	hasExpiration = seconds == -1
+	hasExpiration = seconds > 0
 	// if -2 means key does not exist.
-	ok = (c.Err() != nil || seconds == -2)
+	ok = seconds != -2
 	return
 }
 
-// GetAll returns all redis entries using the "SCAN" command (2.8+).
-func (r *Service) GetAll() (interface{}, error) {
-	c := r.pool.Get()
-	defer c.Close()
-	if err := c.Err(); err != nil {
-		return nil, err
+// Expire sets the lifetime of the keys with a single round trip,
+// on cluster mode the keys should be on the same node, i.e share a hash tag, see `Slot`.
+func (r *Service) Expire(secondsLifetime int64, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	args := make([][]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = []interface{}{r.Config.Prefix + key, secondsLifetime}
 	}
 
-	redisVal, err := c.Do("SCAN", 0) // 0 -> cursor
+	_, err := r.pipeline(r.Config.Prefix+keys[0], "EXPIRE", args)
+	return err
+}
+
+// GetAll returns all redis entries using the "SCAN" command (2.8+).
+func (r *Service) GetAll() (interface{}, error) {
+	redisVal, err := r.do("", "SCAN", 0) // 0 -> cursor
 
 	if err != nil {
 		return nil, err
@@ -120,97 +220,179 @@ func (r *Service) GetAll() (interface{}, error) {
 	return redisVal, nil
 }
 
-// GetKeys returns all redis keys using the "SCAN" with MATCH command.
+// GetKeys returns all redis keys, without the `Config#Prefix`, using the "SCAN" with MATCH command.
+// On cluster mode only the node of the "prefix" is scanned, the prefix should contain a hash tag, see `Slot`.
 // Read more at:  https://redis.io/commands/scan#the-match-option.
 func (r *Service) GetKeys(prefix string) ([]string, error) {
-	c := r.pool.Get()
+	match := r.Config.Prefix + prefix
+	c := r.conn(match)
 	defer c.Close()
 	if err := c.Err(); err != nil {
 		return nil, err
 	}
 
-	if err := c.Send("SCAN", 0, "MATCH", r.Config.Prefix+prefix+"*", "COUNT", 9999999999); err != nil {
-		return nil, err
-	}
-
-	if err := c.Flush(); err != nil {
-		return nil, err
-	}
-
-	reply, err := c.Receive()
-	if err != nil || reply == nil {
-		return nil, err
-	}
+	var keys []string
+	cursor := 0
+	for {
+		// it returns two entries, the next cursor and the keys of this iteration.
+		reply, err := redis.Values(c.Do("SCAN", cursor, "MATCH", match+"*", "COUNT", 1000))
+		if err != nil {
+			return nil, err
+		}
 
-	// it returns []interface, with two entries, the first one is "0" and the second one is a slice of the keys as []interface{uint8....}.
+		if len(reply) != 2 {
+			return keys, nil
+		}
 
-	if keysInterface, ok := reply.([]interface{}); ok {
-		if len(keysInterface) == 2 {
-			// take the second, it must contain the slice of keys.
-			if keysSliceAsBytes, ok := keysInterface[1].([]interface{}); ok {
-				keys := make([]string, len(keysSliceAsBytes), len(keysSliceAsBytes))
-				for i, k := range keysSliceAsBytes {
-					keys[i] = fmt.Sprintf("%s", k)
-				}
+		page, err := redis.Strings(reply[1], nil)
+		if err != nil {
+			return nil, err
+		}
 
-				return keys, nil
-			}
+		for _, k := range page {
+			keys = append(keys, strings.TrimPrefix(k, r.Config.Prefix))
+		}
 
+		if cursor, err = redis.Int(reply[0], nil); err != nil || cursor == 0 {
+			return keys, err
 		}
 	}
-
-	return nil, nil
 }
 
 // GetBytes returns value, err by its key
 // you can use utils.Deserialize((.GetBytes("yourkey"),&theobject{})
 //returns nil and a filled error if something wrong happens
 func (r *Service) GetBytes(key string) ([]byte, error) {
-	c := r.pool.Get()
-	defer c.Close()
-	if err := c.Err(); err != nil {
+	redisVal, err := r.Get(key)
+	if err != nil {
 		return nil, err
 	}
 
-	redisVal, err := c.Do("GET", r.Config.Prefix+key)
+	return redis.Bytes(redisVal, err)
+}
+
+// GetMultiBytes returns the values of the keys with a single round trip,
+// the value of a missing key is nil.
+// On cluster mode the keys should be on the same node, i.e share a hash tag, see `Slot`.
+func (r *Service) GetMultiBytes(keys ...string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	args := make([][]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = []interface{}{r.Config.Prefix + key}
+	}
 
+	replies, err := r.pipeline(r.Config.Prefix+keys[0], "GET", args)
 	if err != nil {
 		return nil, err
 	}
-	if redisVal == nil {
-		return nil, ErrKeyNotFound.Format(key)
+
+	values := make([][]byte, len(replies))
+	for i, reply := range replies {
+		if reply != nil {
+			values[i], _ = redis.Bytes(reply, nil)
+		}
 	}
 
-	return redis.Bytes(redisVal, err)
+	return values, nil
 }
 
-// Delete removes redis entry by specific key
-func (r *Service) Delete(key string) error {
-	c := r.pool.Get()
-	defer c.Close()
+// Delete removes redis entries by their keys.
+// On cluster mode the keys should be on the same node, i.e share a hash tag, see `Slot`.
+func (r *Service) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = r.Config.Prefix + key
+	}
 
-	_, err := c.Do("DEL", r.Config.Prefix+key)
+	_, err := r.do(r.Config.Prefix+keys[0], "DEL", args...)
 	return err
 }
 
-func dial(network string, addr string, pass string) (redis.Conn, error) {
-	if network == "" {
-		network = DefaultRedisNetwork
+func (r *Service) dialOptions() []redis.DialOption {
+	c := r.Config
+	opts := []redis.DialOption{
+		redis.DialConnectTimeout(c.DialTimeout),
+		redis.DialReadTimeout(c.ReadTimeout),
+		redis.DialWriteTimeout(c.WriteTimeout),
 	}
-	if addr == "" {
-		addr = DefaultRedisAddr
+
+	if c.Password != "" {
+		opts = append(opts, redis.DialPassword(c.Password))
 	}
-	c, err := redis.Dial(network, addr)
-	if err != nil {
-		return nil, err
+
+	return opts
+}
+
+// masterAddr asks the sentinels, in order, for the address of the master.
+func (r *Service) masterAddr() (string, error) {
+	c := r.Config
+	var err error = ErrMasterNotFound.Format(c.MasterName)
+	for _, addr := range c.SentinelAddrs {
+		conn, dErr := redis.Dial(c.Network, addr,
+			redis.DialConnectTimeout(c.DialTimeout),
+			redis.DialReadTimeout(c.ReadTimeout),
+			redis.DialWriteTimeout(c.WriteTimeout))
+		if dErr != nil {
+			err = dErr
+			continue
+		}
+
+		reply, rErr := redis.Strings(conn.Do("SENTINEL", "get-master-addr-by-name", c.MasterName))
+		conn.Close()
+		if rErr == nil && len(reply) == 2 {
+			return net.JoinHostPort(reply[0], reply[1]), nil
+		}
+	}
+
+	return "", err
+}
+
+// newPool returns a new pool of the connections to the address of the "addr",
+// it selects the `Config#Database` if "selectDB" is true.
+func (r *Service) newPool(addr func() (string, error), selectDB bool) *redis.Pool {
+	c := r.Config
+	pool := &redis.Pool{
+		IdleTimeout:     c.IdleTimeout,
+		MaxIdle:         c.MaxIdle,
+		MaxActive:       c.MaxActive,
+		MaxConnLifetime: c.MaxConnLifetime,
+		Wait:            c.Wait,
+	}
+
+	pool.TestOnBorrow = func(c redis.Conn, t time.Time) error {
+		_, err := c.Do("PING")
+		return err
 	}
-	if pass != "" {
-		if _, err = c.Do("AUTH", pass); err != nil {
-			c.Close()
+
+	pool.Dial = func() (redis.Conn, error) {
+		a, err := addr()
+		if err != nil {
+			return nil, err
+		}
+
+		red, err := redis.Dial(c.Network, a, r.dialOptions()...)
+		if err != nil {
 			return nil, err
 		}
+
+		if selectDB && c.Database != "" {
+			if _, err = red.Do("SELECT", c.Database); err != nil {
+				red.Close()
+				return nil, err
+			}
+		}
+
+		return red, nil
 	}
-	return c, err
+
+	return pool
 }
 
 // Connect connects to the redis, called only once
@@ -229,31 +411,36 @@ func (r *Service) Connect() {
 		c.Addr = DefaultRedisAddr
 	}
 
-	pool := &redis.Pool{IdleTimeout: DefaultRedisIdleTimeout, MaxIdle: c.MaxIdle, MaxActive: c.MaxActive}
-	pool.TestOnBorrow = func(c redis.Conn, t time.Time) error {
-		_, err := c.Do("PING")
-		return err
-	}
-
-	if c.Database != "" {
-		pool.Dial = func() (redis.Conn, error) {
-			red, err := dial(c.Network, c.Addr, c.Password)
+	switch {
+	case len(c.ClusterAddrs) > 0:
+		r.cluster = newCluster(c.ClusterAddrs, func(addr string) *redis.Pool {
+			return r.newPool(func() (string, error) { return addr, nil }, false)
+		})
+	case len(c.SentinelAddrs) > 0:
+		pool := r.newPool(r.masterAddr, true)
+		// discard the connections to a master which is demoted by a failover.
+		pool.TestOnBorrow = func(c redis.Conn, t time.Time) error {
+			role, err := redis.Values(c.Do("ROLE"))
 			if err != nil {
-				return nil, err
+				return err
 			}
-			if _, err = red.Do("SELECT", c.Database); err != nil {
-				red.Close()
-				return nil, err
+
+			if len(role) == 0 {
+				return ErrNotMaster
 			}
-			return red, err
-		}
-	} else {
-		pool.Dial = func() (redis.Conn, error) {
-			return dial(c.Network, c.Addr, c.Password)
+
+			if name, _ := redis.String(role[0], nil); name != "master" {
+				return ErrNotMaster
+			}
+
+			return nil
 		}
+		r.pool = pool
+	default:
+		r.pool = r.newPool(func() (string, error) { return c.Addr, nil }, true)
 	}
+
 	r.Connected = true
-	r.pool = pool
 }
 
 // New returns a Redis service filled by the passed config
@@ -0,0 +1,384 @@
+package sql
+
+import (
+	stdsql "database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/sessions"
+)
+
+// testDriver is an in-memory database/sql driver which understands the queries of the `Database`.
+type testDriver struct {
+	mu     sync.Mutex
+	stores map[string]*testStore
+}
+
+type testRow struct {
+	value   []byte
+	expires int64
+}
+
+type testStore struct {
+	mu   sync.Mutex
+	rows map[[2]string]testRow
+	// fail makes the transactions to fail.
+	fail bool
+	// onBegin is called when a transaction begins.
+	onBegin func()
+}
+
+var (
+	testDrv           = &testDriver{stores: make(map[string]*testStore)}
+	errTestConnection = errors.New("connection refused")
+)
+
+func init() {
+	stdsql.Register("sessiondb-test", testDrv)
+}
+
+func (d *testDriver) Open(dsn string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, ok := d.stores[dsn]
+	if !ok {
+		s = &testStore{rows: make(map[[2]string]testRow)}
+		d.stores[dsn] = s
+	}
+
+	return &testConn{store: s}, nil
+}
+
+type testConn struct {
+	store *testStore
+}
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{store: c.store, query: query}, nil
+}
+
+func (c *testConn) Close() error { return nil }
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	s := c.store
+	s.mu.Lock()
+	fail, onBegin := s.fail, s.onBegin
+	s.mu.Unlock()
+
+	if onBegin != nil {
+		onBegin()
+	}
+
+	if fail {
+		return nil, errTestConnection
+	}
+
+	return testTx{}, nil
+}
+
+type testTx struct{}
+
+func (testTx) Commit() error   { return nil }
+func (testTx) Rollback() error { return nil }
+
+type testStmt struct {
+	store *testStore
+	query string
+}
+
+func (s *testStmt) Close() error  { return nil }
+func (s *testStmt) NumInput() int { return -1 }
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.fail {
+		return nil, errTestConnection
+	}
+
+	var n int64
+	switch q := s.query; {
+	case strings.HasPrefix(q, "INSERT INTO iris_sessions (session_id, name, value, expires_at) VALUES"):
+		value, _ := args[2].([]byte)
+		st.rows[[2]string{args[0].(string), args[1].(string)}] = testRow{value: value, expires: args[3].(int64)}
+		n = 1
+	case strings.Contains(q, "WHERE expires_at > 0 AND expires_at <"):
+		for k, r := range st.rows {
+			if r.expires > 0 && r.expires < args[0].(int64) {
+				delete(st.rows, k)
+				n++
+			}
+		}
+	case strings.Contains(q, "WHERE session_id = $1 AND name = $2"), strings.Contains(q, "WHERE session_id = ? AND name = ?"):
+		k := [2]string{args[0].(string), args[1].(string)}
+		if _, ok := st.rows[k]; ok {
+			delete(st.rows, k)
+			n = 1
+		}
+	case strings.HasPrefix(q, "DELETE FROM iris_sessions WHERE session_id ="):
+		for k := range st.rows {
+			if k[0] == args[0].(string) && (k[1] != "" || !strings.Contains(q, "name <> ''")) {
+				delete(st.rows, k)
+				n++
+			}
+		}
+	}
+
+	return driver.RowsAffected(n), nil
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.fail {
+		return nil, errTestConnection
+	}
+
+	rows := &testRows{}
+	switch q := s.query; {
+	case strings.HasPrefix(q, "SELECT value FROM"):
+		rows.columns = []string{"value"}
+		if r, ok := st.rows[[2]string{args[0].(string), args[1].(string)}]; ok && (r.expires == 0 || r.expires > args[2].(int64)) {
+			rows.values = append(rows.values, []driver.Value{r.value})
+		}
+	case strings.HasPrefix(q, "SELECT expires_at FROM"):
+		rows.columns = []string{"expires_at"}
+		if r, ok := st.rows[[2]string{args[0].(string), ""}]; ok {
+			rows.values = append(rows.values, []driver.Value{r.expires})
+		}
+	case strings.HasPrefix(q, "SELECT name, value FROM"):
+		rows.columns = []string{"name", "value"}
+		for k, r := range st.rows {
+			if k[0] == args[0].(string) && k[1] != "" && (r.expires == 0 || r.expires > args[1].(int64)) {
+				rows.values = append(rows.values, []driver.Value{k[1], r.value})
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+type testRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *testRows) Columns() []string { return r.columns }
+func (r *testRows) Close() error      { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newTestDatabase(t *testing.T, cfg Config) (*Database, *testStore) {
+	conn, err := stdsql.Open("sessiondb-test", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.GCInterval = -1
+	db, err := New(conn, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn.Ping() // opens the store.
+	testDrv.mu.Lock()
+	store := testDrv.stores[t.Name()]
+	testDrv.mu.Unlock()
+
+	return db, store
+}
+
+func (s *testStore) get(sid, name string) (interface{}, bool) {
+	s.mu.Lock()
+	r, ok := s.rows[[2]string{sid, name}]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	var value interface{}
+	sessions.DefaultTranscoder.Unmarshal(r.value, &value)
+	return value, true
+}
+
+func TestDialects(t *testing.T) {
+	postgres, err := New(nil, Config{GCInterval: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "INSERT INTO iris_sessions (session_id, name, value, expires_at) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (session_id, name) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at"; postgres.upsertQuery != expected {
+		t.Fatalf("expected the postgres upsert to be:\n%s\nbut got:\n%s", expected, postgres.upsertQuery)
+	}
+
+	if expected := "DELETE FROM iris_sessions WHERE session_id = $1 AND name = $2"; postgres.deleteQuery != expected {
+		t.Fatalf("expected the postgres delete to be:\n%s\nbut got:\n%s", expected, postgres.deleteQuery)
+	}
+
+	mysql, err := New(nil, Config{Dialect: MySQL, Table: "sessions", GCInterval: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "INSERT INTO sessions (session_id, name, value, expires_at) VALUES (?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE value = VALUES(value), expires_at = VALUES(expires_at)"; mysql.upsertQuery != expected {
+		t.Fatalf("expected the mysql upsert to be:\n%s\nbut got:\n%s", expected, mysql.upsertQuery)
+	}
+
+	if _, err = New(nil, Config{Dialect: "oracle"}); err == nil {
+		t.Fatalf("expected an error for an unsupported dialect")
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	db, store := newTestDatabase(t, Config{})
+	defer db.Close()
+
+	db.Acquire("sid", time.Hour)
+	db.Set("sid", sessions.LifeTime{}, "name", "iris", false)
+	db.Set("sid", sessions.LifeTime{}, "age", 9, false)
+
+	if got := db.Get("sid", "name"); got != "iris" {
+		t.Fatalf("expected the value to be 'iris' but got: %v", got)
+	}
+
+	if n := db.Len("sid"); n != 2 {
+		t.Fatalf("expected 2 values but got: %d", n)
+	}
+
+	var keys []string
+	db.Visit("sid", func(key string, value interface{}) { keys = append(keys, key) })
+	if len(keys) != 2 {
+		t.Fatalf("expected to visit 2 values but got: %v", keys)
+	}
+
+	if !db.Delete("sid", "age") || db.Delete("sid", "age") {
+		t.Fatalf("expected the value to be deleted once")
+	}
+
+	db.Clear("sid")
+	if _, ok := store.get("sid", "name"); ok {
+		t.Fatalf("expected the values to be cleared")
+	}
+
+	if _, ok := store.get("sid", ""); !ok {
+		t.Fatalf("expected the session's entry to be kept on clear")
+	}
+
+	db.Release("sid")
+	if _, ok := store.get("sid", ""); ok {
+		t.Fatalf("expected the session's entry to be removed on release")
+	}
+}
+
+func TestDatabaseSweep(t *testing.T) {
+	db, store := newTestDatabase(t, Config{})
+	defer db.Close()
+
+	expired := sessions.LifeTime{Time: time.Now().Add(-time.Minute)}
+	live := sessions.LifeTime{Time: time.Now().Add(time.Hour)}
+	db.Set("expired", expired, "key", "value", false)
+	db.Set("live", live, "key", "value", false)
+
+	if err := db.Sweep(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := store.get("expired", "key"); ok {
+		t.Fatalf("expected the expired row to be removed")
+	}
+
+	if _, ok := store.get("live", "key"); !ok {
+		t.Fatalf("expected the live row to be kept")
+	}
+}
+
+func TestDatabaseBatch(t *testing.T) {
+	db, store := newTestDatabase(t, Config{FlushInterval: time.Hour, BatchSize: 2})
+	defer db.Close()
+
+	db.Set("sid", sessions.LifeTime{}, "first", "1", false)
+	if _, ok := store.get("sid", "first"); ok {
+		t.Fatalf("expected the write to be pending")
+	}
+
+	if got := db.Get("sid", "first"); got != "1" {
+		t.Fatalf("expected the pending value to be visible but got: %v", got)
+	}
+
+	// the batch is full.
+	db.Set("sid", sessions.LifeTime{}, "second", "2", false)
+	if got, _ := store.get("sid", "first"); got != "1" {
+		t.Fatalf("expected the batch to be flushed but got: %v", got)
+	}
+
+	db.Delete("sid", "first")
+	if err := db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := store.get("sid", "first"); ok {
+		t.Fatalf("expected the pending delete to be flushed")
+	}
+}
+
+func TestDatabaseFlushFailure(t *testing.T) {
+	db, store := newTestDatabase(t, Config{FlushInterval: time.Hour})
+	defer db.Close()
+
+	db.Set("sid", sessions.LifeTime{}, "kept", "old", false)
+	db.Set("sid", sessions.LifeTime{}, "overridden", "old", false)
+
+	store.mu.Lock()
+	store.fail = true
+	store.onBegin = func() {
+		// a newer write, made while the flush runs.
+		db.Set("sid", sessions.LifeTime{}, "overridden", "new", false)
+	}
+	store.mu.Unlock()
+
+	if err := db.Flush(); err == nil {
+		t.Fatalf("expected the flush to fail")
+	}
+
+	if got := db.Get("sid", "kept"); got != "old" {
+		t.Fatalf("expected the failed write to be still pending but got: %v", got)
+	}
+
+	store.mu.Lock()
+	store.fail = false
+	store.onBegin = nil
+	store.mu.Unlock()
+
+	if err := db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := store.get("sid", "kept"); got != "old" {
+		t.Fatalf("expected the failed write to be retried but got: %v", got)
+	}
+
+	if got, _ := store.get("sid", "overridden"); got != "new" {
+		t.Fatalf("expected the newer write to be kept but got: %v", got)
+	}
+}
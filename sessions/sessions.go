@@ -1,6 +1,7 @@
 package sessions
 
 import (
+	"crypto/cipher"
 	"net/http"
 	"time"
 
@@ -17,23 +18,59 @@ import (
 type Sessions struct {
 	config   Config
 	provider *provider
+	// the ciphers of the `Config#StatelessKeys`.
+	ciphers []cipher.AEAD
 }
 
 // New returns a new fast, feature-rich sessions manager
-// it can be adapted to an iris station
+// it can be adapted to an iris station.
+// It panics if the `Config#Stateless` is true and the `Config#StatelessKeys` are invalid
+// and if the `Config#SessionIDLength` and the `Config#SessionIDAlphabet` do not generate safe session ids.
 func New(cfg Config) *Sessions {
-	return &Sessions{
+	s := &Sessions{
 		config:   cfg.Validate(),
 		provider: newProvider(),
 	}
+	s.provider.manager = s
+
+	if err := s.config.validateSessionID(); err != nil {
+		panic(err.Error())
+	}
+
+	if s.config.Stateless {
+		s.ciphers = newStatelessCiphers(s.config.StatelessKeys)
+	}
+
+	if s.config.JWT {
+		if s.config.Stateless {
+			panic("sessions: the stateless and the JWT modes can not be combined")
+		}
+
+		if len(s.config.JWTKeys) == 0 {
+			panic("sessions: JWT mode requires at least one of the JWTKeys")
+		}
+	}
+
+	if s.config.GCInterval > 0 {
+		go s.gc(s.config.GCInterval)
+	}
+
+	return s
 }
 
 // UseDatabase adds a session database to the manager's provider,
-// a session db doesn't have write access
+// a session db doesn't have write access.
+// It's not used by the stateless sessions, see `Config#Stateless`.
 func (s *Sessions) UseDatabase(db Database) {
 	s.provider.RegisterDatabase(db)
 }
 
+// Database returns the session database of the manager, the in-memory one if `UseDatabase` was not called,
+// see `Migrate`.
+func (s *Sessions) Database() Database {
+	return s.provider.database()
+}
+
 // updateCookie gains the ability of updating the session browser cookie to any method which wants to update it
 func (s *Sessions) updateCookie(ctx context.Context, sid string, expires time.Duration) {
 	cookie := &http.Cookie{}
@@ -64,28 +101,42 @@ func (s *Sessions) updateCookie(ctx context.Context, sid string, expires time.Du
 	}
 
 	// encode the session id cookie client value right before send it.
-	cookie.Value = s.encodeCookieValue(cookie.Value)
-	AddCookie(ctx, cookie, s.config.AllowReclaim)
+	if s.config.JWT {
+		cookie.Value = s.issueToken(ctx, sid, expires)
+	} else {
+		cookie.Value = s.encodeCookieValue(cookie.Value)
+	}
+	removeResponseCookie(ctx, s.config.cookieName(ctx))
+	AddCookie(ctx, cookie, s.config.AllowReclaim, s.config.cookieOptions(ctx)...)
 }
 
 // Start should start the session for the particular request.
 func (s *Sessions) Start(ctx context.Context) *Session {
-	cookieValue := s.decodeCookieValue(GetCookie(ctx, s.config.Cookie))
+	if s.config.Stateless {
+		return s.applyExpirationPolicies(ctx, s.startStateless(ctx))
+	}
+
+	cookieValue := s.sessionID(ctx)
 
 	if cookieValue == "" { // cookie doesn't exists, let's generate a session and add set a cookie
 		sid := s.config.SessionIDGenerator()
+		expires := s.config.sessionExpires()
 
-		sess := s.provider.Init(sid, s.config.Expires)
+		sess := s.provider.Init(sid, expires)
 		sess.isNew = s.provider.db.Len(sid) == 0
 
-		s.updateCookie(ctx, sid, s.config.Expires)
+		s.updateCookie(ctx, sid, expires)
+		fire(s.provider.createListeners, ctx, sess)
 
-		return sess
+		return s.applyExpirationPolicies(ctx, sess)
 	}
 
-	sess := s.provider.Read(cookieValue, s.config.Expires)
+	sess, created := s.provider.Read(cookieValue, s.config.sessionExpires())
+	if created {
+		fire(s.provider.createListeners, ctx, sess)
+	}
 
-	return sess
+	return s.applyExpirationPolicies(ctx, sess)
 }
 
 // ShiftExpiration move the expire date of a session to a new date
@@ -96,48 +147,65 @@ func (s *Sessions) ShiftExpiration(ctx context.Context) {
 
 // UpdateExpiration change expire date of a session to a new date
 // by using timeout value passed by `expires` receiver.
+// It's limited by the `Config#AbsoluteExpiration`.
 func (s *Sessions) UpdateExpiration(ctx context.Context, expires time.Duration) {
-	cookieValue := s.decodeCookieValue(GetCookie(ctx, s.config.Cookie))
+	if s.config.Stateless {
+		if sess := s.startStateless(ctx); !sess.isNew && (expires > 0 || expires == -1) {
+			sess.Lifetime.Time = sess.store.shift(s.capExpiration(sess, expires))
+			fire(s.provider.updateListeners, ctx, sess)
+		}
+		return
+	}
+
+	cookieValue := s.sessionID(ctx)
 
 	if cookieValue != "" {
+		sess, found := s.provider.get(cookieValue)
+		if found {
+			expires = s.capExpiration(sess, expires)
+		}
+
 		// we should also allow it to expire when the browser closed
 		if s.provider.UpdateExpiration(cookieValue, expires) || expires == -1 {
 			s.updateCookie(ctx, cookieValue, expires)
+
+			if found {
+				fire(s.provider.updateListeners, ctx, sess)
+			}
 		}
 	}
 }
 
-// DestroyListener is the form of a destroy listener.
-// Look `OnDestroy` for more.
-type DestroyListener func(sid string)
+// Destroy remove the session data and remove the associated cookie.
+func (s *Sessions) Destroy(ctx context.Context) {
+	// the next `Get` of the request starts a new session.
+	ctx.Values().Remove(sessionContextKey + s.config.Cookie)
 
-// OnDestroy registers one or more destroy listeners.
-// A destroy listener is fired when a session has been removed entirely from the server (the entry) and client-side (the cookie).
-// Note that if a destroy listener is blocking, then the session manager will delay respectfully,
-// use a goroutine inside the listener to avoid that behavior.
-func (s *Sessions) OnDestroy(listeners ...DestroyListener) {
-	for _, ln := range listeners {
-		s.provider.registerDestroyListener(ln)
+	if s.config.Stateless {
+		s.startStateless(ctx).Destroy()
+		return
 	}
-}
 
-// Destroy remove the session data and remove the associated cookie.
-func (s *Sessions) Destroy(ctx context.Context) {
-	cookieValue := GetCookie(ctx, s.config.Cookie)
 	// decode the client's cookie value in order to find the server's session id
 	// to destroy the session data.
-	cookieValue = s.decodeCookieValue(cookieValue)
+	cookieValue := s.sessionID(ctx)
 	if cookieValue == "" { // nothing to destroy
 		return
 	}
 	RemoveCookie(ctx, s.config)
 
-	s.provider.Destroy(cookieValue)
+	if s.config.JWT {
+		// the next `Start` of the request does not read the token again.
+		ctx.Values().Set(jwtContextKey+s.config.Cookie, new(jwtToken))
+	}
+
+	s.provider.destroy(ctx, cookieValue)
 }
 
 // DestroyByID removes the session entry
 // from the server-side memory (and database if registered).
 // Client's session cookie will still exist but it will be reseted on the next request.
+// The stateless sessions can not be destroyed by their id, see `Config#Stateless`.
 //
 // It's safe to use it even if you are not sure if a session with that id exists.
 //
@@ -150,10 +218,21 @@ func (s *Sessions) DestroyByID(sid string) {
 // DestroyAll removes all sessions
 // from the server-side memory (and database if registered).
 // Client's session cookie will still exist but it will be reseted on the next request.
+// The stateless sessions can not be destroyed by this method, see `Config#Stateless`.
 func (s *Sessions) DestroyAll() {
 	s.provider.DestroyAll()
 }
 
+// sessionID returns the session id of the request's cookie,
+// or the subject of the request's token if the `Config#JWT` is true.
+func (s *Sessions) sessionID(ctx context.Context) string {
+	if s.config.JWT {
+		return s.requestToken(ctx).subject()
+	}
+
+	return s.decodeCookieValue(GetCookie(ctx, s.config.cookieName(ctx)))
+}
+
 // let's keep these funcs simple, we can do it with two lines but we may add more things in the future.
 func (s *Sessions) decodeCookieValue(cookieValue string) string {
 	if cookieValue == "" {
@@ -1,12 +1,20 @@
 package sessions_test
 
 import (
+	"encoding/gob"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/iris-contrib/httpexpect"
 	"github.com/kataras/iris"
 	"github.com/kataras/iris/context"
 	"github.com/kataras/iris/httptest"
 	"github.com/kataras/iris/sessions"
+	"github.com/kataras/iris/sessions/remember"
 )
 
 func TestSessions(t *testing.T) {
@@ -192,3 +200,788 @@ func TestFlashMessages(t *testing.T) {
 	e.POST("/set").WithJSON(values).Expect().Status(iris.StatusOK)
 	e.GET("/get_single").Expect().Status(iris.StatusOK).Body().Equal(valueSingleValue)
 }
+
+func TestSessionsCookieOptions(t *testing.T) {
+	app := iris.New()
+
+	sess := sessions.New(sessions.Config{
+		Cookie: "sid",
+		CookieOptions: []context.CookieOption{
+			context.CookieSameSite(context.SameSiteLax),
+			context.CookiePartitioned,
+			context.CookieHost,
+		},
+	})
+
+	app.Get("/set", func(ctx context.Context) {
+		sess.Start(ctx).Set("name", "iris")
+	})
+
+	app.Get("/get", func(ctx context.Context) {
+		ctx.WriteString(sess.Start(ctx).GetString("name"))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	setCookie := e.GET("/set").Expect().Status(iris.StatusOK).Header("Set-Cookie").Raw()
+	for _, attr := range []string{"__Host-sid=", "Path=/", "Secure", "SameSite=Lax", "Partitioned"} {
+		if !strings.Contains(setCookie, attr) {
+			t.Fatalf("expected the session cookie to contain %q but got %q", attr, setCookie)
+		}
+	}
+	if strings.Contains(setCookie, "Domain=") {
+		t.Fatalf("expected the __Host- session cookie to have no domain but got %q", setCookie)
+	}
+
+	sid := strings.TrimPrefix(strings.SplitN(setCookie, ";", 2)[0], "__Host-sid=")
+	e.GET("/get").WithCookie("__Host-sid", sid).Expect().Status(iris.StatusOK).Body().Equal("iris")
+}
+
+func TestStatelessSessions(t *testing.T) {
+	oldKey, key := []byte("0123456789abcdef"), []byte("fedcba9876543210fedcba9876543210")
+
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "mycustomsessionid", Stateless: true, StatelessKeys: [][]byte{key}})
+	testSessions(t, sess, app)
+
+	newStatelessApp := func(keys ...[]byte) *iris.Application {
+		app := iris.New()
+		sess := sessions.New(sessions.Config{Cookie: "sid", Stateless: true, StatelessKeys: keys, StatelessMaxSize: 512})
+
+		app.Get("/set", func(ctx context.Context) {
+			s := sess.Start(ctx)
+			s.Set("name", "iris")
+			s.Set("large", strings.Repeat("x", 1024))
+			s.SetFlash("notice", "saved")
+		})
+
+		app.Get("/get", func(ctx context.Context) {
+			s := sess.Start(ctx)
+			ctx.Writef("%s %v %s", s.GetString("name"), s.Get("large"), s.GetFlashString("notice"))
+		})
+
+		return app
+	}
+
+	e := httptest.New(t, newStatelessApp(oldKey), httptest.URL("http://example.com"))
+	r := e.GET("/set").Expect().Status(iris.StatusOK)
+	value := r.Cookie("sid").Value().Raw()
+	if n := len(r.Raw().Header["Set-Cookie"]); n != 1 {
+		t.Fatalf("expected a single session cookie but got %d", n)
+	}
+
+	// another instance, with a rotated key, reads the cookie and re-encrypts it by its current key.
+	e = httptest.New(t, newStatelessApp(key, oldKey), httptest.URL("http://example.com"))
+	r = e.GET("/get").WithCookie("sid", value).Expect().Status(iris.StatusOK)
+	r.Body().Equal("iris <nil> saved")
+	rotated := r.Cookie("sid").Value().Raw()
+
+	// the flash message is removed after fetch once.
+	e.GET("/get").WithCookie("sid", rotated).Expect().Status(iris.StatusOK).Body().Equal("iris <nil> ")
+
+	// the old key is removed.
+	e = httptest.New(t, newStatelessApp(key), httptest.URL("http://example.com"))
+	e.GET("/get").WithCookie("sid", value).Expect().Status(iris.StatusOK).Body().Equal(" <nil> ")
+	e.GET("/get").WithCookie("sid", rotated).Expect().Status(iris.StatusOK).Body().Equal("iris <nil> ")
+}
+
+func TestSessionsExpirationPolicies(t *testing.T) {
+	newApp := func(cfg sessions.Config) *iris.Application {
+		app := iris.New()
+		sess := sessions.New(cfg)
+
+		app.Get("/set", func(ctx context.Context) {
+			sess.Start(ctx).Set("name", "iris")
+		})
+
+		app.Get("/get", func(ctx context.Context) {
+			s := sess.Start(ctx)
+			ctx.JSON(s.GetAll())
+		})
+
+		return app
+	}
+
+	idle := httptest.New(t, newApp(sessions.Config{
+		Cookie:      "sid",
+		Expires:     time.Hour,
+		IdleTimeout: 2 * time.Second,
+	}), httptest.URL("http://example.com"))
+
+	absolute := httptest.New(t, newApp(sessions.Config{
+		Cookie:             "sid",
+		Expires:            time.Hour,
+		SlidingExpiration:  true,
+		AbsoluteExpiration: 2 * time.Second,
+	}), httptest.URL("http://example.com"))
+
+	expected := map[string]interface{}{"name": "iris"}
+
+	idleSid := idle.GET("/set").Expect().Status(iris.StatusOK).Cookie("sid").Value().Raw()
+	idle.GET("/get").WithCookie("sid", idleSid).Expect().Status(iris.StatusOK).JSON().Object().Equal(expected)
+
+	c := absolute.GET("/set").Expect().Status(iris.StatusOK).Cookie("sid")
+	if maxAge := c.Raw().MaxAge; maxAge <= 0 || maxAge > 2 {
+		t.Fatalf("expected the session cookie to be limited by the absolute expiration but got max age %d", maxAge)
+	}
+	absoluteSid := c.Value().Raw()
+	// the sliding expiration sends the cookie on each request.
+	r := absolute.GET("/get").WithCookie("sid", absoluteSid).Expect().Status(iris.StatusOK)
+	r.JSON().Object().Equal(expected)
+	r.Cookie("sid").Value().Equal(absoluteSid)
+
+	time.Sleep(2100 * time.Millisecond)
+
+	idle.GET("/get").WithCookie("sid", idleSid).Expect().Status(iris.StatusOK).JSON().Object().Empty()
+	absolute.GET("/get").WithCookie("sid", absoluteSid).Expect().Status(iris.StatusOK).JSON().Object().Empty()
+}
+
+func TestSessionsRotateID(t *testing.T) {
+	for _, stateless := range []bool{false, true} {
+		app := iris.New()
+		sess := sessions.New(sessions.Config{
+			Cookie:                   "sid",
+			RotateIDOnAuthentication: true,
+			Stateless:                stateless,
+			StatelessKeys:            [][]byte{[]byte("0123456789abcdef")},
+		})
+
+		destroyed := false
+		sess.OnDestroy(func(context.Context, *sessions.Session) { destroyed = true })
+
+		app.Get("/login", func(ctx context.Context) {
+			s := sess.Start(ctx)
+			s.Set("theme", "dark")
+			oldID := s.ID()
+
+			if err := s.Authenticate(ctx, "user", "iris"); err != nil {
+				t.Fatal(err)
+			}
+
+			if s.ID() == oldID || sess.Start(ctx).ID() != s.ID() {
+				t.Fatalf("expected the session id to be rotated on the same request")
+			}
+		})
+
+		app.Get("/get", func(ctx context.Context) {
+			s := sess.Start(ctx)
+			ctx.Writef("%s %s", s.GetString("theme"), s.GetString("user"))
+		})
+
+		e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+		oldID := e.GET("/get").Expect().Status(iris.StatusOK).Cookie("sid").Value().Raw()
+		r := e.GET("/login").WithCookie("sid", oldID).Expect().Status(iris.StatusOK)
+		if n := len(r.Raw().Header["Set-Cookie"]); n != 1 {
+			t.Fatalf("expected a single session cookie but got %d", n)
+		}
+		newID := r.Cookie("sid").Value().Raw()
+
+		e.GET("/get").WithCookie("sid", newID).Expect().Status(iris.StatusOK).Body().Equal("dark iris")
+		if !stateless {
+			// the old id is invalidated.
+			e.GET("/get").WithCookie("sid", oldID).Expect().Status(iris.StatusOK).Body().Equal(" ")
+		}
+
+		if destroyed {
+			t.Fatalf("expected the rotation to not fire the destroy listeners")
+		}
+	}
+}
+
+func TestFlashMessagesView(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "sid"})
+
+	templates := map[string]string{
+		"flashes.html": `{{ if hasFlash }}{{ flash "notice" }}{{ range $key, $message := flashes }} {{ $key }}={{ $message }}{{ end }}{{ end }}`,
+	}
+	tmpl := iris.HTML("", ".html").Binary(func(name string) ([]byte, error) {
+		return []byte(templates[name]), nil
+	}, func() []string { return []string{"flashes.html"} })
+	for name, fn := range sessions.FlashFuncs {
+		tmpl.AddFunc(name, fn)
+	}
+	app.RegisterView(tmpl)
+
+	app.Get("/set", func(ctx context.Context) {
+		s := sess.Start(ctx)
+		s.SetFlash("notice", "saved")
+		s.SetFlash("count", 3)
+		s.SetFlash("ok", "true")
+	})
+
+	app.Get("/typed", func(ctx context.Context) {
+		s := sess.Start(ctx)
+		count, err := s.GetFlashInt("count")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx.Writef("%s %d %v %d", s.PeekFlashString("notice"), count, s.GetFlashBooleanDefault("ok", false), s.GetFlashIntDefault("missing", -2))
+	})
+
+	app.Get("/view", sess.FlashHandler, func(ctx context.Context) {
+		ctx.View("flashes.html")
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	sid := e.GET("/set").Expect().Status(iris.StatusOK).Cookie("sid").Value().Raw()
+	e.GET("/typed").WithCookie("sid", sid).Expect().Status(iris.StatusOK).Body().Equal("saved 3 true -2")
+	// the read messages are removed, the peeked one is rendered.
+	e.GET("/view").WithCookie("sid", sid).Expect().Status(iris.StatusOK).Body().Equal("saved notice=saved")
+	e.GET("/view").WithCookie("sid", sid).Expect().Status(iris.StatusOK).Body().Empty()
+}
+
+func TestSessionsEvents(t *testing.T) {
+	for _, stateless := range []bool{false, true} {
+		app := iris.New()
+		sess := sessions.New(sessions.Config{
+			Cookie:        "sid",
+			Stateless:     stateless,
+			StatelessKeys: [][]byte{[]byte("0123456789abcdef")},
+		})
+
+		var (
+			mu     sync.Mutex
+			events []string
+		)
+		listener := func(event string) sessions.Listener {
+			return func(ctx context.Context, s *sessions.Session) {
+				mu.Lock()
+				events = append(events, fmt.Sprintf("%s:%s:%v", event, s.GetString("user"), ctx != nil))
+				mu.Unlock()
+			}
+		}
+
+		sess.OnCreate(listener("create"))
+		sess.OnUpdate(listener("update"))
+		sess.OnDestroy(listener("destroy"))
+
+		app.Get("/login", func(ctx context.Context) {
+			sess.Start(ctx).Set("user", "iris")
+		})
+
+		app.Get("/extend", func(ctx context.Context) {
+			sess.Start(ctx)
+			sess.UpdateExpiration(ctx, time.Hour)
+		})
+
+		app.Get("/logout", func(ctx context.Context) {
+			sess.Destroy(ctx)
+		})
+
+		e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+		sid := e.GET("/login").Expect().Status(iris.StatusOK).Cookie("sid").Value().Raw()
+		r := e.GET("/extend").WithCookie("sid", sid).Expect().Status(iris.StatusOK)
+		if stateless {
+			sid = r.Cookie("sid").Value().Raw()
+		}
+		e.GET("/logout").WithCookie("sid", sid).Expect().Status(iris.StatusOK)
+
+		// the values of server-side sessions are changed without a context.
+		expected := []string{"create::true", fmt.Sprintf("update:iris:%v", stateless), "update:iris:true", "destroy:iris:true"}
+		if got := strings.Join(events, " "); got != strings.Join(expected, " ") {
+			t.Fatalf("[stateless: %v] expected events: %v but got: %v", stateless, expected, events)
+		}
+	}
+}
+
+type testUser struct {
+	Name  string
+	Roles []string
+}
+
+func init() {
+	gob.Register(testUser{})
+}
+
+func TestSessionsDecode(t *testing.T) {
+	defer func() { sessions.DefaultTranscoder = sessions.JSONTranscoder }()
+
+	for _, transcoder := range []sessions.Transcoder{sessions.JSONTranscoder, sessions.GobTranscoder, sessions.MsgPackTranscoder} {
+		sessions.DefaultTranscoder = transcoder
+
+		for _, stateless := range []bool{false, true} {
+			app := iris.New()
+			sess := sessions.New(sessions.Config{
+				Cookie:        "sid",
+				Stateless:     stateless,
+				StatelessKeys: [][]byte{[]byte("0123456789abcdef")},
+			})
+
+			expected := testUser{Name: "iris", Roles: []string{"admin"}}
+
+			app.Get("/set", func(ctx context.Context) {
+				s := sess.Start(ctx)
+				s.Set("user", expected)
+				s.SetFlash("user", &expected)
+			})
+
+			app.Get("/get", func(ctx context.Context) {
+				s := sess.Start(ctx)
+
+				var user, flashUser testUser
+				if err := s.Decode("user", &user); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.DecodeFlash("user", &flashUser); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := s.Decode("missing", &user); err == nil {
+					t.Fatalf("expected an error for a missing value")
+				}
+
+				ctx.JSON([]testUser{user, flashUser})
+			})
+
+			e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+			sid := e.GET("/set").Expect().Status(iris.StatusOK).Cookie("sid").Value().Raw()
+			e.GET("/get").WithCookie("sid", sid).Expect().Status(iris.StatusOK).JSON().Equal([]testUser{expected, expected})
+		}
+	}
+
+	// the values of the databases are decoded to an interface{} by the transcoder.
+	b, err := sessions.GobTranscoder.Marshal(testUser{Name: "iris"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value interface{}
+	if err = sessions.GobTranscoder.Unmarshal(b, &value); err != nil {
+		t.Fatal(err)
+	}
+
+	if user, ok := value.(testUser); !ok || user.Name != "iris" {
+		t.Fatalf("expected a testUser but got %#v", value)
+	}
+}
+
+func TestSessionsSweep(t *testing.T) {
+	app := iris.New()
+	// a long interval, the expired sessions are swept manually.
+	sess := sessions.New(sessions.Config{Cookie: "sid", Expires: time.Second, GCInterval: time.Hour})
+
+	app.Get("/", func(ctx context.Context) {
+		sess.Start(ctx).Set("key", "value")
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	e.GET("/").Expect().Status(iris.StatusOK)
+
+	if n, err := sess.Sweep(); err != nil || n != 0 {
+		t.Fatalf("expected no expired sessions but got %d: %v", n, err)
+	}
+
+	if stats := sess.Stats(); stats.Active != 1 || stats.Expired != 0 {
+		t.Fatalf("expected 1 active session but got %#v", stats)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+
+	// not removed by a timer.
+	if stats := sess.Stats(); stats.Active != 1 {
+		t.Fatalf("expected 1 active session before the sweep but got %#v", stats)
+	}
+
+	if n, err := sess.Sweep(); err != nil || n != 1 {
+		t.Fatalf("expected 1 expired session but got %d: %v", n, err)
+	}
+
+	if expected, got := `{"active":0,"expired":1,"errors":0}`, sess.StatsVar().String(); got != expected {
+		t.Fatalf("expected stats: %s but got: %s", expected, got)
+	}
+}
+
+func TestSessionsLock(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "sid", LockTimeout: 100 * time.Millisecond})
+
+	app.Get("/inc", sess.LockHandler, func(ctx context.Context) {
+		s := sess.Start(ctx)
+		n := s.GetIntDefault("n", 0)
+		// let the concurrent requests read the same value, if they are not locked.
+		time.Sleep(time.Millisecond)
+		s.Set("n", n+1)
+	})
+
+	app.Get("/get", func(ctx context.Context) {
+		s := sess.Start(ctx)
+
+		unlock, err := s.Lock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = s.Lock(); err == nil || err.Error() != sessions.ErrLockTimeout.Error() {
+			t.Fatalf("expected the lock timeout error but got: %v", err)
+		}
+		unlock()
+
+		if err = s.Update(func() error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx.Writef("%d", s.GetIntDefault("n", 0))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	sid := e.GET("/inc").Expect().Status(iris.StatusOK).Cookie("sid").Value().Raw()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.GET("/inc").WithCookie("sid", sid).Expect().Status(iris.StatusOK)
+		}()
+	}
+	wg.Wait()
+
+	e.GET("/get").WithCookie("sid", sid).Expect().Status(iris.StatusOK).Body().Equal("21")
+}
+
+func TestSessionsJWT(t *testing.T) {
+	app := iris.New()
+	previousKey := []byte("previous-key")
+	sess := sessions.New(sessions.Config{
+		Cookie:    "sid",
+		Expires:   time.Hour,
+		JWT:       true,
+		JWTKeys:   [][]byte{[]byte("current-key"), previousKey},
+		JWTIssuer: "iris",
+	})
+
+	app.Get("/set", func(ctx context.Context) {
+		sess.Start(ctx).Set("key", "value")
+		ctx.WriteString(sess.Token(ctx))
+	})
+
+	app.Get("/get", func(ctx context.Context) {
+		ctx.WriteString(sess.Start(ctx).GetString("key"))
+	})
+
+	app.Get("/claims", func(ctx context.Context) {
+		ctx.WriteString(sess.SetClaims(ctx, map[string]interface{}{"role": "admin"}))
+	})
+
+	app.Get("/role", func(ctx context.Context) {
+		ctx.Writef("%v %v", sess.Claims(ctx)["role"], sess.Start(ctx).GetString("key"))
+	})
+
+	app.Get("/id", func(ctx context.Context) {
+		ctx.WriteString(sess.Start(ctx).ID())
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	r := e.GET("/set").Expect().Status(iris.StatusOK)
+	token := r.Body().Raw()
+	r.Cookie("sid").Value().Equal(token)
+
+	// the header is preferred over the cookie.
+	e.GET("/get").WithHeader("Authorization", "Bearer "+token).Expect().Status(iris.StatusOK).Body().Equal("value")
+	e.GET("/get").WithHeader("Authorization", "Bearer "+token+"x").Expect().Status(iris.StatusOK).Body().Empty()
+
+	claimsToken := e.GET("/claims").WithHeader("Authorization", "Bearer "+token).Expect().Status(iris.StatusOK).Body().Raw()
+	if claimsToken == token {
+		t.Fatalf("expected a new token")
+	}
+	e.GET("/role").WithHeader("Authorization", "Bearer "+claimsToken).Expect().Status(iris.StatusOK).Body().Equal("admin value")
+
+	// the tokens of the previous key are verified, the "none" algorithm and the other issuers are not.
+	issue := func(config sessions.Config) (token, sid string) {
+		issuer := sessions.New(config)
+		app := iris.New()
+		app.Get("/", func(ctx context.Context) {
+			token, sid = issuer.Token(ctx), issuer.Start(ctx).ID()
+		})
+		httptest.New(t, app).GET("/").Expect().Status(iris.StatusOK)
+		return
+	}
+
+	previous, sid := issue(sessions.Config{JWT: true, JWTKeys: [][]byte{previousKey}, JWTIssuer: "iris"})
+	e.GET("/id").WithHeader("Authorization", "Bearer "+previous).Expect().Status(iris.StatusOK).Body().Equal(sid)
+
+	none := "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0." + strings.Split(previous, ".")[1] + "."
+	e.GET("/id").WithHeader("Authorization", "Bearer "+none).Expect().Status(iris.StatusOK).Body().NotEqual(sid)
+
+	other, sid := issue(sessions.Config{JWT: true, JWTKeys: [][]byte{previousKey}, JWTIssuer: "other"})
+	e.GET("/id").WithHeader("Authorization", "Bearer "+other).Expect().Status(iris.StatusOK).Body().NotEqual(sid)
+}
+
+func TestSessionsHandler(t *testing.T) {
+	app := iris.New()
+	preferences := sessions.New(sessions.Config{
+		Cookie:        "prefs",
+		Expires:       24 * time.Hour,
+		Stateless:     true,
+		StatelessKeys: [][]byte{[]byte("0123456789abcdef")},
+	})
+	auth := sessions.New(sessions.Config{Cookie: "sid", Expires: time.Hour})
+
+	app.Use(preferences.Handler())
+
+	app.Get("/theme", func(ctx context.Context) {
+		if sessions.GetByCookie(ctx, "sid") != nil {
+			t.Fatalf("expected no session of an unbound manager")
+		}
+
+		sessions.Get(ctx).Set("theme", "dark")
+	})
+
+	admin := app.Party("/admin", auth.Handler())
+	admin.Get("/login", func(ctx context.Context) {
+		sessions.Get(ctx).Set("user", "iris")
+		// the same session is returned on the same request.
+		if sessions.Get(ctx) != sessions.Get(ctx) {
+			t.Fatalf("expected the same session")
+		}
+	})
+	admin.Get("/", func(ctx context.Context) {
+		ctx.Writef("%s %s", sessions.Get(ctx).GetString("user"), sessions.GetByCookie(ctx, "prefs").GetString("theme"))
+	})
+	admin.Get("/logout", func(ctx context.Context) {
+		auth.Destroy(ctx)
+		ctx.WriteString(sessions.Get(ctx).GetString("user"))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	prefs := e.GET("/theme").Expect().Status(iris.StatusOK).Cookie("prefs").Value().Raw()
+	sid := e.GET("/admin/login").Expect().Status(iris.StatusOK).Cookie("sid").Value().Raw()
+
+	e.GET("/admin/").WithCookie("prefs", prefs).WithCookie("sid", sid).Expect().Status(iris.StatusOK).Body().Equal("iris dark")
+	e.GET("/admin/logout").WithCookie("prefs", prefs).WithCookie("sid", sid).Expect().Status(iris.StatusOK).Body().Empty()
+}
+
+func TestSessionsRemember(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "sid"})
+
+	var stolen string
+	r := remember.New(sess, remember.NewMemStore(), remember.Config{
+		Cookie:  "remember",
+		OnTheft: func(ctx context.Context, userID string) { stolen = userID },
+	})
+
+	app.Use(r.Handler)
+
+	app.Get("/login", func(ctx context.Context) {
+		if err := sess.Start(ctx).Authenticate(ctx, "user_id", "iris"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.Remember(ctx, "iris"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	app.Get("/user", func(ctx context.Context) {
+		ctx.WriteString(sess.Get(ctx).GetString("user_id"))
+	})
+
+	app.Get("/logout", func(ctx context.Context) {
+		sess.Destroy(ctx)
+		if err := r.Forget(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// a new client for each request, without the cookies of the previous ones.
+	e := func() *httpexpect.Expect { return httptest.New(t, app, httptest.URL("http://example.com")) }
+
+	token := e().GET("/login").Expect().Status(iris.StatusOK).Cookie("remember").Value().Raw()
+
+	// a new session, without the "sid" cookie, is re-established by the token and the token is rotated.
+	res := e().GET("/user").WithCookie("remember", token).Expect().Status(iris.StatusOK)
+	res.Body().Equal("iris")
+	rotated := res.Cookie("remember").Value().Raw()
+	if rotated == token || strings.Split(rotated, ":")[0] != strings.Split(token, ":")[0] {
+		t.Fatalf("expected the secret to be rotated but the series to be kept")
+	}
+
+	// the previous secret is accepted for a grace period.
+	e().GET("/user").WithCookie("remember", token).Expect().Status(iris.StatusOK).Body().Equal("iris")
+
+	// a wrong secret of the series is a theft, all of the user's tokens are removed.
+	stolenToken := strings.Split(token, ":")[0] + ":wrong"
+	e().GET("/user").WithCookie("remember", stolenToken).Expect().Status(iris.StatusOK).Body().Empty()
+	if stolen != "iris" {
+		t.Fatalf("expected a theft of the user's token")
+	}
+	e().GET("/user").WithCookie("remember", rotated).Expect().Status(iris.StatusOK).Body().Empty()
+
+	// the token is removed on logout.
+	token = e().GET("/login").Expect().Status(iris.StatusOK).Cookie("remember").Value().Raw()
+	e().GET("/logout").WithCookie("remember", token).Expect().Status(iris.StatusOK)
+	e().GET("/user").WithCookie("remember", token).Expect().Status(iris.StatusOK).Body().Empty()
+}
+
+func TestSessionsMigrate(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "sid", Expires: time.Hour})
+
+	app.Get("/set", func(ctx context.Context) {
+		sess.Start(ctx).Set("key", "value")
+	})
+
+	app.Get("/get", func(ctx context.Context) {
+		ctx.WriteString(sess.Start(ctx).GetString("key"))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	e.GET("/set").Expect().Status(iris.StatusOK)
+
+	// the in-memory database of another manager as the new database.
+	dst := sessions.New(sessions.Config{}).Database()
+	src := sess.Database()
+
+	var lifetime sessions.LifeTime
+	result, err := sessions.Migrate(src, dst, sessions.MigrateOptions{
+		OnSession: func(sid string, lt sessions.LifeTime) { lifetime = lt },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Migrated != 1 || result.Skipped != 0 {
+		t.Fatalf("expected 1 migrated session but got %#v", result)
+	}
+
+	if d := lifetime.DurationUntilExpiration(); d <= 59*time.Minute || d > time.Hour {
+		t.Fatalf("expected the remaining lifetime of the session but got %s", d)
+	}
+
+	// the existing sessions are skipped.
+	if result, _ = sessions.Migrate(src, dst); result.Migrated != 0 || result.Skipped != 1 {
+		t.Fatalf("expected 1 skipped session but got %#v", result)
+	}
+
+	// the client is not logged out.
+	sess.UseDatabase(dst)
+	e.GET("/get").Expect().Status(iris.StatusOK).Body().Equal("value")
+}
+
+func TestSessionsIDGenerator(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "sid", SessionIDLength: 40, SessionIDAlphabet: sessions.SessionIDAlphabetHex})
+
+	app.Get("/", func(ctx context.Context) {
+		ctx.WriteString(sess.Start(ctx).ID())
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	id := e.GET("/").Expect().Status(iris.StatusOK).Body().Raw()
+	if !regexp.MustCompile("^[0-9a-f]{40}$").MatchString(id) {
+		t.Fatalf("expected a session id of 40 hexadecimal digits but got '%s'", id)
+	}
+
+	expectPanic := func(name string, cfg sessions.Config) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("%s: expected a panic", name)
+			}
+		}()
+		sessions.New(cfg)
+	}
+
+	// 8 * 4 = 32 bits of entropy.
+	expectPanic("weak", sessions.Config{SessionIDLength: 8, SessionIDAlphabet: sessions.SessionIDAlphabetHex})
+	expectPanic("alphabet", sessions.Config{SessionIDAlphabet: "aab"})
+	expectPanic("cookie-safe", sessions.Config{SessionIDAlphabet: "ab;"})
+
+	// the custom generators are not called by the New, i.e the deterministic ones of the tests.
+	calls := 0
+	counter := sessions.New(sessions.Config{Cookie: "sid", SessionIDGenerator: func() string {
+		calls++
+		return fmt.Sprintf("id%d", calls)
+	}})
+	if calls != 0 {
+		t.Fatalf("expected the custom generator to not be called by the New but it was called %d times", calls)
+	}
+
+	app = iris.New()
+	app.Get("/", func(ctx context.Context) {
+		ctx.WriteString(counter.Start(ctx).ID())
+	})
+
+	e = httptest.New(t, app, httptest.URL("http://example.com"))
+	id = e.GET("/").Expect().Status(iris.StatusOK).Body().Raw()
+	if id != "id1" || calls != 1 {
+		t.Fatalf("expected the first id of the custom generator but got '%s' after %d calls", id, calls)
+	}
+}
+
+func TestSessionsCookieAttributes(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{
+		Cookie:         "sid",
+		CookieDomain:   ".example.com",
+		CookiePath:     "/app",
+		CookieSameSite: context.SameSiteLax,
+	})
+
+	handler := func(ctx context.Context) {
+		sess.Start(ctx).Set("key", "value")
+	}
+
+	app.Get("/app", handler)
+	// the attributes of an embedded Party are overridden.
+	app.Party("/widget", sess.Handler(context.CookieSameSite(context.SameSiteNone), context.CookiePartitioned)).Get("/", handler)
+
+	e := httptest.New(t, app, httptest.URL("http://app.example.com"))
+
+	cookie := e.GET("/app").Expect().Status(iris.StatusOK).Header("Set-Cookie").Raw()
+	for _, attr := range []string{"sid=", "Path=/app", "Domain=example.com", "HttpOnly", "SameSite=Lax"} {
+		if !strings.Contains(cookie, attr) {
+			t.Fatalf("expected the cookie attribute %s but got: %s", attr, cookie)
+		}
+	}
+	if strings.Contains(cookie, "Secure") {
+		t.Fatalf("expected a non-secure cookie but got: %s", cookie)
+	}
+
+	cookie = e.GET("/widget").Expect().Status(iris.StatusOK).Header("Set-Cookie").Raw()
+	for _, attr := range []string{"sid=", "Path=/app", "Secure", "SameSite=None", "Partitioned"} {
+		if !strings.Contains(cookie, attr) {
+			t.Fatalf("expected the cookie attribute %s but got: %s", attr, cookie)
+		}
+	}
+}
+
+func TestEncryptedTranscoder(t *testing.T) {
+	oldKey, newKey := []byte("the-old-aes-128-"), []byte("the-new-aes-128-")
+
+	old, err := sessions.NewEncryptedTranscoder(sessions.GobTranscoder, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := old.Marshal("value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "value") {
+		t.Fatalf("expected an encrypted value")
+	}
+
+	// the values of the old key are still decrypted after a new one is prepended.
+	rotated, _ := sessions.NewEncryptedTranscoder(sessions.GobTranscoder, newKey, oldKey)
+	var value interface{}
+	if err = rotated.Unmarshal(b, &value); err != nil || value != "value" {
+		t.Fatalf("expected the decrypted value but got: %v: %v", value, err)
+	}
+
+	other, _ := sessions.NewEncryptedTranscoder(sessions.GobTranscoder, newKey)
+	if err = other.Unmarshal(b, &value); err == nil {
+		t.Fatalf("expected an error for a value of an unknown key")
+	}
+
+	if _, err = sessions.NewEncryptedTranscoder(nil, []byte("short")); err == nil {
+		t.Fatalf("expected an error for an invalid key")
+	}
+}
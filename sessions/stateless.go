@@ -0,0 +1,381 @@
+package sessions
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+)
+
+// DefaultStatelessMaxSize is the default maximum size of the stateless session's cookie value.
+const DefaultStatelessMaxSize = 4000
+
+// ErrStatelessCookieTooLarge is logged when a value of a stateless session
+// does not fit to its cookie, see `Config#StatelessMaxSize`.
+var ErrStatelessCookieTooLarge = errors.New("session cookie: larger than %d bytes")
+
+// statelessContextKey is the prefix of the context's key of the request's stateless session.
+const statelessContextKey = "iris.session.stateless."
+
+// releasedSession is the context's value of a released stateless session.
+type releasedSession struct{}
+
+// statelessPayload is the encrypted value of a stateless session's cookie.
+type statelessPayload struct {
+	ID string `json:"id" msgpack:"id"`
+	// Expires is the expiration as unix seconds, zero for none.
+	Expires int64                  `json:"exp,omitempty" msgpack:"exp,omitempty"`
+	Values  map[string]interface{} `json:"v,omitempty" msgpack:"v,omitempty"`
+	Flashes map[string]interface{} `json:"f,omitempty" msgpack:"f,omitempty"`
+}
+
+// newStatelessCiphers returns the AES-GCM ciphers of the "keys", it panics on invalid key sizes.
+func newStatelessCiphers(keys [][]byte) []cipher.AEAD {
+	if len(keys) == 0 {
+		panic("sessions: stateless mode requires at least one of the StatelessKeys")
+	}
+
+	ciphers, err := newCiphers(keys)
+	if err != nil {
+		panic("sessions: invalid stateless key: " + err.Error())
+	}
+
+	return ciphers
+}
+
+// newCiphers returns the AES-GCM ciphers of the "keys".
+func newCiphers(keys [][]byte) ([]cipher.AEAD, error) {
+	ciphers := make([]cipher.AEAD, len(keys))
+	for i, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if ciphers[i], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+
+	return ciphers, nil
+}
+
+// seal encrypts and authenticates the "payload" by the first key,
+// the cookie's name is authenticated as well, so a value can not be moved to another cookie.
+func (s *Sessions) seal(payload []byte) (string, error) {
+	aead := s.ciphers[0]
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(payload)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, payload, []byte(s.config.Cookie))), nil
+}
+
+// open decrypts the cookie's "value" by any of the keys,
+// "rotated" reports whether it was not encrypted by the first one.
+func (s *Sessions) open(value string) (payload []byte, rotated bool, ok bool) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, false, false
+	}
+
+	for i, aead := range s.ciphers {
+		if len(b) < aead.NonceSize() {
+			continue
+		}
+
+		nonce, ciphertext := b[:aead.NonceSize()], b[aead.NonceSize():]
+		if payload, err = aead.Open(nil, nonce, ciphertext, []byte(s.config.Cookie)); err == nil {
+			return payload, i > 0, true
+		}
+	}
+
+	return nil, false, false
+}
+
+// startStateless returns the request's stateless session, it decodes its cookie once per request.
+func (s *Sessions) startStateless(ctx context.Context) *Session {
+	key := statelessContextKey + s.config.Cookie
+	v := ctx.Values().Get(key)
+	if sess, ok := v.(*Session); ok {
+		return sess
+	}
+
+	var (
+		p       statelessPayload
+		rotated bool
+		ok      bool
+	)
+	// the request's cookie is not read again after the session's release.
+	if _, isReleased := v.(releasedSession); !isReleased {
+		var b []byte
+		if b, rotated, ok = s.open(GetCookie(ctx, s.config.cookieName(ctx))); ok {
+			ok = DefaultTranscoder.Unmarshal(b, &p) == nil && p.ID != "" &&
+				(p.Expires == 0 || p.Expires > time.Now().Unix())
+		}
+	}
+
+	if !ok {
+		p = statelessPayload{ID: s.config.SessionIDGenerator()}
+		if expires := s.config.sessionExpires(); expires > 0 {
+			p.Expires = time.Now().Add(expires).Unix()
+		}
+	}
+
+	store := &cookieStore{
+		sessions: s,
+		ctx:      ctx,
+		id:       p.ID,
+		expires:  p.Expires,
+		maxAge:   s.config.Expires,
+		values:   p.Values,
+		flashes:  p.Flashes,
+	}
+	if store.values == nil {
+		store.values = make(map[string]interface{})
+	}
+
+	sess := &Session{
+		sid:      p.ID,
+		isNew:    !ok,
+		provider: s.provider,
+		flashes:  make(map[string]*flashMessage, len(p.Flashes)),
+		store:    store,
+	}
+	for k, v := range p.Flashes {
+		sess.flashes[k] = &flashMessage{value: v}
+	}
+	if p.Expires > 0 {
+		sess.Lifetime = LifeTime{Time: time.Unix(p.Expires, 0)}
+	}
+
+	ctx.Values().Set(key, sess)
+
+	// send the cookie of a new session, like the server-side sessions,
+	// and re-encrypt the rotated ones by the current key.
+	if !ok || rotated {
+		store.mu.Lock()
+		if err := store.saveLocked(); err != nil {
+			store.logError("session: %v", err)
+		}
+		store.mu.Unlock()
+	}
+
+	if !ok {
+		fire(s.provider.createListeners, ctx, sess)
+	}
+
+	return sess
+}
+
+// cookieStore is the `Database` of a stateless session, it keeps the values of the request's session
+// and it writes them to the session's cookie on each change, the "sid" arguments are ignored.
+type cookieStore struct {
+	sessions *Sessions
+	ctx      context.Context
+	id       string
+
+	mu      sync.RWMutex
+	expires int64
+	// maxAge is the expiration of the cookie when the session does not expire, see `Config#Expires`.
+	maxAge  time.Duration
+	values  map[string]interface{}
+	flashes map[string]interface{}
+}
+
+var _ Database = (*cookieStore)(nil)
+
+// saveLocked writes the session's cookie, the caller should lock the "mu".
+func (c *cookieStore) saveLocked() error {
+	b, err := DefaultTranscoder.Marshal(statelessPayload{
+		ID:      c.id,
+		Expires: c.expires,
+		Values:  c.values,
+		Flashes: c.flashes,
+	})
+	if err != nil {
+		return err
+	}
+
+	value, err := c.sessions.seal(b)
+	if err != nil {
+		return err
+	}
+
+	if maxSize := c.sessions.config.StatelessMaxSize; len(value) > maxSize {
+		return ErrStatelessCookieTooLarge.Format(maxSize)
+	}
+
+	expires := c.maxAge
+	if c.expires > 0 {
+		expires = time.Until(time.Unix(c.expires, 0))
+	}
+
+	c.sessions.setStatelessCookie(c.ctx, value, expires)
+	return nil
+}
+
+// logError logs the "err" of the cookie and it counts it, see `Sessions#Stats`.
+func (c *cookieStore) logError(format string, args ...interface{}) {
+	atomic.AddUint64(&c.sessions.provider.errors, 1)
+	c.ctx.Logger().Errorf(format, args...)
+}
+
+// setFlashes replaces the flash messages of the cookie, the removed ones are not included.
+func (c *cookieStore) setFlashes(flashes map[string]interface{}) {
+	c.mu.Lock()
+	c.flashes = flashes
+	if err := c.saveLocked(); err != nil {
+		c.logError("session: flashes: %v", err)
+	}
+	c.mu.Unlock()
+}
+
+// shift updates the expiration of the session, see `Sessions#UpdateExpiration`.
+func (c *cookieStore) shift(expires time.Duration) time.Time {
+	c.mu.Lock()
+	c.expires = 0
+	c.maxAge = expires
+	if expires > 0 {
+		c.expires = time.Now().Add(expires).Unix()
+	}
+
+	var t time.Time
+	if c.expires > 0 {
+		t = time.Unix(c.expires, 0)
+	}
+
+	if err := c.saveLocked(); err != nil {
+		c.logError("session: %v", err)
+	}
+	c.mu.Unlock()
+
+	return t
+}
+
+func (c *cookieStore) Acquire(sid string, expires time.Duration) LifeTime {
+	return LifeTime{}
+}
+
+// Set sets the value to the cookie, the value is not stored if the cookie becomes too large.
+func (c *cookieStore) Set(sid string, lifetime LifeTime, key string, value interface{}, immutable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, existed := c.values[key]
+	c.values[key] = value
+
+	if err := c.saveLocked(); err != nil {
+		if existed {
+			c.values[key] = old
+		} else {
+			delete(c.values, key)
+		}
+
+		c.logError("session: unable to set the value of key: '%s': %v", key, err)
+	}
+}
+
+func (c *cookieStore) Get(sid string, key string) interface{} {
+	c.mu.RLock()
+	v := c.values[key]
+	c.mu.RUnlock()
+	return v
+}
+
+func (c *cookieStore) Visit(sid string, cb func(key string, value interface{})) {
+	c.mu.RLock()
+	values := make(map[string]interface{}, len(c.values))
+	for k, v := range c.values {
+		values[k] = v
+	}
+	c.mu.RUnlock()
+
+	for k, v := range values {
+		cb(k, v)
+	}
+}
+
+func (c *cookieStore) Len(sid string) int {
+	c.mu.RLock()
+	n := len(c.values)
+	c.mu.RUnlock()
+	return n
+}
+
+func (c *cookieStore) Delete(sid string, key string) (deleted bool) {
+	c.mu.Lock()
+	if _, deleted = c.values[key]; deleted {
+		delete(c.values, key)
+		if err := c.saveLocked(); err != nil {
+			c.logError("session: %v", err)
+		}
+	}
+	c.mu.Unlock()
+	return
+}
+
+func (c *cookieStore) Clear(sid string) {
+	c.mu.Lock()
+	c.values = make(map[string]interface{})
+	if err := c.saveLocked(); err != nil {
+		c.logError("session: %v", err)
+	}
+	c.mu.Unlock()
+}
+
+// Release removes the session's cookie, the next `Sessions#Start` of the request starts a new session.
+func (c *cookieStore) Release(sid string) {
+	c.mu.Lock()
+	c.values = make(map[string]interface{})
+	c.flashes = nil
+	c.sessions.setStatelessCookie(c.ctx, "", -1)
+	c.mu.Unlock()
+
+	c.ctx.Values().Set(statelessContextKey+c.sessions.config.Cookie, releasedSession{})
+}
+
+// setStatelessCookie sets the stateless session's cookie, it replaces
+// the cookie which was set by a previous change of the session on the same request.
+// An empty "value" deletes the cookie and a negative "expires" removes it when the browser closes.
+func (s *Sessions) setStatelessCookie(ctx context.Context, value string, expires time.Duration) {
+	cookie := &http.Cookie{
+		Name:     s.config.Cookie,
+		Value:    value,
+		Path:     "/",
+		Domain:   formatCookieDomain(ctx, s.config.DisableSubdomainPersistence),
+		HttpOnly: true,
+	}
+
+	options := s.config.cookieOptions(ctx)
+	if value == "" {
+		cookie.Expires = CookieExpireDelete
+		cookie.MaxAge = -1
+		// the options may modify the expiration, delete it after them.
+		options = append(options, context.CookieMaxAge(-1))
+	} else if expires >= 0 {
+		if expires == 0 { // unlimited life
+			cookie.Expires = CookieExpireUnlimited
+		} else {
+			cookie.Expires = time.Now().Add(expires)
+		}
+		cookie.MaxAge = int(time.Until(cookie.Expires).Seconds())
+	}
+
+	if ctx.Request().TLS != nil && s.config.CookieSecureTLS {
+		cookie.Secure = true
+	}
+
+	removeResponseCookie(ctx, s.config.cookieName(ctx))
+	AddCookie(ctx, cookie, false, options...)
+}
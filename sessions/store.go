@@ -0,0 +1,48 @@
+package sessions
+
+import (
+	"github.com/kataras/iris/context"
+)
+
+// Store is the minimal API of a request's session, the `*Session` implements it.
+//
+// The mvc controllers and the hero handlers can depend on it instead of the `*Session`,
+// so they can be tested with a fake one:
+//
+// mvcApp.Register(sess.Store)
+//
+// type UserController struct {
+//     Session sessions.Store
+// }
+type Store interface {
+	// ID returns the session's id.
+	ID() string
+	// Get returns the value of the "key", nil if it's missing.
+	Get(key string) interface{}
+	// Set sets the value of the "key".
+	Set(key string, value interface{})
+	// Delete removes the value of the "key" and reports whether it was there.
+	Delete(key string) bool
+	// Destroy destroys the session.
+	Destroy()
+}
+
+var _ Store = (*Session)(nil)
+
+// Store returns the session of the request as a `Store`, the session is started once per request, see `Get`,
+// and its `Destroy` removes the client's cookie too, like the `Sessions#Destroy`.
+// It's the dependency of the `Store` for the mvc controllers and the hero handlers.
+func (s *Sessions) Store(ctx context.Context) Store {
+	return &requestStore{Session: s.Get(ctx), ctx: ctx, manager: s}
+}
+
+// requestStore is the `Store` of the `Sessions#Store`.
+type requestStore struct {
+	*Session
+	ctx     context.Context
+	manager *Sessions
+}
+
+func (r *requestStore) Destroy() {
+	r.manager.Destroy(r.ctx)
+}
@@ -1,6 +1,18 @@
 package sessions
 
-import "encoding/json"
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/core/msgpack"
+)
 
 type (
 	// Marshaler is the common marshaler interface, used by transcoder.
@@ -18,9 +30,23 @@ type (
 	}
 )
 
-// DefaultTranscoder is the default transcoder across databases, it's the JSON by default.
-// Change it if you want a different serialization/deserialization inside your session databases (when `UseDatabase` is used).
-var DefaultTranscoder = defaultTranscoder{}
+var (
+	// JSONTranscoder is the JSON transcoder, the values are decoded to maps, slices, strings, float64 and bools,
+	// use the `Session#Decode` to convert them to the type they were stored with.
+	JSONTranscoder Transcoder = defaultTranscoder{}
+	// GobTranscoder is the gob transcoder, the values are decoded to the type they were stored with,
+	// the custom types should be registered by the `gob.Register`, i.e gob.Register(User{}).
+	GobTranscoder Transcoder = gobTranscoder{}
+	// MsgPackTranscoder is the MessagePack transcoder, see the `core/msgpack` package,
+	// the values are decoded like the JSON ones, use the `Session#Decode` to convert them
+	// to the type they were stored with.
+	MsgPackTranscoder Transcoder = msgpackTranscoder{}
+)
+
+// DefaultTranscoder is the default transcoder across databases and the cookies of the stateless sessions, it's the JSON by default.
+// Change it, before any session is started, if you want a different serialization/deserialization
+// inside your session databases (when `UseDatabase` is used), i.e to the `GobTranscoder` or to the `MsgPackTranscoder`.
+var DefaultTranscoder = JSONTranscoder
 
 type defaultTranscoder struct{}
 
@@ -47,3 +73,136 @@ func (d defaultTranscoder) Unmarshal(b []byte, outPtr interface{}) error {
 
 	return json.Unmarshal(b, outPtr)
 }
+
+type msgpackTranscoder struct{}
+
+func (d msgpackTranscoder) Marshal(value interface{}) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (d msgpackTranscoder) Unmarshal(b []byte, outPtr interface{}) error {
+	return msgpack.Unmarshal(b, outPtr)
+}
+
+func init() {
+	// the types which are encoded as interfaces by the gob transcoder.
+	gob.Register(statelessPayload{})
+	gob.Register(time.Time{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+type gobTranscoder struct{}
+
+func (d gobTranscoder) Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	// encode it as an interface, so it can be decoded to an interface{} by the databases.
+	if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (d gobTranscoder) Unmarshal(b []byte, outPtr interface{}) error {
+	var value interface{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&value); err != nil {
+		return err
+	}
+
+	return assign(value, outPtr)
+}
+
+var errAssign = errors.New("unable to assign a value of type %T to %T")
+
+// assign sets the "value" to the "outPtr" if it's assignable to its element,
+// a pointer value is assigned to a non-pointer element as well.
+func assign(value interface{}, outPtr interface{}) error {
+	if ptr, ok := outPtr.(*interface{}); ok {
+		*ptr = value
+		return nil
+	}
+
+	out := reflect.ValueOf(outPtr)
+	if value == nil || out.Kind() != reflect.Ptr || out.IsNil() {
+		return errAssign.Format(value, outPtr)
+	}
+
+	v, elem := reflect.ValueOf(value), out.Elem()
+	if v.Kind() == reflect.Ptr && !v.IsNil() && !v.Type().AssignableTo(elem.Type()) {
+		v = v.Elem()
+	}
+
+	if !v.Type().AssignableTo(elem.Type()) {
+		return errAssign.Format(value, outPtr)
+	}
+
+	elem.Set(v)
+	return nil
+}
+
+var (
+	errEncryptionKeys = errors.New("session: encryption: at least one key is required")
+	errDecrypt        = errors.New("session: encryption: unable to decrypt the value")
+)
+
+// NewEncryptedTranscoder returns a `Transcoder` which encrypts and authenticates (AES-GCM) the data
+// of the "transcoder", i.e for the encryption at rest of the sessions' values of a file-based database.
+// A nil "transcoder" is the `DefaultTranscoder`.
+// The "keys" should be 16, 24 or 32 bytes long for the AES-128, AES-192 or AES-256,
+// the first one encrypts the values and all of them decrypt them, so a new key can be prepended
+// and the old ones can be removed when the values they encrypted are expired.
+func NewEncryptedTranscoder(transcoder Transcoder, keys ...[]byte) (Transcoder, error) {
+	if len(keys) == 0 {
+		return nil, errEncryptionKeys
+	}
+
+	ciphers, err := newCiphers(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedTranscoder{transcoder: transcoder, ciphers: ciphers}, nil
+}
+
+type encryptedTranscoder struct {
+	transcoder Transcoder
+	ciphers    []cipher.AEAD
+}
+
+func (t *encryptedTranscoder) inner() Transcoder {
+	if t.transcoder == nil {
+		return DefaultTranscoder
+	}
+
+	return t.transcoder
+}
+
+func (t *encryptedTranscoder) Marshal(value interface{}) ([]byte, error) {
+	b, err := t.inner().Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	aead := t.ciphers[0]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(b)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, b, nil), nil
+}
+
+func (t *encryptedTranscoder) Unmarshal(b []byte, outPtr interface{}) error {
+	for _, aead := range t.ciphers {
+		if len(b) < aead.NonceSize() {
+			continue
+		}
+
+		if data, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil); err == nil {
+			return t.inner().Unmarshal(data, outPtr)
+		}
+	}
+
+	return errDecrypt
+}
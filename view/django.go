@@ -406,3 +406,22 @@ func (s *DjangoEngine) ExecuteWriter(w io.Writer, filename string, layout string
 
 	return fmt.Errorf("template with name %s doesn't exists in the dir", filename)
 }
+
+// ExecuteWriterFuncs is like `ExecuteWriter` but the "funcs" are passed
+// to this execution's context only, i.e {{ csrfToken() }}, see `Context#ViewFunc`.
+func (s *DjangoEngine) ExecuteWriterFuncs(w io.Writer, filename string, layout string, bindingData interface{}, funcs map[string]interface{}) error {
+	if len(funcs) == 0 {
+		return s.ExecuteWriter(w, filename, layout, bindingData)
+	}
+
+	data := getPongoContext(bindingData)
+	ctx := make(pongo2.Context, len(data)+len(funcs))
+	for k, v := range data {
+		ctx[k] = v
+	}
+	for k, v := range funcs {
+		ctx[k] = v
+	}
+
+	return s.ExecuteWriter(w, filename, layout, ctx)
+}
@@ -1,5 +1,7 @@
 package view
 
+import "io"
+
 // EngineFuncer is an addition of a view engine,
 // if a view engine implements that interface
 // then iris can add some closed-relative iris functions
@@ -9,6 +11,14 @@ type EngineFuncer interface {
 	AddFunc(funcName string, funcBody interface{})
 }
 
+// EngineFuncsExecutor is the interface which a view engine should implement
+// in order to support the per-execution functions of the `Context#ViewFunc`.
+type EngineFuncsExecutor interface {
+	// ExecuteWriterFuncs is like the `Engine#ExecuteWriter`
+	// but the "funcs" are available to this execution only.
+	ExecuteWriterFuncs(w io.Writer, filename string, layout string, bindingData interface{}, funcs map[string]interface{}) error
+}
+
 // these will be added to all template engines used
 // and completes the EngineFuncer interface.
 //
@@ -0,0 +1,127 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// testTemplates writes the "files" to a new temp directory and returns it.
+func testTemplates(t testing.TB, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "iris-view")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, contents := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// testExecuteFuncs executes the "filename" concurrently, each time with a different "user" function,
+// and checks that each execution sees its own one only.
+func testExecuteFuncs(t *testing.T, e interface {
+	Engine
+	EngineFuncsExecutor
+}, filename, layout, expectedFormat string) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			user := fmt.Sprintf("user%d", i)
+			var b bytes.Buffer
+			err := e.ExecuteWriterFuncs(&b, filename, layout, nil, map[string]interface{}{
+				"user": func() string { return user },
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if expected := fmt.Sprintf(expectedFormat, user); b.String() != expected {
+				t.Errorf("expected '%s' but got: '%s'", expected, b.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// the functions of the executions do not leak to the next ones.
+	var b bytes.Buffer
+	if err := e.ExecuteWriter(&b, filename, layout, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := fmt.Sprintf(expectedFormat, "guest"); b.String() != expected {
+		t.Fatalf("expected the registered function after the executions '%s' but got: '%s'", expected, b.String())
+	}
+}
+
+func TestHTMLExecuteWriterFuncs(t *testing.T) {
+	dir := testTemplates(t, map[string]string{
+		"index.html":  `Hello {{ user }}`,
+		"layout.html": `<main>{{ yield }}</main>`,
+	})
+	defer os.RemoveAll(dir)
+
+	e := HTML(dir, ".html")
+	e.AddFunc("user", func() string { return "guest" })
+	if err := e.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	testExecuteFuncs(t, e, "index.html", "", "Hello %s")
+	testExecuteFuncs(t, e, "index.html", "layout.html", "<main>Hello %s</main>")
+}
+
+func TestDjangoExecuteWriterFuncs(t *testing.T) {
+	dir := testTemplates(t, map[string]string{
+		"index.html": `Hello {{ user() }}`,
+	})
+	defer os.RemoveAll(dir)
+
+	e := Django(dir, ".html")
+	e.AddFunc("user", func() string { return "guest" })
+	if err := e.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	testExecuteFuncs(t, e, "index.html", "", "Hello %s")
+}
+
+func BenchmarkHTMLExecuteWriterFuncs(b *testing.B) {
+	for _, n := range []int{1, 10, 100} {
+		files := map[string]string{"index.html": `Hello {{ user }}`}
+		for i := 0; i < n-1; i++ {
+			files[fmt.Sprintf("partial%d.html", i)] = `{{ define "block" }}partial{{ end }}`
+		}
+
+		dir := testTemplates(b, files)
+		e := HTML(dir, ".html")
+		e.AddFunc("user", func() string { return "guest" })
+		if err := e.Load(); err != nil {
+			b.Fatal(err)
+		}
+
+		funcs := map[string]interface{}{"user": func() string { return "user" }}
+		b.Run(fmt.Sprintf("%d templates", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := e.ExecuteWriterFuncs(ioutil.Discard, "index.html", "", nil, funcs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		os.RemoveAll(dir)
+	}
+}
@@ -34,6 +34,10 @@ type (
 		//
 		middleware func(name string, contents string) (string, error)
 		Templates  *template.Template
+		// pristine is a never executed clone of the Templates,
+		// the templates can not be cloned after their first execution,
+		// see `ExecuteWriterFuncs`.
+		pristine *template.Template
 		//
 	}
 )
@@ -218,7 +222,7 @@ func (s *HTMLEngine) Load() error {
 		// }
 
 		// embedded
-		return s.loadAssets()
+		return s.keepPristine(s.loadAssets())
 	}
 
 	// load from directory, make the dir absolute here too.
@@ -228,7 +232,26 @@ func (s *HTMLEngine) Load() error {
 	}
 	// change the directory field configuration, load happens after directory has been setted, so we will not have any problems here.
 	s.directory = dir
-	return s.loadDirectory()
+	return s.keepPristine(s.loadDirectory())
+}
+
+// keepPristine clones the just loaded templates for the `ExecuteWriterFuncs`,
+// even if some of them failed to load, like the `ExecuteWriter` does.
+func (s *HTMLEngine) keepPristine(err error) error {
+	if s.Templates == nil {
+		return err
+	}
+
+	pristine, cloneErr := s.Templates.Clone()
+	if cloneErr != nil {
+		if err == nil {
+			err = cloneErr
+		}
+		return err
+	}
+
+	s.pristine = pristine
+	return err
 }
 
 // loadDirectory builds the templates from directory.
@@ -375,17 +398,17 @@ func (s *HTMLEngine) loadAssets() error {
 	return templateErr
 }
 
-func (s *HTMLEngine) executeTemplateBuf(name string, binding interface{}) (*bytes.Buffer, error) {
+func (s *HTMLEngine) executeTemplateBuf(templates *template.Template, name string, binding interface{}) (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
-	err := s.Templates.ExecuteTemplate(buf, name, binding)
+	err := templates.ExecuteTemplate(buf, name, binding)
 
 	return buf, err
 }
 
-func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
+func (s *HTMLEngine) layoutFuncsFor(templates *template.Template, name string, binding interface{}) {
 	funcs := template.FuncMap{
 		"yield": func() (template.HTML, error) {
-			buf, err := s.executeTemplateBuf(name, binding)
+			buf, err := s.executeTemplateBuf(templates, name, binding)
 			// Return safe HTML here since we are rendering our own template.
 			return template.HTML(buf.String()), err
 		},
@@ -394,8 +417,8 @@ func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
 		},
 		"partial": func(partialName string) (template.HTML, error) {
 			fullPartialName := fmt.Sprintf("%s-%s", partialName, name)
-			if s.Templates.Lookup(fullPartialName) != nil {
-				buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			if templates.Lookup(fullPartialName) != nil {
+				buf, err := s.executeTemplateBuf(templates, fullPartialName, binding)
 				return template.HTML(buf.String()), err
 			}
 			return "", nil
@@ -408,14 +431,14 @@ func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
 			ext := filepath.Ext(name)
 			root := name[:len(name)-len(ext)]
 			fullPartialName := fmt.Sprintf("%s%s%s", root, partialName, ext)
-			if s.Templates.Lookup(fullPartialName) != nil {
-				buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			if templates.Lookup(fullPartialName) != nil {
+				buf, err := s.executeTemplateBuf(templates, fullPartialName, binding)
 				return template.HTML(buf.String()), err
 			}
 			return "", nil
 		},
 		"render": func(fullPartialName string) (template.HTML, error) {
-			buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			buf, err := s.executeTemplateBuf(templates, fullPartialName, binding)
 			return template.HTML(buf.String()), err
 		},
 	}
@@ -423,20 +446,20 @@ func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
 	for k, v := range s.layoutFuncs {
 		funcs[k] = v
 	}
-	if tpl := s.Templates.Lookup(name); tpl != nil {
+	if tpl := templates.Lookup(name); tpl != nil {
 		tpl.Funcs(funcs)
 	}
 }
 
-func (s *HTMLEngine) runtimeFuncsFor(name string, binding interface{}) {
+func (s *HTMLEngine) runtimeFuncsFor(templates *template.Template, name string, binding interface{}) {
 	funcs := template.FuncMap{
 		"render": func(fullPartialName string) (template.HTML, error) {
-			buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			buf, err := s.executeTemplateBuf(templates, fullPartialName, binding)
 			return template.HTML(buf.String()), err
 		},
 	}
 
-	if tpl := s.Templates.Lookup(name); tpl != nil {
+	if tpl := templates.Lookup(name); tpl != nil {
 		tpl.Funcs(funcs)
 	}
 }
@@ -459,11 +482,60 @@ func (s *HTMLEngine) ExecuteWriter(w io.Writer, name string, layout string, bind
 	layout = getLayout(layout, s.layout)
 
 	if layout != "" {
-		s.layoutFuncsFor(name, bindingData)
+		s.layoutFuncsFor(s.Templates, name, bindingData)
 		name = layout
 	} else {
-		s.runtimeFuncsFor(name, bindingData)
+		s.runtimeFuncsFor(s.Templates, name, bindingData)
 	}
 
 	return s.Templates.ExecuteTemplate(w, name, bindingData)
 }
+
+// ExecuteWriterFuncs is like `ExecuteWriter` but the "funcs" override
+// the template functions for this execution only, the shared templates are not modified,
+// so it's safe to pass functions bound to a request, see `Context#ViewFunc`.
+//
+// The templates are parsed once, so the functions' names should be registered
+// by the `AddFunc` too, i.e with a placeholder function.
+//
+// The html/template resolves the functions of all of the associated templates,
+// the layouts and the partials, at the set's level, so each execution clones the whole set,
+// its cost grows with the number of the loaded templates (see the BenchmarkHTMLExecuteWriterFuncs).
+// Prefer the binding data, or the `AddFunc` for the functions which are not bound to a request,
+// on the hot paths of applications with many templates.
+func (s *HTMLEngine) ExecuteWriterFuncs(w io.Writer, name string, layout string, bindingData interface{}, funcs map[string]interface{}) error {
+	if len(funcs) == 0 {
+		return s.ExecuteWriter(w, name, layout, bindingData)
+	}
+
+	if s.reload {
+		s.rmu.Lock()
+		defer s.rmu.Unlock()
+		if err := s.Load(); err != nil {
+			return err
+		}
+	}
+
+	if s.pristine == nil {
+		return fmt.Errorf("html: templates are not loaded")
+	}
+
+	// a clone per execution, the functions of the layout and the request's ones
+	// are bound to this execution only.
+	templates, err := s.pristine.Clone()
+	if err != nil {
+		return err
+	}
+	templates.Funcs(funcs)
+
+	layout = getLayout(layout, s.layout)
+
+	if layout != "" {
+		s.layoutFuncsFor(templates, name, bindingData)
+		name = layout
+	} else {
+		s.runtimeFuncsFor(templates, name, bindingData)
+	}
+
+	return templates.ExecuteTemplate(w, name, bindingData)
+}
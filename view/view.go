@@ -57,6 +57,27 @@ func (v *View) ExecuteWriter(w io.Writer, filename string, layout string, bindin
 	return e.ExecuteWriter(w, filename, layout, bindingData)
 }
 
+// ExecuteWriterFuncs calls the correct view Engine's ExecuteWriterFuncs func, if it's an `EngineFuncsExecutor`,
+// otherwise the "funcs" are ignored and its ExecuteWriter is called instead.
+func (v *View) ExecuteWriterFuncs(w io.Writer, filename string, layout string, bindingData interface{}, funcs map[string]interface{}) error {
+	if len(filename) > 2 {
+		if filename[0] == '/' { // omit first slash
+			filename = filename[1:]
+		}
+	}
+
+	e := v.Find(filename)
+	if e == nil {
+		return errNoViewEngineForExt.Format(filepath.Ext(filename))
+	}
+
+	if executor, ok := e.(EngineFuncsExecutor); ok && len(funcs) > 0 {
+		return executor.ExecuteWriterFuncs(w, filename, layout, bindingData, funcs)
+	}
+
+	return e.ExecuteWriter(w, filename, layout, bindingData)
+}
+
 // AddFunc adds a function to all registered engines.
 // Each template engine that supports functions has its own AddFunc too.
 func (v *View) AddFunc(funcName string, funcBody interface{}) {
@@ -0,0 +1,194 @@
+package websocket
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+var (
+	// ErrAckTimeout is returned by the `Connection#EmitWithAck` when the client does not reply in time.
+	ErrAckTimeout = errors.New("websocket: the reply of the message is timed out")
+	// ErrAckDisconnected is returned by the `Connection#EmitWithAck` when the connection is disconnected before the reply.
+	ErrAckDisconnected = errors.New("websocket: the connection is disconnected before the reply of the message")
+
+	errInvalidAckMessage = errors.New("websocket: invalid acknowledgement message: %s")
+)
+
+// RequestFunc is the callback of the messages of the client's `EmitWithAck`, its result is the reply,
+// it should be a string, int, bool or any valid JSON/Go struct, see `Connection#OnRequest`.
+type RequestFunc func(message interface{}) (reply interface{})
+
+// ackMessage is a request or a reply of the `EmitWithAck`.
+// The messages are "iris-websocket-message:$event;6;$id;$type;$data" and "iris-websocket-message:$event;7;$id;$type;$data",
+// where the $type;$data are the ones of the event's messages.
+type ackMessage struct {
+	id    string
+	reply bool
+	data  interface{}
+}
+
+// EmitWithAck sends a message to the client, like the `Emit`, and waits for its reply, up to the "timeout".
+// A zero or negative "timeout" waits until the reply or the disconnection of the connection.
+// The client replies by its `OnRequest` handler of the event, or with a null
+// if it has only `On` listeners, so it is an acknowledgement of the message too.
+//
+// It blocks until the reply, it should not be called by the listeners of the connection,
+// they are called by its reader which reads the reply, they can call it in a new goroutine.
+// The messages of the `EmitWithAck` are always text messages, the event's `Codec` is not used.
+func (c *connection) EmitWithAck(event string, data interface{}, timeout time.Duration) (interface{}, error) {
+	if err := c.server.checkEventPayload(event, data); err != nil {
+		return nil, err
+	}
+
+	id := strconv.FormatUint(atomic.AddUint64(&c.ackID, 1), 10)
+	message, err := websocketAckSerialize(websocketAckRequestMessageType, event, id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	replyCh := make(chan interface{}, 1)
+	c.acksMu.Lock()
+	if atomic.LoadUint32(&c.disconnected) == 1 {
+		c.acksMu.Unlock()
+		return nil, ErrAckDisconnected
+	}
+	if c.acks == nil {
+		c.acks = make(map[string]chan interface{})
+	}
+	c.acks[id] = replyCh
+	c.acksMu.Unlock()
+
+	c.writeDefault(message)
+
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	select {
+	case reply, ok := <-replyCh:
+		if !ok {
+			return nil, ErrAckDisconnected
+		}
+		return reply, nil
+	case <-timeoutCh:
+		c.acksMu.Lock()
+		delete(c.acks, id)
+		c.acksMu.Unlock()
+		return nil, ErrAckTimeout
+	}
+}
+
+// OnRequest registers the handler of the messages of the client's `EmitWithAck` of the "event",
+// its result is sent to the client as the reply. One handler per event, the `On` listeners
+// of the event are not called for the requests, if it's missing they are and the reply is a null.
+func (c *connection) OnRequest(event string, cb RequestFunc) {
+	c.acksMu.Lock()
+	if c.onRequestListeners == nil {
+		c.onRequestListeners = make(map[string]RequestFunc)
+	}
+	c.onRequestListeners[event] = cb
+	c.acksMu.Unlock()
+}
+
+// cancelAcks stops the `EmitWithAck` which wait for their replies, it's called on disconnect.
+func (c *connection) cancelAcks() {
+	c.acksMu.Lock()
+	for id, replyCh := range c.acks {
+		close(replyCh)
+		delete(c.acks, id)
+	}
+	c.acksMu.Unlock()
+}
+
+// ackMessageReceived resolves the `EmitWithAck` of a reply or calls the handler of a request and sends its reply.
+func (c *connection) ackMessageReceived(event string, websocketMessage string) {
+	m, err := websocketAckDeserialize(event, websocketMessage)
+	if err != nil {
+		c.FireOnError(err)
+		return
+	}
+
+	if m.reply {
+		c.acksMu.Lock()
+		replyCh := c.acks[m.id]
+		delete(c.acks, m.id)
+		c.acksMu.Unlock()
+
+		if replyCh != nil {
+			replyCh <- m.data
+		}
+		return
+	}
+
+	c.acksMu.Lock()
+	cb := c.onRequestListeners[event]
+	c.acksMu.Unlock()
+
+	var reply interface{}
+	if cb != nil {
+		reply = cb(m.data)
+	} else if listeners := c.onEventListeners[event]; listeners != nil {
+		c.fireMessage(listeners, m.data)
+	}
+
+	message, err := websocketAckSerialize(websocketAckReplyMessageType, event, m.id, reply)
+	if err != nil {
+		c.FireOnError(err)
+		return
+	}
+
+	c.writeDefault(message)
+}
+
+// isAckMessage reports whether the custom message of the "event" is a request or a reply of the `EmitWithAck`.
+func isAckMessage(event string, websocketMessage string) bool {
+	idx := websocketMessagePrefixAndSepIdx + len(event) + 1
+	if len(websocketMessage) <= idx {
+		return false
+	}
+
+	t := websocketMessage[idx]
+	return t == '0'+byte(websocketAckRequestMessageType) || t == '0'+byte(websocketAckReplyMessageType)
+}
+
+// websocketAckSerialize returns the request or the reply, by the "ackType", of the "id" with the "data".
+func websocketAckSerialize(ackType websocketMessageType, event, id string, data interface{}) ([]byte, error) {
+	message, err := websocketMessageSerialize(event, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// iris-websocket-message:$event;$type;$data -> iris-websocket-message:$event;$ackType;$id;$type;$data
+	head := websocketMessagePrefixLen + len(event) + websocketMessageSeparatorLen
+	return []byte(message[:head] + ackType.String() + websocketMessageSeparator + id + websocketMessageSeparator + message[head:]), nil
+}
+
+// websocketAckDeserialize parses a request or a reply of the `EmitWithAck`.
+func websocketAckDeserialize(event string, websocketMessage string) (m ackMessage, err error) {
+	idx := websocketMessagePrefixAndSepIdx + len(event) + 1
+	m.reply = websocketMessage[idx] == '0'+byte(websocketAckReplyMessageType)
+
+	// $id;$type;$data
+	rest := websocketMessage[idx+1:]
+	if !strings.HasPrefix(rest, websocketMessageSeparator) {
+		return m, errInvalidAckMessage.Format(websocketMessage)
+	}
+	rest = rest[websocketMessageSeparatorLen:]
+
+	sep := strings.Index(rest, websocketMessageSeparator)
+	if sep <= 0 || len(rest) < sep+3 {
+		return m, errInvalidAckMessage.Format(websocketMessage)
+	}
+	m.id = rest[:sep]
+
+	// the $type;$data is the same as the one of the event's messages.
+	m.data, err = websocketMessageDeserialize(event, websocketMessage[:idx]+rest[sep+websocketMessageSeparatorLen:])
+	return
+}
@@ -0,0 +1,130 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// isError reports whether the "err" is the "e" error.
+func isError(err error, e errors.Error) bool {
+	got, ok := err.(errors.Error)
+	return ok && e.Equal(got)
+}
+
+type testAck struct {
+	reply interface{}
+	err   error
+}
+
+// emitWithAck calls the `EmitWithAck` of the "c" in a new goroutine and returns the id of its request.
+func emitWithAck(t *testing.T, c *connection, underline *testConn, timeout time.Duration) (string, <-chan testAck) {
+	n := len(underline.written())
+	result := make(chan testAck, 1)
+	go func() {
+		reply, err := c.EmitWithAck("chat", "hello", timeout)
+		result <- testAck{reply, err}
+	}()
+
+	if !eventually(func() bool { return len(underline.written()) > n }) {
+		t.Fatalf("expected the request to be sent")
+	}
+
+	m, err := websocketAckDeserialize("chat", underline.written()[n])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.reply || m.data != "hello" {
+		t.Fatalf("expected a request of 'hello' but got: %#v", m)
+	}
+
+	return m.id, result
+}
+
+func ackReply(t *testing.T, c *connection, id string, data interface{}) {
+	message, err := websocketAckSerialize(websocketAckReplyMessageType, "chat", id, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ackMessageReceived("chat", string(message))
+}
+
+func TestEmitWithAckReply(t *testing.T) {
+	s := New(Config{})
+	c, underline := newTestConnection(s)
+	defer c.Disconnect()
+
+	id, result := emitWithAck(t, c, underline, time.Second)
+
+	// the replies of unknown ids are ignored.
+	ackReply(t, c, id+"0", "unknown")
+	select {
+	case r := <-result:
+		t.Fatalf("expected the reply of an unknown id to be ignored but got: %#v", r)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	ackReply(t, c, id, "world")
+	if r := <-result; r.err != nil || r.reply != "world" {
+		t.Fatalf("expected the reply 'world' but got: %#v", r)
+	}
+
+	// a second reply of the same id is ignored too.
+	ackReply(t, c, id, "again")
+	c.acksMu.Lock()
+	pending := len(c.acks)
+	c.acksMu.Unlock()
+	if pending != 0 {
+		t.Fatalf("expected no pending acknowledgements but got: %d", pending)
+	}
+}
+
+func TestEmitWithAckTimeout(t *testing.T) {
+	s := New(Config{})
+	c, underline := newTestConnection(s)
+	defer c.Disconnect()
+
+	id, result := emitWithAck(t, c, underline, 20*time.Millisecond)
+	if r := <-result; !isError(r.err, ErrAckTimeout) {
+		t.Fatalf("expected the timeout error but got: %#v", r)
+	}
+
+	// the late reply is ignored.
+	ackReply(t, c, id, "late")
+
+	// a zero timeout waits until the reply.
+	id, result = emitWithAck(t, c, underline, 0)
+	select {
+	case r := <-result:
+		t.Fatalf("expected the zero timeout to wait for the reply but got: %#v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	ackReply(t, c, id, "world")
+	if r := <-result; r.err != nil || r.reply != "world" {
+		t.Fatalf("expected the reply 'world' but got: %#v", r)
+	}
+}
+
+func TestEmitWithAckDisconnect(t *testing.T) {
+	s := New(Config{})
+	c, underline := newTestConnection(s)
+
+	_, result := emitWithAck(t, c, underline, -1)
+	c.Disconnect()
+
+	select {
+	case r := <-result:
+		if !isError(r.err, ErrAckDisconnected) {
+			t.Fatalf("expected the disconnected error but got: %#v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the disconnection to stop the wait")
+	}
+
+	if _, err := c.EmitWithAck("chat", "hello", time.Second); !isError(err, ErrAckDisconnected) {
+		t.Fatalf("expected the disconnected error after the disconnection but got: %v", err)
+	}
+}
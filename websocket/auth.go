@@ -0,0 +1,54 @@
+package websocket
+
+import (
+	"net/http"
+)
+
+// AuthError is an error of the `Config#Authenticate` with the status code of the rejected upgrade request,
+// the other errors reject it with the 401 Unauthorized.
+type AuthError struct {
+	StatusCode int
+	Reason     string
+}
+
+func (e AuthError) Error() string {
+	return "websocket: " + e.Reason
+}
+
+var (
+	// ErrUnauthorized rejects the upgrade request with the 401 Unauthorized, see `Config#Authenticate`.
+	ErrUnauthorized = AuthError{StatusCode: http.StatusUnauthorized, Reason: "unauthorized"}
+	// ErrForbidden rejects the upgrade request with the 403 Forbidden, see `Config#Authenticate`.
+	ErrForbidden = AuthError{StatusCode: http.StatusForbidden, Reason: "forbidden"}
+)
+
+// authStatusCode returns the status code of a rejected upgrade request.
+func authStatusCode(err error) int {
+	if authErr, ok := err.(AuthError); ok && authErr.StatusCode > 0 {
+		return authErr.StatusCode
+	}
+
+	return http.StatusUnauthorized
+}
+
+// Identity returns the identity of the connection's client, the result of the `Config#Authenticate`,
+// it's empty if it's not set.
+func (c *connection) Identity() string {
+	return c.identity
+}
+
+// GetConnectionsByIdentity returns the connections of the "identity", i.e the ones of the tabs of a user,
+// see `Config#Authenticate`.
+func (s *Server) GetConnectionsByIdentity(identity string) []Connection {
+	var conns []Connection
+
+	s.mu.RLock()
+	for _, cKV := range s.connections {
+		if cKV.value.identity == identity {
+			conns = append(conns, cKV.value)
+		}
+	}
+	s.mu.RUnlock()
+
+	return conns
+}
@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+)
+
+func TestAuthenticate(t *testing.T) {
+	s := New(Config{
+		Authenticate: func(ctx context.Context) (string, error) {
+			switch ctx.URLParam("user") {
+			case "":
+				return "", errors.New("missing user")
+			case "banned":
+				return "", ErrForbidden
+			case "teapot":
+				return "", AuthError{StatusCode: http.StatusTeapot, Reason: "teapot"}
+			}
+			return ctx.URLParam("user"), nil
+		},
+	})
+
+	identities := make(chan string, 1)
+	s.OnConnection(func(c Connection) {
+		identities <- c.Identity()
+	})
+
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	app.Get("/ws", s.Handler())
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	tests := map[string]int{
+		"":             http.StatusUnauthorized,
+		"?user=":       http.StatusUnauthorized,
+		"?user=banned": http.StatusForbidden,
+		"?user=teapot": http.StatusTeapot,
+	}
+
+	for query, status := range tests {
+		_, resp, err := websocket.DefaultDialer.Dial(url+query, nil)
+		if err == nil || resp == nil || resp.StatusCode != status {
+			t.Fatalf("expected the upgrade of '%s' to be rejected with %d but got: %v", query, status, resp)
+		}
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?user=kataras", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if identity := <-identities; identity != "kataras" {
+		t.Fatalf("expected the identity of the connection to be 'kataras' but got: '%s'", identity)
+	}
+
+	if conns := s.GetConnectionsByIdentity("kataras"); len(conns) != 1 {
+		t.Fatalf("expected 1 connection of the identity but got: %d", len(conns))
+	}
+}
@@ -24,7 +24,10 @@ func ClientHandler() context.Handler {
 var ClientSource = []byte(`var websocketStringMessageType = 0;
 var websocketIntMessageType = 1;
 var websocketBoolMessageType = 2;
+var websocketBytesMessageType = 3;
 var websocketJSONMessageType = 4;
+var websocketAckRequestMessageType = 6;
+var websocketAckReplyMessageType = 7;
 var websocketMessagePrefix = "iris-websocket-message:";
 var websocketMessageSeparator = ";";
 var websocketMessagePrefixLen = websocketMessagePrefix.length;
@@ -32,6 +35,8 @@ var websocketMessageSeparatorLen = websocketMessageSeparator.length;
 var websocketMessagePrefixAndSepIdx = websocketMessagePrefixLen + websocketMessageSeparatorLen - 1;
 var websocketMessagePrefixIdx = websocketMessagePrefixLen - 1;
 var websocketMessageSeparatorIdx = websocketMessageSeparatorLen - 1;
+// the declared events of the server, their messages are decoded, see the Server#ClientSource.
+var websocketEvents = {};
 var Ws = (function () {
     //
     function Ws(endpoint, protocols) {
@@ -41,6 +46,10 @@ var Ws = (function () {
         this.disconnectListeners = [];
         this.nativeMessageListeners = [];
         this.messageListeners = {};
+        this.requestListeners = {};
+        // the EmitWithAck which wait for their replies, by id.
+        this.acks = {};
+        this.ackID = 0;
         if (!window["WebSocket"]) {
             return;
         }
@@ -59,6 +68,7 @@ var Ws = (function () {
             return null;
         });
         this.conn.onclose = (function (evt) {
+            _this.rejectAcks();
             _this.fireDisconnect();
             return null;
         });
@@ -119,13 +129,16 @@ var Ws = (function () {
         }
         var websocketMessageType = parseInt(websocketMessage.charAt(skipLen - 2));
         var theMessage = websocketMessage.substring(skipLen, websocketMessage.length);
+        return this.decodeData(websocketMessageType, theMessage);
+    };
+    Ws.prototype.decodeData = function (websocketMessageType, theMessage) {
         if (websocketMessageType == websocketIntMessageType) {
             return parseInt(theMessage);
         }
         else if (websocketMessageType == websocketBoolMessageType) {
-            return Boolean(theMessage);
+            return theMessage == "true";
         }
-        else if (websocketMessageType == websocketStringMessageType) {
+        else if (websocketMessageType == websocketStringMessageType || websocketMessageType == websocketBytesMessageType) {
             return theMessage;
         }
         else if (websocketMessageType == websocketJSONMessageType) {
@@ -162,7 +175,16 @@ var Ws = (function () {
         if (message.indexOf(websocketMessagePrefix) != -1) {
             var event_1 = this.getWebsocketCustomEvent(message);
             if (event_1 != "") {
+                var t = parseInt(message.charAt(websocketMessagePrefixLen + event_1.length + websocketMessageSeparatorLen));
+                if (t == websocketAckRequestMessageType || t == websocketAckReplyMessageType) {
+                    this.ackMessageReceived(event_1, t, message);
+                    return;
+                }
                 // it's a custom message
+                if (websocketEvents.hasOwnProperty(event_1)) {
+                    this.fireMessage(event_1, this.decodeMessage(event_1, message));
+                    return;
+                }
                 this.fireMessage(event_1, this.getCustomMessage(event_1, message));
                 return;
             }
@@ -228,6 +250,78 @@ var Ws = (function () {
         var messageStr = this.encodeMessage(event, data);
         this.EmitMessage(messageStr);
     };
+    // EmitWithAck sends an iris-custom websocket message and returns a promise of the server's reply,
+    // it's rejected if there is no reply in "timeout" milliseconds or on disconnect,
+    // a zero or negative "timeout" waits until the reply or the disconnect.
+    Ws.prototype.EmitWithAck = function (event, data, timeout) {
+        var _this = this;
+        var id = String(++this.ackID);
+        return new Promise(function (resolve, reject) {
+            var timer;
+            if (timeout > 0) {
+                timer = setTimeout(function () {
+                    delete _this.acks[id];
+                    reject(new Error("websocket: the reply of the message is timed out"));
+                }, timeout);
+            }
+            _this.acks[id] = { resolve: resolve, reject: reject, timer: timer };
+            _this.EmitMessage(_this.encodeAck(websocketAckRequestMessageType, event, id, data));
+        });
+    };
+    // OnRequest registers the handler of the messages of the server's EmitWithAck of the event,
+    // its result, or the result of its promise, is the reply.
+    // If it's missing then the On listeners are called and the reply is a null.
+    Ws.prototype.OnRequest = function (event, cb) {
+        this.requestListeners[event] = cb;
+    };
+    // iris-websocket-message:$event;$type;$data -> iris-websocket-message:$event;$ackType;$id;$type;$data
+    Ws.prototype.encodeAck = function (ackType, event, id, data) {
+        var m = (data === null || typeof (data) === "undefined") ? this._msg(event, websocketJSONMessageType, "null") : this.encodeMessage(event, data);
+        var head = websocketMessagePrefixLen + event.length + websocketMessageSeparatorLen;
+        return m.substring(0, head) + String(ackType) + websocketMessageSeparator + id + websocketMessageSeparator + m.substring(head);
+    };
+    Ws.prototype.ackMessageReceived = function (event, ackType, websocketMessage) {
+        var _this = this;
+        // $id;$type;$data
+        var rest = websocketMessage.substring(websocketMessagePrefixLen + event.length + 2 * websocketMessageSeparatorLen + 1);
+        var sep = rest.indexOf(websocketMessageSeparator);
+        if (sep <= 0) {
+            return;
+        }
+        var id = rest.substring(0, sep);
+        var data = this.decodeData(parseInt(rest.charAt(sep + 1)), rest.substring(sep + 3));
+        if (ackType == websocketAckReplyMessageType) {
+            var ack = this.acks[id];
+            if (ack) {
+                delete this.acks[id];
+                clearTimeout(ack.timer);
+                ack.resolve(data);
+            }
+            return;
+        }
+        var reply = null;
+        var cb = this.requestListeners[event];
+        if (cb) {
+            reply = cb(data);
+        }
+        else {
+            this.fireMessage(event, rest.substring(sep + 3));
+        }
+        if (reply && typeof (reply.then) === "function") {
+            reply.then(function (r) { _this.EmitMessage(_this.encodeAck(websocketAckReplyMessageType, event, id, r)); });
+            return;
+        }
+        this.EmitMessage(this.encodeAck(websocketAckReplyMessageType, event, id, reply));
+    };
+    Ws.prototype.rejectAcks = function () {
+        for (var id in this.acks) {
+            if (this.acks.hasOwnProperty(id)) {
+                clearTimeout(this.acks[id].timer);
+                this.acks[id].reject(new Error("websocket: the connection is disconnected before the reply of the message"));
+            }
+        }
+        this.acks = {};
+    };
     return Ws;
 }());
 `)
@@ -3,8 +3,11 @@
 const websocketStringMessageType = 0;
 const websocketIntMessageType = 1;
 const websocketBoolMessageType = 2;
-// bytes is missing here for reasons I will explain somewhen
+const websocketBytesMessageType = 3;
 const websocketJSONMessageType = 4;
+// the messages of the EmitWithAck and their replies.
+const websocketAckRequestMessageType = 6;
+const websocketAckReplyMessageType = 7;
 
 const websocketMessagePrefix = "iris-websocket-message:";
 const websocketMessageSeparator = ";";
@@ -14,11 +17,20 @@ var websocketMessageSeparatorLen = websocketMessageSeparator.length;
 var websocketMessagePrefixAndSepIdx = websocketMessagePrefixLen + websocketMessageSeparatorLen - 1;
 var websocketMessagePrefixIdx = websocketMessagePrefixLen - 1;
 var websocketMessageSeparatorIdx = websocketMessageSeparatorLen - 1;
+// the declared events of the server, their messages are decoded, see the Server#ClientSource.
+var websocketEvents: { [event: string]: boolean } = {};
 
 type onConnectFunc = () => void;
 type onWebsocketDisconnectFunc = () => void;
 type onWebsocketNativeMessageFunc = (websocketMessage: string) => void;
 type onMessageFunc = (message: any) => void;
+type onRequestFunc = (message: any) => any;
+
+interface pendingAck {
+    resolve: (reply: any) => void;
+    reject: (err: Error) => void;
+    timer: number;
+}
 
 class Ws {
     private conn: WebSocket;
@@ -30,6 +42,11 @@ class Ws {
     private disconnectListeners: onWebsocketDisconnectFunc[] = [];
     private nativeMessageListeners: onWebsocketNativeMessageFunc[] = [];
     private messageListeners: { [event: string]: onMessageFunc[] } = {};
+    private requestListeners: { [event: string]: onRequestFunc } = {};
+
+    // the EmitWithAck which wait for their replies, by id.
+    private acks: { [id: string]: pendingAck } = {};
+    private ackID: number = 0;
 
     //
 
@@ -54,6 +71,7 @@ class Ws {
         });
 
         this.conn.onclose = ((evt: Event): any => {
+            this.rejectAcks();
             this.fireDisconnect();
             return null;
         });
@@ -122,11 +140,15 @@ class Ws {
         }
         let websocketMessageType = parseInt(websocketMessage.charAt(skipLen - 2));
         let theMessage = websocketMessage.substring(skipLen, websocketMessage.length);
+        return this.decodeData(websocketMessageType, theMessage);
+    }
+
+    private decodeData(websocketMessageType: number, theMessage: string): any {
         if (websocketMessageType == websocketIntMessageType) {
             return parseInt(theMessage);
         } else if (websocketMessageType == websocketBoolMessageType) {
-            return Boolean(theMessage);
-        } else if (websocketMessageType == websocketStringMessageType) {
+            return theMessage == "true";
+        } else if (websocketMessageType == websocketStringMessageType || websocketMessageType == websocketBytesMessageType) {
             return theMessage;
         } else if (websocketMessageType == websocketJSONMessageType) {
             return JSON.parse(theMessage);
@@ -167,7 +189,16 @@ class Ws {
         if (message.indexOf(websocketMessagePrefix) != -1) {
             let event = this.getWebsocketCustomEvent(message);
             if (event != "") {
+                let t = parseInt(message.charAt(websocketMessagePrefixLen + event.length + websocketMessageSeparatorLen));
+                if (t == websocketAckRequestMessageType || t == websocketAckReplyMessageType) {
+                    this.ackMessageReceived(event, t, message);
+                    return;
+                }
                 // it's a custom message
+                if (websocketEvents.hasOwnProperty(event)) {
+                    this.fireMessage(event, this.decodeMessage(event, message));
+                    return;
+                }
                 this.fireMessage(event, this.getCustomMessage(event, message));
                 return;
             }
@@ -249,6 +280,83 @@ class Ws {
         this.EmitMessage(messageStr);
     }
 
+    // EmitWithAck sends an iris-custom websocket message and returns a promise of the server's reply,
+    // it's rejected if there is no reply in "timeout" milliseconds or on disconnect,
+    // a zero or negative "timeout" waits until the reply or the disconnect.
+    EmitWithAck(event: string, data: any, timeout: number): Promise<any> {
+        let id = String(++this.ackID);
+        return new Promise<any>((resolve, reject) => {
+            let timer: any;
+            if (timeout > 0) {
+                timer = setTimeout(() => {
+                    delete this.acks[id];
+                    reject(new Error("websocket: the reply of the message is timed out"));
+                }, timeout);
+            }
+            this.acks[id] = { resolve: resolve, reject: reject, timer: timer };
+            this.EmitMessage(this.encodeAck(websocketAckRequestMessageType, event, id, data));
+        });
+    }
+
+    // OnRequest registers the handler of the messages of the server's EmitWithAck of the event,
+    // its result, or the result of its promise, is the reply.
+    // If it's missing then the On listeners are called and the reply is a null.
+    OnRequest(event: string, cb: onRequestFunc): void {
+        this.requestListeners[event] = cb;
+    }
+
+    // iris-websocket-message:$event;$type;$data -> iris-websocket-message:$event;$ackType;$id;$type;$data
+    private encodeAck(ackType: number, event: string, id: string, data: any): string {
+        let m = (data === null || typeof (data) === "undefined") ? this._msg(event, websocketJSONMessageType, "null") : this.encodeMessage(event, data);
+        let head = websocketMessagePrefixLen + event.length + websocketMessageSeparatorLen;
+        return m.substring(0, head) + String(ackType) + websocketMessageSeparator + id + websocketMessageSeparator + m.substring(head);
+    }
+
+    private ackMessageReceived(event: string, ackType: number, websocketMessage: string): void {
+        // $id;$type;$data
+        let rest = websocketMessage.substring(websocketMessagePrefixLen + event.length + 2 * websocketMessageSeparatorLen + 1);
+        let sep = rest.indexOf(websocketMessageSeparator);
+        if (sep <= 0) {
+            return;
+        }
+        let id = rest.substring(0, sep);
+        let data = this.decodeData(parseInt(rest.charAt(sep + 1)), rest.substring(sep + 3));
+
+        if (ackType == websocketAckReplyMessageType) {
+            let ack = this.acks[id];
+            if (ack) {
+                delete this.acks[id];
+                clearTimeout(ack.timer);
+                ack.resolve(data);
+            }
+            return;
+        }
+
+        let reply: any = null;
+        let cb = this.requestListeners[event];
+        if (cb) {
+            reply = cb(data);
+        } else {
+            this.fireMessage(event, rest.substring(sep + 3));
+        }
+
+        if (reply && typeof (reply.then) === "function") {
+            reply.then((r: any) => { this.EmitMessage(this.encodeAck(websocketAckReplyMessageType, event, id, r)); });
+            return;
+        }
+        this.EmitMessage(this.encodeAck(websocketAckReplyMessageType, event, id, reply));
+    }
+
+    private rejectAcks(): void {
+        for (let id in this.acks) {
+            if (this.acks.hasOwnProperty(id)) {
+                clearTimeout(this.acks[id].timer);
+                this.acks[id].reject(new Error("websocket: the connection is disconnected before the reply of the message"));
+            }
+        }
+        this.acks = {};
+    }
+
     //
 
 }
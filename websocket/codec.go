@@ -0,0 +1,167 @@
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// Codec marshals and unmarshals the data of the messages of an event, see `Server#RegisterCodec`.
+//
+// A Codec can be built on top of any serialization format, i.e the protobuf:
+//
+//	type protobufCodec struct{}
+//
+//	func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+//	    return proto.Marshal(v.(proto.Message))
+//	}
+//
+//	func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+//	    return proto.Unmarshal(data, v.(proto.Message))
+//	}
+//
+// or the msgpack, its `msgpack.Marshal` and `msgpack.Unmarshal` have the same signatures.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is the `Codec` of the "encoding/json".
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+var errCodecMissing = errors.New("websocket: the event '%s' has no codec to unmarshal its message")
+
+// RegisterCodec sets the "codec" of the messages of the "event", the empty event registers the codec
+// of all of the events which have not their own.
+//
+// The data of the `Emit` of the event is marshaled by the codec and it's sent as a binary message,
+// the data of a received message of the event is unmarshaled by the codec to the type of each `On` listener,
+// i.e the `func(*pb.ChatMessage)`, the `func([]byte)` listeners receive the marshaled data as it is.
+// The clients should use the same framing, the event's messages are "iris-websocket-message:$event;5;$marshaled_data".
+//
+// It should be called before the server accepts any connection.
+//
+// Example:
+// ws.RegisterCodec("chat", protobufCodec{})
+//
+//	c.On("chat", func(msg *pb.ChatMessage) {
+//	    c.To(websocket.Broadcast).Emit("chat", msg)
+//	})
+func (s *Server) RegisterCodec(event string, codec Codec) {
+	s.mu.Lock()
+	if s.codecs == nil {
+		s.codecs = make(map[string]Codec)
+	}
+	s.codecs[event] = codec
+	s.mu.Unlock()
+}
+
+// codec returns the codec of the "event", if any.
+func (s *Server) codec(event string) Codec {
+	s.mu.RLock()
+	codec, ok := s.codecs[event]
+	if !ok {
+		codec = s.codecs[""]
+	}
+	s.mu.RUnlock()
+	return codec
+}
+
+// serialize returns the message of the "event", marshaled by the event's codec or by the built-in text framing.
+func (s *Server) serialize(event string, data interface{}) ([]byte, error) {
+	if err := s.checkEventPayload(event, data); err != nil {
+		return nil, err
+	}
+
+	codec := s.codec(event)
+	if codec == nil {
+		message, err := websocketMessageSerialize(event, data)
+		return []byte(message), err
+	}
+
+	payload, err := codec.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	b := websocketMessageBuffer.Get()
+	b.WriteString(websocketMessagePrefix)
+	b.WriteString(event)
+	b.WriteString(websocketMessageSeparator)
+	b.WriteString(websocketCodecMessageType.String())
+	b.WriteString(websocketMessageSeparator)
+	b.Write(payload)
+	message := append([]byte(nil), b.Bytes()...)
+	websocketMessageBuffer.Put(b)
+
+	return message, nil
+}
+
+// isCodecMessage reports whether the "data" is a message of a codec, they are sent as binary messages.
+func isCodecMessage(data []byte) bool {
+	if len(data) <= websocketMessagePrefixLen || string(data[:websocketMessagePrefixLen]) != websocketMessagePrefix {
+		return false
+	}
+
+	s := data[websocketMessagePrefixLen:]
+	for i := range s {
+		if s[i] == websocketMessageSeparatorByte {
+			return len(s) > i+2 && s[i+1] == '0'+byte(websocketCodecMessageType) && s[i+2] == websocketMessageSeparatorByte
+		}
+	}
+
+	return false
+}
+
+// fireCodecMessage unmarshals the "payload" of a codec message of the "event"
+// to the type of each one of the "listeners" and calls them.
+func (c *connection) fireCodecMessage(event string, payload []byte, listeners []MessageFunc) {
+	codec := c.server.codec(event)
+
+	for i := range listeners {
+		if fn, ok := listeners[i].(func()); ok {
+			fn()
+			continue
+		}
+
+		if fnBytes, ok := listeners[i].(func([]byte)); ok {
+			fnBytes(payload)
+			continue
+		}
+
+		fn := reflect.ValueOf(listeners[i])
+		if fn.Kind() != reflect.Func || fn.Type().NumIn() != 1 {
+			continue
+		}
+
+		if codec == nil {
+			c.FireOnError(errCodecMissing.Format(event))
+			return
+		}
+
+		typ := fn.Type().In(0)
+		var ptr reflect.Value
+		if typ.Kind() == reflect.Ptr {
+			ptr = reflect.New(typ.Elem())
+		} else {
+			ptr = reflect.New(typ)
+		}
+
+		if err := codec.Unmarshal(payload, ptr.Interface()); err != nil {
+			c.FireOnError(err)
+			continue
+		}
+
+		if typ.Kind() == reflect.Ptr {
+			fn.Call([]reflect.Value{ptr})
+		} else {
+			fn.Call([]reflect.Value{ptr.Elem()})
+		}
+	}
+}
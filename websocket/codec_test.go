@@ -0,0 +1,232 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kataras/iris"
+)
+
+// testCodec is a json codec which prefixes its data, so it can't be confused with the built-in framing.
+type testCodec struct {
+	marshaled, unmarshaled uint32
+}
+
+var testCodecPrefix = []byte("codec:")
+
+func (c *testCodec) Marshal(v interface{}) ([]byte, error) {
+	atomic.AddUint32(&c.marshaled, 1)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), testCodecPrefix...), b...), nil
+}
+
+func (c *testCodec) Unmarshal(data []byte, v interface{}) error {
+	atomic.AddUint32(&c.unmarshaled, 1)
+	if !bytes.HasPrefix(data, testCodecPrefix) {
+		return errors.New("test codec: missing prefix")
+	}
+	return json.Unmarshal(data[len(testCodecPrefix):], v)
+}
+
+type testCodecMessage struct {
+	From string `json:"from"`
+	Text string `json:"text"`
+}
+
+func testCodecFrame(event string, payload []byte) []byte {
+	frame := websocketMessagePrefix + event + websocketMessageSeparator +
+		websocketCodecMessageType.String() + websocketMessageSeparator
+	return append([]byte(frame), payload...)
+}
+
+func TestIsCodecMessage(t *testing.T) {
+	tests := map[string]bool{
+		string(testCodecFrame("chat", []byte("data"))): true,
+		string(testCodecFrame("chat", nil)):            true,
+		websocketMessagePrefix + "chat;0;text":         false,
+		websocketMessagePrefix + "chat;5":              false,
+		websocketMessagePrefix + "chat":                false,
+		websocketMessagePrefix:                         false,
+		"chat;5;data":                                  false,
+		"":                                             false,
+	}
+
+	for data, expected := range tests {
+		if got := isCodecMessage([]byte(data)); got != expected {
+			t.Fatalf("expected isCodecMessage of '%s' to be %v but got %v", data, expected, got)
+		}
+	}
+}
+
+func TestCodec(t *testing.T) {
+	codec := new(testCodec)
+
+	s := New(Config{})
+	s.RegisterCodec("chat", codec)
+
+	var (
+		pointers = make(chan *testCodecMessage, 1)
+		values   = make(chan testCodecMessage, 1)
+		raw      = make(chan []byte, 1)
+		calls    = make(chan struct{}, 1)
+		errs     = make(chan error, 1)
+	)
+
+	s.OnConnection(func(c Connection) {
+		c.OnError(func(err error) {
+			errs <- err
+		})
+
+		c.On("chat", func(msg *testCodecMessage) {
+			pointers <- msg
+			c.Emit("chat", testCodecMessage{From: "server", Text: "re: " + msg.Text})
+		})
+		c.On("chat", func(msg testCodecMessage) {
+			values <- msg
+		})
+		c.On("chat", func(b []byte) {
+			raw <- b
+		})
+		c.On("chat", func() {
+			calls <- struct{}{}
+		})
+
+		c.On("nocodec", func(msg testCodecMessage) {
+			t.Errorf("the listener of an event without codec should not be called but got: %#v", msg)
+		})
+	})
+
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	app.Get("/ws", s.Handler())
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	expected := testCodecMessage{From: "kataras", Text: "hello"}
+	payload, err := codec.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = conn.WriteMessage(websocket.BinaryMessage, testCodecFrame("chat", payload)); err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	select {
+	case msg := <-pointers:
+		if msg == nil || *msg != expected {
+			t.Fatalf("expected the pointer listener to receive %#v but got %#v", expected, msg)
+		}
+	case <-timeout:
+		t.Fatal("timeout waiting for the pointer listener")
+	}
+
+	select {
+	case msg := <-values:
+		if msg != expected {
+			t.Fatalf("expected the value listener to receive %#v but got %#v", expected, msg)
+		}
+	case <-timeout:
+		t.Fatal("timeout waiting for the value listener")
+	}
+
+	select {
+	case b := <-raw:
+		if !bytes.Equal(b, payload) {
+			t.Fatalf("expected the bytes listener to receive the marshaled data '%s' but got '%s'", payload, b)
+		}
+	case <-timeout:
+		t.Fatal("timeout waiting for the bytes listener")
+	}
+
+	select {
+	case <-calls:
+	case <-timeout:
+		t.Fatal("timeout waiting for the no-argument listener")
+	}
+
+	// the reply of the server is a binary message with the same framing.
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	typ, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ != websocket.BinaryMessage {
+		t.Fatalf("expected the message of a codec to be binary but got type: %d", typ)
+	}
+
+	reply, err := codec.Marshal(testCodecMessage{From: "server", Text: "re: hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectedFrame := testCodecFrame("chat", reply); !bytes.Equal(data, expectedFrame) {
+		t.Fatalf("expected the reply to be '%s' but got '%s'", expectedFrame, data)
+	}
+
+	// only the typed listeners unmarshal, the test's own Marshal calls are counted too.
+	if got := atomic.LoadUint32(&codec.unmarshaled); got != 2 {
+		t.Fatalf("expected the codec to unmarshal the message 2 times but got %d", got)
+	}
+	if got := atomic.LoadUint32(&codec.marshaled); got != 3 {
+		t.Fatalf("expected the codec to marshal 3 times but got %d", got)
+	}
+
+	// an event without a codec, and no default one, can't be unmarshaled to a typed listener.
+	if err = conn.WriteMessage(websocket.BinaryMessage, testCodecFrame("nocodec", payload)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if expectedErr := errCodecMissing.Format("nocodec"); err == nil || err.Error() != expectedErr.Error() {
+			t.Fatalf("expected the error '%v' but got '%v'", expectedErr, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the missing codec error")
+	}
+}
+
+func TestCodecDefault(t *testing.T) {
+	s := New(Config{})
+	codec := new(testCodec)
+	s.RegisterCodec("", codec)
+
+	if got := s.codec("any"); got != codec {
+		t.Fatalf("expected the default codec for an event without its own but got: %v", got)
+	}
+
+	s.RegisterCodec("chat", JSONCodec)
+	if got := s.codec("chat"); got != JSONCodec {
+		t.Fatalf("expected the codec of the event to override the default one but got: %v", got)
+	}
+
+	data, err := s.serialize("chat", testCodecMessage{From: "kataras", Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := testCodecFrame("chat", []byte(`{"from":"kataras","text":"hello"}`)); !bytes.Equal(data, expected) {
+		t.Fatalf("expected the message to be '%s' but got '%s'", expected, data)
+	}
+}
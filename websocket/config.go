@@ -16,12 +16,16 @@ const (
 	DefaultWebsocketPongTimeout = 60 * time.Second
 	// DefaultWebsocketPingPeriod (DefaultPongTimeout * 9) / 10
 	DefaultWebsocketPingPeriod = (DefaultWebsocketPongTimeout * 9) / 10
+	// DefaultWebsocketMaxMissedPongs 2
+	DefaultWebsocketMaxMissedPongs = 2
 	// DefaultWebsocketMaxMessageSize 1024
 	DefaultWebsocketMaxMessageSize = 1024
 	// DefaultWebsocketReadBufferSize 4096
 	DefaultWebsocketReadBufferSize = 4096
 	// DefaultWebsocketWriterBufferSize 4096
 	DefaultWebsocketWriterBufferSize = 4096
+	// DefaultWebsocketExchangeQueueSize 1024
+	DefaultWebsocketExchangeQueueSize = 1024
 	// DefaultClientSourcePath "/iris-ws.js"
 	DefaultClientSourcePath = "/iris-ws.js"
 )
@@ -47,6 +51,12 @@ type Config struct {
 	// CheckOrigin a function that is called right before the handshake,
 	// if returns false then that client is not allowed to connect with the websocket server.
 	CheckOrigin func(r *http.Request) bool
+	// Authenticate a function that is called right before the handshake, like the `CheckOrigin`,
+	// it returns the identity of the client, i.e its user's id, which is bound to the connection, see `Connection#Identity`.
+	// If it returns an error then the upgrade request is rejected with the 401 Unauthorized,
+	// or with the status code of the error if it's an `AuthError`, i.e the `ErrForbidden`.
+	// If empty then all of the clients are allowed and they have no identity.
+	Authenticate func(ctx context.Context) (identity string, err error)
 	// HandshakeTimeout specifies the duration for the handshake to complete.
 	HandshakeTimeout time.Duration
 	// WriteTimeout time allowed to write a message to the connection.
@@ -57,12 +67,19 @@ type Config struct {
 	// 0 means no timeout.
 	// Default value is 0
 	ReadTimeout time.Duration
-	// PongTimeout allowed to read the next pong message from the connection.
+	// PongTimeout allowed to read the pong message of a ping from the connection,
+	// otherwise the ping is missed, see `MaxMissedPongs`.
 	// Default value is 60 * time.Second
 	PongTimeout time.Duration
 	// PingPeriod send ping messages to the connection within this period. Must be less than PongTimeout.
-	// Default value is 60 *time.Second
+	// Default value is 54 * time.Second
 	PingPeriod time.Duration
+	// MaxMissedPongs is the number of the consecutive pings which the connection can miss,
+	// it is disconnected when they are missed and its `OnTimeout` callbacks are fired,
+	// so the dead connections, i.e of the clients which lost their network, are not kept around.
+	// A negative value disables it.
+	// Default value is 2
+	MaxMissedPongs int
 	// MaxMessageSize max message size allowed from connection.
 	// Default value is 1024
 	MaxMessageSize int64
@@ -89,6 +106,33 @@ type Config struct {
 	// subprotocol by selecting the first match in this list with a protocol
 	// requested by the client.
 	Subprotocols []string
+
+	// InstanceID is the id of this instance of the application, it is the origin of the messages
+	// which are published to the `Exchange`, see `Server#UseExchange`.
+	// It should be unique per instance.
+	//
+	// Defaults to 32 random characters.
+	InstanceID string
+	// ExchangeQueueSize is the maximum number of the messages which are waiting to be published
+	// to the `Exchange`, they are published by a goroutine so a slow pub/sub system can not block the senders.
+	// If the queue is full then the message is sent to the connections of this instance only
+	// and the emit returns the `ErrExchangeQueueFull`.
+	//
+	// Defaults to 1024.
+	ExchangeQueueSize int
+	// ExchangeError is called when the `Exchange` failed to publish a message, it's optional.
+	ExchangeError func(err error)
+
+	// WriteQueueSize is the maximum number of the messages which are waiting to be sent to each client,
+	// so a slow client can not block the senders, i.e the broadcasts, or exhaust the server's memory.
+	// If it's set then the messages are written by a goroutine per connection.
+	//
+	// Defaults to 0, the messages are written by their senders.
+	WriteQueueSize int
+	// WriteQueuePolicy is the behavior of a full write queue, see `WriteQueueSize`.
+	//
+	// Defaults to the `WriteQueueDropOldest`.
+	WriteQueuePolicy WriteQueuePolicy
 }
 
 // Validate validates the configuration
@@ -102,10 +146,14 @@ func (c Config) Validate() Config {
 		c.ReadTimeout = DefaultWebsocketReadTimeout
 	}
 
-	if c.PongTimeout < 0 {
+	if c.PongTimeout <= 0 {
 		c.PongTimeout = DefaultWebsocketPongTimeout
 	}
 
+	if c.MaxMissedPongs == 0 {
+		c.MaxMissedPongs = DefaultWebsocketMaxMissedPongs
+	}
+
 	if c.PingPeriod <= 0 {
 		c.PingPeriod = DefaultWebsocketPingPeriod
 	}
@@ -139,5 +187,13 @@ func (c Config) Validate() Config {
 		c.IDGenerator = DefaultIDGenerator
 	}
 
+	if c.InstanceID == "" {
+		c.InstanceID = randomString(32)
+	}
+
+	if c.ExchangeQueueSize <= 0 {
+		c.ExchangeQueueSize = DefaultWebsocketExchangeQueueSize
+	}
+
 	return c
 }
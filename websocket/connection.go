@@ -6,6 +6,7 @@ import (
 	"net"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -129,7 +130,8 @@ type (
 	// NativeMessageFunc is the callback for native websocket messages, receives one []byte parameter which is the raw client's message
 	NativeMessageFunc func([]byte)
 	// MessageFunc is the second argument to the Emitter's Emit functions.
-	// A callback which should receives one parameter of type string, int, bool or any valid JSON/Go struct
+	// A callback which should receives one parameter of type string, int, bool or any valid JSON/Go struct,
+	// or any type of the event's `Codec`, see `Server#RegisterCodec`.
 	MessageFunc interface{}
 	// PingFunc is the callback which fires each ping
 	PingFunc func()
@@ -144,6 +146,8 @@ type (
 
 		// ID returns the connection's identifier
 		ID() string
+		// Identity returns the identity of the connection's client which is returned by the `Config#Authenticate`.
+		Identity() string
 
 		// Server returns the websocket server instance
 		// which this connection is listening to.
@@ -169,6 +173,16 @@ type (
 		OnPing(PingFunc)
 		// OnPong  registers a callback which fires on pong message received
 		OnPong(PongFunc)
+		// EmitWithAck sends a message to the client and waits for its reply, up to the "timeout",
+		// a zero or negative "timeout" waits until the reply or the disconnection,
+		// it should not be called by the listeners of the connection, they are called by the reader of the reply.
+		EmitWithAck(event string, data interface{}, timeout time.Duration) (reply interface{}, err error)
+		// OnRequest registers the handler of the messages of the client's `EmitWithAck` of the event,
+		// its result is the reply.
+		OnRequest(event string, cb RequestFunc)
+		// OnTimeout registers a callback which fires when this connection is disconnected
+		// because it did not answer to the pings, see `Config#MaxMissedPongs`.
+		OnTimeout(TimeoutFunc)
 		// FireOnError can be used to send a custom error message to the connection
 		//
 		// It does nothing more than firing the OnError listeners. It doesn't send anything to the client.
@@ -213,19 +227,24 @@ type (
 		GetValueString(key string) string
 		// GetValueInt gets a value as integer by its key from the connection's mem store.
 		GetValueInt(key string) int
+		// WriteQueueLen returns the number of the messages which are waiting to be sent to the client,
+		// it's always 0 if the `Config#WriteQueueSize` is not set.
+		WriteQueueLen() int
 	}
 
 	connection struct {
 		err                      error
 		underline                UnderlineConnection
 		id                       string
+		identity                 string // see `Config#Authenticate`.
 		messageType              int
-		disconnected             bool
+		disconnected             uint32 // 1 when it's disconnected, atomic.
 		onDisconnectListeners    []DisconnectFunc
 		onRoomLeaveListeners     []LeaveRoomFunc
 		onErrorListeners         []ErrorFunc
 		onPingListeners          []PingFunc
 		onPongListeners          []PongFunc
+		onTimeoutListeners       []TimeoutFunc
 		onNativeMessageListeners []NativeMessageFunc
 		onEventListeners         map[string][]MessageFunc
 		started                  bool
@@ -244,6 +263,24 @@ type (
 		// same exists for reader look here: https://godoc.org/github.com/gorilla/websocket#hdr-Control_Messages
 		// but we only use one reader in one goroutine, so we are safe.
 		// readerMu sync.Mutex
+
+		// the outbound messages, if the `Config#WriteQueueSize` is set, see `startWriter`.
+		queue chan []byte
+		// closed on disconnect, it stops the writer.
+		done chan struct{}
+		// 1 when the connection is disconnected by the `WriteQueueCloseConnection` policy.
+		queueClosed uint32
+
+		// the requests of the `EmitWithAck` which wait for their replies, by id, and the handlers of the client's ones.
+		ackID              uint64
+		acks               map[string]chan interface{}
+		onRequestListeners map[string]RequestFunc
+		acksMu             sync.Mutex
+
+		// the heartbeat's state, see `awaitPong`.
+		lastPong    int64 // unix nanoseconds.
+		missedPongs uint32
+		timedOut    uint32
 	}
 )
 
@@ -256,10 +293,11 @@ var _ Connection = &connection{}
 // Use the `Connection#Disconnect` instead.
 const CloseMessage = websocket.CloseMessage
 
-func newConnection(ctx context.Context, s *Server, underlineConn UnderlineConnection, id string) *connection {
+func newConnection(ctx context.Context, s *Server, underlineConn UnderlineConnection, id, identity string) *connection {
 	c := &connection{
 		underline:                underlineConn,
 		id:                       id,
+		identity:                 identity,
 		messageType:              websocket.TextMessage,
 		onDisconnectListeners:    make([]DisconnectFunc, 0),
 		onRoomLeaveListeners:     make([]LeaveRoomFunc, 0),
@@ -276,6 +314,11 @@ func newConnection(ctx context.Context, s *Server, underlineConn UnderlineConnec
 		c.messageType = websocket.BinaryMessage
 	}
 
+	if s.config.WriteQueueSize > 0 {
+		c.queue = make(chan []byte, s.config.WriteQueueSize)
+		c.done = make(chan struct{})
+	}
+
 	c.self = newEmitter(c, c.id)
 	c.broadcast = newEmitter(c, Broadcast)
 	c.all = newEmitter(c, All)
@@ -310,9 +353,25 @@ func (c *connection) Write(websocketMessageType int, data []byte) error {
 }
 
 // writeDefault is the same as write but the message type is the configured by c.messageType
-// if BinaryMessages is enabled then it's raw []byte as you expected to work with protobufs
+// if BinaryMessages is enabled then it's raw []byte as you expected to work with protobufs,
+// the messages of a `Codec` are always binary.
+// The message is queued if the `Config#WriteQueueSize` is set.
 func (c *connection) writeDefault(data []byte) {
-	c.Write(c.messageType, data)
+	if c.queue != nil {
+		c.enqueue(data)
+		return
+	}
+
+	c.Write(c.messageTypeOf(data), data)
+}
+
+// messageTypeOf returns the websocket message type of the "data", see `writeDefault`.
+func (c *connection) messageTypeOf(data []byte) int {
+	if isCodecMessage(data) {
+		return websocket.BinaryMessage
+	}
+
+	return c.messageType
 }
 
 const (
@@ -342,18 +401,21 @@ func (c *connection) startPinger() {
 		for {
 			// using sleep avoids the ticker error that causes a memory leak
 			time.Sleep(c.server.config.PingPeriod)
-			if c.disconnected {
+			if atomic.LoadUint32(&c.disconnected) == 1 {
 				// verifies if already disconected
 				break
 			}
 			//fire all OnPing methods
 			c.fireOnPing()
 			// try to ping the client, if failed then it disconnects
+			sent := time.Now()
 			err := c.Write(websocket.PingMessage, []byte{})
 			if err != nil {
 				// must stop to exit the loop and finish the go routine
 				break
 			}
+			// disconnect it if it does not answer, see `Config#MaxMissedPongs`.
+			c.awaitPong(sent)
 		}
 	}()
 }
@@ -381,6 +443,7 @@ func (c *connection) startReader() {
 		if hasReadTimeout {
 			conn.SetReadDeadline(time.Now().Add(c.server.config.ReadTimeout))
 		}
+		c.pongReceived()
 		//fire all OnPong methods
 		go c.fireOnPong()
 
@@ -411,6 +474,33 @@ func (c *connection) startReader() {
 
 }
 
+// fireMessage calls the event "listeners" with the deserialized "customMessage".
+func (c *connection) fireMessage(listeners []MessageFunc, customMessage interface{}) {
+	for i := range listeners {
+		if fn, ok := listeners[i].(func()); ok { // its a simple func(){} callback
+			fn()
+		} else if fnString, ok := listeners[i].(func(string)); ok {
+
+			if msgString, is := customMessage.(string); is {
+				fnString(msgString)
+			} else if msgInt, is := customMessage.(int); is {
+				// here if server side waiting for string but client side sent an int, just convert this int to a string
+				fnString(strconv.Itoa(msgInt))
+			}
+
+		} else if fnInt, ok := listeners[i].(func(int)); ok {
+			fnInt(customMessage.(int))
+		} else if fnBool, ok := listeners[i].(func(bool)); ok {
+			fnBool(customMessage.(bool))
+		} else if fnBytes, ok := listeners[i].(func([]byte)); ok {
+			fnBytes(customMessage.([]byte))
+		} else {
+			listeners[i].(func(interface{}))(customMessage)
+		}
+
+	}
+}
+
 // messageReceived checks the incoming message and fire the nativeMessage listeners or the event listeners (ws custom message)
 func (c *connection) messageReceived(data []byte) {
 
@@ -418,6 +508,11 @@ func (c *connection) messageReceived(data []byte) {
 		customData := string(data)
 		//it's a custom ws message
 		receivedEvt := getWebsocketCustomEvent(customData)
+		if isAckMessage(receivedEvt, customData) {
+			c.ackMessageReceived(receivedEvt, customData)
+			return
+		}
+
 		listeners := c.onEventListeners[receivedEvt]
 		if listeners == nil { // if not listeners for this event exit from here
 			return
@@ -427,29 +522,12 @@ func (c *connection) messageReceived(data []byte) {
 			return
 		}
 
-		for i := range listeners {
-			if fn, ok := listeners[i].(func()); ok { // its a simple func(){} callback
-				fn()
-			} else if fnString, ok := listeners[i].(func(string)); ok {
-
-				if msgString, is := customMessage.(string); is {
-					fnString(msgString)
-				} else if msgInt, is := customMessage.(int); is {
-					// here if server side waiting for string but client side sent an int, just convert this int to a string
-					fnString(strconv.Itoa(msgInt))
-				}
-
-			} else if fnInt, ok := listeners[i].(func(int)); ok {
-				fnInt(customMessage.(int))
-			} else if fnBool, ok := listeners[i].(func(bool)); ok {
-				fnBool(customMessage.(bool))
-			} else if fnBytes, ok := listeners[i].(func([]byte)); ok {
-				fnBytes(customMessage.([]byte))
-			} else {
-				listeners[i].(func(interface{}))(customMessage)
-			}
-
+		if payload, ok := customMessage.(codecMessage); ok {
+			c.fireCodecMessage(receivedEvt, payload, listeners)
+			return
 		}
+
+		c.fireMessage(listeners, customMessage)
 	} else {
 		// it's native websocket message
 		for i := range c.onNativeMessageListeners {
@@ -576,6 +654,11 @@ func (c *connection) Wait() {
 	// start the ping
 	c.startPinger()
 
+	// start the writer of the queued messages, if any
+	if c.queue != nil {
+		c.startWriter()
+	}
+
 	// start the messages reader
 	c.startReader()
 }
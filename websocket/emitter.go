@@ -29,15 +29,33 @@ func newEmitter(c *connection, to string) *emitter {
 }
 
 func (e *emitter) EmitMessage(nativeMessage []byte) error {
-	e.conn.server.emitMessage(e.conn.id, e.to, nativeMessage)
-	return nil
+	return e.conn.server.emitMessage(e.conn.id, e.to, nativeMessage)
 }
 
 func (e *emitter) Emit(event string, data interface{}) error {
-	message, err := websocketMessageSerialize(event, data)
+	message, err := e.conn.server.serialize(event, data)
 	if err != nil {
 		return err
 	}
-	e.EmitMessage([]byte(message))
-	return nil
+	return e.EmitMessage(message)
+}
+
+// serverEmitter is the emitter of the `Server#To`, its messages have no sender connection.
+type serverEmitter struct {
+	server *Server
+	to     string
+}
+
+var _ Emitter = &serverEmitter{}
+
+func (e *serverEmitter) EmitMessage(nativeMessage []byte) error {
+	return e.server.emitMessage("", e.to, nativeMessage)
+}
+
+func (e *serverEmitter) Emit(event string, data interface{}) error {
+	message, err := e.server.serialize(event, data)
+	if err != nil {
+		return err
+	}
+	return e.EmitMessage(message)
 }
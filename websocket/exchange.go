@@ -0,0 +1,136 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/kataras/iris/core/errors"
+)
+
+// ExchangeMessageVersion is the version of the envelope of the messages
+// which are published to the `Exchange`, the messages of a newer version are ignored,
+// so the instances of a rolling upgrade do not receive messages which they can not read.
+const ExchangeMessageVersion = 1
+
+// ErrExchangeQueueFull is returned by the emits when the message could not be queued to be published
+// to the `Exchange`, it's sent to the connections of this instance only, see `Config#ExchangeQueueSize`.
+var ErrExchangeQueueFull = errors.New("websocket: the exchange queue is full, the message is not published")
+
+// Exchange is the pub/sub bridge of the websocket servers of the horizontally scaled instances of an application,
+// the messages which are sent to a room, to a connection or to all of the connections of a server
+// are published to the exchange and each one of the other instances sends them to its own connections.
+//
+// An Exchange can be built on top of any pub/sub system, i.e the redis one, see the "websocket/exchange/redis",
+// or the NATS:
+//
+//	type natsExchange struct{ conn *nats.Conn }
+//
+//	func (e *natsExchange) Publish(message []byte) error {
+//	    return e.conn.Publish("iris-websocket", message)
+//	}
+//
+//	func (e *natsExchange) Subscribe(onMessage func(message []byte)) error {
+//	    _, err := e.conn.Subscribe("iris-websocket", func(m *nats.Msg) { onMessage(m.Data) })
+//	    return err
+//	}
+//
+// See `Server#UseExchange` too.
+type Exchange interface {
+	// Publish sends the "message" to all of the subscribers, including the publisher itself.
+	Publish(message []byte) error
+	// Subscribe registers the "onMessage" which should be called for each one of the published messages,
+	// it's called once, by the `Server#UseExchange`.
+	Subscribe(onMessage func(message []byte)) error
+}
+
+// exchangeMessage is the envelope of a message of the `Exchange`.
+type exchangeMessage struct {
+	Version int `json:"v"`
+	// Origin is the `Config#InstanceID` of the publisher,
+	// its own messages are already sent to its connections.
+	Origin string `json:"o"`
+	From   string `json:"f,omitempty"`
+	To     string `json:"t,omitempty"`
+	Data   []byte `json:"d"`
+}
+
+// UseExchange sets the pub/sub bridge of the server, so the messages which are sent by the servers
+// of the other instances of the application reach the connections of this one and the opposite.
+// The rooms and the connections are still local to each instance but an emit to a room
+// reaches all of its connections, wherever they are connected to.
+//
+// The messages are published by a goroutine, through a queue of the `Config#ExchangeQueueSize`,
+// the failures of the exchange are reported to the `Config#ExchangeError`.
+//
+// It should be called once, before the server accepts any connection.
+//
+// Example:
+// ws := websocket.New(websocket.Config{})
+// exchange, err := redis.New("chat", service.Config{Addr: "127.0.0.1:6379"})
+// err = ws.UseExchange(exchange)
+func (s *Server) UseExchange(exchange Exchange) error {
+	if err := exchange.Subscribe(s.onExchangeMessage); err != nil {
+		return err
+	}
+
+	queue := make(chan []byte, s.config.ExchangeQueueSize)
+	go s.startPublisher(exchange, queue)
+
+	s.mu.Lock()
+	s.exchange = exchange
+	s.publishQueue = queue
+	s.mu.Unlock()
+	return nil
+}
+
+// startPublisher publishes the queued messages to the exchange.
+func (s *Server) startPublisher(exchange Exchange, queue <-chan []byte) {
+	for message := range queue {
+		if err := exchange.Publish(message); err != nil && s.config.ExchangeError != nil {
+			s.config.ExchangeError(err)
+		}
+	}
+}
+
+// publish queues a message of this server to be sent to the other instances, if an exchange is in use.
+func (s *Server) publish(from, to string, data []byte) error {
+	s.mu.RLock()
+	queue := s.publishQueue
+	s.mu.RUnlock()
+
+	if queue == nil {
+		return nil
+	}
+
+	message, err := json.Marshal(exchangeMessage{
+		Version: ExchangeMessageVersion,
+		Origin:  s.config.InstanceID,
+		From:    from,
+		To:      to,
+		Data:    data,
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case queue <- message:
+		return nil
+	default:
+		return ErrExchangeQueueFull
+	}
+}
+
+// onExchangeMessage sends a message of the exchange to the connections of this server,
+// the malformed, the newer and the own messages are ignored.
+func (s *Server) onExchangeMessage(message []byte) {
+	var m exchangeMessage
+	if err := json.Unmarshal(message, &m); err != nil {
+		return
+	}
+
+	if m.Version < 1 || m.Version > ExchangeMessageVersion || m.Origin == s.config.InstanceID {
+		return
+	}
+
+	s.emitLocal(m.From, m.To, m.Data)
+}
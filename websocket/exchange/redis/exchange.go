@@ -0,0 +1,87 @@
+// Package redis provides the redis pub/sub `websocket.Exchange`, so the messages of the websocket servers
+// of the horizontally scaled instances of an application reach the connections of all of them.
+package redis
+
+import (
+	"sync"
+
+	"github.com/kataras/iris/core/errors"
+	"github.com/kataras/iris/sessions/sessiondb/redis/service"
+	"github.com/kataras/iris/websocket"
+)
+
+// DefaultChannel is the redis channel of the messages when the channel of the `New` is empty.
+const DefaultChannel = "iris-websocket"
+
+// ErrSubscribed is returned by the `Exchange#Subscribe` when it's called more than once.
+var ErrSubscribed = errors.New("websocket: redis: the exchange is subscribed already")
+
+// Exchange is the redis pub/sub bridge of the websocket servers.
+type Exchange struct {
+	redis   *service.Service
+	channel string
+
+	mu          sync.Mutex
+	unsubscribe func()
+}
+
+var _ websocket.Exchange = (*Exchange)(nil)
+
+// New connects to the redis and returns a new exchange of the "channel",
+// the instances of an application should use the same channel.
+//
+// Example:
+// exchange, err := redis.New("chat", service.Config{Addr: "127.0.0.1:6379"})
+// err = ws.UseExchange(exchange)
+func New(channel string, cfg ...service.Config) (*Exchange, error) {
+	s := service.New(cfg...)
+	s.Connect()
+	if _, err := s.PingPong(); err != nil {
+		return nil, err
+	}
+
+	return NewFromService(s, channel), nil
+}
+
+// NewFromService returns a new exchange of the "channel" which uses a connected redis service,
+// i.e the one of the sessions' database.
+func NewFromService(s *service.Service, channel string) *Exchange {
+	if channel == "" {
+		channel = DefaultChannel
+	}
+
+	return &Exchange{redis: s, channel: channel}
+}
+
+// Publish sends the "message" to the channel.
+func (e *Exchange) Publish(message []byte) error {
+	return e.redis.Publish(e.channel, message)
+}
+
+// Subscribe calls the "onMessage" for each one of the messages of the channel until the `Close`.
+func (e *Exchange) Subscribe(onMessage func(message []byte)) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.unsubscribe != nil {
+		return ErrSubscribed
+	}
+
+	unsubscribe, err := e.redis.Subscribe(e.channel, onMessage)
+	if err != nil {
+		return err
+	}
+
+	e.unsubscribe = unsubscribe
+	return nil
+}
+
+// Close stops the subscription, the redis service is not closed.
+func (e *Exchange) Close() error {
+	e.mu.Lock()
+	if e.unsubscribe != nil {
+		e.unsubscribe()
+	}
+	e.mu.Unlock()
+	return nil
+}
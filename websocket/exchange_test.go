@@ -0,0 +1,166 @@
+package websocket
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// testExchange is an in-memory exchange, its publishes wait for the "block", if any.
+type testExchange struct {
+	mu          sync.Mutex
+	subscribers []func([]byte)
+	published   int
+	err         error
+	block       chan struct{}
+}
+
+func (e *testExchange) Publish(message []byte) error {
+	e.mu.Lock()
+	block := e.block
+	e.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+
+	e.mu.Lock()
+	e.published++
+	err := e.err
+	subscribers := append([]func([]byte){}, e.subscribers...)
+	e.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	for _, onMessage := range subscribers {
+		onMessage(message)
+	}
+	return nil
+}
+
+func (e *testExchange) Subscribe(onMessage func([]byte)) error {
+	e.mu.Lock()
+	e.subscribers = append(e.subscribers, onMessage)
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *testExchange) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.published
+}
+
+func TestExchange(t *testing.T) {
+	exchange := new(testExchange)
+
+	s1, s2 := New(Config{}), New(Config{})
+	for _, s := range []*Server{s1, s2} {
+		if err := s.UseExchange(exchange); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c1, underline1 := newTestConnection(s1)
+	c2, underline2 := newTestConnection(s2)
+	defer c1.Disconnect()
+	defer c2.Disconnect()
+
+	if err := s1.To(All).EmitMessage([]byte("all")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !eventually(func() bool { return len(underline2.written()) == 1 }) || underline2.written()[0] != "all" {
+		t.Fatalf("expected the message to reach the connection of the other instance but got: %v", underline2.written())
+	}
+
+	if got := underline1.written(); len(got) != 1 || got[0] != "all" {
+		t.Fatalf("expected the message to reach the local connection once but got: %v", got)
+	}
+
+	// to a connection of the other instance.
+	if err := s1.To(c2.ID()).EmitMessage([]byte("remote")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !eventually(func() bool { return len(underline2.written()) == 2 }) || underline2.written()[1] != "remote" {
+		t.Fatalf("expected the message to reach the remote connection but got: %v", underline2.written())
+	}
+
+	// to a local connection, it's not published.
+	published := exchange.count()
+	if err := c1.To(c1.ID()).EmitMessage([]byte("local")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := underline1.written(); len(got) != 2 || got[1] != "local" {
+		t.Fatalf("expected the message to reach the local connection but got: %v", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if n := exchange.count(); n != published {
+		t.Fatalf("expected the message to a local connection to not be published but got %d publishes", n-published)
+	}
+}
+
+func TestExchangeQueue(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		failures []error
+	)
+
+	exchange := &testExchange{block: make(chan struct{}), err: errTestClosed}
+	s := New(Config{
+		ExchangeQueueSize: 1,
+		ExchangeError: func(err error) {
+			mu.Lock()
+			failures = append(failures, err)
+			mu.Unlock()
+		},
+	})
+
+	if err := s.UseExchange(exchange); err != nil {
+		t.Fatal(err)
+	}
+
+	c, underline := newTestConnection(s)
+	defer c.Disconnect()
+
+	sent := make(chan error, 3)
+	go func() {
+		for _, msg := range []string{"1", "2", "3"} {
+			sent <- s.To(All).EmitMessage([]byte(msg))
+			// the publisher takes the first one.
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	// the emits do not wait for the blocked exchange.
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-sent:
+			if i < 2 && err != nil {
+				t.Fatalf("expected the message %d to be queued but got: %v", i+1, err)
+			}
+			if i == 2 && !isError(err, ErrExchangeQueueFull) {
+				t.Fatalf("expected the full queue error but got: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected the emit to not wait for the exchange")
+		}
+	}
+
+	if got := underline.written(); len(got) != 3 {
+		t.Fatalf("expected all of the messages to reach the local connection but got: %v", got)
+	}
+
+	close(exchange.block)
+	if !eventually(func() bool { return exchange.count() == 2 }) {
+		t.Fatalf("expected the queued messages to be published but got: %d", exchange.count())
+	}
+
+	if !eventually(func() bool { mu.Lock(); defer mu.Unlock(); return len(failures) == 2 }) {
+		t.Fatalf("expected the failures of the exchange to be reported")
+	}
+}
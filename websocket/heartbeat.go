@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TimeoutFunc is the callback which fires when a connection is disconnected
+// because it did not answer to the pings, see `Config#MaxMissedPongs`.
+type TimeoutFunc func()
+
+// OnTimeout registers a callback which fires when the connection did not answer
+// to the last `Config#MaxMissedPongs` pings in time, right before it is disconnected.
+func (c *connection) OnTimeout(cb TimeoutFunc) {
+	c.onTimeoutListeners = append(c.onTimeoutListeners, cb)
+}
+
+// pongReceived resets the missed pongs of the connection.
+func (c *connection) pongReceived() {
+	atomic.StoreInt64(&c.lastPong, time.Now().UnixNano())
+	atomic.StoreUint32(&c.missedPongs, 0)
+}
+
+// awaitPong checks, after the `Config#PongTimeout`, if the ping which is sent at the "sent" time is answered,
+// the connection is disconnected if it's the `Config#MaxMissedPongs` consecutive one which is not.
+func (c *connection) awaitPong(sent time.Time) {
+	maxMissed := c.server.config.MaxMissedPongs
+	if maxMissed < 0 {
+		return
+	}
+
+	time.AfterFunc(c.server.config.PongTimeout, func() {
+		if atomic.LoadInt64(&c.lastPong) >= sent.UnixNano() {
+			return
+		}
+
+		if atomic.AddUint32(&c.missedPongs, 1) < uint32(maxMissed) {
+			return
+		}
+
+		if !atomic.CompareAndSwapUint32(&c.timedOut, 0, 1) {
+			return
+		}
+
+		for i := range c.onTimeoutListeners {
+			c.onTimeoutListeners[i]()
+		}
+
+		c.Disconnect()
+	})
+}
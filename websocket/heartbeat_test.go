@@ -0,0 +1,88 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAwaitPong(t *testing.T) {
+	s := New(Config{PongTimeout: 10 * time.Millisecond, MaxMissedPongs: 2})
+	c, underline := newTestConnection(s)
+
+	var timeouts uint32
+	c.OnTimeout(func() { atomic.AddUint32(&timeouts, 1) })
+
+	// an answered ping resets the missed ones.
+	c.awaitPong(time.Now())
+	time.Sleep(30 * time.Millisecond)
+	c.awaitPong(time.Now())
+	time.Sleep(time.Millisecond)
+	c.pongReceived()
+	c.awaitPong(time.Now())
+	time.Sleep(30 * time.Millisecond)
+
+	if !s.IsConnected(c.ID()) || atomic.LoadUint32(&timeouts) != 0 {
+		t.Fatalf("expected the connection to stay connected after a single missed pong")
+	}
+
+	// the second consecutive missed pong disconnects it.
+	c.awaitPong(time.Now())
+	if !eventually(func() bool { return !s.IsConnected(c.ID()) }) {
+		t.Fatalf("expected the connection to be disconnected after %d missed pongs", s.config.MaxMissedPongs)
+	}
+
+	if !underline.isClosed() || atomic.LoadUint32(&timeouts) != 1 {
+		t.Fatalf("expected the underline connection to be closed and the timeout to be fired once")
+	}
+}
+
+func TestAwaitPongDisabled(t *testing.T) {
+	s := New(Config{PongTimeout: 5 * time.Millisecond, MaxMissedPongs: -1})
+	c, _ := newTestConnection(s)
+	defer c.Disconnect()
+
+	for i := 0; i < 3; i++ {
+		c.awaitPong(time.Now())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !s.IsConnected(c.ID()) {
+		t.Fatalf("expected the connection to stay connected when the missed pongs are not checked")
+	}
+}
+
+func TestPinger(t *testing.T) {
+	s := New(Config{PingPeriod: 10 * time.Millisecond, PongTimeout: 5 * time.Millisecond, MaxMissedPongs: 2})
+
+	// the client which answers to the pings stays connected.
+	c, underline := newTestConnection(s)
+	go c.Wait()
+
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(2 * time.Millisecond):
+				underline.pong()
+			}
+		}
+	}()
+
+	time.Sleep(60 * time.Millisecond)
+	close(stop)
+	if !s.IsConnected(c.ID()) {
+		t.Fatalf("expected the connection which answers to the pings to stay connected")
+	}
+	c.Disconnect()
+
+	// the one which does not is reaped.
+	c, underline = newTestConnection(s)
+	go c.Wait()
+
+	if !eventually(func() bool { return !s.IsConnected(c.ID()) }) || !underline.isClosed() {
+		t.Fatalf("expected the connection which does not answer to the pings to be disconnected")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kataras/iris/core/errors"
@@ -18,6 +19,11 @@ const (
 	websocketBoolMessageType
 	websocketBytesMessageType
 	websocketJSONMessageType
+	// the data is marshaled by the event's `Codec`, see `Server#RegisterCodec`.
+	websocketCodecMessageType
+	// a message of the `EmitWithAck` and its reply, see the ack.go.
+	websocketAckRequestMessageType
+	websocketAckReplyMessageType
 )
 
 const (
@@ -55,6 +61,12 @@ func (m websocketMessageType) Name() string {
 		return "[]byte"
 	} else if m == websocketJSONMessageType {
 		return "json"
+	} else if m == websocketCodecMessageType {
+		return "codec"
+	} else if m == websocketAckRequestMessageType {
+		return "ack request"
+	} else if m == websocketAckReplyMessageType {
+		return "ack reply"
 	}
 
 	return "Invalid(" + m.String() + ")"
@@ -104,6 +116,9 @@ func websocketMessageSerialize(event string, data interface{}) (string, error) {
 
 }
 
+// codecMessage is the marshaled data of a message of a `Codec`.
+type codecMessage []byte
+
 var errInvalidTypeMessage = errors.New("Type %s is invalid for message: %s")
 
 // websocketMessageDeserialize deserializes a custom websocket message from the client
@@ -127,6 +142,8 @@ func websocketMessageDeserialize(event string, websocketMessage string) (message
 		message = []byte(_message)
 	} else if _type == websocketJSONMessageType {
 		err = json.Unmarshal([]byte(_message), &message)
+	} else if _type == websocketCodecMessageType {
+		message = codecMessage(_message) // unmarshaled by the event's codec, to the type of each listener.
 	} else {
 		return nil, errInvalidTypeMessage.Format(_type.Name(), websocketMessage)
 	}
@@ -151,12 +168,17 @@ const (
 	letterIdxMax  = 63 / letterIdxBits   // # of letter indices fitting in 63 bits
 )
 
-var src = rand.NewSource(time.Now().UnixNano())
+var (
+	src   = rand.NewSource(time.Now().UnixNano())
+	srcMu sync.Mutex // the source is not safe for concurrent use, the ids are generated by many connections.
+)
 
 // random takes a parameter (int) and returns random slice of byte
 // ex: var randomstrbytes []byte; randomstrbytes = utils.Random(32)
 func random(n int) []byte {
 	b := make([]byte, n)
+	srcMu.Lock()
+	defer srcMu.Unlock()
 	// A src.Int63() generates 63 random bits, enough for letterIdxMax characters!
 	for i, cache, remain := n-1, src.Int63(), letterIdxMax; i >= 0; {
 		if remain == 0 {
@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"sync/atomic"
+)
+
+// WriteQueuePolicy is the behavior of a connection's write queue when it's full, see `Config#WriteQueueSize`.
+type WriteQueuePolicy uint8
+
+const (
+	// WriteQueueDropOldest drops the oldest queued message to make room for the new one,
+	// the slow client misses messages but it stays connected. It's the default policy.
+	WriteQueueDropOldest WriteQueuePolicy = iota
+	// WriteQueueCloseConnection disconnects the slow client.
+	WriteQueueCloseConnection
+	// WriteQueueBlock waits until there is room in the queue, the sender,
+	// i.e a broadcast, is as slow as the slowest client of its receivers.
+	WriteQueueBlock
+)
+
+// WriteQueueStats are the metrics of the write queues of the connections of a server, see `Server#WriteQueueStats`.
+type WriteQueueStats struct {
+	// Pending is the number of the queued messages of all of the connections.
+	Pending int
+	// MaxPending is the number of the queued messages of the connection with the deepest queue.
+	MaxPending int
+	// Dropped is the number of the messages which are dropped by the `WriteQueueDropOldest` policy.
+	Dropped uint64
+	// Closed is the number of the connections which are disconnected by the `WriteQueueCloseConnection` policy.
+	Closed uint64
+}
+
+// WriteQueueStats returns the metrics of the write queues of the connections,
+// the `Dropped` and `Closed` are counted since the server's creation.
+// The queues are enabled by the `Config#WriteQueueSize`.
+func (s *Server) WriteQueueStats() WriteQueueStats {
+	stats := WriteQueueStats{
+		Dropped: atomic.LoadUint64(&s.droppedMessages),
+		Closed:  atomic.LoadUint64(&s.closedConnections),
+	}
+
+	s.mu.RLock()
+	for _, cKV := range s.connections {
+		n := cKV.value.WriteQueueLen()
+		stats.Pending += n
+		if n > stats.MaxPending {
+			stats.MaxPending = n
+		}
+	}
+	s.mu.RUnlock()
+
+	return stats
+}
+
+// WriteQueueLen returns the number of the queued messages of the connection, see `Config#WriteQueueSize`.
+func (c *connection) WriteQueueLen() int {
+	return len(c.queue)
+}
+
+// enqueue adds the "data" to the write queue, if it's full then the `Config#WriteQueuePolicy` decides.
+func (c *connection) enqueue(data []byte) {
+	if c.server.config.WriteQueuePolicy == WriteQueueBlock {
+		select {
+		case c.queue <- data:
+		case <-c.done:
+		}
+		return
+	}
+
+	for {
+		select {
+		case c.queue <- data:
+			return
+		case <-c.done:
+			return
+		default:
+		}
+
+		if c.server.config.WriteQueuePolicy == WriteQueueCloseConnection {
+			if atomic.CompareAndSwapUint32(&c.queueClosed, 0, 1) {
+				atomic.AddUint64(&c.server.closedConnections, 1)
+				c.Disconnect()
+			}
+			return
+		}
+
+		select {
+		case <-c.queue:
+			atomic.AddUint64(&c.server.droppedMessages, 1)
+		default: // the writer took one already.
+		}
+	}
+}
+
+// startWriter writes the queued messages until the connection is disconnected,
+// a failed write disconnects the connection.
+func (c *connection) startWriter() {
+	go func() {
+		for {
+			select {
+			case data := <-c.queue:
+				if err := c.Write(c.messageTypeOf(data), data); err != nil {
+					// the connection is off, disconnect it (if not already)
+					// in order to release the blocked senders of the `WriteQueueBlock`.
+					c.Disconnect()
+					return
+				}
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
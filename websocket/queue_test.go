@@ -0,0 +1,181 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteQueueDropOldest(t *testing.T) {
+	s := New(Config{WriteQueueSize: 2})
+	c, underline := newTestConnection(s)
+
+	for _, msg := range []string{"1", "2", "3", "4", "5"} {
+		c.writeDefault([]byte(msg))
+	}
+
+	stats := s.WriteQueueStats()
+	if stats.Dropped != 3 || stats.Pending != 2 || stats.MaxPending != 2 || stats.Closed != 0 {
+		t.Fatalf("expected 3 dropped and 2 pending messages but got: %#v", stats)
+	}
+
+	c.startWriter()
+	if !eventually(func() bool { return len(underline.written()) == 2 }) {
+		t.Fatalf("expected the queued messages to be written but got: %v", underline.written())
+	}
+
+	if got := strings.Join(underline.written(), ","); got != "4,5" {
+		t.Fatalf("expected the newest messages to be kept but got: %s", got)
+	}
+
+	if stats = s.WriteQueueStats(); stats.Pending != 0 {
+		t.Fatalf("expected no pending messages but got: %d", stats.Pending)
+	}
+
+	c.Disconnect()
+}
+
+func TestWriteQueueCloseConnection(t *testing.T) {
+	s := New(Config{WriteQueueSize: 1, WriteQueuePolicy: WriteQueueCloseConnection})
+	c, underline := newTestConnection(s)
+	other, _ := newTestConnection(s)
+
+	disconnected := 0
+	c.OnDisconnect(func() { disconnected++ })
+
+	c.writeDefault([]byte("1"))
+	c.writeDefault([]byte("2"))
+	c.writeDefault([]byte("3"))
+
+	if s.IsConnected(c.ID()) || !underline.isClosed() || disconnected != 1 {
+		t.Fatalf("expected the slow connection to be disconnected once")
+	}
+
+	if !s.IsConnected(other.ID()) {
+		t.Fatalf("expected the other connection to stay connected")
+	}
+
+	if stats := s.WriteQueueStats(); stats.Closed != 1 || stats.Dropped != 0 {
+		t.Fatalf("expected 1 closed connection but got: %#v", stats)
+	}
+
+	other.Disconnect()
+}
+
+func TestWriteQueueBlock(t *testing.T) {
+	s := New(Config{WriteQueueSize: 1, WriteQueuePolicy: WriteQueueBlock})
+	c, underline := newTestConnection(s)
+
+	c.writeDefault([]byte("1"))
+
+	sent := make(chan struct{})
+	go func() {
+		c.writeDefault([]byte("2"))
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+		t.Fatalf("expected the sender to wait for room in the queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if stats := s.WriteQueueStats(); stats.Pending != 1 || stats.Dropped != 0 || stats.Closed != 0 {
+		t.Fatalf("expected 1 pending message but got: %#v", stats)
+	}
+
+	c.startWriter()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the sender to be released by the writer")
+	}
+
+	if !eventually(func() bool { return strings.Join(underline.written(), ",") == "1,2" }) {
+		t.Fatalf("expected all of the messages to be written in order but got: %v", underline.written())
+	}
+
+	c.Disconnect()
+}
+
+func TestWriteQueueBlockDisconnect(t *testing.T) {
+	s := New(Config{WriteQueueSize: 1, WriteQueuePolicy: WriteQueueBlock})
+	c, _ := newTestConnection(s)
+
+	c.writeDefault([]byte("1"))
+
+	sent := make(chan struct{})
+	go func() {
+		c.writeDefault([]byte("2"))
+		close(sent)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	c.Disconnect()
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the blocked sender to be released on disconnect")
+	}
+}
+
+func TestWriteQueueWriteFailure(t *testing.T) {
+	s := New(Config{WriteQueueSize: 1, WriteQueuePolicy: WriteQueueBlock})
+	c, underline := newTestConnection(s)
+	underline.writeErr = errors.New("broken pipe")
+	underline.block = make(chan struct{})
+
+	c.startWriter()
+	c.writeDefault([]byte("1")) // taken by the writer, which waits on the underline connection.
+	if !eventually(func() bool { return c.WriteQueueLen() == 0 }) {
+		t.Fatalf("expected the writer to take the message")
+	}
+	c.writeDefault([]byte("2")) // queued.
+
+	sent := make(chan struct{})
+	go func() {
+		c.writeDefault([]byte("3")) // blocked.
+		close(sent)
+	}()
+
+	close(underline.block)
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the blocked sender to be released after the failed write")
+	}
+
+	if s.IsConnected(c.ID()) || !underline.isClosed() {
+		t.Fatalf("expected the connection to be disconnected after the failed write")
+	}
+}
+
+func TestWriteQueueStats(t *testing.T) {
+	s := New(Config{WriteQueueSize: 4})
+	c1, _ := newTestConnection(s)
+	c2, _ := newTestConnection(s)
+
+	if stats := s.WriteQueueStats(); stats != (WriteQueueStats{}) {
+		t.Fatalf("expected empty stats but got: %#v", stats)
+	}
+
+	c1.writeDefault([]byte("1"))
+	for i := 0; i < 3; i++ {
+		c2.writeDefault([]byte("2"))
+	}
+
+	if stats := s.WriteQueueStats(); stats.Pending != 4 || stats.MaxPending != 3 {
+		t.Fatalf("expected 4 pending messages and a deepest queue of 3 but got: %#v", stats)
+	}
+
+	// the disconnected connections are not counted.
+	c2.Disconnect()
+	if stats := s.WriteQueueStats(); stats.Pending != 1 || stats.MaxPending != 1 {
+		t.Fatalf("expected 1 pending message but got: %#v", stats)
+	}
+
+	c1.Disconnect()
+}
@@ -0,0 +1,256 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/context"
+	"github.com/kataras/iris/core/errors"
+)
+
+var errEventPayload = errors.New("websocket: the event '%s' is declared with a payload of %s, got: %s")
+
+// DeclareEvent declares the "event" with the type of its "payload", i.e `ChatMessage{}`,
+// a nil payload declares an event without data.
+//
+// The `Emit` and the `EmitWithAck` of a declared event return an error if its data is not of that type,
+// the `ClientSource` decodes the messages of the declared events, so its listeners receive their data,
+// i.e the objects of the JSON payloads instead of the strings, and the `ClientTypeScript`
+// returns the declarations of their types, so the front-end and the back-end events stay in sync.
+//
+// It should be called before the server accepts any connection.
+func (s *Server) DeclareEvent(event string, payload interface{}) {
+	s.mu.Lock()
+	if s.events == nil {
+		s.events = make(map[string]reflect.Type)
+	}
+	s.events[event] = reflect.TypeOf(payload)
+	s.mu.Unlock()
+}
+
+// checkEventPayload returns an error if the "event" is declared with a payload of another type than the "data".
+func (s *Server) checkEventPayload(event string, data interface{}) error {
+	s.mu.RLock()
+	typ, declared := s.events[event]
+	s.mu.RUnlock()
+
+	if !declared {
+		return nil
+	}
+
+	got := reflect.TypeOf(data)
+	if got == typ || (got != nil && typ != nil && indirectType(got) == indirectType(typ)) {
+		return nil
+	}
+
+	return errEventPayload.Format(event, typeName(typ), typeName(got))
+}
+
+// declaredEvents returns the names of the declared events, sorted, and their payloads' types.
+func (s *Server) declaredEvents() ([]string, map[string]reflect.Type) {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.events))
+	events := make(map[string]reflect.Type, len(s.events))
+	for name, typ := range s.events {
+		names = append(names, name)
+		events[name] = typ
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(names)
+	return names, events
+}
+
+var clientEventsDeclaration = []byte("var websocketEvents = {};")
+
+// ClientSource returns the javascript client-side library, the `ClientSource`,
+// which decodes the messages of the declared events, see `DeclareEvent`.
+func (s *Server) ClientSource() []byte {
+	names, _ := s.declaredEvents()
+	events := make(map[string]bool, len(names))
+	for _, name := range names {
+		events[name] = true
+	}
+
+	b, _ := json.Marshal(events)
+	return bytes.Replace(ClientSource, clientEventsDeclaration, []byte("var websocketEvents = "+string(b)+";"), 1)
+}
+
+// ClientHandler is the handler which serves the javascript client-side library of the server, see `ClientSource`.
+// It's the typed alternative of the `websocket.ClientHandler`.
+func (s *Server) ClientHandler() context.Handler {
+	modNow := time.Now()
+	return func(ctx context.Context) {
+		ctx.ContentType("application/javascript")
+		if _, err := ctx.WriteWithExpiration(s.ClientSource(), modNow); err != nil {
+			ctx.StatusCode(500)
+			ctx.StopExecution()
+		}
+	}
+}
+
+// ClientTypeScript returns the typescript declarations of the javascript client-side library of the server,
+// with the types of the payloads of the declared events, see `DeclareEvent`.
+// The structs are declared as interfaces of their json fields.
+//
+// Example:
+// ioutil.WriteFile("./frontend/iris-ws.d.ts", ws.ClientTypeScript(), 0644)
+func (s *Server) ClientTypeScript() []byte {
+	names, events := s.declaredEvents()
+	g := &tsGenerator{declared: make(map[reflect.Type]string)}
+
+	var eventsDecl bytes.Buffer
+	eventsDecl.WriteString("export interface WsEvents {\n")
+	for _, name := range names {
+		typ := "void"
+		if events[name] != nil {
+			typ = g.typeOf(events[name])
+		}
+		eventsDecl.WriteString("    " + strconv.Quote(name) + ": " + typ + ";\n")
+	}
+	eventsDecl.WriteString("}\n\n")
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by the iris websocket server, DO NOT EDIT.\n\n")
+	for _, decl := range g.interfaces {
+		b.WriteString(decl)
+	}
+	b.Write(eventsDecl.Bytes())
+	b.WriteString(tsClientDeclaration)
+	return b.Bytes()
+}
+
+const tsClientDeclaration = `export declare class Ws {
+    constructor(endpoint: string, protocols?: string[]);
+    OnConnect(fn: () => void): void;
+    OnDisconnect(fn: () => void): void;
+    OnMessage(cb: (websocketMessage: string) => void): void;
+    On<E extends keyof WsEvents>(event: E, cb: (message: WsEvents[E]) => void): void;
+    OnRequest<E extends keyof WsEvents>(event: E, cb: (message: WsEvents[E]) => any): void;
+    Emit<E extends keyof WsEvents>(event: E, data: WsEvents[E]): void;
+    EmitWithAck<E extends keyof WsEvents>(event: E, data: WsEvents[E], timeout: number): Promise<any>;
+    EmitMessage(websocketMessage: string): void;
+    Disconnect(): void;
+}
+`
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// tsGenerator converts the go types to typescript types, the named structs are declared as interfaces.
+type tsGenerator struct {
+	declared   map[reflect.Type]string
+	interfaces []string
+}
+
+func (g *tsGenerator) typeOf(typ reflect.Type) string {
+	typ = indirectType(typ)
+
+	if typ == timeType {
+		return "string"
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return "string" // []byte.
+		}
+		return g.typeOf(typ.Elem()) + "[]"
+	case reflect.Map:
+		return "{ [key: string]: " + g.typeOf(typ.Elem()) + " }"
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return "{ " + strings.Join(g.fieldsOf(typ), " ") + " }"
+		}
+		return g.declare(typ)
+	default:
+		return "any"
+	}
+}
+
+// declare adds the interface of the named struct, once, and returns its name.
+func (g *tsGenerator) declare(typ reflect.Type) string {
+	if name, ok := g.declared[typ]; ok {
+		return name
+	}
+
+	name := typ.Name()
+	g.declared[typ] = name // before its fields, for the recursive types.
+
+	var b bytes.Buffer
+	b.WriteString("export interface " + name + " {\n")
+	for _, field := range g.fieldsOf(typ) {
+		b.WriteString("    " + field + "\n")
+	}
+	b.WriteString("}\n\n")
+
+	g.interfaces = append(g.interfaces, b.String())
+	return name
+}
+
+// fieldsOf returns the typescript fields of the json fields of the struct.
+func (g *tsGenerator) fieldsOf(typ reflect.Type) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous { // unexported.
+			continue
+		}
+
+		name, opts := f.Name, ""
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.IndexByte(tag, ','); idx >= 0 {
+				tag, opts = tag[:idx], tag[idx:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		} else if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct {
+			// the fields of the embedded structs are promoted.
+			fields = append(fields, g.fieldsOf(indirectType(f.Type))...)
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		optional := ""
+		if strings.Contains(opts, "omitempty") || f.Type.Kind() == reflect.Ptr {
+			optional = "?"
+		}
+
+		fields = append(fields, strconv.Quote(name)+optional+": "+g.typeOf(f.Type)+";")
+	}
+
+	return fields
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+func typeName(typ reflect.Type) string {
+	if typ == nil {
+		return "nil"
+	}
+	return typ.String()
+}
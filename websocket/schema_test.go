@@ -0,0 +1,129 @@
+package websocket
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type (
+	testMeta struct {
+		At time.Time `json:"at"`
+	}
+
+	testUser struct {
+		ID      int        `json:"id"`
+		Name    string     `json:"name"`
+		Email   *string    `json:"email"`
+		Tags    []string   `json:"tags,omitempty"`
+		Friends []testUser `json:"friends"`
+		Ignored string     `json:"-"`
+		secret  string
+	}
+
+	testChatMessage struct {
+		testMeta
+		From        testUser          `json:"from"`
+		Text        string            `json:"text"`
+		Attachments map[string][]byte `json:"attachments"`
+		Position    struct {
+			X, Y float64
+		} `json:"position"`
+	}
+)
+
+func testDeclaredServer() *Server {
+	s := New(Config{})
+	s.DeclareEvent("chat", testChatMessage{})
+	s.DeclareEvent("count", 0)
+	s.DeclareEvent("typing", nil)
+	return s
+}
+
+func TestCheckEventPayload(t *testing.T) {
+	s := testDeclaredServer()
+
+	valid := map[string]interface{}{
+		"chat":       testChatMessage{},
+		"count":      42,
+		"typing":     nil,
+		"undeclared": "anything",
+	}
+
+	for event, data := range valid {
+		if err := s.checkEventPayload(event, data); err != nil {
+			t.Fatalf("expected the payload of '%s' to be valid but got: %v", event, err)
+		}
+	}
+
+	// the pointers of the declared types are valid too.
+	if err := s.checkEventPayload("chat", &testChatMessage{}); err != nil {
+		t.Fatalf("expected the pointer payload to be valid but got: %v", err)
+	}
+
+	invalid := map[string]interface{}{
+		"chat":   "text",
+		"count":  "42",
+		"typing": true,
+	}
+
+	for event, data := range invalid {
+		if err := s.checkEventPayload(event, data); err == nil {
+			t.Fatalf("expected the payload %#v of '%s' to be invalid", data, event)
+		}
+	}
+
+	if err := s.checkEventPayload("chat", nil); err == nil || !strings.Contains(err.Error(), "websocket.testChatMessage, got: nil") {
+		t.Fatalf("expected the missing payload to be invalid but got: %v", err)
+	}
+
+	// the emits of the declared events are checked.
+	c, _ := newTestConnection(s)
+	defer c.Disconnect()
+
+	if err := c.Emit("count", "not a number"); err == nil {
+		t.Fatalf("expected the emit of an invalid payload to fail")
+	}
+
+	if _, err := c.EmitWithAck("count", "not a number", time.Second); err == nil {
+		t.Fatalf("expected the emit with ack of an invalid payload to fail")
+	}
+}
+
+const expectedClientTypeScript = `// Code generated by the iris websocket server, DO NOT EDIT.
+
+export interface testUser {
+    "id": number;
+    "name": string;
+    "email"?: string;
+    "tags"?: string[];
+    "friends": testUser[];
+}
+
+export interface testChatMessage {
+    "at": string;
+    "from": testUser;
+    "text": string;
+    "attachments": { [key: string]: string };
+    "position": { "X": number; "Y": number; };
+}
+
+export interface WsEvents {
+    "chat": testChatMessage;
+    "count": number;
+    "typing": void;
+}
+
+`
+
+func TestClientTypeScript(t *testing.T) {
+	got := string(testDeclaredServer().ClientTypeScript())
+	if expected := expectedClientTypeScript + tsClientDeclaration; got != expected {
+		t.Fatalf("expected the typescript declarations:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	// the declarations are stable.
+	if again := string(testDeclaredServer().ClientTypeScript()); again != got {
+		t.Fatalf("expected the same declarations on each call but got:\n%s", again)
+	}
+}
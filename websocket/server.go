@@ -1,7 +1,10 @@
 package websocket
 
 import (
+	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/kataras/iris/context"
 
@@ -103,11 +106,20 @@ type (
 	//
 	// To serve the built'n javascript client-side library look the `websocket.ClientHandler`.
 	Server struct {
+		// the metrics of the write queues, see `WriteQueueStats`,
+		// first for the alignment of their atomic operations.
+		droppedMessages   uint64
+		closedConnections uint64
+
 		config                Config
 		connections           connections
 		rooms                 map[string][]string // by default a connection is joined to a room which has the connection id as its name
-		mu                    sync.RWMutex        // for rooms
+		mu                    sync.RWMutex        // for the rooms and the connections
 		onConnectionListeners []ConnectionFunc
+		exchange              Exchange                // the pub/sub bridge of the instances, if any, see `UseExchange`.
+		publishQueue          chan []byte             // the messages which wait to be published to the exchange.
+		codecs                map[string]Codec        // by event, see `RegisterCodec`.
+		events                map[string]reflect.Type // the payloads of the declared events, see `DeclareEvent`.
 		//connectionPool        sync.Pool // sadly we can't make this because the websocket connection is live until is closed.
 		upgrader websocket.Upgrader
 	}
@@ -174,11 +186,22 @@ func (s *Server) Handler() context.Handler {
 //
 // If the upgrade fails, then Upgrade replies to the client with an HTTP error
 // response and the return `Connection.Err()` is filled with that error.
+// The `Config#Authenticate`, if any, is called before the upgrade, see `AuthError`.
 //
 // For a more high-level function use the `Handler()` and `OnConnecton` events.
 // This one does not starts the connection's writer and reader, so after your `On/OnMessage` events registration
 // the caller has to call the `Connection#Wait` function, otherwise the connection will be not handled.
 func (s *Server) Upgrade(ctx context.Context) Connection {
+	var identity string
+	if s.config.Authenticate != nil {
+		id, err := s.config.Authenticate(ctx)
+		if err != nil {
+			ctx.StatusCode(authStatusCode(err))
+			return &connection{err: err}
+		}
+		identity = id
+	}
+
 	conn, err := s.upgrader.Upgrade(ctx.ResponseWriter(), ctx.Request(), ctx.ResponseWriter().Header())
 	if err != nil {
 		ctx.Application().Logger().Warnf("websocket error: %v\n", err)
@@ -186,18 +209,20 @@ func (s *Server) Upgrade(ctx context.Context) Connection {
 		return &connection{err: err}
 	}
 
-	return s.handleConnection(ctx, conn)
+	return s.handleConnection(ctx, conn, identity)
 }
 
 // wrapConnection wraps an underline connection to an iris websocket connection.
 // It does NOT starts its writer, reader and event mux, the caller is responsible for that.
-func (s *Server) handleConnection(ctx context.Context, websocketConn UnderlineConnection) *connection {
+func (s *Server) handleConnection(ctx context.Context, websocketConn UnderlineConnection, identity string) *connection {
 	// use the config's id generator (or the default) to create a websocket client/connection id
 	cid := s.config.IDGenerator(ctx)
 	// create the new connection
-	c := newConnection(ctx, s, websocketConn, cid)
+	c := newConnection(ctx, s, websocketConn, cid, identity)
 	// add the connection to the Server's list
+	s.mu.Lock()
 	s.connections.add(cid, c)
+	s.mu.Unlock()
 
 	// join to itself
 	s.Join(c.ID(), c.ID())
@@ -237,8 +262,7 @@ func (s *Server) OnConnection(cb ConnectionFunc) {
 // useful when you have defined a custom connection id generator (based on a database)
 // and you want to check if that connection is already connected (on multiple tabs)
 func (s *Server) IsConnected(connID string) bool {
-	c := s.connections.get(connID)
-	return c != nil
+	return s.GetConnection(connID) != nil
 }
 
 // Join joins a websocket client to a room,
@@ -253,12 +277,86 @@ func (s *Server) Join(roomName string, connID string) {
 
 // join used internally, no locks used.
 func (s *Server) join(roomName string, connID string) {
-	if s.rooms[roomName] == nil {
-		s.rooms[roomName] = make([]string, 0)
+	for _, id := range s.rooms[roomName] {
+		if id == connID { // already joined.
+			return
+		}
 	}
+
 	s.rooms[roomName] = append(s.rooms[roomName], connID)
 }
 
+// JoinRoom joins the "c" connection to the "roomName" room, same as the `Join(roomName, c.ID())`.
+// The connection leaves all of its rooms automatically when it's disconnected,
+// so the applications do not have to keep their own maps of the rooms' connections.
+func (s *Server) JoinRoom(c Connection, roomName string) {
+	s.Join(roomName, c.ID())
+}
+
+// LeaveRoom removes the "c" connection from the "roomName" room, same as the `Leave(roomName, c.ID())`.
+//
+// Returns true if the connection has actually left from the particular room.
+func (s *Server) LeaveRoom(c Connection, roomName string) bool {
+	return s.Leave(roomName, c.ID())
+}
+
+// To returns an emitter which sends the messages of the server, they have no sender connection,
+// to the connections of the "roomName" room, to a single connection by its id or to all of them by the `All`,
+// i.e from an http handler or a background goroutine:
+//
+// server.To("room1").Emit("chat", "a message for the room1")
+func (s *Server) To(roomName string) Emitter {
+	return &serverEmitter{server: s, to: roomName}
+}
+
+// Rooms returns the names of the rooms which have at least one connection,
+// except the rooms of the connections' ids, each connection is joined to its own.
+func (s *Server) Rooms() []string {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.rooms))
+	for name := range s.rooms {
+		if s.connections.get(name) == nil {
+			names = append(names, name)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// RoomsOf returns the names of the rooms which the connection of the "connID" is joined to,
+// except its own room.
+func (s *Server) RoomsOf(connID string) []string {
+	var names []string
+
+	s.mu.RLock()
+	for name, ids := range s.rooms {
+		if name == connID {
+			continue
+		}
+
+		for _, id := range ids {
+			if id == connID {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// GetTotalConnectionsByRoom returns the number of the connections which are joined to the "roomName" room.
+func (s *Server) GetTotalConnectionsByRoom(roomName string) int {
+	s.mu.RLock()
+	n := len(s.rooms[roomName])
+	s.mu.RUnlock()
+	return n
+}
+
 // IsJoined reports if a specific room has a specific connection into its values.
 // First parameter is the room name, second is the connection's id.
 //
@@ -347,7 +445,14 @@ func (s *Server) GetConnections() []Connection {
 
 // GetConnection returns single connection
 func (s *Server) GetConnection(key string) Connection {
-	return s.connections.get(key)
+	s.mu.RLock()
+	c := s.connections.get(key)
+	s.mu.RUnlock()
+
+	if c == nil { // do not return a non-nil interface of a nil connection.
+		return nil
+	}
+	return c
 }
 
 // GetConnectionsByRoom returns a list of Connection
@@ -357,7 +462,9 @@ func (s *Server) GetConnectionsByRoom(roomName string) []Connection {
 	var conns []Connection
 	if connIDs, found := s.rooms[roomName]; found {
 		for _, connID := range connIDs {
-			conns = append(conns, s.connections.get(connID))
+			if c := s.connections.get(connID); c != nil {
+				conns = append(conns, c)
+			}
 		}
 
 	}
@@ -374,38 +481,61 @@ func (s *Server) GetConnectionsByRoom(roomName string) []Connection {
 //
 // You SHOULD use connection.EmitMessage/Emit/To().Emit/EmitMessage instead.
 // let's keep it unexported for the best.
-func (s *Server) emitMessage(from, to string, data []byte) {
+//
+// The message is published to the other instances too, if an `Exchange` is in use,
+// except the messages to a connection of this server.
+func (s *Server) emitMessage(from, to string, data []byte) error {
+	s.emitLocal(from, to, data)
+
+	if to != All && to != Broadcast {
+		s.mu.RLock()
+		local := s.connections.get(to) != nil
+		s.mu.RUnlock()
+		if local {
+			return nil
+		}
+	}
+
+	return s.publish(from, to, data)
+}
+
+// emitLocal sends the message to the connections of this server only.
+func (s *Server) emitLocal(from, to string, data []byte) {
+	// a copy of the receivers, the rooms and the connections can be modified while the message is sent.
+	var (
+		conns   []*connection
+		missing []string
+	)
+
+	s.mu.RLock()
 	if to != All && to != Broadcast {
-		if s.rooms[to] != nil {
-			// it suppose to send the message to a specific room/or a user inside its own room
-			for _, connectionIDInsideRoom := range s.rooms[to] {
-				if c := s.connections.get(connectionIDInsideRoom); c != nil {
-					c.writeDefault(data) //send the message to the client(s)
-				} else {
-					// the connection is not connected but it's inside the room, we remove it on disconnect but for ANY CASE:
-					cid := connectionIDInsideRoom
-					if c != nil {
-						cid = c.id
-					}
-					s.Leave(cid, to)
-				}
+		// it suppose to send the message to a specific room/or a user inside its own room
+		for _, connectionIDInsideRoom := range s.rooms[to] {
+			if c := s.connections.get(connectionIDInsideRoom); c != nil {
+				conns = append(conns, c)
+			} else {
+				// the connection is not connected but it's inside the room, we remove it on disconnect but for ANY CASE:
+				missing = append(missing, connectionIDInsideRoom)
 			}
 		}
 	} else {
 		// it suppose to send the message to all opened connections or to all except the sender
 		for _, cKV := range s.connections {
-			connID := cKV.key
-			if to != All && to != connID { // if it's not suppose to send to all connections (including itself)
-				if to == Broadcast && from == connID { // if broadcast to other connections except this
-					continue //here we do the opossite of previous block,
-					// just skip this connection when it's suppose to send the message to all connections except the sender
-				}
-
+			if to == Broadcast && from == cKV.key { // if broadcast to other connections except this
+				continue // just skip this connection when it's suppose to send the message to all connections except the sender
 			}
-			// send to the client(s) when the top validators passed
-			cKV.value.writeDefault(data)
+			conns = append(conns, cKV.value)
 		}
 	}
+	s.mu.RUnlock()
+
+	for _, connID := range missing {
+		s.Leave(to, connID)
+	}
+
+	for _, c := range conns {
+		c.writeDefault(data) //send the message to the client(s)
+	}
 }
 
 // Disconnect force-disconnects a websocket connection based on its connection.ID()
@@ -422,9 +552,16 @@ func (s *Server) Disconnect(connID string) (err error) {
 	s.LeaveAll(connID)
 
 	// remove the connection from the list
-	if c, ok := s.connections.remove(connID); ok {
-		if !c.disconnected {
-			c.disconnected = true
+	s.mu.Lock()
+	c, ok := s.connections.remove(connID)
+	s.mu.Unlock()
+
+	if ok {
+		if atomic.CompareAndSwapUint32(&c.disconnected, 0, 1) {
+			if c.done != nil {
+				close(c.done) // stop the writer and the blocked senders.
+			}
+			c.cancelAcks()
 
 			// fire the disconnect callbacks, if any
 			c.fireDisconnect()
@@ -0,0 +1,84 @@
+package websocket
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestServerRooms(t *testing.T) {
+	s := New(Config{})
+	c1, underline1 := newTestConnection(s)
+	c2, underline2 := newTestConnection(s)
+	c3, underline3 := newTestConnection(s)
+	defer c3.Disconnect()
+
+	var left []string
+	c1.OnLeave(func(roomName string) { left = append(left, roomName) })
+
+	c1.Join("room1")
+	c1.Join("room1") // joined once.
+	c1.Join("room2")
+	s.JoinRoom(c2, "room1")
+
+	if expected, got := []string{"room1", "room2"}, s.Rooms(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected the rooms %v but got: %v", expected, got)
+	}
+
+	rooms := s.RoomsOf(c1.ID())
+	sort.Strings(rooms)
+	if expected := []string{"room1", "room2"}; !reflect.DeepEqual(rooms, expected) {
+		t.Fatalf("expected the rooms of the connection %v but got: %v", expected, rooms)
+	}
+
+	if n := s.GetTotalConnectionsByRoom("room1"); n != 2 {
+		t.Fatalf("expected 2 connections in the room but got: %d", n)
+	}
+
+	if !c2.IsJoined("room1") || c3.IsJoined("room1") {
+		t.Fatalf("expected only the joined connections to be in the room")
+	}
+
+	s.To("room1").EmitMessage([]byte("to room1"))
+	if len(underline1.written()) != 1 || len(underline2.written()) != 1 || len(underline3.written()) != 0 {
+		t.Fatalf("expected the message to reach the connections of the room only")
+	}
+
+	if !s.LeaveRoom(c2, "room1") || s.LeaveRoom(c2, "room1") {
+		t.Fatalf("expected the connection to leave the room once")
+	}
+
+	// the empty rooms are removed.
+	if c1.Leave("room2"); s.GetTotalConnectionsByRoom("room2") != 0 {
+		t.Fatalf("expected the room to be empty")
+	}
+
+	if expected, got := []string{"room1"}, s.Rooms(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected the rooms %v but got: %v", expected, got)
+	}
+
+	// the connection leaves all of its rooms on disconnect.
+	c1.Join("room3")
+	left = nil
+	c1.Disconnect()
+
+	if rooms := s.Rooms(); len(rooms) != 0 {
+		t.Fatalf("expected no rooms after the disconnection but got: %v", rooms)
+	}
+
+	expected := []string{c1.ID(), "room1", "room3"}
+	sort.Strings(expected)
+	sort.Strings(left)
+	if !reflect.DeepEqual(left, expected) {
+		t.Fatalf("expected the leave callbacks of %v but got: %v", expected, left)
+	}
+
+	if s.IsJoined(c1.ID(), c1.ID()) || s.GetConnection(c1.ID()) != nil {
+		t.Fatalf("expected the connection to be removed")
+	}
+
+	c2.Disconnect()
+	if n := s.GetTotalConnections(); n != 1 {
+		t.Fatalf("expected 1 connection but got: %d", n)
+	}
+}
@@ -0,0 +1,132 @@
+package websocket
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+var errTestClosed = errors.New("closed")
+
+// testConn is a fake underline connection which records the written messages.
+type testConn struct {
+	mu       sync.Mutex
+	messages []string
+	controls []int
+	closed   bool
+	// writeErr, if not nil, is returned by the writes.
+	writeErr error
+	// block, if not nil, blocks the writes until it's closed.
+	block chan struct{}
+	// closeCh is closed on Close, it releases the reads.
+	closeCh chan struct{}
+	// reads are the messages which are read by the connection, until it's closed.
+	reads chan []byte
+
+	pongHandler func(string) error
+}
+
+func newTestConn() *testConn {
+	return &testConn{closeCh: make(chan struct{}), reads: make(chan []byte, 16)}
+}
+
+func (c *testConn) SetWriteDeadline(t time.Time) error  { return nil }
+func (c *testConn) SetReadDeadline(t time.Time) error   { return nil }
+func (c *testConn) SetReadLimit(limit int64)            {}
+func (c *testConn) SetPingHandler(h func(string) error) {}
+
+func (c *testConn) SetPongHandler(h func(string) error) {
+	c.mu.Lock()
+	c.pongHandler = h
+	c.mu.Unlock()
+}
+
+func (c *testConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errTestClosed
+	}
+	c.controls = append(c.controls, messageType)
+	return nil
+}
+
+func (c *testConn) WriteMessage(messageType int, data []byte) error {
+	c.mu.Lock()
+	block := c.block
+	c.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writeErr != nil {
+		return c.writeErr
+	}
+	c.messages = append(c.messages, string(data))
+	return nil
+}
+
+func (c *testConn) ReadMessage() (int, []byte, error) {
+	select {
+	case data := <-c.reads:
+		return 1, data, nil
+	case <-c.closeCh:
+		return 0, nil, errTestClosed
+	}
+}
+
+func (c *testConn) NextWriter(messageType int) (io.WriteCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *testConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.closeCh)
+	}
+	return nil
+}
+
+// pong calls the pong handler of the connection, like a client which answers to a ping.
+func (c *testConn) pong() {
+	c.mu.Lock()
+	h := c.pongHandler
+	c.mu.Unlock()
+	if h != nil {
+		h("")
+	}
+}
+
+func (c *testConn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *testConn) written() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.messages...)
+}
+
+// newTestConnection registers a new connection of the "s" server over a fake underline connection,
+// its reader, writer and pinger are not started.
+func newTestConnection(s *Server) (*connection, *testConn) {
+	underline := newTestConn()
+	return s.handleConnection(nil, underline, ""), underline
+}
+
+// eventually reports whether the "cond" is true in a second.
+func eventually(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return cond()
+}